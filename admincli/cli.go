@@ -0,0 +1,252 @@
+package admincli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+)
+
+// EnvToken is the environment variable the admin token is read from if --token is not set, so the token
+// doesn't leak to the shell history and process list.
+const EnvToken = "FAUCET_ADMIN_TOKEN"
+
+// command is the `faucet admin` subcommand.
+type command struct {
+	usage       string
+	description string
+	args        int
+	run         func(ctx context.Context, c Client, flags *pflag.FlagSet, args []string) (json.RawMessage, error)
+	flags       func(flags *pflag.FlagSet)
+}
+
+var commands = map[string]command{
+	"pause": {
+		usage:       "pause [--reason <reason>]",
+		description: "suspend funding, requests are rejected with 503 and kind server.paused",
+		flags: func(flags *pflag.FlagSet) {
+			flags.String("reason", "", "reason returned to the clients")
+		},
+		run: func(ctx context.Context, c Client, flags *pflag.FlagSet, args []string) (json.RawMessage, error) {
+			reason, _ := flags.GetString("reason")
+			return c.Do(ctx, http.MethodPost, "/admin/controls/pause", map[string]string{"reason": reason})
+		},
+	},
+	"resume": {
+		usage:       "resume",
+		description: "resume funding suspended by pause",
+		run: func(ctx context.Context, c Client, flags *pflag.FlagSet, args []string) (json.RawMessage, error) {
+			return c.Do(ctx, http.MethodPost, "/admin/controls/resume", nil)
+		},
+	},
+	"set-amount": {
+		usage:       "set-amount <amount>",
+		description: "change the amount granted to each request, in the base denom, until restart",
+		args:        1,
+		run: func(ctx context.Context, c Client, flags *pflag.FlagSet, args []string) (json.RawMessage, error) {
+			return c.Do(ctx, http.MethodPut, "/admin/controls/transfer-amount", map[string]string{"amount": args[0]})
+		},
+	},
+	"block-address": {
+		usage:       "block-address <address> [--reason <reason>]",
+		description: "refuse funding of the address",
+		args:        1,
+		flags: func(flags *pflag.FlagSet) {
+			flags.String("reason", "", "reason recorded with the address")
+		},
+		run: func(ctx context.Context, c Client, flags *pflag.FlagSet, args []string) (json.RawMessage, error) {
+			reason, _ := flags.GetString("reason")
+			return c.Do(ctx, http.MethodPut, "/admin/blocked-addresses/"+url.PathEscape(args[0]),
+				map[string]string{"reason": reason})
+		},
+	},
+	"unblock-address": {
+		usage:       "unblock-address <address>",
+		description: "allow funding of the blocked address again",
+		args:        1,
+		run: func(ctx context.Context, c Client, flags *pflag.FlagSet, args []string) (json.RawMessage, error) {
+			return c.Do(ctx, http.MethodDelete, "/admin/blocked-addresses/"+url.PathEscape(args[0]), nil)
+		},
+	},
+	"issue-key": {
+		usage:       "issue-key <holder>",
+		description: "issue API key of the holder, the key is printed once and kept by the faucet until restart",
+		args:        1,
+		run: func(ctx context.Context, c Client, flags *pflag.FlagSet, args []string) (json.RawMessage, error) {
+			return c.Do(ctx, http.MethodPost, "/admin/api-keys", map[string]string{"holder": args[0]})
+		},
+	},
+	"stats": {
+		usage:       "stats",
+		description: "print the funding statistics and the state of the runtime controls",
+		run: func(ctx context.Context, c Client, flags *pflag.FlagSet, args []string) (json.RawMessage, error) {
+			stats, err := c.Do(ctx, http.MethodGet, "/stats", nil)
+			if err != nil {
+				return nil, err
+			}
+			controls, err := c.Do(ctx, http.MethodGet, "/admin/controls", nil)
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(map[string]json.RawMessage{"stats": stats, "controls": controls})
+		},
+	},
+	"refill-propose": {
+		usage:       "refill-propose <amount>",
+		description: "prepare the transaction refilling the faucet from the multisig treasury, e.g. 1000000000ucore",
+		args:        1,
+		run: func(ctx context.Context, c Client, flags *pflag.FlagSet, args []string) (json.RawMessage, error) {
+			return c.Do(ctx, http.MethodPost, "/admin/refills", map[string]string{"amount": args[0]})
+		},
+	},
+	"refill-sign": {
+		usage:       "refill-sign <id> <signature-file>",
+		description: "add the signature produced by `cored tx sign` to the refill proposal",
+		args:        2,
+		run: func(ctx context.Context, c Client, flags *pflag.FlagSet, args []string) (json.RawMessage, error) {
+			signature, err := os.ReadFile(args[1])
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			if !json.Valid(signature) {
+				return nil, errors.Errorf("signature file %s is not JSON", args[1])
+			}
+			return c.Do(ctx, http.MethodPost, "/admin/refills/"+url.PathEscape(args[0])+"/signatures",
+				json.RawMessage(signature))
+		},
+	},
+	"refill-broadcast": {
+		usage:       "refill-broadcast <id>",
+		description: "broadcast the refill proposal signed by enough treasury signers",
+		args:        1,
+		run: func(ctx context.Context, c Client, flags *pflag.FlagSet, args []string) (json.RawMessage, error) {
+			return c.Do(ctx, http.MethodPost, "/admin/refills/"+url.PathEscape(args[0])+"/broadcast", nil)
+		},
+	},
+	"jobs": {
+		usage:       "jobs",
+		description: "show the background jobs with their last runs and errors",
+		run: func(ctx context.Context, c Client, flags *pflag.FlagSet, args []string) (json.RawMessage, error) {
+			return c.Do(ctx, http.MethodGet, "/admin/jobs", nil)
+		},
+	},
+	"config-changes": {
+		usage:       "config-changes [--since <time>] [--limit <n>] [--cursor <cursor>]",
+		description: "show who changed the runtime configuration, when and what was changed",
+		flags: func(flags *pflag.FlagSet) {
+			flags.String("since", "", "RFC3339 time of the oldest change, changes of the last 30 days are shown if empty")
+			flags.Int("limit", 0, "maximal number of changes shown, 100 if zero")
+			flags.String("cursor", "", "nextCursor of the previous page")
+		},
+		run: func(ctx context.Context, c Client, flags *pflag.FlagSet, args []string) (json.RawMessage, error) {
+			query := url.Values{}
+			if since, _ := flags.GetString("since"); since != "" {
+				query.Set("since", since)
+			}
+			if limit, _ := flags.GetInt("limit"); limit > 0 {
+				query.Set("limit", strconv.Itoa(limit))
+			}
+			if cursor, _ := flags.GetString("cursor"); cursor != "" {
+				query.Set("cursor", cursor)
+			}
+			path := "/admin/config/changes"
+			if len(query) > 0 {
+				path += "?" + query.Encode()
+			}
+			return c.Do(ctx, http.MethodGet, path, nil)
+		},
+	},
+	"run-job": {
+		usage:       "run-job <name>",
+		description: "run the background job at once, e.g. after fixing the cause of its failures",
+		args:        1,
+		run: func(ctx context.Context, c Client, flags *pflag.FlagSet, args []string) (json.RawMessage, error) {
+			return c.Do(ctx, http.MethodPost, "/admin/jobs/"+url.PathEscape(args[0])+"/run", nil)
+		},
+	},
+}
+
+// Run executes the subcommand given by args, e.g. ["pause", "--reason", "incident"], printing the JSON response
+// to stdout.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "--help" || args[0] == "-h" {
+		printUsage(stderr)
+		return nil
+	}
+	name := args[0]
+	cmd, ok := commands[name]
+	if !ok {
+		printUsage(stderr)
+		return errors.Errorf("unknown command %q", name)
+	}
+
+	flags := pflag.NewFlagSet("faucet admin "+name, pflag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var cfg Config
+	flags.StringVar(&cfg.URL, "url", "http://localhost:8090", "base URL of the faucet")
+	flags.StringVar(&cfg.Token, "token", "", "admin token, read from "+EnvToken+" if empty")
+	flags.StringVar(&cfg.CertFile, "cert", "", "client certificate file for mutual TLS")
+	flags.StringVar(&cfg.KeyFile, "key", "", "client key file for mutual TLS")
+	flags.StringVar(&cfg.CAFile, "ca", "", "CA file verifying the server certificate, system roots are used if empty")
+	flags.StringVar(&cfg.Actor, "actor", os.Getenv("USER"), "operator recorded with the configuration changes")
+	if cmd.flags != nil {
+		cmd.flags(flags)
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return errors.WithStack(err)
+	}
+	if flags.NArg() != cmd.args {
+		return errors.Errorf("usage: faucet admin %s", cmd.usage)
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv(EnvToken)
+	}
+
+	client, err := NewClient(cfg)
+	if err != nil {
+		return err
+	}
+	resp, err := cmd.run(ctx, client, flags, flags.Args())
+	if err != nil {
+		return err
+	}
+	return printJSON(stdout, resp)
+}
+
+func printJSON(w io.Writer, resp json.RawMessage) error {
+	if len(bytes.TrimSpace(resp)) == 0 {
+		return nil
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, resp, "", "  "); err != nil {
+		return errors.WithStack(err)
+	}
+	out.WriteString("\n")
+	_, err := w.Write(out.Bytes())
+	return errors.WithStack(err)
+}
+
+func printUsage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("Usage: faucet admin <command> [--url <url>] [--token <token>] [--cert <file> --key <file>] [--ca <file>]\n\nCommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %-45s %s\n", commands[name].usage, commands[name].description)
+	}
+	_, _ = io.WriteString(w, b.String())
+}
@@ -0,0 +1,113 @@
+package admincli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordedRequest struct {
+	Method string
+	Path   string
+	Auth   string
+	Actor  string
+	Body   map[string]string
+}
+
+func newTestServer(t *testing.T, requests *[]recordedRequest) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := recordedRequest{Method: r.Method, Path: r.URL.EscapedPath(), Auth: r.Header.Get("Authorization"),
+			Actor: r.Header.Get("X-Faucet-Actor")}
+		if body, _ := io.ReadAll(r.Body); len(body) > 0 {
+			require.NoError(t, json.Unmarshal(body, &req.Body))
+		}
+		*requests = append(*requests, req)
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/faucet/v1/admin/blocked-addresses/unknown" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"type":"error","content":[{"kind":"address.not_blocked","message":"address is not blocked"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRun(t *testing.T) {
+	requireT := require.New(t)
+
+	var requests []recordedRequest
+	server := newTestServer(t, &requests)
+	t.Setenv("USER", "")
+
+	var stdout bytes.Buffer
+	requireT.NoError(Run(context.Background(), []string{
+		"pause", "--url", server.URL, "--token", "secret", "--reason", "incident", "--actor", "alice",
+	}, &stdout, io.Discard))
+	requireT.Equal("{\n  \"ok\": true\n}\n", stdout.String())
+
+	t.Setenv(EnvToken, "env-secret")
+	for _, args := range [][]string{
+		{"resume"},
+		{"set-amount", "1000"},
+		{"block-address", "devcore1abc", "--reason", "abuse"},
+		{"issue-key", "partner"},
+		{"stats"},
+		{"refill-propose", "1000ucore"},
+		{"refill-broadcast", "abc"},
+		{"jobs"},
+		{"run-job", "gc"},
+		{"config-changes", "--since", "2023-01-01T00:00:00Z", "--limit", "10"},
+	} {
+		requireT.NoError(Run(context.Background(), append(args, "--url", server.URL), io.Discard, io.Discard))
+	}
+
+	requireT.Equal([]recordedRequest{
+		{Method: http.MethodPost, Path: "/api/faucet/v1/admin/controls/pause", Auth: "Bearer secret", Actor: "alice",
+			Body: map[string]string{"reason": "incident"}},
+		{Method: http.MethodPost, Path: "/api/faucet/v1/admin/controls/resume", Auth: "Bearer env-secret"},
+		{Method: http.MethodPut, Path: "/api/faucet/v1/admin/controls/transfer-amount", Auth: "Bearer env-secret",
+			Body: map[string]string{"amount": "1000"}},
+		{Method: http.MethodPut, Path: "/api/faucet/v1/admin/blocked-addresses/devcore1abc", Auth: "Bearer env-secret",
+			Body: map[string]string{"reason": "abuse"}},
+		{Method: http.MethodPost, Path: "/api/faucet/v1/admin/api-keys", Auth: "Bearer env-secret",
+			Body: map[string]string{"holder": "partner"}},
+		{Method: http.MethodGet, Path: "/api/faucet/v1/stats", Auth: "Bearer env-secret"},
+		{Method: http.MethodGet, Path: "/api/faucet/v1/admin/controls", Auth: "Bearer env-secret"},
+		{Method: http.MethodPost, Path: "/api/faucet/v1/admin/refills", Auth: "Bearer env-secret",
+			Body: map[string]string{"amount": "1000ucore"}},
+		{Method: http.MethodPost, Path: "/api/faucet/v1/admin/refills/abc/broadcast", Auth: "Bearer env-secret"},
+		{Method: http.MethodGet, Path: "/api/faucet/v1/admin/jobs", Auth: "Bearer env-secret"},
+		{Method: http.MethodPost, Path: "/api/faucet/v1/admin/jobs/gc/run", Auth: "Bearer env-secret"},
+		{Method: http.MethodGet, Path: "/api/faucet/v1/admin/config/changes", Auth: "Bearer env-secret"},
+	}, requests)
+}
+
+func TestRunErrors(t *testing.T) {
+	requireT := require.New(t)
+
+	var requests []recordedRequest
+	server := newTestServer(t, &requests)
+	ctx := context.Background()
+	t.Setenv(EnvToken, "")
+
+	err := Run(ctx, []string{"unblock-address", "unknown", "--url", server.URL, "--token", "secret"}, io.Discard, io.Discard)
+	var apiErr APIError
+	requireT.ErrorAs(err, &apiErr)
+	requireT.Equal(APIError{Status: http.StatusNotFound, Kind: "address.not_blocked", Message: "address is not blocked"}, apiErr)
+
+	requireT.Error(Run(ctx, []string{"unknown"}, io.Discard, io.Discard))
+	requireT.Error(Run(ctx, []string{"set-amount", "--url", server.URL, "--token", "secret"}, io.Discard, io.Discard))
+	requireT.Error(Run(ctx, []string{"resume", "--url", server.URL, "--token", ""}, io.Discard, io.Discard))
+	requireT.Error(Run(ctx, []string{"resume", "--url", server.URL, "--token", "secret", "--cert", "cert.pem"},
+		io.Discard, io.Discard))
+	requireT.Len(requests, 1)
+}
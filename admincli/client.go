@@ -0,0 +1,160 @@
+// Package admincli implements `faucet admin` subcommands calling the admin API, so operations may be scripted
+// and included in runbooks.
+package admincli
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const requestTimeout = 30 * time.Second
+
+// Config configures the connection to the faucet.
+type Config struct {
+	// URL is the base URL of the faucet, e.g. https://faucet.example.com.
+	URL string
+	// Token is the admin token of the faucet.
+	Token string
+	// CertFile and KeyFile are the client certificate presented to the proxy terminating mutual TLS.
+	CertFile string
+	KeyFile  string
+	// CAFile is the CA verifying the server certificate, system roots are used if empty.
+	CAFile string
+	// Actor names the operator in the configuration changes made by the requests, the faucet records the IP
+	// of the caller if it is empty.
+	Actor string
+}
+
+// Client calls the admin API.
+type Client struct {
+	baseURL string
+	token   string
+	actor   string
+	client  *http.Client
+}
+
+// NewClient returns the client authenticating with the admin token and the client certificate, if configured.
+func NewClient(cfg Config) (Client, error) {
+	if cfg.URL == "" {
+		return Client{}, errors.New("faucet URL is required")
+	}
+	if cfg.Token == "" {
+		return Client{}, errors.New("admin token is required")
+	}
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return Client{}, errors.New("client certificate and key must be set together")
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return Client{}, errors.Wrap(err, "loading client certificate failed")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if cfg.CAFile != "" {
+		ca, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return Client{}, errors.Wrap(err, "reading CA file failed")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return Client{}, errors.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return Client{
+		baseURL: strings.TrimSuffix(cfg.URL, "/") + "/api/faucet/v1",
+		token:   cfg.Token,
+		actor:   cfg.Actor,
+		client:  &http.Client{Transport: transport, Timeout: requestTimeout},
+	}, nil
+}
+
+// APIError is the error returned by the faucet.
+type APIError struct {
+	Status  int
+	Kind    string
+	Message string
+}
+
+func (e APIError) Error() string {
+	if e.Kind == "" {
+		return fmt.Sprintf("faucet returned %d: %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("faucet returned %d (%s): %s", e.Status, e.Kind, e.Message)
+}
+
+// Do sends the request with JSON body, if it is not nil, and returns the raw JSON response.
+func (c Client) Do(ctx context.Context, method, path string, body interface{}) (json.RawMessage, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if c.actor != "" {
+		req.Header.Set("X-Faucet-Actor", c.actor)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "calling faucet failed")
+	}
+	defer res.Body.Close()
+	payload, err := io.ReadAll(io.LimitReader(res.Body, 10<<20))
+	if err != nil {
+		return nil, errors.Wrap(err, "reading response failed")
+	}
+	if res.StatusCode > 299 {
+		return nil, errors.WithStack(parseAPIError(res.StatusCode, payload))
+	}
+	return payload, nil
+}
+
+func parseAPIError(status int, payload []byte) APIError {
+	var resp struct {
+		Message string `json:"message"`
+		Content []struct {
+			Kind    string `json:"kind"`
+			Message string `json:"message"`
+		} `json:"content"`
+	}
+	apiErr := APIError{Status: status, Message: strings.TrimSpace(string(payload))}
+	if json.Unmarshal(payload, &resp) != nil {
+		return apiErr
+	}
+	switch {
+	case len(resp.Content) > 0:
+		apiErr.Kind = resp.Content[0].Kind
+		apiErr.Message = resp.Content[0].Message
+	case resp.Message != "":
+		apiErr.Message = resp.Message
+	}
+	return apiErr
+}
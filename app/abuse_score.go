@@ -0,0 +1,303 @@
+package app
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+// AbuseDecision is what happens to the request scored by the abuse scorer.
+type AbuseDecision string
+
+// Decisions of the abuse scorer.
+const (
+	AbuseDecisionAllow   AbuseDecision = "allow"
+	AbuseDecisionCaptcha AbuseDecision = "captcha"
+	AbuseDecisionDeny    AbuseDecision = "deny"
+)
+
+// Signals combined into the abuse score. Each signal is from 0 (benign) to 1 (abusive).
+const (
+	// AbuseSignalIPReputation is 1 if the IP is flagged by the IP reputation, e.g. as Tor exit.
+	AbuseSignalIPReputation = "ip_reputation"
+	// AbuseSignalFrequency grows with the number of requests sent from the IP recently.
+	AbuseSignalFrequency = "frequency"
+	// AbuseSignalBalance grows with the balance the recipient holds already.
+	AbuseSignalBalance = "balance"
+	// AbuseSignalCaptcha is 1 minus the score of the captcha token, 0 if there is no token or it carries no score.
+	AbuseSignalCaptcha = "captcha"
+)
+
+// AbuseSignals are the signals known to the abuse scorer.
+var AbuseSignals = []string{AbuseSignalIPReputation, AbuseSignalFrequency, AbuseSignalBalance, AbuseSignalCaptcha}
+
+// CaptchaScorer is implemented by the captcha verifiers scoring the solved tokens, e.g. reCAPTCHA v3.
+type CaptchaScorer interface {
+	// ScoreCaptcha verifies the token like VerifyCaptcha and returns its score from 0 (bot) to 1 (human).
+	ScoreCaptcha(ctx context.Context, token, remoteIP string) (float64, error)
+}
+
+// AbuseScoringConfig configures the abuse scorer.
+type AbuseScoringConfig struct {
+	// Weights of the signals, the score is the sum of the signals multiplied by their weights.
+	Weights map[string]float64
+	// CaptchaThreshold is the score from which the captcha is required, 0 means it is never required.
+	CaptchaThreshold float64
+	// DenyThreshold is the score from which the request is refused, 0 means requests are never refused.
+	DenyThreshold float64
+	// FrequencyLimit is the number of requests from the IP within FrequencyWindow making the frequency signal 1.
+	FrequencyLimit  int
+	FrequencyWindow time.Duration
+	// BalanceLimit is the balance of the recipient making the balance signal 1.
+	BalanceLimit chain.Coin
+}
+
+// AbuseScorer combines the signals of the request into the abuse score and decides whether the request is allowed,
+// requires the captcha or is refused. Request frequencies are tracked in memory, so each instance scores
+// the requests it has seen only.
+type AbuseScorer struct {
+	cfg      AbuseScoringConfig
+	balances BalanceSource
+	captcha  CaptchaVerifier
+	clock    clock.Clock
+
+	decisions *prometheus.CounterVec
+	scores    prometheus.Histogram
+
+	mu       sync.Mutex
+	requests map[string][]time.Time
+}
+
+// NewAbuseScorer returns the abuse scorer querying the balances of the recipients from the source and verifying
+// the captcha tokens by the verifier. Verifier is required if the captcha threshold is set, balance source
+// if the balance signal is weighted.
+func NewAbuseScorer(
+	cfg AbuseScoringConfig,
+	balances BalanceSource,
+	captcha CaptchaVerifier,
+	clk clock.Clock,
+) (*AbuseScorer, error) {
+	for signal, weight := range cfg.Weights {
+		if !isAbuseSignal(signal) {
+			return nil, errors.Errorf("unknown abuse signal %q, expected one of %v", signal, AbuseSignals)
+		}
+		if weight < 0 {
+			return nil, errors.Errorf("weight of abuse signal %q must not be negative", signal)
+		}
+	}
+	if cfg.CaptchaThreshold < 0 || cfg.DenyThreshold < 0 {
+		return nil, errors.New("abuse score thresholds must not be negative")
+	}
+	if cfg.CaptchaThreshold == 0 && cfg.DenyThreshold == 0 {
+		return nil, errors.New("captcha or deny threshold of the abuse score is required")
+	}
+	if cfg.CaptchaThreshold > 0 && cfg.DenyThreshold > 0 && cfg.DenyThreshold <= cfg.CaptchaThreshold {
+		return nil, errors.New("deny threshold of the abuse score must be above the captcha threshold")
+	}
+	if cfg.CaptchaThreshold > 0 && captcha == nil {
+		return nil, errors.New("captcha must be configured to require it by the abuse score")
+	}
+	if cfg.Weights[AbuseSignalFrequency] > 0 && (cfg.FrequencyLimit <= 0 || cfg.FrequencyWindow <= 0) {
+		return nil, errors.New("frequency limit and window are required to weight the request frequency")
+	}
+	if cfg.Weights[AbuseSignalBalance] > 0 && (balances == nil || cfg.BalanceLimit.Amount.IsNil() ||
+		!cfg.BalanceLimit.Amount.IsPositive()) {
+		return nil, errors.New("balance source and limit are required to weight the balance of the recipient")
+	}
+	return &AbuseScorer{
+		cfg:      cfg,
+		balances: balances,
+		captcha:  captcha,
+		clock:    clk,
+		decisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "faucet_abuse_decisions_total",
+			Help: "Number of funding requests scored by the abuse scorer, by decision",
+		}, []string{"decision"}),
+		scores: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "faucet_abuse_score",
+			Help:    "Abuse scores of the funding requests",
+			Buckets: prometheus.LinearBuckets(10, 10, 10),
+		}),
+		requests: map[string][]time.Time{},
+	}, nil
+}
+
+func isAbuseSignal(signal string) bool {
+	for _, s := range AbuseSignals {
+		if s == signal {
+			return true
+		}
+	}
+	return false
+}
+
+// Collectors returns the metrics of the abuse scorer.
+func (s *AbuseScorer) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{s.decisions, s.scores}
+}
+
+// AbuseAssessment is the outcome of scoring the request.
+type AbuseAssessment struct {
+	Score    float64
+	Signals  map[string]float64
+	Decision AbuseDecision
+}
+
+// Assess scores the request funding the address. The captcha token of the requester, if any, is verified,
+// the request whose score requires the captcha is allowed once it is solved.
+func (s *AbuseScorer) Assess(
+	ctx context.Context,
+	requester Requester,
+	address chain.AccAddress,
+) (AbuseAssessment, error) {
+	signals := map[string]float64{}
+	if requester.IPReputation != "" {
+		signals[AbuseSignalIPReputation] = 1
+	}
+	if s.cfg.FrequencyLimit > 0 {
+		signals[AbuseSignalFrequency] = math.Min(
+			float64(s.recordRequest(requester.IP))/float64(s.cfg.FrequencyLimit), 1)
+	}
+	if s.cfg.Weights[AbuseSignalBalance] > 0 {
+		signals[AbuseSignalBalance] = s.balanceSignal(ctx, address)
+	}
+	captchaSolved := false
+	if token := requester.Proofs[ProofCaptcha]; token != "" && s.captcha != nil {
+		signal, err := s.captchaSignal(ctx, token, requester.IP)
+		if err != nil {
+			return AbuseAssessment{}, err
+		}
+		signals[AbuseSignalCaptcha] = signal
+		captchaSolved = true
+	}
+
+	var score float64
+	for signal, value := range signals {
+		score += s.cfg.Weights[signal] * value
+	}
+	decision := AbuseDecisionAllow
+	switch {
+	case s.cfg.DenyThreshold > 0 && score >= s.cfg.DenyThreshold:
+		decision = AbuseDecisionDeny
+	case s.cfg.CaptchaThreshold > 0 && score >= s.cfg.CaptchaThreshold && !captchaSolved:
+		decision = AbuseDecisionCaptcha
+	}
+	s.decisions.WithLabelValues(string(decision)).Inc()
+	s.scores.Observe(score)
+	return AbuseAssessment{Score: score, Signals: signals, Decision: decision}, nil
+}
+
+// recordRequest records the request of the IP and returns the number of its requests within the window.
+// At most the limit of the requests is kept per IP, more of them don't change the signal.
+func (s *AbuseScorer) recordRequest(ip string) int {
+	now := s.clock.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := append(s.recentRequests(s.requests[ip], now), now)
+	if len(requests) > s.cfg.FrequencyLimit {
+		requests = requests[len(requests)-s.cfg.FrequencyLimit:]
+	}
+	s.requests[ip] = requests
+	return len(requests)
+}
+
+// recentRequests returns the requests within the window, the caller must hold the mutex.
+func (s *AbuseScorer) recentRequests(requests []time.Time, now time.Time) []time.Time {
+	since := now.Add(-s.cfg.FrequencyWindow)
+	i := sort.Search(len(requests), func(i int) bool {
+		return requests[i].After(since)
+	})
+	return requests[i:]
+}
+
+// Prune forgets the IPs without requests within the window, so the memory doesn't grow with the IPs seen once.
+func (s *AbuseScorer) Prune() {
+	now := s.clock.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ip, requests := range s.requests {
+		if recent := s.recentRequests(requests, now); len(recent) > 0 {
+			s.requests[ip] = recent
+		} else {
+			delete(s.requests, ip)
+		}
+	}
+}
+
+// balanceSignal returns the balance of the recipient relative to the limit. If the balance can't be queried,
+// the signal is 0, so a lagging node doesn't refuse the requests.
+func (s *AbuseScorer) balanceSignal(ctx context.Context, address chain.AccAddress) float64 {
+	limit := s.cfg.BalanceLimit
+	balance, err := s.balances.Balance(ctx, address, limit.Denom)
+	if err != nil {
+		logger.Get(ctx).Warn("Querying balance of the recipient failed, it is not scored",
+			zap.String("address", address.String()), zap.Error(err))
+		return 0
+	}
+	if balance.GTE(limit.Amount) {
+		return 1
+	}
+	ratio, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(balance.BigInt()),
+		new(big.Float).SetInt(limit.Amount.BigInt()),
+	).Float64()
+	return ratio
+}
+
+// captchaSignal verifies the token and returns 1 minus its score. Tokens of the verifiers which don't score them
+// are only verified.
+func (s *AbuseScorer) captchaSignal(ctx context.Context, token, remoteIP string) (float64, error) {
+	scorer, ok := s.captcha.(CaptchaScorer)
+	if !ok {
+		return 0, s.captcha.VerifyCaptcha(ctx, token, remoteIP)
+	}
+	score, err := scorer.ScoreCaptcha(ctx, token, remoteIP)
+	if err != nil {
+		return 0, err
+	}
+	return 1 - math.Max(0, math.Min(score, 1)), nil
+}
+
+// WithAbuseScoring returns a copy of the app scoring the funding requests by the scorer. Requests scored above
+// the captcha threshold must carry the solved captcha token, the ones above the deny threshold are refused.
+// Requests authenticated by the admin token, API key or bypass token are not scored.
+func (a App) WithAbuseScoring(scorer *AbuseScorer) App {
+	a.abuseScorer = scorer
+	return a
+}
+
+// checkAbuseScore applies the decision of the abuse scorer to the request.
+func (a App) checkAbuseScore(ctx context.Context, requester Requester, address chain.AccAddress) error {
+	if a.abuseScorer == nil || requester.Admin || requester.APIKeyHolder != "" || requester.BypassTokenID != "" {
+		return nil
+	}
+	assessment, err := a.abuseScorer.Assess(ctx, requester, address)
+	if err != nil {
+		return err
+	}
+	switch assessment.Decision {
+	case AbuseDecisionDeny:
+		logger.Get(ctx).Info("Request refused by abuse score",
+			zap.Float64("score", assessment.Score), zap.Any("signals", assessment.Signals))
+		err := errors.Wrapf(ErrAbuseSuspected, "abuse score %.1f", assessment.Score)
+		a.ReportBlocked(ctx, requester, address.String(), err)
+		return err
+	case AbuseDecisionCaptcha:
+		return errors.Wrapf(ErrCaptchaFailed, "captcha token is required, abuse score %.1f", assessment.Score)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockCaptchaScorer struct {
+	mockCaptcha
+	score float64
+}
+
+func (m *mockCaptchaScorer) ScoreCaptcha(ctx context.Context, token, remoteIP string) (float64, error) {
+	if err := m.VerifyCaptcha(ctx, token, remoteIP); err != nil {
+		return 0, err
+	}
+	return m.score, nil
+}
+
+func TestAbuseScore(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	cfg := AbuseScoringConfig{
+		Weights: map[string]float64{
+			AbuseSignalIPReputation: 50,
+			AbuseSignalFrequency:    30,
+			AbuseSignalBalance:      20,
+			AbuseSignalCaptcha:      40,
+		},
+		CaptchaThreshold: 40,
+		DenyThreshold:    80,
+		FrequencyLimit:   3,
+		FrequencyWindow:  time.Hour,
+		BalanceLimit:     chain.NewCoin("udevcore", chain.NewInt(10000)),
+	}
+	_, err = NewAbuseScorer(AbuseScoringConfig{Weights: map[string]float64{"age": 1}, DenyThreshold: 1}, nil, nil, clk)
+	requireT.Error(err)
+	_, err = NewAbuseScorer(AbuseScoringConfig{CaptchaThreshold: 50, DenyThreshold: 50}, nil, &mockCaptcha{}, clk)
+	requireT.Error(err)
+	_, err = NewAbuseScorer(cfg, nil, &mockCaptcha{}, clk)
+	requireT.Error(err)
+
+	scorer, err := NewAbuseScorer(cfg, &mockBalances{}, &mockCaptcha{}, clk)
+	requireT.NoError(err)
+	batcher := &mockBatcher{txHash: "tx1"}
+	a := New(batcher, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{}, network,
+		chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithAbuseScoring(scorer)
+
+	// 1 of 3 requests from the IP
+	_, err = a.GiveFunds(ctx, Requester{IP: "203.0.113.1"}, address)
+	requireT.NoError(err)
+
+	// flagged IP requires the captcha
+	flagged := Requester{IP: "203.0.113.2", IPReputation: "tor"}
+	_, err = a.GiveFunds(ctx, flagged, address)
+	requireT.ErrorIs(err, ErrCaptchaFailed)
+	flagged.Proofs = Proofs{ProofCaptcha: "solved"}
+	_, err = a.GiveFunds(ctx, flagged, address)
+	requireT.NoError(err)
+
+	// third request from the flagged IP reaches the deny threshold even with the captcha
+	_, err = a.GiveFunds(ctx, flagged, address)
+	requireT.ErrorIs(err, ErrAbuseSuspected)
+	requireT.Equal(2, batcher.calls)
+
+	// automation is not scored
+	_, err = a.GiveFunds(ctx, Requester{IP: "203.0.113.2", IPReputation: "tor", Admin: true}, address)
+	requireT.NoError(err)
+
+	// requests are forgotten once the window elapses
+	clk.Advance(time.Hour)
+	scorer.Prune()
+	requireT.Empty(scorer.requests)
+	_, err = a.GiveFunds(ctx, flagged, address)
+	requireT.NoError(err)
+}
+
+func TestAbuseScoreSignals(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	const hoarderAddress = "devcore1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqp09pnng"
+	_, hoarder, err := parseAddress(hoarderAddress)
+	requireT.NoError(err)
+
+	balances := &mockBalances{balances: map[string]chain.Int{string(hoarder): chain.NewInt(5000)}}
+	captcha := &mockCaptchaScorer{score: 0.1}
+	scorer, err := NewAbuseScorer(AbuseScoringConfig{
+		Weights: map[string]float64{
+			AbuseSignalBalance: 20,
+			AbuseSignalCaptcha: 40,
+		},
+		CaptchaThreshold: 30,
+		BalanceLimit:     chain.NewCoin("udevcore", chain.NewInt(10000)),
+	}, balances, captcha, clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)))
+	requireT.NoError(err)
+
+	assessment, err := scorer.Assess(ctx, Requester{IP: "203.0.113.1"}, hoarder)
+	requireT.NoError(err)
+	requireT.InDelta(10, assessment.Score, 0.001)
+	requireT.Equal(AbuseDecisionAllow, assessment.Decision)
+
+	// low captcha score raises the abuse score, but the solved captcha satisfies the captcha decision
+	assessment, err = scorer.Assess(ctx, Requester{IP: "203.0.113.1", Proofs: Proofs{ProofCaptcha: "solved"}}, hoarder)
+	requireT.NoError(err)
+	requireT.InDelta(46, assessment.Score, 0.001)
+	requireT.InDelta(0.9, assessment.Signals[AbuseSignalCaptcha], 0.001)
+	requireT.Equal(AbuseDecisionAllow, assessment.Decision)
+
+	_, err = scorer.Assess(ctx, Requester{IP: "203.0.113.1", Proofs: Proofs{ProofCaptcha: "bot"}}, hoarder)
+	requireT.ErrorIs(err, ErrCaptchaFailed)
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var recipientNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// Recipient is the named internal recipient of the address book.
+type Recipient struct {
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// AddressBook persists named recipients.
+type AddressBook interface {
+	PutRecipient(ctx context.Context, recipient Recipient) error
+	// Recipient returns the recipient or ErrRecipientNotFound.
+	Recipient(ctx context.Context, name string) (Recipient, error)
+	Recipients(ctx context.Context) ([]Recipient, error)
+	// DeleteRecipient deletes the recipient or returns ErrRecipientNotFound.
+	DeleteRecipient(ctx context.Context, name string) error
+}
+
+// WithAddressBook returns a copy of the app resolving recipients using the address book.
+func (a App) WithAddressBook(book AddressBook) App {
+	a.addressBook = book
+	return a
+}
+
+// PutRecipient adds the recipient to the address book or updates its address.
+func (a App) PutRecipient(ctx context.Context, name, address string) (Recipient, error) {
+	if !recipientNameRegexp.MatchString(name) {
+		return Recipient{}, errors.Wrapf(ErrInvalidRecipientName, "name %q doesn't match %s", name, recipientNameRegexp)
+	}
+	if _, err := a.validateAddress(address); err != nil {
+		return Recipient{}, err
+	}
+
+	recipient := Recipient{Name: name, Address: address, UpdatedAt: a.clock.Now().UTC()}
+	if err := a.addressBook.PutRecipient(ctx, recipient); err != nil {
+		return Recipient{}, err
+	}
+	return recipient, nil
+}
+
+// Recipients returns all the recipients of the address book ordered by name.
+func (a App) Recipients(ctx context.Context) ([]Recipient, error) {
+	return a.addressBook.Recipients(ctx)
+}
+
+// DeleteRecipient removes the recipient from the address book.
+func (a App) DeleteRecipient(ctx context.Context, name string) error {
+	return a.addressBook.DeleteRecipient(ctx, name)
+}
+
+// RecipientAddress returns the address of the named recipient.
+func (a App) RecipientAddress(ctx context.Context, name string) (string, error) {
+	recipient, err := a.addressBook.Recipient(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return recipient.Address, nil
+}
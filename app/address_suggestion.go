@@ -0,0 +1,70 @@
+package app
+
+import (
+	"strings"
+)
+
+const (
+	// bech32Charset is the alphabet of the data part of bech32 addresses.
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	// maxSuggestedLength bounds the work spent on suggestions, it covers 32-byte addresses with long prefixes.
+	maxSuggestedLength = 100
+)
+
+// suggestAddress returns the valid address of the prefix differing from the invalid one by casing or a single
+// substituted, transposed, missing or extra character. Nothing is suggested if there are many candidates,
+// because a wrong suggestion would send the funds to someone else.
+func suggestAddress(address, prefix string) string {
+	address = strings.TrimSpace(address)
+	if len(address) > maxSuggestedLength {
+		return ""
+	}
+	lowered := strings.ToLower(address)
+	if lowered != address && isValidAddress(lowered, prefix) {
+		return lowered
+	}
+
+	separator := strings.LastIndex(lowered, "1")
+	if separator < 0 {
+		return ""
+	}
+	dataStart := separator + 1
+
+	candidates := map[string]bool{}
+	try := func(candidate string) {
+		if candidate != lowered && isValidAddress(candidate, prefix) {
+			candidates[candidate] = true
+		}
+	}
+	for i := dataStart; i < len(lowered); i++ {
+		// substituted character
+		for _, c := range bech32Charset {
+			try(lowered[:i] + string(c) + lowered[i+1:])
+		}
+		// extra character
+		try(lowered[:i] + lowered[i+1:])
+		// transposed characters
+		if i+1 < len(lowered) {
+			try(lowered[:i] + lowered[i+1:i+2] + lowered[i:i+1] + lowered[i+2:])
+		}
+	}
+	// missing character
+	for i := dataStart; i <= len(lowered); i++ {
+		for _, c := range bech32Charset {
+			try(lowered[:i] + string(c) + lowered[i:])
+		}
+	}
+
+	if len(candidates) != 1 {
+		return ""
+	}
+	for candidate := range candidates {
+		return candidate
+	}
+	return ""
+}
+
+func isValidAddress(address, prefix string) bool {
+	hrp, _, err := parseAddress(address)
+	return err == nil && hrp == prefix
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestAddress(t *testing.T) {
+	const valid = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+
+	testCases := []struct {
+		name       string
+		address    string
+		suggestion string
+	}{
+		{name: "substituted", address: strings.Replace(valid, "xxy", "xzy", 1), suggestion: valid},
+		{name: "mixed case", address: strings.Replace(valid, "krrr", "KRRR", 1), suggestion: valid},
+		{name: "transposed", address: strings.Replace(valid, "948n", "498n", 1), suggestion: valid},
+		{name: "missing", address: strings.Replace(valid, "q6kr", "q6r", 1), suggestion: valid},
+		{name: "extra", address: strings.Replace(valid, "svaz", "svvaz", 1), suggestion: valid},
+		{name: "surrounding spaces", address: " " + strings.Replace(valid, "xxy", "xzy", 1) + " ", suggestion: valid},
+		{name: "two substitutions", address: strings.Replace(strings.Replace(valid, "xxy", "xzy", 1), "svaz", "sqaz", 1)},
+		{name: "different prefix", address: "cosmos169ltjnyvfcxhfxa03xc6qdsu9068ceynym2awx"},
+		{name: "garbage", address: "not an address"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.suggestion, suggestAddress(tc.address, "devcore"))
+		})
+	}
+}
@@ -2,58 +2,442 @@ package app
 
 import (
 	"context"
+	"time"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
 
-	"github.com/CoreumFoundation/coreum/pkg/config"
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/attribution"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
 )
 
 // App implements core functionality.
 type App struct {
 	batcher        Batcher
-	transferAmount sdk.Coin
-	network        config.Network
+	chain          ChainClient
+	txTracker      *TxTracker
+	history        HistoryStore
+	ledger         LedgerStore
+	transferAmount chain.Coin
+	network        chain.Network
+	clock          clock.Clock
+
+	exampleTxSigner ExampleTxSigner
+	maxQueueDepth   int
+	addressBook     AddressBook
+	events          *EventBus
+	tenantFeeDenoms map[string]string
+	// maxTransferAmount is the absolute maximum of a single transfer, nil or zero means no limit.
+	maxTransferAmount   chain.Int
+	txAttribution       bool
+	congestion          *CongestionMonitor
+	claimCodes          ClaimCodeStore
+	bypassTokens        BypassTokenStore
+	namedAccounts       namedAccounts
+	ipAnonymizer        *IPAnonymizer
+	queryStore          QueryStore
+	signing             *signingStatus
+	challenges          *challenges
+	callbacks           CallbackValidator
+	verifiers           []Verifier
+	pow                 *powVerifier
+	ownership           *signedChallenges
+	identity            *identityVerifier
+	configAudit         ConfigAuditStore
+	refills             *refills
+	tenantNotifications TenantNotificationStore
+	denomMetadata       *denomMetadataCache
+	controls            *controls
+	blocklist           Blocklist
+	cooldown            addressCooldown
+	experiment          Experiment
+	tos                 *TermsOfService
+	budgets             map[string]*dailyBudget
+	email               *emailVerifier
+	phone               *phoneVerifier
+	fundingLinks        *fundingLinks
+	lifetimeCap         lifetimeCap
+	balanceThreshold    balanceThreshold
+	transparency        *transparency
+	abuseScorer         *AbuseScorer
+	qrLinkTemplate      string
 }
 
 // New returns a new instance of the App.
 func New(
 	batcher Batcher,
-	network config.Network,
-	transferAmount sdk.Coin,
+	chain ChainClient,
+	txTracker *TxTracker,
+	history HistoryStore,
+	ledger LedgerStore,
+	network chain.Network,
+	transferAmount chain.Coin,
 ) App {
 	return App{
 		batcher:        batcher,
+		chain:          chain,
+		txTracker:      txTracker,
+		history:        history,
+		ledger:         ledger,
 		network:        network,
 		transferAmount: transferAmount,
+		clock:          clock.System{},
+		signing:        &signingStatus{},
+		controls:       &controls{},
 	}
 }
 
+// WithClock returns a copy of the app using the clock to timestamp fundings, budgets and reports.
+func (a App) WithClock(clock clock.Clock) App {
+	a.clock = clock
+	return a
+}
+
+// Now returns the current time of the app clock, which may be fast-forwarded on test networks.
+func (a App) Now() time.Time {
+	return a.clock.Now().UTC()
+}
+
+// WithEventBus returns a copy of the app publishing the events of funding requests on the bus.
+func (a App) WithEventBus(bus *EventBus) App {
+	a.events = bus
+	return a
+}
+
+// WithTenantFeeDenoms returns a copy of the app paying the fees of the tenants' requests in the denoms
+// the tenants are mapped to. Fees of other tenants are paid in the gas price denom of the chain.
+func (a App) WithTenantFeeDenoms(feeDenoms map[string]string) App {
+	a.tenantFeeDenoms = feeDenoms
+	return a
+}
+
+// WithMaxTransferAmount returns a copy of the app refusing single transfers above the amount. Zero means no limit.
+func (a App) WithMaxTransferAmount(amount chain.Int) App {
+	a.maxTransferAmount = amount
+	return a
+}
+
+// WithTxAttribution returns a copy of the app attributing the transfers to the tenant, session and API key
+// of the requester in the memo of the transaction, so the spend may be reconciled from on-chain data alone.
+func (a App) WithTxAttribution(enabled bool) App {
+	a.txAttribution = enabled
+	return a
+}
+
+// WithCongestionMonitor returns a copy of the app reducing the grants when the chain is congested.
+func (a App) WithCongestionMonitor(monitor *CongestionMonitor) App {
+	a.congestion = monitor
+	return a
+}
+
+// WithIPAnonymizer returns a copy of the app storing the IPs of the clients anonymized by the anonymizer.
+func (a App) WithIPAnonymizer(anonymizer *IPAnonymizer) App {
+	a.ipAnonymizer = anonymizer
+	return a
+}
+
+// WithMaxQueueDepth returns a copy of the app rejecting requests if the number of requests waiting to be sent
+// reaches the depth. Zero means no limit.
+func (a App) WithMaxQueueDepth(depth int) App {
+	a.maxQueueDepth = depth
+	return a
+}
+
 // Batcher indicates the required functionality to connect to coreum blockchain.
 type Batcher interface {
-	SendToken(ctx context.Context, destAddress sdk.AccAddress, amount sdk.Coin) (string, error)
+	// SendToken sends the amount paying the fee in the fee denom, empty denom means the gas price denom of the chain.
+	// The returned fee is the share of the request in the fee of the transaction. Non-zero attribution is encoded
+	// into the memo of the transaction.
+	SendToken(
+		ctx context.Context,
+		destAddress chain.AccAddress,
+		amount chain.Coin,
+		feeDenom string,
+		attr attribution.Attribution,
+	) (string, chain.Coin, error)
+	// Backlog returns the number of pending requests and the estimated time needed to process them.
+	Backlog() (int, time.Duration)
 }
 
 // GiveFunds gives funds to people asking for it.
-func (a App) GiveFunds(ctx context.Context, address string) (string, error) {
+func (a App) GiveFunds(ctx context.Context, requester Requester, address string) (string, error) {
+	requester, err := a.verifyToSAccepted(requester)
+	if err != nil {
+		return "", err
+	}
+	sdkAddr, err := a.validateAddress(address)
+	if err != nil {
+		return "", err
+	}
+	if err := a.validateCallbackURL(requester.CallbackURL); err != nil {
+		return "", err
+	}
+	if err := validateInvoiceID(requester); err != nil {
+		return "", err
+	}
+	if _, err := a.txTracker.RequiredConfirmations(requester.MinConfirmations); err != nil {
+		return "", err
+	}
+	// human verification runs last, so the proofs, e.g. captcha tokens, aren't spent on the request rejected anyway
+	if err := a.verify(ctx, requester); err != nil {
+		return "", err
+	}
+	if err := a.checkOwnership(ctx, requester, address); err != nil {
+		return "", err
+	}
+	if err := a.checkEmailVerified(ctx, requester, address); err != nil {
+		return "", err
+	}
+	if err := a.checkPhoneVerified(ctx, requester, address); err != nil {
+		return "", err
+	}
+	if err := a.checkAbuseScore(ctx, requester, sdkAddr); err != nil {
+		return "", err
+	}
+
+	return a.sendWithCooldown(ctx, requester, sdkAddr, a.grantAmount(requester))
+}
+
+// send sends the amount to the address, recording the funding and publishing the events of its progress.
+func (a App) send(ctx context.Context, requester Requester, address chain.AccAddress, amount chain.Coin) (string, error) {
+	// the tracker keeps the override only, subscribers get the resolved number, so they don't have to know
+	// the default
+	confirmationsOverride := requester.MinConfirmations
+	minConfirmations, err := a.txTracker.RequiredConfirmations(confirmationsOverride)
+	if err != nil {
+		return "", err
+	}
+	requester.MinConfirmations = minConfirmations
+
+	if err := a.checkPaused(); err != nil {
+		a.ReportBlocked(ctx, requester, address.String(), err)
+		return "", err
+	}
+	if err := a.checkBlocked(ctx, address); err != nil {
+		a.ReportBlocked(ctx, requester, address.String(), err)
+		return "", err
+	}
+	if err := a.checkMaxTransferAmount(ctx, requester, amount); err != nil {
+		a.ReportBlocked(ctx, requester, address.String(), err)
+		return "", err
+	}
+	if err := a.checkBalanceThreshold(ctx, requester, address, amount.Denom); err != nil {
+		a.ReportBlocked(ctx, requester, address.String(), err)
+		return "", err
+	}
+	if err := a.signing.check(a.clock.Now()); err != nil {
+		a.ReportBlocked(ctx, requester, address.String(), err)
+		return "", err
+	}
+	if err := a.checkBackpressure(); err != nil {
+		a.ReportBlocked(ctx, requester, address.String(), err)
+		return "", err
+	}
+	spend, err := a.reserveBudget(ctx, amount)
+	if err != nil {
+		a.ReportBlocked(ctx, requester, address.String(), err)
+		return "", err
+	}
+	capReserved, err := a.reserveLifetimeCap(ctx, requester, address, amount)
+	if err != nil {
+		a.releaseBudget(amount.Denom, spend)
+		a.ReportBlocked(ctx, requester, address.String(), err)
+		return "", err
+	}
+	a.publish(ctx, Event{Kind: EventRequestAccepted, Requester: requester, Address: address.String()})
+
+	txHash, fee, err := a.batcher.SendToken(
+		ctx,
+		address,
+		amount,
+		a.tenantFeeDenoms[requester.Tenant],
+		a.attribution(requester),
+	)
+	a.signing.observe(ctx, a.clock.Now(), err)
+	if err != nil {
+		a.releaseBudget(amount.Denom, spend)
+		if capReserved {
+			a.releaseLifetimeCap(ctx, address, amount)
+		}
+		a.recordIncident(ctx, requester, IncidentKindTransferFailed, err)
+		a.publish(ctx, Event{Kind: EventFailed, Requester: requester, Address: address.String(), Reason: err.Error()})
+		if isSigningFailure(err) {
+			return "", ThrottledError{
+				Cause:           errors.Wrapf(ErrSigningUnavailable, "err:%s", err),
+				NextAvailableAt: a.clock.Now().Add(signingRetryInterval).UTC(),
+			}
+		}
+		return "", errors.Wrapf(ErrUnableToTransferToken, "err:%s", err)
+	}
+	a.recordFunding(ctx, requester, address, txHash, amount, fee)
+	a.recordSpend(ctx, requester, txHash, amount)
+	a.txTracker.AddRequest(txHash, TxRequest{
+		RequestID:             requester.RequestID,
+		Address:               address.String(),
+		RequiredConfirmations: confirmationsOverride,
+	})
+	// logger carries the request ID, so the request is traced to the transaction it is batched into
+	logger.Get(ctx).Info("Request included in transaction", zap.String("txHash", txHash))
+	a.publish(ctx, Event{Kind: EventBroadcast, Requester: requester, Address: address.String(), TxHash: txHash})
+
+	return txHash, nil
+}
+
+// grantAmount returns the amount granted to the requester, reduced if the chain is congested.
+// At least one unit is always granted.
+func (a App) grantAmount(requester Requester) chain.Coin {
+	transferAmount := a.baseTransferAmount()
+	if amount, ok := a.experimentTransferAmount(requester); ok {
+		transferAmount = chain.NewCoin(transferAmount.Denom, amount)
+	}
+	percent := a.congestion.AmountPercent()
+	if percent >= 100 {
+		return transferAmount
+	}
+	amount := transferAmount.Amount.MulRaw(percent).QuoRaw(100)
+	if !amount.IsPositive() {
+		amount = chain.NewInt(1)
+	}
+	return chain.NewCoin(transferAmount.Denom, amount)
+}
+
+// attribution returns the attribution of the transfer requested by the requester. If attribution is disabled,
+// only the hash of the invoice ID is set, so the clients passing the invoice ID may always correlate the transfer.
+func (a App) attribution(requester Requester) attribution.Attribution {
+	var invoiceHash string
+	if requester.InvoiceID != "" {
+		invoiceHash = attribution.HashInvoiceID(requester.InvoiceID)
+	}
+	if !a.txAttribution {
+		return attribution.Attribution{InvoiceHash: invoiceHash}
+	}
+	tenant := requester.Tenant
+	if tenant == "" {
+		tenant = DefaultTenant
+	}
+	return attribution.Attribution{
+		Tenant:      tenant,
+		Session:     requester.Session,
+		APIKeyHash:  requester.APIKeyHash,
+		InvoiceHash: invoiceHash,
+	}
+}
+
+// ReportBlocked publishes the event of the request rejected before reaching the app, e.g. by rate limiting.
+// Address is empty if it is not known yet.
+func (a App) ReportBlocked(ctx context.Context, requester Requester, address string, err error) {
+	a.publish(ctx, Event{Kind: EventBlocked, Requester: requester, Address: address, Reason: err.Error()})
+}
+
+func (a App) publish(ctx context.Context, event Event) {
+	event.Time = a.clock.Now().UTC()
+	a.events.Publish(ctx, event)
+}
+
+// validateAddress parses the address and verifies it belongs to the network.
+func (a App) validateAddress(address string) (chain.AccAddress, error) {
 	prefix, sdkAddr, err := parseAddress(address)
 	if err != nil {
-		return "", errors.Wrapf(ErrInvalidAddressFormat, "err:%s", err)
+		err = errors.Wrapf(ErrInvalidAddressFormat, "err:%s", err)
+		if suggestion := suggestAddress(address, a.network.AddressPrefix()); suggestion != "" {
+			return nil, errors.WithStack(AddressSuggestionError{Cause: err, Suggestion: suggestion})
+		}
+		return nil, err
 	}
 
 	if prefix != a.network.AddressPrefix() {
-		return "", errors.Wrapf(
+		return nil, errors.Wrapf(
 			ErrAddressPrefixUnsupported,
 			"account prefix (%s) does not match expected prefix (%s)",
 			prefix,
 			a.network.AddressPrefix(),
 		)
 	}
+	return sdkAddr, nil
+}
+
+// checkMaxTransferAmount refuses the transfer above the absolute maximum. It is a safety valve against
+// misconfiguration, so the violation is logged as an error and recorded as an incident to alert operators.
+func (a App) checkMaxTransferAmount(ctx context.Context, requester Requester, amount chain.Coin) error {
+	if a.maxTransferAmount.IsNil() || a.maxTransferAmount.IsZero() || amount.Amount.LTE(a.maxTransferAmount) {
+		return nil
+	}
+	err := errors.Wrapf(
+		ErrTransferAboveMaximum,
+		"transfer of %s exceeds the absolute maximum of %s%s",
+		amount,
+		a.maxTransferAmount,
+		amount.Denom,
+	)
+	logger.Get(ctx).Error("Transfer above absolute maximum refused", zap.Stringer("amount", amount), zap.Error(err))
+	a.recordIncident(ctx, requester, IncidentKindTransferAboveMaximum, err)
+	return err
+}
+
+// checkBackpressure rejects the request if too many requests are waiting already. The client is advised to retry
+// once the current backlog is estimated to be processed.
+func (a App) checkBackpressure() error {
+	if a.maxQueueDepth <= 0 {
+		return nil
+	}
+	pending, drainTime := a.batcher.Backlog()
+	if pending < a.maxQueueDepth {
+		return nil
+	}
+	return ThrottledError{
+		Cause:           errors.Wrapf(ErrQueueFull, "%d requests are pending", pending),
+		NextAvailableAt: a.clock.Now().Add(drainTime).UTC(),
+	}
+}
+
+// TxStatus returns the status of the funding transaction.
+func (a App) TxStatus(txHash string) (TxStatus, error) {
+	return a.txTracker.Status(txHash)
+}
+
+// RequestTxStatus returns the status of the transaction the funding request is included in.
+func (a App) RequestTxStatus(requestID string) (TxStatus, error) {
+	return a.txTracker.RequestStatus(requestID)
+}
 
-	txHash, err := a.batcher.SendToken(ctx, sdkAddr, a.transferAmount)
+// recordFunding stores the funding in history. Failure is only logged because the funds are already sent
+// and returning an error would make the client retry.
+func (a App) recordFunding(
+	ctx context.Context,
+	requester Requester,
+	address chain.AccAddress,
+	txHash string,
+	amount chain.Coin,
+	fee chain.Coin,
+) {
+	err := a.history.RecordFunding(ctx, FundingRecord{
+		RequestID:   requester.RequestID,
+		Address:     address.String(),
+		IP:          a.ipAnonymizer.atRest(requester.IP),
+		Fingerprint: requester.Fingerprint,
+		Amount:      amount,
+		Fee:         fee,
+		TxHash:      txHash,
+		Time:        a.clock.Now().UTC(),
+		ToSVersion:  requester.ToSVersion,
+		InvoiceID:   requester.InvoiceID,
+	})
 	if err != nil {
-		return "", errors.Wrapf(ErrUnableToTransferToken, "err:%s", err)
+		logger.Get(ctx).Error("Recording funding history failed", zap.String("txHash", txHash), zap.Error(err))
 	}
+}
 
-	return txHash, nil
+func (a App) recordIncident(ctx context.Context, requester Requester, kind string, incidentErr error) {
+	err := a.history.RecordIncident(ctx, Incident{
+		RequestID: requester.RequestID,
+		Kind:      kind,
+		Message:   incidentErr.Error(),
+		Time:      a.clock.Now().UTC(),
+	})
+	if err != nil {
+		logger.Get(ctx).Error("Recording incident failed", zap.String("kind", kind), zap.Error(err))
+	}
 }
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+type mockHistory struct {
+	fundings  []FundingRecord
+	incidents []Incident
+}
+
+func (m *mockHistory) RecordFunding(ctx context.Context, record FundingRecord) error {
+	m.fundings = append(m.fundings, record)
+	return nil
+}
+
+func (m *mockHistory) FundingsSince(ctx context.Context, since time.Time) ([]FundingRecord, error) {
+	return m.fundings, nil
+}
+
+func (m *mockHistory) RecordIncident(ctx context.Context, incident Incident) error {
+	m.incidents = append(m.incidents, incident)
+	return nil
+}
+
+func (m *mockHistory) IncidentsSince(ctx context.Context, since time.Time) ([]Incident, error) {
+	return m.incidents, nil
+}
+
+func TestCheckMaxTransferAmount(t *testing.T) {
+	requireT := require.New(t)
+
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+	history := &mockHistory{}
+	a := New(nil, nil, nil, history, nil, chain.Network{}, chain.Coin{})
+	requester := Requester{RequestID: "rq1"}
+	amount := chain.NewCoin("ucore", chain.NewInt(1000))
+
+	// no limit by default
+	requireT.NoError(a.checkMaxTransferAmount(ctx, requester, amount))
+
+	a = a.WithMaxTransferAmount(chain.NewInt(1000))
+	requireT.NoError(a.checkMaxTransferAmount(ctx, requester, amount))
+	requireT.Empty(history.incidents)
+
+	err := a.checkMaxTransferAmount(ctx, requester, chain.NewCoin("ucore", chain.NewInt(10000)))
+	requireT.ErrorIs(err, ErrTransferAboveMaximum)
+	requireT.Len(history.incidents, 1)
+	requireT.Equal(IncidentKindTransferAboveMaximum, history.incidents[0].Kind)
+	requireT.Equal("rq1", history.incidents[0].RequestID)
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// BalanceSource returns the balance the address holds on chain.
+type BalanceSource interface {
+	Balance(ctx context.Context, address chain.AccAddress, denom string) (chain.Int, error)
+}
+
+// balanceThreshold refuses funding the addresses holding more than the threshold already.
+type balanceThreshold struct {
+	source    BalanceSource
+	threshold chain.Int
+}
+
+// WithBalanceThreshold returns a copy of the app refusing to fund the addresses which already hold more than
+// the threshold of the transfer denom, the most common pattern of hoarding. Zero threshold disables the check.
+// Requests authorized by the admin token are not refused.
+func (a App) WithBalanceThreshold(source BalanceSource, threshold chain.Int) App {
+	a.balanceThreshold = balanceThreshold{source: source, threshold: threshold}
+	return a
+}
+
+// checkBalanceThreshold queries the balance of the address and refuses it above the threshold. If the balance
+// can't be queried, the request is let through, so a lagging node doesn't take the faucet down.
+func (a App) checkBalanceThreshold(
+	ctx context.Context,
+	requester Requester,
+	address chain.AccAddress,
+	denom string,
+) error {
+	t := a.balanceThreshold
+	if t.source == nil || t.threshold.IsNil() || t.threshold.IsZero() || requester.Admin {
+		return nil
+	}
+	balance, err := t.source.Balance(ctx, address, denom)
+	if err != nil {
+		logger.Get(ctx).Warn("Querying balance of the recipient failed, threshold is not checked",
+			zap.String("address", address.String()), zap.Error(err))
+		return nil
+	}
+	if balance.GT(t.threshold) {
+		return errors.Wrapf(ErrBalanceAboveThreshold, "balance %s%s exceeds the threshold of %s%s",
+			balance, denom, t.threshold, denom)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockBalances struct {
+	// balances are keyed by the address bytes
+	balances map[string]chain.Int
+	err      error
+}
+
+func (m *mockBalances) Balance(_ context.Context, address chain.AccAddress, _ string) (chain.Int, error) {
+	if m.err != nil {
+		return chain.Int{}, m.err
+	}
+	if balance, ok := m.balances[string(address)]; ok {
+		return balance, nil
+	}
+	return chain.NewInt(0), nil
+}
+
+func TestBalanceThreshold(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const (
+		address        = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+		hoarderAddress = "devcore1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqp09pnng"
+	)
+
+	_, sdkAddr, err := parseAddress(address)
+	requireT.NoError(err)
+	_, hoarderSDKAddr, err := parseAddress(hoarderAddress)
+	requireT.NoError(err)
+
+	batcher := &mockBatcher{txHash: "tx1"}
+	balances := &mockBalances{balances: map[string]chain.Int{
+		string(sdkAddr):        chain.NewInt(5000),
+		string(hoarderSDKAddr): chain.NewInt(5001),
+	}}
+	a := New(batcher, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{}, network,
+		chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithBalanceThreshold(balances, chain.NewInt(5000))
+
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.NoError(err)
+	_, err = a.GiveFunds(ctx, Requester{}, hoarderAddress)
+	requireT.ErrorIs(err, ErrBalanceAboveThreshold)
+	requireT.Equal(1, batcher.calls)
+
+	// admin is not refused
+	_, err = a.GiveFunds(ctx, Requester{Admin: true}, hoarderAddress)
+	requireT.NoError(err)
+
+	// failed query doesn't block funding
+	balances.err = errors.New("node unavailable")
+	_, err = a.GiveFunds(ctx, Requester{}, hoarderAddress)
+	requireT.NoError(err)
+	requireT.Equal(3, batcher.calls)
+}
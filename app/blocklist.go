@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// BlockedAddress is the address the faucet refuses to fund.
+type BlockedAddress struct {
+	Address   string    `json:"address"`
+	Reason    string    `json:"reason,omitempty"`
+	BlockedAt time.Time `json:"blockedAt"`
+}
+
+// Blocklist persists the blocked addresses, keyed by the address bytes.
+type Blocklist interface {
+	BlockAddress(ctx context.Context, address chain.AccAddress, entry BlockedAddress) error
+	UnblockAddress(ctx context.Context, address chain.AccAddress) error
+	BlockedAddress(ctx context.Context, address chain.AccAddress) (BlockedAddress, bool, error)
+	BlockedAddresses(ctx context.Context) ([]BlockedAddress, error)
+}
+
+// WithBlocklist returns a copy of the app refusing to fund the addresses blocked by the operators.
+func (a App) WithBlocklist(blocklist Blocklist) App {
+	a.blocklist = blocklist
+	return a
+}
+
+// BlockAddress blocks funding of the address, replacing the reason if it is blocked already.
+func (a App) BlockAddress(ctx context.Context, address, reason string) (BlockedAddress, error) {
+	if a.blocklist == nil {
+		return BlockedAddress{}, errors.New("blocklist is not configured")
+	}
+	sdkAddr, err := a.validateAddress(address)
+	if err != nil {
+		return BlockedAddress{}, err
+	}
+	entry := BlockedAddress{
+		Address:   strings.ToLower(address),
+		Reason:    reason,
+		BlockedAt: a.clock.Now().UTC(),
+	}
+	if err := a.blocklist.BlockAddress(ctx, sdkAddr, entry); err != nil {
+		return BlockedAddress{}, err
+	}
+	logger.Get(ctx).Warn("Address blocked", zap.String("address", entry.Address), zap.String("reason", reason))
+	return entry, nil
+}
+
+// UnblockAddress allows funding of the blocked address again.
+func (a App) UnblockAddress(ctx context.Context, address string) error {
+	if a.blocklist == nil {
+		return errors.Wrapf(ErrAddressNotBlocked, "address: %s", address)
+	}
+	sdkAddr, err := a.validateAddress(address)
+	if err != nil {
+		return err
+	}
+	return a.blocklist.UnblockAddress(ctx, sdkAddr)
+}
+
+// BlockedAddresses returns all the blocked addresses.
+func (a App) BlockedAddresses(ctx context.Context) ([]BlockedAddress, error) {
+	if a.blocklist == nil {
+		return []BlockedAddress{}, nil
+	}
+	return a.blocklist.BlockedAddresses(ctx)
+}
+
+func (a App) checkBlocked(ctx context.Context, address chain.AccAddress) error {
+	if a.blocklist == nil {
+		return nil
+	}
+	entry, blocked, err := a.blocklist.BlockedAddress(ctx, address)
+	if err != nil {
+		return err
+	}
+	if !blocked {
+		return nil
+	}
+	if entry.Reason != "" {
+		return errors.Wrapf(ErrAddressBlocked, "address %s: %s", entry.Address, entry.Reason)
+	}
+	return errors.Wrapf(ErrAddressBlocked, "address %s", entry.Address)
+}
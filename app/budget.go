@@ -0,0 +1,194 @@
+package app
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// BudgetWindow is the rolling window the daily budget applies to.
+const BudgetWindow = 24 * time.Hour
+
+// dailyBudget caps the total amount of the denom sent in the rolling window, so misconfiguration or abuse can't
+// drain the wallet. Spends of the window are loaded from the history once and tracked in memory afterwards.
+// Each denom has its own budget, so exhausting one of them doesn't block grants of the others.
+type dailyBudget struct {
+	limit chain.Coin
+
+	mu     sync.Mutex
+	loaded bool
+	spends []*budgetSpend
+}
+
+type budgetSpend struct {
+	time   time.Time
+	amount chain.Int
+}
+
+// BudgetStatus is the state of the daily budget of the denom.
+type BudgetStatus struct {
+	Limit     chain.Coin
+	Spent     chain.Coin
+	Remaining chain.Coin
+	// NextReleaseAt is the time the oldest spend leaves the window, zero if nothing is spent.
+	NextReleaseAt time.Time
+}
+
+// WithDailyBudget returns a copy of the app refusing transfers of the denom once the total amount of it sent
+// in the rolling 24h window would exceed its limit. Denoms without positive limit are not capped.
+func (a App) WithDailyBudget(limits ...chain.Coin) App {
+	a.budgets = map[string]*dailyBudget{}
+	for _, limit := range limits {
+		if limit.Amount.IsPositive() {
+			a.budgets[limit.Denom] = &dailyBudget{limit: limit}
+		}
+	}
+	return a
+}
+
+// Budgets returns the status of the daily budgets ordered by denom.
+func (a App) Budgets(ctx context.Context) ([]BudgetStatus, error) {
+	denoms := make([]string, 0, len(a.budgets))
+	for denom := range a.budgets {
+		denoms = append(denoms, denom)
+	}
+	sort.Strings(denoms)
+
+	now := a.clock.Now().UTC()
+	statuses := make([]BudgetStatus, 0, len(denoms))
+	for _, denom := range denoms {
+		status, err := a.budgets[denom].status(ctx, a.history, now)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// reserveBudget reserves the amount in the budget of its denom. The returned reservation, nil if the denom
+// is not subject to the budget, must be released if the transfer fails.
+func (a App) reserveBudget(ctx context.Context, amount chain.Coin) (*budgetSpend, error) {
+	b := a.budgets[amount.Denom]
+	if b == nil {
+		return nil, nil
+	}
+	now := a.clock.Now().UTC()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	spent, err := b.spentLocked(ctx, a.history, now)
+	if err != nil {
+		return nil, err
+	}
+	if spent.Add(amount.Amount).GT(b.limit.Amount) {
+		return nil, ThrottledError{
+			Cause: BudgetExceededError{
+				Cause: errors.Wrapf(ErrBudgetExceeded, "%s%s of the daily budget of %s is spent already",
+					spent, amount.Denom, b.limit),
+				Denom: amount.Denom,
+			},
+			NextAvailableAt: b.resetAtLocked(now, spent, amount.Amount),
+		}
+	}
+	spend := &budgetSpend{time: now, amount: amount.Amount}
+	b.spends = append(b.spends, spend)
+	return spend, nil
+}
+
+// releaseBudget gives back the reservation of the failed transfer of the denom.
+func (a App) releaseBudget(denom string, spend *budgetSpend) {
+	if spend == nil {
+		return
+	}
+	b := a.budgets[denom]
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, s := range b.spends {
+		if s == spend {
+			b.spends = append(b.spends[:i], b.spends[i+1:]...)
+			return
+		}
+	}
+}
+
+func (b *dailyBudget) status(ctx context.Context, history HistoryStore, now time.Time) (BudgetStatus, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	spent, err := b.spentLocked(ctx, history, now)
+	if err != nil {
+		return BudgetStatus{}, err
+	}
+	status := BudgetStatus{
+		Limit:     b.limit,
+		Spent:     chain.NewCoin(b.limit.Denom, spent),
+		Remaining: chain.NewCoin(b.limit.Denom, chain.NewInt(0)),
+	}
+	if spent.LT(b.limit.Amount) {
+		status.Remaining = b.limit.Sub(status.Spent)
+	}
+	if len(b.spends) > 0 {
+		status.NextReleaseAt = b.spends[0].time.Add(BudgetWindow).UTC()
+	}
+	return status, nil
+}
+
+// spentLocked returns the total amount spent in the window, loading the spends from the history first if needed.
+func (b *dailyBudget) spentLocked(ctx context.Context, history HistoryStore, now time.Time) (chain.Int, error) {
+	if !b.loaded {
+		if err := b.loadLocked(ctx, history, now); err != nil {
+			return chain.Int{}, err
+		}
+	}
+	b.pruneLocked(now)
+
+	spent := chain.NewInt(0)
+	for _, s := range b.spends {
+		spent = spent.Add(s.amount)
+	}
+	return spent, nil
+}
+
+func (b *dailyBudget) loadLocked(ctx context.Context, history HistoryStore, now time.Time) error {
+	records, err := history.FundingsSince(ctx, now.Add(-BudgetWindow))
+	if err != nil {
+		return errors.Wrap(err, "loading spends of the daily budget failed")
+	}
+	for _, r := range records {
+		if r.Amount.Denom == b.limit.Denom {
+			b.spends = append(b.spends, &budgetSpend{time: r.Time, amount: r.Amount.Amount})
+		}
+	}
+	sort.SliceStable(b.spends, func(i, j int) bool { return b.spends[i].time.Before(b.spends[j].time) })
+	b.loaded = true
+	return nil
+}
+
+// pruneLocked forgets the spends which left the window.
+func (b *dailyBudget) pruneLocked(now time.Time) {
+	windowStart := now.Add(-BudgetWindow)
+	var i int
+	for i < len(b.spends) && !b.spends[i].time.After(windowStart) {
+		i++
+	}
+	b.spends = b.spends[i:]
+}
+
+// resetAtLocked returns the time enough spends leave the window for the amount to fit in the budget.
+func (b *dailyBudget) resetAtLocked(now time.Time, spent, amount chain.Int) time.Time {
+	for _, s := range b.spends {
+		spent = spent.Sub(s.amount)
+		if spent.Add(amount).LTE(b.limit.Amount) {
+			return s.time.Add(BudgetWindow).UTC()
+		}
+	}
+	// the amount alone exceeds the budget
+	return now.Add(BudgetWindow)
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+func TestDailyBudget(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+
+	now := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewManual(now)
+	// funding recorded before the restart counts against the budget
+	history := &mockHistory{fundings: []FundingRecord{
+		{Amount: chain.NewCoin("udevcore", chain.NewInt(1000)), Time: now.Add(-12 * time.Hour)},
+	}}
+	batcher := &mockBatcher{txHash: "tx1"}
+	a := New(batcher, nil, NewTxTracker(nil, 1, nil, clock.System{}), history, &mockLedger{}, network,
+		chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clk).
+		WithDailyBudget(chain.NewCoin("udevcore", chain.NewInt(3000)))
+
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.NoError(err)
+
+	// failed transfer gives the reservation back
+	batcher.err = errors.New("boom")
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.ErrorIs(err, ErrUnableToTransferToken)
+	batcher.err = nil
+
+	// admin is capped too
+	clk.Advance(time.Hour)
+	_, err = a.GiveFunds(ctx, Requester{Admin: true}, address)
+	requireT.NoError(err)
+	_, err = a.GiveFunds(ctx, Requester{Admin: true}, address)
+	requireT.ErrorIs(err, ErrBudgetExceeded)
+	var throttled ThrottledError
+	requireT.ErrorAs(err, &throttled)
+	// the funding recorded before the restart leaves the window first
+	requireT.Equal(now.Add(12*time.Hour), throttled.NextAvailableAt)
+	requireT.Equal(3, batcher.calls)
+
+	clk.Advance(11 * time.Hour)
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.NoError(err)
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.ErrorIs(err, ErrBudgetExceeded)
+	requireT.ErrorAs(err, &throttled)
+	requireT.Equal(now.Add(24*time.Hour), throttled.NextAvailableAt)
+}
+
+func TestDenomBudgets(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+
+	now := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	history := &mockHistory{fundings: []FundingRecord{
+		{Amount: chain.NewCoin("uusdc", chain.NewInt(400)), Time: now.Add(-time.Hour)},
+		{Amount: chain.NewCoin("udevcore", chain.NewInt(100)), Time: now.Add(-2 * time.Hour)},
+	}}
+	a := New(&mockBatcher{}, nil, NewTxTracker(nil, 1, nil, clock.System{}), history, &mockLedger{}, network,
+		chain.NewCoin("udevcore", chain.NewInt(100))).
+		WithClock(clock.NewManual(now)).
+		WithDailyBudget(
+			chain.NewCoin("udevcore", chain.NewInt(1000)),
+			chain.NewCoin("uusdc", chain.NewInt(500)),
+			chain.NewCoin("uatom", chain.NewInt(0)),
+		)
+
+	// exhausted stablecoin budget doesn't block native grants
+	_, err = a.reserveBudget(ctx, chain.NewCoin("uusdc", chain.NewInt(200)))
+	requireT.ErrorIs(err, ErrBudgetExceeded)
+	var exceeded BudgetExceededError
+	requireT.ErrorAs(err, &exceeded)
+	requireT.Equal("uusdc", exceeded.Denom)
+	spend, err := a.reserveBudget(ctx, chain.NewCoin("udevcore", chain.NewInt(200)))
+	requireT.NoError(err)
+	requireT.NotNil(spend)
+	// denoms without budget are not capped
+	spend, err = a.reserveBudget(ctx, chain.NewCoin("uatom", chain.NewInt(1000000)))
+	requireT.NoError(err)
+	requireT.Nil(spend)
+
+	budgets, err := a.Budgets(ctx)
+	requireT.NoError(err)
+	requireT.Equal([]BudgetStatus{
+		{
+			Limit:         chain.NewCoin("udevcore", chain.NewInt(1000)),
+			Spent:         chain.NewCoin("udevcore", chain.NewInt(300)),
+			Remaining:     chain.NewCoin("udevcore", chain.NewInt(700)),
+			NextReleaseAt: now.Add(22 * time.Hour),
+		},
+		{
+			Limit:         chain.NewCoin("uusdc", chain.NewInt(500)),
+			Spent:         chain.NewCoin("uusdc", chain.NewInt(400)),
+			Remaining:     chain.NewCoin("uusdc", chain.NewInt(100)),
+			NextReleaseAt: now.Add(23 * time.Hour),
+		},
+	}, budgets)
+}
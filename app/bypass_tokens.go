@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+)
+
+const (
+	// bypassTokenPrefix marks the bypass tokens, so they are recognized when leaked.
+	bypassTokenPrefix = "fbt_"
+	// bypassTokenIDLength is the number of hex characters of the token hash identifying the token.
+	bypassTokenIDLength = 16
+)
+
+var bypassTokenHolderRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,64}$`)
+
+// BypassToken exempts its holder, e.g. the CI pipeline, from the IP rate limits and the address cooldown.
+// Requests are limited by the quota of the token instead. Only the hash of the token is stored.
+type BypassToken struct {
+	ID     string `json:"id"`
+	Holder string `json:"holder"`
+	Hash   string `json:"hash"`
+	// Quota is the number of requests allowed in the period, the whole lifetime of the token if the period is zero.
+	Quota  uint64        `json:"quota"`
+	Period time.Duration `json:"period"`
+	// Used is the number of requests made since WindowStart.
+	Used        uint64    `json:"used"`
+	WindowStart time.Time `json:"windowStart"`
+	CreatedAt   time.Time `json:"createdAt"`
+	// ExpiresAt is the time the token is not accepted anymore and is collected, zero means it never expires.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// expired tells if the token is not accepted at the time anymore.
+func (t BypassToken) expired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !now.Before(t.ExpiresAt)
+}
+
+// Consume returns the token with one request of its quota consumed at the time. The quota is renewed once
+// the period passes since the start of the window.
+func (t BypassToken) Consume(now time.Time) (BypassToken, error) {
+	if t.expired(now) {
+		return t, errors.Wrapf(ErrBypassTokenInvalid, "token %s is expired", t.ID)
+	}
+	if t.Period > 0 && !now.Before(t.WindowStart.Add(t.Period)) {
+		t.Used = 0
+		t.WindowStart = now
+	}
+	if t.Used >= t.Quota {
+		err := errors.Wrapf(ErrBypassTokenQuotaExhausted, "token %s of %q has already used its quota", t.ID, t.Holder)
+		if t.Period == 0 {
+			return t, err
+		}
+		return t, ThrottledError{Cause: err, NextAvailableAt: t.WindowStart.Add(t.Period)}
+	}
+	t.Used++
+	return t, nil
+}
+
+// BypassTokenSpec describes the bypass token to issue.
+type BypassTokenSpec struct {
+	Holder string
+	Quota  uint64
+	Period time.Duration
+	// ExpiresAt is optional expiry time of the token.
+	ExpiresAt time.Time
+}
+
+// BypassTokenStore persists the bypass tokens, keyed by their IDs.
+type BypassTokenStore interface {
+	PutBypassToken(ctx context.Context, token BypassToken) error
+	// BypassTokens returns all the tokens ordered by ID.
+	BypassTokens(ctx context.Context) ([]BypassToken, error)
+	// DeleteBypassToken deletes the token or returns ErrBypassTokenNotFound.
+	DeleteBypassToken(ctx context.Context, id string) error
+	// UseBypassToken atomically consumes one request of the quota of the token having the hash,
+	// or returns ErrBypassTokenInvalid or ErrBypassTokenQuotaExhausted.
+	UseBypassToken(ctx context.Context, id, hash string, now time.Time) (BypassToken, error)
+	// SetBypassTokenExpiry changes the expiry time of the token or returns ErrBypassTokenNotFound.
+	SetBypassTokenExpiry(ctx context.Context, id string, expiresAt time.Time) (BypassToken, error)
+	// DeleteExpiredBypassTokens deletes the tokens expired at the time and returns the number of deleted ones.
+	DeleteExpiredBypassTokens(ctx context.Context, now time.Time) (int, error)
+}
+
+// WithBypassTokens returns a copy of the app exempting the holders of the bypass tokens from the rate limits.
+func (a App) WithBypassTokens(store BypassTokenStore) App {
+	a.bypassTokens = store
+	return a
+}
+
+// CreateBypassToken issues the bypass token of the spec. The token is returned only once, the store keeps its hash.
+func (a App) CreateBypassToken(ctx context.Context, spec BypassTokenSpec) (BypassToken, string, error) {
+	if a.bypassTokens == nil {
+		return BypassToken{}, "", errors.New("bypass tokens are not supported")
+	}
+	if !bypassTokenHolderRegexp.MatchString(spec.Holder) {
+		return BypassToken{}, "", errors.Wrap(ErrInvalidBypassTokenSpec,
+			"holder must be 1-64 letters, digits, dots, dashes or underscores")
+	}
+	if spec.Quota == 0 {
+		return BypassToken{}, "", errors.Wrap(ErrInvalidBypassTokenSpec, "quota must be positive")
+	}
+	if spec.Period < 0 {
+		return BypassToken{}, "", errors.Wrap(ErrInvalidBypassTokenSpec, "period must not be negative")
+	}
+	now := a.clock.Now().UTC()
+	if !spec.ExpiresAt.IsZero() && !spec.ExpiresAt.After(now) {
+		return BypassToken{}, "", errors.Wrap(ErrInvalidBypassTokenSpec, "expiry time must be in the future")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return BypassToken{}, "", errors.WithStack(err)
+	}
+	raw := bypassTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	id, hash := hashBypassToken(raw)
+	token := BypassToken{
+		ID:          id,
+		Holder:      spec.Holder,
+		Hash:        hash,
+		Quota:       spec.Quota,
+		Period:      spec.Period,
+		WindowStart: now,
+		CreatedAt:   now,
+		ExpiresAt:   spec.ExpiresAt.UTC(),
+	}
+	if err := a.bypassTokens.PutBypassToken(ctx, token); err != nil {
+		return BypassToken{}, "", err
+	}
+	logger.Get(ctx).Info("Bypass token issued", zap.String("id", id), zap.String("holder", spec.Holder))
+	return token, raw, nil
+}
+
+// BypassTokens returns all the bypass tokens ordered by ID.
+func (a App) BypassTokens(ctx context.Context) ([]BypassToken, error) {
+	if a.bypassTokens == nil {
+		return []BypassToken{}, nil
+	}
+	return a.bypassTokens.BypassTokens(ctx)
+}
+
+// DeleteBypassToken revokes the bypass token.
+func (a App) DeleteBypassToken(ctx context.Context, id string) error {
+	if a.bypassTokens == nil {
+		return errors.Wrapf(ErrBypassTokenNotFound, "token: %s", id)
+	}
+	if err := a.bypassTokens.DeleteBypassToken(ctx, id); err != nil {
+		return err
+	}
+	logger.Get(ctx).Info("Bypass token revoked", zap.String("id", id))
+	return nil
+}
+
+// UseBypassToken authenticates the holder of the token and consumes one request of its quota.
+func (a App) UseBypassToken(ctx context.Context, raw string) (BypassToken, error) {
+	if a.bypassTokens == nil || !strings.HasPrefix(raw, bypassTokenPrefix) {
+		return BypassToken{}, errors.Wrap(ErrBypassTokenInvalid, "unknown token")
+	}
+	id, hash := hashBypassToken(raw)
+	return a.bypassTokens.UseBypassToken(ctx, id, hash, a.clock.Now().UTC())
+}
+
+// VerifyBypassTokenHash tells if the hash stored with the token is the expected one, in constant time.
+func VerifyBypassTokenHash(token BypassToken, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(token.Hash), []byte(hash)) == 1
+}
+
+// hashBypassToken returns the ID of the token and its hash.
+func hashBypassToken(raw string) (string, string) {
+	sum := sha256.Sum256([]byte(raw))
+	hash := hex.EncodeToString(sum[:])
+	return hash[:bypassTokenIDLength], hash
+}
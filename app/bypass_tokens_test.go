@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockBypassTokens struct {
+	tokens map[string]BypassToken
+}
+
+func (m *mockBypassTokens) PutBypassToken(_ context.Context, token BypassToken) error {
+	m.tokens[token.ID] = token
+	return nil
+}
+
+func (m *mockBypassTokens) BypassTokens(context.Context) ([]BypassToken, error) {
+	tokens := []BypassToken{}
+	for _, t := range m.tokens {
+		tokens = append(tokens, t)
+	}
+	return tokens, nil
+}
+
+func (m *mockBypassTokens) DeleteBypassToken(_ context.Context, id string) error {
+	delete(m.tokens, id)
+	return nil
+}
+
+func (m *mockBypassTokens) UseBypassToken(_ context.Context, id, hash string, now time.Time) (BypassToken, error) {
+	token, ok := m.tokens[id]
+	if !ok || !VerifyBypassTokenHash(token, hash) {
+		return BypassToken{}, ErrBypassTokenInvalid
+	}
+	token, err := token.Consume(now)
+	if err != nil {
+		return token, err
+	}
+	m.tokens[id] = token
+	return token, nil
+}
+
+func (m *mockBypassTokens) SetBypassTokenExpiry(_ context.Context, id string, expiresAt time.Time) (BypassToken, error) {
+	token := m.tokens[id]
+	token.ExpiresAt = expiresAt
+	m.tokens[id] = token
+	return token, nil
+}
+
+func (m *mockBypassTokens) DeleteExpiredBypassTokens(context.Context, time.Time) (int, error) {
+	return 0, nil
+}
+
+func TestBypassTokenConsume(t *testing.T) {
+	requireT := require.New(t)
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	token := BypassToken{ID: "id", Holder: "ci", Quota: 2, Period: time.Hour, WindowStart: now}
+	var err error
+	token, err = token.Consume(now)
+	requireT.NoError(err)
+	token, err = token.Consume(now.Add(time.Minute))
+	requireT.NoError(err)
+	_, err = token.Consume(now.Add(2 * time.Minute))
+	requireT.ErrorIs(err, ErrBypassTokenQuotaExhausted)
+	var throttled ThrottledError
+	requireT.ErrorAs(err, &throttled)
+	requireT.Equal(now.Add(time.Hour), throttled.NextAvailableAt)
+
+	// quota is renewed once the period passes
+	token, err = token.Consume(now.Add(time.Hour))
+	requireT.NoError(err)
+	requireT.EqualValues(1, token.Used)
+	requireT.Equal(now.Add(time.Hour), token.WindowStart)
+
+	// quota of the token without the period is never renewed
+	token = BypassToken{ID: "id", Quota: 1, WindowStart: now}
+	token, err = token.Consume(now)
+	requireT.NoError(err)
+	_, err = token.Consume(now.Add(24 * time.Hour))
+	requireT.ErrorIs(err, ErrBypassTokenQuotaExhausted)
+	requireT.False(errors.As(err, &throttled))
+
+	token = BypassToken{ID: "id", Quota: 1, WindowStart: now, ExpiresAt: now.Add(time.Hour)}
+	_, err = token.Consume(now.Add(time.Hour))
+	requireT.ErrorIs(err, ErrBypassTokenInvalid)
+}
+
+func TestBypassTokenExemptsFromCooldown(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	a := New(&mockBatcher{txHash: "tx1"}, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{},
+		network, chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clk).
+		WithBypassTokens(&mockBypassTokens{tokens: map[string]BypassToken{}}).
+		WithAddressCooldown(mockCooldowns{}, time.Hour)
+
+	_, _, err = a.CreateBypassToken(ctx, BypassTokenSpec{Holder: "ci"})
+	requireT.ErrorIs(err, ErrInvalidBypassTokenSpec)
+	token, raw, err := a.CreateBypassToken(ctx, BypassTokenSpec{Holder: "ci", Quota: 2})
+	requireT.NoError(err)
+	requireT.True(strings.HasPrefix(raw, bypassTokenPrefix))
+	requireT.NotContains(token.Hash, raw)
+
+	_, err = a.UseBypassToken(ctx, bypassTokenPrefix+"unknown")
+	requireT.ErrorIs(err, ErrBypassTokenInvalid)
+
+	for i := 0; i < 2; i++ {
+		used, err := a.UseBypassToken(ctx, raw)
+		requireT.NoError(err)
+		_, err = a.GiveFunds(ctx, Requester{BypassTokenID: used.ID}, address)
+		requireT.NoError(err)
+	}
+	_, err = a.UseBypassToken(ctx, raw)
+	requireT.ErrorIs(err, ErrBypassTokenQuotaExhausted)
+
+	// requests without the token are still subject to the cooldown
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.NoError(err)
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.ErrorIs(err, ErrAddressCooldown)
+}
@@ -0,0 +1,28 @@
+package app
+
+import (
+	"github.com/pkg/errors"
+)
+
+// CallbackValidator verifies that the callback URL supplied by the client may be notified about the result
+// of the funding, e.g. that its host is allowed and it doesn't point to the internal network.
+type CallbackValidator interface {
+	ValidateCallbackURL(rawURL string) error
+}
+
+// WithCallbacks returns a copy of the app accepting callback URLs verified by the validator. Requests with callback
+// URL are rejected if it is not set.
+func (a App) WithCallbacks(validator CallbackValidator) App {
+	a.callbacks = validator
+	return a
+}
+
+func (a App) validateCallbackURL(rawURL string) error {
+	if rawURL == "" {
+		return nil
+	}
+	if a.callbacks == nil {
+		return errors.Wrap(ErrInvalidCallbackURL, "callbacks are disabled")
+	}
+	return a.callbacks.ValidateCallbackURL(rawURL)
+}
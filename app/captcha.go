@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// ProofCaptcha is the name of the captcha verifier and the key of the captcha token in Requester.Proofs.
+const ProofCaptcha = "captcha"
+
+// CaptchaVerifier verifies the captcha token solved by the client.
+type CaptchaVerifier interface {
+	// VerifyCaptcha returns ErrCaptchaFailed if the token is rejected and ErrCaptchaUnavailable if it can't be
+	// verified at the moment.
+	VerifyCaptcha(ctx context.Context, token, remoteIP string) error
+}
+
+// WithCaptcha returns a copy of the app funding only the clients presenting the captcha token accepted
+// by the verifier.
+func (a App) WithCaptcha(verifier CaptchaVerifier) App {
+	return a.WithVerifiers(captchaVerification{verifier: verifier})
+}
+
+// WithFlaggedCaptcha returns a copy of the app requiring the captcha token accepted by the verifier only from
+// the clients whose IPs are flagged by the IP reputation, e.g. Tor exits.
+func (a App) WithFlaggedCaptcha(verifier CaptchaVerifier) App {
+	return a.WithVerifiers(flaggedCaptchaVerification{captchaVerification{verifier: verifier}})
+}
+
+// captchaVerification adapts the captcha verifier to the verifier chain.
+type captchaVerification struct {
+	verifier CaptchaVerifier
+}
+
+func (captchaVerification) Name() string {
+	return ProofCaptcha
+}
+
+func (c captchaVerification) Verify(ctx context.Context, token string, requester Requester) error {
+	if token == "" {
+		return errors.Wrap(ErrCaptchaFailed, "captcha token is required")
+	}
+	return c.verifier.VerifyCaptcha(ctx, token, requester.IP)
+}
+
+// flaggedCaptchaVerification verifies the captcha of the flagged requesters only.
+type flaggedCaptchaVerification struct {
+	captchaVerification
+}
+
+func (c flaggedCaptchaVerification) Verify(ctx context.Context, token string, requester Requester) error {
+	if requester.IPReputation == "" {
+		return nil
+	}
+	if token == "" {
+		return errors.Wrapf(ErrCaptchaFailed, "captcha token is required from %s network", requester.IPReputation)
+	}
+	return c.verifier.VerifyCaptcha(ctx, token, requester.IP)
+}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockCaptcha struct {
+	verified []string
+}
+
+func (m *mockCaptcha) VerifyCaptcha(_ context.Context, token, _ string) error {
+	if token != "solved" {
+		return errors.WithStack(ErrCaptchaFailed)
+	}
+	m.verified = append(m.verified, token)
+	return nil
+}
+
+func TestGiveFundsCaptcha(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	verifier := &mockCaptcha{}
+	a := New(&mockBatcher{txHash: "tx1"}, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{},
+		network, chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clk).
+		WithCaptcha(verifier)
+
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.ErrorIs(err, ErrCaptchaFailed)
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofCaptcha: "bot"}}, address)
+	requireT.ErrorIs(err, ErrCaptchaFailed)
+	// invalid requests don't spend the token
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofCaptcha: "solved"}}, "invalid")
+	requireT.Error(err)
+	requireT.Empty(verifier.verified)
+
+	txHash, err := a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofCaptcha: "solved"}}, address)
+	requireT.NoError(err)
+	requireT.Equal("tx1", txHash)
+	requireT.Len(verifier.verified, 1)
+
+	// automation is exempt
+	_, err = a.GiveFunds(ctx, Requester{APIKeyHolder: "ci"}, address)
+	requireT.NoError(err)
+	_, err = a.GiveFunds(ctx, Requester{Admin: true}, address)
+	requireT.NoError(err)
+}
+
+func TestGiveFundsFlaggedCaptcha(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+
+	verifier := &mockCaptcha{}
+	a := New(&mockBatcher{txHash: "tx1"}, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{},
+		network, chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))).
+		WithFlaggedCaptcha(verifier)
+
+	// clients of clean IPs are not asked for captcha
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.NoError(err)
+
+	_, err = a.GiveFunds(ctx, Requester{IPReputation: "tor"}, address)
+	requireT.ErrorIs(err, ErrCaptchaFailed)
+	_, err = a.GiveFunds(ctx, Requester{IPReputation: "tor", Proofs: Proofs{ProofCaptcha: "bot"}}, address)
+	requireT.ErrorIs(err, ErrCaptchaFailed)
+	_, err = a.GiveFunds(ctx, Requester{IPReputation: "tor", Proofs: Proofs{ProofCaptcha: "solved"}}, address)
+	requireT.NoError(err)
+	requireT.Len(verifier.verified, 1)
+}
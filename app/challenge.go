@@ -0,0 +1,191 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+const (
+	// ChallengeTTL is how long the requester has to broadcast the transaction answering the challenge.
+	ChallengeTTL = 10 * time.Minute
+	// challengeMemoPrefix makes the memo recognizable in block explorers.
+	challengeMemoPrefix = "faucet-challenge:"
+	// maxPendingChallenges protects memory if challenges are created but never completed.
+	maxPendingChallenges = 10000
+)
+
+// Challenge asks the requester to prove the key control of the address by broadcasting any transaction signed by
+// the address with the memo. The dust needed to pay the fee is sent to the address when the challenge is created.
+type Challenge struct {
+	ID         string
+	Address    string
+	Memo       string
+	Dust       chain.Coin
+	DustTxHash string
+	ExpiresAt  time.Time
+
+	sdkAddr chain.AccAddress
+	// tosVersion is the version of the terms of service accepted when the challenge was created, the token
+	// might be expired by the time it is completed.
+	tosVersion string
+}
+
+// TxMemoSource returns the memo and the signers of the transaction.
+type TxMemoSource interface {
+	// TxMemo returns the memo and the signers of the transaction, signers are empty if the transaction is not found.
+	TxMemo(ctx context.Context, txHash string) (string, []chain.AccAddress, error)
+}
+
+// challenges keeps the pending challenges in memory, they are short-lived, so they are not persisted.
+type challenges struct {
+	source TxMemoSource
+	dust   chain.Coin
+
+	mu      sync.Mutex
+	pending map[string]Challenge
+}
+
+// WithOnChainChallenge returns a copy of the app funding the addresses which answered the challenge by
+// broadcasting self-signed transaction with the memo. The dust is sent upfront to pay the fee of that transaction.
+func (a App) WithOnChainChallenge(source TxMemoSource, dust chain.Coin) App {
+	a.challenges = &challenges{source: source, dust: dust, pending: map[string]Challenge{}}
+	return a
+}
+
+// OnChainChallengeEnabled tells if the on-chain challenge is enabled.
+func (a App) OnChainChallengeEnabled() bool {
+	return a.challenges != nil
+}
+
+// CreateChallenge sends the dust to the address and returns the challenge to complete before the address is funded.
+func (a App) CreateChallenge(ctx context.Context, requester Requester, address string) (Challenge, error) {
+	requester, err := a.verifyToSAccepted(requester)
+	if err != nil {
+		return Challenge{}, err
+	}
+	sdkAddr, err := a.validateAddress(address)
+	if err != nil {
+		return Challenge{}, err
+	}
+	// the dust is not worth sending if the address can't be funded after the challenge is completed
+	if err := a.checkCooldown(ctx, requester, sdkAddr); err != nil {
+		return Challenge{}, err
+	}
+	memo, err := newChallengeMemo()
+	if err != nil {
+		return Challenge{}, err
+	}
+
+	c := a.challenges
+	now := a.clock.Now().UTC()
+	c.mu.Lock()
+	c.pruneLocked(now)
+	full := len(c.pending) >= maxPendingChallenges
+	c.mu.Unlock()
+	if full {
+		return Challenge{}, errors.Wrapf(ErrQueueFull, "%d challenges are pending", maxPendingChallenges)
+	}
+
+	dustRequester := requester
+	dustRequester.RequestID = requester.RequestID + "-dust"
+	txHash, err := a.send(ctx, dustRequester, sdkAddr, c.dust)
+	if err != nil {
+		return Challenge{}, err
+	}
+
+	challenge := Challenge{
+		ID:         uuid.New().String(),
+		Address:    address,
+		Memo:       memo,
+		Dust:       c.dust,
+		DustTxHash: txHash,
+		ExpiresAt:  now.Add(ChallengeTTL),
+		sdkAddr:    sdkAddr,
+		tosVersion: requester.ToSVersion,
+	}
+	c.mu.Lock()
+	c.pending[challenge.ID] = challenge
+	c.mu.Unlock()
+
+	return challenge, nil
+}
+
+// CompleteChallenge funds the address of the challenge if the transaction is signed by it and carries the memo
+// of the challenge. Each challenge is completed once.
+func (a App) CompleteChallenge(ctx context.Context, requester Requester, id, txHash string) (string, error) {
+	c := a.challenges
+	now := a.clock.Now().UTC()
+	c.mu.Lock()
+	c.pruneLocked(now)
+	challenge, exists := c.pending[id]
+	c.mu.Unlock()
+	if !exists {
+		return "", errors.Wrapf(ErrChallengeNotFound, "id: %s", id)
+	}
+
+	memo, signers, err := c.source.TxMemo(ctx, txHash)
+	if err != nil {
+		return "", errors.Wrapf(ErrUnableToTransferToken, "err:%s", err)
+	}
+	if len(signers) == 0 {
+		return "", errors.Wrapf(ErrChallengeTxNotFound, "tx: %s", txHash)
+	}
+	if memo != challenge.Memo {
+		return "", errors.Wrapf(ErrChallengeFailed, "memo of tx %s doesn't match the challenge", txHash)
+	}
+	if len(signers) != 1 || !signers[0].Equals(challenge.sdkAddr) {
+		return "", errors.Wrapf(ErrChallengeFailed, "tx %s is not signed by %s only", txHash, challenge.Address)
+	}
+
+	// removed before funding, so concurrent completions of the same challenge fund the address once
+	c.mu.Lock()
+	_, exists = c.pending[id]
+	delete(c.pending, id)
+	c.mu.Unlock()
+	if !exists {
+		return "", errors.Wrapf(ErrChallengeNotFound, "id: %s", id)
+	}
+
+	requester.ToSVersion = challenge.tosVersion
+	fundTxHash, err := a.sendWithCooldown(ctx, requester, challenge.sdkAddr, a.grantAmount(requester))
+	if err != nil {
+		// the requester proved the key control, so the challenge may be completed again
+		c.mu.Lock()
+		c.pending[id] = challenge
+		c.mu.Unlock()
+		return "", err
+	}
+	logger.Get(ctx).Info("Challenge completed", zap.String("address", challenge.Address), zap.String("challengeTx", txHash))
+	return fundTxHash, nil
+}
+
+// pruneLocked deletes the expired challenges and returns their number.
+func (c *challenges) pruneLocked(now time.Time) int {
+	var pruned int
+	for id, challenge := range c.pending {
+		if !now.Before(challenge.ExpiresAt) {
+			delete(c.pending, id)
+			pruned++
+		}
+	}
+	return pruned
+}
+
+func newChallengeMemo() (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return challengeMemoPrefix + hex.EncodeToString(random), nil
+}
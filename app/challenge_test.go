@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockLedger struct {
+	txs []LedgerTransaction
+}
+
+func (m *mockLedger) RecordLedgerTransaction(ctx context.Context, tx LedgerTransaction) error {
+	m.txs = append(m.txs, tx)
+	return nil
+}
+
+func (m *mockLedger) LedgerTransactionsSince(ctx context.Context, since time.Time) ([]LedgerTransaction, error) {
+	return m.txs, nil
+}
+
+type mockTxMemoSource struct {
+	memo    string
+	signers []chain.AccAddress
+}
+
+func (m *mockTxMemoSource) TxMemo(ctx context.Context, txHash string) (string, []chain.AccAddress, error) {
+	return m.memo, m.signers, nil
+}
+
+func TestOnChainChallenge(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+	_, sdkAddr, err := chain.DecodeBech32(address)
+	requireT.NoError(err)
+
+	clk := clock.NewOffset()
+	history := &mockHistory{}
+	batcher := &mockBatcher{txHash: "tx1"}
+	source := &mockTxMemoSource{}
+	dust := chain.NewCoin("udevcore", chain.NewInt(10))
+	a := New(batcher, nil, NewTxTracker(nil, 1, nil, clock.System{}), history, &mockLedger{}, network,
+		chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clk).
+		WithOnChainChallenge(source, dust)
+
+	challenge, err := a.CreateChallenge(ctx, Requester{RequestID: "rq1"}, address)
+	requireT.NoError(err)
+	requireT.Regexp(`^faucet-challenge:[0-9a-f]{32}$`, challenge.Memo)
+	requireT.Equal("tx1", challenge.DustTxHash)
+	requireT.Len(history.fundings, 1)
+	requireT.Equal(dust.String(), history.fundings[0].Amount.String())
+	requireT.Equal("rq1-dust", history.fundings[0].RequestID)
+
+	_, err = a.CompleteChallenge(ctx, Requester{}, challenge.ID, "answer")
+	requireT.ErrorIs(err, ErrChallengeTxNotFound)
+
+	source.memo = "other"
+	source.signers = []chain.AccAddress{sdkAddr}
+	_, err = a.CompleteChallenge(ctx, Requester{}, challenge.ID, "answer")
+	requireT.ErrorIs(err, ErrChallengeFailed)
+
+	source.memo = challenge.Memo
+	source.signers = []chain.AccAddress{chain.AccAddress("other-signer-address")}
+	_, err = a.CompleteChallenge(ctx, Requester{}, challenge.ID, "answer")
+	requireT.ErrorIs(err, ErrChallengeFailed)
+
+	source.signers = []chain.AccAddress{sdkAddr}
+	batcher.txHash = "tx2"
+	txHash, err := a.CompleteChallenge(ctx, Requester{}, challenge.ID, "answer")
+	requireT.NoError(err)
+	requireT.Equal("tx2", txHash)
+	requireT.Len(history.fundings, 2)
+	requireT.Equal("1000udevcore", history.fundings[1].Amount.String())
+
+	// each challenge is completed once
+	_, err = a.CompleteChallenge(ctx, Requester{}, challenge.ID, "answer")
+	requireT.ErrorIs(err, ErrChallengeNotFound)
+
+	challenge, err = a.CreateChallenge(ctx, Requester{}, address)
+	requireT.NoError(err)
+	clk.Advance(ChallengeTTL)
+	_, err = a.CompleteChallenge(ctx, Requester{}, challenge.ID, "answer")
+	requireT.ErrorIs(err, ErrChallengeNotFound)
+}
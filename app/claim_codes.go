@@ -0,0 +1,242 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+const (
+	// MaxClaimCodesPerRequest limits the number of claim codes generated at once.
+	MaxClaimCodesPerRequest = 10000
+	// claimCodeBytes of randomness give 80 bits of entropy, so codes can't be guessed.
+	claimCodeBytes = 10
+)
+
+var claimCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// ClaimCode grants the amount to its holders, e.g. attendees of an event, bypassing the IP rate limit.
+type ClaimCode struct {
+	Code string `json:"code"`
+	// Amount is the set of coins sent on each use, in any denoms held by the funding accounts.
+	Amount  chain.Coins `json:"amount"`
+	MaxUses int         `json:"maxUses"`
+	Uses    int         `json:"uses"`
+	// Address is the only address the code may be claimed to, any address if it is empty.
+	Address   string    `json:"address,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	// ExpiresAt is the time the code can't be claimed anymore and is collected, zero means it never expires.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// expired tells if the code can't be claimed at the time anymore.
+func (c ClaimCode) expired(now time.Time) bool {
+	return !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt)
+}
+
+// ClaimCodeSpec describes the claim codes to generate.
+type ClaimCodeSpec struct {
+	Amount  chain.Coins
+	MaxUses int
+	Address string
+	// ExpiresAt is optional expiry time of the codes.
+	ExpiresAt time.Time
+}
+
+// ClaimResult is the result of claiming the code.
+type ClaimResult struct {
+	Address string
+	Amount  chain.Coins
+	// TxHashes are the hashes of the transactions sending the coins, one per denom.
+	TxHashes []string
+}
+
+// ClaimCodeStore persists the claim codes.
+type ClaimCodeStore interface {
+	PutClaimCodes(ctx context.Context, codes []ClaimCode) error
+	// ClaimCode returns the code or ErrClaimCodeNotFound.
+	ClaimCode(ctx context.Context, code string) (ClaimCode, error)
+	ClaimCodes(ctx context.Context) ([]ClaimCode, error)
+	// DeleteClaimCode deletes the code or returns ErrClaimCodeNotFound.
+	DeleteClaimCode(ctx context.Context, code string) error
+	// UseClaimCode atomically consumes one use of the code, or returns ErrClaimCodeNotFound or ErrClaimCodeUsedUp.
+	UseClaimCode(ctx context.Context, code string) (ClaimCode, error)
+	// ReleaseClaimCode gives back the use consumed by the claim which failed.
+	ReleaseClaimCode(ctx context.Context, code string) error
+	// SetClaimCodeExpiry changes the expiry time of the code or returns ErrClaimCodeNotFound.
+	SetClaimCodeExpiry(ctx context.Context, code string, expiresAt time.Time) (ClaimCode, error)
+	// DeleteExpiredClaimCodes deletes the codes expired at the time and returns the number of deleted ones.
+	DeleteExpiredClaimCodes(ctx context.Context, now time.Time) (int, error)
+}
+
+// WithClaimCodes returns a copy of the app granting funds to the holders of the claim codes.
+func (a App) WithClaimCodes(store ClaimCodeStore) App {
+	a.claimCodes = store
+	return a
+}
+
+// CreateClaimCodes generates the count of random claim codes of the spec.
+func (a App) CreateClaimCodes(ctx context.Context, spec ClaimCodeSpec, count int) ([]ClaimCode, error) {
+	if count < 1 || count > MaxClaimCodesPerRequest {
+		return nil, errors.Wrapf(ErrInvalidClaimCode, "count must be between 1 and %d", MaxClaimCodesPerRequest)
+	}
+	if spec.Amount.Empty() || spec.Amount.Validate() != nil {
+		return nil, errors.Wrapf(ErrInvalidClaimCode, "amount %q is invalid", spec.Amount)
+	}
+	if spec.MaxUses < 1 {
+		return nil, errors.Wrap(ErrInvalidClaimCode, "number of uses must be positive")
+	}
+	if spec.Address != "" {
+		if _, err := a.validateAddress(spec.Address); err != nil {
+			return nil, err
+		}
+	}
+
+	now := a.clock.Now().UTC()
+	if !spec.ExpiresAt.IsZero() && !spec.ExpiresAt.After(now) {
+		return nil, errors.Wrap(ErrInvalidClaimCode, "expiry time must be in the future")
+	}
+	codes := make([]ClaimCode, 0, count)
+	for i := 0; i < count; i++ {
+		code, err := newClaimCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, ClaimCode{
+			Code:      code,
+			Amount:    spec.Amount,
+			MaxUses:   spec.MaxUses,
+			Address:   spec.Address,
+			CreatedAt: now,
+			ExpiresAt: spec.ExpiresAt.UTC(),
+		})
+	}
+	if err := a.claimCodes.PutClaimCodes(ctx, codes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// ClaimCodes returns all the claim codes ordered by code.
+func (a App) ClaimCodes(ctx context.Context) ([]ClaimCode, error) {
+	return a.claimCodes.ClaimCodes(ctx)
+}
+
+// DeleteClaimCode revokes the claim code.
+func (a App) DeleteClaimCode(ctx context.Context, code string) error {
+	return a.claimCodes.DeleteClaimCode(ctx, normalizeClaimCode(code))
+}
+
+// Claim sends the amount of the claim code to the address. Empty address means the address the code is bound to.
+// Each denom is sent by a separate transfer, the use is given back only if none of them succeeds.
+func (a App) Claim(ctx context.Context, requester Requester, code, address string) (ClaimResult, error) {
+	requester, err := a.verifyToSAccepted(requester)
+	if err != nil {
+		return ClaimResult{}, err
+	}
+	code = normalizeClaimCode(code)
+	claimCode, err := a.claimCodes.ClaimCode(ctx, code)
+	if err != nil {
+		return ClaimResult{}, err
+	}
+	if claimCode.expired(a.clock.Now()) {
+		return ClaimResult{}, errors.Wrapf(ErrClaimCodeExpired, "code: %s", code)
+	}
+	switch {
+	case address == "" && claimCode.Address == "":
+		return ClaimResult{}, errors.Wrap(ErrInvalidAddressFormat, "address is required")
+	case address == "":
+		address = claimCode.Address
+	case claimCode.Address != "" && address != claimCode.Address:
+		return ClaimResult{}, errors.Wrapf(ErrClaimCodeAddressMismatch, "address %s", address)
+	}
+	sdkAddr, err := a.validateAddress(address)
+	if err != nil {
+		return ClaimResult{}, err
+	}
+
+	claimCode, err = a.claimCodes.UseClaimCode(ctx, code)
+	if err != nil {
+		return ClaimResult{}, err
+	}
+
+	txHashes := make([]string, len(claimCode.Amount))
+	errs := make([]error, len(claimCode.Amount))
+	var wg sync.WaitGroup
+	wg.Add(len(claimCode.Amount))
+	for i, coin := range claimCode.Amount {
+		i, coin := i, coin
+		coinRequester := requester
+		if len(claimCode.Amount) > 1 {
+			// each denom gets its own request ID, so history records of the claim don't collide
+			coinRequester.RequestID = requester.RequestID + "-" + coin.Denom
+		}
+		go func() {
+			defer wg.Done()
+			txHashes[i], errs[i] = a.send(ctx, coinRequester, sdkAddr, coin)
+		}()
+	}
+	wg.Wait()
+
+	var sent []string
+	var firstErr error
+	for i, err := range errs {
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		sent = append(sent, txHashes[i])
+	}
+	if firstErr != nil {
+		if len(sent) == 0 {
+			if err := a.claimCodes.ReleaseClaimCode(ctx, code); err != nil {
+				logger.Get(ctx).Error("Releasing claim code failed", zap.Error(err))
+			}
+		} else {
+			logger.Get(ctx).Error("Claim sent partially", zap.Strings("txHashes", sent), zap.Error(firstErr))
+		}
+		return ClaimResult{}, firstErr
+	}
+
+	return ClaimResult{Address: address, Amount: claimCode.Amount, TxHashes: sent}, nil
+}
+
+// newClaimCode returns random code formatted in groups of 4 characters, e.g. ABCD-EFGH-IJKL-MNOP.
+func newClaimCode() (string, error) {
+	random := make([]byte, claimCodeBytes)
+	if _, err := rand.Read(random); err != nil {
+		return "", errors.WithStack(err)
+	}
+	encoded := claimCodeEncoding.EncodeToString(random)
+	groups := make([]string, 0, len(encoded)/4)
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:i+4])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// normalizeClaimCode makes the code typed by hand match the stored one.
+func normalizeClaimCode(code string) string {
+	code = strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(code))
+	groups := make([]string, 0, len(code)/4+1)
+	for i := 0; i < len(code); i += 4 {
+		end := i + 4
+		if end > len(code) {
+			end = len(code)
+		}
+		groups = append(groups, code[i:end])
+	}
+	return strings.Join(groups, "-")
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+type mockClaimCodeStore struct {
+	ClaimCodeStore
+	codes map[string]ClaimCode
+}
+
+func (m *mockClaimCodeStore) PutClaimCodes(ctx context.Context, codes []ClaimCode) error {
+	for _, c := range codes {
+		m.codes[c.Code] = c
+	}
+	return nil
+}
+
+func (m *mockClaimCodeStore) ClaimCode(ctx context.Context, code string) (ClaimCode, error) {
+	c, ok := m.codes[code]
+	if !ok {
+		return ClaimCode{}, ErrClaimCodeNotFound
+	}
+	return c, nil
+}
+
+func (m *mockClaimCodeStore) UseClaimCode(ctx context.Context, code string) (ClaimCode, error) {
+	c, err := m.ClaimCode(ctx, code)
+	if err != nil {
+		return ClaimCode{}, err
+	}
+	c.Uses++
+	m.codes[code] = c
+	return c, nil
+}
+
+func TestNewClaimCode(t *testing.T) {
+	requireT := require.New(t)
+
+	code, err := newClaimCode()
+	requireT.NoError(err)
+	requireT.Regexp(`^[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}$`, code)
+	requireT.Equal(code, normalizeClaimCode(code))
+
+	other, err := newClaimCode()
+	requireT.NoError(err)
+	requireT.NotEqual(code, other)
+
+	requireT.Equal("ABCD-EFGH-IJ", normalizeClaimCode(" abcd efghij"))
+}
+
+func TestCreateClaimCodes(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	store := &mockClaimCodeStore{codes: map[string]ClaimCode{}}
+	a := New(nil, nil, nil, nil, nil, chain.Network{}, chain.Coin{}).WithClaimCodes(store)
+	amount := chain.NewCoins(chain.NewCoin("ucore", chain.NewInt(10)), chain.NewCoin("uatom", chain.NewInt(5)))
+
+	codes, err := a.CreateClaimCodes(ctx, ClaimCodeSpec{Amount: amount, MaxUses: 3}, 5)
+	requireT.NoError(err)
+	requireT.Len(codes, 5)
+	requireT.Len(store.codes, 5)
+	requireT.Equal(3, codes[0].MaxUses)
+	requireT.Equal(amount.String(), codes[0].Amount.String())
+
+	_, err = a.CreateClaimCodes(ctx, ClaimCodeSpec{Amount: amount, MaxUses: 0}, 1)
+	requireT.ErrorIs(err, ErrInvalidClaimCode)
+	_, err = a.CreateClaimCodes(ctx, ClaimCodeSpec{Amount: chain.NewCoins(), MaxUses: 1}, 1)
+	requireT.ErrorIs(err, ErrInvalidClaimCode)
+	_, err = a.CreateClaimCodes(ctx, ClaimCodeSpec{Amount: amount, MaxUses: 1}, MaxClaimCodesPerRequest+1)
+	requireT.ErrorIs(err, ErrInvalidClaimCode)
+}
+
+func TestClaimAddressMismatch(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	store := &mockClaimCodeStore{codes: map[string]ClaimCode{
+		"AAAA-BBBB": {
+			Code:    "AAAA-BBBB",
+			Amount:  chain.NewCoins(chain.NewCoin("ucore", chain.NewInt(10))),
+			MaxUses: 1,
+			Address: "devcore1bound",
+		},
+	}}
+	a := New(nil, nil, nil, nil, nil, chain.Network{}, chain.Coin{}).WithClaimCodes(store)
+
+	_, err := a.Claim(ctx, Requester{}, "aaaabbbb", "devcore1other")
+	requireT.ErrorIs(err, ErrClaimCodeAddressMismatch)
+	// rejected claim doesn't consume the use
+	requireT.Equal(0, store.codes["AAAA-BBBB"].Uses)
+
+	_, err = a.Claim(ctx, Requester{}, "CCCC-DDDD", "devcore1other")
+	requireT.ErrorIs(err, ErrClaimCodeNotFound)
+}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+const (
+	// periodicMinRequests is the minimum number of requests in cluster required to analyze their timing.
+	periodicMinRequests = 4
+	// periodicMaxVariation is the maximum coefficient of variation of intervals between requests
+	// for which the requests are considered to be sent periodically (e.g. by a cron job).
+	periodicMaxVariation = 0.1
+)
+
+// AddressCluster groups funded addresses linked by shared IPs or fingerprints.
+type AddressCluster struct {
+	Addresses    []string
+	IPs          []string
+	Fingerprints []string
+	Requests     int
+	TotalAmount  chain.Coins
+	FirstSeen    time.Time
+	LastSeen     time.Time
+	// Periodic tells that the requests arrive at regular intervals, which is typical for scripts.
+	Periodic bool
+}
+
+// ClusterReport clusters addresses funded since the given time, returning clusters containing at least
+// minSize addresses, the biggest consumers first.
+func (a App) ClusterReport(ctx context.Context, since time.Time, minSize int) ([]AddressCluster, error) {
+	records, err := a.queries().FundingsSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	return clusterFundings(records, minSize), nil
+}
+
+func clusterFundings(records []FundingRecord, minSize int) []AddressCluster {
+	uf := newUnionFind()
+	ipOwners := map[string]string{}
+	fingerprintOwners := map[string]string{}
+	for _, r := range records {
+		uf.add(r.Address)
+		if r.IP != "" {
+			if owner, exists := ipOwners[r.IP]; exists {
+				uf.union(owner, r.Address)
+			} else {
+				ipOwners[r.IP] = r.Address
+			}
+		}
+		if r.Fingerprint != "" {
+			if owner, exists := fingerprintOwners[r.Fingerprint]; exists {
+				uf.union(owner, r.Address)
+			} else {
+				fingerprintOwners[r.Fingerprint] = r.Address
+			}
+		}
+	}
+
+	grouped := map[string][]FundingRecord{}
+	for _, r := range records {
+		root := uf.find(r.Address)
+		grouped[root] = append(grouped[root], r)
+	}
+
+	var clusters []AddressCluster
+	for _, group := range grouped {
+		cluster := newAddressCluster(group)
+		if len(cluster.Addresses) >= minSize {
+			clusters = append(clusters, cluster)
+		}
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Requests != clusters[j].Requests {
+			return clusters[i].Requests > clusters[j].Requests
+		}
+		return clusters[i].Addresses[0] < clusters[j].Addresses[0]
+	})
+	return clusters
+}
+
+func newAddressCluster(records []FundingRecord) AddressCluster {
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+
+	cluster := AddressCluster{
+		Requests:    len(records),
+		TotalAmount: chain.NewCoins(),
+		FirstSeen:   records[0].Time,
+		LastSeen:    records[len(records)-1].Time,
+		Periodic:    isPeriodic(records),
+	}
+	for _, r := range records {
+		cluster.Addresses = append(cluster.Addresses, r.Address)
+		if r.IP != "" {
+			cluster.IPs = append(cluster.IPs, r.IP)
+		}
+		if r.Fingerprint != "" {
+			cluster.Fingerprints = append(cluster.Fingerprints, r.Fingerprint)
+		}
+		cluster.TotalAmount = cluster.TotalAmount.Add(r.Amount)
+	}
+	cluster.Addresses = sortedUniq(cluster.Addresses)
+	cluster.IPs = sortedUniq(cluster.IPs)
+	cluster.Fingerprints = sortedUniq(cluster.Fingerprints)
+	return cluster
+}
+
+// isPeriodic expects records to be sorted by time.
+func isPeriodic(records []FundingRecord) bool {
+	if len(records) < periodicMinRequests {
+		return false
+	}
+
+	intervals := make([]float64, 0, len(records)-1)
+	var sum float64
+	for i := 1; i < len(records); i++ {
+		interval := float64(records[i].Time.Sub(records[i-1].Time))
+		intervals = append(intervals, interval)
+		sum += interval
+	}
+	mean := sum / float64(len(intervals))
+	if mean == 0 {
+		return false
+	}
+
+	var variance float64
+	for _, interval := range intervals {
+		variance += (interval - mean) * (interval - mean)
+	}
+	stdDev := math.Sqrt(variance / float64(len(intervals)))
+	return stdDev/mean <= periodicMaxVariation
+}
+
+func sortedUniq(values []string) []string {
+	values = lo.Uniq(values)
+	sort.Strings(values)
+	return values
+}
+
+type unionFind struct {
+	parents map[string]string
+}
+
+func newUnionFind() unionFind {
+	return unionFind{parents: map[string]string{}}
+}
+
+func (uf unionFind) add(item string) {
+	if _, exists := uf.parents[item]; !exists {
+		uf.parents[item] = item
+	}
+}
+
+func (uf unionFind) find(item string) string {
+	for uf.parents[item] != item {
+		uf.parents[item] = uf.parents[uf.parents[item]]
+		item = uf.parents[item]
+	}
+	return item
+}
+
+func (uf unionFind) union(a, b string) {
+	rootA, rootB := uf.find(a), uf.find(b)
+	if rootA != rootB {
+		uf.parents[rootB] = rootA
+	}
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+func TestClusterFundings(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	amount := chain.NewCoin("ucore", chain.NewInt(10))
+	records := []FundingRecord{
+		// farm: addresses linked through shared IP and shared fingerprint, sent every minute
+		{Address: "addr1", IP: "1.1.1.1", Fingerprint: "fp1", Amount: amount, Time: start},
+		{Address: "addr2", IP: "1.1.1.1", Fingerprint: "fp2", Amount: amount, Time: start.Add(time.Minute)},
+		{Address: "addr3", IP: "2.2.2.2", Fingerprint: "fp2", Amount: amount, Time: start.Add(2 * time.Minute)},
+		{Address: "addr3", IP: "3.3.3.3", Fingerprint: "fp2", Amount: amount, Time: start.Add(3 * time.Minute)},
+		// independent users
+		{Address: "addr4", IP: "4.4.4.4", Fingerprint: "fp4", Amount: amount, Time: start.Add(5 * time.Minute)},
+		{Address: "addr5", IP: "5.5.5.5", Fingerprint: "fp5", Amount: amount, Time: start.Add(time.Hour)},
+		{Address: "addr6", IP: "5.5.5.5", Fingerprint: "fp6", Amount: amount, Time: start.Add(3 * time.Hour)},
+	}
+
+	clusters := clusterFundings(records, 2)
+	requireT.Len(clusters, 2)
+
+	farm := clusters[0]
+	assertT.Equal([]string{"addr1", "addr2", "addr3"}, farm.Addresses)
+	assertT.Equal([]string{"1.1.1.1", "2.2.2.2", "3.3.3.3"}, farm.IPs)
+	assertT.Equal([]string{"fp1", "fp2"}, farm.Fingerprints)
+	assertT.Equal(4, farm.Requests)
+	assertT.Equal("40ucore", farm.TotalAmount.String())
+	assertT.Equal(start, farm.FirstSeen)
+	assertT.Equal(start.Add(3*time.Minute), farm.LastSeen)
+	assertT.True(farm.Periodic)
+
+	assertT.Equal([]string{"addr5", "addr6"}, clusters[1].Addresses)
+	assertT.False(clusters[1].Periodic)
+
+	assertT.Len(clusterFundings(records, 1), 3)
+}
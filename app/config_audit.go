@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+)
+
+// Sources of the configuration changes.
+const (
+	// ConfigSourceAdminAPI is the change applied by the operator through the admin API.
+	ConfigSourceAdminAPI = "admin_api"
+	// ConfigSourceReload is the change picked up by reloading the configuration, e.g. the IP lists.
+	ConfigSourceReload = "reload"
+)
+
+// ConfigDiff is the change of the single setting.
+type ConfigDiff struct {
+	Setting string `json:"setting"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+}
+
+// ConfigChange records what was changed in the runtime configuration, who changed it and when.
+type ConfigChange struct {
+	ID   string    `json:"id"`
+	Time time.Time `json:"time"`
+	// Actor identifies who changed the configuration, e.g. the operator calling the admin API or the reload job.
+	Actor  string       `json:"actor"`
+	Source string       `json:"source"`
+	Diff   []ConfigDiff `json:"diff"`
+}
+
+// ConfigAuditStore persists the configuration changes.
+type ConfigAuditStore interface {
+	RecordConfigChange(ctx context.Context, change ConfigChange) error
+	// ConfigChangesSince returns the changes recorded since the time ordered by time.
+	ConfigChangesSince(ctx context.Context, since time.Time) ([]ConfigChange, error)
+}
+
+// WithConfigAudit returns a copy of the app recording the changes of the runtime configuration in the store.
+// The changes are published on the event bus whether they are recorded or not.
+func (a App) WithConfigAudit(store ConfigAuditStore) App {
+	a.configAudit = store
+	return a
+}
+
+// ConfigChanges returns the configuration changes recorded since the time, the oldest first.
+func (a App) ConfigChanges(ctx context.Context, since time.Time) ([]ConfigChange, error) {
+	if a.configAudit == nil {
+		return []ConfigChange{}, nil
+	}
+	changes, err := a.configAudit.ConfigChangesSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	if changes == nil {
+		changes = []ConfigChange{}
+	}
+	return changes, nil
+}
+
+// RecordConfigChange records the change made by the actor and publishes it on the event bus. Nothing is recorded
+// if the diff is empty. The change is applied already, so failure to record it is only logged.
+func (a App) RecordConfigChange(ctx context.Context, actor, source string, diff []ConfigDiff) {
+	if len(diff) == 0 {
+		return
+	}
+	change := ConfigChange{
+		ID:     uuid.New().String(),
+		Time:   a.clock.Now().UTC(),
+		Actor:  actor,
+		Source: source,
+		Diff:   diff,
+	}
+	log := logger.Get(ctx)
+	log.Info("Configuration changed", zap.String("actor", actor), zap.String("source", source),
+		zap.Any("diff", diff))
+	if a.configAudit != nil {
+		if err := a.configAudit.RecordConfigChange(ctx, change); err != nil {
+			log.Error("Recording configuration change failed", zap.Error(err))
+		}
+	}
+	a.publish(ctx, Event{Kind: EventConfigChanged, Actor: actor, Changes: diff})
+}
+
+// diffControls returns the settings changed between the states of the runtime controls.
+func diffControls(before, after Controls) []ConfigDiff {
+	var diff []ConfigDiff
+	if before.Paused != after.Paused {
+		diff = append(diff, ConfigDiff{
+			Setting: "paused",
+			Old:     strconv.FormatBool(before.Paused),
+			New:     strconv.FormatBool(after.Paused),
+		})
+	}
+	if before.PauseReason != after.PauseReason {
+		diff = append(diff, ConfigDiff{Setting: "pauseReason", Old: before.PauseReason, New: after.PauseReason})
+	}
+	if before.TransferAmount.String() != after.TransferAmount.String() {
+		diff = append(diff, ConfigDiff{
+			Setting: "transferAmount",
+			Old:     before.TransferAmount.String(),
+			New:     after.TransferAmount.String(),
+		})
+	}
+	return diff
+}
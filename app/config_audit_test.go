@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockConfigAudit struct {
+	changes []ConfigChange
+}
+
+func (m *mockConfigAudit) RecordConfigChange(ctx context.Context, change ConfigChange) error {
+	m.changes = append(m.changes, change)
+	return nil
+}
+
+func (m *mockConfigAudit) ConfigChangesSince(ctx context.Context, since time.Time) ([]ConfigChange, error) {
+	var changes []ConfigChange
+	for _, change := range m.changes {
+		if !change.Time.Before(since) {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func TestConfigAudit(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	audit := &mockConfigAudit{}
+	bus := NewEventBus()
+	// the bus is not run, so events stay queued for the subscriber
+	bus.Subscribe("test", func(ctx context.Context, event Event) {})
+	a := New(&mockBatcher{}, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{},
+		chain.Network{}, chain.NewCoin("ucore", chain.NewInt(10))).
+		WithClock(clock.NewManual(now)).
+		WithEventBus(bus).
+		WithConfigAudit(audit)
+
+	// nothing is recorded if the configuration is not changed
+	a.Resume(ctx, "alice")
+	_, err := a.SetTransferAmount(ctx, "alice", chain.NewInt(10))
+	requireT.NoError(err)
+	requireT.Empty(audit.changes)
+
+	a.Pause(ctx, "alice", "incident")
+	_, err = a.SetTransferAmount(ctx, "bob", chain.NewInt(20))
+	requireT.NoError(err)
+
+	changes, err := a.ConfigChanges(ctx, now)
+	requireT.NoError(err)
+	requireT.Len(changes, 2)
+	requireT.Equal("alice", changes[0].Actor)
+	requireT.Equal(ConfigSourceAdminAPI, changes[0].Source)
+	requireT.Equal(now, changes[0].Time)
+	requireT.Equal([]ConfigDiff{
+		{Setting: "paused", Old: "false", New: "true"},
+		{Setting: "pauseReason", Old: "", New: "incident"},
+	}, changes[0].Diff)
+	requireT.Equal("bob", changes[1].Actor)
+	requireT.Equal([]ConfigDiff{{Setting: "transferAmount", Old: "10ucore", New: "20ucore"}}, changes[1].Diff)
+
+	events := bus.subscriptions[0].events
+	requireT.Len(events, 2)
+	event := <-events
+	requireT.Equal(EventConfigChanged, event.Kind)
+	requireT.Equal("alice", event.Actor)
+	requireT.Equal(changes[0].Diff, event.Changes)
+
+	// changes are still published without the store
+	a = a.WithConfigAudit(nil)
+	a.RecordConfigChange(ctx, "job:ipLists", ConfigSourceReload, []ConfigDiff{{Setting: "ip-denylist"}})
+	requireT.Len(events, 2)
+	changes, err = a.ConfigChanges(ctx, now)
+	requireT.NoError(err)
+	requireT.Empty(changes)
+}
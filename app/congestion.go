@@ -0,0 +1,101 @@
+package app
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+)
+
+const congestionPollInterval = 15 * time.Second
+
+// CongestionSource measures the congestion of the chain.
+type CongestionSource interface {
+	// GasPriceMultiplier returns the ratio of the current minimum gas price to the initial gas price of the chain.
+	GasPriceMultiplier(ctx context.Context) (float64, error)
+}
+
+// CongestionLevel reduces the grants to the percentage of the transfer amount once the gas price multiplier
+// reaches the threshold.
+type CongestionLevel struct {
+	Multiplier    float64
+	AmountPercent int64
+}
+
+// NewCongestionMonitor returns the monitor applying the highest of the levels reached by the gas price multiplier.
+func NewCongestionMonitor(source CongestionSource, levels ...CongestionLevel) *CongestionMonitor {
+	levels = append([]CongestionLevel{}, levels...)
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Multiplier < levels[j].Multiplier })
+	return &CongestionMonitor{
+		source:     source,
+		levels:     levels,
+		multiplier: 1,
+	}
+}
+
+// CongestionMonitor tracks the congestion of the chain, so grants are reduced during surges.
+type CongestionMonitor struct {
+	source CongestionSource
+	levels []CongestionLevel
+
+	mu         sync.RWMutex
+	multiplier float64
+}
+
+// AmountPercent returns the percentage of the transfer amount granted at the current congestion.
+// Nil monitor always grants the full amount.
+func (m *CongestionMonitor) AmountPercent() int64 {
+	if m == nil {
+		return 100
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	percent := int64(100)
+	for _, level := range m.levels {
+		if m.multiplier >= level.Multiplier {
+			percent = level.AmountPercent
+		}
+	}
+	return percent
+}
+
+// Run polls the congestion of the chain until the context is canceled. If the congestion can't be measured,
+// the last known value is kept.
+func (m *CongestionMonitor) Run(ctx context.Context) error {
+	for {
+		if err := m.poll(ctx); err != nil {
+			logger.Get(ctx).Error("Measuring chain congestion failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return errors.WithStack(ctx.Err())
+		case <-time.After(congestionPollInterval):
+		}
+	}
+}
+
+func (m *CongestionMonitor) poll(ctx context.Context) error {
+	multiplier, err := m.source.GasPriceMultiplier(ctx)
+	if err != nil {
+		return err
+	}
+	previous := m.AmountPercent()
+
+	m.mu.Lock()
+	m.multiplier = multiplier
+	m.mu.Unlock()
+
+	if current := m.AmountPercent(); current != previous {
+		logger.Get(ctx).Warn("Grant amount changed due to chain congestion",
+			zap.Float64("gasPriceMultiplier", math.Round(multiplier*100)/100),
+			zap.Int64("amountPercent", current))
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+type mockCongestionSource struct {
+	multiplier float64
+}
+
+func (m *mockCongestionSource) GasPriceMultiplier(ctx context.Context) (float64, error) {
+	return m.multiplier, nil
+}
+
+func TestCongestionMonitor(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+	source := &mockCongestionSource{multiplier: 1}
+	monitor := NewCongestionMonitor(source,
+		CongestionLevel{Multiplier: 5, AmountPercent: 0},
+		CongestionLevel{Multiplier: 2, AmountPercent: 50},
+	)
+	a := New(nil, nil, nil, nil, nil, chain.Network{}, chain.NewCoin("ucore", chain.NewInt(1000))).
+		WithCongestionMonitor(monitor)
+
+	requireT.NoError(monitor.poll(ctx))
+	assertT.EqualValues(100, monitor.AmountPercent())
+	assertT.Equal("1000ucore", a.grantAmount(Requester{}).String())
+
+	source.multiplier = 2.5
+	requireT.NoError(monitor.poll(ctx))
+	assertT.EqualValues(50, monitor.AmountPercent())
+	assertT.Equal("500ucore", a.grantAmount(Requester{}).String())
+
+	// at least one unit is granted
+	source.multiplier = 10
+	requireT.NoError(monitor.poll(ctx))
+	assertT.EqualValues(0, monitor.AmountPercent())
+	assertT.Equal("1ucore", a.grantAmount(Requester{}).String())
+
+	var nilMonitor *CongestionMonitor
+	assertT.EqualValues(100, nilMonitor.AmountPercent())
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// Controls is the state of the runtime controls applied by the operators.
+type Controls struct {
+	Paused      bool
+	PauseReason string
+	PausedAt    time.Time
+	// TransferAmount is the amount granted to each request, before it is reduced by congestion.
+	TransferAmount chain.Coin
+}
+
+// controls are changed by the operators at runtime and shared by all the copies of the app. They are kept in memory,
+// so the configured values apply again after restart.
+type controls struct {
+	mu             sync.RWMutex
+	paused         bool
+	pauseReason    string
+	pausedAt       time.Time
+	transferAmount chain.Int
+}
+
+// Controls returns the current state of the runtime controls.
+func (a App) Controls() Controls {
+	a.controls.mu.RLock()
+	defer a.controls.mu.RUnlock()
+
+	return Controls{
+		Paused:         a.controls.paused,
+		PauseReason:    a.controls.pauseReason,
+		PausedAt:       a.controls.pausedAt,
+		TransferAmount: a.baseTransferAmountLocked(),
+	}
+}
+
+// Pause suspends funding until the app is resumed. Requests are rejected with ErrFundingPaused. The change is
+// audited as made by the actor, like all the changes of the controls.
+func (a App) Pause(ctx context.Context, actor, reason string) Controls {
+	before := a.Controls()
+	a.controls.mu.Lock()
+	if !a.controls.paused {
+		a.controls.paused = true
+		a.controls.pausedAt = a.clock.Now().UTC()
+	}
+	a.controls.pauseReason = reason
+	a.controls.mu.Unlock()
+
+	logger.Get(ctx).Warn("Funding paused", zap.String("reason", reason))
+	return a.auditControls(ctx, actor, before)
+}
+
+// Resume resumes funding paused by Pause.
+func (a App) Resume(ctx context.Context, actor string) Controls {
+	before := a.Controls()
+	a.controls.mu.Lock()
+	a.controls.paused = false
+	a.controls.pauseReason = ""
+	a.controls.pausedAt = time.Time{}
+	a.controls.mu.Unlock()
+
+	logger.Get(ctx).Info("Funding resumed")
+	return a.auditControls(ctx, actor, before)
+}
+
+// SetTransferAmount changes the amount granted to each request in the denom of the configured transfer amount.
+// The amount can't exceed the absolute maximum of a single transfer.
+func (a App) SetTransferAmount(ctx context.Context, actor string, amount chain.Int) (Controls, error) {
+	if amount.IsNil() || !amount.IsPositive() {
+		return Controls{}, errors.Wrap(ErrInvalidAmount, "transfer amount must be positive")
+	}
+	if !a.maxTransferAmount.IsNil() && !a.maxTransferAmount.IsZero() && amount.GT(a.maxTransferAmount) {
+		return Controls{}, errors.Wrapf(ErrInvalidAmount, "transfer amount exceeds the absolute maximum of %s%s",
+			a.maxTransferAmount, a.transferAmount.Denom)
+	}
+
+	before := a.Controls()
+	a.controls.mu.Lock()
+	a.controls.transferAmount = amount
+	a.controls.mu.Unlock()
+
+	logger.Get(ctx).Warn("Transfer amount changed", zap.Stringer("amount", amount))
+	return a.auditControls(ctx, actor, before), nil
+}
+
+// auditControls records the changes of the controls since the state before and returns the current state.
+func (a App) auditControls(ctx context.Context, actor string, before Controls) Controls {
+	after := a.Controls()
+	a.RecordConfigChange(ctx, actor, ConfigSourceAdminAPI, diffControls(before, after))
+	return after
+}
+
+// checkPaused returns ErrFundingPaused if funding is paused by the operator.
+func (a App) checkPaused() error {
+	a.controls.mu.RLock()
+	defer a.controls.mu.RUnlock()
+
+	if !a.controls.paused {
+		return nil
+	}
+	if a.controls.pauseReason != "" {
+		return errors.Wrap(ErrFundingPaused, a.controls.pauseReason)
+	}
+	return errors.WithStack(ErrFundingPaused)
+}
+
+// baseTransferAmount returns the transfer amount set by the operator, or the configured one.
+func (a App) baseTransferAmount() chain.Coin {
+	a.controls.mu.RLock()
+	defer a.controls.mu.RUnlock()
+
+	return a.baseTransferAmountLocked()
+}
+
+func (a App) baseTransferAmountLocked() chain.Coin {
+	if a.controls.transferAmount.IsNil() {
+		return a.transferAmount
+	}
+	return chain.NewCoin(a.transferAmount.Denom, a.controls.transferAmount)
+}
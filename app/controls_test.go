@@ -0,0 +1,106 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockBlocklist map[string]BlockedAddress
+
+func (m mockBlocklist) BlockAddress(ctx context.Context, address chain.AccAddress, entry BlockedAddress) error {
+	m[string(address)] = entry
+	return nil
+}
+
+func (m mockBlocklist) UnblockAddress(ctx context.Context, address chain.AccAddress) error {
+	delete(m, string(address))
+	return nil
+}
+
+func (m mockBlocklist) BlockedAddress(ctx context.Context, address chain.AccAddress) (BlockedAddress, bool, error) {
+	entry, ok := m[string(address)]
+	return entry, ok, nil
+}
+
+func (m mockBlocklist) BlockedAddresses(ctx context.Context) ([]BlockedAddress, error) {
+	entries := make([]BlockedAddress, 0, len(m))
+	for _, entry := range m {
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func TestPause(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	batcher := &mockBatcher{txHash: "txhash"}
+	a := New(batcher, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{}, chain.Network{},
+		chain.Coin{}).WithClock(clock.NewOffset())
+	amount := chain.NewCoin("ucore", chain.NewInt(10))
+
+	controls := a.Pause(ctx, "alice", "incident")
+	requireT.True(controls.Paused)
+	requireT.Equal("incident", controls.PauseReason)
+	requireT.False(controls.PausedAt.IsZero())
+
+	// the state is shared by the copies of the app created by the options
+	_, err := a.WithMaxQueueDepth(10).send(ctx, Requester{}, chain.AccAddress{}, amount)
+	requireT.ErrorIs(err, ErrFundingPaused)
+	requireT.Zero(batcher.calls)
+
+	requireT.False(a.Resume(ctx, "alice").Paused)
+	_, err = a.send(ctx, Requester{}, chain.AccAddress{}, amount)
+	requireT.NoError(err)
+	requireT.Equal(1, batcher.calls)
+}
+
+func TestSetTransferAmount(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	a := New(nil, nil, nil, nil, nil, chain.Network{}, chain.NewCoin("ucore", chain.NewInt(1000))).
+		WithMaxTransferAmount(chain.NewInt(5000))
+	requireT.Equal("1000ucore", a.Controls().TransferAmount.String())
+
+	controls, err := a.SetTransferAmount(ctx, "alice", chain.NewInt(2000))
+	requireT.NoError(err)
+	requireT.Equal("2000ucore", controls.TransferAmount.String())
+	requireT.Equal("2000ucore", a.baseTransferAmount().String())
+
+	_, err = a.SetTransferAmount(ctx, "alice", chain.NewInt(5001))
+	requireT.ErrorIs(err, ErrInvalidAmount)
+	_, err = a.SetTransferAmount(ctx, "alice", chain.NewInt(0))
+	requireT.ErrorIs(err, ErrInvalidAmount)
+	requireT.Equal("2000ucore", a.Controls().TransferAmount.String())
+}
+
+func TestBlockedAddress(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	batcher := &mockBatcher{txHash: "txhash"}
+	blocklist := mockBlocklist{}
+	a := New(batcher, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{}, chain.Network{},
+		chain.Coin{}).
+		WithClock(clock.NewOffset()).
+		WithBlocklist(blocklist)
+	amount := chain.NewCoin("ucore", chain.NewInt(10))
+	blocked := chain.AccAddress("blocked-address-1234")
+
+	requireT.NoError(blocklist.BlockAddress(ctx, blocked, BlockedAddress{Address: "devcore1blocked", Reason: "abuse"}))
+	_, err := a.send(ctx, Requester{}, blocked, amount)
+	requireT.ErrorIs(err, ErrAddressBlocked)
+	requireT.Zero(batcher.calls)
+
+	_, err = a.send(ctx, Requester{}, chain.AccAddress("other-address-123456"), amount)
+	requireT.NoError(err)
+	requireT.Equal(1, batcher.calls)
+}
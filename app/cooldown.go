@@ -0,0 +1,98 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// AddressCooldowns persists the time each address was last funded, so the cooldown survives restarts.
+type AddressCooldowns interface {
+	// AddressFundedAt returns the time the address was last funded, zero if it is not recorded.
+	AddressFundedAt(ctx context.Context, address chain.AccAddress) (time.Time, error)
+	// ReserveAddressCooldown atomically records the funding of the address at now unless it was funded after
+	// notBefore. It returns the recorded funding time and whether it was reserved.
+	ReserveAddressCooldown(ctx context.Context, address chain.AccAddress, now, notBefore time.Time) (time.Time, bool, error)
+	// ReleaseAddressCooldown gives back the reservation made at the time if funding failed.
+	ReleaseAddressCooldown(ctx context.Context, address chain.AccAddress, reservedAt time.Time) error
+	// DeleteAddressCooldownsBefore deletes the funding times older than the time.
+	DeleteAddressCooldownsBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+// addressCooldown refuses funding the same address more than once per period.
+type addressCooldown struct {
+	store  AddressCooldowns
+	period time.Duration
+}
+
+// WithAddressCooldown returns a copy of the app funding each address at most once per period. Requests authorized
+// by the admin token or the bypass tokens are not subject to the cooldown.
+func (a App) WithAddressCooldown(store AddressCooldowns, period time.Duration) App {
+	a.cooldown = addressCooldown{store: store, period: period}
+	return a
+}
+
+func (c addressCooldown) enabled(requester Requester) bool {
+	return c.store != nil && c.period > 0 && !requester.Admin && requester.BypassTokenID == ""
+}
+
+// checkCooldown returns ErrAddressCooldown if the address was funded within the cooldown period.
+func (a App) checkCooldown(ctx context.Context, requester Requester, address chain.AccAddress) error {
+	if !a.cooldown.enabled(requester) {
+		return nil
+	}
+	fundedAt, err := a.cooldown.store.AddressFundedAt(ctx, address)
+	if err != nil {
+		return err
+	}
+	if now := a.clock.Now().UTC(); fundedAt.After(now.Add(-a.cooldown.period)) {
+		return a.cooldownError(address, fundedAt)
+	}
+	return nil
+}
+
+// sendWithCooldown sends the amount to the address, reserving the cooldown of the address first, so concurrent
+// requests fund it once. The reservation is given back if the funding fails.
+func (a App) sendWithCooldown(
+	ctx context.Context,
+	requester Requester,
+	address chain.AccAddress,
+	amount chain.Coin,
+) (string, error) {
+	if !a.cooldown.enabled(requester) {
+		return a.send(ctx, requester, address, amount)
+	}
+
+	now := a.clock.Now().UTC()
+	fundedAt, reserved, err := a.cooldown.store.ReserveAddressCooldown(ctx, address, now, now.Add(-a.cooldown.period))
+	if err != nil {
+		return "", err
+	}
+	if !reserved {
+		err := a.cooldownError(address, fundedAt)
+		a.ReportBlocked(ctx, requester, address.String(), err)
+		return "", err
+	}
+
+	txHash, err := a.send(ctx, requester, address, amount)
+	if err != nil {
+		if err := a.cooldown.store.ReleaseAddressCooldown(ctx, address, now); err != nil {
+			logger.Get(ctx).Error("Releasing address cooldown failed", zap.Error(err))
+		}
+		return "", err
+	}
+	return txHash, nil
+}
+
+func (a App) cooldownError(address chain.AccAddress, fundedAt time.Time) error {
+	return ThrottledError{
+		Cause:           errors.Wrapf(ErrAddressCooldown, "address %s was funded at %s", address, fundedAt.Format(time.RFC3339)),
+		NextAvailableAt: fundedAt.Add(a.cooldown.period).UTC(),
+	}
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockCooldowns map[string]time.Time
+
+func (m mockCooldowns) AddressFundedAt(ctx context.Context, address chain.AccAddress) (time.Time, error) {
+	return m[string(address)], nil
+}
+
+func (m mockCooldowns) ReserveAddressCooldown(
+	ctx context.Context,
+	address chain.AccAddress,
+	now, notBefore time.Time,
+) (time.Time, bool, error) {
+	if fundedAt := m[string(address)]; fundedAt.After(notBefore) {
+		return fundedAt, false, nil
+	}
+	m[string(address)] = now
+	return now, true, nil
+}
+
+func (m mockCooldowns) ReleaseAddressCooldown(ctx context.Context, address chain.AccAddress, reservedAt time.Time) error {
+	if m[string(address)].Equal(reservedAt) {
+		delete(m, string(address))
+	}
+	return nil
+}
+
+func (m mockCooldowns) DeleteAddressCooldownsBefore(ctx context.Context, before time.Time) (int, error) {
+	var deleted int
+	for address, fundedAt := range m {
+		if fundedAt.Before(before) {
+			delete(m, address)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func TestAddressCooldown(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewManual(now)
+	batcher := &mockBatcher{txHash: "tx1"}
+	cooldowns := mockCooldowns{}
+	a := New(batcher, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{}, network,
+		chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clk).
+		WithAddressCooldown(cooldowns, time.Hour)
+
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.NoError(err)
+
+	clk.Advance(30 * time.Minute)
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.ErrorIs(err, ErrAddressCooldown)
+	var throttled ThrottledError
+	requireT.ErrorAs(err, &throttled)
+	requireT.Equal(now.Add(time.Hour), throttled.NextAvailableAt)
+	requireT.Equal(1, batcher.calls)
+
+	// admin requests are not subject to the cooldown and don't reset it
+	_, err = a.GiveFunds(ctx, Requester{Admin: true}, address)
+	requireT.NoError(err)
+	requireT.Equal(2, batcher.calls)
+
+	// failed funding gives the reservation back
+	clk.Advance(30 * time.Minute)
+	batcher.err = errors.New("connection refused")
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.ErrorIs(err, ErrUnableToTransferToken)
+	requireT.Empty(cooldowns)
+
+	batcher.err = nil
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.NoError(err)
+
+	clk.Advance(2 * time.Hour)
+	collected, err := a.CollectGarbage(ctx)
+	requireT.NoError(err)
+	requireT.Equal(1, collected[ExpiringAddressCooldown])
+	requireT.Empty(cooldowns)
+}
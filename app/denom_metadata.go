@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// denomMetadataRetryInterval is how long the denom without metadata, or whose metadata can't be queried, is
+// rendered without display units before the chain is asked again.
+const denomMetadataRetryInterval = 5 * time.Minute
+
+// DenomMetadataSource provides the metadata of denoms registered on chain.
+type DenomMetadataSource interface {
+	// DenomMetadata returns the metadata of the denom, its base is empty if the denom has no metadata.
+	DenomMetadata(ctx context.Context, denom string) (chain.DenomMetadata, error)
+}
+
+// DisplayCoin is the coin expressed in the display unit of its denom, e.g. 1.00 TESTCORE instead of 1000000utestcore.
+type DisplayCoin struct {
+	Denom    string
+	Symbol   string
+	Decimals uint32
+	Amount   string
+}
+
+type denomMetadataEntry struct {
+	display   DisplayCoin
+	found     bool
+	fetchedAt time.Time
+}
+
+type denomMetadataCache struct {
+	source DenomMetadataSource
+
+	mu      sync.Mutex
+	entries map[string]denomMetadataEntry
+}
+
+// WithDenomMetadata returns a copy of the app rendering coins in display units defined by the on-chain
+// denom metadata. Metadata rarely changes, so it is cached.
+func (a App) WithDenomMetadata(source DenomMetadataSource) App {
+	a.denomMetadata = &denomMetadataCache{source: source, entries: map[string]denomMetadataEntry{}}
+	return a
+}
+
+// DisplayCoin returns the coin expressed in the display unit of its denom. False is returned if the denom
+// has no metadata or the metadata is not enabled.
+func (a App) DisplayCoin(ctx context.Context, coin chain.Coin) (DisplayCoin, bool) {
+	if a.denomMetadata == nil {
+		return DisplayCoin{}, false
+	}
+	display, found := a.denomMetadata.get(ctx, coin.Denom, a.clock.Now())
+	if !found {
+		return DisplayCoin{}, false
+	}
+	display.Amount = formatDecimal(coin.Amount.String(), display.Decimals)
+	return display, true
+}
+
+func (c *denomMetadataCache) get(ctx context.Context, denom string, now time.Time) (DisplayCoin, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[denom]
+	if ok && (entry.found || now.Sub(entry.fetchedAt) < denomMetadataRetryInterval) {
+		return entry.display, entry.found
+	}
+
+	entry = denomMetadataEntry{fetchedAt: now}
+	metadata, err := c.source.DenomMetadata(ctx, denom)
+	if err != nil {
+		logger.Get(ctx).Warn("Querying denom metadata failed", zap.String("denom", denom), zap.Error(err))
+	} else {
+		entry.display, entry.found = displayUnit(metadata)
+	}
+	c.entries[denom] = entry
+	return entry.display, entry.found
+}
+
+// displayUnit returns the display unit of the metadata, false if the unit is not defined.
+func displayUnit(metadata chain.DenomMetadata) (DisplayCoin, bool) {
+	if metadata.Display == "" {
+		return DisplayCoin{}, false
+	}
+	for _, unit := range metadata.DenomUnits {
+		if unit.Denom == metadata.Display {
+			return DisplayCoin{
+				Denom:    metadata.Display,
+				Symbol:   metadata.Symbol,
+				Decimals: unit.Exponent,
+			}, true
+		}
+	}
+	return DisplayCoin{}, false
+}
+
+// formatDecimal shifts the decimal point of the integer amount by decimals places, keeping at least
+// two fractional digits, e.g. 1000000 with 6 decimals is 1.00 and 1234567 is 1.234567.
+func formatDecimal(amount string, decimals uint32) string {
+	if decimals == 0 {
+		return amount
+	}
+	negative := strings.HasPrefix(amount, "-")
+	amount = strings.TrimPrefix(amount, "-")
+	if pad := int(decimals) + 1 - len(amount); pad > 0 {
+		amount = strings.Repeat("0", pad) + amount
+	}
+	integer, fraction := amount[:len(amount)-int(decimals)], amount[len(amount)-int(decimals):]
+	fraction = strings.TrimRight(fraction, "0")
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+	if negative {
+		integer = "-" + integer
+	}
+	return integer + "." + fraction
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockDenomMetadataSource struct {
+	metadata map[string]chain.DenomMetadata
+	err      error
+	calls    int
+}
+
+func (m *mockDenomMetadataSource) DenomMetadata(ctx context.Context, denom string) (chain.DenomMetadata, error) {
+	m.calls++
+	return m.metadata[denom], m.err
+}
+
+func TestDisplayCoin(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	clk := clock.NewOffset()
+	source := &mockDenomMetadataSource{err: errors.New("connection refused")}
+	a := New(nil, nil, nil, nil, nil, chain.Network{}, chain.Coin{}).WithClock(clk).WithDenomMetadata(source)
+	coin := chain.NewCoin("utestcore", chain.NewInt(1500000))
+
+	// failures are not retried until the retry interval elapses
+	_, ok := a.DisplayCoin(ctx, coin)
+	requireT.False(ok)
+	_, ok = a.DisplayCoin(ctx, coin)
+	requireT.False(ok)
+	requireT.Equal(1, source.calls)
+
+	clk.Advance(denomMetadataRetryInterval)
+	source.err = nil
+	source.metadata = map[string]chain.DenomMetadata{
+		"utestcore": {
+			Base:    "utestcore",
+			Display: "testcore",
+			Symbol:  "TESTCORE",
+			DenomUnits: []*chain.DenomUnit{
+				{Denom: "utestcore", Exponent: 0},
+				{Denom: "testcore", Exponent: 6},
+			},
+		},
+	}
+	display, ok := a.DisplayCoin(ctx, coin)
+	requireT.True(ok)
+	requireT.Equal(DisplayCoin{Denom: "testcore", Symbol: "TESTCORE", Decimals: 6, Amount: "1.50"}, display)
+
+	// found metadata is cached
+	_, ok = a.DisplayCoin(ctx, chain.NewCoin("utestcore", chain.NewInt(1)))
+	requireT.True(ok)
+	requireT.Equal(2, source.calls)
+
+	// metadata without display unit is useless
+	_, ok = a.DisplayCoin(ctx, chain.NewCoin("ibc/ABC", chain.NewInt(1)))
+	requireT.False(ok)
+}
+
+func TestFormatDecimal(t *testing.T) {
+	requireT := require.New(t)
+
+	requireT.Equal("1.00", formatDecimal("1000000", 6))
+	requireT.Equal("1.234567", formatDecimal("1234567", 6))
+	requireT.Equal("0.000015", formatDecimal("15", 6))
+	requireT.Equal("0.00", formatDecimal("0", 6))
+	requireT.Equal("1000", formatDecimal("1000", 0))
+	requireT.Equal("-2.50", formatDecimal("-25", 1))
+	requireT.Equal("123456789012.00", formatDecimal("123456789012000000000000000000", 18))
+}
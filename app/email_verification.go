@@ -0,0 +1,240 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ProofEmail is the key of the token of the link sent by email in Requester.Proofs.
+	ProofEmail = "email"
+
+	minEmailKeyLength = 16
+)
+
+// EmailMessage is the email sent to the client.
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// EmailSender delivers the emails, e.g. by SMTP or Amazon SES.
+type EmailSender interface {
+	SendEmail(ctx context.Context, msg EmailMessage) error
+}
+
+// EmailVerification is the pending link sent to the email, the address is funded once it is opened.
+type EmailVerification struct {
+	ID        string    `json:"id"`
+	Address   string    `json:"address"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// EmailVerificationStore persists the pending links, so each of them is used once.
+type EmailVerificationStore interface {
+	PutEmailVerification(ctx context.Context, verification EmailVerification) error
+	// UseEmailVerification deletes the link and returns it, it returns ErrEmailVerificationFailed if the link
+	// is unknown, used already or expired at the time.
+	UseEmailVerification(ctx context.Context, id string, now time.Time) (EmailVerification, error)
+	// DeleteExpiredEmailVerifications deletes the links expired at the time and returns the number of deleted ones.
+	DeleteExpiredEmailVerifications(ctx context.Context, now time.Time) (int, error)
+}
+
+// EmailVerificationConfig configures the email verification.
+type EmailVerificationConfig struct {
+	// SigningKey of at least 16 characters signs the links.
+	SigningKey string
+	// LinkURL is the URL the token is appended to as the `token` query parameter, either the email/verify endpoint
+	// of the faucet or the page of the faucet UI calling it.
+	LinkURL string
+	// TTL is how long the link may be opened once sent.
+	TTL time.Duration
+	// Subject is the subject of the email.
+	Subject string
+}
+
+// EmailVerificationRequest describes the link sent to the email.
+type EmailVerificationRequest struct {
+	Address   string
+	ExpiresAt time.Time
+}
+
+// EmailFunding is the result of opening the link.
+type EmailFunding struct {
+	Address string
+	TxHash  string
+}
+
+type emailVerifier struct {
+	cfg    EmailVerificationConfig
+	store  EmailVerificationStore
+	sender EmailSender
+}
+
+// WithEmailVerification returns a copy of the app funding the address only once the client opens the one-time
+// link sent to the email, so each grant costs a working mailbox. The links are signed by the key of the config
+// and stored until they are used or expire. Requests authenticated by the admin token, API key or bypass token
+// are exempt, because they are sent by automation.
+func (a App) WithEmailVerification(
+	cfg EmailVerificationConfig,
+	store EmailVerificationStore,
+	sender EmailSender,
+) (App, error) {
+	if len(cfg.SigningKey) < minEmailKeyLength {
+		return App{}, errors.Errorf("key of at least %d characters is required to sign the email links",
+			minEmailKeyLength)
+	}
+	linkURL, err := url.Parse(cfg.LinkURL)
+	if err != nil || (linkURL.Scheme != "http" && linkURL.Scheme != "https") || linkURL.Host == "" {
+		return App{}, errors.Errorf("link URL must be absolute http(s) URL, got %q", cfg.LinkURL)
+	}
+	if cfg.TTL <= 0 {
+		return App{}, errors.New("lifetime of the email link must be positive")
+	}
+	if store == nil || sender == nil {
+		return App{}, errors.New("store and sender are required to verify emails")
+	}
+	if cfg.Subject == "" {
+		cfg.Subject = "Confirm your faucet request"
+	}
+	a.email = &emailVerifier{cfg: cfg, store: store, sender: sender}
+	return a, nil
+}
+
+// EmailVerificationEnabled tells if the addresses are funded only through the links sent by email.
+func (a App) EmailVerificationEnabled() bool {
+	return a.email != nil
+}
+
+// RequestEmailVerification sends the link funding the address to the email.
+func (a App) RequestEmailVerification(ctx context.Context, address, email string) (EmailVerificationRequest, error) {
+	if a.email == nil {
+		return EmailVerificationRequest{}, errors.Wrap(ErrEmailVerificationFailed, "email verification is not required")
+	}
+	if _, err := a.validateAddress(address); err != nil {
+		return EmailVerificationRequest{}, err
+	}
+	parsed, err := mail.ParseAddress(email)
+	if err != nil || parsed.Address != strings.TrimSpace(email) {
+		return EmailVerificationRequest{}, errors.Wrapf(ErrInvalidEmail, "email: %q", email)
+	}
+
+	rawID := make([]byte, 16)
+	if _, err := rand.Read(rawID); err != nil {
+		return EmailVerificationRequest{}, errors.WithStack(err)
+	}
+	now := a.clock.Now().UTC()
+	verification := EmailVerification{
+		ID:        hex.EncodeToString(rawID),
+		Address:   address,
+		Email:     parsed.Address,
+		CreatedAt: now,
+		ExpiresAt: now.Add(a.email.cfg.TTL).Truncate(time.Second),
+	}
+	if err := a.email.store.PutEmailVerification(ctx, verification); err != nil {
+		return EmailVerificationRequest{}, err
+	}
+	if err := a.email.sender.SendEmail(ctx, a.email.message(verification)); err != nil {
+		return EmailVerificationRequest{}, errors.Wrapf(ErrEmailUnavailable, "err:%s", err)
+	}
+	return EmailVerificationRequest{Address: address, ExpiresAt: verification.ExpiresAt}, nil
+}
+
+// VerifyEmail funds the address of the link opened by the client.
+func (a App) VerifyEmail(ctx context.Context, requester Requester, token string) (EmailFunding, error) {
+	if a.email == nil {
+		return EmailFunding{}, errors.Wrap(ErrEmailVerificationFailed, "email verification is not required")
+	}
+	address, _, err := a.email.parse(token)
+	if err != nil {
+		return EmailFunding{}, err
+	}
+	proofs := Proofs{}
+	for name, proof := range requester.Proofs {
+		proofs[name] = proof
+	}
+	proofs[ProofEmail] = token
+	requester.Proofs = proofs
+
+	txHash, err := a.GiveFunds(ctx, requester, address)
+	if err != nil {
+		return EmailFunding{}, err
+	}
+	return EmailFunding{Address: address, TxHash: txHash}, nil
+}
+
+// checkEmailVerified spends the link sent to the email of the client, it must be issued for the address.
+func (a App) checkEmailVerified(ctx context.Context, requester Requester, address string) error {
+	if a.email == nil || requester.Admin || requester.APIKeyHolder != "" || requester.BypassTokenID != "" {
+		return nil
+	}
+	token := requester.Proofs[ProofEmail]
+	if token == "" {
+		return errors.Wrap(ErrEmailVerificationFailed, "address must be confirmed by the link sent by email")
+	}
+	linkAddress, id, err := a.email.parse(token)
+	if err != nil {
+		return err
+	}
+	if linkAddress != address {
+		return errors.Wrap(ErrEmailVerificationFailed, "link is issued for another address")
+	}
+	_, err = a.email.store.UseEmailVerification(ctx, id, a.clock.Now().UTC())
+	return err
+}
+
+func (v *emailVerifier) token(verification EmailVerification) string {
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(verification.Address))
+	return encoded + "." + verification.ID + "." + v.signature(verification.Address, verification.ID)
+}
+
+// parse checks the signature of the token and returns the address and ID of the link.
+func (v *emailVerifier) parse(token string) (string, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", "", errors.Wrap(ErrEmailVerificationFailed, "malformed link")
+	}
+	address, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", "", errors.Wrap(ErrEmailVerificationFailed, "malformed link")
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(v.signature(string(address), parts[1]))) {
+		return "", "", errors.Wrap(ErrEmailVerificationFailed, "link is not issued by the faucet")
+	}
+	return string(address), parts[1], nil
+}
+
+func (v *emailVerifier) signature(address, id string) string {
+	mac := hmac.New(sha256.New, []byte(v.cfg.SigningKey))
+	mac.Write([]byte("email\x00" + address + "\x00" + id))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (v *emailVerifier) message(verification EmailVerification) EmailMessage {
+	separator := "?"
+	if strings.Contains(v.cfg.LinkURL, "?") {
+		separator = "&"
+	}
+	link := v.cfg.LinkURL + separator + "token=" + url.QueryEscape(v.token(verification))
+	return EmailMessage{
+		To:      verification.Email,
+		Subject: v.cfg.Subject,
+		Body: fmt.Sprintf("Open the link to receive the funds to %s:\r\n\r\n%s\r\n\r\n"+
+			"The link may be used once until %s. Ignore this email if you didn't request the funds.\r\n",
+			verification.Address, link, verification.ExpiresAt.Format(time.RFC1123)),
+	}
+}
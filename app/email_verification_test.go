@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockEmailVerificationStore struct {
+	EmailVerificationStore
+	verifications map[string]EmailVerification
+}
+
+func (m *mockEmailVerificationStore) PutEmailVerification(ctx context.Context, v EmailVerification) error {
+	m.verifications[v.ID] = v
+	return nil
+}
+
+func (m *mockEmailVerificationStore) UseEmailVerification(
+	ctx context.Context,
+	id string,
+	now time.Time,
+) (EmailVerification, error) {
+	v, ok := m.verifications[id]
+	if !ok {
+		return EmailVerification{}, errors.Wrap(ErrEmailVerificationFailed, "link is unknown or used already")
+	}
+	if !now.Before(v.ExpiresAt) {
+		return EmailVerification{}, errors.Wrap(ErrEmailVerificationFailed, "link is expired")
+	}
+	delete(m.verifications, id)
+	return v, nil
+}
+
+type mockEmailSender struct {
+	sent []EmailMessage
+	err  error
+}
+
+func (m *mockEmailSender) SendEmail(ctx context.Context, msg EmailMessage) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+var linkRegexp = regexp.MustCompile(`https://\S+`)
+
+func linkToken(t *testing.T, msg EmailMessage) string {
+	link, err := url.Parse(linkRegexp.FindString(msg.Body))
+	require.NoError(t, err)
+	return link.Query().Get("token")
+}
+
+func TestEmailVerification(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+	const other = "devcore1kdxhx3vv6n0g4ffgdqp8y7e44q7rzjvvlvlg3q"
+
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	batcher := &mockBatcher{txHash: "tx1"}
+	a := New(batcher, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{}, network,
+		chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clk)
+	requireT.False(a.EmailVerificationEnabled())
+
+	store := &mockEmailVerificationStore{verifications: map[string]EmailVerification{}}
+	sender := &mockEmailSender{}
+	cfg := EmailVerificationConfig{
+		SigningKey: "0123456789abcdef",
+		LinkURL:    "https://faucet.example.com/verify?lang=en",
+		TTL:        time.Hour,
+	}
+	_, err = a.WithEmailVerification(EmailVerificationConfig{SigningKey: "short", LinkURL: cfg.LinkURL, TTL: time.Hour},
+		store, sender)
+	requireT.Error(err)
+	_, err = a.WithEmailVerification(EmailVerificationConfig{SigningKey: cfg.SigningKey, LinkURL: "/verify",
+		TTL: time.Hour}, store, sender)
+	requireT.Error(err)
+	a, err = a.WithEmailVerification(cfg, store, sender)
+	requireT.NoError(err)
+	requireT.True(a.EmailVerificationEnabled())
+
+	_, err = a.RequestEmailVerification(ctx, address, "not an email")
+	requireT.ErrorIs(err, ErrInvalidEmail)
+	_, err = a.RequestEmailVerification(ctx, address, "Dev <dev@example.com>")
+	requireT.ErrorIs(err, ErrInvalidEmail)
+	_, err = a.RequestEmailVerification(ctx, "devcore1invalid", "dev@example.com")
+	requireT.ErrorIs(err, ErrInvalidAddressFormat)
+
+	request, err := a.RequestEmailVerification(ctx, address, "dev@example.com")
+	requireT.NoError(err)
+	requireT.Equal(clk.Now().Add(time.Hour), request.ExpiresAt)
+	requireT.Len(sender.sent, 1)
+	requireT.Equal("dev@example.com", sender.sent[0].To)
+	requireT.Equal("Confirm your faucet request", sender.sent[0].Subject)
+	requireT.Contains(sender.sent[0].Body, "https://faucet.example.com/verify?lang=en&token=")
+	token := linkToken(t, sender.sent[0])
+
+	// the address is funded only through the link
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.ErrorIs(err, ErrEmailVerificationFailed)
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofEmail: token}}, other)
+	requireT.ErrorIs(err, ErrEmailVerificationFailed)
+	tampered := "0"
+	if strings.HasSuffix(token, "0") {
+		tampered = "1"
+	}
+	_, err = a.VerifyEmail(ctx, Requester{}, token[:len(token)-1]+tampered)
+	requireT.ErrorIs(err, ErrEmailVerificationFailed)
+	requireT.Zero(batcher.calls)
+
+	funding, err := a.VerifyEmail(ctx, Requester{}, token)
+	requireT.NoError(err)
+	requireT.Equal(EmailFunding{Address: address, TxHash: "tx1"}, funding)
+	// the link is used once
+	_, err = a.VerifyEmail(ctx, Requester{}, token)
+	requireT.ErrorIs(err, ErrEmailVerificationFailed)
+
+	// automation is exempt
+	_, err = a.GiveFunds(ctx, Requester{Admin: true}, other)
+	requireT.NoError(err)
+
+	_, err = a.RequestEmailVerification(ctx, other, "dev@example.com")
+	requireT.NoError(err)
+	clk.Advance(time.Hour)
+	_, err = a.VerifyEmail(ctx, Requester{}, linkToken(t, sender.sent[1]))
+	requireT.ErrorIs(err, ErrEmailVerificationFailed)
+	requireT.ErrorContains(err, "expired")
+
+	sender.err = errors.New("connection refused")
+	_, err = a.RequestEmailVerification(ctx, address, "dev@example.com")
+	requireT.ErrorIs(err, ErrEmailUnavailable)
+}
@@ -1,10 +1,126 @@
 package app
 
-import "github.com/pkg/errors"
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
 
 // Error type produced by app.
 var (
-	ErrInvalidAddressFormat     = errors.New("invalid address format")
-	ErrAddressPrefixUnsupported = errors.New("address prefix is not supported by this chain")
-	ErrUnableToTransferToken    = errors.New("unable to transfer tokens")
+	ErrInvalidAddressFormat        = errors.New("invalid address format")
+	ErrAddressPrefixUnsupported    = errors.New("address prefix is not supported by this chain")
+	ErrUnableToTransferToken       = errors.New("unable to transfer tokens")
+	ErrTxNotFound                  = errors.New("transaction not found")
+	ErrQueueFull                   = errors.New("too many pending requests")
+	ErrTransferAboveMaximum        = errors.New("transfer amount exceeds the absolute maximum")
+	ErrRecipientNotFound           = errors.New("recipient not found in address book")
+	ErrInvalidRecipientName        = errors.New("invalid recipient name")
+	ErrClaimCodeNotFound           = errors.New("claim code not found")
+	ErrClaimCodeUsedUp             = errors.New("claim code is used up")
+	ErrClaimCodeAddressMismatch    = errors.New("claim code is bound to another address")
+	ErrInvalidClaimCode            = errors.New("invalid claim code")
+	ErrClaimCodeExpired            = errors.New("claim code is expired")
+	ErrSigningUnavailable          = errors.New("signing unavailable")
+	ErrChallengeNotFound           = errors.New("challenge not found or expired")
+	ErrChallengeTxNotFound         = errors.New("challenge transaction not found")
+	ErrChallengeFailed             = errors.New("challenge failed")
+	ErrInvalidCallbackURL          = errors.New("callback URL is not allowed")
+	ErrFundingPaused               = errors.New("funding is paused by the operator")
+	ErrInvalidAmount               = errors.New("invalid amount")
+	ErrAddressBlocked              = errors.New("address is blocked")
+	ErrAddressNotBlocked           = errors.New("address is not blocked")
+	ErrInvalidExpiry               = errors.New("invalid expiry")
+	ErrAddressCooldown             = errors.New("address was funded recently")
+	ErrLifetimeCapReached          = errors.New("address reached its lifetime funding cap")
+	ErrBudgetExceeded              = errors.New("daily budget exceeded")
+	ErrToSNotAccepted              = errors.New("terms of service are not accepted")
+	ErrToSTokenInvalid             = errors.New("terms of service acceptance token is invalid")
+	ErrToSVersionMismatch          = errors.New("terms of service version is not the current one")
+	ErrToSNotRequired              = errors.New("terms of service acceptance is not required")
+	ErrBypassTokenInvalid          = errors.New("bypass token is invalid")
+	ErrBypassTokenNotFound         = errors.New("bypass token not found")
+	ErrBypassTokenQuotaExhausted   = errors.New("bypass token quota exhausted")
+	ErrInvalidBypassTokenSpec      = errors.New("invalid bypass token spec")
+	ErrNamedAccountsDisabled       = errors.New("named accounts are disabled")
+	ErrNamedAccountNotFound        = errors.New("named account not found")
+	ErrInvalidAccountLabel         = errors.New("invalid account label")
+	ErrAccountLabelUnauthorized    = errors.New("account label requires authentication")
+	ErrInvalidConfirmations        = errors.New("invalid number of confirmations")
+	ErrCaptchaFailed               = errors.New("captcha verification failed")
+	ErrCaptchaUnavailable          = errors.New("captcha verification is unavailable")
+	ErrTenantNotificationsNotFound = errors.New("tenant notifications not found")
+	ErrInvalidTenantNotifications  = errors.New("invalid tenant notifications")
+	ErrQRLinkUnavailable           = errors.New("wallet deep link is not configured")
+	ErrPoWFailed                   = errors.New("proof of work verification failed")
+	ErrRefillProposalNotFound      = errors.New("refill proposal not found")
+	ErrRefillSignatureInvalid      = errors.New("invalid signature of the refill proposal")
+	ErrInvalidRefill               = errors.New("invalid refill")
+	ErrInvalidInvoiceID            = errors.New("invalid invoice ID")
+	ErrInvoiceIDUnauthorized       = errors.New("invoice ID requires API key")
+	ErrBalanceAboveThreshold       = errors.New("address holds enough funds already")
+	ErrAbuseSuspected              = errors.New("request is suspected of abuse")
+	ErrOwnershipProofFailed        = errors.New("proof of address ownership failed")
+	ErrIdentityFailed              = errors.New("identity verification failed")
+	ErrIdentityRejected            = errors.New("account doesn't meet the requirements")
+	ErrIdentityUnavailable         = errors.New("identity provider is unavailable")
+	ErrIdentityProviderNotFound    = errors.New("identity provider not found")
+	ErrIdentityQuotaExhausted      = errors.New("account quota exhausted")
+	ErrEmailVerificationFailed     = errors.New("email verification failed")
+	ErrInvalidEmail                = errors.New("invalid email")
+	ErrEmailUnavailable            = errors.New("email delivery is unavailable")
+	ErrPhoneVerificationFailed     = errors.New("phone verification failed")
+	ErrInvalidPhone                = errors.New("invalid phone number")
+	ErrSMSUnavailable              = errors.New("SMS delivery is unavailable")
+	ErrInvalidFundingLink          = errors.New("invalid funding link")
+	ErrFundingLinkRejected         = errors.New("funding link is rejected")
+	ErrFundingLinkExpired          = errors.New("funding link is expired")
+	ErrFundingLinkUsed             = errors.New("funding link is used already")
 )
+
+// ThrottledError is returned when the request is rejected temporarily and might be retried later.
+// It wraps the cause, so it is still matched by errors.Is.
+type ThrottledError struct {
+	Cause           error
+	NextAvailableAt time.Time
+}
+
+func (e ThrottledError) Error() string {
+	return e.Cause.Error()
+}
+
+// Unwrap returns the cause of the rejection.
+func (e ThrottledError) Unwrap() error {
+	return e.Cause
+}
+
+// BudgetExceededError is returned when the daily budget of the denom is exhausted.
+type BudgetExceededError struct {
+	Cause error
+	Denom string
+}
+
+func (e BudgetExceededError) Error() string {
+	return e.Cause.Error()
+}
+
+// Unwrap returns the cause of the rejection.
+func (e BudgetExceededError) Unwrap() error {
+	return e.Cause
+}
+
+// AddressSuggestionError is returned when the address is invalid, but the valid address the client most probably
+// meant is known, e.g. a single character is mistyped.
+type AddressSuggestionError struct {
+	Cause      error
+	Suggestion string
+}
+
+func (e AddressSuggestionError) Error() string {
+	return e.Cause.Error()
+}
+
+// Unwrap returns the cause of the rejection.
+func (e AddressSuggestionError) Unwrap() error {
+	return e.Cause
+}
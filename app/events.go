@@ -0,0 +1,140 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/coreum-tools/pkg/parallel"
+)
+
+// eventBufferSize is the number of events queued for each subscriber before new events are dropped.
+const eventBufferSize = 1024
+
+// EventKind is the kind of the event published on the event bus.
+type EventKind string
+
+// Kinds of published events.
+const (
+	// EventRequestAccepted is published when the funding request passes validation and is queued for sending.
+	EventRequestAccepted EventKind = "request_accepted"
+	// EventBroadcast is published when the transaction funding the request is broadcast.
+	EventBroadcast EventKind = "broadcast"
+	// EventConfirmed is published when the transaction collects the number of confirmations required by any
+	// of its requests, so it may be published more than once for the same transaction.
+	// Transaction may fund many requests, so Requester and Address of the event are empty.
+	EventConfirmed EventKind = "confirmed"
+	// EventFailed is published when sending the funds fails.
+	EventFailed EventKind = "failed"
+	// EventBlocked is published when the request is rejected by rate limiting or backpressure.
+	EventBlocked EventKind = "blocked"
+	// EventConfigChanged is published when the runtime configuration is changed, e.g. by the admin API.
+	// It doesn't belong to any request, so Requester and Address of the event are empty.
+	EventConfigChanged EventKind = "config_changed"
+)
+
+// Event describes something that happened to the funding request or transaction.
+type Event struct {
+	Kind      EventKind
+	Time      time.Time
+	Requester Requester
+	Address   string
+	TxHash    string
+	// Confirmations is the number of confirmations collected by the transaction of the confirmed event.
+	Confirmations int64
+	// Reason is the error causing the failed and blocked events.
+	Reason string
+	// Actor is who changed the configuration of the config changed event.
+	Actor string
+	// Changes are the settings changed by the config changed event.
+	Changes []ConfigDiff
+}
+
+// Subscriber handles events delivered by the event bus.
+type Subscriber func(ctx context.Context, event Event)
+
+type subscription struct {
+	name    string
+	handler Subscriber
+	events  chan Event
+}
+
+// NewEventBus returns new instance of EventBus.
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// EventBus delivers events to in-process subscribers. Each subscriber receives events in the order they were
+// published, in its own goroutine, so slow subscriber never blocks funding. If the subscriber can't keep up,
+// events are dropped for it. Nil bus is valid and discards all the events.
+type EventBus struct {
+	mu            sync.RWMutex
+	subscriptions []*subscription
+}
+
+// Subscribe registers the subscriber. It must be called before the bus is run.
+func (b *EventBus) Subscribe(name string, handler Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscriptions = append(b.subscriptions, &subscription{
+		name:    name,
+		handler: handler,
+		events:  make(chan Event, eventBufferSize),
+	})
+}
+
+// Publish queues the event to be delivered to all the subscribers.
+func (b *EventBus) Publish(ctx context.Context, event Event) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, s := range b.subscriptions {
+		select {
+		case s.events <- event:
+		default:
+			logger.Get(ctx).Warn("Event subscriber is too slow, dropping event",
+				zap.String("subscriber", s.name), zap.String("kind", string(event.Kind)))
+		}
+	}
+}
+
+// Run delivers events to subscribers until the context is canceled.
+func (b *EventBus) Run(ctx context.Context) error {
+	b.mu.RLock()
+	subscriptions := b.subscriptions
+	b.mu.RUnlock()
+
+	if len(subscriptions) == 0 {
+		<-ctx.Done()
+		return errors.WithStack(ctx.Err())
+	}
+
+	return parallel.Run(ctx, func(ctx context.Context, spawn parallel.SpawnFn) error {
+		for _, s := range subscriptions {
+			s := s
+			spawn(s.name, parallel.Fail, func(ctx context.Context) error {
+				for {
+					select {
+					case <-ctx.Done():
+						return errors.WithStack(ctx.Err())
+					case event := <-s.events:
+						s.handler(ctx, event)
+					}
+				}
+			})
+		}
+		return nil
+	})
+}
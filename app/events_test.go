@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+func TestEventBus(t *testing.T) {
+	requireT := require.New(t)
+
+	ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), zaptest.NewLogger(t)))
+	t.Cleanup(cancel)
+
+	bus := NewEventBus()
+	received := map[string]chan Event{
+		"first":  make(chan Event, 10),
+		"second": make(chan Event, 10),
+	}
+	for name, ch := range received {
+		ch := ch
+		bus.Subscribe(name, func(ctx context.Context, event Event) {
+			ch <- event
+		})
+	}
+	go func() {
+		_ = bus.Run(ctx)
+	}()
+
+	bus.Publish(ctx, Event{Kind: EventRequestAccepted, Address: "addr1"})
+	bus.Publish(ctx, Event{Kind: EventBroadcast, Address: "addr1", TxHash: "tx1"})
+
+	for _, ch := range received {
+		for _, kind := range []EventKind{EventRequestAccepted, EventBroadcast} {
+			select {
+			case event := <-ch:
+				requireT.Equal(kind, event.Kind)
+				requireT.Equal("addr1", event.Address)
+				requireT.False(event.Time.IsZero())
+			case <-time.After(time.Second):
+				requireT.Fail("event not delivered")
+			}
+		}
+	}
+
+	// nil bus discards events
+	var nilBus *EventBus
+	nilBus.Publish(ctx, Event{Kind: EventFailed})
+}
+
+func TestTxTracker_PublishesConfirmed(t *testing.T) {
+	requireT := require.New(t)
+
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+	bus := NewEventBus()
+	// the bus is not run, so events stay queued for the subscriber
+	bus.Subscribe("test", func(ctx context.Context, event Event) {})
+
+	chain := &mockChainClient{latestHeight: 10, txHeights: map[string]int64{"tx1": 10}}
+	tracker := NewTxTracker(chain, 2, bus, clock.System{})
+	tracker.TxBroadcast("tx1", 10, nil)
+
+	requireT.NoError(tracker.poll(ctx))
+	requireT.Len(bus.subscriptions[0].events, 0)
+
+	chain.latestHeight = 11
+	requireT.NoError(tracker.poll(ctx))
+	chain.latestHeight = 12
+	requireT.NoError(tracker.poll(ctx))
+
+	// confirmation is published once
+	requireT.Len(bus.subscriptions[0].events, 1)
+	event := <-bus.subscriptions[0].events
+	requireT.Equal(EventConfirmed, event.Kind)
+	requireT.Equal("tx1", event.TxHash)
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"hash/fnv"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// Groups of the clients taking part in the experiment.
+const (
+	VariantControl    = "control"
+	VariantExperiment = "experiment"
+)
+
+// Experiment applies the variant of the policy to the share of the clients, so its impact may be compared with
+// the control group before it is rolled out to everyone.
+type Experiment struct {
+	// Name identifies the experiment in metrics, renaming it reshuffles the groups.
+	Name string
+	// Percent of the clients assigned to the experiment group.
+	Percent int
+	// TransferAmount granted to the experiment group, nil means the amount of the control group.
+	TransferAmount chain.Int
+}
+
+// Validate verifies the experiment.
+func (e Experiment) Validate() error {
+	if e.Name == "" {
+		return errors.New("experiment name is required")
+	}
+	if e.Percent < 0 || e.Percent > 100 {
+		return errors.Errorf("percent of the clients must be between 0 and 100, got %d", e.Percent)
+	}
+	if !e.TransferAmount.IsNil() && !e.TransferAmount.IsPositive() {
+		return errors.New("transfer amount of the experiment must be positive")
+	}
+	return nil
+}
+
+// Enabled tells if the experiment is configured.
+func (e Experiment) Enabled() bool {
+	return e.Name != ""
+}
+
+// Variant returns the group the client is assigned to. The identity is hashed together with the name
+// of the experiment, so the same client always lands in the same group of the experiment, while groups of different
+// experiments are independent. Requests without identity are always in the control group.
+func (e Experiment) Variant(identity string) string {
+	if !e.Enabled() || identity == "" {
+		return VariantControl
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(e.Name))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(identity))
+	if h.Sum64()%100 < uint64(e.Percent) {
+		return VariantExperiment
+	}
+	return VariantControl
+}
+
+// WithExperiment returns a copy of the app granting the experiment amount to the experiment group.
+func (a App) WithExperiment(experiment Experiment) App {
+	a.experiment = experiment
+	return a
+}
+
+// Experiment returns the configured experiment.
+func (a App) Experiment() Experiment {
+	return a.experiment
+}
+
+// ExperimentVariant returns the group of the requester. Requests authenticated with the API key are identified
+// by the key, other ones by the IP.
+func (a App) ExperimentVariant(requester Requester) string {
+	identity := requester.IP
+	if requester.APIKeyHash != "" {
+		identity = requester.APIKeyHash
+	}
+	return a.experiment.Variant(identity)
+}
+
+// experimentTransferAmount returns the amount granted to the requester by the experiment, if any.
+func (a App) experimentTransferAmount(requester Requester) (chain.Int, bool) {
+	if a.experiment.TransferAmount.IsNil() || a.ExperimentVariant(requester) != VariantExperiment {
+		return chain.Int{}, false
+	}
+	return a.experiment.TransferAmount, true
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+func TestExperimentVariant(t *testing.T) {
+	requireT := require.New(t)
+
+	experiment := Experiment{Name: "stricter-limits", Percent: 30}
+	var assigned int
+	for i := 0; i < 1000; i++ {
+		identity := fmt.Sprintf("10.0.%d.%d", i/256, i%256)
+		variant := experiment.Variant(identity)
+		// assignment is consistent
+		requireT.Equal(variant, experiment.Variant(identity))
+		if variant == VariantExperiment {
+			assigned++
+		}
+	}
+	requireT.InDelta(300, assigned, 60)
+
+	requireT.Equal(VariantControl, experiment.Variant(""))
+	requireT.Equal(VariantControl, Experiment{Name: "none", Percent: 0}.Variant("10.0.0.1"))
+	requireT.Equal(VariantExperiment, Experiment{Name: "all", Percent: 100}.Variant("10.0.0.1"))
+	requireT.Equal(VariantControl, Experiment{Percent: 100}.Variant("10.0.0.1"))
+
+	requireT.Error(Experiment{Percent: 10}.Validate())
+	requireT.Error(Experiment{Name: "x", Percent: 101}.Validate())
+	requireT.Error(Experiment{Name: "x", Percent: 10, TransferAmount: chain.NewInt(0)}.Validate())
+	requireT.NoError(Experiment{Name: "x", Percent: 10, TransferAmount: chain.NewInt(1)}.Validate())
+}
+
+func TestExperimentTransferAmount(t *testing.T) {
+	requireT := require.New(t)
+
+	a := New(nil, nil, nil, nil, nil, chain.Network{}, chain.NewCoin("ucore", chain.NewInt(1000))).
+		WithExperiment(Experiment{Name: "smaller-grants", Percent: 100, TransferAmount: chain.NewInt(400)})
+
+	requireT.Equal(VariantExperiment, a.ExperimentVariant(Requester{IP: "1.2.3.4"}))
+	requireT.Equal("400ucore", a.grantAmount(Requester{IP: "1.2.3.4"}).String())
+	// requests without identity belong to the control group
+	requireT.Equal("1000ucore", a.grantAmount(Requester{}).String())
+
+	a = a.WithExperiment(Experiment{Name: "smaller-grants", Percent: 0, TransferAmount: chain.NewInt(400)})
+	requireT.Equal(VariantControl, a.ExperimentVariant(Requester{IP: "1.2.3.4"}))
+	requireT.Equal("1000ucore", a.grantAmount(Requester{IP: "1.2.3.4"}).String())
+}
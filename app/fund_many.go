@@ -0,0 +1,41 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// FundingResult is the result of funding a single address of the batch.
+type FundingResult struct {
+	Index   int
+	Address string
+	TxHash  string
+	Err     error
+}
+
+// GiveFundsMany funds all the addresses concurrently. Result of each funding is sent to the returned channel
+// as soon as it completes, so the results are not ordered. The channel is closed once all the fundings complete.
+func (a App) GiveFundsMany(ctx context.Context, requester Requester, addresses []string) <-chan FundingResult {
+	results := make(chan FundingResult, len(addresses))
+
+	var wg sync.WaitGroup
+	wg.Add(len(addresses))
+	for i, address := range addresses {
+		i, address := i, address
+		// each item gets its own request ID, so history records of the batch don't collide
+		itemRequester := requester
+		itemRequester.RequestID = fmt.Sprintf("%s-%d", requester.RequestID, i)
+		go func() {
+			defer wg.Done()
+			txHash, err := a.GiveFunds(ctx, itemRequester, address)
+			results <- FundingResult{Index: i, Address: address, TxHash: txHash, Err: err}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
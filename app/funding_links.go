@@ -0,0 +1,261 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+const (
+	// MaxFundingLinksPerRequest limits the number of funding links minted at once.
+	MaxFundingLinksPerRequest = 1000
+
+	minFundingLinkKeyLength = 16
+)
+
+// FundingLinkConfig configures the funding links.
+type FundingLinkConfig struct {
+	// SigningKey of at least 16 characters signs the links.
+	SigningKey string
+	// LinkURL is the URL the token is appended to as the `token` query parameter, the page of the faucet UI
+	// asking for the address and redeeming the link.
+	LinkURL string
+}
+
+// FundingLinkSpec describes the funding links to mint.
+type FundingLinkSpec struct {
+	// Amount is sent to the address the link is redeemed for.
+	Amount chain.Int
+	// Denom of the amount, the denom of the chain if empty.
+	Denom string
+	// ExpiresAt is the time the links can't be redeemed anymore.
+	ExpiresAt time.Time
+}
+
+// FundingLink is the single-use URL funding the address submitted by its holder. Everything needed to redeem it
+// is embedded into the token and signed, so the links aren't stored until they are redeemed.
+type FundingLink struct {
+	ID        string
+	URL       string
+	Token     string
+	Amount    chain.Coin
+	ExpiresAt time.Time
+}
+
+// FundingLinkRedemption records the redeemed link until it expires, so it is redeemed once.
+type FundingLinkRedemption struct {
+	ID         string    `json:"id"`
+	Address    string    `json:"address"`
+	RedeemedAt time.Time `json:"redeemedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// FundingLinkResult is the result of redeeming the link.
+type FundingLinkResult struct {
+	Address string
+	Amount  chain.Coin
+	TxHash  string
+}
+
+// FundingLinkStore persists the redeemed links.
+type FundingLinkStore interface {
+	// RedeemFundingLink atomically records the redemption, or returns ErrFundingLinkUsed if the link is redeemed
+	// already.
+	RedeemFundingLink(ctx context.Context, redemption FundingLinkRedemption) error
+	// ReleaseFundingLink forgets the redemption which failed, so the link may be redeemed again.
+	ReleaseFundingLink(ctx context.Context, id string) error
+	// DeleteExpiredFundingLinks deletes the redemptions of the links expired at the time and returns the number
+	// of deleted ones.
+	DeleteExpiredFundingLinks(ctx context.Context, now time.Time) (int, error)
+}
+
+type fundingLinkClaims struct {
+	ID        string `json:"id"`
+	Amount    string `json:"amount"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type fundingLinks struct {
+	cfg   FundingLinkConfig
+	store FundingLinkStore
+}
+
+// WithFundingLinks returns a copy of the app redeeming the funding links minted by the admin, e.g. for
+// the workshop hosts to distribute among the attendees. Each link funds the address submitted by its holder once.
+func (a App) WithFundingLinks(cfg FundingLinkConfig, store FundingLinkStore) (App, error) {
+	if len(cfg.SigningKey) < minFundingLinkKeyLength {
+		return App{}, errors.Errorf("key of at least %d characters is required to sign the funding links",
+			minFundingLinkKeyLength)
+	}
+	linkURL, err := url.Parse(cfg.LinkURL)
+	if err != nil || (linkURL.Scheme != "http" && linkURL.Scheme != "https") || linkURL.Host == "" {
+		return App{}, errors.Errorf("link URL must be absolute http(s) URL, got %q", cfg.LinkURL)
+	}
+	if store == nil {
+		return App{}, errors.New("store is required to redeem funding links")
+	}
+	a.fundingLinks = &fundingLinks{cfg: cfg, store: store}
+	return a, nil
+}
+
+// FundingLinksEnabled tells if the funding links are minted and redeemed.
+func (a App) FundingLinksEnabled() bool {
+	return a.fundingLinks != nil
+}
+
+// CreateFundingLinks mints the count of funding links of the spec.
+func (a App) CreateFundingLinks(ctx context.Context, spec FundingLinkSpec, count int) ([]FundingLink, error) {
+	if a.fundingLinks == nil {
+		return nil, errors.Wrap(ErrInvalidFundingLink, "funding links are not enabled")
+	}
+	if count < 1 || count > MaxFundingLinksPerRequest {
+		return nil, errors.Wrapf(ErrInvalidFundingLink, "count must be between 1 and %d", MaxFundingLinksPerRequest)
+	}
+	if spec.Denom == "" {
+		spec.Denom = a.network.Denom()
+	}
+	if spec.Amount.IsNil() || !spec.Amount.IsPositive() {
+		return nil, errors.Wrap(ErrInvalidFundingLink, "amount must be positive")
+	}
+	// the coin is built directly, because NewCoin panics on invalid denoms
+	amount := chain.Coin{Denom: spec.Denom, Amount: spec.Amount}
+	if err := amount.Validate(); err != nil {
+		return nil, errors.Wrapf(ErrInvalidFundingLink, "invalid denom %q", spec.Denom)
+	}
+	now := a.clock.Now().UTC()
+	expiresAt := spec.ExpiresAt.UTC().Truncate(time.Second)
+	if !expiresAt.After(now) {
+		return nil, errors.Wrap(ErrInvalidFundingLink, "expiry time must be in the future")
+	}
+
+	links := make([]FundingLink, 0, count)
+	for i := 0; i < count; i++ {
+		rawID := make([]byte, 16)
+		if _, err := rand.Read(rawID); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		claims := fundingLinkClaims{
+			ID:        hex.EncodeToString(rawID),
+			Amount:    amount.String(),
+			ExpiresAt: expiresAt.Unix(),
+		}
+		token, err := a.fundingLinks.token(claims)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, FundingLink{
+			ID:        claims.ID,
+			URL:       a.fundingLinks.url(token),
+			Token:     token,
+			Amount:    amount,
+			ExpiresAt: expiresAt,
+		})
+	}
+	return links, nil
+}
+
+// RedeemFundingLink sends the amount of the link to the address. The link is released if the transfer fails,
+// so the holder may try again.
+func (a App) RedeemFundingLink(
+	ctx context.Context,
+	requester Requester,
+	token, address string,
+) (FundingLinkResult, error) {
+	if a.fundingLinks == nil {
+		return FundingLinkResult{}, errors.Wrap(ErrFundingLinkRejected, "funding links are not enabled")
+	}
+	requester, err := a.verifyToSAccepted(requester)
+	if err != nil {
+		return FundingLinkResult{}, err
+	}
+	claims, err := a.fundingLinks.parse(token)
+	if err != nil {
+		return FundingLinkResult{}, err
+	}
+	amount, err := chain.ParseCoinNormalized(claims.Amount)
+	if err != nil {
+		return FundingLinkResult{}, errors.Wrap(ErrFundingLinkRejected, "amount of the link is invalid")
+	}
+	now := a.clock.Now().UTC()
+	expiresAt := time.Unix(claims.ExpiresAt, 0).UTC()
+	if !now.Before(expiresAt) {
+		return FundingLinkResult{}, errors.Wrapf(ErrFundingLinkExpired, "link %s", claims.ID)
+	}
+	sdkAddr, err := a.validateAddress(address)
+	if err != nil {
+		return FundingLinkResult{}, err
+	}
+
+	if err := a.fundingLinks.store.RedeemFundingLink(ctx, FundingLinkRedemption{
+		ID:         claims.ID,
+		Address:    address,
+		RedeemedAt: now,
+		ExpiresAt:  expiresAt,
+	}); err != nil {
+		return FundingLinkResult{}, err
+	}
+	txHash, err := a.send(ctx, requester, sdkAddr, amount)
+	if err != nil {
+		if err := a.fundingLinks.store.ReleaseFundingLink(ctx, claims.ID); err != nil {
+			logger.Get(ctx).Error("Releasing funding link failed", zap.Error(err))
+		}
+		return FundingLinkResult{}, err
+	}
+	return FundingLinkResult{Address: address, Amount: amount, TxHash: txHash}, nil
+}
+
+func (l *fundingLinks) token(claims fundingLinkClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + l.signature(encoded), nil
+}
+
+// parse checks the signature of the token and returns its claims.
+func (l *fundingLinks) parse(token string) (fundingLinkClaims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return fundingLinkClaims{}, errors.Wrap(ErrFundingLinkRejected, "malformed link")
+	}
+	if !hmac.Equal([]byte(signature), []byte(l.signature(encoded))) {
+		return fundingLinkClaims{}, errors.Wrap(ErrFundingLinkRejected, "link is not issued by the faucet")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return fundingLinkClaims{}, errors.Wrap(ErrFundingLinkRejected, "malformed link")
+	}
+	var claims fundingLinkClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ID == "" {
+		return fundingLinkClaims{}, errors.Wrap(ErrFundingLinkRejected, "malformed link")
+	}
+	return claims, nil
+}
+
+func (l *fundingLinks) signature(encoded string) string {
+	mac := hmac.New(sha256.New, []byte(l.cfg.SigningKey))
+	mac.Write([]byte("funding-link\x00" + encoded))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (l *fundingLinks) url(token string) string {
+	separator := "?"
+	if strings.Contains(l.cfg.LinkURL, "?") {
+		separator = "&"
+	}
+	return l.cfg.LinkURL + separator + "token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockFundingLinkStore struct {
+	redemptions map[string]FundingLinkRedemption
+}
+
+func (m *mockFundingLinkStore) RedeemFundingLink(ctx context.Context, redemption FundingLinkRedemption) error {
+	if _, ok := m.redemptions[redemption.ID]; ok {
+		return errors.Wrapf(ErrFundingLinkUsed, "link %s", redemption.ID)
+	}
+	m.redemptions[redemption.ID] = redemption
+	return nil
+}
+
+func (m *mockFundingLinkStore) ReleaseFundingLink(ctx context.Context, id string) error {
+	delete(m.redemptions, id)
+	return nil
+}
+
+func (m *mockFundingLinkStore) DeleteExpiredFundingLinks(ctx context.Context, now time.Time) (int, error) {
+	var deleted int
+	for id, r := range m.redemptions {
+		if !now.Before(r.ExpiresAt) {
+			delete(m.redemptions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func TestFundingLinks(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+	const other = "devcore1kdxhx3vv6n0g4ffgdqp8y7e44q7rzjvvlvlg3q"
+
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	batcher := &mockBatcher{txHash: "tx1"}
+	a := New(batcher, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{}, network,
+		chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clk)
+	requireT.False(a.FundingLinksEnabled())
+
+	store := &mockFundingLinkStore{redemptions: map[string]FundingLinkRedemption{}}
+	cfg := FundingLinkConfig{SigningKey: "0123456789abcdef", LinkURL: "https://faucet.example.com/redeem"}
+	_, err = a.WithFundingLinks(FundingLinkConfig{SigningKey: "short", LinkURL: cfg.LinkURL}, store)
+	requireT.Error(err)
+	_, err = a.WithFundingLinks(FundingLinkConfig{SigningKey: cfg.SigningKey, LinkURL: "/redeem"}, store)
+	requireT.Error(err)
+	_, err = a.WithFundingLinks(cfg, nil)
+	requireT.Error(err)
+	a, err = a.WithFundingLinks(cfg, store)
+	requireT.NoError(err)
+	requireT.True(a.FundingLinksEnabled())
+
+	expiresAt := clk.Now().Add(time.Hour)
+	spec := FundingLinkSpec{Amount: chain.NewInt(500), ExpiresAt: expiresAt}
+	for _, count := range []int{0, MaxFundingLinksPerRequest + 1} {
+		_, err = a.CreateFundingLinks(ctx, spec, count)
+		requireT.ErrorIs(err, ErrInvalidFundingLink)
+	}
+	for _, invalid := range []FundingLinkSpec{
+		{Amount: chain.NewInt(0), ExpiresAt: expiresAt},
+		{Amount: chain.NewInt(500), Denom: "1!", ExpiresAt: expiresAt},
+		{Amount: chain.NewInt(500), ExpiresAt: clk.Now()},
+	} {
+		_, err = a.CreateFundingLinks(ctx, invalid, 1)
+		requireT.ErrorIs(err, ErrInvalidFundingLink)
+	}
+
+	links, err := a.CreateFundingLinks(ctx, spec, 2)
+	requireT.NoError(err)
+	requireT.Len(links, 2)
+	requireT.NotEqual(links[0].ID, links[1].ID)
+	requireT.Equal("500udevcore", links[0].Amount.String())
+	requireT.Equal(expiresAt, links[0].ExpiresAt)
+	requireT.True(strings.HasPrefix(links[0].URL, cfg.LinkURL+"?token="))
+
+	// links are signed by the faucet
+	_, err = a.RedeemFundingLink(ctx, Requester{}, "garbage", address)
+	requireT.ErrorIs(err, ErrFundingLinkRejected)
+	tampered := links[0].Token[:len(links[0].Token)-1] + "0"
+	if tampered == links[0].Token {
+		tampered = links[0].Token[:len(links[0].Token)-1] + "1"
+	}
+	_, err = a.RedeemFundingLink(ctx, Requester{}, tampered, address)
+	requireT.ErrorIs(err, ErrFundingLinkRejected)
+	forged, err := (&fundingLinks{cfg: FundingLinkConfig{SigningKey: "fedcba9876543210"}}).token(fundingLinkClaims{
+		ID: "forged", Amount: "1000000udevcore", ExpiresAt: expiresAt.Unix(),
+	})
+	requireT.NoError(err)
+	_, err = a.RedeemFundingLink(ctx, Requester{}, forged, address)
+	requireT.ErrorIs(err, ErrFundingLinkRejected)
+
+	_, err = a.RedeemFundingLink(ctx, Requester{}, links[0].Token, "devcore1invalid")
+	requireT.ErrorIs(err, ErrInvalidAddressFormat)
+
+	result, err := a.RedeemFundingLink(ctx, Requester{}, links[0].Token, address)
+	requireT.NoError(err)
+	requireT.Equal(FundingLinkResult{Address: address, Amount: links[0].Amount, TxHash: "tx1"}, result)
+	// each link is redeemed once
+	_, err = a.RedeemFundingLink(ctx, Requester{}, links[0].Token, other)
+	requireT.ErrorIs(err, ErrFundingLinkUsed)
+
+	// the link is released if the transfer fails
+	batcher.err = errors.New("node is down")
+	_, err = a.RedeemFundingLink(ctx, Requester{}, links[1].Token, other)
+	requireT.Error(err)
+	batcher.err = nil
+	_, err = a.RedeemFundingLink(ctx, Requester{}, links[1].Token, other)
+	requireT.NoError(err)
+
+	// the redemptions are collected once the links expire
+	links, err = a.CreateFundingLinks(ctx, spec, 1)
+	requireT.NoError(err)
+	clk.Advance(time.Hour)
+	_, err = a.RedeemFundingLink(ctx, Requester{}, links[0].Token, address)
+	requireT.ErrorIs(err, ErrFundingLinkExpired)
+	collected, err := a.CollectGarbage(ctx)
+	requireT.NoError(err)
+	requireT.Equal(2, collected[ExpiringFundingLink])
+}
@@ -0,0 +1,192 @@
+package app
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+)
+
+// Kinds of the expiring artifacts.
+const (
+	ExpiringClaimCode       = "claim_code"
+	ExpiringChallenge       = "challenge"
+	ExpiringAddressCooldown = "address_cooldown"
+	ExpiringBypassToken     = "bypass_token"
+	ExpiringEmailLink       = "email_link"
+	ExpiringPhoneCode       = "phone_code"
+	ExpiringFundingLink     = "funding_link"
+)
+
+// ExpiringItem is the artifact collected once it expires.
+type ExpiringItem struct {
+	Kind      string
+	ID        string
+	ExpiresAt time.Time
+}
+
+// ExpiringItems returns the items expiring within the duration, the soonest first. Pending challenges are not
+// included, they live for minutes and can't be extended.
+func (a App) ExpiringItems(ctx context.Context, within time.Duration) ([]ExpiringItem, error) {
+	deadline := a.clock.Now().UTC().Add(within)
+	items := []ExpiringItem{}
+	if a.claimCodes != nil {
+		codes, err := a.claimCodes.ClaimCodes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range codes {
+			if !c.ExpiresAt.IsZero() && !c.ExpiresAt.After(deadline) {
+				items = append(items, ExpiringItem{Kind: ExpiringClaimCode, ID: c.Code, ExpiresAt: c.ExpiresAt})
+			}
+		}
+	}
+	if a.bypassTokens != nil {
+		tokens, err := a.bypassTokens.BypassTokens(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tokens {
+			if !t.ExpiresAt.IsZero() && !t.ExpiresAt.After(deadline) {
+				items = append(items, ExpiringItem{Kind: ExpiringBypassToken, ID: t.ID, ExpiresAt: t.ExpiresAt})
+			}
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ExpiresAt.Before(items[j].ExpiresAt)
+	})
+	return items, nil
+}
+
+// ExtendExpiry moves the expiry time of the item, so it isn't collected before the operator is done with it.
+func (a App) ExtendExpiry(ctx context.Context, kind, id string, expiresAt time.Time) (ExpiringItem, error) {
+	if !expiresAt.After(a.clock.Now()) {
+		return ExpiringItem{}, errors.Wrap(ErrInvalidExpiry, "expiry time must be in the future")
+	}
+	switch kind {
+	case ExpiringClaimCode:
+		if a.claimCodes == nil {
+			return ExpiringItem{}, errors.Wrapf(ErrClaimCodeNotFound, "code: %s", id)
+		}
+		code, err := a.claimCodes.SetClaimCodeExpiry(ctx, normalizeClaimCode(id), expiresAt.UTC())
+		if err != nil {
+			return ExpiringItem{}, err
+		}
+		return ExpiringItem{Kind: ExpiringClaimCode, ID: code.Code, ExpiresAt: code.ExpiresAt}, nil
+	case ExpiringBypassToken:
+		if a.bypassTokens == nil {
+			return ExpiringItem{}, errors.Wrapf(ErrBypassTokenNotFound, "token: %s", id)
+		}
+		token, err := a.bypassTokens.SetBypassTokenExpiry(ctx, id, expiresAt.UTC())
+		if err != nil {
+			return ExpiringItem{}, err
+		}
+		return ExpiringItem{Kind: ExpiringBypassToken, ID: token.ID, ExpiresAt: token.ExpiresAt}, nil
+	default:
+		return ExpiringItem{}, errors.Wrapf(ErrInvalidExpiry, "items of kind %q can't be extended", kind)
+	}
+}
+
+// CollectGarbage deletes the artifacts expired by now and returns the number of collected ones by kind.
+func (a App) CollectGarbage(ctx context.Context) (map[string]int, error) {
+	now := a.clock.Now().UTC()
+	collected := map[string]int{}
+	if a.claimCodes != nil {
+		n, err := a.claimCodes.DeleteExpiredClaimCodes(ctx, now)
+		if err != nil {
+			return collected, err
+		}
+		collected[ExpiringClaimCode] = n
+	}
+	if a.bypassTokens != nil {
+		n, err := a.bypassTokens.DeleteExpiredBypassTokens(ctx, now)
+		if err != nil {
+			return collected, err
+		}
+		collected[ExpiringBypassToken] = n
+	}
+	if a.email != nil {
+		n, err := a.email.store.DeleteExpiredEmailVerifications(ctx, now)
+		if err != nil {
+			return collected, err
+		}
+		collected[ExpiringEmailLink] = n
+	}
+	if a.phone != nil {
+		n, err := a.phone.store.DeleteExpiredPhoneVerifications(ctx, now)
+		if err != nil {
+			return collected, err
+		}
+		collected[ExpiringPhoneCode] = n
+	}
+	if a.fundingLinks != nil {
+		n, err := a.fundingLinks.store.DeleteExpiredFundingLinks(ctx, now)
+		if err != nil {
+			return collected, err
+		}
+		collected[ExpiringFundingLink] = n
+	}
+	if a.cooldown.store != nil && a.cooldown.period > 0 {
+		n, err := a.cooldown.store.DeleteAddressCooldownsBefore(ctx, now.Add(-a.cooldown.period))
+		if err != nil {
+			return collected, err
+		}
+		collected[ExpiringAddressCooldown] = n
+	}
+	if a.challenges != nil {
+		a.challenges.mu.Lock()
+		collected[ExpiringChallenge] = a.challenges.pruneLocked(now)
+		a.challenges.mu.Unlock()
+	}
+	return collected, nil
+}
+
+// GarbageCollector collects the expired artifacts, so the store doesn't grow with the items nobody can use anymore.
+// It is run periodically by the scheduler.
+type GarbageCollector struct {
+	app App
+
+	collected *prometheus.CounterVec
+	lastRun   prometheus.Gauge
+}
+
+// NewGarbageCollector returns the garbage collector of the app.
+func NewGarbageCollector(app App) *GarbageCollector {
+	return &GarbageCollector{
+		app: app,
+		collected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "faucet_gc_collected_total",
+			Help: "Number of expired artifacts collected, by kind",
+		}, []string{"kind"}),
+		lastRun: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "faucet_gc_last_success_timestamp_seconds",
+			Help: "Unix time of the last successful garbage collection",
+		}),
+	}
+}
+
+// Collectors returns the metrics of the garbage collector.
+func (gc *GarbageCollector) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{gc.collected, gc.lastRun}
+}
+
+// Collect collects the expired artifacts once.
+func (gc *GarbageCollector) Collect(ctx context.Context) error {
+	collected, err := gc.app.CollectGarbage(ctx)
+	for kind, n := range collected {
+		gc.collected.WithLabelValues(kind).Add(float64(n))
+		if n > 0 {
+			logger.Get(ctx).Info("Expired artifacts collected", zap.String("kind", kind), zap.Int("count", n))
+		}
+	}
+	if err != nil {
+		return err
+	}
+	gc.lastRun.SetToCurrentTime()
+	return nil
+}
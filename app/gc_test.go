@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+func (m *mockClaimCodeStore) ClaimCodes(ctx context.Context) ([]ClaimCode, error) {
+	codes := make([]ClaimCode, 0, len(m.codes))
+	for _, c := range m.codes {
+		codes = append(codes, c)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i].Code < codes[j].Code })
+	return codes, nil
+}
+
+func (m *mockClaimCodeStore) SetClaimCodeExpiry(ctx context.Context, code string, expiresAt time.Time) (ClaimCode, error) {
+	c, err := m.ClaimCode(ctx, code)
+	if err != nil {
+		return ClaimCode{}, err
+	}
+	c.ExpiresAt = expiresAt
+	m.codes[code] = c
+	return c, nil
+}
+
+func (m *mockClaimCodeStore) DeleteExpiredClaimCodes(ctx context.Context, now time.Time) (int, error) {
+	var deleted int
+	for code, c := range m.codes {
+		if c.expired(now) {
+			delete(m.codes, code)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func TestGarbageCollection(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	amount := chain.NewCoins(chain.NewCoin("ucore", chain.NewInt(10)))
+	store := &mockClaimCodeStore{codes: map[string]ClaimCode{
+		"AAAA": {Code: "AAAA", Amount: amount, MaxUses: 1, ExpiresAt: now.Add(2 * time.Hour)},
+		"BBBB": {Code: "BBBB", Amount: amount, MaxUses: 1, ExpiresAt: now.Add(time.Hour)},
+		"CCCC": {Code: "CCCC", Amount: amount, MaxUses: 1, ExpiresAt: now.Add(48 * time.Hour)},
+		"DDDD": {Code: "DDDD", Amount: amount, MaxUses: 1},
+	}}
+	clk := clock.NewManual(now)
+	a := New(nil, nil, nil, nil, nil, chain.Network{}, chain.Coin{}).
+		WithClock(clk).
+		WithClaimCodes(store).
+		WithOnChainChallenge(nil, chain.Coin{})
+	a.challenges.pending["expired"] = Challenge{ID: "expired", ExpiresAt: now.Add(time.Minute)}
+
+	items, err := a.ExpiringItems(ctx, 24*time.Hour)
+	requireT.NoError(err)
+	requireT.Equal([]ExpiringItem{
+		{Kind: ExpiringClaimCode, ID: "BBBB", ExpiresAt: now.Add(time.Hour)},
+		{Kind: ExpiringClaimCode, ID: "AAAA", ExpiresAt: now.Add(2 * time.Hour)},
+	}, items)
+
+	item, err := a.ExtendExpiry(ctx, ExpiringClaimCode, "aaaa", now.Add(72*time.Hour))
+	requireT.NoError(err)
+	requireT.Equal(ExpiringItem{Kind: ExpiringClaimCode, ID: "AAAA", ExpiresAt: now.Add(72 * time.Hour)}, item)
+	_, err = a.ExtendExpiry(ctx, ExpiringClaimCode, "BBBB", now)
+	requireT.ErrorIs(err, ErrInvalidExpiry)
+	_, err = a.ExtendExpiry(ctx, ExpiringChallenge, "expired", now.Add(time.Hour))
+	requireT.ErrorIs(err, ErrInvalidExpiry)
+
+	clk.Advance(time.Hour)
+	_, err = a.Claim(ctx, Requester{}, "BBBB", "devcore1any")
+	requireT.ErrorIs(err, ErrClaimCodeExpired)
+
+	collected, err := a.CollectGarbage(ctx)
+	requireT.NoError(err)
+	requireT.Equal(map[string]int{ExpiringClaimCode: 1, ExpiringChallenge: 1}, collected)
+	requireT.Len(store.codes, 3)
+	requireT.Empty(a.challenges.pending)
+}
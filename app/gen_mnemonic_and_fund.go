@@ -2,36 +2,91 @@ package app
 
 import (
 	"context"
+	"time"
 
-	"github.com/cosmos/cosmos-sdk/crypto/hd"
-	"github.com/cosmos/cosmos-sdk/crypto/keyring"
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/secret"
 )
 
 // GenMnemonicAndFundResult is the response returned from GenMnemonicAndFund.
 type GenMnemonicAndFundResult struct {
-	TxHash   string
-	Mnemonic string
+	TxHash string
+	// Mnemonic is redacted when logged or marshaled, it is revealed only in the response.
+	Mnemonic secret.String
 	Address  string
+	// Label is the label the account is generated under, empty if it is not named.
+	Label     string
+	CreatedAt time.Time
+	// ExampleTx is the signed transaction sending 1 unit from the funded account to itself, set only
+	// if the example transaction signer is configured.
+	ExampleTx []byte
 }
 
-// GenMnemonicAndFund generates a private key and funds it.
-func (a App) GenMnemonicAndFund(ctx context.Context) (GenMnemonicAndFundResult, error) {
-	kr := keyring.NewInMemory()
-	info, mnemonic, err := kr.NewMnemonic("", keyring.English, sdk.GetConfig().GetFullBIP44Path(), "", hd.Secp256k1)
+// ExampleTxSigner signs the example transaction returned together with the generated mnemonic.
+type ExampleTxSigner interface {
+	SignSelfSend(ctx context.Context, mnemonic secret.String, amount chain.Coin) ([]byte, error)
+}
+
+// WithExampleTxSigner returns a copy of the app returning signed example transaction from GenMnemonicAndFund.
+func (a App) WithExampleTxSigner(signer ExampleTxSigner) App {
+	a.exampleTxSigner = signer
+	return a
+}
+
+// GenMnemonicAndFund generates a private key and funds it. If the label is set, the account generated under
+// the label by the requester before is funded instead of the new one. The request is subject to the same
+// verifiers and abuse score as GiveFunds, so it can't be used to get around them.
+func (a App) GenMnemonicAndFund(ctx context.Context, requester Requester, label string) (GenMnemonicAndFundResult, error) {
+	requester, err := a.verifyToSAccepted(requester)
 	if err != nil {
-		return GenMnemonicAndFundResult{}, errors.Wrapf(ErrUnableToTransferToken, "err:%s", err)
+		return GenMnemonicAndFundResult{}, err
+	}
+	if err := a.verify(ctx, requester); err != nil {
+		return GenMnemonicAndFundResult{}, err
+	}
+	if label != "" {
+		result, err := a.genNamedAndFund(ctx, requester, label)
+		if err != nil {
+			return GenMnemonicAndFundResult{}, err
+		}
+		a.signExampleTx(ctx, &result)
+		return result, nil
 	}
-	sdkAddr := info.GetAddress()
-	txHash, err := a.batcher.SendToken(ctx, sdkAddr, a.transferAmount)
+	sdkAddr, mnemonic, err := chain.GenerateMnemonic()
 	if err != nil {
 		return GenMnemonicAndFundResult{}, errors.Wrapf(ErrUnableToTransferToken, "err:%s", err)
 	}
+	if err := a.checkAbuseScore(ctx, requester, sdkAddr); err != nil {
+		return GenMnemonicAndFundResult{}, err
+	}
+	txHash, err := a.send(ctx, requester, sdkAddr, a.grantAmount(requester))
+	if err != nil {
+		return GenMnemonicAndFundResult{}, err
+	}
 
-	return GenMnemonicAndFundResult{
+	result := GenMnemonicAndFundResult{
 		TxHash:   txHash,
 		Mnemonic: mnemonic,
 		Address:  sdkAddr.String(),
-	}, nil
+	}
+	a.signExampleTx(ctx, &result)
+	return result, nil
+}
+
+// signExampleTx sets the example transaction of the funded account, if the signer is configured.
+func (a App) signExampleTx(ctx context.Context, result *GenMnemonicAndFundResult) {
+	if a.exampleTxSigner == nil {
+		return
+	}
+	var err error
+	// the account is funded already, so failure doesn't fail the request
+	result.ExampleTx, err = a.exampleTxSigner.SignSelfSend(ctx, result.Mnemonic, chain.NewCoin(a.transferAmount.Denom, chain.NewInt(1)))
+	if err != nil {
+		logger.Get(ctx).Error("Signing example transaction failed", zap.String("address", result.Address), zap.Error(err))
+	}
 }
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// Requester describes the client asking for funds. Tenant and Session are used for budget accounting only.
+type Requester struct {
+	RequestID   string
+	IP          string
+	Fingerprint string
+	Tenant      string
+	Session     string
+	// APIKeyHash identifies the API key the request is authenticated with, empty if there is none.
+	APIKeyHash string
+	// APIKeyHolder is the holder of the API key the request is authenticated with, empty if there is none.
+	APIKeyHolder string
+	// CallbackURL is notified once the transaction funding the request is confirmed, empty if there is none.
+	CallbackURL string
+	// MinConfirmations is the number of confirmations required to report the request as confirmed, 0 means
+	// the default of the faucet.
+	MinConfirmations int64
+	// BypassTokenID identifies the bypass token the request is authenticated with, such requests are exempt
+	// from the address cooldown. It is empty if there is none.
+	BypassTokenID string
+	// Admin tells if the request is authorized by the admin token.
+	Admin bool
+	// Proofs are the proofs of the client being a human checked by the verifiers, e.g. the solved captcha.
+	Proofs Proofs
+	// IPReputation is the category of the network the IP is flagged as, e.g. tor, empty if it is not flagged.
+	IPReputation string
+	// InvoiceID is the ID of the invoice of the API key client the funding is correlated with, empty if there
+	// is none. Its hash is embedded into the memo of the transaction.
+	InvoiceID string
+	// ToSToken is the token proving the client accepted the terms of service, empty if there is none.
+	ToSToken string
+	// ToSVersion is the version of the terms of service accepted by the client, set once the token is verified.
+	ToSVersion string
+}
+
+// FundingRecord describes a single funding performed by the faucet.
+type FundingRecord struct {
+	RequestID   string     `json:"requestId"`
+	Address     string     `json:"address"`
+	IP          string     `json:"ip"`
+	Fingerprint string     `json:"fingerprint"`
+	Amount      chain.Coin `json:"amount"`
+	// Fee is the share of the funding in the fee of the transaction, it is empty in records stored
+	// before fees were tracked.
+	Fee    chain.Coin `json:"fee"`
+	TxHash string     `json:"txHash"`
+	Time   time.Time  `json:"time"`
+	// ToSVersion is the version of the terms of service accepted by the client, empty if acceptance
+	// was not required.
+	ToSVersion string `json:"tosVersion,omitempty"`
+	// InvoiceID is the ID of the invoice the client correlates the funding with, empty if there is none.
+	InvoiceID string `json:"invoiceId,omitempty"`
+}
+
+// Incident describes a failure which operators should be aware of.
+type Incident struct {
+	RequestID string    `json:"requestId"`
+	Kind      string    `json:"kind"`
+	Message   string    `json:"message"`
+	Time      time.Time `json:"time"`
+}
+
+// Incident kinds.
+const (
+	IncidentKindTransferFailed       = "transfer_failed"
+	IncidentKindTransferAboveMaximum = "transfer_above_maximum"
+)
+
+// HistoryStore persists the history of fundings and incidents.
+type HistoryStore interface {
+	RecordFunding(ctx context.Context, record FundingRecord) error
+	FundingsSince(ctx context.Context, since time.Time) ([]FundingRecord, error)
+	RecordIncident(ctx context.Context, incident Incident) error
+	IncidentsSince(ctx context.Context, since time.Time) ([]Incident, error)
+}
@@ -0,0 +1,326 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+const (
+	// ProofIdentity is the name of the identity verifier and the key of the session token in Requester.Proofs.
+	ProofIdentity = "identity"
+	// IdentityLoginTTL is how long the client has to authenticate at the identity provider once the login starts.
+	IdentityLoginTTL = 10 * time.Minute
+
+	minIdentityKeyLength = 16
+)
+
+// Identity is the account the client authenticated with at the identity provider.
+type Identity struct {
+	// Provider is the name of the identity provider, e.g. github.
+	Provider string `json:"provider"`
+	// Subject is the stable ID of the account at the provider.
+	Subject string `json:"subject"`
+	// Name is the name of the account shown to the operators, e.g. GitHub login, it may change over time.
+	Name string `json:"name"`
+}
+
+// Key identifies the account the quota is tracked for.
+func (i Identity) Key() string {
+	return i.Provider + ":" + i.Subject
+}
+
+// IdentityProvider authenticates the clients by the OAuth flow of the provider, e.g. GitHub, and checks their
+// accounts meet the requirements of the faucet.
+type IdentityProvider interface {
+	// Name identifies the provider in the login and callback URLs.
+	Name() string
+	// LoginURL returns the URL the client is sent to for authentication, the provider sends it back to the callback
+	// with the state.
+	LoginURL(state string) string
+	// Authenticate exchanges the code passed to the callback for the identity of the account. It returns
+	// ErrIdentityRejected if the account doesn't meet the requirements, ErrIdentityFailed if the code is rejected
+	// and ErrIdentityUnavailable if the provider can't be asked.
+	Authenticate(ctx context.Context, code string) (Identity, error)
+}
+
+// IdentityConfig configures the identity verification.
+type IdentityConfig struct {
+	// SigningKey of at least 16 characters signs the session tokens.
+	SigningKey string
+	// SessionTTL is how long the session token is accepted once issued.
+	SessionTTL time.Duration
+	// Quota is the number of requests each account may make in the QuotaPeriod, or in total if the period is zero.
+	// Zero quota means the accounts are not limited.
+	Quota       uint64
+	QuotaPeriod time.Duration
+}
+
+// IdentitySession is the token issued to the client once it authenticates.
+type IdentitySession struct {
+	Token     string
+	Identity  Identity
+	ExpiresAt time.Time
+}
+
+// IdentityUsage is the number of requests the account made in the current window of its quota.
+type IdentityUsage struct {
+	Key         string    `json:"key"`
+	Used        uint64    `json:"used"`
+	WindowStart time.Time `json:"windowStart"`
+}
+
+// Consume returns the usage with one request consumed at the time. The quota is renewed once the period passes
+// since the start of the window.
+func (u IdentityUsage) Consume(now time.Time, quota uint64, period time.Duration) (IdentityUsage, error) {
+	u = u.renew(now, period)
+	if err := u.Check(now, quota, period); err != nil {
+		return u, err
+	}
+	u.Used++
+	return u, nil
+}
+
+// Check returns ErrIdentityQuotaExhausted if no request of the quota is left at the time, without consuming one.
+func (u IdentityUsage) Check(now time.Time, quota uint64, period time.Duration) error {
+	u = u.renew(now, period)
+	if u.Used < quota {
+		return nil
+	}
+	err := errors.Wrapf(ErrIdentityQuotaExhausted, "account %s has already used its quota", u.Key)
+	if period == 0 {
+		return err
+	}
+	return ThrottledError{Cause: err, NextAvailableAt: u.WindowStart.Add(period)}
+}
+
+func (u IdentityUsage) renew(now time.Time, period time.Duration) IdentityUsage {
+	if u.WindowStart.IsZero() || (period > 0 && !now.Before(u.WindowStart.Add(period))) {
+		u.Used = 0
+		u.WindowStart = now
+	}
+	return u
+}
+
+// IdentityStore tracks the requests of the accounts.
+type IdentityStore interface {
+	// UseIdentityQuota atomically consumes one request of the quota of the account identified by the key,
+	// or returns ErrIdentityQuotaExhausted.
+	UseIdentityQuota(
+		ctx context.Context,
+		key string,
+		quota uint64,
+		period time.Duration,
+		now time.Time,
+	) (IdentityUsage, error)
+	// IdentityQuotaUsage returns the usage of the quota of the account identified by the key, the zero usage
+	// if the account made no requests yet.
+	IdentityQuotaUsage(ctx context.Context, key string) (IdentityUsage, error)
+}
+
+// identityVerifier issues the session tokens to the authenticated clients and checks them before funding.
+type identityVerifier struct {
+	cfg       IdentityConfig
+	providers map[string]IdentityProvider
+	states    *signedChallenges
+	store     IdentityStore
+	clock     clock.Clock
+}
+
+type identityClaims struct {
+	Provider  string `json:"p"`
+	Subject   string `json:"s"`
+	Name      string `json:"n"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// WithIdentity returns a copy of the app funding only the clients authenticated by any of the identity providers,
+// e.g. GitHub, so a single person can't farm the funds with many addresses. Each account may make the number
+// of requests given by the quota of the config. The clock of the app must be set before. The login states are
+// signed by the key generated on start, so the logins in progress are invalidated by restart, the session tokens
+// are signed by the key of the config and survive it. Requests authenticated by the admin token, API key
+// or bypass token are exempt, because they are sent by automation.
+func (a App) WithIdentity(cfg IdentityConfig, store IdentityStore, providers ...IdentityProvider) (App, error) {
+	if len(providers) == 0 {
+		return App{}, errors.New("at least one identity provider is required")
+	}
+	if len(cfg.SigningKey) < minIdentityKeyLength {
+		return App{}, errors.Errorf("key of at least %d characters is required to sign the identity sessions",
+			minIdentityKeyLength)
+	}
+	if cfg.SessionTTL <= 0 {
+		return App{}, errors.New("lifetime of the identity session must be positive")
+	}
+	if cfg.QuotaPeriod < 0 {
+		return App{}, errors.New("period of the account quota must not be negative")
+	}
+	if cfg.Quota > 0 && store == nil {
+		return App{}, errors.New("store is required to track the account quotas")
+	}
+	states, err := newSignedChallenges(IdentityLoginTTL, a.clock, ErrIdentityFailed)
+	if err != nil {
+		return App{}, err
+	}
+	v := &identityVerifier{
+		cfg:       cfg,
+		providers: map[string]IdentityProvider{},
+		states:    states,
+		store:     store,
+		clock:     a.clock,
+	}
+	for _, p := range providers {
+		if _, exists := v.providers[p.Name()]; exists {
+			return App{}, errors.Errorf("identity provider %s is configured twice", p.Name())
+		}
+		v.providers[p.Name()] = p
+	}
+	a.identity = v
+	return a.WithVerifiers(v), nil
+}
+
+// IdentityProviders returns the names of the identity providers the clients may authenticate with, sorted.
+func (a App) IdentityProviders() []string {
+	if a.identity == nil {
+		return nil
+	}
+	names := make([]string, 0, len(a.identity.providers))
+	for name := range a.identity.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IdentityLoginURL starts the login with the provider and returns the URL the client is sent to.
+func (a App) IdentityLoginURL(provider string) (string, error) {
+	p, err := a.identityProvider(provider)
+	if err != nil {
+		return "", err
+	}
+	state, _, err := a.identity.states.issue()
+	if err != nil {
+		return "", err
+	}
+	return p.LoginURL(state), nil
+}
+
+// CompleteIdentityLogin finishes the login with the provider once the client is sent back with the code
+// and the state, and issues the session token proving the identity of the client.
+func (a App) CompleteIdentityLogin(ctx context.Context, provider, code, state string) (IdentitySession, error) {
+	p, err := a.identityProvider(provider)
+	if err != nil {
+		return IdentitySession{}, err
+	}
+	if code == "" {
+		return IdentitySession{}, errors.Wrap(ErrIdentityFailed, "authorization code is required")
+	}
+	expiresAt, err := a.identity.states.verify(state)
+	if err != nil {
+		return IdentitySession{}, err
+	}
+	if err := a.identity.states.spend(state, expiresAt); err != nil {
+		return IdentitySession{}, err
+	}
+	identity, err := p.Authenticate(ctx, code)
+	if err != nil {
+		return IdentitySession{}, err
+	}
+	identity.Provider = p.Name()
+	return a.identity.issue(identity)
+}
+
+func (a App) identityProvider(name string) (IdentityProvider, error) {
+	if a.identity == nil {
+		return nil, errors.Wrap(ErrIdentityProviderNotFound, "identity verification is not required")
+	}
+	p, ok := a.identity.providers[name]
+	if !ok {
+		return nil, errors.Wrapf(ErrIdentityProviderNotFound, "provider: %s", name)
+	}
+	return p, nil
+}
+
+func (v *identityVerifier) issue(identity Identity) (IdentitySession, error) {
+	expiresAt := v.clock.Now().Add(v.cfg.SessionTTL).UTC().Truncate(time.Second)
+	payload, err := json.Marshal(identityClaims{
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Name:      identity.Name,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return IdentitySession{}, errors.WithStack(err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return IdentitySession{
+		Token:     encoded + "." + base64.RawURLEncoding.EncodeToString(v.signature(encoded)),
+		Identity:  identity,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (v *identityVerifier) signature(encoded string) []byte {
+	mac := hmac.New(sha256.New, []byte(v.cfg.SigningKey))
+	mac.Write([]byte("identity\x00" + encoded))
+	return mac.Sum(nil)
+}
+
+func (v *identityVerifier) Name() string {
+	return ProofIdentity
+}
+
+// Verify checks the session token and consumes one request of the quota of its account.
+func (v *identityVerifier) Verify(ctx context.Context, token string, _ Requester) error {
+	if token == "" {
+		providers := make([]string, 0, len(v.providers))
+		for name := range v.providers {
+			providers = append(providers, name)
+		}
+		sort.Strings(providers)
+		return errors.Wrapf(ErrIdentityFailed, "authentication with %s is required", strings.Join(providers, " or "))
+	}
+	identity, err := v.verify(token)
+	if err != nil {
+		return err
+	}
+	if v.cfg.Quota == 0 {
+		return nil
+	}
+	_, err = v.store.UseIdentityQuota(ctx, identity.Key(), v.cfg.Quota, v.cfg.QuotaPeriod, v.clock.Now().UTC())
+	return err
+}
+
+func (v *identityVerifier) verify(token string) (Identity, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return Identity{}, errors.Wrap(ErrIdentityFailed, "malformed session token")
+	}
+	rawSignature, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(rawSignature, v.signature(encoded)) {
+		return Identity{}, errors.Wrap(ErrIdentityFailed, "invalid signature of the session token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Identity{}, errors.Wrap(ErrIdentityFailed, "malformed session token")
+	}
+	var claims identityClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return Identity{}, errors.Wrap(ErrIdentityFailed, "malformed session token")
+	}
+	if !v.clock.Now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return Identity{}, errors.Wrap(ErrIdentityFailed, "session token is expired")
+	}
+	if _, ok := v.providers[claims.Provider]; !ok {
+		return Identity{}, errors.Wrapf(ErrIdentityFailed, "provider %s is not accepted anymore", claims.Provider)
+	}
+	return Identity{Provider: claims.Provider, Subject: claims.Subject, Name: claims.Name}, nil
+}
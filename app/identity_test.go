@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockIdentityProvider struct {
+	accounts map[string]Identity
+}
+
+func (m mockIdentityProvider) Name() string {
+	return "github"
+}
+
+func (m mockIdentityProvider) LoginURL(state string) string {
+	return "https://github.example.com/authorize?state=" + url.QueryEscape(state)
+}
+
+func (m mockIdentityProvider) Authenticate(ctx context.Context, code string) (Identity, error) {
+	identity, ok := m.accounts[code]
+	if !ok {
+		return Identity{}, errors.Wrap(ErrIdentityRejected, "account is too young")
+	}
+	return identity, nil
+}
+
+type mockIdentityStore map[string]IdentityUsage
+
+func (m mockIdentityStore) UseIdentityQuota(
+	ctx context.Context,
+	key string,
+	quota uint64,
+	period time.Duration,
+	now time.Time,
+) (IdentityUsage, error) {
+	usage, err := m[key].Consume(now, quota, period)
+	if err != nil {
+		return usage, err
+	}
+	usage.Key = key
+	m[key] = usage
+	return usage, nil
+}
+
+func (m mockIdentityStore) IdentityQuotaUsage(ctx context.Context, key string) (IdentityUsage, error) {
+	usage := m[key]
+	usage.Key = key
+	return usage, nil
+}
+
+func TestIdentity(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	provider := mockIdentityProvider{accounts: map[string]Identity{
+		"code1": {Subject: "42", Name: "octocat"},
+	}}
+	a := New(&mockBatcher{}, nil, NewTxTracker(nil, 1, nil, clk), &mockHistory{}, &mockLedger{}, chain.Network{},
+		chain.Coin{}).WithClock(clk)
+	cfg := IdentityConfig{
+		SigningKey:  "0123456789abcdef",
+		SessionTTL:  time.Hour,
+		Quota:       1,
+		QuotaPeriod: 24 * time.Hour,
+	}
+	_, err := a.WithIdentity(IdentityConfig{SigningKey: "short", SessionTTL: time.Hour}, nil, provider)
+	requireT.Error(err)
+	_, err = a.WithIdentity(cfg, nil, provider)
+	requireT.Error(err)
+	_, err = a.WithIdentity(cfg, mockIdentityStore{}, provider, provider)
+	requireT.Error(err)
+	a, err = a.WithIdentity(cfg, mockIdentityStore{}, provider)
+	requireT.NoError(err)
+	requireT.Equal([]string{"github"}, a.IdentityProviders())
+
+	_, err = a.IdentityLoginURL("discord")
+	requireT.ErrorIs(err, ErrIdentityProviderNotFound)
+	loginURL, err := a.IdentityLoginURL("github")
+	requireT.NoError(err)
+	state := strings.TrimPrefix(loginURL, "https://github.example.com/authorize?state=")
+	state, err = url.QueryUnescape(state)
+	requireT.NoError(err)
+
+	_, err = a.CompleteIdentityLogin(ctx, "github", "code1", "forged")
+	requireT.ErrorIs(err, ErrIdentityFailed)
+	session, err := a.CompleteIdentityLogin(ctx, "github", "code1", state)
+	requireT.NoError(err)
+	requireT.Equal(Identity{Provider: "github", Subject: "42", Name: "octocat"}, session.Identity)
+	requireT.Equal(clk.Now().Add(time.Hour), session.ExpiresAt)
+	// state is used once
+	_, err = a.CompleteIdentityLogin(ctx, "github", "code1", state)
+	requireT.ErrorIs(err, ErrIdentityFailed)
+
+	// accounts not meeting the requirements get no session
+	loginURL, err = a.IdentityLoginURL("github")
+	requireT.NoError(err)
+	state, err = url.QueryUnescape(strings.TrimPrefix(loginURL, "https://github.example.com/authorize?state="))
+	requireT.NoError(err)
+	_, err = a.CompleteIdentityLogin(ctx, "github", "code2", state)
+	requireT.ErrorIs(err, ErrIdentityRejected)
+
+	requireT.ErrorIs(a.verify(ctx, Requester{}), ErrIdentityFailed)
+	requireT.ErrorIs(a.verify(ctx, Requester{Proofs: Proofs{ProofIdentity: session.Token + "x"}}), ErrIdentityFailed)
+	requireT.NoError(a.verify(ctx, Requester{Proofs: Proofs{ProofIdentity: session.Token}}))
+	// the quota of the account is used up
+	err = a.verify(ctx, Requester{Proofs: Proofs{ProofIdentity: session.Token}})
+	requireT.ErrorIs(err, ErrIdentityQuotaExhausted)
+	var throttled ThrottledError
+	requireT.ErrorAs(err, &throttled)
+	requireT.Equal(clk.Now().Add(24*time.Hour), throttled.NextAvailableAt)
+	// automation is exempt
+	requireT.NoError(a.verify(ctx, Requester{Admin: true}))
+
+	clk.Advance(time.Hour)
+	requireT.ErrorIs(a.verify(ctx, Requester{Proofs: Proofs{ProofIdentity: session.Token}}), ErrIdentityFailed)
+}
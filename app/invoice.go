@@ -0,0 +1,25 @@
+package app
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+var invoiceIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9._:/#-]{1,128}$`)
+
+// validateInvoiceID verifies the invoice ID of the requester. It is accepted from the API key clients only,
+// their billing systems reconcile the transfers with the invoices.
+func validateInvoiceID(requester Requester) error {
+	if requester.InvoiceID == "" {
+		return nil
+	}
+	if !invoiceIDRegexp.MatchString(requester.InvoiceID) {
+		return errors.Wrap(ErrInvalidInvoiceID,
+			"invoice ID must be 1-128 letters, digits, dots, colons, slashes, hashes, dashes or underscores")
+	}
+	if requester.APIKeyHolder == "" {
+		return errors.Wrap(ErrInvoiceIDUnauthorized, "API key is required to pass the invoice ID")
+	}
+	return nil
+}
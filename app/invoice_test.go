@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/attribution"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+func TestGiveFundsInvoice(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+
+	batcher := &mockBatcher{txHash: "tx1"}
+	history := &mockHistory{}
+	a := New(batcher, nil, NewTxTracker(nil, 1, nil, clock.System{}), history, &mockLedger{}, network,
+		chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	_, err = a.GiveFunds(ctx, Requester{InvoiceID: "INV-1001"}, address)
+	requireT.ErrorIs(err, ErrInvoiceIDUnauthorized)
+	_, err = a.GiveFunds(ctx, Requester{APIKeyHolder: "billing", InvoiceID: "INV 1001"}, address)
+	requireT.ErrorIs(err, ErrInvalidInvoiceID)
+	_, err = a.GiveFunds(ctx, Requester{APIKeyHolder: "billing", InvoiceID: strings.Repeat("1", 129)}, address)
+	requireT.ErrorIs(err, ErrInvalidInvoiceID)
+	requireT.Zero(batcher.calls)
+
+	// hash is embedded even if the attribution is disabled
+	_, err = a.GiveFunds(ctx, Requester{APIKeyHolder: "billing", InvoiceID: "INV-1001"}, address)
+	requireT.NoError(err)
+	requireT.Equal(attribution.Attribution{InvoiceHash: attribution.HashInvoiceID("INV-1001")}, batcher.attr)
+	requireT.Len(history.fundings, 1)
+	requireT.Equal("INV-1001", history.fundings[0].InvoiceID)
+
+	a = a.WithTxAttribution(true)
+	_, err = a.GiveFunds(ctx, Requester{Tenant: "billing", APIKeyHolder: "billing", InvoiceID: "INV-1002"}, address)
+	requireT.NoError(err)
+	requireT.Equal(attribution.Attribution{
+		Tenant:      "billing",
+		InvoiceHash: attribution.HashInvoiceID("INV-1002"),
+	}, batcher.attr)
+}
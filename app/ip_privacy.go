@@ -0,0 +1,133 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+// IP privacy modes.
+const (
+	// IPPrivacyOff keeps full IPs in the history.
+	IPPrivacyOff = "off"
+	// IPPrivacyHash replaces IPs with salted hashes, so fundings from the same IP are still linked.
+	IPPrivacyHash = "hash"
+	// IPPrivacyTruncate replaces IPs with their /24 (IPv4) or /48 (IPv6) networks.
+	IPPrivacyTruncate = "truncate"
+)
+
+// IPPrivacyModes lists the supported IP privacy modes.
+var IPPrivacyModes = []string{IPPrivacyOff, IPPrivacyHash, IPPrivacyTruncate}
+
+const (
+	ipHashPrefix        = "h:"
+	ipHashLength        = 32
+	minIPHashSaltLength = 16
+	ipv4TruncatedBits   = 24
+	ipv6TruncatedBits   = 48
+)
+
+// IPAnonymizingStore rewrites the IPs stored in the funding history.
+type IPAnonymizingStore interface {
+	// AnonymizeFundingIPs replaces IPs of the fundings recorded in the period with the result of anonymize
+	// and returns the number of updated records.
+	AnonymizeFundingIPs(ctx context.Context, since, before time.Time, anonymize func(ip string) string) (int, error)
+}
+
+// IPAnonymizer keeps full IPs of the clients in the funding history only for the retention period. The limiters
+// work on the IPs of live requests, so they are not affected.
+type IPAnonymizer struct {
+	mode      string
+	salt      []byte
+	retention time.Duration
+	store     IPAnonymizingStore
+	clock     clock.Clock
+
+	anonymizedBefore time.Time
+}
+
+// NewIPAnonymizer returns the anonymizer of the mode. Zero retention means IPs are anonymized before they are
+// stored. Salt is required by the hash mode.
+func NewIPAnonymizer(
+	mode string,
+	salt string,
+	retention time.Duration,
+	store IPAnonymizingStore,
+	clk clock.Clock,
+) (*IPAnonymizer, error) {
+	switch mode {
+	case IPPrivacyHash:
+		if len(salt) < minIPHashSaltLength {
+			return nil, errors.Errorf("salt of at least %d characters is required to hash IPs", minIPHashSaltLength)
+		}
+	case IPPrivacyTruncate:
+	default:
+		return nil, errors.Errorf("unsupported IP privacy mode %q", mode)
+	}
+	if retention < 0 {
+		return nil, errors.New("retention of full IPs must not be negative")
+	}
+	return &IPAnonymizer{
+		mode:      mode,
+		salt:      []byte(salt),
+		retention: retention,
+		store:     store,
+		clock:     clk,
+	}, nil
+}
+
+// Anonymize returns the hash or the network of the IP. Values which are not IPs, including already anonymized ones,
+// are returned unchanged, so anonymization may be applied repeatedly.
+func (a *IPAnonymizer) Anonymize(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+	if a.mode == IPPrivacyHash {
+		mac := hmac.New(sha256.New, a.salt)
+		mac.Write([]byte(parsed.String()))
+		return ipHashPrefix + hex.EncodeToString(mac.Sum(nil))[:ipHashLength]
+	}
+	mask := net.CIDRMask(ipv6TruncatedBits, 8*net.IPv6len)
+	if v4 := parsed.To4(); v4 != nil {
+		parsed = v4
+		mask = net.CIDRMask(ipv4TruncatedBits, 8*net.IPv4len)
+	}
+	return (&net.IPNet{IP: parsed.Mask(mask), Mask: mask}).String()
+}
+
+// atRest returns the IP as it is stored in the history at the time funding is recorded.
+func (a *IPAnonymizer) atRest(ip string) string {
+	if a == nil || a.retention > 0 {
+		return ip
+	}
+	return a.Anonymize(ip)
+}
+
+// IPAnonymizeInterval is how often the IPs of the fundings older than the retention period are anonymized.
+const IPAnonymizeInterval = time.Minute
+
+// AnonymizeExpired anonymizes the IPs of the fundings older than the retention period.
+func (a *IPAnonymizer) AnonymizeExpired(ctx context.Context) error {
+	before := a.clock.Now().UTC().Add(-a.retention)
+	// the first pass covers the whole history, the next ones only the fundings expired since the previous pass
+	updated, err := a.store.AnonymizeFundingIPs(ctx, a.anonymizedBefore, before, a.Anonymize)
+	if err != nil {
+		return err
+	}
+	a.anonymizedBefore = before
+	if updated > 0 {
+		logger.Get(ctx).Info("IPs anonymized", zap.Int("records", updated), zap.Time("before", before))
+	}
+	return nil
+}
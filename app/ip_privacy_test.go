@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockIPAnonymizingStore struct {
+	periods [][2]time.Time
+}
+
+func (m *mockIPAnonymizingStore) AnonymizeFundingIPs(
+	ctx context.Context,
+	since, before time.Time,
+	anonymize func(ip string) string,
+) (int, error) {
+	m.periods = append(m.periods, [2]time.Time{since, before})
+	return 0, nil
+}
+
+func TestIPAnonymizer(t *testing.T) {
+	requireT := require.New(t)
+
+	_, err := NewIPAnonymizer(IPPrivacyHash, "short", 0, nil, clock.System{})
+	requireT.Error(err)
+	_, err = NewIPAnonymizer("unknown", "", 0, nil, clock.System{})
+	requireT.Error(err)
+
+	truncating, err := NewIPAnonymizer(IPPrivacyTruncate, "", 0, nil, clock.System{})
+	requireT.NoError(err)
+	requireT.Equal("192.168.7.0/24", truncating.Anonymize("192.168.7.42"))
+	requireT.Equal("2001:db8:abcd::/48", truncating.Anonymize("2001:db8:abcd:12::1"))
+	requireT.Equal("192.168.7.0/24", truncating.Anonymize("192.168.7.0/24"))
+	requireT.Equal("", truncating.Anonymize(""))
+
+	hashing, err := NewIPAnonymizer(IPPrivacyHash, "0123456789abcdef", 0, nil, clock.System{})
+	requireT.NoError(err)
+	hash := hashing.Anonymize("192.168.7.42")
+	requireT.Regexp(`^h:[0-9a-f]{32}$`, hash)
+	requireT.Equal(hash, hashing.Anonymize("192.168.7.42"))
+	requireT.NotEqual(hash, hashing.Anonymize("192.168.7.43"))
+	requireT.Equal(hash, hashing.Anonymize(hash))
+
+	otherSalt, err := NewIPAnonymizer(IPPrivacyHash, "fedcba9876543210", 0, nil, clock.System{})
+	requireT.NoError(err)
+	requireT.NotEqual(hash, otherSalt.Anonymize("192.168.7.42"))
+}
+
+func TestIPAnonymizerRetention(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	clk := clock.NewOffset()
+	store := &mockIPAnonymizingStore{}
+	anonymizer, err := NewIPAnonymizer(IPPrivacyTruncate, "", time.Hour, store, clk)
+	requireT.NoError(err)
+
+	history := &mockHistory{}
+	a := New(nil, nil, nil, history, nil, chain.Network{}, chain.Coin{}).WithIPAnonymizer(anonymizer)
+	a.recordFunding(ctx, Requester{IP: "10.1.2.3"}, chain.AccAddress{}, "tx", chain.Coin{}, chain.Coin{})
+	// full IP is kept during the retention period
+	requireT.Equal("10.1.2.3", history.fundings[0].IP)
+
+	requireT.NoError(anonymizer.AnonymizeExpired(ctx))
+	clk.Advance(time.Hour)
+	requireT.NoError(anonymizer.AnonymizeExpired(ctx))
+	requireT.Len(store.periods, 2)
+	requireT.True(store.periods[0][0].IsZero())
+	requireT.Equal(store.periods[0][1], store.periods[1][0])
+	requireT.Equal(time.Hour, store.periods[1][1].Sub(store.periods[1][0]).Round(time.Minute))
+
+	anonymizer, err = NewIPAnonymizer(IPPrivacyTruncate, "", 0, store, clk)
+	requireT.NoError(err)
+	a = a.WithIPAnonymizer(anonymizer)
+	a.recordFunding(ctx, Requester{IP: "10.1.2.3"}, chain.AccAddress{}, "tx", chain.Coin{}, chain.Coin{})
+	// without retention IP is anonymized before it is stored
+	requireT.Equal("10.1.2.0/24", history.fundings[1].IP)
+}
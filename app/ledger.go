@@ -0,0 +1,248 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// DefaultTenant is the tenant the requests not assigned to any tenant are accounted to.
+const DefaultTenant = "public"
+
+// ledgerReconcileGrace is the time given to the transaction to be included in a block before it is reported
+// as missing on chain.
+const ledgerReconcileGrace = time.Minute
+
+// Ledger account kinds.
+const (
+	// LedgerAccountTreasury is the account budgets are allocated from.
+	LedgerAccountTreasury = "treasury"
+	// LedgerAccountBudget holds the funds allocated to the tenant and session.
+	LedgerAccountBudget = "budget"
+	// LedgerAccountSpent holds the funds sent to recipients on behalf of the tenant and session.
+	LedgerAccountSpent = "spent"
+)
+
+// Ledger transaction kinds.
+const (
+	LedgerTxAllocation = "allocation"
+	LedgerTxSpend      = "spend"
+)
+
+// Ledger discrepancy kinds.
+const (
+	// DiscrepancyMissingOnChain means the funding transaction is not found on chain.
+	DiscrepancyMissingOnChain = "missing_on_chain"
+	// DiscrepancyAmountMismatch means the amount spent according to the ledger doesn't match the funding history.
+	DiscrepancyAmountMismatch = "amount_mismatch"
+)
+
+// LedgerAccount identifies the account of the budget ledger.
+type LedgerAccount struct {
+	Kind    string `json:"kind"`
+	ChainID string `json:"chainId"`
+	Tenant  string `json:"tenant,omitempty"`
+	Session string `json:"session,omitempty"`
+}
+
+// LedgerTransaction moves the amount from the credited account to the debited one. Each transaction is balanced,
+// so the sum of balances of all the accounts is always zero.
+type LedgerTransaction struct {
+	ID     string        `json:"id"`
+	Kind   string        `json:"kind"`
+	Debit  LedgerAccount `json:"debit"`
+	Credit LedgerAccount `json:"credit"`
+	Amount chain.Coin    `json:"amount"`
+	TxHash string        `json:"txHash,omitempty"`
+	Time   time.Time     `json:"time"`
+}
+
+// LedgerBalance is the sum of debits and credits of the account.
+type LedgerBalance struct {
+	Account LedgerAccount
+	Debits  chain.Coins
+	Credits chain.Coins
+}
+
+// Balance returns the balance of the account in the denom, it is negative if the account is credited more
+// than debited.
+func (b LedgerBalance) Balance(denom string) chain.Int {
+	return b.Debits.AmountOf(denom).Sub(b.Credits.AmountOf(denom))
+}
+
+// LedgerDiscrepancy describes the mismatch between the ledger and the funding history or the chain.
+type LedgerDiscrepancy struct {
+	Kind    string
+	TxHash  string
+	Message string
+}
+
+// LedgerStore persists the budget ledger.
+type LedgerStore interface {
+	RecordLedgerTransaction(ctx context.Context, tx LedgerTransaction) error
+	LedgerTransactionsSince(ctx context.Context, since time.Time) ([]LedgerTransaction, error)
+}
+
+// AllocateBudget allocates the budget to the tenant and session, session may be empty.
+func (a App) AllocateBudget(ctx context.Context, tenant, session string, amount chain.Coin) error {
+	if tenant == "" {
+		tenant = DefaultTenant
+	}
+	chainID := string(a.network.ChainID())
+	return a.ledger.RecordLedgerTransaction(ctx, LedgerTransaction{
+		ID:     uuid.New().String(),
+		Kind:   LedgerTxAllocation,
+		Debit:  LedgerAccount{Kind: LedgerAccountBudget, ChainID: chainID, Tenant: tenant, Session: session},
+		Credit: LedgerAccount{Kind: LedgerAccountTreasury, ChainID: chainID},
+		Amount: amount,
+		Time:   a.clock.Now().UTC(),
+	})
+}
+
+// LedgerBalances returns balances of all the ledger accounts.
+func (a App) LedgerBalances(ctx context.Context) ([]LedgerBalance, error) {
+	txs, err := a.queries().LedgerTransactionsSince(ctx, time.Unix(0, 0))
+	if err != nil {
+		return nil, err
+	}
+	return ledgerBalances(txs), nil
+}
+
+// ReconcileLedger compares the ledger transactions recorded since the given time against the funding history
+// and the chain.
+func (a App) ReconcileLedger(ctx context.Context, since time.Time) ([]LedgerDiscrepancy, error) {
+	txs, err := a.queries().LedgerTransactionsSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	records, err := a.queries().FundingsSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	discrepancies := reconcileHistory(txs, records)
+
+	// many fundings are sent in a single transaction, so each of them is checked once
+	checked := map[string]bool{}
+	deadline := a.clock.Now().UTC().Add(-ledgerReconcileGrace)
+	for _, tx := range txs {
+		if tx.Kind != LedgerTxSpend || checked[tx.TxHash] || tx.Time.After(deadline) {
+			continue
+		}
+		checked[tx.TxHash] = true
+
+		height, err := a.chain.TxHeight(ctx, tx.TxHash)
+		if err != nil {
+			return nil, err
+		}
+		if height == 0 {
+			discrepancies = append(discrepancies, LedgerDiscrepancy{
+				Kind:    DiscrepancyMissingOnChain,
+				TxHash:  tx.TxHash,
+				Message: "transaction is not found on chain",
+			})
+		}
+	}
+
+	return discrepancies, nil
+}
+
+// recordSpend stores the spend in the ledger. Similar to the history, failure is only logged.
+func (a App) recordSpend(ctx context.Context, requester Requester, txHash string, amount chain.Coin) {
+	tenant := requester.Tenant
+	if tenant == "" {
+		tenant = DefaultTenant
+	}
+	chainID := string(a.network.ChainID())
+	err := a.ledger.RecordLedgerTransaction(ctx, LedgerTransaction{
+		ID:     uuid.New().String(),
+		Kind:   LedgerTxSpend,
+		Debit:  LedgerAccount{Kind: LedgerAccountSpent, ChainID: chainID, Tenant: tenant, Session: requester.Session},
+		Credit: LedgerAccount{Kind: LedgerAccountBudget, ChainID: chainID, Tenant: tenant, Session: requester.Session},
+		Amount: amount,
+		TxHash: txHash,
+		Time:   a.clock.Now().UTC(),
+	})
+	if err != nil {
+		logger.Get(ctx).Error("Recording ledger spend failed", zap.String("txHash", txHash), zap.Error(err))
+	}
+}
+
+func ledgerBalances(txs []LedgerTransaction) []LedgerBalance {
+	balances := map[LedgerAccount]*LedgerBalance{}
+	get := func(account LedgerAccount) *LedgerBalance {
+		b, ok := balances[account]
+		if !ok {
+			b = &LedgerBalance{Account: account, Debits: chain.NewCoins(), Credits: chain.NewCoins()}
+			balances[account] = b
+		}
+		return b
+	}
+	for _, tx := range txs {
+		debit := get(tx.Debit)
+		debit.Debits = debit.Debits.Add(tx.Amount)
+		credit := get(tx.Credit)
+		credit.Credits = credit.Credits.Add(tx.Amount)
+	}
+
+	result := make([]LedgerBalance, 0, len(balances))
+	for _, b := range balances {
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].Account, result[j].Account
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.ChainID != b.ChainID {
+			return a.ChainID < b.ChainID
+		}
+		if a.Tenant != b.Tenant {
+			return a.Tenant < b.Tenant
+		}
+		return a.Session < b.Session
+	})
+	return result
+}
+
+func reconcileHistory(txs []LedgerTransaction, records []FundingRecord) []LedgerDiscrepancy {
+	var hashes []string
+	spent := map[string]chain.Coins{}
+	funded := map[string]chain.Coins{}
+	add := func(amounts map[string]chain.Coins, txHash string, amount chain.Coin) {
+		if _, ok := spent[txHash]; !ok {
+			if _, ok := funded[txHash]; !ok {
+				hashes = append(hashes, txHash)
+			}
+		}
+		amounts[txHash] = amounts[txHash].Add(amount)
+	}
+	for _, tx := range txs {
+		if tx.Kind == LedgerTxSpend {
+			add(spent, tx.TxHash, tx.Amount)
+		}
+	}
+	for _, r := range records {
+		add(funded, r.TxHash, r.Amount)
+	}
+
+	var discrepancies []LedgerDiscrepancy
+	for _, txHash := range hashes {
+		if spent[txHash].String() != funded[txHash].String() {
+			discrepancies = append(discrepancies, LedgerDiscrepancy{
+				Kind:    DiscrepancyAmountMismatch,
+				TxHash:  txHash,
+				Message: fmt.Sprintf("ledger spent %s, history funded %s", spent[txHash], funded[txHash]),
+			})
+		}
+	}
+	return discrepancies
+}
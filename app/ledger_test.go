@@ -0,0 +1,68 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+func TestLedgerBalances(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	treasury := LedgerAccount{Kind: LedgerAccountTreasury, ChainID: "chain"}
+	budget := LedgerAccount{Kind: LedgerAccountBudget, ChainID: "chain", Tenant: "ci"}
+	spent := LedgerAccount{Kind: LedgerAccountSpent, ChainID: "chain", Tenant: "ci"}
+	coin := func(amount int64) chain.Coin {
+		return chain.NewCoin("ucore", chain.NewInt(amount))
+	}
+	txs := []LedgerTransaction{
+		{Kind: LedgerTxAllocation, Debit: budget, Credit: treasury, Amount: coin(100)},
+		{Kind: LedgerTxSpend, Debit: spent, Credit: budget, Amount: coin(30)},
+		{Kind: LedgerTxSpend, Debit: spent, Credit: budget, Amount: coin(80)},
+	}
+
+	balances := ledgerBalances(txs)
+	requireT.Len(balances, 3)
+	assertT.Equal(budget, balances[0].Account)
+	assertT.Equal(int64(-10), balances[0].Balance("ucore").Int64())
+	assertT.Equal(spent, balances[1].Account)
+	assertT.Equal(int64(110), balances[1].Balance("ucore").Int64())
+	assertT.Equal(treasury, balances[2].Account)
+	assertT.Equal(int64(-100), balances[2].Balance("ucore").Int64())
+
+	total := chain.NewInt(0)
+	for _, b := range balances {
+		total = total.Add(b.Balance("ucore"))
+	}
+	assertT.True(total.IsZero())
+}
+
+func TestReconcileHistory(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	coin := chain.NewCoin("ucore", chain.NewInt(10))
+	now := time.Now()
+	txs := []LedgerTransaction{
+		{Kind: LedgerTxSpend, Amount: coin, TxHash: "tx1", Time: now},
+		{Kind: LedgerTxSpend, Amount: coin, TxHash: "tx1", Time: now},
+		{Kind: LedgerTxSpend, Amount: coin, TxHash: "tx2", Time: now},
+		{Kind: LedgerTxAllocation, Amount: coin, Time: now},
+	}
+	records := []FundingRecord{
+		{Amount: coin, TxHash: "tx1"},
+		{Amount: coin, TxHash: "tx1"},
+		{Amount: coin, TxHash: "tx3"},
+	}
+
+	discrepancies := reconcileHistory(txs, records)
+	requireT.Len(discrepancies, 2)
+	assertT.Equal("tx2", discrepancies[0].TxHash)
+	assertT.Equal(DiscrepancyAmountMismatch, discrepancies[0].Kind)
+	assertT.Equal("tx3", discrepancies[1].TxHash)
+}
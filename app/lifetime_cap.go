@@ -0,0 +1,142 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// AddressTotal is the cumulative amount sent to the address.
+type AddressTotal struct {
+	Address   string      `json:"address"`
+	Amount    chain.Coins `json:"amount"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+}
+
+// AddressTotals persists the cumulative amounts sent to the addresses, keyed by the address bytes.
+type AddressTotals interface {
+	// AddressTotal returns the cumulative amount sent to the address, it is empty if nothing is recorded.
+	AddressTotal(ctx context.Context, address chain.AccAddress) (AddressTotal, error)
+	// ReserveAddressTotal atomically adds the amount to the total of the address unless the total of the denom
+	// would exceed the limit, nil limit means no limit. It returns the total and whether the amount was added.
+	ReserveAddressTotal(
+		ctx context.Context,
+		address chain.AccAddress,
+		amount chain.Coin,
+		limit chain.Int,
+		now time.Time,
+	) (AddressTotal, bool, error)
+	// ReleaseAddressTotal subtracts the amount of the failed transfer from the total of the address.
+	ReleaseAddressTotal(ctx context.Context, address chain.AccAddress, amount chain.Coin) error
+	// ResetAddressTotal deletes the total of the address.
+	ResetAddressTotal(ctx context.Context, address chain.AccAddress) error
+}
+
+// lifetimeCap refuses funding the address once the cumulative amount sent to it reaches the limit.
+type lifetimeCap struct {
+	store AddressTotals
+	limit chain.Coin
+}
+
+// WithLifetimeCap returns a copy of the app tracking the cumulative amount sent to each address and refusing
+// to fund the address once the amount of the denom reaches the limit. Zero limit means the totals are tracked only.
+// Requests authorized by the admin token are counted, but not refused.
+func (a App) WithLifetimeCap(store AddressTotals, limit chain.Coin) App {
+	a.lifetimeCap = lifetimeCap{store: store, limit: limit}
+	return a
+}
+
+// LifetimeCap returns the limit of the cumulative amount sent to each address, zero if there is none.
+func (a App) LifetimeCap() chain.Coin {
+	return a.lifetimeCap.limit
+}
+
+// AddressTotalsTracked reports whether the cumulative amounts sent to the addresses are tracked.
+func (a App) AddressTotalsTracked() bool {
+	return a.lifetimeCap.store != nil
+}
+
+// AddressTotal returns the cumulative amount sent to the address.
+func (a App) AddressTotal(ctx context.Context, address string) (AddressTotal, error) {
+	if a.lifetimeCap.store == nil {
+		return AddressTotal{}, errors.New("address totals are not tracked")
+	}
+	sdkAddr, err := a.validateAddress(address)
+	if err != nil {
+		return AddressTotal{}, err
+	}
+	return a.lifetimeCap.store.AddressTotal(ctx, sdkAddr)
+}
+
+// FirstTimeAddress tells if the address has never been funded, so newcomers may be treated more leniently than
+// repeat requesters. Addresses are never first-time ones if the totals are not tracked. Resetting the total makes
+// the address first-time again.
+func (a App) FirstTimeAddress(ctx context.Context, address string) (bool, error) {
+	if a.lifetimeCap.store == nil {
+		return false, nil
+	}
+	sdkAddr, err := a.validateAddress(address)
+	if err != nil {
+		return false, err
+	}
+	total, err := a.lifetimeCap.store.AddressTotal(ctx, sdkAddr)
+	if err != nil {
+		return false, err
+	}
+	return total.Amount.IsZero(), nil
+}
+
+// ResetAddressTotal forgets the cumulative amount sent to the address, so it may be funded up to the limit again.
+func (a App) ResetAddressTotal(ctx context.Context, address string) error {
+	if a.lifetimeCap.store == nil {
+		return errors.New("address totals are not tracked")
+	}
+	sdkAddr, err := a.validateAddress(address)
+	if err != nil {
+		return err
+	}
+	if err := a.lifetimeCap.store.ResetAddressTotal(ctx, sdkAddr); err != nil {
+		return err
+	}
+	logger.Get(ctx).Warn("Address total reset", zap.String("address", address))
+	return nil
+}
+
+// reserveLifetimeCap adds the amount to the total of the address. It returns false if nothing is reserved,
+// so there is nothing to release.
+func (a App) reserveLifetimeCap(
+	ctx context.Context,
+	requester Requester,
+	address chain.AccAddress,
+	amount chain.Coin,
+) (bool, error) {
+	c := a.lifetimeCap
+	if c.store == nil {
+		return false, nil
+	}
+	var limit chain.Int
+	if !requester.Admin && amount.Denom == c.limit.Denom && c.limit.Amount.IsPositive() {
+		limit = c.limit.Amount
+	}
+	total, reserved, err := c.store.ReserveAddressTotal(ctx, address, amount, limit, a.clock.Now().UTC())
+	if err != nil {
+		return false, err
+	}
+	if !reserved {
+		return false, errors.Wrapf(ErrLifetimeCapReached, "address %s has received %s of the lifetime cap of %s",
+			address, chain.NewCoin(amount.Denom, total.Amount.AmountOf(amount.Denom)), c.limit)
+	}
+	return true, nil
+}
+
+func (a App) releaseLifetimeCap(ctx context.Context, address chain.AccAddress, amount chain.Coin) {
+	if err := a.lifetimeCap.store.ReleaseAddressTotal(ctx, address, amount); err != nil {
+		logger.Get(ctx).Error("Releasing address total failed", zap.Error(err))
+	}
+}
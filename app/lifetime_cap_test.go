@@ -0,0 +1,157 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockAddressTotals struct {
+	mu     sync.Mutex
+	totals map[string]AddressTotal
+}
+
+func (m *mockAddressTotals) AddressTotal(_ context.Context, address chain.AccAddress) (AddressTotal, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.totals[string(address)], nil
+}
+
+func (m *mockAddressTotals) ReserveAddressTotal(
+	_ context.Context,
+	address chain.AccAddress,
+	amount chain.Coin,
+	limit chain.Int,
+	now time.Time,
+) (AddressTotal, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	total := m.totals[string(address)]
+	if !limit.IsNil() && total.Amount.AmountOf(amount.Denom).Add(amount.Amount).GT(limit) {
+		return total, false, nil
+	}
+	total.Amount = total.Amount.Add(amount)
+	total.UpdatedAt = now
+	if m.totals == nil {
+		m.totals = map[string]AddressTotal{}
+	}
+	m.totals[string(address)] = total
+	return total, true, nil
+}
+
+func (m *mockAddressTotals) ReleaseAddressTotal(_ context.Context, address chain.AccAddress, amount chain.Coin) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	total := m.totals[string(address)]
+	total.Amount = total.Amount.Sub(chain.NewCoins(amount))
+	m.totals[string(address)] = total
+	return nil
+}
+
+func (m *mockAddressTotals) ResetAddressTotal(_ context.Context, address chain.AccAddress) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.totals, string(address))
+	return nil
+}
+
+func TestLifetimeCap(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const (
+		address      = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+		otherAddress = "devcore1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqp09pnng"
+	)
+
+	batcher := &mockBatcher{txHash: "tx1"}
+	a := New(batcher, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{}, network,
+		chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithLifetimeCap(&mockAddressTotals{}, chain.NewCoin("udevcore", chain.NewInt(2000)))
+	requireT.True(a.AddressTotalsTracked())
+
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.NoError(err)
+
+	// failed transfer is not counted
+	batcher.err = errors.New("boom")
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.ErrorIs(err, ErrUnableToTransferToken)
+	batcher.err = nil
+
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.NoError(err)
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.ErrorIs(err, ErrLifetimeCapReached)
+
+	// other addresses are not affected
+	_, err = a.GiveFunds(ctx, Requester{}, otherAddress)
+	requireT.NoError(err)
+
+	// admin overrides the cap, but the amount is still counted
+	_, err = a.GiveFunds(ctx, Requester{Admin: true}, address)
+	requireT.NoError(err)
+	total, err := a.AddressTotal(ctx, address)
+	requireT.NoError(err)
+	requireT.Equal("3000udevcore", total.Amount.String())
+	requireT.Equal(5, batcher.calls)
+
+	requireT.NoError(a.ResetAddressTotal(ctx, address))
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.NoError(err)
+	total, err = a.AddressTotal(ctx, address)
+	requireT.NoError(err)
+	requireT.Equal("1000udevcore", total.Amount.String())
+}
+
+func TestFirstTimeAddress(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+
+	batcher := &mockBatcher{txHash: "tx1"}
+	a := New(batcher, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{}, network,
+		chain.NewCoin("udevcore", chain.NewInt(1000)))
+
+	// addresses are never first-time ones if the totals are not tracked
+	firstTime, err := a.FirstTimeAddress(ctx, address)
+	requireT.NoError(err)
+	requireT.False(firstTime)
+
+	a = a.WithLifetimeCap(&mockAddressTotals{}, chain.Coin{})
+	firstTime, err = a.FirstTimeAddress(ctx, address)
+	requireT.NoError(err)
+	requireT.True(firstTime)
+
+	// failed transfer doesn't count
+	batcher.err = errors.New("boom")
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.ErrorIs(err, ErrUnableToTransferToken)
+	firstTime, err = a.FirstTimeAddress(ctx, address)
+	requireT.NoError(err)
+	requireT.True(firstTime)
+
+	batcher.err = nil
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.NoError(err)
+	firstTime, err = a.FirstTimeAddress(ctx, address)
+	requireT.NoError(err)
+	requireT.False(firstTime)
+
+	_, err = a.FirstTimeAddress(ctx, "core10krrrqxxy948n5p9xvwgq6krgy9hg5g8zjsq5k")
+	requireT.ErrorIs(err, ErrAddressPrefixUnsupported)
+}
@@ -0,0 +1,203 @@
+package app
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/secret"
+)
+
+const minMnemonicKeyLength = 16
+
+var accountLabelRegexp = regexp.MustCompile(`^[a-zA-Z0-9._:/-]{1,128}$`)
+
+// NamedAccount is the account generated by gen-funded under the label, kept so re-runs of the test suite
+// get the same account instead of the new one each time. The mnemonic is stored encrypted.
+type NamedAccount struct {
+	// Namespace isolates the labels of different API key holders.
+	Namespace         string    `json:"namespace"`
+	Label             string    `json:"label"`
+	Address           string    `json:"address"`
+	EncryptedMnemonic []byte    `json:"encryptedMnemonic"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+// NamedAccountStore persists the named accounts, keyed by their namespace and label.
+type NamedAccountStore interface {
+	// NamedAccount returns the account or ErrNamedAccountNotFound.
+	NamedAccount(ctx context.Context, namespace, label string) (NamedAccount, error)
+	// CreateNamedAccount stores the account unless the account of the label exists already. It returns
+	// the stored account, so concurrent requests of the same label get the same account.
+	CreateNamedAccount(ctx context.Context, account NamedAccount) (NamedAccount, error)
+}
+
+// MnemonicCipher encrypts the mnemonics of the named accounts with AES-256-GCM.
+type MnemonicCipher struct {
+	aead cipher.AEAD
+}
+
+// NewMnemonicCipher returns the cipher using the key derived from the passphrase.
+func NewMnemonicCipher(passphrase string) (*MnemonicCipher, error) {
+	if len(passphrase) < minMnemonicKeyLength {
+		return nil, errors.Errorf("key of at least %d characters is required to encrypt the mnemonics", minMnemonicKeyLength)
+	}
+	key := sha256.Sum256([]byte(passphrase))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &MnemonicCipher{aead: aead}, nil
+}
+
+// Encrypt returns the mnemonic encrypted and prefixed with the random nonce. The address is authenticated
+// together with the mnemonic, so the ciphertext can't be moved to another account.
+func (c *MnemonicCipher) Encrypt(mnemonic secret.String, address string) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return c.aead.Seal(nonce, nonce, []byte(mnemonic.Reveal()), []byte(address)), nil
+}
+
+// Decrypt returns the mnemonic encrypted by Encrypt.
+func (c *MnemonicCipher) Decrypt(ciphertext []byte, address string) (secret.String, error) {
+	if len(ciphertext) < c.aead.NonceSize() {
+		return secret.String{}, errors.New("ciphertext is too short")
+	}
+	nonce, sealed := ciphertext[:c.aead.NonceSize()], ciphertext[c.aead.NonceSize():]
+	mnemonic, err := c.aead.Open(nil, nonce, sealed, []byte(address))
+	if err != nil {
+		return secret.String{}, errors.Wrap(err, "unable to decrypt the mnemonic, was the key changed?")
+	}
+	return secret.New(string(mnemonic)), nil
+}
+
+type namedAccounts struct {
+	store  NamedAccountStore
+	cipher *MnemonicCipher
+}
+
+// WithNamedAccounts returns a copy of the app keeping the accounts generated under the label, so they can be
+// retrieved again by the same API key holder.
+func (a App) WithNamedAccounts(store NamedAccountStore, cipher *MnemonicCipher) App {
+	a.namedAccounts = namedAccounts{store: store, cipher: cipher}
+	return a
+}
+
+// NamedAccountsEnabled tells if the accounts may be generated under the label.
+func (a App) NamedAccountsEnabled() bool {
+	return a.namedAccounts.store != nil && a.namedAccounts.cipher != nil
+}
+
+// NamedAccount returns the account generated under the label by the requester, without funding it.
+func (a App) NamedAccount(ctx context.Context, requester Requester, label string) (GenMnemonicAndFundResult, error) {
+	namespace, err := a.accountNamespace(requester, label)
+	if err != nil {
+		return GenMnemonicAndFundResult{}, err
+	}
+	account, err := a.namedAccounts.store.NamedAccount(ctx, namespace, label)
+	if err != nil {
+		return GenMnemonicAndFundResult{}, err
+	}
+	return a.decryptNamedAccount(account)
+}
+
+// genNamedAndFund funds the account generated under the label, generating it on first use.
+func (a App) genNamedAndFund(ctx context.Context, requester Requester, label string) (GenMnemonicAndFundResult, error) {
+	namespace, err := a.accountNamespace(requester, label)
+	if err != nil {
+		return GenMnemonicAndFundResult{}, err
+	}
+	account, err := a.namedAccounts.store.NamedAccount(ctx, namespace, label)
+	if errors.Is(err, ErrNamedAccountNotFound) {
+		account, err = a.createNamedAccount(ctx, namespace, label)
+	}
+	if err != nil {
+		return GenMnemonicAndFundResult{}, err
+	}
+	result, err := a.decryptNamedAccount(account)
+	if err != nil {
+		return GenMnemonicAndFundResult{}, err
+	}
+
+	sdkAddr, err := a.validateAddress(result.Address)
+	if err != nil {
+		return GenMnemonicAndFundResult{}, err
+	}
+	if err := a.checkAbuseScore(ctx, requester, sdkAddr); err != nil {
+		return GenMnemonicAndFundResult{}, err
+	}
+	// the account is reused, so it is subject to the cooldown as any other address
+	result.TxHash, err = a.sendWithCooldown(ctx, requester, sdkAddr, a.grantAmount(requester))
+	if err != nil {
+		return GenMnemonicAndFundResult{}, err
+	}
+	return result, nil
+}
+
+func (a App) createNamedAccount(ctx context.Context, namespace, label string) (NamedAccount, error) {
+	sdkAddr, mnemonic, err := chain.GenerateMnemonic()
+	if err != nil {
+		return NamedAccount{}, errors.Wrapf(ErrUnableToTransferToken, "err:%s", err)
+	}
+	encrypted, err := a.namedAccounts.cipher.Encrypt(mnemonic, sdkAddr.String())
+	if err != nil {
+		return NamedAccount{}, err
+	}
+	account, err := a.namedAccounts.store.CreateNamedAccount(ctx, NamedAccount{
+		Namespace:         namespace,
+		Label:             label,
+		Address:           sdkAddr.String(),
+		EncryptedMnemonic: encrypted,
+		CreatedAt:         a.clock.Now().UTC(),
+	})
+	if err != nil {
+		return NamedAccount{}, err
+	}
+	logger.Get(ctx).Info("Named account generated", zap.String("label", label), zap.String("address", account.Address))
+	return account, nil
+}
+
+func (a App) decryptNamedAccount(account NamedAccount) (GenMnemonicAndFundResult, error) {
+	mnemonic, err := a.namedAccounts.cipher.Decrypt(account.EncryptedMnemonic, account.Address)
+	if err != nil {
+		return GenMnemonicAndFundResult{}, err
+	}
+	return GenMnemonicAndFundResult{
+		Mnemonic:  mnemonic,
+		Address:   account.Address,
+		Label:     account.Label,
+		CreatedAt: account.CreatedAt,
+	}, nil
+}
+
+// accountNamespace returns the namespace of the labels of the requester. Labels are namespaced by the holder
+// of the API key, so the clients can't retrieve the accounts of others and rotated keys keep the accounts.
+func (a App) accountNamespace(requester Requester, label string) (string, error) {
+	if !a.NamedAccountsEnabled() {
+		return "", errors.Wrap(ErrNamedAccountsDisabled, "encryption key of the named accounts is not configured")
+	}
+	if !accountLabelRegexp.MatchString(label) {
+		return "", errors.Wrap(ErrInvalidAccountLabel,
+			"label must be 1-128 letters, digits, dots, colons, slashes, dashes or underscores")
+	}
+	if requester.APIKeyHolder == "" {
+		return "", errors.Wrap(ErrAccountLabelUnauthorized, "API key is required to use the label")
+	}
+	return requester.APIKeyHolder, nil
+}
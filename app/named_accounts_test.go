@@ -0,0 +1,116 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+	"github.com/CoreumFoundation/faucet/pkg/secret"
+)
+
+type mockNamedAccounts map[string]NamedAccount
+
+func (m mockNamedAccounts) NamedAccount(_ context.Context, namespace, label string) (NamedAccount, error) {
+	account, ok := m[namespace+"/"+label]
+	if !ok {
+		return NamedAccount{}, errors.WithStack(ErrNamedAccountNotFound)
+	}
+	return account, nil
+}
+
+func (m mockNamedAccounts) CreateNamedAccount(_ context.Context, account NamedAccount) (NamedAccount, error) {
+	if existing, ok := m[account.Namespace+"/"+account.Label]; ok {
+		return existing, nil
+	}
+	m[account.Namespace+"/"+account.Label] = account
+	return account, nil
+}
+
+func TestMnemonicCipher(t *testing.T) {
+	requireT := require.New(t)
+
+	_, err := NewMnemonicCipher("short")
+	requireT.Error(err)
+
+	c, err := NewMnemonicCipher("0123456789abcdef")
+	requireT.NoError(err)
+	encrypted, err := c.Encrypt(secret.New("mnemonic words"), "address1")
+	requireT.NoError(err)
+	requireT.NotContains(string(encrypted), "mnemonic words")
+
+	mnemonic, err := c.Decrypt(encrypted, "address1")
+	requireT.NoError(err)
+	requireT.Equal("mnemonic words", mnemonic.Reveal())
+
+	// ciphertext is bound to the address
+	_, err = c.Decrypt(encrypted, "address2")
+	requireT.Error(err)
+
+	other, err := NewMnemonicCipher("fedcba9876543210")
+	requireT.NoError(err)
+	_, err = other.Decrypt(encrypted, "address1")
+	requireT.Error(err)
+}
+
+func TestGenMnemonicAndFundNamed(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	network.SetSDKConfig()
+
+	cipher, err := NewMnemonicCipher("0123456789abcdef")
+	requireT.NoError(err)
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	a := New(&mockBatcher{txHash: "tx1"}, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{},
+		network, chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clk)
+
+	ci := Requester{APIKeyHolder: "ci"}
+	_, err = a.GenMnemonicAndFund(ctx, ci, "suite-1")
+	requireT.ErrorIs(err, ErrNamedAccountsDisabled)
+
+	a = a.WithNamedAccounts(mockNamedAccounts{}, cipher)
+	_, err = a.GenMnemonicAndFund(ctx, Requester{}, "suite-1")
+	requireT.ErrorIs(err, ErrAccountLabelUnauthorized)
+	_, err = a.GenMnemonicAndFund(ctx, ci, "invalid label")
+	requireT.ErrorIs(err, ErrInvalidAccountLabel)
+
+	first, err := a.GenMnemonicAndFund(ctx, ci, "suite-1")
+	requireT.NoError(err)
+	requireT.Equal("tx1", first.TxHash)
+	requireT.Equal("suite-1", first.Label)
+
+	// the same label gives the same account
+	second, err := a.GenMnemonicAndFund(ctx, ci, "suite-1")
+	requireT.NoError(err)
+	requireT.Equal(first.Address, second.Address)
+	requireT.Equal(first.Mnemonic.Reveal(), second.Mnemonic.Reveal())
+
+	retrieved, err := a.NamedAccount(ctx, ci, "suite-1")
+	requireT.NoError(err)
+	requireT.Equal(first.Address, retrieved.Address)
+	requireT.Equal(first.Mnemonic.Reveal(), retrieved.Mnemonic.Reveal())
+	requireT.Empty(retrieved.TxHash)
+
+	// labels of other holders are separate
+	_, err = a.NamedAccount(ctx, Requester{APIKeyHolder: "partner"}, "suite-1")
+	requireT.ErrorIs(err, ErrNamedAccountNotFound)
+	other, err := a.GenMnemonicAndFund(ctx, Requester{APIKeyHolder: "partner"}, "suite-1")
+	requireT.NoError(err)
+	requireT.NotEqual(first.Address, other.Address)
+
+	// requests without the label generate new accounts
+	unnamed, err := a.GenMnemonicAndFund(ctx, ci, "")
+	requireT.NoError(err)
+	requireT.NotEqual(first.Address, unnamed.Address)
+	requireT.Empty(unnamed.Label)
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+const (
+	// ProofOwnership is the key of the proof of owning the recipient address in Requester.Proofs.
+	ProofOwnership = "ownership"
+	// OwnershipChallengeTTL is how long the client has to sign the challenge and request the funds.
+	OwnershipChallengeTTL = 5 * time.Minute
+)
+
+// OwnershipChallenge is the nonce the client signs by the key of the recipient address before requesting the funds.
+type OwnershipChallenge struct {
+	Challenge string
+	ExpiresAt time.Time
+}
+
+// WithOwnershipProof returns a copy of the app funding only the addresses whose owners sign the issued challenge
+// off-chain as specified by ADR-36, e.g. by Keplr signArbitrary, so the funds go to the wallets the clients
+// control. The clock of the app must be set before. The challenges are signed by the key generated on start,
+// so they are invalidated by restart. Requests authenticated by the admin token, API key or bypass token are
+// exempt, because they are sent by automation.
+func (a App) WithOwnershipProof() (App, error) {
+	challenges, err := newSignedChallenges(OwnershipChallengeTTL, a.clock, ErrOwnershipProofFailed)
+	if err != nil {
+		return App{}, err
+	}
+	a.ownership = challenges
+	return a, nil
+}
+
+// OwnershipProofEnabled tells if the proof of owning the recipient address is required.
+func (a App) OwnershipProofEnabled() bool {
+	return a.ownership != nil
+}
+
+// CreateOwnershipChallenge returns new challenge to be signed by the key of the recipient address.
+func (a App) CreateOwnershipChallenge() (OwnershipChallenge, error) {
+	if a.ownership == nil {
+		return OwnershipChallenge{}, errors.Wrap(ErrOwnershipProofFailed, "proof of ownership is not required")
+	}
+	challenge, expiresAt, err := a.ownership.issue()
+	if err != nil {
+		return OwnershipChallenge{}, err
+	}
+	return OwnershipChallenge{Challenge: challenge, ExpiresAt: expiresAt}, nil
+}
+
+// checkOwnership verifies the proof in the format <challenge>:<public key>:<signature>, where the public key
+// and the ADR-36 signature of the challenge by the address are base64-encoded, as returned by Keplr signArbitrary.
+func (a App) checkOwnership(_ context.Context, requester Requester, address string) error {
+	if a.ownership == nil || requester.Admin || requester.APIKeyHolder != "" || requester.BypassTokenID != "" {
+		return nil
+	}
+	proof := requester.Proofs[ProofOwnership]
+	if proof == "" {
+		return errors.Wrap(ErrOwnershipProofFailed, "signature of the ownership challenge is required")
+	}
+	parts := strings.Split(proof, ":")
+	if len(parts) != 3 {
+		return errors.Wrap(ErrOwnershipProofFailed, "proof must be <challenge>:<public key>:<signature>")
+	}
+	challenge := parts[0]
+	pubKey, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return errors.Wrap(ErrOwnershipProofFailed, "public key must be base64-encoded")
+	}
+	signature, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return errors.Wrap(ErrOwnershipProofFailed, "signature must be base64-encoded")
+	}
+	expiresAt, err := a.ownership.verify(challenge)
+	if err != nil {
+		return err
+	}
+	if err := chain.VerifyADR36Signature(address, []byte(challenge), pubKey, signature); err != nil {
+		return errors.Wrapf(ErrOwnershipProofFailed, "signature of %s: %s", address, err)
+	}
+	return a.ownership.spend(challenge, expiresAt)
+}
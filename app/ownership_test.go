@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+func TestGiveFundsOwnershipProof(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	key := secp256k1.GenPrivKey()
+	address, err := bech32.ConvertAndEncode("devcore", key.PubKey().Address())
+	requireT.NoError(err)
+	pubKey := base64.StdEncoding.EncodeToString(key.PubKey().Bytes())
+	sign := func(signer string, challenge string) string {
+		signature, err := key.Sign(chain.ADR36SignBytes(signer, []byte(challenge)))
+		requireT.NoError(err)
+		return challenge + ":" + pubKey + ":" + base64.StdEncoding.EncodeToString(signature)
+	}
+
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	a := New(&mockBatcher{txHash: "tx1"}, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{},
+		network, chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clk)
+	requireT.False(a.OwnershipProofEnabled())
+	_, err = a.CreateOwnershipChallenge()
+	requireT.ErrorIs(err, ErrOwnershipProofFailed)
+
+	a, err = a.WithOwnershipProof()
+	requireT.NoError(err)
+	requireT.True(a.OwnershipProofEnabled())
+	challenge, err := a.CreateOwnershipChallenge()
+	requireT.NoError(err)
+	requireT.Equal(clk.Now().Add(OwnershipChallengeTTL), challenge.ExpiresAt)
+
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.ErrorIs(err, ErrOwnershipProofFailed)
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofOwnership: challenge.Challenge}}, address)
+	requireT.ErrorIs(err, ErrOwnershipProofFailed)
+	// the key doesn't own another address
+	const other = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofOwnership: sign(other, challenge.Challenge)}}, other)
+	requireT.ErrorIs(err, ErrOwnershipProofFailed)
+	requireT.ErrorContains(err, "public key doesn't belong to the signer")
+	// the signature covers the challenge
+	forged, _, err := a.ownership.issue()
+	requireT.NoError(err)
+	proof := sign(address, forged)
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofOwnership: challenge.Challenge + proof[len(forged):]}},
+		address)
+	requireT.ErrorIs(err, ErrOwnershipProofFailed)
+	requireT.ErrorContains(err, "signature is invalid")
+
+	txHash, err := a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofOwnership: sign(address, challenge.Challenge)}},
+		address)
+	requireT.NoError(err)
+	requireT.Equal("tx1", txHash)
+	// each challenge is used once
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofOwnership: sign(address, challenge.Challenge)}}, address)
+	requireT.ErrorIs(err, ErrOwnershipProofFailed)
+
+	// automation is exempt
+	_, err = a.GiveFunds(ctx, Requester{APIKeyHolder: "ci"}, address)
+	requireT.NoError(err)
+
+	challenge, err = a.CreateOwnershipChallenge()
+	requireT.NoError(err)
+	clk.Advance(OwnershipChallengeTTL)
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofOwnership: sign(address, challenge.Challenge)}}, address)
+	requireT.ErrorIs(err, ErrOwnershipProofFailed)
+}
@@ -0,0 +1,243 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ProofPhone is the key of the ID and the code sent by SMS, joined by ".", in Requester.Proofs.
+	ProofPhone = "phone"
+
+	// maxPhoneCodeAttempts is the number of wrong codes after which the verification is dropped, so the code
+	// can't be guessed.
+	maxPhoneCodeAttempts = 5
+	phoneCodeDigits      = 6
+	minPhoneKeyLength    = 16
+)
+
+// phoneRegexp matches the phone numbers in E.164 format.
+var phoneRegexp = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
+// SMSSender delivers the text messages, e.g. by Twilio.
+type SMSSender interface {
+	SendSMS(ctx context.Context, to, body string) error
+}
+
+// PhoneVerification is the pending code sent to the phone number, the address is funded once the code is presented.
+type PhoneVerification struct {
+	ID      string `json:"id"`
+	Address string `json:"address"`
+	// PhoneKey identifies the phone number without storing it.
+	PhoneKey string `json:"phoneKey"`
+	// CodeHash is the hash of the code and the ID, the code itself isn't stored.
+	CodeHash  string    `json:"codeHash"`
+	Attempts  int       `json:"attempts"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// PhoneVerificationStore persists the pending codes, so each of them is used once.
+type PhoneVerificationStore interface {
+	PutPhoneVerification(ctx context.Context, verification PhoneVerification) error
+	// UsePhoneVerification deletes the verification and returns it if the hash of the code matches. Otherwise it
+	// counts the attempt, deletes the verification once maxAttempts are made and returns ErrPhoneVerificationFailed,
+	// as it does if the verification is unknown, used already or expired at the time.
+	UsePhoneVerification(
+		ctx context.Context,
+		id, codeHash string,
+		maxAttempts int,
+		now time.Time,
+	) (PhoneVerification, error)
+	// DeleteExpiredPhoneVerifications deletes the codes expired at the time and returns the number of deleted ones.
+	DeleteExpiredPhoneVerifications(ctx context.Context, now time.Time) (int, error)
+}
+
+// PhoneVerificationConfig configures the phone verification.
+type PhoneVerificationConfig struct {
+	// HashKey of at least 16 characters keys the HMAC identifying the phone numbers in the stores, so the numbers
+	// can't be recovered from the stored keys by hashing all of them.
+	HashKey string
+	// CodeTTL is how long the code may be used once sent.
+	CodeTTL time.Duration
+	// Quota is the number of addresses each phone number may fund in the QuotaPeriod, or in total if the period
+	// is zero. Zero quota means the phone numbers are not limited.
+	Quota       uint64
+	QuotaPeriod time.Duration
+	// SendQuota is the number of codes sent to each phone number in the SendQuotaPeriod, or in total if the
+	// period is zero, so a number can't be flooded with messages. Zero quota means the sends are not limited.
+	SendQuota       uint64
+	SendQuotaPeriod time.Duration
+}
+
+// PhoneVerificationRequest describes the code sent to the phone number.
+type PhoneVerificationRequest struct {
+	ID        string
+	Address   string
+	ExpiresAt time.Time
+}
+
+type phoneVerifier struct {
+	cfg    PhoneVerificationConfig
+	store  PhoneVerificationStore
+	quotas IdentityStore
+	sender SMSSender
+}
+
+// WithPhoneVerification returns a copy of the app funding the address only if the client presents the code sent
+// by SMS to the phone number, so each grant costs a phone number. The quota of each phone number is tracked
+// by the identity store the same way the quotas of the accounts are. Requests authenticated by the admin token,
+// API key or bypass token are exempt, because they are sent by automation.
+func (a App) WithPhoneVerification(
+	cfg PhoneVerificationConfig,
+	store PhoneVerificationStore,
+	quotas IdentityStore,
+	sender SMSSender,
+) (App, error) {
+	if cfg.CodeTTL <= 0 {
+		return App{}, errors.New("lifetime of the phone code must be positive")
+	}
+	if len(cfg.HashKey) < minPhoneKeyLength {
+		return App{}, errors.Errorf("key of at least %d characters is required to hash the phone numbers",
+			minPhoneKeyLength)
+	}
+	if cfg.QuotaPeriod < 0 || cfg.SendQuotaPeriod < 0 {
+		return App{}, errors.New("period of the phone quota must not be negative")
+	}
+	if store == nil || sender == nil {
+		return App{}, errors.New("store and sender are required to verify phone numbers")
+	}
+	if (cfg.Quota > 0 || cfg.SendQuota > 0) && quotas == nil {
+		return App{}, errors.New("store is required to track the phone quotas")
+	}
+	a.phone = &phoneVerifier{cfg: cfg, store: store, quotas: quotas, sender: sender}
+	return a, nil
+}
+
+// PhoneVerificationEnabled tells if the addresses are funded only with the codes sent by SMS.
+func (a App) PhoneVerificationEnabled() bool {
+	return a.phone != nil
+}
+
+// RequestPhoneVerification sends the code funding the address to the phone number. The client presents the ID
+// of the returned request and the code as the ProofPhone proof. Nothing is sent to the number which has used its
+// quota already or has been sent SendQuota codes in the period.
+func (a App) RequestPhoneVerification(
+	ctx context.Context,
+	address, phone string,
+) (PhoneVerificationRequest, error) {
+	if a.phone == nil {
+		return PhoneVerificationRequest{}, errors.Wrap(ErrPhoneVerificationFailed, "phone verification is not required")
+	}
+	if _, err := a.validateAddress(address); err != nil {
+		return PhoneVerificationRequest{}, err
+	}
+	if !phoneRegexp.MatchString(phone) {
+		return PhoneVerificationRequest{}, errors.Wrapf(ErrInvalidPhone, "phone number must be in E.164 format, got %q",
+			phone)
+	}
+
+	now := a.clock.Now().UTC()
+	key := a.phone.phoneKey(phone)
+	if a.phone.cfg.Quota > 0 {
+		usage, err := a.phone.quotas.IdentityQuotaUsage(ctx, key)
+		if err != nil {
+			return PhoneVerificationRequest{}, err
+		}
+		if err := usage.Check(now, a.phone.cfg.Quota, a.phone.cfg.QuotaPeriod); err != nil {
+			return PhoneVerificationRequest{}, err
+		}
+	}
+	if a.phone.cfg.SendQuota > 0 {
+		_, err := a.phone.quotas.UseIdentityQuota(ctx, "sms:"+key, a.phone.cfg.SendQuota,
+			a.phone.cfg.SendQuotaPeriod, now)
+		if err != nil {
+			return PhoneVerificationRequest{}, err
+		}
+	}
+
+	rawID := make([]byte, 16)
+	if _, err := rand.Read(rawID); err != nil {
+		return PhoneVerificationRequest{}, errors.WithStack(err)
+	}
+	code, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return PhoneVerificationRequest{}, errors.WithStack(err)
+	}
+	verification := PhoneVerification{
+		ID:        hex.EncodeToString(rawID),
+		Address:   address,
+		PhoneKey:  key,
+		CreatedAt: now,
+		ExpiresAt: now.Add(a.phone.cfg.CodeTTL).Truncate(time.Second),
+	}
+	codeStr := fmt.Sprintf("%0*d", phoneCodeDigits, code.Int64())
+	verification.CodeHash = phoneCodeHash(verification.ID, codeStr)
+	if err := a.phone.store.PutPhoneVerification(ctx, verification); err != nil {
+		return PhoneVerificationRequest{}, err
+	}
+	body := fmt.Sprintf("Your faucet code is %s, it expires at %s.", codeStr,
+		verification.ExpiresAt.Format("15:04 MST"))
+	if err := a.phone.sender.SendSMS(ctx, phone, body); err != nil {
+		return PhoneVerificationRequest{}, errors.Wrapf(ErrSMSUnavailable, "err:%s", err)
+	}
+	return PhoneVerificationRequest{
+		ID:        verification.ID,
+		Address:   address,
+		ExpiresAt: verification.ExpiresAt,
+	}, nil
+}
+
+// checkPhoneVerified spends the code sent to the phone number of the client, it must be sent for the address.
+// One funding of the quota of the phone number is consumed once the code is accepted.
+func (a App) checkPhoneVerified(ctx context.Context, requester Requester, address string) error {
+	if a.phone == nil || requester.Admin || requester.APIKeyHolder != "" || requester.BypassTokenID != "" {
+		return nil
+	}
+	proof := requester.Proofs[ProofPhone]
+	if proof == "" {
+		return errors.Wrap(ErrPhoneVerificationFailed, "code sent by SMS is required")
+	}
+	id, code, ok := strings.Cut(proof, ".")
+	if !ok || id == "" || code == "" {
+		return errors.Wrap(ErrPhoneVerificationFailed, "proof must be the ID and the code joined by \".\"")
+	}
+	now := a.clock.Now().UTC()
+	verification, err := a.phone.store.UsePhoneVerification(ctx, id, phoneCodeHash(id, code), maxPhoneCodeAttempts,
+		now)
+	if err != nil {
+		return err
+	}
+	if verification.Address != address {
+		return errors.Wrap(ErrPhoneVerificationFailed, "code is sent for another address")
+	}
+	if a.phone.cfg.Quota == 0 {
+		return nil
+	}
+	_, err = a.phone.quotas.UseIdentityQuota(ctx, verification.PhoneKey, a.phone.cfg.Quota, a.phone.cfg.QuotaPeriod,
+		now)
+	return err
+}
+
+// phoneKey identifies the phone number in the stores, so the numbers aren't kept in plain text. The space of
+// E.164 numbers is small enough to hash all of them, so the hash is keyed.
+func (v *phoneVerifier) phoneKey(phone string) string {
+	mac := hmac.New(sha256.New, []byte(v.cfg.HashKey))
+	mac.Write([]byte(phone))
+	return "phone:" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func phoneCodeHash(id, code string) string {
+	hash := sha256.Sum256([]byte(id + "\x00" + code))
+	return hex.EncodeToString(hash[:])
+}
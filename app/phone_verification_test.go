@@ -0,0 +1,202 @@
+package app
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockPhoneVerificationStore struct {
+	PhoneVerificationStore
+	verifications map[string]PhoneVerification
+}
+
+func (m *mockPhoneVerificationStore) PutPhoneVerification(ctx context.Context, v PhoneVerification) error {
+	m.verifications[v.ID] = v
+	return nil
+}
+
+func (m *mockPhoneVerificationStore) UsePhoneVerification(
+	ctx context.Context,
+	id, codeHash string,
+	maxAttempts int,
+	now time.Time,
+) (PhoneVerification, error) {
+	v, ok := m.verifications[id]
+	if !ok || !now.Before(v.ExpiresAt) {
+		return PhoneVerification{}, errors.Wrap(ErrPhoneVerificationFailed, "code is unknown, used already or expired")
+	}
+	if v.CodeHash != codeHash {
+		v.Attempts++
+		m.verifications[id] = v
+		if v.Attempts >= maxAttempts {
+			delete(m.verifications, id)
+		}
+		return PhoneVerification{}, errors.Wrap(ErrPhoneVerificationFailed, "code is wrong")
+	}
+	delete(m.verifications, id)
+	return v, nil
+}
+
+func (m *mockPhoneVerificationStore) DeleteExpiredPhoneVerifications(ctx context.Context, now time.Time) (int, error) {
+	var deleted int
+	for id, v := range m.verifications {
+		if !now.Before(v.ExpiresAt) {
+			delete(m.verifications, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+type mockSMSSender struct {
+	sent map[string][]string
+	err  error
+}
+
+func (m *mockSMSSender) SendSMS(ctx context.Context, to, body string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent[to] = append(m.sent[to], body)
+	return nil
+}
+
+var codeRegexp = regexp.MustCompile(`\d{6}`)
+
+func TestPhoneVerification(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+	const other = "devcore1kdxhx3vv6n0g4ffgdqp8y7e44q7rzjvvlvlg3q"
+	const phone = "+14155552671"
+
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	a := New(&mockBatcher{txHash: "tx1"}, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{},
+		network, chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clk)
+	requireT.False(a.PhoneVerificationEnabled())
+
+	store := &mockPhoneVerificationStore{verifications: map[string]PhoneVerification{}}
+	quotas := mockIdentityStore{}
+	sender := &mockSMSSender{sent: map[string][]string{}}
+	cfg := PhoneVerificationConfig{
+		HashKey:         "0123456789abcdef",
+		CodeTTL:         10 * time.Minute,
+		Quota:           1,
+		QuotaPeriod:     24 * time.Hour,
+		SendQuota:       2,
+		SendQuotaPeriod: time.Hour,
+	}
+	_, err = a.WithPhoneVerification(PhoneVerificationConfig{HashKey: cfg.HashKey}, store, quotas, sender)
+	requireT.Error(err)
+	_, err = a.WithPhoneVerification(PhoneVerificationConfig{CodeTTL: time.Minute}, store, quotas, sender)
+	requireT.Error(err)
+	_, err = a.WithPhoneVerification(cfg, store, nil, sender)
+	requireT.Error(err)
+	a, err = a.WithPhoneVerification(cfg, store, quotas, sender)
+	requireT.NoError(err)
+	requireT.True(a.PhoneVerificationEnabled())
+
+	for _, invalid := range []string{"14155552671", "+0155552671", "+1 415 555 2671", "+1415"} {
+		_, err = a.RequestPhoneVerification(ctx, address, invalid)
+		requireT.ErrorIs(err, ErrInvalidPhone, invalid)
+	}
+	_, err = a.RequestPhoneVerification(ctx, "devcore1invalid", phone)
+	requireT.ErrorIs(err, ErrInvalidAddressFormat)
+
+	request, err := a.RequestPhoneVerification(ctx, address, phone)
+	requireT.NoError(err)
+	requireT.Equal(clk.Now().Add(10*time.Minute), request.ExpiresAt)
+	requireT.Len(sender.sent[phone], 1)
+	code := codeRegexp.FindString(sender.sent[phone][0])
+	requireT.NotEmpty(code)
+	for _, v := range store.verifications {
+		requireT.NotContains(v.PhoneKey, phone)
+		requireT.NotEqual(code, v.CodeHash)
+	}
+
+	// the address is funded only with the code
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.ErrorIs(err, ErrPhoneVerificationFailed)
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofPhone: code}}, address)
+	requireT.ErrorIs(err, ErrPhoneVerificationFailed)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "000001"
+	}
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofPhone: request.ID + "." + wrong}}, address)
+	requireT.ErrorIs(err, ErrPhoneVerificationFailed)
+
+	txHash, err := a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofPhone: request.ID + "." + code}}, address)
+	requireT.NoError(err)
+	requireT.Equal("tx1", txHash)
+	// each code is used once
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofPhone: request.ID + "." + code}}, address)
+	requireT.ErrorIs(err, ErrPhoneVerificationFailed)
+
+	// the code is bound to the address
+	request, err = a.RequestPhoneVerification(ctx, address, "+442071838750")
+	requireT.NoError(err)
+	code = codeRegexp.FindString(sender.sent["+442071838750"][0])
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofPhone: request.ID + "." + code}}, other)
+	requireT.ErrorIs(err, ErrPhoneVerificationFailed)
+
+	// the phone number funds one address a day, no more codes are sent to it until then
+	_, err = a.RequestPhoneVerification(ctx, other, phone)
+	requireT.ErrorIs(err, ErrIdentityQuotaExhausted)
+	var throttled ThrottledError
+	requireT.ErrorAs(err, &throttled)
+	requireT.Equal(clk.Now().Add(24*time.Hour), throttled.NextAvailableAt)
+	requireT.Len(sender.sent[phone], 1)
+
+	// the phone number is sent two codes an hour
+	for i := 0; i < 2; i++ {
+		_, err = a.RequestPhoneVerification(ctx, other, "+33142685300")
+		requireT.NoError(err)
+	}
+	_, err = a.RequestPhoneVerification(ctx, other, "+33142685300")
+	requireT.ErrorIs(err, ErrIdentityQuotaExhausted)
+	requireT.Len(sender.sent["+33142685300"], 2)
+
+	// automation is exempt
+	_, err = a.GiveFunds(ctx, Requester{APIKeyHolder: "ci"}, other)
+	requireT.NoError(err)
+
+	// the code is dropped after too many wrong attempts
+	request, err = a.RequestPhoneVerification(ctx, other, "+61291234567")
+	requireT.NoError(err)
+	code = codeRegexp.FindString(sender.sent["+61291234567"][0])
+	for i := 0; i < maxPhoneCodeAttempts; i++ {
+		wrong := "00000" + string(rune('0'+i))
+		if wrong == code {
+			wrong = "999999"
+		}
+		_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofPhone: request.ID + "." + wrong}}, other)
+		requireT.ErrorIs(err, ErrPhoneVerificationFailed)
+	}
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofPhone: request.ID + "." + code}}, other)
+	requireT.ErrorIs(err, ErrPhoneVerificationFailed)
+
+	sender.err = errors.New("carrier rejected")
+	_, err = a.RequestPhoneVerification(ctx, address, "+819012345678")
+	requireT.ErrorIs(err, ErrSMSUnavailable)
+
+	// the codes not used or not delivered are collected once expired
+	clk.Advance(11 * time.Minute)
+	collected, err := a.CollectGarbage(ctx)
+	requireT.NoError(err)
+	requireT.Equal(3, collected[ExpiringPhoneCode])
+}
@@ -0,0 +1,124 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ProofPoW is the name of the proof-of-work verifier and the key of the solution in Requester.Proofs.
+	ProofPoW = "pow"
+	// PoWChallengeTTL is how long the client has to solve the challenge and request the funds.
+	PoWChallengeTTL = 5 * time.Minute
+	// MaxPoWDifficulty is the maximal number of leading zero bits required, solving it takes hours already.
+	MaxPoWDifficulty = 32
+	// maxPoWSolutionLength bounds the data hashed on verification.
+	maxPoWSolutionLength = 64
+)
+
+// PoWChallenge is the puzzle the client solves before requesting the funds. The solution is any string of up to
+// 64 characters such that SHA-256 of <challenge>:<solution> starts with Difficulty zero bits.
+type PoWChallenge struct {
+	Challenge  string
+	Difficulty int
+	ExpiresAt  time.Time
+}
+
+// powVerifier checks the solutions of the challenges it issues.
+type powVerifier struct {
+	difficulty int
+	challenges *signedChallenges
+}
+
+// WithProofOfWork returns a copy of the app funding only the clients presenting the solution of the proof-of-work
+// challenge of the difficulty, so CLI users are protected from bots without captcha. The clock of the app must be
+// set before. The challenges are signed by the key generated on start, so they are invalidated by restart.
+func (a App) WithProofOfWork(difficulty int) (App, error) {
+	if difficulty < 1 || difficulty > MaxPoWDifficulty {
+		return App{}, errors.Errorf("proof-of-work difficulty must be between 1 and %d, got %d",
+			MaxPoWDifficulty, difficulty)
+	}
+	challenges, err := newSignedChallenges(PoWChallengeTTL, a.clock, ErrPoWFailed)
+	if err != nil {
+		return App{}, err
+	}
+	a.pow = &powVerifier{difficulty: difficulty, challenges: challenges}
+	return a.WithVerifiers(a.pow), nil
+}
+
+// PoWEnabled tells if the proof-of-work challenge is required.
+func (a App) PoWEnabled() bool {
+	return a.pow != nil
+}
+
+// CreatePoWChallenge returns new proof-of-work challenge.
+func (a App) CreatePoWChallenge() (PoWChallenge, error) {
+	if a.pow == nil {
+		return PoWChallenge{}, errors.Wrap(ErrPoWFailed, "proof of work is not required")
+	}
+	return a.pow.create()
+}
+
+func (p *powVerifier) create() (PoWChallenge, error) {
+	challenge, expiresAt, err := p.challenges.issue()
+	if err != nil {
+		return PoWChallenge{}, err
+	}
+	return PoWChallenge{
+		Challenge:  challenge,
+		Difficulty: p.difficulty,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+func (p *powVerifier) Name() string {
+	return ProofPoW
+}
+
+// Verify checks the proof in the format <challenge>:<solution>.
+func (p *powVerifier) Verify(_ context.Context, proof string, _ Requester) error {
+	if proof == "" {
+		return errors.Wrap(ErrPoWFailed, "solution of the proof-of-work challenge is required")
+	}
+	challenge, solution, found := strings.Cut(proof, ":")
+	if !found || len(solution) > maxPoWSolutionLength {
+		return errors.Wrap(ErrPoWFailed, "proof must be <challenge>:<solution>")
+	}
+	expiresAt, err := p.challenges.verify(challenge)
+	if err != nil {
+		return err
+	}
+	if PoWLeadingZeros(challenge, solution) < p.difficulty {
+		return errors.Wrapf(ErrPoWFailed, "solution doesn't reach difficulty %d", p.difficulty)
+	}
+	return p.challenges.spend(challenge, expiresAt)
+}
+
+// PoWLeadingZeros returns the number of leading zero bits of SHA-256 of <challenge>:<solution>.
+func PoWLeadingZeros(challenge, solution string) int {
+	sum := sha256.Sum256([]byte(challenge + ":" + solution))
+	zeros := 0
+	for _, b := range sum {
+		if b != 0 {
+			return zeros + bits.LeadingZeros8(b)
+		}
+		zeros += 8
+	}
+	return zeros
+}
+
+// SolvePoW returns the solution of the challenge, it is what the client does before requesting the funds.
+func SolvePoW(challenge string, difficulty int) string {
+	for i := 0; ; i++ {
+		solution := strconv.Itoa(i)
+		if PoWLeadingZeros(challenge, solution) >= difficulty {
+			return solution
+		}
+	}
+}
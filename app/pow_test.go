@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+func TestGiveFundsProofOfWork(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	a := New(&mockBatcher{txHash: "tx1"}, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{},
+		network, chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clk)
+	requireT.False(a.PoWEnabled())
+	_, err = a.WithProofOfWork(MaxPoWDifficulty + 1)
+	requireT.Error(err)
+
+	a, err = a.WithProofOfWork(8)
+	requireT.NoError(err)
+	requireT.True(a.PoWEnabled())
+
+	challenge, err := a.CreatePoWChallenge()
+	requireT.NoError(err)
+	requireT.Equal(8, challenge.Difficulty)
+	requireT.Equal(clk.Now().Add(PoWChallengeTTL), challenge.ExpiresAt)
+	solution := SolvePoW(challenge.Challenge, challenge.Difficulty)
+	requireT.GreaterOrEqual(PoWLeadingZeros(challenge.Challenge, solution), 8)
+
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.ErrorIs(err, ErrPoWFailed)
+	wrong := solution + "x"
+	for PoWLeadingZeros(challenge.Challenge, wrong) >= 8 {
+		wrong += "x"
+	}
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofPoW: challenge.Challenge + ":" + wrong}}, address)
+	requireT.ErrorIs(err, ErrPoWFailed)
+	// challenges not issued by the faucet are rejected even if solved
+	forged := strings.Replace(challenge.Challenge, ".", ".0", 1)
+	_, err = a.GiveFunds(ctx, Requester{
+		Proofs: Proofs{ProofPoW: forged + ":" + SolvePoW(forged, challenge.Difficulty)},
+	}, address)
+	requireT.ErrorIs(err, ErrPoWFailed)
+
+	proof := Proofs{ProofPoW: challenge.Challenge + ":" + solution}
+	txHash, err := a.GiveFunds(ctx, Requester{Proofs: proof}, address)
+	requireT.NoError(err)
+	requireT.Equal("tx1", txHash)
+	// each challenge is used once
+	_, err = a.GiveFunds(ctx, Requester{Proofs: proof}, address)
+	requireT.ErrorIs(err, ErrPoWFailed)
+
+	challenge, err = a.CreatePoWChallenge()
+	requireT.NoError(err)
+	solution = SolvePoW(challenge.Challenge, challenge.Difficulty)
+	clk.Advance(PoWChallengeTTL)
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{ProofPoW: challenge.Challenge + ":" + solution}}, address)
+	requireT.ErrorIs(err, ErrPoWFailed)
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+const preflightRetryInterval = 30 * time.Second
+
+// ErrPreflightPending is reported by the preflight until the first simulation succeeds.
+var ErrPreflightPending = errors.New("preflight simulation hasn't succeeded yet")
+
+// preflightAddress is the dummy recipient of simulated transfers. Nobody holds its key, but it doesn't matter
+// because nothing is ever sent to it.
+var preflightAddress = func() chain.AccAddress {
+	hash := sha256.Sum256([]byte("faucet-preflight"))
+	return chain.AccAddress(hash[:20])
+}()
+
+// TransferSimulator builds, signs and simulates the transfer without broadcasting it.
+type TransferSimulator interface {
+	SimulateTransfer(
+		ctx context.Context,
+		fromAddress chain.AccAddress,
+		feeDenom string,
+		destAddress chain.AccAddress,
+		amount chain.Coin,
+	) error
+}
+
+// NewPreflight returns the preflight simulating the transfer of the amount from each of the funding accounts
+// paying the fee in each of the fee denoms. Empty denom means the gas price denom of the chain.
+func NewPreflight(
+	simulator TransferSimulator,
+	addresses []chain.AccAddress,
+	amount chain.Coin,
+	feeDenoms ...string,
+) *Preflight {
+	return &Preflight{
+		simulator: simulator,
+		addresses: addresses,
+		amount:    amount,
+		feeDenoms: feeDenoms,
+		err:       ErrPreflightPending,
+	}
+}
+
+// Preflight verifies the fees, denom and state of the funding accounts end-to-end before the faucet is reported
+// ready, so misconfiguration is caught before the first real request.
+type Preflight struct {
+	simulator TransferSimulator
+	addresses []chain.AccAddress
+	amount    chain.Coin
+	feeDenoms []string
+
+	mu  sync.RWMutex
+	err error
+}
+
+// Ready returns nil once the simulation succeeded, otherwise the error of the last attempt.
+// Nil preflight is always ready.
+func (p *Preflight) Ready() error {
+	if p == nil {
+		return nil
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.err
+}
+
+// Run simulates the transfers until they succeed, then waits for the context to be canceled.
+func (p *Preflight) Run(ctx context.Context) error {
+	log := logger.Get(ctx)
+	for {
+		err := p.check(ctx)
+		p.mu.Lock()
+		p.err = err
+		p.mu.Unlock()
+		if err == nil {
+			log.Info("Preflight simulation succeeded")
+			<-ctx.Done()
+			return errors.WithStack(ctx.Err())
+		}
+
+		log.Error("Preflight simulation failed", zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return errors.WithStack(ctx.Err())
+		case <-time.After(preflightRetryInterval):
+		}
+	}
+}
+
+func (p *Preflight) check(ctx context.Context) error {
+	feeDenoms := p.feeDenoms
+	if len(feeDenoms) == 0 {
+		feeDenoms = []string{""}
+	}
+	for _, address := range p.addresses {
+		for _, feeDenom := range feeDenoms {
+			if err := p.simulator.SimulateTransfer(ctx, address, feeDenom, preflightAddress, p.amount); err != nil {
+				return errors.Wrapf(err, "simulating transfer from %s with fee denom %q failed", address, feeDenom)
+			}
+		}
+	}
+	return nil
+}
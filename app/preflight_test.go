@@ -0,0 +1,61 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+type mockSimulator struct {
+	failingDenom string
+	simulated    []string
+}
+
+func (m *mockSimulator) SimulateTransfer(
+	ctx context.Context,
+	fromAddress chain.AccAddress,
+	feeDenom string,
+	destAddress chain.AccAddress,
+	amount chain.Coin,
+) error {
+	m.simulated = append(m.simulated, fromAddress.String()+"/"+feeDenom)
+	if feeDenom == m.failingDenom {
+		return errors.New("insufficient funds")
+	}
+	return nil
+}
+
+func TestPreflight(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+	addr1 := chain.AccAddress([]byte("address1address1addr"))
+	addr2 := chain.AccAddress([]byte("address2address2addr"))
+	simulator := &mockSimulator{failingDenom: "ufee"}
+	preflight := NewPreflight(simulator, []chain.AccAddress{addr1, addr2}, chain.NewCoin("ucore", chain.NewInt(10)), "", "ufee")
+
+	requireT.ErrorIs(preflight.Ready(), ErrPreflightPending)
+
+	// the first failure stops the check
+	err := preflight.check(ctx)
+	requireT.Error(err)
+	assertT.Contains(err.Error(), "ufee")
+	assertT.Equal([]string{addr1.String() + "/", addr1.String() + "/ufee"}, simulator.simulated)
+
+	simulator.failingDenom = "none"
+	simulator.simulated = nil
+	requireT.NoError(preflight.check(ctx))
+	assertT.Len(simulator.simulated, 4)
+
+	var nilPreflight *Preflight
+	assertT.NoError(nilPreflight.Ready())
+}
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"strings"
+)
+
+// QRAddressPlaceholder is replaced with the address in the wallet deep link template.
+const QRAddressPlaceholder = "{address}"
+
+// WithQRLinkTemplate returns a copy of the app rendering the wallet deep links into the QR codes on request.
+// The template must contain QRAddressPlaceholder, e.g. https://wallet.example.com/send?to={address}.
+func (a App) WithQRLinkTemplate(template string) App {
+	a.qrLinkTemplate = template
+	return a
+}
+
+// QRLinkEnabled tells if the QR codes may encode the wallet deep link instead of the plain address.
+func (a App) QRLinkEnabled() bool {
+	return a.qrLinkTemplate != ""
+}
+
+// QRContent validates the address and returns the content of its QR code, the wallet deep link of the address
+// if link is set.
+func (a App) QRContent(address string, link bool) (string, error) {
+	if _, err := a.validateAddress(address); err != nil {
+		return "", err
+	}
+	// bech32 addresses may be uppercase, wallets expect the canonical lowercase form
+	content := strings.ToLower(address)
+	if !link {
+		return content, nil
+	}
+	if a.qrLinkTemplate == "" {
+		return "", ErrQRLinkUnavailable
+	}
+	return strings.ReplaceAll(a.qrLinkTemplate, QRAddressPlaceholder, content), nil
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+func TestQRContent(t *testing.T) {
+	requireT := require.New(t)
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+
+	a := New(&mockBatcher{}, nil, nil, nil, nil, network, chain.Coin{})
+	requireT.False(a.QRLinkEnabled())
+
+	content, err := a.QRContent("DEVCORE10KRRRQXXY948N5P9XVWGQ6KRGY9HG5G8SVAZ62", false)
+	requireT.NoError(err)
+	requireT.Equal(address, content)
+	_, err = a.QRContent(address, true)
+	requireT.ErrorIs(err, ErrQRLinkUnavailable)
+	_, err = a.QRContent("invalid", false)
+	requireT.ErrorIs(err, ErrInvalidAddressFormat)
+	_, err = a.QRContent("core10krrrqxxy948n5p9xvwgq6krgy9hg5g8zjsq5k", false)
+	requireT.ErrorIs(err, ErrAddressPrefixUnsupported)
+
+	a = a.WithQRLinkTemplate("https://wallet.example.com/send?to=" + QRAddressPlaceholder)
+	requireT.True(a.QRLinkEnabled())
+	content, err = a.QRContent(address, true)
+	requireT.NoError(err)
+	requireT.Equal("https://wallet.example.com/send?to="+address, content)
+}
@@ -0,0 +1,38 @@
+package app
+
+import (
+	"context"
+	"time"
+)
+
+// QueryStore serves the reads of the history and the ledger made by statistics, reports and reconciliation.
+// None of them is on the funding path, so they may be served by the read replica lagging behind the primary store.
+type QueryStore interface {
+	FundingsSince(ctx context.Context, since time.Time) ([]FundingRecord, error)
+	IncidentsSince(ctx context.Context, since time.Time) ([]Incident, error)
+	LedgerTransactionsSince(ctx context.Context, since time.Time) ([]LedgerTransaction, error)
+}
+
+// WithQueryStore returns a copy of the app serving statistics and reports from the store, e.g. the read replica.
+func (a App) WithQueryStore(store QueryStore) App {
+	a.queryStore = store
+	return a
+}
+
+// queries returns the store serving the reads, the primary stores are used if no query store is set.
+func (a App) queries() QueryStore {
+	if a.queryStore != nil {
+		return a.queryStore
+	}
+	return primaryQueries{HistoryStore: a.history, LedgerStore: a.ledger}
+}
+
+type primaryQueries struct {
+	HistoryStore
+	LedgerStore
+}
+
+// FundingsSince returns the fundings recorded since the given time, e.g. to export them.
+func (a App) FundingsSince(ctx context.Context, since time.Time) ([]FundingRecord, error) {
+	return a.queries().FundingsSince(ctx, since)
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// AddressQuota describes how much the address may still be funded by the requester.
+type AddressQuota struct {
+	Address string
+	// Amount is the amount sent by the next request.
+	Amount chain.Coin
+	// Remaining is the number of requests the address may still be funded by, nil if it is not limited.
+	// The address may be funded once per cooldown period and up to its lifetime cap.
+	Remaining *uint64
+	// LifetimeRemaining is the amount the address may still receive before reaching the lifetime cap, nil if
+	// there is no cap.
+	LifetimeRemaining *chain.Coin
+	// Blocked tells if the address is blocked by the operator.
+	Blocked bool
+	// NextAllowedAt is the time the address may be funded again, zero if it is never funded again.
+	NextAllowedAt time.Time
+}
+
+// AddressQuota returns the quota of the address, without consuming any of it. The quota tells the clients when
+// they may request the funds again, the actual request is still checked against the limits.
+func (a App) AddressQuota(ctx context.Context, requester Requester, address string) (AddressQuota, error) {
+	sdkAddr, err := a.validateAddress(address)
+	if err != nil {
+		return AddressQuota{}, err
+	}
+	now := a.clock.Now().UTC()
+	quota := AddressQuota{
+		Address:       sdkAddr.String(),
+		Amount:        a.grantAmount(requester),
+		NextAllowedAt: now,
+	}
+
+	if err := a.checkBlocked(ctx, sdkAddr); err != nil {
+		if !errors.Is(err, ErrAddressBlocked) {
+			return AddressQuota{}, err
+		}
+		quota.Blocked = true
+		quota.Remaining = uint64Ptr(0)
+		quota.NextAllowedAt = time.Time{}
+		return quota, nil
+	}
+
+	if a.cooldown.enabled(requester) {
+		fundedAt, err := a.cooldown.store.AddressFundedAt(ctx, sdkAddr)
+		if err != nil {
+			return AddressQuota{}, err
+		}
+		if availableAt := fundedAt.Add(a.cooldown.period); availableAt.After(now) {
+			quota.NextAllowedAt = availableAt.UTC()
+			quota.Remaining = uint64Ptr(0)
+		} else {
+			quota.Remaining = uint64Ptr(1)
+		}
+	}
+
+	c := a.lifetimeCap
+	if c.store != nil && !requester.Admin && c.limit.Amount.IsPositive() && c.limit.Denom == quota.Amount.Denom {
+		total, err := c.store.AddressTotal(ctx, sdkAddr)
+		if err != nil {
+			return AddressQuota{}, err
+		}
+		remaining := c.limit.Amount.Sub(total.Amount.AmountOf(c.limit.Denom))
+		if remaining.IsNegative() {
+			remaining = chain.NewInt(0)
+		}
+		lifetimeRemaining := chain.NewCoin(c.limit.Denom, remaining)
+		quota.LifetimeRemaining = &lifetimeRemaining
+
+		requests := remaining.Quo(quota.Amount.Amount).Uint64()
+		if quota.Remaining == nil || requests < *quota.Remaining {
+			quota.Remaining = &requests
+		}
+		if requests == 0 {
+			// the cap is never renewed
+			quota.NextAllowedAt = time.Time{}
+		}
+	}
+	return quota, nil
+}
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
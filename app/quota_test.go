@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+func TestAddressQuota(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewManual(now)
+	a := New(&mockBatcher{txHash: "tx1"}, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{},
+		network, chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clk)
+
+	// nothing limits the address
+	quota, err := a.AddressQuota(ctx, Requester{}, address)
+	requireT.NoError(err)
+	requireT.Nil(quota.Remaining)
+	requireT.Nil(quota.LifetimeRemaining)
+	requireT.Equal(now, quota.NextAllowedAt)
+	requireT.Equal("1000udevcore", quota.Amount.String())
+
+	a = a.WithAddressCooldown(mockCooldowns{}, time.Hour).
+		WithLifetimeCap(&mockAddressTotals{}, chain.NewCoin("udevcore", chain.NewInt(2500)))
+	quota, err = a.AddressQuota(ctx, Requester{}, address)
+	requireT.NoError(err)
+	requireT.EqualValues(1, *quota.Remaining)
+	requireT.Equal("2500udevcore", quota.LifetimeRemaining.String())
+	requireT.Equal(now, quota.NextAllowedAt)
+
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.NoError(err)
+	quota, err = a.AddressQuota(ctx, Requester{}, address)
+	requireT.NoError(err)
+	requireT.EqualValues(0, *quota.Remaining)
+	requireT.Equal("1500udevcore", quota.LifetimeRemaining.String())
+	requireT.Equal(now.Add(time.Hour), quota.NextAllowedAt)
+
+	// the cap allows one more request
+	clk.Advance(time.Hour)
+	_, err = a.GiveFunds(ctx, Requester{}, address)
+	requireT.NoError(err)
+	clk.Advance(time.Hour)
+	quota, err = a.AddressQuota(ctx, Requester{}, address)
+	requireT.NoError(err)
+	requireT.EqualValues(0, *quota.Remaining)
+	requireT.Equal("500udevcore", quota.LifetimeRemaining.String())
+	requireT.True(quota.NextAllowedAt.IsZero())
+
+	// admin is subject to neither the cooldown nor the cap
+	quota, err = a.AddressQuota(ctx, Requester{Admin: true}, address)
+	requireT.NoError(err)
+	requireT.Nil(quota.Remaining)
+
+	a = a.WithBlocklist(mockBlocklist{})
+	_, err = a.BlockAddress(ctx, address, "abuse")
+	requireT.NoError(err)
+	quota, err = a.AddressQuota(ctx, Requester{}, address)
+	requireT.NoError(err)
+	requireT.True(quota.Blocked)
+	requireT.EqualValues(0, *quota.Remaining)
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+// signedChallenges issues the challenges signed by the key generated on start, so they don't need to be stored
+// until they are used and are invalidated by restart. Only the used ones are remembered, so each is used once.
+// Errors are wrapped by the error of the verifier using the challenges.
+type signedChallenges struct {
+	key   []byte
+	ttl   time.Duration
+	clock clock.Clock
+	err   error
+
+	mu    sync.Mutex
+	spent map[string]time.Time
+}
+
+func newSignedChallenges(ttl time.Duration, clk clock.Clock, err error) (*signedChallenges, error) {
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &signedChallenges{key: key, ttl: ttl, clock: clk, err: err, spent: map[string]time.Time{}}, nil
+}
+
+// issue returns new challenge and its expiry.
+func (s *signedChallenges) issue() (string, time.Time, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", time.Time{}, errors.WithStack(err)
+	}
+	expiresAt := s.clock.Now().UTC().Add(s.ttl).Truncate(time.Second)
+	payload := strconv.FormatInt(expiresAt.Unix(), 10) + "." + hex.EncodeToString(nonce)
+	return payload + "." + s.sign(payload), expiresAt, nil
+}
+
+func (s *signedChallenges) sign(payload string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// verify checks the challenge is issued by the faucet and not expired, it returns its expiry.
+func (s *signedChallenges) verify(challenge string) (time.Time, error) {
+	parts := strings.Split(challenge, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.Wrap(s.err, "malformed challenge")
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.sign(payload))) {
+		return time.Time{}, errors.Wrap(s.err, "challenge is not issued by the faucet")
+	}
+	expiry, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Wrap(s.err, "malformed challenge")
+	}
+	expiresAt := time.Unix(expiry, 0).UTC()
+	if !s.clock.Now().UTC().Before(expiresAt) {
+		return time.Time{}, errors.Wrap(s.err, "challenge is expired")
+	}
+	return expiresAt, nil
+}
+
+// spend marks the verified challenge as used, it fails if it is used already.
+func (s *signedChallenges) spend(challenge string, expiresAt time.Time) error {
+	now := s.clock.Now().UTC()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for spent, expiry := range s.spent {
+		if !now.Before(expiry) {
+			delete(s.spent, spent)
+		}
+	}
+	if _, spent := s.spent[challenge]; spent {
+		return errors.Wrap(s.err, "challenge is used already")
+	}
+	s.spent[challenge] = expiresAt
+	return nil
+}
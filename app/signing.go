@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+)
+
+// signingRetryInterval is how long funding requests are rejected after signing failed, before signing is retried.
+const signingRetryInterval = 15 * time.Second
+
+// SigningFailure is implemented by the errors of chain adapters reporting that the transaction couldn't be signed,
+// e.g. because the key backend is unavailable.
+type SigningFailure interface {
+	SigningFailure() bool
+}
+
+// isSigningFailure tells if the error is caused by the signer.
+func isSigningFailure(err error) bool {
+	var failure SigningFailure
+	return errors.As(err, &failure) && failure.SigningFailure()
+}
+
+// signingStatus tracks availability of the signer. Once signing fails, funding requests are rejected at once
+// until the retry interval elapses, the next request then tries to sign again. Endpoints which don't sign
+// anything keep serving.
+type signingStatus struct {
+	mu               sync.RWMutex
+	unavailableSince time.Time
+	retryAt          time.Time
+	cause            error
+}
+
+// check returns the error if signing is known to be unavailable.
+func (s *signingStatus) check(now time.Time) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.unavailableSince.IsZero() || !now.Before(s.retryAt) {
+		return nil
+	}
+	return s.errorLocked()
+}
+
+// observe records the result of the transfer.
+func (s *signingStatus) observe(ctx context.Context, now time.Time, err error) {
+	if s == nil {
+		return
+	}
+	failed := err != nil && isSigningFailure(err)
+	if err != nil && !failed {
+		// other failures, e.g. of the chain, tell nothing about the signer
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !failed {
+		if !s.unavailableSince.IsZero() {
+			logger.Get(ctx).Info("Signing is available again", zap.Duration("outage", now.Sub(s.unavailableSince)))
+		}
+		s.unavailableSince = time.Time{}
+		s.cause = nil
+		return
+	}
+	if s.unavailableSince.IsZero() {
+		logger.Get(ctx).Error("Signing is unavailable, funding is suspended", zap.Error(err))
+		s.unavailableSince = now
+	}
+	s.retryAt = now.Add(signingRetryInterval)
+	s.cause = err
+}
+
+func (s *signingStatus) errorLocked() error {
+	return ThrottledError{
+		Cause:           errors.Wrapf(ErrSigningUnavailable, "since %s: %s", s.unavailableSince.Format(time.RFC3339), s.cause),
+		NextAvailableAt: s.retryAt.UTC(),
+	}
+}
+
+// SigningStatus returns the error if funding is suspended because the signer is unavailable, nil otherwise.
+func (a App) SigningStatus() error {
+	if a.signing == nil {
+		return nil
+	}
+	a.signing.mu.RLock()
+	defer a.signing.mu.RUnlock()
+
+	if a.signing.unavailableSince.IsZero() {
+		return nil
+	}
+	return a.signing.errorLocked()
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/attribution"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockSigningError struct{}
+
+func (mockSigningError) Error() string {
+	return "key backend unavailable"
+}
+
+func (mockSigningError) SigningFailure() bool {
+	return true
+}
+
+type mockBatcher struct {
+	txHash string
+	err    error
+	calls  int
+	attr   attribution.Attribution
+}
+
+func (m *mockBatcher) SendToken(
+	ctx context.Context,
+	destAddress chain.AccAddress,
+	amount chain.Coin,
+	feeDenom string,
+	attr attribution.Attribution,
+) (string, chain.Coin, error) {
+	m.calls++
+	m.attr = attr
+	return m.txHash, chain.Coin{}, m.err
+}
+
+func (m *mockBatcher) Backlog() (int, time.Duration) {
+	return 0, 0
+}
+
+func TestSigningUnavailable(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	clk := clock.NewOffset()
+	batcher := &mockBatcher{err: errors.WithStack(mockSigningError{})}
+	a := New(batcher, nil, NewTxTracker(nil, 1, nil, clk), &mockHistory{}, nil, chain.Network{}, chain.Coin{}).WithClock(clk)
+	amount := chain.NewCoin("ucore", chain.NewInt(10))
+	requireT.NoError(a.SigningStatus())
+
+	_, err := a.send(ctx, Requester{}, chain.AccAddress{}, amount)
+	requireT.ErrorIs(err, ErrSigningUnavailable)
+	var throttled ThrottledError
+	requireT.ErrorAs(err, &throttled)
+	requireT.ErrorIs(a.SigningStatus(), ErrSigningUnavailable)
+
+	// requests are rejected without trying to sign until the retry interval elapses
+	_, err = a.send(ctx, Requester{}, chain.AccAddress{}, amount)
+	requireT.ErrorIs(err, ErrSigningUnavailable)
+	requireT.Equal(1, batcher.calls)
+
+	// failure of the chain doesn't tell anything about the signer
+	clk.Advance(signingRetryInterval)
+	batcher.err = errors.New("connection refused")
+	_, err = a.send(ctx, Requester{}, chain.AccAddress{}, amount)
+	requireT.ErrorIs(err, ErrUnableToTransferToken)
+	requireT.Equal(2, batcher.calls)
+	requireT.ErrorIs(a.SigningStatus(), ErrSigningUnavailable)
+}
+
+func TestSigningStatusRecovery(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	s := &signingStatus{}
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.observe(ctx, now, mockSigningError{})
+	requireT.ErrorIs(s.check(now.Add(time.Second)), ErrSigningUnavailable)
+	requireT.NoError(s.check(now.Add(signingRetryInterval)))
+
+	s.observe(ctx, now.Add(signingRetryInterval), nil)
+	requireT.NoError(s.check(now.Add(signingRetryInterval)))
+	requireT.True(s.unavailableSince.IsZero())
+}
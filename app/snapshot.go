@@ -0,0 +1,99 @@
+package app
+
+import (
+	"sort"
+	"time"
+)
+
+// Breaker states.
+const (
+	// BreakerClosed means requests pass through.
+	BreakerClosed = "closed"
+	// BreakerOpen means requests are rejected at once until the retry time.
+	BreakerOpen = "open"
+)
+
+// BreakerSigning is the breaker suspending funding while the signer is unavailable.
+const BreakerSigning = "signing"
+
+// Snapshot is the in-memory state of the app at the moment, intended for debugging during incidents.
+type Snapshot struct {
+	Time time.Time
+	// QueueDepth is the number of funding requests waiting for the result.
+	QueueDepth int
+	// QueueDrainTime is the estimated time needed to process the queued requests.
+	QueueDrainTime time.Duration
+	// InFlightTxs are the tracked transactions not confirmed yet, the oldest first.
+	InFlightTxs []InFlightTx
+	Breakers    []Breaker
+	Paused      bool
+}
+
+// InFlightTx is the transaction waiting for the inclusion or confirmations.
+type InFlightTx struct {
+	TxStatus
+	BroadcastAt time.Time
+}
+
+// Breaker is the state of the circuit breaker rejecting requests while the dependency is unavailable.
+type Breaker struct {
+	Name    string
+	State   string
+	Since   time.Time
+	RetryAt time.Time
+	Cause   string
+}
+
+// Snapshot returns the in-memory state of the app.
+func (a App) Snapshot() Snapshot {
+	depth, drainTime := a.batcher.Backlog()
+	return Snapshot{
+		Time:           a.clock.Now().UTC(),
+		QueueDepth:     depth,
+		QueueDrainTime: drainTime,
+		InFlightTxs:    a.txTracker.inFlight(),
+		Breakers:       []Breaker{a.signing.breaker()},
+		Paused:         a.Controls().Paused,
+	}
+}
+
+func (s *signingStatus) breaker() Breaker {
+	breaker := Breaker{Name: BreakerSigning, State: BreakerClosed}
+	if s == nil {
+		return breaker
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.unavailableSince.IsZero() {
+		return breaker
+	}
+	breaker.State = BreakerOpen
+	breaker.Since = s.unavailableSince.UTC()
+	breaker.RetryAt = s.retryAt.UTC()
+	breaker.Cause = s.cause.Error()
+	return breaker
+}
+
+func (t *TxTracker) inFlight() []InFlightTx {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	txs := []InFlightTx{}
+	for _, tx := range t.txs {
+		if tx.status.State == TxStateConfirmed || tx.status.State == TxStateFailed {
+			continue
+		}
+		status := tx.status
+		status.RequiredConfirmations = t.requiredConfirmations
+		status.Requests = append([]TxRequest{}, tx.status.Requests...)
+		txs = append(txs, InFlightTx{TxStatus: status, BroadcastAt: tx.trackedAt.UTC()})
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		if !txs[i].BroadcastAt.Equal(txs[j].BroadcastAt) {
+			return txs[i].BroadcastAt.Before(txs[j].BroadcastAt)
+		}
+		return txs[i].TxHash < txs[j].TxHash
+	})
+	return txs
+}
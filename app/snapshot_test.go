@@ -0,0 +1,42 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+func TestSnapshot(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	txTracker := NewTxTracker(nil, 2, nil, clock.System{})
+	a := New(&mockBatcher{}, nil, txTracker, nil, nil, chain.Network{}, chain.NewCoin("ucore", chain.NewInt(10))).
+		WithClock(clock.NewManual(now))
+
+	txTracker.TxBroadcast("tx1", 0, nil)
+	txTracker.TxBroadcast("tx2", 5, nil)
+	txTracker.AddRequest("tx2", TxRequest{RequestID: "rq1", Address: "address1"})
+	txTracker.txs["tx2"].status.State = TxStateConfirmed
+
+	snapshot := a.Snapshot()
+	requireT.Equal(now, snapshot.Time)
+	requireT.Len(snapshot.InFlightTxs, 1)
+	requireT.Equal("tx1", snapshot.InFlightTxs[0].TxHash)
+	requireT.Equal([]Breaker{{Name: BreakerSigning, State: BreakerClosed}}, snapshot.Breakers)
+
+	a.signing.observe(ctx, now, mockSigningError{})
+	breaker := a.Snapshot().Breakers[0]
+	requireT.Equal(BreakerOpen, breaker.State)
+	requireT.Equal(now, breaker.Since)
+	requireT.Equal(now.Add(signingRetryInterval), breaker.RetryAt)
+	requireT.NotEmpty(breaker.Cause)
+}
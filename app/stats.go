@@ -0,0 +1,74 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// StatsWindows are the periods the statistics are computed for.
+var StatsWindows = []time.Duration{24 * time.Hour, 7 * 24 * time.Hour}
+
+// NetworkInfo describes the network the faucet operates on.
+type NetworkInfo struct {
+	ChainID        string
+	Denom          string
+	AddressPrefix  string
+	TransferAmount chain.Coin
+}
+
+// WindowStats are the statistics of the fundings over the window.
+type WindowStats struct {
+	Window          time.Duration
+	Grants          int
+	UniqueAddresses int
+	Amount          chain.Coins
+	// Fees are the fees paid for the fundings, per fee denom.
+	Fees chain.Coins
+}
+
+// NetworkInfo returns the information about the network the faucet operates on.
+func (a App) NetworkInfo() NetworkInfo {
+	return NetworkInfo{
+		ChainID:        string(a.network.ChainID()),
+		Denom:          a.network.Denom(),
+		AddressPrefix:  a.network.AddressPrefix(),
+		TransferAmount: a.grantAmount(Requester{}),
+	}
+}
+
+// Stats returns the statistics of the fundings over StatsWindows.
+func (a App) Stats(ctx context.Context) ([]WindowStats, error) {
+	now := a.clock.Now().UTC()
+	longest := StatsWindows[len(StatsWindows)-1]
+	records, err := a.queries().FundingsSince(ctx, now.Add(-longest))
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]WindowStats, 0, len(StatsWindows))
+	for _, window := range StatsWindows {
+		since := now.Add(-window)
+		windowStats := WindowStats{
+			Window: window,
+			Amount: chain.NewCoins(),
+			Fees:   chain.NewCoins(),
+		}
+		addresses := map[string]struct{}{}
+		for _, r := range records {
+			if r.Time.Before(since) {
+				continue
+			}
+			windowStats.Grants++
+			windowStats.Amount = windowStats.Amount.Add(r.Amount)
+			if r.Fee.Denom != "" {
+				windowStats.Fees = windowStats.Fees.Add(r.Fee)
+			}
+			addresses[r.Address] = struct{}{}
+		}
+		windowStats.UniqueAddresses = len(addresses)
+		stats = append(stats, windowStats)
+	}
+	return stats, nil
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+const summaryTopConsumers = 10
+
+// Summary summarizes the activity of the faucet over a period.
+type Summary struct {
+	From            time.Time
+	To              time.Time
+	Grants          int
+	UniqueAddresses int
+	TotalAmount     chain.Coins
+	DailyBurn       chain.Coins
+	TopConsumers    []AddressCluster
+	Incidents       []Incident
+}
+
+// Summary returns the summary of the faucet activity since the given time.
+func (a App) Summary(ctx context.Context, since time.Time) (Summary, error) {
+	records, err := a.queries().FundingsSince(ctx, since)
+	if err != nil {
+		return Summary{}, err
+	}
+	incidents, err := a.queries().IncidentsSince(ctx, since)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	summary := Summary{
+		From:            since,
+		To:              a.clock.Now().UTC(),
+		Grants:          len(records),
+		UniqueAddresses: len(lo.UniqBy(records, func(r FundingRecord) string { return r.Address })),
+		TotalAmount:     chain.NewCoins(),
+		DailyBurn:       chain.NewCoins(),
+		Incidents:       incidents,
+	}
+	for _, r := range records {
+		summary.TotalAmount = summary.TotalAmount.Add(r.Amount)
+	}
+	if period := summary.To.Sub(summary.From); period > 0 {
+		for _, coin := range summary.TotalAmount {
+			summary.DailyBurn = summary.DailyBurn.Add(chain.NewCoin(
+				coin.Denom,
+				coin.Amount.MulRaw(int64(24*time.Hour)).QuoRaw(int64(period)),
+			))
+		}
+	}
+
+	summary.TopConsumers = clusterFundings(records, 1)
+	if len(summary.TopConsumers) > summaryTopConsumers {
+		summary.TopConsumers = summary.TopConsumers[:summaryTopConsumers]
+	}
+
+	return summary, nil
+}
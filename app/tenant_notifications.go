@@ -0,0 +1,168 @@
+package app
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	maxTenantWebhooks        = 5
+	maxTenantWebhookURLength = 2048
+	// SlackWebhookHost is the host of Slack incoming webhooks, each posting to a single channel.
+	SlackWebhookHost = "hooks.slack.com"
+)
+
+// AlertThresholds are the numbers of events of the tenant within the alert window raising the alert,
+// 0 disables the alert.
+type AlertThresholds struct {
+	Failed  int `json:"failed"`
+	Blocked int `json:"blocked"`
+}
+
+// TenantNotifications configures where the events of the tenant's requests are delivered.
+type TenantNotifications struct {
+	Tenant string `json:"tenant"`
+	// Webhooks receive the events and alerts of the tenant as JSON.
+	Webhooks []string `json:"webhooks"`
+	// SlackWebhooks are the incoming webhooks of the Slack channels receiving the alerts.
+	SlackWebhooks []string `json:"slackWebhooks"`
+	// Events are the kinds of events delivered to the webhooks, all of them if empty.
+	Events    []EventKind     `json:"events"`
+	Alerts    AlertThresholds `json:"alerts"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// Routes tells if the event of the kind is delivered to the webhooks.
+func (n TenantNotifications) Routes(kind EventKind) bool {
+	if len(n.Events) == 0 {
+		return true
+	}
+	for _, k := range n.Events {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// TenantNotificationStore persists the notification settings of the tenants.
+type TenantNotificationStore interface {
+	// TenantNotifications returns the settings of the tenant or ErrTenantNotificationsNotFound.
+	TenantNotifications(ctx context.Context, tenant string) (TenantNotifications, error)
+	// AllTenantNotifications returns the settings of all the tenants.
+	AllTenantNotifications(ctx context.Context) ([]TenantNotifications, error)
+	PutTenantNotifications(ctx context.Context, notifications TenantNotifications) error
+	// DeleteTenantNotifications deletes the settings of the tenant or returns ErrTenantNotificationsNotFound.
+	DeleteTenantNotifications(ctx context.Context, tenant string) error
+}
+
+// WithTenantNotifications returns a copy of the app letting the tenants configure the delivery of their events.
+func (a App) WithTenantNotifications(store TenantNotificationStore) App {
+	a.tenantNotifications = store
+	return a
+}
+
+// TenantNotificationsEnabled tells if the tenants may configure the delivery of their events.
+func (a App) TenantNotificationsEnabled() bool {
+	return a.tenantNotifications != nil
+}
+
+// TenantNotifications returns the notification settings of the tenant.
+func (a App) TenantNotifications(ctx context.Context, tenant string) (TenantNotifications, error) {
+	if err := a.checkTenantNotifications(); err != nil {
+		return TenantNotifications{}, err
+	}
+	return a.tenantNotifications.TenantNotifications(ctx, tenant)
+}
+
+// SetTenantNotifications validates and stores the notification settings of the tenant, replacing the previous
+// ones.
+func (a App) SetTenantNotifications(
+	ctx context.Context,
+	notifications TenantNotifications,
+) (TenantNotifications, error) {
+	if err := a.checkTenantNotifications(); err != nil {
+		return TenantNotifications{}, err
+	}
+	if err := validateTenantNotifications(notifications); err != nil {
+		return TenantNotifications{}, err
+	}
+	notifications.UpdatedAt = a.clock.Now().UTC()
+	if err := a.tenantNotifications.PutTenantNotifications(ctx, notifications); err != nil {
+		return TenantNotifications{}, err
+	}
+	return notifications, nil
+}
+
+// DeleteTenantNotifications deletes the notification settings of the tenant, so its events are not delivered
+// anymore.
+func (a App) DeleteTenantNotifications(ctx context.Context, tenant string) error {
+	if err := a.checkTenantNotifications(); err != nil {
+		return err
+	}
+	return a.tenantNotifications.DeleteTenantNotifications(ctx, tenant)
+}
+
+func (a App) checkTenantNotifications() error {
+	if a.tenantNotifications == nil {
+		return errors.Wrap(ErrTenantNotificationsNotFound, "tenant notifications are disabled")
+	}
+	return nil
+}
+
+func validateTenantNotifications(n TenantNotifications) error {
+	if n.Tenant == "" {
+		return errors.Wrap(ErrInvalidTenantNotifications, "tenant is required")
+	}
+	if len(n.Webhooks) > maxTenantWebhooks || len(n.SlackWebhooks) > maxTenantWebhooks {
+		return errors.Wrapf(ErrInvalidTenantNotifications, "at most %d webhooks of each kind are allowed",
+			maxTenantWebhooks)
+	}
+	for _, webhook := range n.Webhooks {
+		if _, err := parseTenantWebhook(webhook); err != nil {
+			return err
+		}
+	}
+	for _, webhook := range n.SlackWebhooks {
+		u, err := parseTenantWebhook(webhook)
+		if err != nil {
+			return err
+		}
+		if strings.ToLower(u.Hostname()) != SlackWebhookHost {
+			return errors.Wrapf(ErrInvalidTenantNotifications, "Slack webhook must point to %s", SlackWebhookHost)
+		}
+	}
+	for _, kind := range n.Events {
+		switch kind {
+		case EventRequestAccepted, EventBroadcast, EventFailed, EventBlocked:
+		default:
+			return errors.Wrapf(ErrInvalidTenantNotifications, "event %q can't be routed to the tenant", kind)
+		}
+	}
+	if n.Alerts.Failed < 0 || n.Alerts.Blocked < 0 {
+		return errors.Wrap(ErrInvalidTenantNotifications, "alert thresholds must not be negative")
+	}
+	return nil
+}
+
+func parseTenantWebhook(rawURL string) (*url.URL, error) {
+	if len(rawURL) > maxTenantWebhookURLength {
+		return nil, errors.Wrapf(ErrInvalidTenantNotifications, "URL is longer than %d characters",
+			maxTenantWebhookURLength)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, errors.Wrap(ErrInvalidTenantNotifications, "malformed webhook URL")
+	}
+	if u.Scheme != "https" {
+		return nil, errors.Wrap(ErrInvalidTenantNotifications, "webhook must use https")
+	}
+	if u.User != nil {
+		return nil, errors.Wrap(ErrInvalidTenantNotifications, "credentials in webhook URL are not allowed")
+	}
+	return u, nil
+}
@@ -0,0 +1,168 @@
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	minToSKeyLength = 16
+	tosNonceLength  = 8
+	tosClientLength = 16
+)
+
+// TermsOfService issues the short-lived tokens proving the client accepted the version of the terms of service
+// and verifies them before funding. Tokens are signed with HMAC-SHA256 and bound to the IP and fingerprint
+// of the client, so they can't be shared between clients.
+type TermsOfService struct {
+	version string
+	key     []byte
+	ttl     time.Duration
+}
+
+// NewTermsOfService returns the terms of service of the version, accepted for ttl once the token is issued.
+func NewTermsOfService(version, key string, ttl time.Duration) (*TermsOfService, error) {
+	if version == "" {
+		return nil, errors.New("version of the terms of service is required")
+	}
+	if len(key) < minToSKeyLength {
+		return nil, errors.Errorf("key of at least %d characters is required to sign the tokens", minToSKeyLength)
+	}
+	if ttl <= 0 {
+		return nil, errors.New("lifetime of the token must be positive")
+	}
+	return &TermsOfService{version: version, key: []byte(key), ttl: ttl}, nil
+}
+
+// ToSAcceptance is the token issued to the client accepting the terms of service.
+type ToSAcceptance struct {
+	Token     string
+	Version   string
+	ExpiresAt time.Time
+}
+
+type tosClaims struct {
+	Version   string `json:"v"`
+	Client    string `json:"c"`
+	ExpiresAt int64  `json:"exp"`
+	Nonce     string `json:"n"`
+}
+
+// WithTermsOfService returns a copy of the app funding only the clients presenting the token of the accepted
+// terms of service. Requests authorized by the admin token are exempt.
+func (a App) WithTermsOfService(tos *TermsOfService) App {
+	a.tos = tos
+	return a
+}
+
+// ToSVersion returns the version of the terms of service the clients must accept, empty if acceptance
+// is not required.
+func (a App) ToSVersion() string {
+	if a.tos == nil {
+		return ""
+	}
+	return a.tos.version
+}
+
+// AcceptTermsOfService issues the token proving the requester accepted the version of the terms of service.
+// The version must be the current one, so the client doesn't accept the terms it has never been shown.
+func (a App) AcceptTermsOfService(requester Requester, version string) (ToSAcceptance, error) {
+	if a.tos == nil {
+		return ToSAcceptance{}, errors.Wrap(ErrToSNotRequired, "terms of service are not configured")
+	}
+	if version != a.tos.version {
+		return ToSAcceptance{}, errors.Wrapf(ErrToSVersionMismatch, "version %q is accepted, current one is %q",
+			version, a.tos.version)
+	}
+	nonce := make([]byte, tosNonceLength)
+	if _, err := rand.Read(nonce); err != nil {
+		return ToSAcceptance{}, errors.WithStack(err)
+	}
+	expiresAt := a.clock.Now().Add(a.tos.ttl).UTC().Truncate(time.Second)
+	token, err := a.tos.sign(tosClaims{
+		Version:   a.tos.version,
+		Client:    a.tos.client(requester),
+		ExpiresAt: expiresAt.Unix(),
+		Nonce:     hex.EncodeToString(nonce),
+	})
+	if err != nil {
+		return ToSAcceptance{}, err
+	}
+	return ToSAcceptance{Token: token, Version: a.tos.version, ExpiresAt: expiresAt}, nil
+}
+
+// verifyToSAccepted returns the requester with the version of the terms of service recorded, so it is stored
+// in the history and published in the events. Error is returned if the token is missing or invalid.
+func (a App) verifyToSAccepted(requester Requester) (Requester, error) {
+	if a.tos == nil || requester.Admin {
+		return requester, nil
+	}
+	if requester.ToSToken == "" {
+		return requester, errors.Wrapf(ErrToSNotAccepted, "terms of service %s must be accepted", a.tos.version)
+	}
+	claims, err := a.tos.verify(requester.ToSToken)
+	if err != nil {
+		return requester, err
+	}
+	switch {
+	case claims.Version != a.tos.version:
+		return requester, errors.Wrapf(ErrToSNotAccepted, "version %s is accepted, current one is %s",
+			claims.Version, a.tos.version)
+	case !a.clock.Now().Before(time.Unix(claims.ExpiresAt, 0)):
+		return requester, errors.Wrap(ErrToSTokenInvalid, "token is expired")
+	case !hmac.Equal([]byte(claims.Client), []byte(a.tos.client(requester))):
+		return requester, errors.Wrap(ErrToSTokenInvalid, "token is issued to another client")
+	}
+	requester.ToSVersion = claims.Version
+	return requester, nil
+}
+
+// client returns the value binding the token to the IP and fingerprint of the requester, it doesn't reveal them.
+func (t *TermsOfService) client(requester Requester) string {
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write([]byte("client\x00" + requester.IP + "\x00" + requester.Fingerprint))
+	return hex.EncodeToString(mac.Sum(nil))[:2*tosClientLength]
+}
+
+func (t *TermsOfService) sign(claims tosClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(t.signature(encoded)), nil
+}
+
+func (t *TermsOfService) verify(token string) (tosClaims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return tosClaims{}, errors.Wrap(ErrToSTokenInvalid, "malformed token")
+	}
+	rawSignature, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(rawSignature, t.signature(encoded)) {
+		return tosClaims{}, errors.Wrap(ErrToSTokenInvalid, "invalid signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return tosClaims{}, errors.Wrap(ErrToSTokenInvalid, "malformed token")
+	}
+	var claims tosClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return tosClaims{}, errors.Wrap(ErrToSTokenInvalid, "malformed token")
+	}
+	return claims, nil
+}
+
+func (t *TermsOfService) signature(encoded string) []byte {
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write([]byte("token\x00" + encoded))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+func TestTermsOfService(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+
+	_, err = NewTermsOfService("2023-01", "short", time.Minute)
+	requireT.Error(err)
+	tos, err := NewTermsOfService("2023-01", "0123456789abcdef", 10*time.Minute)
+	requireT.NoError(err)
+
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	history := &mockHistory{}
+	a := New(&mockBatcher{txHash: "tx1"}, nil, NewTxTracker(nil, 1, nil, clock.System{}), history, &mockLedger{}, network,
+		chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clk).
+		WithTermsOfService(tos)
+	requester := Requester{IP: "1.2.3.4", Fingerprint: "fp"}
+
+	// funding is refused until the terms are accepted
+	_, err = a.GiveFunds(ctx, requester, address)
+	requireT.ErrorIs(err, ErrToSNotAccepted)
+
+	_, err = a.AcceptTermsOfService(requester, "2022-12")
+	requireT.ErrorIs(err, ErrToSVersionMismatch)
+	acceptance, err := a.AcceptTermsOfService(requester, "2023-01")
+	requireT.NoError(err)
+	requireT.Equal("2023-01", acceptance.Version)
+	requireT.Equal(clk.Now().Add(10*time.Minute), acceptance.ExpiresAt)
+
+	// the token is bound to the client
+	other := Requester{IP: "5.6.7.8", Fingerprint: "fp", ToSToken: acceptance.Token}
+	_, err = a.GiveFunds(ctx, other, address)
+	requireT.ErrorIs(err, ErrToSTokenInvalid)
+
+	// tampered token is rejected
+	tampered := requester
+	tampered.ToSToken = "x" + acceptance.Token
+	_, err = a.GiveFunds(ctx, tampered, address)
+	requireT.ErrorIs(err, ErrToSTokenInvalid)
+
+	// the accepted version is recorded in the history
+	requester.ToSToken = acceptance.Token
+	_, err = a.GiveFunds(ctx, requester, address)
+	requireT.NoError(err)
+	requireT.Len(history.fundings, 1)
+	requireT.Equal("2023-01", history.fundings[0].ToSVersion)
+
+	// tokens issued for the previous version are not accepted once the terms change
+	newTos, err := NewTermsOfService("2023-02", "0123456789abcdef", 10*time.Minute)
+	requireT.NoError(err)
+	_, err = a.WithTermsOfService(newTos).GiveFunds(ctx, requester, address)
+	requireT.ErrorIs(err, ErrToSNotAccepted)
+
+	clk.Advance(10 * time.Minute)
+	_, err = a.GiveFunds(ctx, requester, address)
+	requireT.ErrorIs(err, ErrToSTokenInvalid)
+
+	// admin is exempt
+	_, err = a.GiveFunds(ctx, Requester{Admin: true}, address)
+	requireT.NoError(err)
+	requireT.Empty(history.fundings[1].ToSVersion)
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// Transparency are the top-level totals of the faucet published without authorization, e.g. on the community
+// status page. Nothing related to the particular recipients is included.
+type Transparency struct {
+	// Distributed is the amount sent since the funding history starts.
+	Distributed chain.Coins
+	Grants      int
+	// Balance is the balance of the funding accounts in the transfer denom, nil if it can't be queried.
+	Balance   *chain.Coin
+	StartedAt time.Time
+	Uptime    time.Duration
+}
+
+// transparencyCacheTTL is how long the totals are reused. The endpoint is public, so the history isn't scanned and
+// the balances aren't queried for each request, the totals are as fresh as the responses cached by the clients.
+const transparencyCacheTTL = 30 * time.Second
+
+type transparency struct {
+	source    BalanceSource
+	addresses []chain.AccAddress
+	startedAt time.Time
+
+	mu         sync.Mutex
+	cached     Transparency
+	computedAt time.Time
+}
+
+// WithTransparency returns a copy of the app publishing the totals, the balance is summed over the funding
+// addresses. The uptime is counted from now on.
+func (a App) WithTransparency(source BalanceSource, addresses []chain.AccAddress) App {
+	a.transparency = &transparency{
+		source:    source,
+		addresses: addresses,
+		startedAt: a.clock.Now().UTC(),
+	}
+	return a
+}
+
+// TransparencyEnabled tells if the totals are published.
+func (a App) TransparencyEnabled() bool {
+	return a.transparency != nil
+}
+
+// Transparency returns the top-level totals of the faucet, computed at most once per transparencyCacheTTL.
+// If the balance can't be queried, the totals are returned without it, so the status page keeps working while
+// the node lags.
+func (a App) Transparency(ctx context.Context) (Transparency, error) {
+	t := a.transparency
+	if t == nil {
+		return Transparency{}, nil
+	}
+	now := a.clock.Now().UTC()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.computedAt.IsZero() || now.Sub(t.computedAt) >= transparencyCacheTTL {
+		result, err := a.computeTransparency(ctx)
+		if err != nil {
+			return Transparency{}, err
+		}
+		t.cached = result
+		t.computedAt = now
+	}
+	result := t.cached
+	result.Uptime = now.Sub(t.startedAt).Truncate(time.Second)
+	return result, nil
+}
+
+func (a App) computeTransparency(ctx context.Context) (Transparency, error) {
+	t := a.transparency
+	// history is keyed by Unix time, zero time is out of its range
+	records, err := a.queries().FundingsSince(ctx, time.Unix(0, 0))
+	if err != nil {
+		return Transparency{}, err
+	}
+
+	result := Transparency{
+		Distributed: chain.NewCoins(),
+		Grants:      len(records),
+		StartedAt:   t.startedAt,
+	}
+	for _, r := range records {
+		result.Distributed = result.Distributed.Add(r.Amount)
+	}
+
+	denom := a.transferAmount.Denom
+	balance := chain.NewCoin(denom, chain.NewInt(0))
+	for _, address := range t.addresses {
+		amount, err := t.source.Balance(ctx, address, denom)
+		if err != nil {
+			logger.Get(ctx).Warn("Querying balance of the funding account failed, balance is not published",
+				zap.String("address", address.String()), zap.Error(err))
+			return result, nil
+		}
+		balance = balance.AddAmount(amount)
+	}
+	result.Balance = &balance
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+func TestTransparency(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	_, first, err := parseAddress("devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62")
+	requireT.NoError(err)
+	_, second, err := parseAddress("devcore1kdxhx3vv6n0g4ffgdqp8y7e44q7rzjvvlvlg3q")
+	requireT.NoError(err)
+
+	startedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewManual(startedAt)
+	history := &mockHistory{fundings: []FundingRecord{
+		{Address: "devcore1a", Amount: chain.NewCoin("udevcore", chain.NewInt(1000)), Time: startedAt.Add(-30 * 24 * time.Hour)},
+		{Address: "devcore1b", Amount: chain.NewCoin("udevcore", chain.NewInt(1000)), Time: startedAt},
+		{Address: "devcore1b", Amount: chain.NewCoin("uusdc", chain.NewInt(5)), Time: startedAt},
+	}}
+	a := New(&mockBatcher{txHash: "tx1"}, nil, NewTxTracker(nil, 1, nil, clock.System{}), history, &mockLedger{}, network,
+		chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clk)
+	requireT.False(a.TransparencyEnabled())
+
+	balances := &mockBalances{balances: map[string]chain.Int{
+		string(first):  chain.NewInt(7000),
+		string(second): chain.NewInt(3000),
+	}}
+	a = a.WithTransparency(balances, []chain.AccAddress{first, second})
+	requireT.True(a.TransparencyEnabled())
+
+	clk.Advance(90 * time.Minute)
+	transparency, err := a.Transparency(ctx)
+	requireT.NoError(err)
+	requireT.Equal("2000udevcore,5uusdc", transparency.Distributed.String())
+	requireT.Equal(3, transparency.Grants)
+	requireT.NotNil(transparency.Balance)
+	requireT.Equal("10000udevcore", transparency.Balance.String())
+	requireT.Equal(startedAt, transparency.StartedAt)
+	requireT.Equal(90*time.Minute, transparency.Uptime)
+
+	// the totals are reused until the cache expires
+	balances.err = errors.New("node is down")
+	history.fundings = append(history.fundings,
+		FundingRecord{Address: "devcore1c", Amount: chain.NewCoin("udevcore", chain.NewInt(1000)), Time: clk.Now()})
+	clk.Advance(time.Second)
+	transparency, err = a.Transparency(ctx)
+	requireT.NoError(err)
+	requireT.Equal(3, transparency.Grants)
+	requireT.NotNil(transparency.Balance)
+	requireT.Equal(90*time.Minute+time.Second, transparency.Uptime)
+
+	// the totals are published even if the node is down
+	clk.Advance(transparencyCacheTTL)
+	transparency, err = a.Transparency(ctx)
+	requireT.NoError(err)
+	requireT.Nil(transparency.Balance)
+	requireT.Equal(4, transparency.Grants)
+}
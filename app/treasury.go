@@ -0,0 +1,255 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// RefillStatus is the stage of the refill proposal.
+type RefillStatus string
+
+// Refill statuses.
+const (
+	// RefillPending means the proposal is collecting the signatures.
+	RefillPending RefillStatus = "pending"
+	// RefillReady means enough signatures are collected, so the transaction may be broadcast.
+	RefillReady RefillStatus = "ready"
+	// RefillBroadcast means the transaction is broadcast.
+	RefillBroadcast RefillStatus = "broadcast"
+)
+
+// Treasury prepares and broadcasts the transactions of the multisig account refilling the faucet. They are signed
+// offline by the signers of the account.
+type Treasury interface {
+	Address() chain.AccAddress
+	Threshold() int
+	Signers() []chain.AccAddress
+	// PrepareSend returns the JSON of the unsigned transaction sending the amount to the address, together with
+	// the account number and sequence it must be signed with.
+	PrepareSend(
+		ctx context.Context,
+		to chain.AccAddress,
+		amount chain.Coins,
+	) (txJSON []byte, accountNumber, sequence uint64, err error)
+	// VerifySignature verifies the signature of the transaction made by the signer and returns its address.
+	VerifySignature(txJSON []byte, accountNumber, sequence uint64, signature []byte) (chain.AccAddress, error)
+	// Broadcast combines the signatures into the signature of the account and broadcasts the transaction.
+	Broadcast(ctx context.Context, txJSON []byte, sequence uint64, signatures [][]byte) (string, error)
+}
+
+// RefillProposal is the transaction sending funds from the multisig treasury to the faucet, collecting
+// the signatures of the treasury signers until it can be broadcast.
+type RefillProposal struct {
+	ID        string      `json:"id"`
+	Treasury  string      `json:"treasury"`
+	Recipient string      `json:"recipient"`
+	Amount    chain.Coins `json:"amount"`
+	Threshold int         `json:"threshold"`
+	Signers   []string    `json:"signers"`
+	// UnsignedTx is the JSON of the transaction signed by the signers with the account number and sequence.
+	UnsignedTx    json.RawMessage `json:"unsignedTx"`
+	AccountNumber uint64          `json:"accountNumber"`
+	Sequence      uint64          `json:"sequence"`
+	// Signatures are the signatures collected so far by the address of the signer.
+	Signatures  map[string]json.RawMessage `json:"signatures"`
+	Status      RefillStatus               `json:"status"`
+	TxHash      string                     `json:"txHash,omitempty"`
+	CreatedAt   time.Time                  `json:"createdAt"`
+	BroadcastAt time.Time                  `json:"broadcastAt"`
+}
+
+// SignedBy returns the addresses of the signers who signed the proposal, ordered.
+func (p RefillProposal) SignedBy() []string {
+	signedBy := make([]string, 0, len(p.Signatures))
+	for signer := range p.Signatures {
+		signedBy = append(signedBy, signer)
+	}
+	sort.Strings(signedBy)
+	return signedBy
+}
+
+// RefillProposalStore persists the refill proposals.
+type RefillProposalStore interface {
+	PutRefillProposal(ctx context.Context, proposal RefillProposal) error
+	// RefillProposal returns the proposal or ErrRefillProposalNotFound.
+	RefillProposal(ctx context.Context, id string) (RefillProposal, error)
+	// RefillProposals returns all the proposals ordered by creation time.
+	RefillProposals(ctx context.Context) ([]RefillProposal, error)
+}
+
+// refills prepares the refill proposals, the mutex serializes the updates of the proposals.
+type refills struct {
+	treasury  Treasury
+	store     RefillProposalStore
+	recipient chain.AccAddress
+
+	mu sync.Mutex
+}
+
+// WithTreasury returns a copy of the app preparing the transactions refilling the recipient, the funding account
+// of the faucet, from the multisig treasury. Proposals collect the signatures of the treasury signers until
+// there are enough of them to broadcast the transaction.
+func (a App) WithTreasury(treasury Treasury, store RefillProposalStore, recipient chain.AccAddress) App {
+	a.refills = &refills{treasury: treasury, store: store, recipient: recipient}
+	return a
+}
+
+// TreasuryEnabled tells if the refills from the multisig treasury are configured.
+func (a App) TreasuryEnabled() bool {
+	return a.refills != nil
+}
+
+// ProposeRefill prepares the transaction sending the amount from the treasury to the faucet.
+func (a App) ProposeRefill(ctx context.Context, amount chain.Coins) (RefillProposal, error) {
+	r, err := a.treasury()
+	if err != nil {
+		return RefillProposal{}, err
+	}
+	if amount.Empty() || !amount.IsValid() {
+		return RefillProposal{}, errors.Wrapf(ErrInvalidRefill, "invalid amount %q", amount)
+	}
+	txJSON, accountNumber, sequence, err := r.treasury.PrepareSend(ctx, r.recipient, amount)
+	if err != nil {
+		return RefillProposal{}, err
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return RefillProposal{}, errors.WithStack(err)
+	}
+
+	proposal := RefillProposal{
+		ID:            hex.EncodeToString(id),
+		Treasury:      r.treasury.Address().String(),
+		Recipient:     r.recipient.String(),
+		Amount:        amount,
+		Threshold:     r.treasury.Threshold(),
+		UnsignedTx:    txJSON,
+		AccountNumber: accountNumber,
+		Sequence:      sequence,
+		Signatures:    map[string]json.RawMessage{},
+		Status:        RefillPending,
+		CreatedAt:     a.clock.Now().UTC(),
+	}
+	for _, signer := range r.treasury.Signers() {
+		proposal.Signers = append(proposal.Signers, signer.String())
+	}
+	if err := r.store.PutRefillProposal(ctx, proposal); err != nil {
+		return RefillProposal{}, err
+	}
+	logger.Get(ctx).Info("Refill proposed", zap.String("id", proposal.ID), zap.Stringer("amount", amount),
+		zap.Uint64("sequence", sequence))
+	return proposal, nil
+}
+
+// RefillProposals returns all the refill proposals.
+func (a App) RefillProposals(ctx context.Context) ([]RefillProposal, error) {
+	r, err := a.treasury()
+	if err != nil {
+		return nil, err
+	}
+	return r.store.RefillProposals(ctx)
+}
+
+// RefillProposal returns the refill proposal.
+func (a App) RefillProposal(ctx context.Context, id string) (RefillProposal, error) {
+	r, err := a.treasury()
+	if err != nil {
+		return RefillProposal{}, err
+	}
+	return r.store.RefillProposal(ctx, id)
+}
+
+// AddRefillSignature verifies the signature of the treasury signer and adds it to the proposal. Signature
+// of the same signer replaces the previous one. The proposal is ready once the threshold is reached.
+func (a App) AddRefillSignature(ctx context.Context, id string, signature []byte) (RefillProposal, error) {
+	r, err := a.treasury()
+	if err != nil {
+		return RefillProposal{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	proposal, err := r.store.RefillProposal(ctx, id)
+	if err != nil {
+		return RefillProposal{}, err
+	}
+	if proposal.Status == RefillBroadcast {
+		return RefillProposal{}, errors.Wrapf(ErrInvalidRefill, "proposal %s is broadcast already", id)
+	}
+	signer, err := r.treasury.VerifySignature(proposal.UnsignedTx, proposal.AccountNumber, proposal.Sequence, signature)
+	if err != nil {
+		return RefillProposal{}, errors.Wrap(ErrRefillSignatureInvalid, err.Error())
+	}
+
+	proposal.Signatures[signer.String()] = signature
+	if len(proposal.Signatures) >= proposal.Threshold {
+		proposal.Status = RefillReady
+	}
+	if err := r.store.PutRefillProposal(ctx, proposal); err != nil {
+		return RefillProposal{}, err
+	}
+	logger.Get(ctx).Info("Refill signed", zap.String("id", id), zap.Stringer("signer", signer),
+		zap.Int("signatures", len(proposal.Signatures)), zap.Int("threshold", proposal.Threshold))
+	return proposal, nil
+}
+
+// BroadcastRefill broadcasts the transaction of the proposal signed by enough signers.
+func (a App) BroadcastRefill(ctx context.Context, id string) (RefillProposal, error) {
+	r, err := a.treasury()
+	if err != nil {
+		return RefillProposal{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	proposal, err := r.store.RefillProposal(ctx, id)
+	if err != nil {
+		return RefillProposal{}, err
+	}
+	switch proposal.Status {
+	case RefillPending:
+		return RefillProposal{}, errors.Wrapf(ErrInvalidRefill, "proposal %s has %d of %d signatures", id,
+			len(proposal.Signatures), proposal.Threshold)
+	case RefillBroadcast:
+		return RefillProposal{}, errors.Wrapf(ErrInvalidRefill, "proposal %s is broadcast already", id)
+	}
+
+	// signatures are ordered the same way for each attempt, only threshold of them is needed
+	signatures := make([][]byte, 0, proposal.Threshold)
+	for _, signer := range proposal.SignedBy()[:proposal.Threshold] {
+		signatures = append(signatures, proposal.Signatures[signer])
+	}
+	txHash, err := r.treasury.Broadcast(ctx, proposal.UnsignedTx, proposal.Sequence, signatures)
+	if err != nil {
+		return RefillProposal{}, err
+	}
+
+	proposal.Status = RefillBroadcast
+	proposal.TxHash = txHash
+	proposal.BroadcastAt = a.clock.Now().UTC()
+	if err := r.store.PutRefillProposal(ctx, proposal); err != nil {
+		// the funds are moved already, so the proposal is returned anyway
+		logger.Get(ctx).Error("Storing broadcast refill failed", zap.String("id", id), zap.Error(err))
+	}
+	logger.Get(ctx).Info("Refill broadcast", zap.String("id", id), zap.String("txHash", txHash))
+	return proposal, nil
+}
+
+func (a App) treasury() (*refills, error) {
+	if a.refills == nil {
+		return nil, errors.Wrap(ErrInvalidRefill, "treasury is not configured")
+	}
+	return a.refills, nil
+}
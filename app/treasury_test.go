@@ -0,0 +1,161 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+var (
+	treasurySigner1 = chain.AccAddress("treasury-signer-1___")
+	treasurySigner2 = chain.AccAddress("treasury-signer-2___")
+	treasurySigner3 = chain.AccAddress("treasury-signer-3___")
+)
+
+// mockTreasury accepts the signatures being the JSON strings of the signer names.
+type mockTreasury struct {
+	sequence  uint64
+	broadcast [][]byte
+}
+
+func (m *mockTreasury) Address() chain.AccAddress {
+	return chain.AccAddress("treasury____________")
+}
+
+func (m *mockTreasury) Threshold() int {
+	return 2
+}
+
+func (m *mockTreasury) Signers() []chain.AccAddress {
+	return []chain.AccAddress{treasurySigner1, treasurySigner2, treasurySigner3}
+}
+
+func (m *mockTreasury) PrepareSend(
+	_ context.Context,
+	to chain.AccAddress,
+	amount chain.Coins,
+) ([]byte, uint64, uint64, error) {
+	return []byte(`{"to":"` + to.String() + `","amount":"` + amount.String() + `"}`), 7, m.sequence, nil
+}
+
+func (m *mockTreasury) VerifySignature(_ []byte, _, sequence uint64, signature []byte) (chain.AccAddress, error) {
+	if sequence != m.sequence {
+		return nil, errors.New("wrong sequence")
+	}
+	switch string(signature) {
+	case `"signer1"`:
+		return treasurySigner1, nil
+	case `"signer2"`:
+		return treasurySigner2, nil
+	case `"signer3"`:
+		return treasurySigner3, nil
+	default:
+		return nil, errors.New("not a signer")
+	}
+}
+
+func (m *mockTreasury) Broadcast(_ context.Context, _ []byte, _ uint64, signatures [][]byte) (string, error) {
+	m.broadcast = signatures
+	return "refill-tx", nil
+}
+
+type mockRefillProposals map[string]RefillProposal
+
+func (m mockRefillProposals) PutRefillProposal(_ context.Context, proposal RefillProposal) error {
+	m[proposal.ID] = proposal
+	return nil
+}
+
+func (m mockRefillProposals) RefillProposal(_ context.Context, id string) (RefillProposal, error) {
+	proposal, ok := m[id]
+	if !ok {
+		return RefillProposal{}, errors.WithStack(ErrRefillProposalNotFound)
+	}
+	return proposal, nil
+}
+
+func (m mockRefillProposals) RefillProposals(_ context.Context) ([]RefillProposal, error) {
+	proposals := []RefillProposal{}
+	for _, proposal := range m {
+		proposals = append(proposals, proposal)
+	}
+	return proposals, nil
+}
+
+func TestRefill(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewManual(now)
+	a := New(&mockBatcher{}, nil, nil, nil, nil, chain.Network{}, chain.Coin{}).WithClock(clk)
+	requireT.False(a.TreasuryEnabled())
+	_, err := a.ProposeRefill(ctx, chain.NewCoins(chain.NewCoin("ucore", chain.NewInt(1000))))
+	requireT.ErrorIs(err, ErrInvalidRefill)
+
+	treasury := &mockTreasury{sequence: 3}
+	recipient := chain.AccAddress("faucet______________")
+	a = a.WithTreasury(treasury, mockRefillProposals{}, recipient)
+	requireT.True(a.TreasuryEnabled())
+
+	_, err = a.ProposeRefill(ctx, chain.Coins{})
+	requireT.ErrorIs(err, ErrInvalidRefill)
+
+	proposal, err := a.ProposeRefill(ctx, chain.NewCoins(chain.NewCoin("ucore", chain.NewInt(1000))))
+	requireT.NoError(err)
+	requireT.Equal(RefillPending, proposal.Status)
+	requireT.Equal(recipient.String(), proposal.Recipient)
+	requireT.Equal(2, proposal.Threshold)
+	requireT.Len(proposal.Signers, 3)
+	requireT.Equal(uint64(7), proposal.AccountNumber)
+	requireT.Equal(uint64(3), proposal.Sequence)
+	requireT.Equal(now, proposal.CreatedAt)
+
+	_, err = a.RefillProposal(ctx, "missing")
+	requireT.ErrorIs(err, ErrRefillProposalNotFound)
+	_, err = a.AddRefillSignature(ctx, proposal.ID, []byte(`"stranger"`))
+	requireT.ErrorIs(err, ErrRefillSignatureInvalid)
+
+	proposal, err = a.AddRefillSignature(ctx, proposal.ID, []byte(`"signer2"`))
+	requireT.NoError(err)
+	requireT.Equal(RefillPending, proposal.Status)
+	// the same signer doesn't count twice
+	proposal, err = a.AddRefillSignature(ctx, proposal.ID, []byte(`"signer2"`))
+	requireT.NoError(err)
+	requireT.Equal(RefillPending, proposal.Status)
+	requireT.Equal([]string{treasurySigner2.String()}, proposal.SignedBy())
+
+	_, err = a.BroadcastRefill(ctx, proposal.ID)
+	requireT.ErrorIs(err, ErrInvalidRefill)
+	requireT.Nil(treasury.broadcast)
+
+	proposal, err = a.AddRefillSignature(ctx, proposal.ID, []byte(`"signer1"`))
+	requireT.NoError(err)
+	requireT.Equal(RefillReady, proposal.Status)
+
+	clk.Advance(time.Minute)
+	proposal, err = a.BroadcastRefill(ctx, proposal.ID)
+	requireT.NoError(err)
+	requireT.Equal(RefillBroadcast, proposal.Status)
+	requireT.Equal("refill-tx", proposal.TxHash)
+	requireT.Equal(now.Add(time.Minute), proposal.BroadcastAt)
+	requireT.Len(treasury.broadcast, 2)
+
+	// broadcast proposal is final
+	_, err = a.BroadcastRefill(ctx, proposal.ID)
+	requireT.ErrorIs(err, ErrInvalidRefill)
+	_, err = a.AddRefillSignature(ctx, proposal.ID, []byte(`"signer3"`))
+	requireT.ErrorIs(err, ErrInvalidRefill)
+
+	proposals, err := a.RefillProposals(ctx)
+	requireT.NoError(err)
+	requireT.Len(proposals, 1)
+}
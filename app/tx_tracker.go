@@ -0,0 +1,370 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+const (
+	txTrackerPollInterval = 2 * time.Second
+	txTrackerRetention    = time.Hour
+	// txTrackerMaxPendingAge and txTrackerMaxRebroadcasts bound how long the transaction never included in a block
+	// is rebroadcast before it is given up as failed.
+	txTrackerMaxPendingAge   = 10 * time.Minute
+	txTrackerMaxRebroadcasts = 100
+
+	// MaxTxConfirmations is the highest number of confirmations the funding request may require, the transaction
+	// is tracked until it collects the highest number required by its requests.
+	MaxTxConfirmations = 100
+)
+
+// TxState describes the state of a funding transaction on chain.
+type TxState string
+
+// Transaction states reported by the TxTracker.
+const (
+	// TxStatePending means the transaction is not included in any block, either because it hasn't been yet
+	// or because the block including it disappeared from the chain and the transaction was rebroadcast.
+	TxStatePending TxState = "pending"
+	// TxStateIncluded means the transaction is included in a block but doesn't have enough confirmations yet.
+	TxStateIncluded TxState = "included"
+	// TxStateConfirmed means the transaction is buried under the required number of blocks.
+	TxStateConfirmed TxState = "confirmed"
+	// TxStateFailed means the transaction wasn't included in any block for too long, so it is not rebroadcast
+	// anymore and its requests are reported as failed.
+	TxStateFailed TxState = "failed"
+)
+
+// TxRequest identifies the funding request included in the transaction. Many requests are batched into
+// a single transaction, so this is how the grant is found in it.
+type TxRequest struct {
+	RequestID string
+	Address   string
+	// RequiredConfirmations overrides the number of confirmations required to report the request as confirmed,
+	// 0 means the default of the tracker.
+	RequiredConfirmations int64
+}
+
+// TxStatus is the status of a transaction tracked by the TxTracker.
+type TxStatus struct {
+	TxHash        string
+	State         TxState
+	Height        int64
+	Confirmations int64
+	// RequiredConfirmations is the number of confirmations required to report the state as confirmed.
+	RequiredConfirmations int64
+	Rebroadcasts          int
+	Requests              []TxRequest
+}
+
+// ChainClient is the chain functionality required to track the transactions.
+type ChainClient interface {
+	// TxHeight returns the height of the block the transaction is included in, or 0 if the transaction is not found.
+	TxHeight(ctx context.Context, txHash string) (int64, error)
+	LatestHeight(ctx context.Context) (int64, error)
+	BroadcastRawTx(ctx context.Context, txBytes []byte) error
+}
+
+type trackedTx struct {
+	status    TxStatus
+	txBytes   []byte
+	trackedAt time.Time
+	// notifiedConfirmations is the number of confirmations the last EventConfirmed was published at.
+	notifiedConfirmations int64
+}
+
+// NewTxTracker returns new instance of TxTracker. Confirmed transactions are published on the event bus,
+// which may be nil. The clock measures how long the transactions are tracked.
+func NewTxTracker(chain ChainClient, requiredConfirmations int64, events *EventBus, clk clock.Clock) *TxTracker {
+	return &TxTracker{
+		chain:                 chain,
+		requiredConfirmations: requiredConfirmations,
+		events:                events,
+		clock:                 clk,
+		txs:                   map[string]*trackedTx{},
+		requests:              map[string]string{},
+	}
+}
+
+// TxTracker watches broadcast transactions until they collect the required number of confirmations.
+// If a transaction reported as included disappears from the chain, it is moved back to pending and rebroadcast.
+type TxTracker struct {
+	chain                 ChainClient
+	requiredConfirmations int64
+	events                *EventBus
+	clock                 clock.Clock
+
+	mu  sync.RWMutex
+	txs map[string]*trackedTx
+	// requests maps the IDs of the funding requests to the hashes of the transactions including them.
+	requests map[string]string
+}
+
+// TxBroadcast registers broadcast transaction to be tracked.
+func (t *TxTracker) TxBroadcast(txHash string, height int64, txBytes []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tx := &trackedTx{
+		status: TxStatus{
+			TxHash: txHash,
+			State:  TxStatePending,
+		},
+		txBytes:   txBytes,
+		trackedAt: t.clock.Now(),
+	}
+	if height > 0 {
+		tx.status.State = TxStateIncluded
+		tx.status.Height = height
+		tx.status.Confirmations = 1
+	}
+	t.txs[txHash] = tx
+}
+
+// RequiredConfirmations returns the number of confirmations required by the request, resolving 0 to the default
+// of the tracker. It returns ErrInvalidConfirmations if the number is out of range.
+func (t *TxTracker) RequiredConfirmations(requested int64) (int64, error) {
+	if requested == 0 {
+		return t.requiredConfirmations, nil
+	}
+	if requested < 1 || requested > MaxTxConfirmations {
+		return 0, errors.Wrapf(ErrInvalidConfirmations, "number of confirmations must be between 1 and %d",
+			MaxTxConfirmations)
+	}
+	return requested, nil
+}
+
+// AddRequest records that the funding request is included in the tracked transaction.
+// Requests of transactions not being tracked are ignored.
+func (t *TxTracker) AddRequest(txHash string, request TxRequest) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if tx, exists := t.txs[txHash]; exists {
+		tx.status.Requests = append(tx.status.Requests, request)
+		if request.RequestID != "" {
+			t.requests[request.RequestID] = txHash
+		}
+	}
+}
+
+// Status returns the status of the tracked transaction.
+func (t *TxTracker) Status(txHash string) (TxStatus, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.statusLocked(txHash)
+}
+
+// RequestStatus returns the status of the tracked transaction including the funding request.
+func (t *TxTracker) RequestStatus(requestID string) (TxStatus, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	txHash, exists := t.requests[requestID]
+	if !exists {
+		return TxStatus{}, errors.Wrapf(ErrTxNotFound, "tx of request %q is not tracked", requestID)
+	}
+	status, err := t.statusLocked(txHash)
+	if err != nil {
+		return TxStatus{}, err
+	}
+	// the state of the request is reported against the number of confirmations it requires
+	for _, request := range status.Requests {
+		if request.RequestID != requestID || request.RequiredConfirmations == 0 {
+			continue
+		}
+		status.RequiredConfirmations = request.RequiredConfirmations
+		if status.State != TxStatePending && status.State != TxStateFailed {
+			status.State = TxStateIncluded
+			if status.Confirmations >= status.RequiredConfirmations {
+				status.State = TxStateConfirmed
+			}
+		}
+	}
+	return status, nil
+}
+
+func (t *TxTracker) statusLocked(txHash string) (TxStatus, error) {
+	tx, exists := t.txs[txHash]
+	if !exists {
+		return TxStatus{}, errors.Wrapf(ErrTxNotFound, "tx %q is not tracked", txHash)
+	}
+	status := tx.status
+	status.RequiredConfirmations = t.requiredConfirmations
+	status.Requests = append([]TxRequest{}, tx.status.Requests...)
+	return status, nil
+}
+
+func (t *TxTracker) requestConfirmations(request TxRequest) int64 {
+	if request.RequiredConfirmations > 0 {
+		return request.RequiredConfirmations
+	}
+	return t.requiredConfirmations
+}
+
+// settled tells if the transaction collected the confirmations required by all its requests or failed, so it
+// doesn't have to be tracked anymore.
+func (t *TxTracker) settled(tx *trackedTx) bool {
+	if tx.status.State == TxStateFailed {
+		return true
+	}
+	if tx.status.State != TxStateConfirmed {
+		return false
+	}
+	for _, request := range tx.status.Requests {
+		if tx.status.Confirmations < t.requestConfirmations(request) {
+			return false
+		}
+	}
+	return true
+}
+
+// confirmedRequest tells if the transaction reached the number of confirmations required by any of its
+// requests since EventConfirmed was published last time.
+func (t *TxTracker) confirmedRequest(tx *trackedTx) bool {
+	if tx.status.Confirmations <= tx.notifiedConfirmations {
+		return false
+	}
+	if tx.status.Confirmations >= t.requiredConfirmations && tx.notifiedConfirmations < t.requiredConfirmations {
+		return true
+	}
+	for _, request := range tx.status.Requests {
+		required := t.requestConfirmations(request)
+		if tx.status.Confirmations >= required && tx.notifiedConfirmations < required {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs the confirmation worker.
+func (t *TxTracker) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.WithStack(ctx.Err())
+		case <-time.After(txTrackerPollInterval):
+			if err := t.poll(ctx); err != nil {
+				logger.Get(ctx).Error("Tracking transactions failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (t *TxTracker) poll(ctx context.Context) error {
+	t.prune()
+
+	latestHeight, err := t.chain.LatestHeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, txHash := range t.unconfirmed() {
+		if err := t.update(ctx, txHash, latestHeight); err != nil {
+			logger.Get(ctx).Error("Updating transaction status failed", zap.String("txHash", txHash), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (t *TxTracker) update(ctx context.Context, txHash string, latestHeight int64) error {
+	height, err := t.chain.TxHeight(ctx, txHash)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	tx := t.txs[txHash]
+	if height > 0 {
+		tx.status.Height = height
+		tx.status.Confirmations = latestHeight - height + 1
+		tx.status.State = TxStateIncluded
+		if tx.status.Confirmations >= t.requiredConfirmations {
+			tx.status.State = TxStateConfirmed
+		}
+		confirmations := tx.status.Confirmations
+		notify := t.confirmedRequest(tx)
+		if notify {
+			tx.notifiedConfirmations = confirmations
+		}
+		t.mu.Unlock()
+		if notify {
+			t.events.Publish(ctx, Event{Kind: EventConfirmed, TxHash: txHash, Confirmations: confirmations})
+		}
+		return nil
+	}
+
+	if tx.status.State != TxStatePending {
+		logger.Get(ctx).Warn("Transaction disappeared from the chain, rebroadcasting",
+			zap.String("txHash", txHash), zap.Int64("height", tx.status.Height))
+	}
+	if tx.status.Rebroadcasts >= txTrackerMaxRebroadcasts || t.clock.Now().Sub(tx.trackedAt) > txTrackerMaxPendingAge {
+		tx.status.State = TxStateFailed
+		tx.status.Height = 0
+		tx.status.Confirmations = 0
+		// the bytes aren't needed anymore, the failed transaction is kept only to report its state
+		tx.txBytes = nil
+		requests := append([]TxRequest{}, tx.status.Requests...)
+		rebroadcasts := tx.status.Rebroadcasts
+		t.mu.Unlock()
+
+		logger.Get(ctx).Error("Transaction is not included in any block, giving up",
+			zap.String("txHash", txHash), zap.Int("rebroadcasts", rebroadcasts))
+		for _, request := range requests {
+			t.events.Publish(ctx, Event{
+				Kind:      EventFailed,
+				Requester: Requester{RequestID: request.RequestID},
+				Address:   request.Address,
+				TxHash:    txHash,
+				Reason:    "transaction is not included in any block",
+			})
+		}
+		return nil
+	}
+	tx.status.State = TxStatePending
+	tx.status.Height = 0
+	tx.status.Confirmations = 0
+	tx.status.Rebroadcasts++
+	txBytes := tx.txBytes
+	t.mu.Unlock()
+
+	return t.chain.BroadcastRawTx(ctx, txBytes)
+}
+
+func (t *TxTracker) unconfirmed() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var txHashes []string
+	for txHash, tx := range t.txs {
+		if !t.settled(tx) {
+			txHashes = append(txHashes, txHash)
+		}
+	}
+	return txHashes
+}
+
+func (t *TxTracker) prune() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for txHash, tx := range t.txs {
+		if t.settled(tx) && t.clock.Now().Sub(tx.trackedAt) > txTrackerRetention {
+			delete(t.txs, txHash)
+			for _, request := range tx.status.Requests {
+				// the ID might be reused by the later request
+				if t.requests[request.RequestID] == txHash {
+					delete(t.requests, request.RequestID)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockChainClient struct {
+	latestHeight int64
+	txHeights    map[string]int64
+	broadcasts   [][]byte
+}
+
+func (m *mockChainClient) TxHeight(ctx context.Context, txHash string) (int64, error) {
+	return m.txHeights[txHash], nil
+}
+
+func (m *mockChainClient) LatestHeight(ctx context.Context) (int64, error) {
+	return m.latestHeight, nil
+}
+
+func (m *mockChainClient) BroadcastRawTx(ctx context.Context, txBytes []byte) error {
+	m.broadcasts = append(m.broadcasts, txBytes)
+	return nil
+}
+
+func TestTxTracker_Reorg(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+	chain := &mockChainClient{
+		latestHeight: 10,
+		txHeights:    map[string]int64{"tx1": 10},
+	}
+	tracker := NewTxTracker(chain, 3, nil, clock.System{})
+	tracker.TxBroadcast("tx1", 10, []byte("tx1-bytes"))
+
+	status, err := tracker.Status("tx1")
+	requireT.NoError(err)
+	assertT.Equal(TxStateIncluded, status.State)
+	assertT.EqualValues(1, status.Confirmations)
+
+	// the block containing the tx disappears
+	delete(chain.txHeights, "tx1")
+	chain.latestHeight = 11
+	requireT.NoError(tracker.poll(ctx))
+	status, err = tracker.Status("tx1")
+	requireT.NoError(err)
+	assertT.Equal(TxStatePending, status.State)
+	assertT.EqualValues(0, status.Height)
+	assertT.Equal(1, status.Rebroadcasts)
+	assertT.Equal([][]byte{[]byte("tx1-bytes")}, chain.broadcasts)
+
+	// the tx is included again in another block
+	chain.txHeights["tx1"] = 12
+	chain.latestHeight = 13
+	requireT.NoError(tracker.poll(ctx))
+	status, err = tracker.Status("tx1")
+	requireT.NoError(err)
+	assertT.Equal(TxStateIncluded, status.State)
+	assertT.EqualValues(12, status.Height)
+	assertT.EqualValues(2, status.Confirmations)
+
+	chain.latestHeight = 14
+	requireT.NoError(tracker.poll(ctx))
+	status, err = tracker.Status("tx1")
+	requireT.NoError(err)
+	assertT.Equal(TxStateConfirmed, status.State)
+	assertT.EqualValues(3, status.Confirmations)
+}
+
+func TestTxTracker_NotFound(t *testing.T) {
+	tracker := NewTxTracker(&mockChainClient{}, 1, nil, clock.System{})
+	_, err := tracker.Status("unknown")
+	assert.ErrorIs(t, err, ErrTxNotFound)
+}
+
+func TestTxTracker_Requests(t *testing.T) {
+	requireT := require.New(t)
+
+	tracker := NewTxTracker(&mockChainClient{}, 1, nil, clock.System{})
+	tracker.TxBroadcast("tx1", 0, nil)
+	tracker.AddRequest("tx1", TxRequest{RequestID: "rq1", Address: "addr1"})
+	tracker.AddRequest("tx1", TxRequest{RequestID: "rq2", Address: "addr2"})
+	tracker.AddRequest("unknown", TxRequest{RequestID: "rq3", Address: "addr3"})
+
+	status, err := tracker.Status("tx1")
+	requireT.NoError(err)
+	requireT.Equal([]TxRequest{
+		{RequestID: "rq1", Address: "addr1"},
+		{RequestID: "rq2", Address: "addr2"},
+	}, status.Requests)
+
+	_, err = tracker.Status("unknown")
+	requireT.ErrorIs(err, ErrTxNotFound)
+}
+
+func TestTxTracker_RequestStatus(t *testing.T) {
+	requireT := require.New(t)
+
+	tracker := NewTxTracker(&mockChainClient{}, 1, nil, clock.System{})
+	tracker.TxBroadcast("tx1", 10, nil)
+	tracker.AddRequest("tx1", TxRequest{RequestID: "rq1", Address: "addr1"})
+	tracker.AddRequest("tx1", TxRequest{RequestID: "rq2", Address: "addr2"})
+	// requests of transactions not being tracked are ignored
+	tracker.AddRequest("tx2", TxRequest{RequestID: "rq3", Address: "addr3"})
+
+	status, err := tracker.RequestStatus("rq2")
+	requireT.NoError(err)
+	requireT.Equal("tx1", status.TxHash)
+	requireT.Equal(TxStateIncluded, status.State)
+
+	_, err = tracker.RequestStatus("rq3")
+	requireT.ErrorIs(err, ErrTxNotFound)
+}
+
+func TestTxTracker_RequiredConfirmations(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+	chain := &mockChainClient{
+		latestHeight: 10,
+		txHeights:    map[string]int64{"tx1": 10},
+	}
+	tracker := NewTxTracker(chain, 1, nil, clock.System{})
+
+	required, err := tracker.RequiredConfirmations(0)
+	requireT.NoError(err)
+	assertT.EqualValues(1, required)
+	required, err = tracker.RequiredConfirmations(5)
+	requireT.NoError(err)
+	assertT.EqualValues(5, required)
+	for _, invalid := range []int64{-1, MaxTxConfirmations + 1} {
+		_, err = tracker.RequiredConfirmations(invalid)
+		requireT.ErrorIs(err, ErrInvalidConfirmations)
+	}
+
+	tracker.TxBroadcast("tx1", 0, nil)
+	tracker.AddRequest("tx1", TxRequest{RequestID: "rq1", Address: "addr1"})
+	tracker.AddRequest("tx1", TxRequest{RequestID: "rq2", Address: "addr2", RequiredConfirmations: 3})
+
+	requireT.NoError(tracker.poll(ctx))
+	status, err := tracker.Status("tx1")
+	requireT.NoError(err)
+	assertT.Equal(TxStateConfirmed, status.State)
+	assertT.EqualValues(1, status.RequiredConfirmations)
+
+	status, err = tracker.RequestStatus("rq1")
+	requireT.NoError(err)
+	assertT.Equal(TxStateConfirmed, status.State)
+
+	status, err = tracker.RequestStatus("rq2")
+	requireT.NoError(err)
+	assertT.Equal(TxStateIncluded, status.State)
+	assertT.EqualValues(3, status.RequiredConfirmations)
+
+	// the transaction is still tracked until it collects the confirmations required by all the requests
+	assertT.Equal([]string{"tx1"}, tracker.unconfirmed())
+	chain.latestHeight = 12
+	requireT.NoError(tracker.poll(ctx))
+	status, err = tracker.RequestStatus("rq2")
+	requireT.NoError(err)
+	assertT.Equal(TxStateConfirmed, status.State)
+	assertT.EqualValues(3, status.Confirmations)
+	assertT.Empty(tracker.unconfirmed())
+}
+
+func TestTxTracker_GiveUp(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+	bus := NewEventBus()
+	// the bus is not run, so events stay queued for the subscriber
+	bus.Subscribe("test", func(ctx context.Context, event Event) {})
+
+	chain := &mockChainClient{latestHeight: 10, txHeights: map[string]int64{}}
+	clk := clock.NewManual(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	tracker := NewTxTracker(chain, 1, bus, clk)
+	tracker.TxBroadcast("tx1", 0, []byte("tx1-bytes"))
+	tracker.AddRequest("tx1", TxRequest{RequestID: "req1", Address: "devcore1a"})
+
+	// the transaction is rebroadcast a limited number of times
+	for i := 0; i < txTrackerMaxRebroadcasts; i++ {
+		requireT.NoError(tracker.poll(ctx))
+	}
+	tracker.TxBroadcast("tx2", 0, []byte("tx2-bytes"))
+	tracker.AddRequest("tx2", TxRequest{RequestID: "req2", Address: "devcore1b"})
+	clk.Advance(txTrackerMaxPendingAge + time.Second)
+	requireT.NoError(tracker.poll(ctx))
+	status, err := tracker.Status("tx1")
+	requireT.NoError(err)
+	assertT.Equal(TxStateFailed, status.State)
+	assertT.Equal(txTrackerMaxRebroadcasts, status.Rebroadcasts)
+	status, err = tracker.RequestStatus("req1")
+	requireT.NoError(err)
+	assertT.Equal(TxStateFailed, status.State)
+	requireT.Len(chain.broadcasts, txTrackerMaxRebroadcasts)
+
+	// the transaction pending for too long is given up too
+	status, err = tracker.Status("tx2")
+	requireT.NoError(err)
+	assertT.Equal(TxStateFailed, status.State)
+	assertT.Zero(status.Rebroadcasts)
+
+	// the requests are reported as failed and the failed transactions aren't rebroadcast anymore
+	requireT.Len(bus.subscriptions[0].events, 2)
+	event := <-bus.subscriptions[0].events
+	assertT.Equal(EventFailed, event.Kind)
+	requireT.NoError(tracker.poll(ctx))
+	requireT.Len(chain.broadcasts, txTrackerMaxRebroadcasts)
+
+	// and are evicted once the retention passes
+	clk.Advance(txTrackerRetention)
+	tracker.prune()
+	_, err = tracker.Status("tx1")
+	requireT.ErrorIs(err, ErrTxNotFound)
+	_, err = tracker.RequestStatus("req1")
+	requireT.ErrorIs(err, ErrTxNotFound)
+}
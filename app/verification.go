@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+)
+
+// Proofs are the proofs of the client being a human, keyed by the name of the verifier checking them.
+type Proofs map[string]string
+
+// Verifier verifies the client requesting the funds is a human, e.g. by captcha, proof of work or OAuth.
+// The proofs are passed through by the transport as they are, so new verifiers are added without changing it.
+type Verifier interface {
+	// Name identifies the verifier and its proof in Requester.Proofs.
+	Name() string
+	// Verify checks the proof presented by the requester, empty if there is none. The error describes why
+	// the requester is refused, e.g. ErrCaptchaFailed.
+	Verify(ctx context.Context, proof string, requester Requester) error
+}
+
+// WithVerifiers returns a copy of the app funding only the clients passing all the verifiers, in addition to
+// the ones configured already. Verifiers are run in order and the first failure refuses the request. Requests
+// authenticated by the admin token, API key or bypass token are exempt, because they are sent by automation.
+func (a App) WithVerifiers(verifiers ...Verifier) App {
+	a.verifiers = append(append([]Verifier{}, a.verifiers...), verifiers...)
+	return a
+}
+
+// verify runs the verifiers against the requester.
+func (a App) verify(ctx context.Context, requester Requester) error {
+	if requester.Admin || requester.APIKeyHolder != "" || requester.BypassTokenID != "" {
+		return nil
+	}
+	for _, v := range a.verifiers {
+		if err := v.Verify(ctx, requester.Proofs[v.Name()], requester); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+var errMockVerification = errors.New("verification failed")
+
+type mockVerifier struct {
+	name  string
+	calls *[]string
+}
+
+func (m mockVerifier) Name() string {
+	return m.name
+}
+
+func (m mockVerifier) Verify(_ context.Context, proof string, _ Requester) error {
+	*m.calls = append(*m.calls, m.name)
+	if proof != "ok" {
+		return errors.Wrapf(errMockVerification, "%s: proof %q", m.name, proof)
+	}
+	return nil
+}
+
+func TestGiveFundsVerifiers(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+
+	var calls []string
+	a := New(&mockBatcher{txHash: "tx1"}, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{},
+		network, chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))).
+		WithVerifiers(mockVerifier{name: "pow", calls: &calls}).
+		WithVerifiers(mockVerifier{name: "oauth", calls: &calls})
+
+	// the first failure refuses the request
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{"oauth": "ok"}}, address)
+	requireT.ErrorIs(err, errMockVerification)
+	requireT.Equal([]string{"pow"}, calls)
+
+	calls = nil
+	_, err = a.GiveFunds(ctx, Requester{Proofs: Proofs{"pow": "ok", "oauth": "forged"}}, address)
+	requireT.ErrorIs(err, errMockVerification)
+	requireT.Equal([]string{"pow", "oauth"}, calls)
+
+	calls = nil
+	txHash, err := a.GiveFunds(ctx, Requester{Proofs: Proofs{"pow": "ok", "oauth": "ok"}}, address)
+	requireT.NoError(err)
+	requireT.Equal("tx1", txHash)
+	requireT.Equal([]string{"pow", "oauth"}, calls)
+
+	// automation is exempt
+	calls = nil
+	_, err = a.GiveFunds(ctx, Requester{BypassTokenID: "fbt_1"}, address)
+	requireT.NoError(err)
+	requireT.Empty(calls)
+}
+
+func TestGenMnemonicAndFundVerifiers(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	scorer, err := NewAbuseScorer(AbuseScoringConfig{
+		Weights:       map[string]float64{AbuseSignalIPReputation: 100},
+		DenyThreshold: 80,
+	}, nil, nil, clk)
+	requireT.NoError(err)
+
+	var calls []string
+	batcher := &mockBatcher{txHash: "tx1"}
+	a := New(batcher, nil, NewTxTracker(nil, 1, nil, clock.System{}), &mockHistory{}, &mockLedger{}, network,
+		chain.NewCoin("udevcore", chain.NewInt(1000))).
+		WithClock(clk).
+		WithVerifiers(mockVerifier{name: "pow", calls: &calls}).
+		WithAbuseScoring(scorer)
+
+	// generated accounts are not a way around the verifiers
+	_, err = a.GenMnemonicAndFund(ctx, Requester{}, "")
+	requireT.ErrorIs(err, errMockVerification)
+	requireT.Equal([]string{"pow"}, calls)
+	requireT.Zero(batcher.calls)
+
+	// nor the abuse score
+	_, err = a.GenMnemonicAndFund(ctx, Requester{IPReputation: "tor", Proofs: Proofs{"pow": "ok"}}, "")
+	requireT.ErrorIs(err, ErrAbuseSuspected)
+	requireT.Zero(batcher.calls)
+
+	result, err := a.GenMnemonicAndFund(ctx, Requester{Proofs: Proofs{"pow": "ok"}}, "")
+	requireT.NoError(err)
+	requireT.Equal("tx1", result.TxHash)
+	requireT.Equal(1, batcher.calls)
+
+	// automation is exempt
+	calls = nil
+	_, err = a.GenMnemonicAndFund(ctx, Requester{APIKeyHolder: "ci", IPReputation: "tor"}, "")
+	requireT.NoError(err)
+	requireT.Empty(calls)
+}
@@ -0,0 +1,326 @@
+// Package callback notifies the URLs supplied by the clients once the transactions funding their requests
+// are confirmed, so automation doesn't need to poll the tx endpoint.
+package callback
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/egress"
+)
+
+// HeaderSignature carries the signature of the callback in the format t=<unix time>,v1=<hex HMAC-SHA256>,
+// where the HMAC is computed with the signing key over <unix time>.<body>.
+const HeaderSignature = "X-Faucet-Signature"
+
+// MinSigningKeyLength is the minimal length of the key signing the callbacks.
+const MinSigningKeyLength = 16
+
+const (
+	// maxPending is the number of requests waiting for confirmation above which the oldest ones are dropped.
+	maxPending = 10000
+	// pendingTTL is how long the request waits for confirmation of its transaction.
+	pendingTTL   = time.Hour
+	timeout      = 10 * time.Second
+	maxURLLength = 2048
+)
+
+// Payload is the JSON body posted to the callback URL.
+type Payload struct {
+	RequestID string `json:"requestId"`
+	Address   string `json:"address"`
+	TxHash    string `json:"txHash"`
+	Status    string `json:"status"`
+	// Confirmations is the number of confirmations the transaction collected.
+	Confirmations int64     `json:"confirmations,omitempty"`
+	Time          time.Time `json:"time"`
+}
+
+type pending struct {
+	url       string
+	requestID string
+	address   string
+	addedAt   time.Time
+	// minConfirmations is the number of confirmations the request requires before it is notified.
+	minConfirmations int64
+}
+
+// Notifier posts the signed result of the funding to the callback URL once the transaction is confirmed.
+type Notifier struct {
+	allowedHosts []string
+	signingKey   []byte
+	client       *http.Client
+
+	mu      sync.Mutex
+	pending map[string][]pending
+	count   int
+}
+
+// NewNotifier returns the notifier accepting callback URLs of the allowed hosts. Host prefixed with "*." allows
+// all its subdomains. Callbacks are sent through the outbound proxy by the client like the one returned by
+// NewHTTPClient, additionally refusing to connect to the hosts which are not allowed.
+func NewNotifier(allowedHosts []string, signingKey string, outbound egress.Proxy) (*Notifier, error) {
+	n, err := newNotifier(allowedHosts, signingKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	n.client = newHTTPClient(timeout, outbound, n.hostAllowed)
+	return n, nil
+}
+
+func newNotifier(allowedHosts []string, signingKey string, client *http.Client) (*Notifier, error) {
+	if len(allowedHosts) == 0 {
+		return nil, errors.New("at least one callback host must be allowed")
+	}
+	if len(signingKey) < MinSigningKeyLength {
+		return nil, errors.Errorf("callback signing key must be at least %d characters long", MinSigningKeyLength)
+	}
+	hosts := make([]string, 0, len(allowedHosts))
+	for _, h := range allowedHosts {
+		hosts = append(hosts, strings.ToLower(h))
+	}
+	return &Notifier{
+		allowedHosts: hosts,
+		signingKey:   []byte(signingKey),
+		client:       client,
+		pending:      map[string][]pending{},
+	}, nil
+}
+
+// ValidateCallbackURL verifies that the URL uses https, its host is allowed and it is not an internal IP.
+// Names resolving to internal IPs are rejected when the callback is sent.
+func (n *Notifier) ValidateCallbackURL(rawURL string) error {
+	if len(rawURL) > maxURLLength {
+		return errors.Wrapf(app.ErrInvalidCallbackURL, "URL is longer than %d characters", maxURLLength)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.Wrap(app.ErrInvalidCallbackURL, "malformed URL")
+	}
+	if u.Scheme != "https" {
+		return errors.Wrap(app.ErrInvalidCallbackURL, "https is required")
+	}
+	if u.User != nil {
+		return errors.Wrap(app.ErrInvalidCallbackURL, "credentials in URL are not allowed")
+	}
+	host := strings.ToLower(u.Hostname())
+	if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+		return errors.Wrapf(app.ErrInvalidCallbackURL, "%s is not a public IP", host)
+	}
+	if !n.hostAllowed(host) {
+		return errors.Wrapf(app.ErrInvalidCallbackURL, "host %q is not allowed", host)
+	}
+	return nil
+}
+
+func (n *Notifier) hostAllowed(host string) bool {
+	for _, allowed := range n.allowedHosts {
+		if suffix := strings.TrimPrefix(allowed, "*"); suffix != allowed {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle is the event bus subscriber remembering broadcast requests having callback URL and notifying them once
+// their transaction collects the number of confirmations they require.
+func (n *Notifier) Handle(ctx context.Context, event app.Event) {
+	switch event.Kind {
+	case app.EventBroadcast:
+		if event.Requester.CallbackURL != "" {
+			n.add(event)
+		}
+	case app.EventConfirmed:
+		for _, p := range n.take(event.TxHash, event.Confirmations) {
+			payload := Payload{
+				RequestID:     p.requestID,
+				Address:       p.address,
+				TxHash:        event.TxHash,
+				Status:        string(app.TxStateConfirmed),
+				Confirmations: event.Confirmations,
+				Time:          event.Time,
+			}
+			if err := n.send(ctx, p.url, payload); err != nil {
+				logger.Get(ctx).Warn("Sending callback failed",
+					zap.String("requestID", p.requestID), zap.String("txHash", event.TxHash), zap.Error(err))
+			}
+		}
+	default:
+	}
+}
+
+func (n *Notifier) add(event app.Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.count >= maxPending {
+		n.pruneLocked(event.Time)
+	}
+	if n.count >= maxPending {
+		return
+	}
+	n.pending[event.TxHash] = append(n.pending[event.TxHash], pending{
+		url:       event.Requester.CallbackURL,
+		requestID: event.Requester.RequestID,
+		address:   event.Address,
+		addedAt:   event.Time,
+
+		minConfirmations: event.Requester.MinConfirmations,
+	})
+	n.count++
+}
+
+// pruneLocked drops the requests whose transactions are not confirmed in time, most probably they never will be.
+func (n *Notifier) pruneLocked(now time.Time) {
+	for txHash, requests := range n.pending {
+		if now.Sub(requests[0].addedAt) > pendingTTL {
+			n.count -= len(requests)
+			delete(n.pending, txHash)
+		}
+	}
+}
+
+// take removes and returns the requests of the transaction satisfied by the number of confirmations.
+func (n *Notifier) take(txHash string, confirmations int64) []pending {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var taken, left []pending
+	for _, p := range n.pending[txHash] {
+		if p.minConfirmations <= confirmations {
+			taken = append(taken, p)
+		} else {
+			left = append(left, p)
+		}
+	}
+	n.count -= len(taken)
+	if len(left) == 0 {
+		delete(n.pending, txHash)
+	} else {
+		n.pending[txHash] = left
+	}
+	return taken
+}
+
+func (n *Notifier) send(ctx context.Context, callbackURL string, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderSignature, Sign(n.signingKey, time.Now().Unix(), body))
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "posting callback failed")
+	}
+	defer res.Body.Close()
+	if res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return errors.Errorf("callback returned non 2xx response, status: %d, body: %s", res.StatusCode, body)
+	}
+	return nil
+}
+
+// Sign returns the value of the signature header of the body sent at the time.
+func Sign(key []byte, timestamp int64, body []byte) string {
+	t := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(t + "."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%s,v1=%s", t, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// NewHTTPClient returns the client connecting through the outbound proxy to public IPs only and not following
+// redirects, so the callback can't be used to reach the internal network, also by names resolving to internal IPs.
+// The host is resolved before connecting and the proxy is asked to connect to the verified IP, not to the name,
+// so the proxy can't be pointed to the internal network either.
+func NewHTTPClient(timeout time.Duration, outbound egress.Proxy) *http.Client {
+	return newHTTPClient(timeout, outbound, nil)
+}
+
+func newHTTPClient(timeout time.Duration, outbound egress.Proxy, hostAllowed func(host string) bool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// the connection is routed through the outbound proxy by the dialer, after the target is verified
+	transport.Proxy = nil
+	transport.DialContext = guardedDialer(outbound, hostAllowed)
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// guardedDialer returns the dial function verifying that the host is allowed and all its IPs are public before
+// connecting to one of them through the outbound proxy. Nil hostAllowed allows all hosts.
+func guardedDialer(
+	outbound egress.Proxy,
+	hostAllowed func(host string) bool,
+) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if hostAllowed != nil && !hostAllowed(strings.ToLower(host)) {
+			return nil, errors.Errorf("connecting to %s is not allowed", host)
+		}
+		ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+		if err != nil {
+			return nil, errors.Wrapf(err, "resolving %s failed", host)
+		}
+		for _, ip := range ips {
+			if !isPublicIP(ip) {
+				return nil, errors.Errorf("connecting to %s is not allowed", ip)
+			}
+		}
+		for _, ip := range ips {
+			var conn net.Conn
+			conn, err = outbound.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+		}
+		return nil, err
+	}
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), not covered by net.IP.IsPrivate.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func isPublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !sharedAddressSpace.Contains(ip)
+}
@@ -0,0 +1,57 @@
+// Package captcha verifies the captcha tokens solved by the users of the faucet UI, so the funds are not drained
+// by scripts.
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// siteverifyResponse is the response of the verification endpoints of reCAPTCHA, hCaptcha and Turnstile, they
+// share the protocol.
+type siteverifyResponse struct {
+	Success    bool     `json:"success"`
+	Score      *float64 `json:"score"`
+	Hostname   string   `json:"hostname"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// siteverify posts the form to the verification endpoint. It returns app.ErrCaptchaFailed if the token
+// is rejected and app.ErrCaptchaUnavailable if the endpoint can't be asked.
+func siteverify(
+	ctx context.Context,
+	client *http.Client,
+	verifyURL string,
+	form url.Values,
+) (siteverifyResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return siteverifyResponse{}, errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return siteverifyResponse{}, errors.Wrapf(app.ErrCaptchaUnavailable, "err:%s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return siteverifyResponse{}, errors.Wrapf(app.ErrCaptchaUnavailable, "unexpected status %d", resp.StatusCode)
+	}
+	var result siteverifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return siteverifyResponse{}, errors.Wrapf(app.ErrCaptchaUnavailable, "invalid response: %s", err)
+	}
+	if !result.Success {
+		return siteverifyResponse{}, errors.Wrapf(app.ErrCaptchaFailed, "token rejected: %s",
+			strings.Join(result.ErrorCodes, ","))
+	}
+	return result, nil
+}
@@ -0,0 +1,49 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// HcaptchaVerifyURL is the endpoint verifying hCaptcha tokens.
+const HcaptchaVerifyURL = "https://api.hcaptcha.com/siteverify"
+
+// Hcaptcha verifies hCaptcha tokens, for the deployments which can't use Google services.
+type Hcaptcha struct {
+	siteKey   string
+	secret    string
+	verifyURL string
+	client    *http.Client
+}
+
+// NewHcaptcha returns the verifier using the site key and the secret key of the account. The site key is optional,
+// if set, the tokens solved on other sites of the account are rejected.
+func NewHcaptcha(siteKey, secret string, client *http.Client) (*Hcaptcha, error) {
+	if secret == "" {
+		return nil, errors.New("hCaptcha secret is required")
+	}
+	return &Hcaptcha{
+		siteKey:   siteKey,
+		secret:    secret,
+		verifyURL: HcaptchaVerifyURL,
+		client:    client,
+	}, nil
+}
+
+// VerifyCaptcha verifies the token solved by the client of the IP. It returns app.ErrCaptchaFailed if the token
+// is rejected and app.ErrCaptchaUnavailable if hCaptcha can't be asked.
+func (h *Hcaptcha) VerifyCaptcha(ctx context.Context, token, remoteIP string) error {
+	form := url.Values{"secret": {h.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	if h.siteKey != "" {
+		form.Set("sitekey", h.siteKey)
+	}
+	// the score of hCaptcha Enterprise is the risk of the bot, not comparable with reCAPTCHA, so it is not checked
+	_, err := siteverify(ctx, h.client, h.verifyURL, form)
+	return err
+}
@@ -0,0 +1,45 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestHcaptcha(t *testing.T) {
+	requireT := require.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("secret") != "secret" ||
+			r.PostForm.Get("sitekey") != "site-key" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.PostForm.Get("response") {
+		case "solved":
+			_, _ = w.Write([]byte(`{"success": true, "hostname": "faucet.example.com"}`))
+		default:
+			_, _ = w.Write([]byte(`{"success": false, "error-codes": ["invalid-input-response"]}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	h, err := NewHcaptcha("site-key", "secret", srv.Client())
+	requireT.NoError(err)
+	h.verifyURL = srv.URL
+
+	ctx := context.Background()
+	requireT.NoError(h.VerifyCaptcha(ctx, "solved", "203.0.113.1"))
+	requireT.ErrorIs(h.VerifyCaptcha(ctx, "invalid", "203.0.113.1"), app.ErrCaptchaFailed)
+
+	h.siteKey = "other-site-key"
+	requireT.ErrorIs(h.VerifyCaptcha(ctx, "solved", "203.0.113.1"), app.ErrCaptchaUnavailable)
+
+	_, err = NewHcaptcha("site-key", "", srv.Client())
+	requireT.Error(err)
+}
@@ -0,0 +1,73 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// RecaptchaVerifyURL is the endpoint of Google verifying reCAPTCHA tokens.
+const RecaptchaVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
+
+// Recaptcha verifies reCAPTCHA v2 and v3 tokens against Google.
+type Recaptcha struct {
+	secret    string
+	minScore  float64
+	verifyURL string
+	client    *http.Client
+}
+
+// NewRecaptcha returns the verifier using the secret key of the site. Tokens of reCAPTCHA v3 scored below minScore
+// are rejected, tokens of v2 carry no score and are accepted once solved.
+func NewRecaptcha(secret string, minScore float64, client *http.Client) (*Recaptcha, error) {
+	if secret == "" {
+		return nil, errors.New("reCAPTCHA secret is required")
+	}
+	if minScore < 0 || minScore > 1 {
+		return nil, errors.Errorf("reCAPTCHA score threshold must be between 0 and 1, got %v", minScore)
+	}
+	return &Recaptcha{
+		secret:    secret,
+		minScore:  minScore,
+		verifyURL: RecaptchaVerifyURL,
+		client:    client,
+	}, nil
+}
+
+// VerifyCaptcha verifies the token solved by the client of the IP. It returns app.ErrCaptchaFailed if the token
+// is rejected and app.ErrCaptchaUnavailable if Google can't be asked.
+func (r *Recaptcha) VerifyCaptcha(ctx context.Context, token, remoteIP string) error {
+	result, err := r.siteverify(ctx, token, remoteIP)
+	if err != nil {
+		return err
+	}
+	if result.Score != nil && *result.Score < r.minScore {
+		return errors.Wrapf(app.ErrCaptchaFailed, "score %v is below %v", *result.Score, r.minScore)
+	}
+	return nil
+}
+
+// ScoreCaptcha verifies the token solved by the client of the IP and returns its score without applying
+// the minimal score, so the abuse scorer may weigh it. Tokens of v2 carry no score, they score 1 once solved.
+func (r *Recaptcha) ScoreCaptcha(ctx context.Context, token, remoteIP string) (float64, error) {
+	result, err := r.siteverify(ctx, token, remoteIP)
+	if err != nil {
+		return 0, err
+	}
+	if result.Score == nil {
+		return 1, nil
+	}
+	return *result.Score, nil
+}
+
+func (r *Recaptcha) siteverify(ctx context.Context, token, remoteIP string) (siteverifyResponse, error) {
+	form := url.Values{"secret": {r.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	return siteverify(ctx, r.client, r.verifyURL, form)
+}
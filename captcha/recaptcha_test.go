@@ -0,0 +1,61 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestRecaptcha(t *testing.T) {
+	requireT := require.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requireT.NoError(r.ParseForm())
+		requireT.Equal("secret", r.PostForm.Get("secret"))
+		requireT.Equal("203.0.113.1", r.PostForm.Get("remoteip"))
+		switch r.PostForm.Get("response") {
+		case "v2":
+			_, _ = w.Write([]byte(`{"success": true}`))
+		case "v3-human":
+			_, _ = w.Write([]byte(`{"success": true, "score": 0.9}`))
+		case "v3-bot":
+			_, _ = w.Write([]byte(`{"success": true, "score": 0.1}`))
+		case "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte(`{"success": false, "error-codes": ["invalid-input-response"]}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	r, err := NewRecaptcha("secret", 0.5, srv.Client())
+	requireT.NoError(err)
+	r.verifyURL = srv.URL
+
+	ctx := context.Background()
+	requireT.NoError(r.VerifyCaptcha(ctx, "v2", "203.0.113.1"))
+	requireT.NoError(r.VerifyCaptcha(ctx, "v3-human", "203.0.113.1"))
+	requireT.ErrorIs(r.VerifyCaptcha(ctx, "v3-bot", "203.0.113.1"), app.ErrCaptchaFailed)
+	requireT.ErrorIs(r.VerifyCaptcha(ctx, "invalid", "203.0.113.1"), app.ErrCaptchaFailed)
+	requireT.ErrorIs(r.VerifyCaptcha(ctx, "broken", "203.0.113.1"), app.ErrCaptchaUnavailable)
+
+	// scores are reported without the threshold applied
+	score, err := r.ScoreCaptcha(ctx, "v3-bot", "203.0.113.1")
+	requireT.NoError(err)
+	requireT.Equal(0.1, score)
+	score, err = r.ScoreCaptcha(ctx, "v2", "203.0.113.1")
+	requireT.NoError(err)
+	requireT.Equal(1.0, score)
+	_, err = r.ScoreCaptcha(ctx, "invalid", "203.0.113.1")
+	requireT.ErrorIs(err, app.ErrCaptchaFailed)
+
+	_, err = NewRecaptcha("", 0.5, srv.Client())
+	requireT.Error(err)
+	_, err = NewRecaptcha("secret", 1.5, srv.Client())
+	requireT.Error(err)
+}
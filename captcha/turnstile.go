@@ -0,0 +1,43 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// TurnstileVerifyURL is the endpoint of Cloudflare verifying Turnstile tokens.
+const TurnstileVerifyURL = "https://challenges.cloudflare.com/turnstile/v0/siteverify"
+
+// Turnstile verifies Cloudflare Turnstile tokens. Turnstile mostly solves the challenge without user interaction,
+// so it suits the deployments fronted by Cloudflare already.
+type Turnstile struct {
+	secret    string
+	verifyURL string
+	client    *http.Client
+}
+
+// NewTurnstile returns the verifier using the secret key of the widget.
+func NewTurnstile(secret string, client *http.Client) (*Turnstile, error) {
+	if secret == "" {
+		return nil, errors.New("Turnstile secret is required")
+	}
+	return &Turnstile{
+		secret:    secret,
+		verifyURL: TurnstileVerifyURL,
+		client:    client,
+	}, nil
+}
+
+// VerifyCaptcha verifies the token solved by the client of the IP. It returns app.ErrCaptchaFailed if the token
+// is rejected and app.ErrCaptchaUnavailable if Cloudflare can't be asked.
+func (t *Turnstile) VerifyCaptcha(ctx context.Context, token, remoteIP string) error {
+	form := url.Values{"secret": {t.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	_, err := siteverify(ctx, t.client, t.verifyURL, form)
+	return err
+}
@@ -0,0 +1,47 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestTurnstile(t *testing.T) {
+	requireT := require.New(t)
+
+	var remoteIP string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("secret") != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		remoteIP = r.PostForm.Get("remoteip")
+		switch r.PostForm.Get("response") {
+		case "solved":
+			_, _ = w.Write([]byte(`{"success": true, "hostname": "faucet.example.com", "action": "fund"}`))
+		case "outage":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte(`{"success": false, "error-codes": ["timeout-or-duplicate"]}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	ts, err := NewTurnstile("secret", srv.Client())
+	requireT.NoError(err)
+	ts.verifyURL = srv.URL
+
+	ctx := context.Background()
+	requireT.NoError(ts.VerifyCaptcha(ctx, "solved", "203.0.113.1"))
+	requireT.Equal("203.0.113.1", remoteIP)
+	requireT.ErrorIs(ts.VerifyCaptcha(ctx, "reused", "203.0.113.1"), app.ErrCaptchaFailed)
+	requireT.ErrorIs(ts.VerifyCaptcha(ctx, "outage", "203.0.113.1"), app.ErrCaptchaUnavailable)
+
+	_, err = NewTurnstile("", srv.Client())
+	requireT.Error(err)
+}
@@ -0,0 +1,84 @@
+package coreum
+
+import (
+	"context"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/faucet/pkg/logger"
+)
+
+// AwaitConfig configures polling for the inclusion of the broadcast transaction in a block. The interval between
+// queries is doubled after each of them, so the node isn't queried many times while the block is produced.
+type AwaitConfig struct {
+	// InitialInterval is the time after the broadcast the transaction is queried first.
+	InitialInterval time.Duration
+	// MaxInterval caps the interval between queries.
+	MaxInterval time.Duration
+	// Timeout is how long to wait for the inclusion before the transfer is reported as failed.
+	Timeout time.Duration
+}
+
+// DefaultAwaitConfig returns the config querying the transaction about once per block of the coreum chain.
+func DefaultAwaitConfig() AwaitConfig {
+	return AwaitConfig{
+		InitialInterval: time.Second,
+		MaxInterval:     5 * time.Second,
+		Timeout:         time.Minute,
+	}
+}
+
+// WithAwaitConfig returns a copy of the client polling for the inclusion of transactions with the config.
+func (c Client) WithAwaitConfig(cfg AwaitConfig) Client {
+	c.awaitConfig = cfg
+	return c
+}
+
+// txQuerier returns the transaction included in a block, an error is returned if it is not found yet.
+type txQuerier func(ctx context.Context, txHash string) (*sdk.TxResponse, error)
+
+func (c Client) awaitTx(ctx context.Context, txHash string) (*sdk.TxResponse, error) {
+	txSvcClient := sdktx.NewServiceClient(c.clientCtx)
+	return awaitTx(ctx, c.awaitConfig, txHash, func(ctx context.Context, txHash string) (*sdk.TxResponse, error) {
+		res, err := txSvcClient.GetTx(ctx, &sdktx.GetTxRequest{Hash: txHash})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return res.TxResponse, nil
+	})
+}
+
+func awaitTx(ctx context.Context, cfg AwaitConfig, txHash string, query txQuerier) (*sdk.TxResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	interval := cfg.InitialInterval
+	for polls := 1; ; polls++ {
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrapf(ctx.Err(), "transaction %q hasn't been included in a block", txHash)
+		case <-time.After(interval):
+		}
+
+		res, err := query(ctx, txHash)
+		switch {
+		case err == nil && res.Code != 0:
+			return nil, errors.Wrapf(sdkerrors.ABCIError(res.Codespace, res.Code, res.Logs.String()),
+				"transaction %q failed", txHash)
+		case err == nil && res.Height > 0:
+			logger.Get(ctx).Debug("Transaction included in a block",
+				zap.String("txHash", txHash), zap.Int64("height", res.Height), zap.Int("polls", polls))
+			return res, nil
+		}
+
+		interval *= 2
+		if interval > cfg.MaxInterval {
+			interval = cfg.MaxInterval
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package coreum
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+)
+
+func TestAwaitTx(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	cfg := AwaitConfig{InitialInterval: time.Millisecond, MaxInterval: 4 * time.Millisecond, Timeout: time.Second}
+	var polls []time.Time
+	res, err := awaitTx(ctx, cfg, "hash", func(ctx context.Context, txHash string) (*sdk.TxResponse, error) {
+		polls = append(polls, time.Now())
+		switch len(polls) {
+		case 1:
+			return nil, errors.New("not found")
+		case 2, 3, 4:
+			return &sdk.TxResponse{TxHash: txHash}, nil
+		default:
+			return &sdk.TxResponse{TxHash: txHash, Height: 10}, nil
+		}
+	})
+	requireT.NoError(err)
+	requireT.EqualValues(10, res.Height)
+	requireT.Len(polls, 5)
+	// intervals are doubled up to the maximum
+	requireT.GreaterOrEqual(polls[2].Sub(polls[1]), 4*time.Millisecond)
+	requireT.GreaterOrEqual(polls[4].Sub(polls[3]), 4*time.Millisecond)
+
+	_, err = awaitTx(ctx, cfg, "hash", func(ctx context.Context, txHash string) (*sdk.TxResponse, error) {
+		return &sdk.TxResponse{TxHash: txHash, Height: 10, Code: 5, Codespace: "sdk"}, nil
+	})
+	requireT.ErrorContains(err, "insufficient funds")
+
+	cfg.Timeout = 20 * time.Millisecond
+	_, err = awaitTx(ctx, cfg, "hash", func(ctx context.Context, txHash string) (*sdk.TxResponse, error) {
+		return nil, errors.New("not found")
+	})
+	requireT.ErrorIs(err, context.DeadlineExceeded)
+}
@@ -2,8 +2,10 @@ package coreum
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -13,30 +15,48 @@ import (
 
 	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
 	"github.com/CoreumFoundation/coreum-tools/pkg/parallel"
+	"github.com/CoreumFoundation/faucet/pkg/attribution"
 )
 
 type mockCoreumClient struct {
-	mu    sync.Mutex
-	calls []clientCall
+	delay time.Duration
+
+	mu        sync.Mutex
+	calls     []clientCall
+	active    int
+	maxActive int
 }
 
 type clientCall struct {
 	fromAddress sdk.AccAddress
+	feeDenom    string
 	requests    []transferRequest
 }
 
 func (mc *mockCoreumClient) TransferToken(
 	ctx context.Context,
 	fromAddress sdk.AccAddress,
+	feeDenom string,
 	requests ...transferRequest,
-) (string, error) {
+) (string, sdk.Coin, error) {
 	mc.mu.Lock()
-	defer mc.mu.Unlock()
 	mc.calls = append(mc.calls, clientCall{
 		fromAddress: fromAddress,
+		feeDenom:    feeDenom,
 		requests:    requests,
 	})
-	return fromAddress.String(), nil
+	mc.active++
+	if mc.active > mc.maxActive {
+		mc.maxActive = mc.active
+	}
+	mc.mu.Unlock()
+
+	time.Sleep(mc.delay)
+
+	mc.mu.Lock()
+	mc.active--
+	mc.mu.Unlock()
+	return fromAddress.String(), sdk.NewCoin(feeDenom, sdk.NewInt(int64(100*len(requests)))), nil
 }
 
 func TestBatchSend(t *testing.T) {
@@ -55,7 +75,7 @@ func TestBatchSend(t *testing.T) {
 	}
 
 	mock := &mockCoreumClient{}
-	batcher := NewBatcher(mock, fundingAddresses, 10)
+	batcher := NewBatcher(mock, fundingAddresses, 10, 0)
 
 	group := parallel.NewGroup(ctx)
 	group.Spawn("batcher", parallel.Fail, batcher.Run)
@@ -68,10 +88,13 @@ func TestBatchSend(t *testing.T) {
 	requestCount := 100
 	wg.Add(requestCount)
 	for i := 0; i < requestCount; i++ {
+		feeDenom := []string{"fee-a", "fee-b"}[i%2]
 		go func() {
-			txHash, err := batcher.SendToken(ctx, nil, amount)
+			txHash, fee, err := batcher.SendToken(ctx, nil, amount, feeDenom, attribution.Attribution{})
 			assertT.NoError(err)
 			assertT.Greater(len(txHash), 1)
+			assertT.Equal(feeDenom, fee.Denom)
+			assertT.EqualValues(100, fee.Amount.Int64())
 			wg.Done()
 		}()
 	}
@@ -84,7 +107,102 @@ func TestBatchSend(t *testing.T) {
 	totalAddressesCount := 0
 	for _, call := range mock.calls {
 		totalAddressesCount += len(call.requests)
+		assertT.Contains([]string{"fee-a", "fee-b"}, call.feeDenom)
+	}
+
+	assertT.EqualValues(requestCount, totalAddressesCount)
+}
+
+func TestBatchSend_MemoLimit(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+	ctx, cancel := context.WithCancel(ctx)
+	t.Cleanup(cancel)
+	amount := sdk.NewCoin("test-denom", sdk.NewInt(13))
+	address, err := sdk.AccAddressFromHex(secp256k1.GenPrivKey().PubKey().Address().String())
+	requireT.NoError(err)
+
+	mock := &mockCoreumClient{}
+	batcher := NewBatcher(mock, []sdk.AccAddress{address}, 10, 0)
+
+	group := parallel.NewGroup(ctx)
+	group.Spawn("batcher", parallel.Fail, batcher.Run)
+	t.Cleanup(func() {
+		group.Exit(nil)
+		_ = group.Wait()
+	})
+
+	wg := sync.WaitGroup{}
+	requestCount := 20
+	wg.Add(requestCount)
+	for i := 0; i < requestCount; i++ {
+		// sessions are unique, so each request has its own entry in the memo
+		attr := attribution.Attribution{Tenant: "public", Session: fmt.Sprintf("%060d", i)}
+		go func() {
+			defer wg.Done()
+			_, _, err := batcher.SendToken(ctx, nil, amount, "fee-a", attr)
+			assertT.NoError(err)
+		}()
 	}
+	wg.Wait()
 
+	totalAddressesCount := 0
+	for _, call := range mock.calls {
+		totalAddressesCount += len(call.requests)
+		var attributions []attribution.Attribution
+		for _, rq := range call.requests {
+			attributions = append(attributions, rq.attribution)
+		}
+		assertT.LessOrEqual(len(attribution.Memo(attributions)), attribution.MaxMemoLength)
+	}
 	assertT.EqualValues(requestCount, totalAddressesCount)
 }
+
+func TestBatchSend_WorkerPool(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+	ctx, cancel := context.WithCancel(ctx)
+	t.Cleanup(cancel)
+	amount := sdk.NewCoin("test-denom", sdk.NewInt(13))
+	fundingAddresses := []sdk.AccAddress{}
+	for i := 0; i < 4; i++ {
+		address, err := sdk.AccAddressFromHex(secp256k1.GenPrivKey().PubKey().Address().String())
+		requireT.NoError(err)
+		fundingAddresses = append(fundingAddresses, address)
+	}
+
+	mock := &mockCoreumClient{delay: 10 * time.Millisecond}
+	batcher := NewBatcher(mock, fundingAddresses, 1, 2)
+	requireT.Len(batcher.Collectors(), 3)
+
+	group := parallel.NewGroup(ctx)
+	group.Spawn("batcher", parallel.Fail, batcher.Run)
+	t.Cleanup(func() {
+		group.Exit(nil)
+		_ = group.Wait()
+	})
+
+	wg := sync.WaitGroup{}
+	requestCount := 20
+	wg.Add(requestCount)
+	for i := 0; i < requestCount; i++ {
+		go func() {
+			defer wg.Done()
+			_, _, err := batcher.SendToken(ctx, nil, amount, "fee-a", attribution.Attribution{})
+			assertT.NoError(err)
+		}()
+	}
+	wg.Wait()
+
+	// the pool is smaller than the number of addresses, but all of them are used in turn
+	assertT.Equal(2, mock.maxActive)
+	used := map[string]bool{}
+	for _, call := range mock.calls {
+		used[call.fromAddress.String()] = true
+	}
+	assertT.Len(used, len(fundingAddresses))
+}
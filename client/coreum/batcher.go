@@ -2,30 +2,54 @@ package coreum
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
 	"github.com/CoreumFoundation/coreum-tools/pkg/parallel"
+	"github.com/CoreumFoundation/faucet/pkg/attribution"
 )
 
-// NewBatcher returns new instance of Batcher type.
+// initialBatchDuration is the expected duration of sending a batch before any batch is sent.
+const initialBatchDuration = 5 * time.Second
+
+// NewBatcher returns new instance of Batcher type. Batches are signed and broadcast by the pool of workers,
+// each of them using one of the funding addresses at a time. Zero or negative number of workers means one worker
+// per funding address. The pool never exceeds the number of funding addresses, because transactions of the same
+// address can't be signed concurrently.
 func NewBatcher(
 	client coreumClient,
 	fundingAddresses []sdk.AccAddress,
 	batchSize int,
+	workers int,
 ) *Batcher {
+	if workers <= 0 || workers > len(fundingAddresses) {
+		workers = len(fundingAddresses)
+	}
 	requestBufferSize := batchSize // number of requests that will be buffered to be batched
+	idleAddresses := make(chan sdk.AccAddress, len(fundingAddresses))
+	for _, address := range fundingAddresses {
+		idleAddresses <- address
+	}
 	b := &Batcher{
 		requestBuffer:    make(chan request, requestBufferSize),
 		client:           client,
 		fundingAddresses: fundingAddresses,
+		idleAddresses:    idleAddresses,
 		batchSize:        batchSize,
-		batchChan:        make(chan batch),
-		mu:               sync.RWMutex{},
+		workers:          workers,
+		// the batch is ready for every worker finishing the current one
+		batchChan:     make(chan batch, workers),
+		mu:            sync.RWMutex{},
+		batchDuration: int64(initialBatchDuration),
 	}
 
 	return b
@@ -36,8 +60,9 @@ type coreumClient interface {
 	TransferToken(
 		ctx context.Context,
 		fromAddress sdk.AccAddress,
+		feeDenom string,
 		requests ...transferRequest,
-	) (string, error)
+	) (string, sdk.Coin, error)
 }
 
 // Batcher exposes functionality to batch many transfer requests.
@@ -45,35 +70,70 @@ type Batcher struct {
 	requestBuffer    chan request
 	client           coreumClient
 	fundingAddresses []sdk.AccAddress
-	batchSize        int
-	batchChan        chan batch
+	// idleAddresses are the funding addresses not used by any worker at the moment
+	idleAddresses chan sdk.AccAddress
+	batchSize     int
+	workers       int
+	batchChan     chan batch
 
 	mu      sync.RWMutex
 	stopped bool
+
+	busyAddresses sync.Map // funding addresses used by the workers at the moment
+
+	pending       int64 // atomic, number of requests waiting for the result
+	busyWorkers   int64 // atomic, number of workers sending the batch
+	batchDuration int64 // atomic, moving average of batch sending duration in nanoseconds
 }
 
 type result struct {
 	txHash string
+	fee    sdk.Coin
 	err    error
 }
 
 type request struct {
 	responseChan chan result
 	req          transferRequest
+	feeDenom     string
 }
 
-// SendToken receives a single transfer token request, batch sends them and returns the result.
-func (b *Batcher) SendToken(ctx context.Context, destAddress sdk.AccAddress, amount sdk.Coin) (string, error) {
-	resChan, err := b.requestFund(destAddress, amount)
+// SendToken receives a single transfer token request, batch sends them and returns the result. Requests are
+// batched only with the ones paying the fee in the same denom, empty denom means the gas price denom of the chain.
+// The returned fee is the share of the request in the fee of the transaction. The attribution is encoded into
+// the memo of the transaction, batches are split if the memo would exceed the limit.
+func (b *Batcher) SendToken(
+	ctx context.Context,
+	destAddress sdk.AccAddress,
+	amount sdk.Coin,
+	feeDenom string,
+	attr attribution.Attribution,
+) (string, sdk.Coin, error) {
+	atomic.AddInt64(&b.pending, 1)
+	defer atomic.AddInt64(&b.pending, -1)
+
+	resChan, err := b.requestFund(destAddress, amount, feeDenom, attr)
 	if err != nil {
-		return "", err
+		return "", sdk.Coin{}, err
 	}
 	select {
 	case res := <-resChan:
-		return res.txHash, res.err
+		return res.txHash, res.fee, res.err
 	case d := <-ctx.Done():
-		return "", errors.Errorf("request aborted, %v", d)
+		return "", sdk.Coin{}, errors.Errorf("request aborted, %v", d)
+	}
+}
+
+// Backlog returns the number of requests waiting for the result and the estimated time needed to process them,
+// based on the recent duration of sending a batch.
+func (b *Batcher) Backlog() (int, time.Duration) {
+	pending := atomic.LoadInt64(&b.pending)
+	workers := int64(b.batchSize * b.workers)
+	if pending == 0 || workers == 0 {
+		return int(pending), 0
 	}
+	rounds := int64(math.Ceil(float64(pending) / float64(workers)))
+	return int(pending), time.Duration(rounds * atomic.LoadInt64(&b.batchDuration))
 }
 
 func (b *Batcher) close() {
@@ -92,7 +152,12 @@ func (b *Batcher) isClosed() bool {
 	return b.stopped
 }
 
-func (b *Batcher) requestFund(address sdk.AccAddress, amount sdk.Coin) (<-chan result, error) {
+func (b *Batcher) requestFund(
+	address sdk.AccAddress,
+	amount sdk.Coin,
+	feeDenom string,
+	attr attribution.Attribution,
+) (<-chan result, error) {
 	if b.isClosed() {
 		return nil, errors.New("request processor is closed")
 	}
@@ -101,7 +166,9 @@ func (b *Batcher) requestFund(address sdk.AccAddress, amount sdk.Coin) (<-chan r
 		req: transferRequest{
 			destAddress: address,
 			amount:      amount,
+			attribution: attr,
 		},
+		feeDenom: feeDenom,
 	}
 	b.requestBuffer <- req
 	return req.responseChan, nil
@@ -121,10 +188,9 @@ func (b *Batcher) Run(ctx context.Context) error {
 		})
 		spawn("processBatches", parallel.Fail, func(ctx context.Context) error {
 			_ = parallel.Run(ctx, func(ctx context.Context, spawn parallel.SpawnFn) error {
-				for _, fundingAddress := range b.fundingAddresses {
-					fundingAddress := fundingAddress
-					spawn(fundingAddress.String(), parallel.Continue, func(ctx context.Context) error {
-						b.processBatches(ctx, fundingAddress)
+				for i := 0; i < b.workers; i++ {
+					spawn(fmt.Sprintf("worker-%d", i), parallel.Continue, func(ctx context.Context) error {
+						b.processBatches(ctx)
 						return nil
 					})
 				}
@@ -138,14 +204,83 @@ func (b *Batcher) Run(ctx context.Context) error {
 
 type batch []request
 
-func (b *Batcher) processBatches(ctx context.Context, fromAddress sdk.AccAddress) {
+// fitsMemo reports whether the attributions of the batch extended by the request fit into the memo.
+func (ba batch) fitsMemo(req request) bool {
+	attributions := make([]attribution.Attribution, 0, len(ba)+1)
+	for _, r := range ba {
+		attributions = append(attributions, r.req.attribution)
+	}
+	attributions = append(attributions, req.req.attribution)
+	return len(attribution.Memo(attributions)) <= attribution.MaxMemoLength
+}
+
+// Collectors returns the prometheus metrics of the broadcast worker pool.
+func (b *Batcher) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "faucet_broadcast_queue_length",
+			Help: "Number of batches waiting for the broadcast worker",
+		}, func() float64 {
+			return float64(len(b.batchChan))
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "faucet_broadcast_workers",
+			Help: "Size of the broadcast worker pool",
+		}, func() float64 {
+			return float64(b.workers)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "faucet_broadcast_workers_busy",
+			Help: "Number of broadcast workers sending the batch",
+		}, func() float64 {
+			return float64(atomic.LoadInt64(&b.busyWorkers))
+		}),
+	}
+}
+
+// BatcherSnapshot is the state of the broadcast worker pool at the moment.
+type BatcherSnapshot struct {
+	Pending       int `json:"pending"`
+	QueuedBatches int `json:"queuedBatches"`
+	Workers       int `json:"workers"`
+	BusyWorkers   int `json:"busyWorkers"`
+	// BusyAddresses are the funding addresses signing and broadcasting the batch.
+	BusyAddresses []string `json:"busyAddresses"`
+}
+
+// Snapshot returns the state of the broadcast worker pool.
+func (b *Batcher) Snapshot() BatcherSnapshot {
+	snapshot := BatcherSnapshot{
+		Pending:       int(atomic.LoadInt64(&b.pending)),
+		QueuedBatches: len(b.batchChan),
+		Workers:       b.workers,
+		BusyWorkers:   int(atomic.LoadInt64(&b.busyWorkers)),
+		BusyAddresses: []string{},
+	}
+	b.busyAddresses.Range(func(address, _ interface{}) bool {
+		snapshot.BusyAddresses = append(snapshot.BusyAddresses, address.(string))
+		return true
+	})
+	sort.Strings(snapshot.BusyAddresses)
+	return snapshot
+}
+
+// processBatches sends the batches using the funding address which is idle at the moment, so addresses are
+// rotated if there are fewer workers than addresses.
+func (b *Batcher) processBatches(ctx context.Context) {
 	for {
 		ba, ok := <-b.batchChan
 		if !ok {
 			break
 		}
 
+		fromAddress := <-b.idleAddresses
+		atomic.AddInt64(&b.busyWorkers, 1)
+		b.busyAddresses.Store(fromAddress.String(), struct{}{})
 		b.sendBatch(ctx, fromAddress, ba)
+		b.busyAddresses.Delete(fromAddress.String())
+		atomic.AddInt64(&b.busyWorkers, -1)
+		b.idleAddresses <- fromAddress
 	}
 }
 
@@ -161,12 +296,15 @@ func (b *Batcher) sendBatch(ctx context.Context, fromAddress sdk.AccAddress, ba
 		requests = append(requests, r.req)
 	}
 	// TODO: retry can be implemented to make it more resilient to network errors.
+	start := time.Now()
 	//nolint:contextcheck // We don't want to cancel requests on shutdown sequence
-	txHash, err := b.client.TransferToken(ctx, fromAddress, requests...)
+	txHash, fee, err := b.client.TransferToken(ctx, fromAddress, ba[0].feeDenom, requests...)
+	b.observeBatchDuration(time.Since(start))
 	if err != nil {
 		rsp.err = err
 	} else {
 		rsp.txHash = txHash
+		rsp.fee = sdk.NewCoin(fee.Denom, fee.Amount.QuoRaw(int64(len(ba))))
 	}
 
 	for _, rq := range ba {
@@ -174,17 +312,35 @@ func (b *Batcher) sendBatch(ctx context.Context, fromAddress sdk.AccAddress, ba
 	}
 }
 
+// observeBatchDuration updates exponential moving average of the batch sending duration.
+func (b *Batcher) observeBatchDuration(d time.Duration) {
+	for {
+		old := atomic.LoadInt64(&b.batchDuration)
+		updated := old + (int64(d)-old)/5
+		if atomic.CompareAndSwapInt64(&b.batchDuration, old, updated) {
+			return
+		}
+	}
+}
+
+// createBatches groups the requests by fee denom, because the whole transaction pays the fee in a single denom.
 func (b *Batcher) createBatches() {
-	var ba batch
+	batches := map[string]batch{}
 	for {
 		req, ok := <-b.requestBuffer
 		if ok {
-			ba = append(ba, req)
+			if ba := batches[req.feeDenom]; len(ba) > 0 && !ba.fitsMemo(req) {
+				b.batchChan <- ba
+				delete(batches, req.feeDenom)
+			}
+			batches[req.feeDenom] = append(batches[req.feeDenom], req)
 		}
 
-		if (len(ba) >= b.batchSize || len(b.requestBuffer) == 0 || !ok) && len(ba) > 0 {
-			b.batchChan <- ba
-			ba = batch{}
+		for feeDenom, ba := range batches {
+			if len(ba) >= b.batchSize || len(b.requestBuffer) == 0 || !ok {
+				b.batchChan <- ba
+				delete(batches, feeDenom)
+			}
 		}
 
 		if !ok {
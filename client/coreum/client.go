@@ -2,48 +2,130 @@ package coreum
 
 import (
 	"context"
+	"sync"
 
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/client/grpc/tmservice"
 	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/CoreumFoundation/coreum/pkg/client"
 	"github.com/CoreumFoundation/coreum/pkg/config"
+	feemodeltypes "github.com/CoreumFoundation/coreum/x/feemodel/types"
+	"github.com/CoreumFoundation/faucet/pkg/attribution"
+	faucetconfig "github.com/CoreumFoundation/faucet/pkg/config"
 	"github.com/CoreumFoundation/faucet/pkg/logger"
 )
 
+// TxObserver is notified about every transaction broadcast by the client.
+type TxObserver interface {
+	TxBroadcast(txHash string, height int64, txBytes []byte)
+}
+
 // New returns an instance of the Client interface.
-func New(network config.Network, clientCtx client.Context, txf client.Factory) Client {
+func New(network config.Network, grpcClient *grpc.ClientConn, kr keyring.Keyring) Client {
+	clientCtx := client.NewContext(client.DefaultContextConfig(), faucetconfig.NewModuleManager()).
+		WithChainID(string(network.ChainID())).
+		WithBroadcastMode(flags.BroadcastBlock).
+		WithGRPCClient(grpcClient)
+
+	txf := client.Factory{}.
+		WithTxConfig(clientCtx.TxConfig()).
+		WithKeybase(kr).
+		WithChainID(string(network.ChainID())).
+		WithSignMode(signing.SignMode_SIGN_MODE_DIRECT)
+
 	return Client{
-		network:   network,
-		clientCtx: clientCtx,
-		txf:       txf,
+		network:     network,
+		clientCtx:   clientCtx,
+		txf:         txf,
+		awaitConfig: DefaultAwaitConfig(),
+		sequences:   &sequences{bySigner: map[string]uint64{}},
 	}
 }
 
 // Client is used to communicate with coreum blockchain.
 type Client struct {
-	clientCtx client.Context
-	network   config.Network
-	txf       tx.Factory
+	clientCtx    client.Context
+	network      config.Network
+	txf          tx.Factory
+	txObserver   TxObserver
+	feeGasPrices sdk.DecCoins
+	awaitConfig  AwaitConfig
+	sequences    *sequences
+	hooks        FundingHooks
+}
+
+// sequences are the account sequences of the last transactions signed by each address, shared by the copies
+// of the client.
+type sequences struct {
+	mu       sync.Mutex
+	bySigner map[string]uint64
+}
+
+// Sequences returns the account sequence of the last transaction signed by each address.
+func (c Client) Sequences() map[string]uint64 {
+	c.sequences.mu.Lock()
+	defer c.sequences.mu.Unlock()
+
+	result := make(map[string]uint64, len(c.sequences.bySigner))
+	for address, sequence := range c.sequences.bySigner {
+		result[address] = sequence
+	}
+	return result
+}
+
+// WithTxObserver returns a copy of the client notifying the observer about broadcast transactions.
+func (c Client) WithTxObserver(observer TxObserver) Client {
+	c.txObserver = observer
+	return c
+}
+
+// WithFeeGasPrices returns a copy of the client able to pay fees in additional denoms, using the gas prices.
+// Gas price of the chain's fee denom is always queried from the chain.
+func (c Client) WithFeeGasPrices(prices sdk.DecCoins) Client {
+	c.feeGasPrices = prices
+	return c
+}
+
+// WithFundingHooks returns a copy of the client appending the messages of the hooks to the funding transaction
+// of every address. The messages are executed atomically with the transfer, so the transfer fails if any of them
+// fails, together with the other transfers of the batch.
+func (c Client) WithFundingHooks(hooks FundingHooks) Client {
+	c.hooks = hooks
+	return c
 }
 
 type transferRequest struct {
 	amount      sdk.Coin
 	destAddress sdk.AccAddress
+	attribution attribution.Attribution
 }
 
-// TransferToken transfers amount to a list of destination addresses in single tx.
+// TransferToken transfers amount to a list of destination addresses in single tx paying the fee in the fee denom,
+// empty denom means the gas price denom of the chain. The fee paid is returned together with the tx hash.
+// Attributions of the requests are encoded into the memo of the transaction.
 func (c Client) TransferToken(
 	ctx context.Context,
 	fromAddress sdk.AccAddress,
+	feeDenom string,
 	requests ...transferRequest,
-) (string, error) {
+) (string, sdk.Coin, error) {
 	var msgs []sdk.Msg
 	toAddressList := []string{}
+	attributions := make([]attribution.Attribution, 0, len(requests))
 	for _, rq := range requests {
 		toAddressList = append(toAddressList, rq.destAddress.String())
+		attributions = append(attributions, rq.attribution)
 	}
 	log := logger.Get(ctx).With(zap.Stringer("fromAddress", fromAddress), zap.Strings("toAddresses", toAddressList))
 	log.Info("Sending tokens")
@@ -54,18 +136,218 @@ func (c Client) TransferToken(
 			Amount:      []sdk.Coin{rq.amount},
 		}
 		msgs = append(msgs, msg)
+		msgs = append(msgs, c.hooks.messages(fromAddress, rq.destAddress)...)
+	}
+	clientCtx := c.clientCtx.
+		WithFromName(fromAddress.String()).
+		WithFromAddress(fromAddress)
+
+	txf := c.txf.WithMemo(attribution.Memo(attributions))
+	txBytes, fee, err := c.signTx(ctx, txf, clientCtx, feeDenom, msgs...)
+	if err != nil {
+		return "", sdk.Coin{}, err
+	}
+
+	// inclusion is awaited by the client itself, so polling backs off while the block is produced
+	result, err := client.BroadcastRawTx(ctx, clientCtx.WithBroadcastMode(flags.BroadcastSync), txBytes)
+	if err != nil {
+		return "", sdk.Coin{}, err
+	}
+	result, err = c.awaitTx(ctx, result.TxHash)
+	if err != nil {
+		return "", sdk.Coin{}, err
+	}
+
+	if c.txObserver != nil {
+		c.txObserver.TxBroadcast(result.TxHash, result.Height, txBytes)
+	}
+
+	log.Info("Tokens sent", zap.Stringer("fee", fee))
+	return result.TxHash, fee, nil
+}
+
+// SigningError is returned if the transaction couldn't be signed, e.g. because the key backend is unavailable.
+type SigningError struct {
+	Err error
+}
+
+func (e SigningError) Error() string {
+	return "signing transaction failed: " + e.Err.Error()
+}
+
+// Unwrap returns the error of the signer.
+func (e SigningError) Unwrap() error {
+	return e.Err
+}
+
+// SigningFailure tells the app that the failure is caused by the signer, not by the chain.
+func (e SigningError) SigningFailure() bool {
+	return true
+}
+
+// signTx builds and signs the transaction the same way client.BroadcastTx does, but returns the encoded tx
+// so it might be kept for rebroadcasting. The fee is paid in the fee denom, empty denom means the gas price denom
+// of the chain.
+func (c Client) signTx(
+	ctx context.Context,
+	txf tx.Factory,
+	clientCtx client.Context,
+	feeDenom string,
+	msgs ...sdk.Msg,
+) ([]byte, sdk.Coin, error) {
+	acc, err := client.GetAccountInfo(ctx, clientCtx, clientCtx.FromAddress())
+	if err != nil {
+		return nil, sdk.Coin{}, err
+	}
+	txf = txf.
+		WithAccountNumber(acc.GetAccountNumber()).
+		WithSequence(acc.GetSequence())
+
+	gasPrice, err := client.GetGasPrice(ctx, clientCtx)
+	if err != nil {
+		return nil, sdk.Coin{}, err
+	}
+	gasPrice.Amount = gasPrice.Amount.Mul(clientCtx.GasPriceAdjustment())
+	if feeDenom != "" && feeDenom != gasPrice.Denom {
+		price := c.feeGasPrices.AmountOf(feeDenom)
+		if !price.IsPositive() {
+			return nil, sdk.Coin{}, errors.Errorf("gas price of fee denom %q is not configured", feeDenom)
+		}
+		gasPrice = sdk.NewDecCoinFromDec(feeDenom, price)
+	}
+	txf = txf.WithGasPrices(gasPrice.String())
+
+	_, adjusted, err := client.CalculateGas(ctx, clientCtx, txf, msgs...)
+	if err != nil {
+		return nil, sdk.Coin{}, err
+	}
+	txf = txf.WithGas(adjusted)
+	// computed the same way the factory computes the fee from gas prices
+	fee := sdk.NewCoin(gasPrice.Denom, gasPrice.Amount.MulInt64(int64(adjusted)).Ceil().RoundInt())
+
+	unsignedTx, err := txf.BuildUnsignedTx(msgs...)
+	if err != nil {
+		return nil, sdk.Coin{}, errors.WithStack(err)
+	}
+	if err := client.Sign(txf, clientCtx.FromName(), unsignedTx, true); err != nil {
+		return nil, sdk.Coin{}, errors.WithStack(SigningError{Err: err})
+	}
+	c.sequences.mu.Lock()
+	c.sequences.bySigner[clientCtx.FromAddress().String()] = acc.GetSequence()
+	c.sequences.mu.Unlock()
+
+	txBytes, err := clientCtx.TxConfig().TxEncoder()(unsignedTx.GetTx())
+	return txBytes, fee, errors.WithStack(err)
+}
+
+// VerifyKey verifies that the key of the address is available for signing.
+func (c Client) VerifyKey(address sdk.AccAddress) error {
+	_, err := c.txf.Keybase().Key(address.String())
+	return errors.Wrapf(err, "key of %s is missing", address)
+}
+
+// TxHeight returns the height of the block including the transaction, or 0 if transaction is not found.
+func (c Client) TxHeight(ctx context.Context, txHash string) (int64, error) {
+	res, err := sdktx.NewServiceClient(c.clientCtx).GetTx(ctx, &sdktx.GetTxRequest{Hash: txHash})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return 0, nil
+		}
+		return 0, errors.WithStack(err)
+	}
+	return res.TxResponse.Height, nil
+}
+
+// TxMemo returns the memo and the signers of the transaction included in a block, signers are empty
+// if the transaction is not found.
+func (c Client) TxMemo(ctx context.Context, txHash string) (string, []sdk.AccAddress, error) {
+	res, err := sdktx.NewServiceClient(c.clientCtx).GetTx(ctx, &sdktx.GetTxRequest{Hash: txHash})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "", nil, nil
+		}
+		return "", nil, errors.WithStack(err)
+	}
+	// messages are decoded as Any, they must be unpacked before their signers are known
+	if err := res.Tx.UnpackInterfaces(c.clientCtx.InterfaceRegistry()); err != nil {
+		return "", nil, errors.WithStack(err)
+	}
+	return res.Tx.Body.Memo, res.Tx.GetSigners(), nil
+}
+
+// LatestHeight returns the height of the latest block.
+func (c Client) LatestHeight(ctx context.Context) (int64, error) {
+	res, err := tmservice.NewServiceClient(c.clientCtx).GetLatestBlock(ctx, &tmservice.GetLatestBlockRequest{})
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return res.Block.Header.Height, nil
+}
+
+// BroadcastRawTx broadcasts already signed transaction without waiting for its inclusion.
+func (c Client) BroadcastRawTx(ctx context.Context, txBytes []byte) error {
+	_, err := client.BroadcastRawTx(ctx, c.clientCtx.WithBroadcastMode(flags.BroadcastSync), txBytes)
+	return err
+}
+
+// SimulateTransfer builds and signs the transfer the same way TransferToken does, then simulates it instead
+// of broadcasting, so fees, denoms, funding hooks and the state of the account are verified without spending
+// anything.
+func (c Client) SimulateTransfer(
+	ctx context.Context,
+	fromAddress sdk.AccAddress,
+	feeDenom string,
+	destAddress sdk.AccAddress,
+	amount sdk.Coin,
+) error {
+	msg := &banktypes.MsgSend{
+		FromAddress: fromAddress.String(),
+		ToAddress:   destAddress.String(),
+		Amount:      sdk.NewCoins(amount),
 	}
 	clientCtx := c.clientCtx.
 		WithFromName(fromAddress.String()).
 		WithFromAddress(fromAddress)
 
-	txf := c.txf.
-		WithSimulateAndExecute(true)
-	result, err := client.BroadcastTx(ctx, clientCtx, txf, msgs...)
+	msgs := append([]sdk.Msg{msg}, c.hooks.messages(fromAddress, destAddress)...)
+	txBytes, _, err := c.signTx(ctx, c.txf, clientCtx, feeDenom, msgs...)
 	if err != nil {
-		return "", err
+		return err
 	}
+	_, err = sdktx.NewServiceClient(c.clientCtx).Simulate(ctx, &sdktx.SimulateRequest{TxBytes: txBytes})
+	return errors.Wrap(err, "transaction simulation failed")
+}
 
-	log.Info("Tokens sent")
-	return result.TxHash, nil
+// DenomMetadata returns the bank metadata of the denom, its base is empty if the denom has no metadata.
+func (c Client) DenomMetadata(ctx context.Context, denom string) (banktypes.Metadata, error) {
+	res, err := banktypes.NewQueryClient(c.clientCtx).DenomMetadata(ctx, &banktypes.QueryDenomMetadataRequest{Denom: denom})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return banktypes.Metadata{}, nil
+		}
+		return banktypes.Metadata{}, errors.WithStack(err)
+	}
+	return res.Metadata, nil
+}
+
+// GasPriceMultiplier returns the ratio of the current minimum gas price to the initial gas price of the fee model.
+// The fee model escalates the gas price once the average block gas exceeds the escalation threshold, so the ratio
+// reflects the congestion of the chain.
+func (c Client) GasPriceMultiplier(ctx context.Context) (float64, error) {
+	queryClient := feemodeltypes.NewQueryClient(c.clientCtx)
+	params, err := queryClient.Params(ctx, &feemodeltypes.QueryParamsRequest{})
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	minGasPrice, err := queryClient.MinGasPrice(ctx, &feemodeltypes.QueryMinGasPriceRequest{})
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	initial := params.Params.Model.InitialGasPrice
+	if !initial.IsPositive() {
+		return 0, errors.New("initial gas price of the fee model is not positive")
+	}
+	multiplier, err := minGasPrice.MinGasPrice.Amount.Quo(initial).Float64()
+	return multiplier, errors.WithStack(err)
 }
@@ -0,0 +1,35 @@
+package coreum
+
+import (
+	"context"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/pkg/secret"
+)
+
+// SignSelfSend returns ready-to-broadcast transaction sending the amount from the account derived from
+// the mnemonic to itself. The account must already exist on chain and hold enough funds to pay the fee.
+func (c Client) SignSelfSend(ctx context.Context, mnemonic secret.String, amount sdk.Coin) ([]byte, error) {
+	kr := keyring.NewInMemory()
+	address, err := addKey(kr, mnemonic.Reveal(), sdk.GetConfig().GetFullBIP44Path())
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &banktypes.MsgSend{
+		FromAddress: address.String(),
+		ToAddress:   address.String(),
+		Amount:      sdk.NewCoins(amount),
+	}
+	clientCtx := c.clientCtx.
+		WithKeyring(kr).
+		WithFromName(address.String()).
+		WithFromAddress(address)
+
+	txBytes, _, err := c.signTx(ctx, c.txf.WithKeybase(kr), clientCtx, "", msg)
+	return txBytes, errors.Wrap(err, "unable to sign example transaction")
+}
@@ -0,0 +1,234 @@
+package coreum
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	assetfttypes "github.com/CoreumFoundation/coreum/x/asset/ft/types"
+)
+
+// HookAddressPlaceholder is replaced by the funded address in the message of the wasm execute hook.
+const HookAddressPlaceholder = "{address}"
+
+// HookConfig configures the single hook appending the messages to the funding transaction of every address,
+// exactly one of its actions must be set.
+type HookConfig struct {
+	Name string `json:"name"`
+	// WasmExecute executes the contract, e.g. registering the address in the test registry.
+	WasmExecute *WasmExecuteHook `json:"wasmExecute"`
+	// AssetFTMint mints the fungible token to the funding address and sends it to the funded address.
+	AssetFTMint *AssetFTMintHook `json:"assetftMint"`
+}
+
+// WasmExecuteHook executes the contract by the funding address.
+type WasmExecuteHook struct {
+	Contract string `json:"contract"`
+	// Msg is the JSON message of the contract, HookAddressPlaceholder in it is replaced by the funded address.
+	Msg json.RawMessage `json:"msg"`
+	// Funds are the coins sent to the contract together with the message, e.g. "10udevcore".
+	Funds string `json:"funds"`
+}
+
+// AssetFTMintHook mints the amount of the token issued by the funding address, e.g. "1000utest-devcore1...".
+type AssetFTMintHook struct {
+	Amount string `json:"amount"`
+}
+
+// LoadHookConfig reads the hooks from the JSON file.
+func LoadHookConfig(path string) ([]HookConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var configs []HookConfig
+	if err := json.Unmarshal(content, &configs); err != nil {
+		return nil, errors.Wrapf(err, "invalid hook config %s", path)
+	}
+	return configs, nil
+}
+
+type hook struct {
+	name     string
+	contract sdk.AccAddress
+	msg      []byte
+	funds    sdk.Coins
+	mint     sdk.Coin
+}
+
+// FundingHooks build the extra messages of the funding transactions, so the deployments customize onboarding
+// of the addresses without forking the sender.
+type FundingHooks struct {
+	hooks []hook
+}
+
+// NewFundingHooks validates the configs. The messages are signed by the funding address sending the batch, so
+// tokens may be minted only if their issuer is the only funding address.
+func NewFundingHooks(configs []HookConfig, fundingAddresses []sdk.AccAddress) (FundingHooks, error) {
+	var hooks FundingHooks
+	names := map[string]bool{}
+	for i, c := range configs {
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("#%d", i)
+		}
+		if names[c.Name] {
+			return FundingHooks{}, errors.Errorf("hook %s is configured twice", c.Name)
+		}
+		names[c.Name] = true
+
+		var h hook
+		var err error
+		switch {
+		case c.WasmExecute != nil && c.AssetFTMint == nil:
+			h, err = newWasmExecuteHook(*c.WasmExecute)
+		case c.AssetFTMint != nil && c.WasmExecute == nil:
+			h, err = newAssetFTMintHook(*c.AssetFTMint, fundingAddresses)
+		default:
+			err = errors.New("exactly one of wasmExecute and assetftMint must be set")
+		}
+		if err != nil {
+			return FundingHooks{}, errors.Wrapf(err, "invalid hook %s", c.Name)
+		}
+		h.name = c.Name
+		hooks.hooks = append(hooks.hooks, h)
+	}
+	return hooks, nil
+}
+
+func newWasmExecuteHook(c WasmExecuteHook) (hook, error) {
+	contract, err := sdk.AccAddressFromBech32(c.Contract)
+	if err != nil {
+		return hook{}, errors.Wrapf(err, "invalid contract address %q", c.Contract)
+	}
+	msg := &bytes.Buffer{}
+	if err := json.Compact(msg, c.Msg); err != nil || len(c.Msg) == 0 || c.Msg[0] != '{' {
+		return hook{}, errors.New("message of the contract must be JSON object")
+	}
+	funds, err := sdk.ParseCoinsNormalized(c.Funds)
+	if err != nil {
+		return hook{}, errors.Wrapf(err, "invalid funds %q", c.Funds)
+	}
+	return hook{contract: contract, msg: msg.Bytes(), funds: funds}, nil
+}
+
+func newAssetFTMintHook(c AssetFTMintHook, fundingAddresses []sdk.AccAddress) (hook, error) {
+	amount, err := sdk.ParseCoinNormalized(c.Amount)
+	if err != nil || !amount.IsPositive() {
+		return hook{}, errors.Errorf("invalid amount %q", c.Amount)
+	}
+	_, issuer, err := assetfttypes.DeconstructDenom(amount.Denom)
+	if err != nil {
+		return hook{}, errors.Wrapf(err, "denom %s is not issued by assetft module", amount.Denom)
+	}
+	if len(fundingAddresses) != 1 || !fundingAddresses[0].Equals(issuer) {
+		return hook{}, errors.Errorf("issuer %s of %s must be the only funding address", issuer, amount.Denom)
+	}
+	return hook{mint: amount}, nil
+}
+
+// Names returns the names of the hooks.
+func (h FundingHooks) Names() []string {
+	names := make([]string, 0, len(h.hooks))
+	for _, hook := range h.hooks {
+		names = append(names, hook.name)
+	}
+	return names
+}
+
+// messages returns the messages the hooks append to the transfer from the funding address to the funded one.
+func (h FundingHooks) messages(fromAddress, destAddress sdk.AccAddress) []sdk.Msg {
+	var msgs []sdk.Msg
+	for _, hook := range h.hooks {
+		if hook.contract != nil {
+			// bech32 addresses are safe to be embedded into JSON strings
+			msg := bytes.ReplaceAll(hook.msg, []byte(HookAddressPlaceholder), []byte(destAddress.String()))
+			msgs = append(msgs, &wasmExecuteMsg{
+				Sender:   fromAddress.String(),
+				Contract: hook.contract.String(),
+				Msg:      msg,
+				Funds:    hook.funds,
+			})
+			continue
+		}
+		msgs = append(msgs,
+			&assetfttypes.MsgMint{Sender: fromAddress.String(), Coin: hook.mint},
+			&banktypes.MsgSend{
+				FromAddress: fromAddress.String(),
+				ToAddress:   destAddress.String(),
+				Amount:      sdk.NewCoins(hook.mint),
+			},
+		)
+	}
+	return msgs
+}
+
+// wasmExecuteMsg is cosmwasm.wasm.v1.MsgExecuteContract encoded by hand, because the types of wasmd require cgo
+// and the faucet is built without it.
+type wasmExecuteMsg struct {
+	Sender   string
+	Contract string
+	Msg      []byte
+	Funds    sdk.Coins
+}
+
+func (m *wasmExecuteMsg) Reset() { *m = wasmExecuteMsg{} }
+
+func (m *wasmExecuteMsg) String() string {
+	return fmt.Sprintf("execute %s by %s: %s %s", m.Contract, m.Sender, m.Msg, m.Funds)
+}
+
+// ProtoMessage marks the type as protobuf message.
+func (*wasmExecuteMsg) ProtoMessage() {}
+
+// XXX_MessageName returns the name of the message the type URL of the message is built from.
+func (*wasmExecuteMsg) XXX_MessageName() string { //nolint:revive,stylecheck // name is required by gogoproto
+	return "cosmwasm.wasm.v1.MsgExecuteContract"
+}
+
+// Marshal encodes the message the same way the generated code of wasmd does.
+func (m *wasmExecuteMsg) Marshal() ([]byte, error) {
+	var b []byte
+	b = appendString(b, 1, m.Sender)
+	b = appendString(b, 2, m.Contract)
+	b = protowire.AppendTag(b, 3, protowire.BytesType)
+	b = protowire.AppendBytes(b, m.Msg)
+	for _, coin := range m.Funds {
+		var c []byte
+		c = appendString(c, 1, coin.Denom)
+		c = appendString(c, 2, coin.Amount.String())
+		b = protowire.AppendTag(b, 5, protowire.BytesType)
+		b = protowire.AppendBytes(b, c)
+	}
+	return b, nil
+}
+
+// ValidateBasic validates the message.
+func (m *wasmExecuteMsg) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(m.Contract); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(m.Funds.Validate())
+}
+
+// GetSigners returns the sender.
+func (m *wasmExecuteMsg) GetSigners() []sdk.AccAddress {
+	sender, err := sdk.AccAddressFromBech32(m.Sender)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{sender}
+}
+
+func appendString(b []byte, field protowire.Number, value string) []byte {
+	if value == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, field, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
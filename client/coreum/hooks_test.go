@@ -0,0 +1,113 @@
+package coreum
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/CoreumFoundation/coreum/pkg/config"
+	"github.com/CoreumFoundation/coreum/pkg/config/constant"
+	assetfttypes "github.com/CoreumFoundation/coreum/x/asset/ft/types"
+)
+
+func TestFundingHooks(t *testing.T) {
+	requireT := require.New(t)
+
+	network, err := config.NetworkByChainID(constant.ChainIDDev)
+	requireT.NoError(err)
+	c := New(network, nil, keyring.NewInMemory())
+
+	faucet := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	dest := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	contract := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	token := assetfttypes.BuildDenom("utest", faucet)
+
+	for name, configs := range map[string][]HookConfig{
+		"no action":      {{Name: "empty"}},
+		"two actions":    {{WasmExecute: &WasmExecuteHook{}, AssetFTMint: &AssetFTMintHook{}}},
+		"contract":       {{WasmExecute: &WasmExecuteHook{Contract: "invalid", Msg: json.RawMessage(`{}`)}}},
+		"message":        {{WasmExecute: &WasmExecuteHook{Contract: contract.String(), Msg: json.RawMessage(`[]`)}}},
+		"funds":          {{WasmExecute: &WasmExecuteHook{Contract: contract.String(), Msg: json.RawMessage(`{}`), Funds: "x"}}},
+		"native denom":   {{AssetFTMint: &AssetFTMintHook{Amount: "10" + network.Denom()}}},
+		"other issuer":   {{AssetFTMint: &AssetFTMintHook{Amount: "10" + assetfttypes.BuildDenom("utest", dest)}}},
+		"duplicate name": {{Name: "a", AssetFTMint: &AssetFTMintHook{Amount: "10" + token}}, {Name: "a", AssetFTMint: &AssetFTMintHook{Amount: "1" + token}}},
+	} {
+		_, err := NewFundingHooks(configs, []sdk.AccAddress{faucet})
+		requireT.Error(err, name)
+	}
+	// tokens are minted only if the issuer signs every batch
+	_, err = NewFundingHooks([]HookConfig{{AssetFTMint: &AssetFTMintHook{Amount: "10" + token}}},
+		[]sdk.AccAddress{faucet, dest})
+	requireT.Error(err)
+
+	hooks, err := NewFundingHooks([]HookConfig{
+		{
+			Name: "registry",
+			WasmExecute: &WasmExecuteHook{
+				Contract: contract.String(),
+				Msg:      json.RawMessage(`{"register": {"address": "{address}"}}`),
+				Funds:    "5" + network.Denom(),
+			},
+		},
+		{AssetFTMint: &AssetFTMintHook{Amount: "100" + token}},
+	}, []sdk.AccAddress{faucet})
+	requireT.NoError(err)
+	requireT.Equal([]string{"registry", "#1"}, hooks.Names())
+
+	msgs := hooks.messages(faucet, dest)
+	requireT.Len(msgs, 3)
+	requireT.Equal(&wasmExecuteMsg{
+		Sender:   faucet.String(),
+		Contract: contract.String(),
+		Msg:      []byte(`{"register":{"address":"` + dest.String() + `"}}`),
+		Funds:    sdk.NewCoins(sdk.NewInt64Coin(network.Denom(), 5)),
+	}, msgs[0])
+	requireT.Equal(&assetfttypes.MsgMint{Sender: faucet.String(), Coin: sdk.NewInt64Coin(token, 100)}, msgs[1])
+	requireT.Equal(&banktypes.MsgSend{
+		FromAddress: faucet.String(),
+		ToAddress:   dest.String(),
+		Amount:      sdk.NewCoins(sdk.NewInt64Coin(token, 100)),
+	}, msgs[2])
+	for _, msg := range msgs {
+		requireT.NoError(msg.ValidateBasic())
+		requireT.Equal([]sdk.AccAddress{faucet}, msg.GetSigners())
+	}
+
+	// wasm message is encoded as MsgExecuteContract of wasmd
+	b, err := msgs[0].(*wasmExecuteMsg).Marshal()
+	requireT.NoError(err)
+	fields := map[protowire.Number][][]byte{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		requireT.GreaterOrEqual(n, 0)
+		requireT.Equal(protowire.BytesType, typ)
+		value, m := protowire.ConsumeBytes(b[n:])
+		requireT.GreaterOrEqual(m, 0)
+		fields[num] = append(fields[num], value)
+		b = b[n+m:]
+	}
+	requireT.Equal([][]byte{[]byte(faucet.String())}, fields[1])
+	requireT.Equal([][]byte{[]byte(contract.String())}, fields[2])
+	requireT.Equal([][]byte{[]byte(`{"register":{"address":"` + dest.String() + `"}}`)}, fields[3])
+	requireT.Len(fields[5], 1)
+
+	txBuilder := c.clientCtx.TxConfig().NewTxBuilder()
+	requireT.NoError(txBuilder.SetMsgs(msgs...))
+	txBytes, err := c.clientCtx.TxConfig().TxEncoder()(txBuilder.GetTx())
+	requireT.NoError(err)
+	var raw sdktx.TxRaw
+	requireT.NoError(raw.Unmarshal(txBytes))
+	var body sdktx.TxBody
+	requireT.NoError(body.Unmarshal(raw.BodyBytes))
+	requireT.Len(body.Messages, 3)
+	requireT.Equal("/cosmwasm.wasm.v1.MsgExecuteContract", body.Messages[0].TypeUrl)
+	requireT.Equal("/coreum.asset.ft.v1.MsgMint", body.Messages[1].TypeUrl)
+	requireT.Equal("/cosmos.bank.v1beta1.MsgSend", body.Messages[2].TypeUrl)
+}
@@ -0,0 +1,69 @@
+package coreum
+
+import (
+	"bufio"
+	"os"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+)
+
+// FundingAccount is the account derived from the mnemonic together with the sub-accounts derived from the same
+// mnemonic at next HD address indices.
+type FundingAccount struct {
+	Address     sdk.AccAddress
+	SubAccounts []sdk.AccAddress
+}
+
+// NewKeyringFromFile returns keyring containing the keys derived from mnemonics stored in the file,
+// each line containing one mnemonic. For each mnemonic subAccounts additional keys are derived.
+func NewKeyringFromFile(path string, subAccounts uint32) (keyring.Keyring, []FundingAccount, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to open file at %s", path)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	kr := keyring.NewInMemory()
+	var accounts []FundingAccount
+	for scanner.Scan() {
+		mnemonic := scanner.Text()
+		address, err := addKey(kr, mnemonic, sdk.GetConfig().GetFullBIP44Path())
+		if err != nil {
+			return nil, nil, err
+		}
+		account := FundingAccount{Address: address}
+		for i := uint32(1); i <= subAccounts; i++ {
+			hdPath := hd.CreateHDPath(sdk.GetConfig().GetCoinType(), 0, i).String()
+			subAddress, err := addKey(kr, mnemonic, hdPath)
+			if err != nil {
+				return nil, nil, err
+			}
+			account.SubAccounts = append(account.SubAccounts, subAddress)
+		}
+		accounts = append(accounts, account)
+	}
+
+	if len(accounts) == 0 {
+		return nil, nil, errors.New("could not parse any mnemonic")
+	}
+
+	return kr, accounts, nil
+}
+
+// addKey adds the key derived from the mnemonic to the keyring, using its address as the name of the key.
+func addKey(kr keyring.Keyring, mnemonic, hdPath string) (sdk.AccAddress, error) {
+	tempKr := keyring.NewInMemory()
+	info, err := tempKr.NewAccount("temp", mnemonic, "", hdPath, hd.Secp256k1)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse mnemonic key")
+	}
+	address := info.GetAddress()
+	_, err = kr.NewAccount(address.String(), mnemonic, "", hdPath, hd.Secp256k1)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse mnemonic key")
+	}
+	return address, nil
+}
@@ -0,0 +1,39 @@
+package coreum
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeyringFromFileSubAccounts(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	mnemonic := "notice oak worry limit wrap speak medal online prefer cluster roof addict wrist behave treat actual wasp year salad speed social layer crew genius"
+	path := filepath.Join(t.TempDir(), "mnemonic.txt")
+	requireT.NoError(os.WriteFile(path, []byte(mnemonic+"\n"), 0o600))
+
+	_, plain, err := NewKeyringFromFile(path, 0)
+	requireT.NoError(err)
+	requireT.Len(plain, 1)
+	assertT.Empty(plain[0].SubAccounts)
+
+	kr, accounts, err := NewKeyringFromFile(path, 3)
+	requireT.NoError(err)
+	requireT.Len(accounts, 1)
+	assertT.Equal(plain[0].Address, accounts[0].Address)
+	requireT.Len(accounts[0].SubAccounts, 3)
+
+	seen := map[string]bool{accounts[0].Address.String(): true}
+	for _, subAccount := range accounts[0].SubAccounts {
+		assertT.False(seen[subAccount.String()])
+		seen[subAccount.String()] = true
+
+		_, err := kr.Key(subAccount.String())
+		assertT.NoError(err)
+	}
+}
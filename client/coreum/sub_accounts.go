@@ -0,0 +1,99 @@
+package coreum
+
+import (
+	"context"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/faucet/pkg/logger"
+)
+
+// Balance returns the balance of the address in the denom.
+func (c Client) Balance(ctx context.Context, address sdk.AccAddress, denom string) (sdk.Int, error) {
+	res, err := banktypes.NewQueryClient(c.clientCtx).Balance(ctx, &banktypes.QueryBalanceRequest{
+		Address: address.String(),
+		Denom:   denom,
+	})
+	if err != nil {
+		return sdk.Int{}, errors.Wrapf(err, "unable to query balance of %s", address)
+	}
+	return res.Balance.Amount, nil
+}
+
+// DistributeBalance tops up the sub-accounts from the funding account, so the balance of all of them is equal.
+// Only the funds of the funding account are distributed, sub-accounts holding more than the funding account
+// are left untouched and the funding account keeps the same balance as the sub-accounts it tops up.
+func (c Client) DistributeBalance(ctx context.Context, account FundingAccount, denom string) error {
+	if len(account.SubAccounts) == 0 {
+		return nil
+	}
+
+	balance, err := c.Balance(ctx, account.Address, denom)
+	if err != nil {
+		return err
+	}
+	subBalances := make([]sdk.Int, 0, len(account.SubAccounts))
+	for _, subAccount := range account.SubAccounts {
+		subBalance, err := c.Balance(ctx, subAccount, denom)
+		if err != nil {
+			return err
+		}
+		subBalances = append(subBalances, subBalance)
+	}
+
+	level, topUps := topUpAmounts(balance, subBalances)
+	var requests []transferRequest
+	for i, subAccount := range account.SubAccounts {
+		if !topUps[i].IsPositive() {
+			continue
+		}
+		requests = append(requests, transferRequest{
+			destAddress: subAccount,
+			amount:      sdk.NewCoin(denom, topUps[i]),
+		})
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	logger.Get(ctx).Info("Distributing balance to sub-accounts",
+		zap.Stringer("address", account.Address),
+		zap.Stringer("share", sdk.NewCoin(denom, level)))
+	_, _, err = c.TransferToken(ctx, account.Address, "", requests...)
+	return err
+}
+
+// topUpAmounts returns the level the sub-accounts are topped up to and the amount sent to each of them. The poorest
+// sub-accounts are lifted first, until their balance equals the balance left to the funding account, so the sum
+// of the top-ups never exceeds the balance of the funding account.
+func topUpAmounts(balance sdk.Int, subBalances []sdk.Int) (sdk.Int, []sdk.Int) {
+	sorted := make([]sdk.Int, len(subBalances))
+	copy(sorted, subBalances)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LT(sorted[j])
+	})
+
+	sum := balance
+	level := balance
+	for i, subBalance := range sorted {
+		if subBalance.GTE(level) {
+			break
+		}
+		sum = sum.Add(subBalance)
+		level = sum.QuoRaw(int64(i + 2))
+	}
+
+	topUps := make([]sdk.Int, 0, len(subBalances))
+	for _, subBalance := range subBalances {
+		topUp := sdk.ZeroInt()
+		if subBalance.LT(level) {
+			topUp = level.Sub(subBalance)
+		}
+		topUps = append(topUps, topUp)
+	}
+	return level, topUps
+}
@@ -0,0 +1,49 @@
+package coreum
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopUpAmounts(t *testing.T) {
+	ints := func(values ...int64) []sdk.Int {
+		result := make([]sdk.Int, 0, len(values))
+		for _, v := range values {
+			result = append(result, sdk.NewInt(v))
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name        string
+		balance     int64
+		subBalances []int64
+		level       int64
+		topUps      []int64
+	}{
+		{name: "empty sub-accounts", balance: 90, subBalances: []int64{0, 0}, level: 30, topUps: []int64{30, 30}},
+		{name: "equal already", balance: 30, subBalances: []int64{30, 30}, level: 30, topUps: []int64{0, 0}},
+		{name: "rich sub-account", balance: 30, subBalances: []int64{100, 10}, level: 20, topUps: []int64{0, 10}},
+		{name: "poor funding account", balance: 10, subBalances: []int64{100, 10}, level: 10, topUps: []int64{0, 0}},
+		{name: "empty funding account", balance: 0, subBalances: []int64{5, 0}, level: 0, topUps: []int64{0, 0}},
+		{name: "remainder kept", balance: 100, subBalances: []int64{0, 0}, level: 33, topUps: []int64{33, 33}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			level, topUps := topUpAmounts(sdk.NewInt(tc.balance), ints(tc.subBalances...))
+			assert.Equal(t, sdk.NewInt(tc.level).String(), level.String())
+			assert.Equal(t, ints(tc.topUps...), topUps)
+
+			// the funding account never gives away more than it holds
+			spent := sdk.ZeroInt()
+			for _, topUp := range topUps {
+				spent = spent.Add(topUp)
+			}
+			assert.True(t, spent.LTE(sdk.NewInt(tc.balance)))
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package coreum
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"sort"
+
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/cosmos/cosmos-sdk/crypto/types/multisig"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/coreum/pkg/client"
+)
+
+// treasuryGas is the gas limit of the refill transaction. It can't be simulated before the signatures are collected,
+// so it covers the send verifying the multisig signature of the largest practical number of signers.
+const treasuryGas = 300000
+
+// Treasury is the multisig account refilling the faucet. Its transactions are prepared by the faucet, signed
+// offline by the signers and broadcast by the faucet once enough signatures are collected. Signers sign the JSON
+// of the transaction by `cored tx sign <file> --multisig <treasury> --account-number <n> --sequence <n> --offline`.
+type Treasury struct {
+	client Client
+	pubKey *kmultisig.LegacyAminoPubKey
+}
+
+// Treasury returns the multisig treasury of the signers requiring threshold signatures. Public keys are base64
+// encoded compressed secp256k1 keys, as printed by `cored keys show <name> -p`. They are sorted by address, the same
+// way `cored keys add --multisig` does, so the order they are given in doesn't matter.
+func (c Client) Treasury(pubKeys []string, threshold int) (Treasury, error) {
+	if len(pubKeys) == 0 {
+		return Treasury{}, errors.New("treasury signers are required")
+	}
+	if threshold < 1 || threshold > len(pubKeys) {
+		return Treasury{}, errors.Errorf("treasury threshold must be between 1 and %d, got %d", len(pubKeys), threshold)
+	}
+	keys := make([]cryptotypes.PubKey, 0, len(pubKeys))
+	for _, pubKey := range pubKeys {
+		bz, err := base64.StdEncoding.DecodeString(pubKey)
+		if err != nil || len(bz) != secp256k1.PubKeySize {
+			return Treasury{}, errors.Errorf("invalid public key %q of the treasury signer", pubKey)
+		}
+		keys = append(keys, &secp256k1.PubKey{Key: bz})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i].Address(), keys[j].Address()) < 0
+	})
+	return Treasury{
+		client: c,
+		pubKey: kmultisig.NewLegacyAminoPubKey(threshold, keys),
+	}, nil
+}
+
+// Address returns the address of the multisig account.
+func (t Treasury) Address() sdk.AccAddress {
+	return sdk.AccAddress(t.pubKey.Address())
+}
+
+// Threshold returns the number of signatures required.
+func (t Treasury) Threshold() int {
+	return int(t.pubKey.Threshold)
+}
+
+// Signers returns the addresses of the signers.
+func (t Treasury) Signers() []sdk.AccAddress {
+	signers := make([]sdk.AccAddress, 0, len(t.pubKey.PubKeys))
+	for _, pubKey := range t.pubKey.GetPubKeys() {
+		signers = append(signers, sdk.AccAddress(pubKey.Address()))
+	}
+	return signers
+}
+
+// PrepareSend returns the JSON of the unsigned transaction sending the amount from the treasury to the address,
+// together with the account number and sequence it must be signed with. They are taken from the chain, so
+// the treasury account must exist. Any other transaction of the treasury broadcast before this one invalidates it.
+func (t Treasury) PrepareSend(
+	ctx context.Context,
+	to sdk.AccAddress,
+	amount sdk.Coins,
+) (txJSON []byte, accountNumber, sequence uint64, err error) {
+	clientCtx := t.client.clientCtx
+	acc, err := client.GetAccountInfo(ctx, clientCtx, t.Address())
+	if err != nil {
+		return nil, 0, 0, errors.Wrap(err, "treasury account is not found")
+	}
+	gasPrice, err := client.GetGasPrice(ctx, clientCtx)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	gasPrice.Amount = gasPrice.Amount.Mul(clientCtx.GasPriceAdjustment())
+	txJSON, err = t.buildSend(to, amount, gasPrice, acc.GetAccountNumber(), acc.GetSequence())
+	return txJSON, acc.GetAccountNumber(), acc.GetSequence(), err
+}
+
+func (t Treasury) buildSend(
+	to sdk.AccAddress,
+	amount sdk.Coins,
+	gasPrice sdk.DecCoin,
+	accountNumber, sequence uint64,
+) ([]byte, error) {
+	msg := &banktypes.MsgSend{
+		FromAddress: t.Address().String(),
+		ToAddress:   to.String(),
+		Amount:      amount,
+	}
+	txf := t.client.txf.
+		WithSignMode(signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON).
+		WithAccountNumber(accountNumber).
+		WithSequence(sequence).
+		WithGas(treasuryGas).
+		WithGasPrices(gasPrice.String())
+	builder, err := txf.BuildUnsignedTx(msg)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	txJSON, err := t.client.clientCtx.TxConfig().TxJSONEncoder()(builder.GetTx())
+	return txJSON, errors.WithStack(err)
+}
+
+// VerifySignature verifies the signature of the transaction produced by `cored tx sign --multisig` and returns
+// the address of the signer.
+func (t Treasury) VerifySignature(
+	txJSON []byte,
+	accountNumber, sequence uint64,
+	signature []byte,
+) (sdk.AccAddress, error) {
+	sig, tx, err := t.decodeSignature(txJSON, signature)
+	if err != nil {
+		return nil, err
+	}
+	signerData := authsigning.SignerData{
+		ChainID:       t.client.clientCtx.ChainID(),
+		AccountNumber: accountNumber,
+		Sequence:      sequence,
+	}
+	err = authsigning.VerifySignature(sig.PubKey, signerData, sig.Data, t.client.clientCtx.TxConfig().SignModeHandler(), tx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "signature of %s doesn't match the transaction", sdk.AccAddress(sig.PubKey.Address()))
+	}
+	return sdk.AccAddress(sig.PubKey.Address()), nil
+}
+
+// Broadcast combines the signatures into the signature of the multisig account and broadcasts the transaction.
+// The signatures must be verified by VerifySignature before.
+func (t Treasury) Broadcast(ctx context.Context, txJSON []byte, sequence uint64, signatures [][]byte) (string, error) {
+	txBytes, err := t.combine(txJSON, sequence, signatures)
+	if err != nil {
+		return "", err
+	}
+	res, err := client.BroadcastRawTx(ctx, t.client.clientCtx, txBytes)
+	if err != nil {
+		return "", err
+	}
+	return res.TxHash, nil
+}
+
+// combine returns the encoded transaction signed by the multisig account.
+func (t Treasury) combine(txJSON []byte, sequence uint64, signatures [][]byte) ([]byte, error) {
+	txConfig := t.client.clientCtx.TxConfig()
+	tx, err := txConfig.TxJSONDecoder()(txJSON)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	builder, err := txConfig.WrapTxBuilder(tx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	multisigSig := multisig.NewMultisig(len(t.pubKey.PubKeys))
+	for _, signature := range signatures {
+		sig, _, err := t.decodeSignature(txJSON, signature)
+		if err != nil {
+			return nil, err
+		}
+		if err := multisig.AddSignatureV2(multisigSig, sig, t.pubKey.GetPubKeys()); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	err = builder.SetSignatures(signing.SignatureV2{
+		PubKey:   t.pubKey,
+		Data:     multisigSig,
+		Sequence: sequence,
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	txBytes, err := txConfig.TxEncoder()(builder.GetTx())
+	return txBytes, errors.WithStack(err)
+}
+
+// decodeSignature decodes the single signature of the treasury signer, returning the unsigned transaction it is
+// verified against.
+func (t Treasury) decodeSignature(txJSON, signature []byte) (signing.SignatureV2, authsigning.Tx, error) {
+	txConfig := t.client.clientCtx.TxConfig()
+	sigs, err := txConfig.UnmarshalSignatureJSON(signature)
+	if err != nil {
+		return signing.SignatureV2{}, nil, errors.Wrap(err, "invalid signature")
+	}
+	if len(sigs) != 1 {
+		return signing.SignatureV2{}, nil, errors.Errorf("exactly one signature is expected, got %d", len(sigs))
+	}
+	sig := sigs[0]
+	signer := false
+	for _, pubKey := range t.pubKey.GetPubKeys() {
+		if pubKey.Equals(sig.PubKey) {
+			signer = true
+			break
+		}
+	}
+	if !signer {
+		return signing.SignatureV2{}, nil, errors.Errorf("%s is not the treasury signer",
+			sdk.AccAddress(sig.PubKey.Address()))
+	}
+	tx, err := txConfig.TxJSONDecoder()(txJSON)
+	if err != nil {
+		return signing.SignatureV2{}, nil, errors.WithStack(err)
+	}
+	builder, err := txConfig.WrapTxBuilder(tx)
+	if err != nil {
+		return signing.SignatureV2{}, nil, errors.WithStack(err)
+	}
+	return sig, builder.GetTx(), nil
+}
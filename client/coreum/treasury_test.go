@@ -0,0 +1,98 @@
+package coreum
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	kmultisig "github.com/cosmos/cosmos-sdk/crypto/keys/multisig"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/tx/signing"
+	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/coreum/pkg/config"
+	"github.com/CoreumFoundation/coreum/pkg/config/constant"
+)
+
+func TestTreasury(t *testing.T) {
+	requireT := require.New(t)
+
+	network, err := config.NetworkByChainID(constant.ChainIDDev)
+	requireT.NoError(err)
+	c := New(network, nil, keyring.NewInMemory())
+
+	keys := []*secp256k1.PrivKey{secp256k1.GenPrivKey(), secp256k1.GenPrivKey(), secp256k1.GenPrivKey()}
+	pubKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pubKeys = append(pubKeys, base64.StdEncoding.EncodeToString(key.PubKey().Bytes()))
+	}
+	treasury, err := c.Treasury(pubKeys, 2)
+	requireT.NoError(err)
+	requireT.Equal(2, treasury.Threshold())
+	requireT.Len(treasury.Signers(), 3)
+
+	// address doesn't depend on the order of the keys
+	reversed, err := c.Treasury([]string{pubKeys[2], pubKeys[1], pubKeys[0]}, 2)
+	requireT.NoError(err)
+	requireT.Equal(treasury.Address(), reversed.Address())
+
+	_, err = c.Treasury(pubKeys, 4)
+	requireT.Error(err)
+	_, err = c.Treasury([]string{"invalid"}, 1)
+	requireT.Error(err)
+
+	to := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	const accountNumber, sequence = 7, 3
+	txJSON, err := treasury.buildSend(to, sdk.NewCoins(sdk.NewInt64Coin("udevcore", 1000000)),
+		sdk.NewInt64DecCoin("udevcore", 1), accountNumber, sequence)
+	requireT.NoError(err)
+
+	// signers sign the same way `cored tx sign --multisig` does
+	sign := func(key *secp256k1.PrivKey, sequence uint64) []byte {
+		txConfig := c.clientCtx.TxConfig()
+		decoded, err := txConfig.TxJSONDecoder()(txJSON)
+		requireT.NoError(err)
+		builder, err := txConfig.WrapTxBuilder(decoded)
+		requireT.NoError(err)
+		sig, err := tx.SignWithPrivKey(signing.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, authsigning.SignerData{
+			ChainID:       string(network.ChainID()),
+			AccountNumber: accountNumber,
+			Sequence:      sequence,
+		}, builder, key, txConfig, sequence)
+		requireT.NoError(err)
+		signature, err := txConfig.MarshalSignatureJSON([]signing.SignatureV2{sig})
+		requireT.NoError(err)
+		return signature
+	}
+
+	signatures := [][]byte{sign(keys[0], sequence), sign(keys[2], sequence)}
+	for i, signature := range signatures {
+		signer, err := treasury.VerifySignature(txJSON, accountNumber, sequence, signature)
+		requireT.NoError(err)
+		requireT.Equal(sdk.AccAddress(keys[i*2].PubKey().Address()), signer)
+	}
+
+	// signature of other sequence or by someone else is refused
+	_, err = treasury.VerifySignature(txJSON, accountNumber, sequence, sign(keys[1], sequence-1))
+	requireT.Error(err)
+	_, err = treasury.VerifySignature(txJSON, accountNumber, sequence, sign(secp256k1.GenPrivKey(), sequence))
+	requireT.Error(err)
+
+	txBytes, err := treasury.combine(txJSON, sequence, signatures)
+	requireT.NoError(err)
+	decoded, err := c.clientCtx.TxConfig().TxDecoder()(txBytes)
+	requireT.NoError(err)
+	sigTx := decoded.(authsigning.SigVerifiableTx)
+	sigs, err := sigTx.GetSignaturesV2()
+	requireT.NoError(err)
+	requireT.Len(sigs, 1)
+	requireT.Equal(treasury.Address(), sdk.AccAddress(sigs[0].PubKey.Address()))
+	requireT.NoError(authsigning.VerifySignature(sigs[0].PubKey.(*kmultisig.LegacyAminoPubKey), authsigning.SignerData{
+		ChainID:       string(network.ChainID()),
+		AccountNumber: accountNumber,
+		Sequence:      sequence,
+	}, sigs[0].Data, c.clientCtx.TxConfig().SignModeHandler(), sigTx))
+}
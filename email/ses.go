@@ -0,0 +1,171 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// SESEndpoint is the endpoint of Amazon SES API v2 in the region.
+const SESEndpoint = "https://email.%s.amazonaws.com"
+
+// SESCredentials are the credentials of the IAM user or role allowed to call ses:SendEmail.
+type SESCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is required for the temporary credentials only.
+	SessionToken string
+}
+
+// NewSES returns sender delivering the emails by Amazon SES in the region.
+func NewSES(region string, credentials SESCredentials, from string, client *http.Client) (*SES, error) {
+	if region == "" {
+		return nil, errors.New("SES region is required")
+	}
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return nil, errors.New("SES access key ID and secret access key are required")
+	}
+	return &SES{
+		endpoint:    fmt.Sprintf(SESEndpoint, region),
+		region:      region,
+		credentials: credentials,
+		from:        from,
+		client:      client,
+		now:         time.Now,
+	}, nil
+}
+
+// SES delivers the emails by Amazon SES API v2. Requests are signed by AWS Signature Version 4, so the AWS SDK
+// is not needed.
+type SES struct {
+	endpoint    string
+	region      string
+	credentials SESCredentials
+	from        string
+	client      *http.Client
+	now         func() time.Time
+}
+
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+// SendEmail sends the email.
+func (s *SES) SendEmail(ctx context.Context, msg app.EmailMessage) error {
+	var payload struct {
+		FromEmailAddress string `json:"FromEmailAddress"`
+		Destination      struct {
+			ToAddresses []string `json:"ToAddresses"`
+		} `json:"Destination"`
+		Content struct {
+			Simple struct {
+				Subject sesContent `json:"Subject"`
+				Body    struct {
+					Text sesContent `json:"Text"`
+				} `json:"Body"`
+			} `json:"Simple"`
+		} `json:"Content"`
+	}
+	payload.FromEmailAddress = s.from
+	payload.Destination.ToAddresses = []string{msg.To}
+	payload.Content.Simple.Subject = sesContent{Data: msg.Subject, Charset: "UTF-8"}
+	payload.Content.Simple.Body.Text = sesContent{Data: msg.Body, Charset: "UTF-8"}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+"/v2/email/outbound-emails",
+		bytes.NewReader(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.credentials.SessionToken)
+	}
+	signV4(req, body, s.credentials, s.region, "ses", s.now())
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "sending email by SES failed")
+	}
+	defer res.Body.Close()
+	if res.StatusCode > 299 {
+		resBody, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return errors.Errorf("SES returned non 2xx response, status: %d, body: %s", res.StatusCode, resBody)
+	}
+	return nil
+}
+
+// signV4 signs the request by AWS Signature Version 4, the host, X-Amz-Date and the headers already set
+// on the request are signed.
+func signV4(req *http.Request, body []byte, credentials SESCredentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	canonicalHeaders := &strings.Builder{}
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hashHex(body),
+	}, "\n")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hashHex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,92 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+var testCredentials = SESCredentials{
+	AccessKeyID:     "AKIDEXAMPLE",
+	SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+}
+
+// TestSignV4 verifies the signature against get-vanilla case of the AWS Signature Version 4 test suite.
+func TestSignV4(t *testing.T) {
+	requireT := require.New(t)
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	requireT.NoError(err)
+	signV4(req, nil, testCredentials, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+	requireT.Equal("20150830T123600Z", req.Header.Get("X-Amz-Date"))
+	requireT.Equal("AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		req.Header.Get("Authorization"))
+}
+
+func TestSES(t *testing.T) {
+	requireT := require.New(t)
+
+	var received struct {
+		FromEmailAddress string
+		Destination      struct {
+			ToAddresses []string
+		}
+		Content struct {
+			Simple struct {
+				Subject struct{ Data string }
+				Body    struct {
+					Text struct{ Data string }
+				}
+			}
+		}
+	}
+	var authorization string
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requireT.Equal("/v2/email/outbound-emails", r.URL.Path)
+		authorization = r.Header.Get("Authorization")
+		body, err := io.ReadAll(r.Body)
+		requireT.NoError(err)
+		requireT.NoError(json.Unmarshal(body, &received))
+		if fail {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"Email address is not verified."}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := NewSES("", testCredentials, "faucet@example.com", srv.Client())
+	requireT.Error(err)
+	_, err = NewSES("eu-west-1", SESCredentials{AccessKeyID: "AKIDEXAMPLE"}, "faucet@example.com", srv.Client())
+	requireT.Error(err)
+
+	ses, err := NewSES("eu-west-1", testCredentials, "faucet@example.com", srv.Client())
+	requireT.NoError(err)
+	requireT.Equal("https://email.eu-west-1.amazonaws.com", ses.endpoint)
+	ses.endpoint = srv.URL
+	ses.now = func() time.Time { return time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	msg := app.EmailMessage{To: "dev@example.com", Subject: "Confirm", Body: "Open the link"}
+	requireT.NoError(ses.SendEmail(context.Background(), msg))
+	requireT.Equal("faucet@example.com", received.FromEmailAddress)
+	requireT.Equal([]string{"dev@example.com"}, received.Destination.ToAddresses)
+	requireT.Equal("Confirm", received.Content.Simple.Subject.Data)
+	requireT.Equal("Open the link", received.Content.Simple.Body.Text.Data)
+	requireT.True(strings.HasPrefix(authorization,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20230101/eu-west-1/ses/aws4_request, "+
+			"SignedHeaders=content-type;host;x-amz-date, Signature="))
+
+	fail = true
+	err = ses.SendEmail(context.Background(), msg)
+	requireT.ErrorContains(err, "not verified")
+}
@@ -0,0 +1,107 @@
+// Package email delivers the emails sent to the clients, e.g. the verification links.
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// DialFunc opens connection to the address, e.g. through the outbound proxy.
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// NewSMTP returns sender delivering the emails by the SMTP server. Authentication is skipped if username is empty.
+func NewSMTP(address, username, password, from string, dial DialFunc) SMTP {
+	host := address
+	if i := strings.LastIndex(address, ":"); i >= 0 {
+		host = address[:i]
+	}
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return SMTP{
+		address: address,
+		host:    host,
+		auth:    auth,
+		from:    from,
+		dial:    dial,
+	}
+}
+
+// SMTP delivers the emails by the SMTP server, STARTTLS is used if the server supports it.
+type SMTP struct {
+	address string
+	host    string
+	auth    smtp.Auth
+	from    string
+	dial    DialFunc
+}
+
+// SendEmail sends the email.
+func (s SMTP) SendEmail(ctx context.Context, msg app.EmailMessage) error {
+	// the recipient is typed by the client, so it must not inject headers
+	if strings.ContainsAny(msg.To, "\r\n") {
+		return errors.Errorf("invalid recipient %q", msg.To)
+	}
+	data := &bytes.Buffer{}
+	fmt.Fprintf(data, "From: %s\r\n", s.from)
+	fmt.Fprintf(data, "To: %s\r\n", msg.To)
+	fmt.Fprintf(data, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(data, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(data, "Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	data.WriteString(msg.Body)
+
+	return errors.Wrap(s.sendMail(ctx, msg.To, data.Bytes()), "sending email failed")
+}
+
+// sendMail does the same as smtp.SendMail, but over the connection opened by the dial function.
+func (s SMTP) sendMail(ctx context.Context, to string, data []byte) error {
+	conn, err := s.dial(ctx, "tcp", s.address)
+	if err != nil {
+		return err
+	}
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close()
+		return errors.WithStack(err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if s.auth != nil {
+		if err := client.Auth(s.auth); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if err := client.Mail(s.from); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return errors.WithStack(err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := w.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(client.Quit())
+}
@@ -0,0 +1,229 @@
+// Package failover coordinates active/standby deployment of the faucet. Only the instance holding the lease
+// sends funds, standby instances keep verifying their readiness and take over once the lease of the active
+// instance lapses or when they are promoted explicitly.
+package failover
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+// inFlightGrace covers the fundings accepted by the previous holder just before its lease lapsed, which might
+// still be in progress. It must be longer than the timeout of sending a batch of transfers.
+const inFlightGrace = 30 * time.Second
+
+// ErrNotReady is returned if the instance is promoted while its readiness checks fail.
+var ErrNotReady = errors.New("instance is not ready")
+
+// Role of the instance.
+type Role string
+
+// Instance roles.
+const (
+	// RoleActive means the instance holds the lease and sends funds.
+	RoleActive Role = "active"
+	// RolePromoting means the instance holds the lease but waits until the previous holder surely stopped.
+	RolePromoting Role = "promoting"
+	// RoleStandby means the instance doesn't hold the lease.
+	RoleStandby Role = "standby"
+)
+
+// Check verifies one aspect of the readiness of the instance to send funds.
+type Check struct {
+	Name string
+	Fn   func(ctx context.Context) error
+}
+
+// Status describes the state of the instance.
+type Status struct {
+	InstanceID  string
+	Role        Role
+	Lease       Lease
+	ActiveFrom  time.Time
+	ActiveUntil time.Time
+	// Checks maps the name of the readiness check to its error, empty if the check passed.
+	Checks map[string]string
+}
+
+// NewCoordinator returns new coordinator. The lease is renewed every third of its ttl. The expiry of the lease
+// is compared across the instances, so the clock must be the wall clock, not the one fast-forwarded by the admin.
+func NewCoordinator(lease *FileLease, instanceID string, ttl time.Duration, clock clock.Clock, checks ...Check) *Coordinator {
+	return &Coordinator{
+		lease:      lease,
+		instanceID: instanceID,
+		ttl:        ttl,
+		clock:      clock,
+		checks:     checks,
+		results:    map[string]string{},
+	}
+}
+
+// Coordinator acquires and renews the lease on behalf of the instance.
+type Coordinator struct {
+	lease      *FileLease
+	instanceID string
+	ttl        time.Duration
+	clock      clock.Clock
+	checks     []Check
+
+	mu          sync.RWMutex
+	current     Lease
+	activeFrom  time.Time
+	activeUntil time.Time
+	results     map[string]string
+}
+
+// IsActive tells if the instance is allowed to send funds now.
+func (c *Coordinator) IsActive() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.isActive(c.clock.Now())
+}
+
+func (c *Coordinator) isActive(now time.Time) bool {
+	return !now.Before(c.activeFrom) && now.Before(c.activeUntil)
+}
+
+// Status returns the state of the instance.
+func (c *Coordinator) Status() Status {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.clock.Now()
+	role := RoleStandby
+	switch {
+	case c.isActive(now):
+		role = RoleActive
+	case c.current.Holder == c.instanceID && now.Before(c.activeFrom):
+		role = RolePromoting
+	}
+	checks := make(map[string]string, len(c.results))
+	for name, result := range c.results {
+		checks[name] = result
+	}
+	return Status{
+		InstanceID:  c.instanceID,
+		Role:        role,
+		Lease:       c.current,
+		ActiveFrom:  c.activeFrom,
+		ActiveUntil: c.activeUntil,
+		Checks:      checks,
+	}
+}
+
+// Run renews the lease if the instance holds it, or acquires it once the lease of the active instance lapses.
+func (c *Coordinator) Run(ctx context.Context) error {
+	log := logger.Get(ctx)
+	for {
+		if err := c.tick(ctx); err != nil {
+			log.Error("Failover coordination failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return errors.WithStack(ctx.Err())
+		case <-time.After(c.ttl / 3):
+		}
+	}
+}
+
+// Promote takes the lease over from the active instance. The instance starts sending funds once the lease
+// of the previous holder would have expired, so fundings are never sent by both instances.
+func (c *Coordinator) Promote(ctx context.Context) (Status, error) {
+	if !c.verify(ctx) {
+		return c.Status(), errors.WithStack(ErrNotReady)
+	}
+
+	var previous Lease
+	now := c.clock.Now()
+	lease, err := c.lease.Update(func(current Lease) (Lease, bool) {
+		previous = current
+		if current.Holder == c.instanceID {
+			return current, false
+		}
+		return Lease{Holder: c.instanceID, Epoch: current.Epoch + 1, ExpiresAt: now.Add(c.ttl)}, true
+	})
+	if err != nil {
+		return c.Status(), err
+	}
+
+	c.mu.Lock()
+	if previous.Holder != c.instanceID {
+		c.activeFrom = now
+		if previous.Holder != "" && previous.ExpiresAt.Add(inFlightGrace).After(now) {
+			c.activeFrom = previous.ExpiresAt.Add(inFlightGrace)
+		}
+	}
+	c.current = lease
+	c.activeUntil = lease.ExpiresAt
+	c.mu.Unlock()
+
+	logger.Get(ctx).Info("Instance promoted", zap.Time("activeFrom", c.activeFrom), zap.Uint64("epoch", lease.Epoch))
+	return c.Status(), nil
+}
+
+func (c *Coordinator) tick(ctx context.Context) error {
+	ready := c.verify(ctx)
+	now := c.clock.Now()
+	acquired := false
+	lease, err := c.lease.Update(func(current Lease) (Lease, bool) {
+		switch {
+		case !ready:
+			// lease is not renewed, so the standby takes over once it lapses
+			return current, false
+		case current.Holder == c.instanceID:
+			current.ExpiresAt = now.Add(c.ttl)
+			return current, true
+		case current.Holder == "" || now.After(current.ExpiresAt.Add(inFlightGrace)):
+			acquired = true
+			return Lease{Holder: c.instanceID, Epoch: current.Epoch + 1, ExpiresAt: now.Add(c.ttl)}, true
+		}
+		return current, false
+	})
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	wasActive := c.isActive(now)
+	c.current = lease
+	switch {
+	case lease.Holder != c.instanceID:
+		c.activeUntil = time.Time{}
+	case acquired:
+		c.activeFrom = now
+		c.activeUntil = lease.ExpiresAt
+	default:
+		c.activeUntil = lease.ExpiresAt
+	}
+	if active := c.isActive(now); active != wasActive {
+		logger.Get(ctx).Info("Instance role changed", zap.Bool("active", active), zap.String("holder", lease.Holder),
+			zap.Uint64("epoch", lease.Epoch))
+	}
+	return nil
+}
+
+func (c *Coordinator) verify(ctx context.Context) bool {
+	results := make(map[string]string, len(c.checks))
+	ready := true
+	for _, check := range c.checks {
+		results[check.Name] = ""
+		if err := check.Fn(ctx); err != nil {
+			results[check.Name] = err.Error()
+			ready = false
+		}
+	}
+
+	c.mu.Lock()
+	c.results = results
+	c.mu.Unlock()
+	return ready
+}
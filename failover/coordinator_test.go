@@ -0,0 +1,74 @@
+package failover
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+func TestCoordinatorFailover(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	path := filepath.Join(t.TempDir(), "lease.json")
+	ttl := 30 * time.Second
+
+	standbyErr := errors.New("chain unreachable")
+	active := NewCoordinator(NewFileLease(path), "a", ttl, clk)
+	standby := NewCoordinator(NewFileLease(path), "b", ttl, clk, Check{
+		Name: "chain",
+		Fn: func(ctx context.Context) error {
+			return standbyErr
+		},
+	})
+
+	requireT.NoError(active.tick(ctx))
+	requireT.NoError(standby.tick(ctx))
+	assertT.True(active.IsActive())
+	assertT.False(standby.IsActive())
+	assertT.Equal("chain unreachable", standby.Status().Checks["chain"])
+
+	// standby not ready can't be promoted
+	_, err := standby.Promote(ctx)
+	assertT.ErrorIs(err, ErrNotReady)
+	standbyErr = nil
+
+	// active stops renewing, standby takes over only after the lease and in-flight grace pass
+	clk.Advance(ttl)
+	assertT.False(active.IsActive())
+	requireT.NoError(standby.tick(ctx))
+	assertT.False(standby.IsActive())
+	clk.Advance(inFlightGrace + time.Second)
+	requireT.NoError(standby.tick(ctx))
+	assertT.True(standby.IsActive())
+	assertT.Equal(uint64(2), standby.Status().Lease.Epoch)
+
+	// previous active notices it lost the lease
+	requireT.NoError(active.tick(ctx))
+	assertT.False(active.IsActive())
+	assertT.Equal(RoleStandby, active.Status().Role)
+
+	// promotion of the previous active waits until the lease of the current one would have expired
+	status, err := active.Promote(ctx)
+	requireT.NoError(err)
+	assertT.Equal(RolePromoting, status.Role)
+	requireT.NoError(standby.tick(ctx))
+	assertT.False(standby.IsActive())
+	assertT.False(active.IsActive())
+
+	clk.Advance(ttl + inFlightGrace)
+	requireT.NoError(active.tick(ctx))
+	assertT.True(active.IsActive())
+	assertT.Equal(uint64(3), active.Status().Lease.Epoch)
+}
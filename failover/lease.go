@@ -0,0 +1,92 @@
+package failover
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Lease grants the right to send funds to the holder until it expires.
+type Lease struct {
+	Holder string `json:"holder"`
+	// Epoch is increased every time the lease changes the holder, so stale holders might detect they were replaced.
+	Epoch     uint64    `json:"epoch"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// NewFileLease returns the lease kept in the file, which must be on the storage shared by all the instances.
+func NewFileLease(path string) *FileLease {
+	return &FileLease{path: path}
+}
+
+// FileLease keeps the lease in the file. Updates are serialized by an exclusive lock on a sibling lock file.
+type FileLease struct {
+	path string
+}
+
+// Update reads the lease, passes it to the function and stores the lease returned by it, while holding the lock.
+// If the function returns false, the lease is not modified.
+func (l *FileLease) Update(fn func(current Lease) (Lease, bool)) (Lease, error) {
+	unlock, err := lockFile(l.path + ".lock")
+	if err != nil {
+		return Lease{}, err
+	}
+	defer unlock()
+
+	current, err := l.read()
+	if err != nil {
+		return Lease{}, err
+	}
+	updated, ok := fn(current)
+	if !ok {
+		return current, nil
+	}
+	if err := l.write(updated); err != nil {
+		return Lease{}, err
+	}
+	return updated, nil
+}
+
+func (l *FileLease) read() (Lease, error) {
+	content, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Lease{}, nil
+	}
+	if err != nil {
+		return Lease{}, errors.Wrapf(err, "unable to read lease from %s", l.path)
+	}
+	var lease Lease
+	if err := json.Unmarshal(content, &lease); err != nil {
+		return Lease{}, errors.Wrapf(err, "unable to decode lease from %s", l.path)
+	}
+	return lease, nil
+}
+
+// write replaces the file atomically, so the lease is never read partially written.
+func (l *FileLease) write(lease Lease) error {
+	content, err := json.Marshal(lease)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".*")
+	if err != nil {
+		return errors.Wrap(err, "unable to create lease file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "unable to write lease file")
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return errors.Wrap(err, "unable to write lease file")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "unable to write lease file")
+	}
+	return errors.Wrap(os.Rename(tmp.Name(), l.path), "unable to replace lease file")
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package failover
+
+// lockFile is a no-op on systems not supporting flock, so the instances rely on atomic replacement of the lease
+// file only.
+func lockFile(string) (func(), error) {
+	return func() {}, nil
+}
@@ -0,0 +1,366 @@
+package http
+
+import (
+	"crypto/subtle"
+	nethttp "net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/config"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+	"github.com/CoreumFoundation/faucet/pkg/pagination"
+)
+
+const (
+	defaultClusterReportPeriod = 24 * time.Hour
+	defaultReconcilePeriod     = 24 * time.Hour
+	defaultConfigChangesPeriod = 30 * 24 * time.Hour
+)
+
+// maxActorLength bounds the actor recorded with the configuration changes.
+const maxActorLength = 64
+
+func adminAuthMiddleware(token string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(c http.Context) error {
+			if !validAdminToken(c, token) {
+				return errors.Wrap(ErrUnauthorized, "invalid admin token")
+			}
+			return next(c)
+		}
+	}
+}
+
+// adminAuthorized tells if the request carries the admin token, it is always false if admin API is disabled.
+func (h HTTP) adminAuthorized(c http.Context) bool {
+	return h.cfg.AdminToken != "" && validAdminToken(c, h.cfg.AdminToken)
+}
+
+// adminActor returns the operator named by the X-Faucet-Actor header, or the IP of the caller if it is not set.
+func adminActor(c http.Context) string {
+	actor := strings.TrimSpace(c.Request().Header.Get(HeaderXFaucetActor))
+	if actor == "" {
+		return "admin@" + c.RealIP()
+	}
+	if len(actor) > maxActorLength {
+		actor = actor[:maxActorLength]
+	}
+	return actor
+}
+
+func validAdminToken(c http.Context, token string) bool {
+	provided := strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
+
+// ClusterResponse describes a cluster of linked addresses.
+type ClusterResponse struct {
+	Addresses    []string  `json:"addresses"`
+	IPs          []string  `json:"ips"`
+	Fingerprints []string  `json:"fingerprints"`
+	Requests     int       `json:"requests"`
+	TotalAmount  string    `json:"totalAmount"`
+	FirstSeen    time.Time `json:"firstSeen"`
+	LastSeen     time.Time `json:"lastSeen"`
+	Periodic     bool      `json:"periodic"`
+}
+
+// ClusterReportResponse is the output to /admin/reports/clusters request.
+type ClusterReportResponse struct {
+	Since    time.Time         `json:"since"`
+	Clusters []ClusterResponse `json:"clusters"`
+}
+
+func (h HTTP) clusterReportHandle(ctx http.Context) error {
+	period, err := periodFromQuery(ctx, defaultClusterReportPeriod)
+	if err != nil {
+		return err
+	}
+	minSize := 2
+	if s := ctx.QueryParam("minSize"); s != "" {
+		if minSize, err = strconv.Atoi(s); err != nil {
+			return errors.Wrapf(ErrInvalidQuery, "invalid minSize: %s", err)
+		}
+	}
+
+	since := h.app.Now().Add(-period)
+	clusters, err := h.app.ClusterReport(ctx.Request().Context(), since, minSize)
+	if err != nil {
+		return err
+	}
+
+	resp := ClusterReportResponse{
+		Since:    since,
+		Clusters: []ClusterResponse{},
+	}
+	for _, c := range clusters {
+		resp.Clusters = append(resp.Clusters, ClusterResponse{
+			Addresses:    c.Addresses,
+			IPs:          c.IPs,
+			Fingerprints: c.Fingerprints,
+			Requests:     c.Requests,
+			TotalAmount:  c.TotalAmount.String(),
+			FirstSeen:    c.FirstSeen,
+			LastSeen:     c.LastSeen,
+			Periodic:     c.Periodic,
+		})
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
+
+// LedgerAccountResponse identifies the ledger account.
+type LedgerAccountResponse struct {
+	Kind    string `json:"kind"`
+	ChainID string `json:"chainId"`
+	Tenant  string `json:"tenant,omitempty"`
+	Session string `json:"session,omitempty"`
+}
+
+// LedgerBalanceResponse describes the balance of the ledger account.
+type LedgerBalanceResponse struct {
+	Account LedgerAccountResponse `json:"account"`
+	Debits  string                `json:"debits"`
+	Credits string                `json:"credits"`
+	Balance []string              `json:"balance"`
+}
+
+// LedgerBalancesResponse is the output to /admin/ledger/balances request.
+type LedgerBalancesResponse struct {
+	Balances []LedgerBalanceResponse `json:"balances"`
+}
+
+func (h HTTP) ledgerBalancesHandle(ctx http.Context) error {
+	balances, err := h.app.LedgerBalances(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	resp := LedgerBalancesResponse{Balances: []LedgerBalanceResponse{}}
+	for _, b := range balances {
+		balance := []string{}
+		for _, coin := range b.Debits.Add(b.Credits...) {
+			balance = append(balance, b.Balance(coin.Denom).String()+coin.Denom)
+		}
+		resp.Balances = append(resp.Balances, LedgerBalanceResponse{
+			Account: LedgerAccountResponse(b.Account),
+			Debits:  b.Debits.String(),
+			Credits: b.Credits.String(),
+			Balance: balance,
+		})
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
+
+// LedgerAllocationRequest is the input to /admin/ledger/allocations request.
+type LedgerAllocationRequest struct {
+	Tenant  string `json:"tenant"`
+	Session string `json:"session"`
+	Amount  string `json:"amount"`
+}
+
+func (h HTTP) ledgerAllocationHandle(ctx http.Context) error {
+	var rqBody LedgerAllocationRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+	amount, err := chain.ParseCoinNormalized(rqBody.Amount)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidRequest, "invalid amount: %s", err)
+	}
+
+	if err := h.app.AllocateBudget(ctx.Request().Context(), rqBody.Tenant, rqBody.Session, amount); err != nil {
+		return err
+	}
+	return ctx.NoContent(nethttp.StatusNoContent)
+}
+
+// LedgerDiscrepancyResponse describes the mismatch found by ledger reconciliation.
+type LedgerDiscrepancyResponse struct {
+	Kind    string `json:"kind"`
+	TxHash  string `json:"txHash"`
+	Message string `json:"message"`
+}
+
+// LedgerDiscrepanciesResponse is the output to /admin/ledger/discrepancies request.
+type LedgerDiscrepanciesResponse struct {
+	Since         time.Time                   `json:"since"`
+	Discrepancies []LedgerDiscrepancyResponse `json:"discrepancies"`
+}
+
+func (h HTTP) ledgerDiscrepanciesHandle(ctx http.Context) error {
+	period, err := periodFromQuery(ctx, defaultReconcilePeriod)
+	if err != nil {
+		return err
+	}
+
+	since := h.app.Now().Add(-period)
+	discrepancies, err := h.app.ReconcileLedger(ctx.Request().Context(), since)
+	if err != nil {
+		return err
+	}
+
+	resp := LedgerDiscrepanciesResponse{
+		Since:         since,
+		Discrepancies: []LedgerDiscrepancyResponse{},
+	}
+	for _, d := range discrepancies {
+		resp.Discrepancies = append(resp.Discrepancies, LedgerDiscrepancyResponse(d))
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
+
+// RecipientResponse describes the address book entry.
+type RecipientResponse struct {
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// RecipientsResponse is the output to /admin/address-book request.
+type RecipientsResponse struct {
+	Recipients []RecipientResponse `json:"recipients"`
+}
+
+func (h HTTP) recipientsHandle(ctx http.Context) error {
+	recipients, err := h.app.Recipients(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	resp := RecipientsResponse{Recipients: []RecipientResponse{}}
+	for _, r := range recipients {
+		resp.Recipients = append(resp.Recipients, RecipientResponse(r))
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
+
+// PutRecipientRequest is the input to PUT /admin/address-book/:name request.
+type PutRecipientRequest struct {
+	Address string `json:"address"`
+}
+
+func (h HTTP) putRecipientHandle(ctx http.Context) error {
+	var rqBody PutRecipientRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+
+	recipient, err := h.app.PutRecipient(ctx.Request().Context(), ctx.Param("name"), rqBody.Address)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, RecipientResponse(recipient))
+}
+
+func (h HTTP) deleteRecipientHandle(ctx http.Context) error {
+	if err := h.app.DeleteRecipient(ctx.Request().Context(), ctx.Param("name")); err != nil {
+		return err
+	}
+	return ctx.NoContent(nethttp.StatusNoContent)
+}
+
+// FastForwardRequest is the input to /admin/clock/fast-forward request.
+type FastForwardRequest struct {
+	Duration string `json:"duration"`
+}
+
+// FastForwardResponse is the output to /admin/clock/fast-forward request.
+type FastForwardResponse struct {
+	Now time.Time `json:"now"`
+}
+
+func (h HTTP) fastForwardHandle(ctx http.Context) error {
+	var rqBody FastForwardRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(rqBody.Duration)
+	if err != nil || duration <= 0 {
+		return errors.Wrapf(ErrInvalidRequest, "duration must be positive, got %q", rqBody.Duration)
+	}
+
+	return ctx.JSON(nethttp.StatusOK, FastForwardResponse{Now: h.cfg.FastForwardClock.Advance(duration).UTC()})
+}
+
+// ConfigChangesResponse is the output to /admin/config/changes request.
+type ConfigChangesResponse struct {
+	Changes    []app.ConfigChange `json:"changes"`
+	NextCursor string             `json:"nextCursor,omitempty"`
+}
+
+// configChangesSpec lists the configuration changes, the oldest first by default.
+var configChangesSpec = pagination.Spec[app.ConfigChange]{
+	ID: func(c app.ConfigChange) string { return c.ID },
+	Sorts: map[string]func(c app.ConfigChange) string{
+		"time":  func(c app.ConfigChange) string { return pagination.TimeKey(c.Time) },
+		"actor": func(c app.ConfigChange) string { return c.Actor },
+	},
+	DefaultSort: "time",
+	Filters: map[string]func(c app.ConfigChange, value string) bool{
+		"actor":  func(c app.ConfigChange, value string) bool { return c.Actor == value },
+		"source": func(c app.ConfigChange, value string) bool { return c.Source == value },
+	},
+}
+
+func (h HTTP) configChangesHandle(ctx http.Context) error {
+	since, err := sinceFromQuery(ctx, h.app.Now(), defaultConfigChangesPeriod)
+	if err != nil {
+		return err
+	}
+	changes, err := h.app.ConfigChanges(ctx.Request().Context(), since)
+	if err != nil {
+		return err
+	}
+	page, err := listPage(ctx, configChangesSpec, changes)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, ConfigChangesResponse{Changes: page.Items, NextCursor: page.NextCursor})
+}
+
+// sinceFromQuery returns the time given by `since` query parameter, or the start of the `period` ending now
+// if it is not set.
+func sinceFromQuery(ctx http.Context, now time.Time, defaultPeriod time.Duration) (time.Time, error) {
+	if s := ctx.QueryParam("since"); s != "" {
+		since, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(ErrInvalidQuery, "invalid since: %s", err)
+		}
+		return since, nil
+	}
+	period, err := periodFromQuery(ctx, defaultPeriod)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return now.Add(-period), nil
+}
+
+func periodFromQuery(ctx http.Context, defaultPeriod time.Duration) (time.Duration, error) {
+	p := ctx.QueryParam("period")
+	if p == "" {
+		return defaultPeriod, nil
+	}
+	period, err := time.ParseDuration(p)
+	if err != nil {
+		return 0, errors.Wrapf(ErrInvalidQuery, "invalid period: %s", err)
+	}
+	return period, nil
+}
+
+// ConfigResponse is the output to /admin/config request.
+type ConfigResponse struct {
+	Flags []config.Entry `json:"flags"`
+}
+
+func (h HTTP) configHandle(ctx http.Context) error {
+	resp := ConfigResponse{Flags: h.cfg.EffectiveConfig}
+	if resp.Flags == nil {
+		resp.Flags = []config.Entry{}
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
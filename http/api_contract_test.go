@@ -0,0 +1,649 @@
+package http
+
+import (
+	"context"
+	"net"
+	nethttp "net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/attribution"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+	"github.com/CoreumFoundation/faucet/pkg/config"
+	"github.com/CoreumFoundation/faucet/pkg/geoip"
+	"github.com/CoreumFoundation/faucet/pkg/iplist"
+	"github.com/CoreumFoundation/faucet/pkg/iprep"
+	"github.com/CoreumFoundation/faucet/scheduler"
+	"github.com/CoreumFoundation/faucet/store"
+)
+
+const (
+	contractAdminToken = "admin-token"
+	contractAddress    = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+	contractTxHash     = "4C2F5B4E0F6D7A3B1C9E8D2A6B5F4E3D2C1B0A9F8E7D6C5B4A3F2E1D0C9B8A7F"
+	// throttledIP is the IP which has already used its rate limit.
+	throttledIP = "203.0.113.99"
+	// deniedIP is the IP denied by the IP lists.
+	deniedIP = "198.51.100.7"
+	// blockedCountryIP is the IP located in the country blocked by GeoIP.
+	blockedCountryIP = "192.0.2.44"
+	// torExitIP is the IP flagged as Tor exit by the IP reputation.
+	torExitIP = "192.0.2.66"
+)
+
+var (
+	contractNow   = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	sdkConfigOnce sync.Once
+)
+
+type contractLimiter struct{}
+
+func (contractLimiter) IsRequestAllowed(ip net.IP) bool {
+	return !ip.Equal(net.ParseIP(throttledIP))
+}
+
+func (contractLimiter) NextAllowedAt(ip net.IP) time.Time {
+	return contractNow.Add(time.Hour)
+}
+
+func (contractLimiter) Remaining(ip net.IP) uint64 {
+	if ip.Equal(net.ParseIP(throttledIP)) {
+		return 0
+	}
+	return 2
+}
+
+type contractCountries struct{}
+
+func (contractCountries) Country(ip net.IP) (string, error) {
+	if ip.Equal(net.ParseIP(blockedCountryIP)) {
+		return "KP", nil
+	}
+	return "DE", nil
+}
+
+type contractReputation struct{}
+
+func (contractReputation) Category(_ context.Context, ip net.IP) (iprep.Category, error) {
+	if ip.Equal(net.ParseIP(torExitIP)) {
+		return iprep.CategoryTor, nil
+	}
+	return "", nil
+}
+
+type contractSigningError struct{}
+
+func (contractSigningError) Error() string {
+	return "key backend unavailable"
+}
+
+func (contractSigningError) SigningFailure() bool {
+	return true
+}
+
+// contractBatcher includes all the requests in the same transaction.
+type contractBatcher struct {
+	txTracker   *app.TxTracker
+	err         error
+	broadcasted sync.Once
+}
+
+func (b *contractBatcher) SendToken(
+	ctx context.Context,
+	destAddress chain.AccAddress,
+	amount chain.Coin,
+	feeDenom string,
+	attr attribution.Attribution,
+) (string, chain.Coin, error) {
+	if b.err != nil {
+		return "", chain.Coin{}, b.err
+	}
+	b.broadcasted.Do(func() {
+		b.txTracker.TxBroadcast(contractTxHash, 10, nil)
+	})
+	return contractTxHash, chain.NewCoin("udevcore", chain.NewInt(5)), nil
+}
+
+func (b *contractBatcher) Backlog() (int, time.Duration) {
+	return 0, 0
+}
+
+type contractChain struct{}
+
+func (contractChain) TxHeight(ctx context.Context, txHash string) (int64, error) {
+	return 10, nil
+}
+
+func (contractChain) LatestHeight(ctx context.Context) (int64, error) {
+	return 12, nil
+}
+
+func (contractChain) BroadcastRawTx(ctx context.Context, txBytes []byte) error {
+	return nil
+}
+
+// contractMetadata defines the display unit of the gas price denom only, so coins with and without metadata
+// are covered.
+type contractMetadata struct{}
+
+func (contractMetadata) DenomMetadata(ctx context.Context, denom string) (chain.DenomMetadata, error) {
+	if denom != "udevcore" {
+		return chain.DenomMetadata{}, nil
+	}
+	return chain.DenomMetadata{
+		Base:    "udevcore",
+		Display: "devcore",
+		Symbol:  "DEVCORE",
+		DenomUnits: []*chain.DenomUnit{
+			{Denom: "udevcore", Exponent: 0},
+			{Denom: "devcore", Exponent: 6},
+		},
+	}, nil
+}
+
+// newContractServer returns the server of the contract tests, the options configure the optional features of the app.
+func newContractServer(t testing.TB, options ...func(a app.App) app.App) (nethttp.Handler, *contractBatcher) {
+	requireT := require.New(t)
+
+	db, err := store.Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		requireT.NoError(db.Close())
+	})
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	// addresses are rendered in the responses the same way as by the running faucet, the config is sealed once set
+	sdkConfigOnce.Do(network.SetSDKConfig)
+
+	txTracker := app.NewTxTracker(contractChain{}, 1, nil, clock.System{})
+	batcher := &contractBatcher{txTracker: txTracker}
+	a := app.New(
+		batcher,
+		contractChain{},
+		txTracker,
+		db,
+		db,
+		network,
+		chain.NewCoin(network.Denom(), chain.NewInt(1000000)),
+	).
+		WithClock(clock.NewManual(contractNow)).
+		WithAddressBook(db).
+		WithClaimCodes(db).
+		WithConfigAudit(db).
+		WithBypassTokens(db).
+		WithBlocklist(db).
+		WithDenomMetadata(contractMetadata{})
+	for _, option := range options {
+		a = option(a)
+	}
+
+	denylist := filepath.Join(t.TempDir(), "denylist.txt")
+	requireT.NoError(os.WriteFile(denylist, []byte("198.51.100.0/24 # abusive range\n"), 0o600))
+	ipFilter := iplist.NewFilter("", denylist, nil)
+	requireT.NoError(ipFilter.Reload(context.Background()))
+	geoRestriction, err := geoip.NewRestriction(contractCountries{}, nil, []string{"KP"})
+	requireT.NoError(err)
+
+	log := zaptest.NewLogger(t)
+	if _, ok := t.(*testing.B); ok {
+		// benchmarks measure the handlers, logging each request would dominate them
+		log = zap.NewNop()
+	}
+
+	jobs := scheduler.New(db, clock.NewManual(contractNow))
+	jobs.Add(scheduler.Job{Name: "gc", Interval: time.Hour, Run: func(ctx context.Context) error { return nil }})
+
+	h := New(a, contractLimiter{}, Config{
+		AdminToken:         contractAdminToken,
+		Environment:        "devnet",
+		IPFilter:           ipFilter,
+		GeoIP:              geoRestriction,
+		IPReputation:       contractReputation{},
+		IPReputationAction: iprep.ActionBlock,
+		Scheduler:          jobs,
+		EffectiveConfig: []config.Entry{
+			{Name: "admin-token", Value: config.Redacted, Source: config.SourceEnv},
+			{Name: "chain-id", Value: "coreum-devnet-1", Source: config.SourceDefault},
+			{Name: "transfer-amount", Value: "1000000", Source: config.SourceFlag},
+		},
+		SnapshotSources: map[string]func() interface{}{
+			"limiters": func() interface{} { return map[string]int{"ip": 1} },
+		},
+	}, log)
+	h.registerRoutes()
+	return h.server, batcher
+}
+
+func adminHeaders() map[string]string {
+	return map[string]string{"Authorization": "Bearer " + contractAdminToken}
+}
+
+// TestAPIContract verifies the responses of the endpoints against golden files, so renamed fields or changed
+// error kinds break the build before they break the clients.
+func TestAPIContract(t *testing.T) {
+	handler, batcher := newContractServer(t)
+
+	// cases run against the shared server in order, so they may depend on the state created by previous ones
+	cases := []goldenCase{
+		{name: "status", method: nethttp.MethodGet, path: "/api/faucet/v1/status"},
+		{name: "readyz", method: nethttp.MethodGet, path: "/readyz"},
+		{name: "network", method: nethttp.MethodGet, path: "/api/faucet/v1/network"},
+		{
+			name:   "fund",
+			method: nethttp.MethodPost,
+			path:   "/api/faucet/v1/fund",
+			body:   `{"address":"` + contractAddress + `"}`,
+		},
+		{
+			name:   "fund_get",
+			method: nethttp.MethodGet,
+			path:   "/api/faucet/v1/fund?address=" + contractAddress,
+		},
+		{name: "tx", method: nethttp.MethodGet, path: "/api/faucet/v1/tx/" + contractTxHash},
+		{
+			name:   "requests_batch_get",
+			method: nethttp.MethodPost,
+			path:   "/api/faucet/v1/requests:batchGet",
+			body:   `{"requestIds":["rq-fund","rq-unknown"]}`,
+		},
+		{
+			name:   "requests_batch_get_empty",
+			method: nethttp.MethodPost,
+			path:   "/api/faucet/v1/requests:batchGet",
+			body:   `{"requestIds":[]}`,
+		},
+		{
+			name:     "gen_funded",
+			method:   nethttp.MethodPost,
+			path:     "/api/faucet/v1/gen-funded",
+			volatile: []string{"mnemonic", "address"},
+		},
+		{name: "stats", method: nethttp.MethodGet, path: "/api/faucet/v1/stats"},
+		{name: "tx_unknown", method: nethttp.MethodGet, path: "/api/faucet/v1/tx/ABCDEF"},
+		{
+			name:   "fund_invalid_address",
+			method: nethttp.MethodPost,
+			path:   "/api/faucet/v1/fund",
+			body:   `{"address":"invalid"}`,
+		},
+		{
+			name:   "fund_prefix_mismatch",
+			method: nethttp.MethodPost,
+			path:   "/api/faucet/v1/fund",
+			body:   `{"address":"core10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"}`,
+		},
+		{
+			name:   "fund_invalid_body",
+			method: nethttp.MethodPost,
+			path:   "/api/faucet/v1/fund",
+			body:   `{"address":`,
+		},
+		{
+			name:   "fund_callback_disabled",
+			method: nethttp.MethodPost,
+			path:   "/api/faucet/v1/fund",
+			body:   `{"address":"` + contractAddress + `","callbackUrl":"https://hooks.example.com/done"}`,
+		},
+		{
+			name:   "fund_invalid_confirmations",
+			method: nethttp.MethodPost,
+			path:   "/api/faucet/v1/fund",
+			body:   `{"address":"` + contractAddress + `","minConfirmations":1000}`,
+		},
+		{
+			name:   "fund_invoice_unauthorized",
+			method: nethttp.MethodPost,
+			path:   "/api/faucet/v1/fund",
+			body:   `{"address":"` + contractAddress + `","invoiceId":"INV-1001"}`,
+		},
+		{
+			name:     "fund_rate_limited",
+			method:   nethttp.MethodPost,
+			path:     "/api/faucet/v1/fund",
+			body:     `{"address":"` + contractAddress + `"}`,
+			remoteIP: throttledIP,
+		},
+		{
+			name:   "quota",
+			method: nethttp.MethodGet,
+			path:   "/api/faucet/v1/quota?address=" + contractAddress,
+		},
+		{
+			name:     "quota_throttled",
+			method:   nethttp.MethodGet,
+			path:     "/api/faucet/v1/quota",
+			remoteIP: throttledIP,
+		},
+		{
+			name:   "quota_invalid_address",
+			method: nethttp.MethodGet,
+			path:   "/api/faucet/v1/quota?address=invalid",
+		},
+		{
+			name:     "fund_ip_denied",
+			method:   nethttp.MethodPost,
+			path:     "/api/faucet/v1/fund",
+			body:     `{"address":"` + contractAddress + `"}`,
+			remoteIP: deniedIP,
+		},
+		{
+			name:     "fund_country_denied",
+			method:   nethttp.MethodPost,
+			path:     "/api/faucet/v1/fund",
+			body:     `{"address":"` + contractAddress + `"}`,
+			remoteIP: blockedCountryIP,
+		},
+		{
+			name:     "fund_ip_reputation",
+			method:   nethttp.MethodPost,
+			path:     "/api/faucet/v1/fund",
+			body:     `{"address":"` + contractAddress + `"}`,
+			remoteIP: torExitIP,
+		},
+		{name: "qr_invalid_address", method: nethttp.MethodGet, path: "/api/faucet/v1/qr?address=invalid"},
+		{
+			name:   "qr_invalid_size",
+			method: nethttp.MethodGet,
+			path:   "/api/faucet/v1/qr?address=" + contractAddress + "&size=10000",
+		},
+		{
+			name:   "qr_link_unavailable",
+			method: nethttp.MethodGet,
+			path:   "/api/faucet/v1/qr?address=" + contractAddress + "&link=true",
+		},
+		{
+			name:    "fund_bypass_token_invalid",
+			method:  nethttp.MethodPost,
+			path:    "/api/faucet/v1/fund",
+			body:    `{"address":"` + contractAddress + `"}`,
+			headers: map[string]string{HeaderXFaucetToken: "fbt_unknown"},
+		},
+		{
+			name:   "fund_recipient_unauthorized",
+			method: nethttp.MethodPost,
+			path:   "/api/faucet/v1/fund",
+			body:   `{"recipient":"alice"}`,
+		},
+		{
+			name:   "claim_not_found",
+			method: nethttp.MethodPost,
+			path:   "/api/faucet/v1/claim",
+			body:   `{"code":"AAAA-BBBB-CCCC-DDDD","address":"` + contractAddress + `"}`,
+		},
+		{name: "not_found", method: nethttp.MethodGet, path: "/api/faucet/v1/unknown"},
+		{name: "admin_unauthorized", method: nethttp.MethodGet, path: "/api/faucet/v1/admin/address-book"},
+		{
+			name:    "admin_address_book_put",
+			method:  nethttp.MethodPut,
+			path:    "/api/faucet/v1/admin/address-book/alice",
+			body:    `{"address":"` + contractAddress + `"}`,
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_address_book_list",
+			method:  nethttp.MethodGet,
+			path:    "/api/faucet/v1/admin/address-book",
+			headers: adminHeaders(),
+		},
+		{
+			name:    "fund_recipient",
+			method:  nethttp.MethodPost,
+			path:    "/api/faucet/v1/fund",
+			body:    `{"recipient":"alice"}`,
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_address_book_delete",
+			method:  nethttp.MethodDelete,
+			path:    "/api/faucet/v1/admin/address-book/alice",
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_address_book_delete_not_found",
+			method:  nethttp.MethodDelete,
+			path:    "/api/faucet/v1/admin/address-book/alice",
+			headers: adminHeaders(),
+		},
+		{
+			name:     "admin_claim_codes_create",
+			method:   nethttp.MethodPost,
+			path:     "/api/faucet/v1/admin/claim-codes",
+			body:     `{"amount":"1000udevcore","uses":2,"count":1}`,
+			headers:  adminHeaders(),
+			volatile: []string{"code"},
+		},
+		{
+			name:     "admin_claim_codes_create_expiring",
+			method:   nethttp.MethodPost,
+			path:     "/api/faucet/v1/admin/claim-codes",
+			body:     `{"amount":"1000udevcore","expiresAt":"2026-01-03T03:04:05Z"}`,
+			headers:  adminHeaders(),
+			volatile: []string{"code"},
+		},
+		{
+			name:     "admin_claim_codes_list",
+			method:   nethttp.MethodGet,
+			path:     "/api/faucet/v1/admin/claim-codes?status=active&sort=expiresAt&limit=1",
+			headers:  adminHeaders(),
+			volatile: []string{"code", "nextCursor"},
+		},
+		{
+			name:     "admin_expiring",
+			method:   nethttp.MethodGet,
+			path:     "/api/faucet/v1/admin/expiring?within=48h",
+			headers:  adminHeaders(),
+			volatile: []string{"id"},
+		},
+		{
+			name:    "admin_expiring_extend_unsupported",
+			method:  nethttp.MethodPut,
+			path:    "/api/faucet/v1/admin/expiring/challenge/abc",
+			body:    `{"expiresAt":"2026-01-03T03:04:05Z"}`,
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_jobs",
+			method:  nethttp.MethodGet,
+			path:    "/api/faucet/v1/admin/jobs",
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_job_run",
+			method:  nethttp.MethodPost,
+			path:    "/api/faucet/v1/admin/jobs/gc/run",
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_job_run_not_found",
+			method:  nethttp.MethodPost,
+			path:    "/api/faucet/v1/admin/jobs/missing/run",
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_claim_codes_invalid",
+			method:  nethttp.MethodPost,
+			path:    "/api/faucet/v1/admin/claim-codes",
+			body:    `{"amount":"invalid","count":1}`,
+			headers: adminHeaders(),
+		},
+		{
+			name:     "admin_bypass_tokens_create",
+			method:   nethttp.MethodPost,
+			path:     "/api/faucet/v1/admin/bypass-tokens",
+			body:     `{"holder":"ci","quota":500,"period":"24h"}`,
+			headers:  adminHeaders(),
+			volatile: []string{"id", "token"},
+		},
+		{
+			name:     "admin_bypass_tokens_list",
+			method:   nethttp.MethodGet,
+			path:     "/api/faucet/v1/admin/bypass-tokens",
+			headers:  adminHeaders(),
+			volatile: []string{"id"},
+		},
+		{
+			name:    "admin_bypass_tokens_invalid",
+			method:  nethttp.MethodPost,
+			path:    "/api/faucet/v1/admin/bypass-tokens",
+			body:    `{"holder":"ci"}`,
+			headers: adminHeaders(),
+		},
+		{
+			name:     "admin_history",
+			method:   nethttp.MethodGet,
+			path:     "/api/faucet/v1/admin/history?since=2000-01-01T00:00:00Z&address=" + contractAddress + "&limit=1",
+			headers:  adminHeaders(),
+			volatile: []string{"requestId", "nextCursor"},
+		},
+		{
+			name:    "admin_history_invalid_sort",
+			method:  nethttp.MethodGet,
+			path:    "/api/faucet/v1/admin/history?sort=amount",
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_ledger_balances",
+			method:  nethttp.MethodGet,
+			path:    "/api/faucet/v1/admin/ledger/balances",
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_ledger_allocation_invalid",
+			method:  nethttp.MethodPost,
+			path:    "/api/faucet/v1/admin/ledger/allocations",
+			body:    `{"amount":"invalid"}`,
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_config",
+			method:  nethttp.MethodGet,
+			path:    "/api/faucet/v1/admin/config",
+			headers: adminHeaders(),
+		},
+		{
+			name:     "admin_snapshot",
+			method:   nethttp.MethodGet,
+			path:     "/api/faucet/v1/admin/snapshot",
+			headers:  adminHeaders(),
+			volatile: []string{"broadcastAt", "address"},
+		},
+		{
+			name:    "admin_pause",
+			method:  nethttp.MethodPost,
+			path:    "/api/faucet/v1/admin/controls/pause",
+			body:    `{"reason":"scheduled maintenance"}`,
+			headers: map[string]string{"Authorization": "Bearer " + contractAdminToken, "X-Faucet-Actor": "alice"},
+		},
+		// the only change recorded so far, changes recorded at the same time are not ordered
+		{
+			name:     "admin_config_changes",
+			method:   nethttp.MethodGet,
+			path:     "/api/faucet/v1/admin/config/changes?since=2000-01-01T00:00:00Z",
+			headers:  adminHeaders(),
+			volatile: []string{"id"},
+		},
+		{
+			name:   "fund_paused",
+			method: nethttp.MethodPost,
+			path:   "/api/faucet/v1/fund",
+			body:   `{"address":"` + contractAddress + `"}`,
+		},
+		{name: "readyz_paused", method: nethttp.MethodGet, path: "/readyz"},
+		{
+			name:    "admin_resume",
+			method:  nethttp.MethodPost,
+			path:    "/api/faucet/v1/admin/controls/resume",
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_transfer_amount_set",
+			method:  nethttp.MethodPut,
+			path:    "/api/faucet/v1/admin/controls/transfer-amount",
+			body:    `{"amount":"2000000"}`,
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_transfer_amount_invalid",
+			method:  nethttp.MethodPut,
+			path:    "/api/faucet/v1/admin/controls/transfer-amount",
+			body:    `{"amount":"-1"}`,
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_controls",
+			method:  nethttp.MethodGet,
+			path:    "/api/faucet/v1/admin/controls",
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_block_address",
+			method:  nethttp.MethodPut,
+			path:    "/api/faucet/v1/admin/blocked-addresses/" + contractAddress,
+			body:    `{"reason":"abuse"}`,
+			headers: adminHeaders(),
+		},
+		{
+			name:   "fund_blocked",
+			method: nethttp.MethodPost,
+			path:   "/api/faucet/v1/fund",
+			body:   `{"address":"` + contractAddress + `"}`,
+		},
+		{
+			name:    "admin_blocked_addresses",
+			method:  nethttp.MethodGet,
+			path:    "/api/faucet/v1/admin/blocked-addresses",
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_unblock_address",
+			method:  nethttp.MethodDelete,
+			path:    "/api/faucet/v1/admin/blocked-addresses/" + contractAddress,
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_unblock_address_not_blocked",
+			method:  nethttp.MethodDelete,
+			path:    "/api/faucet/v1/admin/blocked-addresses/" + contractAddress,
+			headers: adminHeaders(),
+		},
+		{
+			name:    "admin_clusters",
+			method:  nethttp.MethodGet,
+			path:    "/api/faucet/v1/admin/reports/clusters",
+			headers: adminHeaders(),
+			// the cluster includes the generated account
+			volatile: []string{"addresses"},
+		},
+	}
+	for _, tc := range cases {
+		assertGolden(t, handler, tc)
+	}
+
+	// failures of the chain are reported after all the successful cases, because signing failure suspends funding
+	batcher.err = errors.New("connection refused")
+	assertGolden(t, handler, goldenCase{
+		name:   "fund_transfer_failed",
+		method: nethttp.MethodPost,
+		path:   "/api/faucet/v1/fund",
+		body:   `{"address":"` + contractAddress + `"}`,
+	})
+	batcher.err = errors.WithStack(contractSigningError{})
+	assertGolden(t, handler, goldenCase{
+		name:   "fund_signing_unavailable",
+		method: nethttp.MethodPost,
+		path:   "/api/faucet/v1/fund",
+		body:   `{"address":"` + contractAddress + `"}`,
+	})
+	assertGolden(t, handler, goldenCase{name: "readyz_signing_unavailable", method: nethttp.MethodGet, path: "/readyz"})
+}
@@ -0,0 +1,223 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	nethttp "net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/pkg/attribution"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+	"github.com/CoreumFoundation/faucet/pkg/limiter"
+	"github.com/CoreumFoundation/faucet/pkg/pagination"
+)
+
+// HeaderXAPIKey authenticates the client holding the API key.
+const HeaderXAPIKey = "X-Api-Key"
+
+const (
+	contextKeyAPIKeyHolder = "apiKeyHolder"
+	contextKeyAPIKeyHash   = "apiKeyHash"
+)
+
+// APIKeys authenticates the clients having dedicated quota instead of the IP rate limit, e.g. CI pipelines.
+type APIKeys struct {
+	// Holders maps the API key to the name of its holder.
+	Holders map[string]string
+	// Quota limits the requests of each holder.
+	Quota *limiter.QuotaLimiter
+}
+
+// issuedAPIKeyPrefix marks the API keys issued by the admin API.
+const issuedAPIKeyPrefix = "fk_"
+
+var holderNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,64}$`)
+
+// apiKeyRegistry holds the API keys configured on startup and the ones issued by the admin API. Issued keys
+// are kept in memory only, so they must be added to the configuration to survive restart.
+type apiKeyRegistry struct {
+	mu      sync.RWMutex
+	holders map[string]string
+	issued  map[string]bool
+}
+
+// apiKeyEntry describes the API key without revealing it.
+type apiKeyEntry struct {
+	Hash   string
+	Holder string
+	Issued bool
+}
+
+func newAPIKeyRegistry(holders map[string]string) *apiKeyRegistry {
+	r := &apiKeyRegistry{holders: map[string]string{}, issued: map[string]bool{}}
+	for key, holder := range holders {
+		r.holders[key] = holder
+	}
+	return r
+}
+
+func (r *apiKeyRegistry) holder(key string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	holder, ok := r.holders[key]
+	return holder, ok
+}
+
+// issue generates new API key of the holder. Holder may have many keys sharing its quota.
+func (r *apiKeyRegistry) issue(holder string) (string, error) {
+	if !holderNameRegexp.MatchString(holder) {
+		return "", errors.Wrap(ErrInvalidRequest, "holder must be 1-64 letters, digits, dots, dashes or underscores")
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.WithStack(err)
+	}
+	key := issuedAPIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.holders[key] = holder
+	r.issued[key] = true
+	return key, nil
+}
+
+// list returns the keys identified by their hashes.
+func (r *apiKeyRegistry) list() []apiKeyEntry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]apiKeyEntry, 0, len(r.holders))
+	for key, holder := range r.holders {
+		entries = append(entries, apiKeyEntry{Hash: attribution.HashAPIKey(key), Holder: holder, Issued: r.issued[key]})
+	}
+	return entries
+}
+
+// apiKeyMiddleware authenticates the holder of the API key if the request contains one.
+func apiKeyMiddleware(keys *apiKeyRegistry) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(c http.Context) error {
+			key := c.Request().Header.Get(HeaderXAPIKey)
+			if key == "" {
+				return next(c)
+			}
+			holder, ok := keys.holder(key)
+			if !ok {
+				return errors.Wrap(ErrUnauthorized, "invalid API key")
+			}
+			c.Set(contextKeyAPIKeyHolder, holder)
+			c.Set(contextKeyAPIKeyHash, attribution.HashAPIKey(key))
+			return next(c)
+		}
+	}
+}
+
+// apiKeyHolder returns the holder of the API key the request is authenticated with.
+func apiKeyHolder(c http.Context) (string, bool) {
+	holder, ok := c.Get(contextKeyAPIKeyHolder).(string)
+	return holder, ok
+}
+
+// QuotaUsageResponse describes the usage of the quota in the current window.
+type QuotaUsageResponse struct {
+	Period    string    `json:"period"`
+	Limit     uint64    `json:"limit"`
+	Consumed  uint64    `json:"consumed"`
+	Remaining uint64    `json:"remaining"`
+	ResetsAt  time.Time `json:"resetsAt"`
+}
+
+// KeyUsageResponse is the output to /keys/self/usage request.
+type KeyUsageResponse struct {
+	Holder string               `json:"holder"`
+	Quotas []QuotaUsageResponse `json:"quotas"`
+}
+
+func (h HTTP) keyUsageHandle(ctx http.Context) error {
+	holder, ok := apiKeyHolder(ctx)
+	if !ok {
+		return errors.Wrapf(ErrUnauthorized, "%s header is required", HeaderXAPIKey)
+	}
+
+	resp := KeyUsageResponse{Holder: holder, Quotas: []QuotaUsageResponse{}}
+	for _, u := range h.cfg.APIKeys.Quota.Usage(holder) {
+		resp.Quotas = append(resp.Quotas, QuotaUsageResponse{
+			Period:    u.Quota.Period.String(),
+			Limit:     u.Quota.Limit,
+			Consumed:  u.Consumed,
+			Remaining: u.Remaining,
+			ResetsAt:  u.ResetsAt.UTC(),
+		})
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
+
+// IssueAPIKeyRequest is the input to /admin/api-keys request.
+type IssueAPIKeyRequest struct {
+	Holder string `json:"holder"`
+}
+
+// IssueAPIKeyResponse is the output to /admin/api-keys request. The key is never returned again.
+type IssueAPIKeyResponse struct {
+	Holder string `json:"holder"`
+	Key    string `json:"key"`
+}
+
+func (h HTTP) issueAPIKeyHandle(ctx http.Context) error {
+	var rqBody IssueAPIKeyRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+	key, err := h.apiKeys.issue(rqBody.Holder)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusCreated, IssueAPIKeyResponse{Holder: rqBody.Holder, Key: key})
+}
+
+// APIKeyResponse describes the API key, the key itself is identified by its hash, the one reported with
+// the fundings.
+type APIKeyResponse struct {
+	Hash   string `json:"hash"`
+	Holder string `json:"holder"`
+	// Issued tells the key was issued by the admin API, so it is lost on restart.
+	Issued bool `json:"issued"`
+}
+
+// APIKeysResponse is the output to GET /admin/api-keys request.
+type APIKeysResponse struct {
+	APIKeys    []APIKeyResponse `json:"apiKeys"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+}
+
+// apiKeysSpec lists the API keys ordered by holder by default.
+var apiKeysSpec = pagination.Spec[apiKeyEntry]{
+	ID: func(e apiKeyEntry) string { return e.Hash },
+	Sorts: map[string]func(e apiKeyEntry) string{
+		"holder": func(e apiKeyEntry) string { return e.Holder },
+		"hash":   func(e apiKeyEntry) string { return e.Hash },
+	},
+	DefaultSort: "holder",
+	Filters: map[string]func(e apiKeyEntry, value string) bool{
+		"holder": func(e apiKeyEntry, value string) bool { return e.Holder == value },
+		"issued": func(e apiKeyEntry, value string) bool { return strconv.FormatBool(e.Issued) == value },
+	},
+}
+
+func (h HTTP) apiKeysHandle(ctx http.Context) error {
+	page, err := listPage(ctx, apiKeysSpec, h.apiKeys.list())
+	if err != nil {
+		return err
+	}
+	resp := APIKeysResponse{APIKeys: make([]APIKeyResponse, 0, len(page.Items)), NextCursor: page.NextCursor}
+	for _, e := range page.Items {
+		resp.APIKeys = append(resp.APIKeys, APIKeyResponse{Hash: e.Hash, Holder: e.Holder, Issued: e.Issued})
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
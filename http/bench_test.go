@@ -0,0 +1,59 @@
+package http
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CoreumFoundation/faucet/pkg/latency"
+)
+
+// benchmarkEndpoint sends the requests to the handler in parallel and reports the throughput and the latency
+// percentiles of the handler, the chain is faked, so the numbers are the overhead of the faucet itself.
+// Run by `go test ./http -run '^$' -bench . -benchmem`, `-json` flag makes the output machine-readable.
+func benchmarkEndpoint(b *testing.B, path, body string) {
+	handler, _ := newContractServer(b)
+
+	var mu sync.Mutex
+	latencies := make([]time.Duration, 0, b.N)
+	b.ReportAllocs()
+	b.ResetTimer()
+	started := time.Now()
+	b.RunParallel(func(pb *testing.PB) {
+		local := []time.Duration{}
+		for pb.Next() {
+			req := httptest.NewRequest(nethttp.MethodPost, path, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			sent := time.Now()
+			handler.ServeHTTP(rec, req)
+			local = append(local, time.Since(sent))
+			if rec.Code != nethttp.StatusOK {
+				b.Errorf("unexpected status %d: %s", rec.Code, rec.Body.String())
+				return
+			}
+		}
+		mu.Lock()
+		latencies = append(latencies, local...)
+		mu.Unlock()
+	})
+	elapsed := time.Since(started)
+	b.StopTimer()
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	b.ReportMetric(float64(len(latencies))/elapsed.Seconds(), "req/s")
+	b.ReportMetric(latency.PercentileMS(latencies, 50), "p50-ms")
+	b.ReportMetric(latency.PercentileMS(latencies, 99), "p99-ms")
+}
+
+func BenchmarkFund(b *testing.B) {
+	benchmarkEndpoint(b, "/api/faucet/v1/fund", `{"address":"`+contractAddress+`"}`)
+}
+
+func BenchmarkGenFunded(b *testing.B) {
+	benchmarkEndpoint(b, "/api/faucet/v1/gen-funded", "")
+}
@@ -0,0 +1,137 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+	"github.com/CoreumFoundation/faucet/pkg/pagination"
+)
+
+// HeaderXFaucetToken carries the bypass token exempting the request from the rate limits.
+const HeaderXFaucetToken = "X-Faucet-Token"
+
+const contextKeyBypassToken = "bypassToken"
+
+// BypassTokenResponse describes the bypass token. The token itself is returned only once it is created.
+type BypassTokenResponse struct {
+	ID          string     `json:"id"`
+	Token       string     `json:"token,omitempty"`
+	Holder      string     `json:"holder"`
+	Quota       uint64     `json:"quota"`
+	Period      string     `json:"period,omitempty"`
+	Used        uint64     `json:"used"`
+	WindowStart time.Time  `json:"windowStart"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+}
+
+// BypassTokensResponse is the output to GET /admin/bypass-tokens request.
+type BypassTokensResponse struct {
+	BypassTokens []BypassTokenResponse `json:"bypassTokens"`
+	NextCursor   string                `json:"nextCursor,omitempty"`
+}
+
+// CreateBypassTokenRequest is the input to POST /admin/bypass-tokens request.
+type CreateBypassTokenRequest struct {
+	Holder string `json:"holder"`
+	// Quota is the number of requests allowed in the period.
+	Quota uint64 `json:"quota"`
+	// Period is the duration the quota is renewed after, e.g. "24h", the quota lasts for the lifetime
+	// of the token if it is empty.
+	Period string `json:"period"`
+	// ExpiresAt is the time the token expires at, it never expires if it is not set.
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+func (h HTTP) createBypassTokenHandle(ctx http.Context) error {
+	var rqBody CreateBypassTokenRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+	spec := app.BypassTokenSpec{
+		Holder: rqBody.Holder,
+		Quota:  rqBody.Quota,
+	}
+	if rqBody.Period != "" {
+		period, err := time.ParseDuration(rqBody.Period)
+		if err != nil {
+			return errors.Wrapf(ErrInvalidRequest, "invalid period: %s", err)
+		}
+		spec.Period = period
+	}
+	if rqBody.ExpiresAt != nil {
+		spec.ExpiresAt = *rqBody.ExpiresAt
+	}
+
+	token, raw, err := h.app.CreateBypassToken(ctx.Request().Context(), spec)
+	if err != nil {
+		return err
+	}
+	resp := bypassTokenResponse(token)
+	resp.Token = raw
+	return ctx.JSON(nethttp.StatusCreated, resp)
+}
+
+// bypassTokensSpec lists the bypass tokens ordered by ID by default.
+var bypassTokensSpec = pagination.Spec[app.BypassToken]{
+	ID: func(t app.BypassToken) string { return t.ID },
+	Sorts: map[string]func(t app.BypassToken) string{
+		"id":        func(t app.BypassToken) string { return t.ID },
+		"holder":    func(t app.BypassToken) string { return t.Holder },
+		"createdAt": func(t app.BypassToken) string { return pagination.TimeKey(t.CreatedAt) },
+		"expiresAt": func(t app.BypassToken) string { return pagination.TimeKey(t.ExpiresAt) },
+	},
+	DefaultSort: "id",
+	Filters: map[string]func(t app.BypassToken, value string) bool{
+		"holder": func(t app.BypassToken, value string) bool { return t.Holder == value },
+	},
+}
+
+func (h HTTP) bypassTokensHandle(ctx http.Context) error {
+	tokens, err := h.app.BypassTokens(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+	page, err := listPage(ctx, bypassTokensSpec, tokens)
+	if err != nil {
+		return err
+	}
+	resp := BypassTokensResponse{
+		BypassTokens: make([]BypassTokenResponse, 0, len(page.Items)),
+		NextCursor:   page.NextCursor,
+	}
+	for _, t := range page.Items {
+		resp.BypassTokens = append(resp.BypassTokens, bypassTokenResponse(t))
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
+
+func (h HTTP) deleteBypassTokenHandle(ctx http.Context) error {
+	if err := h.app.DeleteBypassToken(ctx.Request().Context(), ctx.Param("id")); err != nil {
+		return err
+	}
+	return ctx.NoContent(nethttp.StatusNoContent)
+}
+
+func bypassTokenResponse(token app.BypassToken) BypassTokenResponse {
+	resp := BypassTokenResponse{
+		ID:          token.ID,
+		Holder:      token.Holder,
+		Quota:       token.Quota,
+		Used:        token.Used,
+		WindowStart: token.WindowStart,
+		CreatedAt:   token.CreatedAt,
+	}
+	if token.Period > 0 {
+		resp.Period = token.Period.String()
+	}
+	if !token.ExpiresAt.IsZero() {
+		expiresAt := token.ExpiresAt
+		resp.ExpiresAt = &expiresAt
+	}
+	return resp
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// CreateChallengeRequest is the input to /challenges request.
+type CreateChallengeRequest struct {
+	Address string `json:"address"`
+}
+
+// ChallengeResponse is the output to /challenges request.
+type ChallengeResponse struct {
+	ID         string    `json:"id"`
+	Address    string    `json:"address"`
+	Memo       string    `json:"memo"`
+	Dust       string    `json:"dust"`
+	DustTxHash string    `json:"dustTxHash"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+func (h HTTP) createChallengeHandle(ctx http.Context) error {
+	var rqBody CreateChallengeRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	challenge, err := h.app.CreateChallenge(ctx.Request().Context(), requester, rqBody.Address)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusCreated, ChallengeResponse{
+		ID:         challenge.ID,
+		Address:    challenge.Address,
+		Memo:       challenge.Memo,
+		Dust:       challenge.Dust.String(),
+		DustTxHash: challenge.DustTxHash,
+		ExpiresAt:  challenge.ExpiresAt,
+	})
+}
+
+// CompleteChallengeRequest is the input to /challenges/:id/complete request.
+type CompleteChallengeRequest struct {
+	// TxHash is the hash of the transaction signed by the challenged address and carrying the memo of the challenge.
+	TxHash string `json:"txHash"`
+}
+
+func (h HTTP) completeChallengeHandle(ctx http.Context) error {
+	var rqBody CompleteChallengeRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	txHash, err := h.app.CompleteChallenge(ctx.Request().Context(), requester, ctx.Param("id"), rqBody.TxHash)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, FundResponse{TxHash: txHash})
+}
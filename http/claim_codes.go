@@ -0,0 +1,228 @@
+package http
+
+import (
+	"encoding/csv"
+	nethttp "net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+	"github.com/CoreumFoundation/faucet/pkg/pagination"
+)
+
+const mimeTextCSV = "text/csv"
+
+var claimCodeCSVHeader = []string{"code", "amount", "max_uses", "uses", "address", "created_at", "expires_at"}
+
+// ClaimRequest is the input to /claim request.
+type ClaimRequest struct {
+	Code string `json:"code"`
+	// Address may be omitted if the code is bound to the address.
+	Address string `json:"address"`
+}
+
+// ClaimResponse is the output to /claim request.
+type ClaimResponse struct {
+	Address  string   `json:"address"`
+	Amount   string   `json:"amount"`
+	TxHashes []string `json:"txHashes"`
+	// Coins is the amount enriched with the display units.
+	Coins []CoinResponse `json:"coins"`
+}
+
+func (h HTTP) claimHandle(ctx http.Context) error {
+	var rqBody ClaimRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.app.Claim(ctx.Request().Context(), requester, rqBody.Code, rqBody.Address)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, ClaimResponse{
+		Address:  result.Address,
+		Amount:   result.Amount.String(),
+		TxHashes: result.TxHashes,
+		Coins:    h.coinResponses(ctx.Request().Context(), result.Amount),
+	})
+}
+
+// ClaimCodeResponse describes the claim code.
+type ClaimCodeResponse struct {
+	Code      string     `json:"code"`
+	Amount    string     `json:"amount"`
+	MaxUses   int        `json:"maxUses"`
+	Uses      int        `json:"uses"`
+	Address   string     `json:"address,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// ClaimCodesResponse is the output to /admin/claim-codes requests.
+type ClaimCodesResponse struct {
+	ClaimCodes []ClaimCodeResponse `json:"claimCodes"`
+	NextCursor string              `json:"nextCursor,omitempty"`
+}
+
+// CreateClaimCodesRequest is the input to POST /admin/claim-codes request.
+type CreateClaimCodesRequest struct {
+	// Amount is the comma-separated list of coins sent on each use, e.g. "1000000ucore,500uatom".
+	Amount string `json:"amount"`
+	// Uses is the number of times each code may be claimed, 1 by default.
+	Uses int `json:"uses"`
+	// Address binds the codes to the address.
+	Address string `json:"address"`
+	// Count is the number of codes to generate, 1 by default.
+	Count int `json:"count"`
+	// ExpiresAt is the time the codes expire at, they never expire if it is not set.
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+func (h HTTP) createClaimCodesHandle(ctx http.Context) error {
+	var rqBody CreateClaimCodesRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+	amount, err := chain.ParseCoinsNormalized(rqBody.Amount)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidRequest, "invalid amount: %s", err)
+	}
+	if rqBody.Uses == 0 {
+		rqBody.Uses = 1
+	}
+	if rqBody.Count == 0 {
+		rqBody.Count = 1
+	}
+
+	spec := app.ClaimCodeSpec{
+		Amount:  amount,
+		MaxUses: rqBody.Uses,
+		Address: rqBody.Address,
+	}
+	if rqBody.ExpiresAt != nil {
+		spec.ExpiresAt = *rqBody.ExpiresAt
+	}
+
+	codes, err := h.app.CreateClaimCodes(ctx.Request().Context(), spec, rqBody.Count)
+	if err != nil {
+		return err
+	}
+	return writeClaimCodes(ctx, nethttp.StatusCreated, codes, "")
+}
+
+// claimCodesSpec lists the claim codes ordered by code by default. Status filter is one of active, usedUp
+// and expired at the time.
+func claimCodesSpec(now time.Time) pagination.Spec[app.ClaimCode] {
+	status := func(c app.ClaimCode) string {
+		switch {
+		case !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt):
+			return "expired"
+		case c.Uses >= c.MaxUses:
+			return "usedUp"
+		default:
+			return "active"
+		}
+	}
+	return pagination.Spec[app.ClaimCode]{
+		ID: func(c app.ClaimCode) string { return c.Code },
+		Sorts: map[string]func(c app.ClaimCode) string{
+			"code":      func(c app.ClaimCode) string { return c.Code },
+			"createdAt": func(c app.ClaimCode) string { return pagination.TimeKey(c.CreatedAt) },
+			"expiresAt": func(c app.ClaimCode) string { return pagination.TimeKey(c.ExpiresAt) },
+			"uses":      func(c app.ClaimCode) string { return pagination.UintKey(uint64(c.Uses)) },
+		},
+		DefaultSort: "code",
+		Filters: map[string]func(c app.ClaimCode, value string) bool{
+			"address": func(c app.ClaimCode, value string) bool { return c.Address == value },
+			"status":  func(c app.ClaimCode, value string) bool { return status(c) == value },
+		},
+	}
+}
+
+func (h HTTP) claimCodesHandle(ctx http.Context) error {
+	codes, err := h.app.ClaimCodes(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+	page, err := listPage(ctx, claimCodesSpec(h.app.Now()), codes)
+	if err != nil {
+		return err
+	}
+	return writeClaimCodes(ctx, nethttp.StatusOK, page.Items, page.NextCursor)
+}
+
+func (h HTTP) deleteClaimCodeHandle(ctx http.Context) error {
+	if err := h.app.DeleteClaimCode(ctx.Request().Context(), ctx.Param("code")); err != nil {
+		return err
+	}
+	return ctx.NoContent(nethttp.StatusNoContent)
+}
+
+// writeClaimCodes responds with CSV if requested by format=csv query parameter or Accept header, so the codes
+// may be imported directly into the mailing or printing tool of the event organizers. The cursor of the next page
+// is passed in the X-Next-Cursor header of CSV responses.
+func writeClaimCodes(ctx http.Context, status int, codes []app.ClaimCode, nextCursor string) error {
+	if ctx.QueryParam("format") != "csv" && !strings.Contains(ctx.Request().Header.Get(echo.HeaderAccept), mimeTextCSV) {
+		resp := ClaimCodesResponse{ClaimCodes: []ClaimCodeResponse{}, NextCursor: nextCursor}
+		for _, c := range codes {
+			code := ClaimCodeResponse{
+				Code:      c.Code,
+				Amount:    c.Amount.String(),
+				MaxUses:   c.MaxUses,
+				Uses:      c.Uses,
+				Address:   c.Address,
+				CreatedAt: c.CreatedAt,
+			}
+			if !c.ExpiresAt.IsZero() {
+				expiresAt := c.ExpiresAt
+				code.ExpiresAt = &expiresAt
+			}
+			resp.ClaimCodes = append(resp.ClaimCodes, code)
+		}
+		return ctx.JSON(status, resp)
+	}
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, mimeTextCSV+"; charset=utf-8")
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="claim-codes.csv"`)
+	if nextCursor != "" {
+		res.Header().Set(HeaderXNextCursor, nextCursor)
+	}
+	res.WriteHeader(status)
+	w := csv.NewWriter(res)
+	if err := w.Write(claimCodeCSVHeader); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, c := range codes {
+		var expiresAt string
+		if !c.ExpiresAt.IsZero() {
+			expiresAt = c.ExpiresAt.Format(time.RFC3339)
+		}
+		err := w.Write([]string{
+			c.Code,
+			c.Amount.String(),
+			strconv.Itoa(c.MaxUses),
+			strconv.Itoa(c.Uses),
+			c.Address,
+			c.CreatedAt.Format(time.RFC3339),
+			expiresAt,
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	w.Flush()
+	return errors.WithStack(w.Error())
+}
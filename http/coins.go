@@ -0,0 +1,44 @@
+package http
+
+import (
+	"context"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// CoinResponse is the coin in the base denom, enriched with the display unit if the denom has on-chain metadata.
+type CoinResponse struct {
+	Denom   string               `json:"denom"`
+	Amount  string               `json:"amount"`
+	Display *DisplayCoinResponse `json:"display,omitempty"`
+}
+
+// DisplayCoinResponse is the coin expressed in the display unit, so UIs may render e.g. "1.00 TESTCORE"
+// without querying the metadata.
+type DisplayCoinResponse struct {
+	Denom    string `json:"denom"`
+	Symbol   string `json:"symbol,omitempty"`
+	Decimals uint32 `json:"decimals"`
+	Amount   string `json:"amount"`
+}
+
+func (h HTTP) coinResponse(ctx context.Context, coin chain.Coin) CoinResponse {
+	resp := CoinResponse{Denom: coin.Denom, Amount: coin.Amount.String()}
+	if display, ok := h.app.DisplayCoin(ctx, coin); ok {
+		resp.Display = &DisplayCoinResponse{
+			Denom:    display.Denom,
+			Symbol:   display.Symbol,
+			Decimals: display.Decimals,
+			Amount:   display.Amount,
+		}
+	}
+	return resp
+}
+
+func (h HTTP) coinResponses(ctx context.Context, coins chain.Coins) []CoinResponse {
+	resp := make([]CoinResponse, 0, len(coins))
+	for _, coin := range coins {
+		resp = append(resp, h.coinResponse(ctx, coin))
+	}
+	return resp
+}
@@ -0,0 +1,163 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// ControlsResponse is the output to /admin/controls requests.
+type ControlsResponse struct {
+	Paused         bool       `json:"paused"`
+	PauseReason    string     `json:"pauseReason,omitempty"`
+	PausedAt       *time.Time `json:"pausedAt,omitempty"`
+	TransferAmount string     `json:"transferAmount"`
+}
+
+func controlsResponse(controls app.Controls) ControlsResponse {
+	resp := ControlsResponse{
+		Paused:         controls.Paused,
+		PauseReason:    controls.PauseReason,
+		TransferAmount: controls.TransferAmount.String(),
+	}
+	if controls.Paused {
+		resp.PausedAt = &controls.PausedAt
+	}
+	return resp
+}
+
+func (h HTTP) controlsHandle(ctx http.Context) error {
+	return ctx.JSON(nethttp.StatusOK, controlsResponse(h.app.Controls()))
+}
+
+// PauseRequest is the input to /admin/controls/pause request.
+type PauseRequest struct {
+	// Reason is returned to the clients whose requests are rejected.
+	Reason string `json:"reason"`
+}
+
+func (h HTTP) pauseHandle(ctx http.Context) error {
+	var rqBody PauseRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, controlsResponse(h.app.Pause(ctx.Request().Context(), adminActor(ctx), rqBody.Reason)))
+}
+
+func (h HTTP) resumeHandle(ctx http.Context) error {
+	return ctx.JSON(nethttp.StatusOK, controlsResponse(h.app.Resume(ctx.Request().Context(), adminActor(ctx))))
+}
+
+// SetTransferAmountRequest is the input to /admin/controls/transfer-amount request.
+type SetTransferAmountRequest struct {
+	// Amount is the integer amount in the denom of the configured transfer amount.
+	Amount string `json:"amount"`
+}
+
+func (h HTTP) setTransferAmountHandle(ctx http.Context) error {
+	var rqBody SetTransferAmountRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+	amount, ok := chain.NewIntFromString(rqBody.Amount)
+	if !ok {
+		return errors.Wrapf(app.ErrInvalidAmount, "amount %q is not an integer", rqBody.Amount)
+	}
+	controls, err := h.app.SetTransferAmount(ctx.Request().Context(), adminActor(ctx), amount)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, controlsResponse(controls))
+}
+
+// BlockedAddressResponse describes the blocked address.
+type BlockedAddressResponse struct {
+	Address   string    `json:"address"`
+	Reason    string    `json:"reason,omitempty"`
+	BlockedAt time.Time `json:"blockedAt"`
+}
+
+// BlockedAddressesResponse is the output to /admin/blocked-addresses request.
+type BlockedAddressesResponse struct {
+	BlockedAddresses []BlockedAddressResponse `json:"blockedAddresses"`
+}
+
+func (h HTTP) blockedAddressesHandle(ctx http.Context) error {
+	entries, err := h.app.BlockedAddresses(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+	resp := BlockedAddressesResponse{BlockedAddresses: []BlockedAddressResponse{}}
+	for _, e := range entries {
+		resp.BlockedAddresses = append(resp.BlockedAddresses, BlockedAddressResponse(e))
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
+
+// BlockAddressRequest is the input to PUT /admin/blocked-addresses/:address request.
+type BlockAddressRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (h HTTP) blockAddressHandle(ctx http.Context) error {
+	var rqBody BlockAddressRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+	entry, err := h.app.BlockAddress(ctx.Request().Context(), ctx.Param("address"), rqBody.Reason)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, BlockedAddressResponse(entry))
+}
+
+func (h HTTP) unblockAddressHandle(ctx http.Context) error {
+	if err := h.app.UnblockAddress(ctx.Request().Context(), ctx.Param("address")); err != nil {
+		return err
+	}
+	return ctx.NoContent(nethttp.StatusNoContent)
+}
+
+// AddressTotalResponse is the output to /admin/address-totals/:address request.
+type AddressTotalResponse struct {
+	Address     string      `json:"address"`
+	Amount      string      `json:"amount"`
+	Coins       chain.Coins `json:"coins"`
+	UpdatedAt   *time.Time  `json:"updatedAt,omitempty"`
+	LifetimeCap string      `json:"lifetimeCap,omitempty"`
+}
+
+func (h HTTP) addressTotalHandle(ctx http.Context) error {
+	address := ctx.Param("address")
+	total, err := h.app.AddressTotal(ctx.Request().Context(), address)
+	if err != nil {
+		return err
+	}
+	resp := AddressTotalResponse{
+		Address: address,
+		Amount:  total.Amount.AmountOf(h.app.NetworkInfo().Denom).String(),
+		Coins:   total.Amount,
+	}
+	if resp.Coins == nil {
+		resp.Coins = chain.NewCoins()
+	}
+	if !total.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &total.UpdatedAt
+	}
+	if limit := h.app.LifetimeCap(); limit.Amount.IsPositive() {
+		resp.LifetimeCap = limit.String()
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
+
+func (h HTTP) resetAddressTotalHandle(ctx http.Context) error {
+	if err := h.app.ResetAddressTotal(ctx.Request().Context(), ctx.Param("address")); err != nil {
+		return err
+	}
+	return ctx.NoContent(nethttp.StatusNoContent)
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// EmailVerificationRequest is the input to /email/verifications request.
+type EmailVerificationRequest struct {
+	Address string `json:"address"`
+	Email   string `json:"email"`
+}
+
+// EmailVerificationResponse is the output to /email/verifications request.
+type EmailVerificationResponse struct {
+	Address   string    `json:"address"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// EmailVerifyRequest is the input to /email/verify request, the token is taken from the link sent by email.
+// It is accepted as query parameter, so the link may point to the endpoint directly.
+type EmailVerifyRequest struct {
+	Token string `json:"token" query:"token"`
+}
+
+// EmailVerifyResponse is the output to /email/verify request.
+type EmailVerifyResponse struct {
+	Address string `json:"address"`
+	TxHash  string `json:"txHash"`
+}
+
+func (h HTTP) requestEmailVerificationHandle(ctx http.Context) error {
+	var rqBody EmailVerificationRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+
+	request, err := h.app.RequestEmailVerification(ctx.Request().Context(), rqBody.Address, rqBody.Email)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusAccepted, EmailVerificationResponse{
+		Address:   request.Address,
+		ExpiresAt: request.ExpiresAt,
+	})
+}
+
+func (h HTTP) verifyEmailHandle(ctx http.Context) error {
+	var rqBody EmailVerifyRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	funding, err := h.app.VerifyEmail(ctx.Request().Context(), requester, rqBody.Token)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, EmailVerifyResponse{
+		Address: funding.Address,
+		TxHash:  funding.TxHash,
+	})
+}
@@ -2,7 +2,11 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
 	nethttp "net/http"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
@@ -10,11 +14,36 @@ import (
 
 	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
 	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/failover"
+	"github.com/CoreumFoundation/faucet/oidc"
 	"github.com/CoreumFoundation/faucet/pkg/http"
+	"github.com/CoreumFoundation/faucet/scheduler"
 )
 
-// ErrRateLimitExhausted is returned when rate limit is exhausted for an IP address.
-var ErrRateLimitExhausted = errors.New("rate limit exhausted")
+// HeaderRetryAfter tells the client how many seconds to wait before retrying the request.
+const HeaderRetryAfter = "Retry-After"
+
+// Error types produced by http package.
+var (
+	// ErrRateLimitExhausted is returned when rate limit is exhausted for an IP address.
+	ErrRateLimitExhausted = errors.New("rate limit exhausted")
+	// ErrQuotaExhausted is returned when the quota of the API key is exhausted.
+	ErrQuotaExhausted = errors.New("quota exhausted")
+	// ErrInvalidQuery is returned when query parameters are invalid.
+	ErrInvalidQuery = errors.New("invalid query parameters")
+	// ErrInvalidRequest is returned when request body is invalid.
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrStandby is returned when funding is requested from the standby instance.
+	ErrStandby = errors.New("instance is standby")
+	// ErrUnauthorized is returned when the admin token is missing or invalid.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrIPDenied is returned when the IP address is denied by the IP lists.
+	ErrIPDenied = errors.New("IP address is not allowed")
+	// ErrCountryDenied is returned when the IP address is located in the country denied by the GeoIP restriction.
+	ErrCountryDenied = errors.New("requests from this country are not allowed")
+	// ErrIPReputation is returned when the IP address is flagged by the IP reputation, e.g. as Tor exit.
+	ErrIPReputation = errors.New("requests from Tor, VPN and datacenter networks are not allowed")
+)
 
 func writeErrorMiddleware() func(http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
@@ -26,10 +55,13 @@ func writeErrorMiddleware() func(http.HandlerFunc) http.HandlerFunc {
 				if errors.As(err, &echoError) {
 					return err
 				}
-				mappedError := mapError(err)
+				mappedError := mapSingleError(err)
 				if mappedError.Loggable() {
 					logger.Get(c.Request().Context()).Error("Error processing request", zap.Error(err))
 				}
+				if !mappedError.nextAvailableAt.IsZero() {
+					c.Response().Header().Set(HeaderRetryAfter, retryAfter(mappedError.nextAvailableAt))
+				}
 
 				return c.JSON(mappedError.Status(), mappedError)
 			}
@@ -58,6 +90,12 @@ type singleAPIError struct {
 	message  string
 	status   int
 	loggable bool
+	// nextAvailableAt is the time the request might be retried at, if known.
+	nextAvailableAt time.Time
+	// suggestion is the corrected value the client most probably meant, if known.
+	suggestion string
+	// denom is the denom the error is specific to, if any.
+	denom string
 }
 
 func newSingleAPIError(kind, message string, status int, loggable bool) singleAPIError {
@@ -83,8 +121,11 @@ func (err singleAPIError) Loggable() bool {
 
 func (err singleAPIError) MarshalJSON() ([]byte, error) {
 	type errEntity struct {
-		Message string `json:"message"`
-		Kind    string `json:"kind"`
+		Message         string     `json:"message"`
+		Kind            string     `json:"kind"`
+		NextAvailableAt *time.Time `json:"nextAvailableAt,omitempty"`
+		Suggestion      string     `json:"suggestion,omitempty"`
+		Denom           string     `json:"denom,omitempty"`
 	}
 	resp := struct {
 		Type    string      `json:"type"`
@@ -92,23 +133,135 @@ func (err singleAPIError) MarshalJSON() ([]byte, error) {
 	}{
 		Type: "errors",
 		Content: []errEntity{
-			{Message: err.message, Kind: err.kind},
+			{Message: err.message, Kind: err.kind, Suggestion: err.suggestion, Denom: err.denom},
 		},
 	}
+	if !err.nextAvailableAt.IsZero() {
+		resp.Content[0].NextAvailableAt = &err.nextAvailableAt
+	}
 
 	return json.Marshal(resp)
 }
 
+// retryAfter returns the value of Retry-After header in seconds, at least 1.
+func retryAfter(nextAvailableAt time.Time) string {
+	seconds := int64(math.Ceil(time.Until(nextAvailableAt).Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.FormatInt(seconds, 10)
+}
+
 func mapError(err error) APIError {
+	return mapSingleError(err)
+}
+
+func mapSingleError(err error) singleAPIError {
 	errList := map[error]singleAPIError{
-		app.ErrAddressPrefixUnsupported: newSingleAPIError("address.invalid", app.ErrAddressPrefixUnsupported.Error(), nethttp.StatusUnprocessableEntity, false),
-		app.ErrInvalidAddressFormat:     newSingleAPIError("address.invalid", app.ErrInvalidAddressFormat.Error(), nethttp.StatusUnprocessableEntity, false),
-		app.ErrUnableToTransferToken:    newSingleAPIError("server.internal_error", app.ErrUnableToTransferToken.Error(), nethttp.StatusInternalServerError, true),
-		ErrRateLimitExhausted:           newSingleAPIError("server.rate_limit", ErrRateLimitExhausted.Error(), nethttp.StatusTooManyRequests, false),
+		app.ErrAddressPrefixUnsupported:    newSingleAPIError("address.invalid", app.ErrAddressPrefixUnsupported.Error(), nethttp.StatusUnprocessableEntity, false),
+		app.ErrInvalidAddressFormat:        newSingleAPIError("address.invalid", app.ErrInvalidAddressFormat.Error(), nethttp.StatusUnprocessableEntity, false),
+		app.ErrUnableToTransferToken:       newSingleAPIError("server.internal_error", app.ErrUnableToTransferToken.Error(), nethttp.StatusInternalServerError, true),
+		app.ErrTxNotFound:                  newSingleAPIError("tx.not_found", app.ErrTxNotFound.Error(), nethttp.StatusNotFound, false),
+		app.ErrQueueFull:                   newSingleAPIError("server.queue_full", app.ErrQueueFull.Error(), nethttp.StatusServiceUnavailable, false),
+		app.ErrTransferAboveMaximum:        newSingleAPIError("server.transfer_above_maximum", app.ErrTransferAboveMaximum.Error(), nethttp.StatusInternalServerError, false),
+		app.ErrSigningUnavailable:          newSingleAPIError("server.signing_unavailable", app.ErrSigningUnavailable.Error(), nethttp.StatusServiceUnavailable, false),
+		app.ErrRecipientNotFound:           newSingleAPIError("recipient.not_found", app.ErrRecipientNotFound.Error(), nethttp.StatusNotFound, false),
+		app.ErrInvalidRecipientName:        newSingleAPIError("recipient.invalid", app.ErrInvalidRecipientName.Error(), nethttp.StatusBadRequest, false),
+		app.ErrClaimCodeNotFound:           newSingleAPIError("claim_code.not_found", app.ErrClaimCodeNotFound.Error(), nethttp.StatusNotFound, false),
+		app.ErrClaimCodeUsedUp:             newSingleAPIError("claim_code.used_up", app.ErrClaimCodeUsedUp.Error(), nethttp.StatusConflict, false),
+		app.ErrClaimCodeAddressMismatch:    newSingleAPIError("claim_code.address_mismatch", app.ErrClaimCodeAddressMismatch.Error(), nethttp.StatusForbidden, false),
+		app.ErrInvalidClaimCode:            newSingleAPIError("claim_code.invalid", app.ErrInvalidClaimCode.Error(), nethttp.StatusBadRequest, false),
+		app.ErrClaimCodeExpired:            newSingleAPIError("claim_code.expired", app.ErrClaimCodeExpired.Error(), nethttp.StatusGone, false),
+		app.ErrChallengeNotFound:           newSingleAPIError("challenge.not_found", app.ErrChallengeNotFound.Error(), nethttp.StatusNotFound, false),
+		app.ErrChallengeTxNotFound:         newSingleAPIError("challenge.tx_not_found", app.ErrChallengeTxNotFound.Error(), nethttp.StatusConflict, false),
+		app.ErrChallengeFailed:             newSingleAPIError("challenge.failed", app.ErrChallengeFailed.Error(), nethttp.StatusForbidden, false),
+		app.ErrInvalidCallbackURL:          newSingleAPIError("callback.invalid_url", app.ErrInvalidCallbackURL.Error(), nethttp.StatusBadRequest, false),
+		app.ErrFundingPaused:               newSingleAPIError("server.paused", app.ErrFundingPaused.Error(), nethttp.StatusServiceUnavailable, false),
+		app.ErrInvalidAmount:               newSingleAPIError("amount.invalid", app.ErrInvalidAmount.Error(), nethttp.StatusBadRequest, false),
+		app.ErrAddressBlocked:              newSingleAPIError("address.blocked", app.ErrAddressBlocked.Error(), nethttp.StatusForbidden, false),
+		app.ErrAddressNotBlocked:           newSingleAPIError("address.not_blocked", app.ErrAddressNotBlocked.Error(), nethttp.StatusNotFound, false),
+		app.ErrInvalidExpiry:               newSingleAPIError("expiry.invalid", app.ErrInvalidExpiry.Error(), nethttp.StatusBadRequest, false),
+		app.ErrAddressCooldown:             newSingleAPIError("address.cooldown", app.ErrAddressCooldown.Error(), nethttp.StatusTooManyRequests, false),
+		app.ErrBudgetExceeded:              newSingleAPIError("server.budget_exceeded", app.ErrBudgetExceeded.Error(), nethttp.StatusServiceUnavailable, false),
+		app.ErrLifetimeCapReached:          newSingleAPIError("address.lifetime_cap", app.ErrLifetimeCapReached.Error(), nethttp.StatusForbidden, false),
+		app.ErrBalanceAboveThreshold:       newSingleAPIError("address.balance_threshold", app.ErrBalanceAboveThreshold.Error(), nethttp.StatusForbidden, false),
+		app.ErrAbuseSuspected:              newSingleAPIError("abuse.denied", app.ErrAbuseSuspected.Error(), nethttp.StatusForbidden, false),
+		app.ErrToSNotAccepted:              newSingleAPIError("tos.not_accepted", app.ErrToSNotAccepted.Error(), nethttp.StatusForbidden, false),
+		app.ErrToSTokenInvalid:             newSingleAPIError("tos.invalid_token", app.ErrToSTokenInvalid.Error(), nethttp.StatusForbidden, false),
+		app.ErrToSVersionMismatch:          newSingleAPIError("tos.version_mismatch", app.ErrToSVersionMismatch.Error(), nethttp.StatusConflict, false),
+		app.ErrToSNotRequired:              newSingleAPIError("tos.not_required", app.ErrToSNotRequired.Error(), nethttp.StatusNotFound, false),
+		app.ErrBypassTokenInvalid:          newSingleAPIError("bypass_token.invalid", app.ErrBypassTokenInvalid.Error(), nethttp.StatusUnauthorized, false),
+		app.ErrBypassTokenNotFound:         newSingleAPIError("bypass_token.not_found", app.ErrBypassTokenNotFound.Error(), nethttp.StatusNotFound, false),
+		app.ErrBypassTokenQuotaExhausted:   newSingleAPIError("bypass_token.quota_exhausted", app.ErrBypassTokenQuotaExhausted.Error(), nethttp.StatusTooManyRequests, false),
+		app.ErrInvalidBypassTokenSpec:      newSingleAPIError("bypass_token.invalid_spec", app.ErrInvalidBypassTokenSpec.Error(), nethttp.StatusBadRequest, false),
+		app.ErrNamedAccountsDisabled:       newSingleAPIError("account.named_disabled", app.ErrNamedAccountsDisabled.Error(), nethttp.StatusNotFound, false),
+		app.ErrNamedAccountNotFound:        newSingleAPIError("account.not_found", app.ErrNamedAccountNotFound.Error(), nethttp.StatusNotFound, false),
+		app.ErrInvalidAccountLabel:         newSingleAPIError("account.invalid_label", app.ErrInvalidAccountLabel.Error(), nethttp.StatusBadRequest, false),
+		app.ErrAccountLabelUnauthorized:    newSingleAPIError("auth.unauthorized", app.ErrAccountLabelUnauthorized.Error(), nethttp.StatusUnauthorized, false),
+		app.ErrInvalidConfirmations:        newSingleAPIError("confirmations.invalid", app.ErrInvalidConfirmations.Error(), nethttp.StatusBadRequest, false),
+		app.ErrCaptchaFailed:               newSingleAPIError("captcha.failed", app.ErrCaptchaFailed.Error(), nethttp.StatusForbidden, false),
+		app.ErrCaptchaUnavailable:          newSingleAPIError("captcha.unavailable", app.ErrCaptchaUnavailable.Error(), nethttp.StatusServiceUnavailable, true),
+		app.ErrTenantNotificationsNotFound: newSingleAPIError("notifications.not_found", app.ErrTenantNotificationsNotFound.Error(), nethttp.StatusNotFound, false),
+		app.ErrInvalidTenantNotifications:  newSingleAPIError("notifications.invalid", app.ErrInvalidTenantNotifications.Error(), nethttp.StatusBadRequest, false),
+		app.ErrQRLinkUnavailable:           newSingleAPIError("qr.link_unavailable", app.ErrQRLinkUnavailable.Error(), nethttp.StatusNotFound, false),
+		app.ErrPoWFailed:                   newSingleAPIError("pow.failed", app.ErrPoWFailed.Error(), nethttp.StatusForbidden, false),
+		app.ErrOwnershipProofFailed:        newSingleAPIError("ownership.failed", app.ErrOwnershipProofFailed.Error(), nethttp.StatusForbidden, false),
+		app.ErrIdentityFailed:              newSingleAPIError("identity.failed", app.ErrIdentityFailed.Error(), nethttp.StatusForbidden, false),
+		app.ErrIdentityRejected:            newSingleAPIError("identity.rejected", app.ErrIdentityRejected.Error(), nethttp.StatusForbidden, false),
+		app.ErrIdentityUnavailable:         newSingleAPIError("identity.unavailable", app.ErrIdentityUnavailable.Error(), nethttp.StatusServiceUnavailable, true),
+		app.ErrIdentityProviderNotFound:    newSingleAPIError("identity.provider_not_found", app.ErrIdentityProviderNotFound.Error(), nethttp.StatusNotFound, false),
+		app.ErrIdentityQuotaExhausted:      newSingleAPIError("identity.quota_exhausted", app.ErrIdentityQuotaExhausted.Error(), nethttp.StatusTooManyRequests, false),
+		app.ErrRefillProposalNotFound:      newSingleAPIError("refill.not_found", app.ErrRefillProposalNotFound.Error(), nethttp.StatusNotFound, false),
+		app.ErrRefillSignatureInvalid:      newSingleAPIError("refill.invalid_signature", app.ErrRefillSignatureInvalid.Error(), nethttp.StatusBadRequest, false),
+		app.ErrInvalidRefill:               newSingleAPIError("refill.invalid", app.ErrInvalidRefill.Error(), nethttp.StatusConflict, false),
+		app.ErrInvalidInvoiceID:            newSingleAPIError("invoice.invalid_id", app.ErrInvalidInvoiceID.Error(), nethttp.StatusBadRequest, false),
+		app.ErrInvoiceIDUnauthorized:       newSingleAPIError("auth.unauthorized", app.ErrInvoiceIDUnauthorized.Error(), nethttp.StatusUnauthorized, false),
+		ErrRateLimitExhausted:              newSingleAPIError("server.rate_limit", ErrRateLimitExhausted.Error(), nethttp.StatusTooManyRequests, false),
+		ErrQuotaExhausted:                  newSingleAPIError("server.quota_exhausted", ErrQuotaExhausted.Error(), nethttp.StatusTooManyRequests, false),
+		ErrInvalidQuery:                    newSingleAPIError("request.invalid", ErrInvalidQuery.Error(), nethttp.StatusBadRequest, false),
+		ErrInvalidRequest:                  newSingleAPIError("request.invalid", ErrInvalidRequest.Error(), nethttp.StatusBadRequest, false),
+		ErrUnauthorized:                    newSingleAPIError("auth.unauthorized", ErrUnauthorized.Error(), nethttp.StatusUnauthorized, false),
+		ErrIPDenied:                        newSingleAPIError("ip.denied", ErrIPDenied.Error(), nethttp.StatusForbidden, false),
+		ErrCountryDenied:                   newSingleAPIError("geo.denied", ErrCountryDenied.Error(), nethttp.StatusForbidden, false),
+		ErrIPReputation:                    newSingleAPIError("ip.reputation", ErrIPReputation.Error(), nethttp.StatusForbidden, false),
+		ErrStandby:                         newSingleAPIError("server.standby", ErrStandby.Error(), nethttp.StatusServiceUnavailable, false),
+		failover.ErrNotReady:               newSingleAPIError("failover.not_ready", failover.ErrNotReady.Error(), nethttp.StatusConflict, false),
+		app.ErrEmailVerificationFailed:     newSingleAPIError("email.verification_failed", app.ErrEmailVerificationFailed.Error(), nethttp.StatusForbidden, false),
+		app.ErrInvalidEmail:                newSingleAPIError("email.invalid", app.ErrInvalidEmail.Error(), nethttp.StatusBadRequest, false),
+		app.ErrEmailUnavailable:            newSingleAPIError("email.unavailable", app.ErrEmailUnavailable.Error(), nethttp.StatusServiceUnavailable, true),
+		app.ErrPhoneVerificationFailed:     newSingleAPIError("phone.verification_failed", app.ErrPhoneVerificationFailed.Error(), nethttp.StatusForbidden, false),
+		app.ErrInvalidPhone:                newSingleAPIError("phone.invalid", app.ErrInvalidPhone.Error(), nethttp.StatusBadRequest, false),
+		app.ErrSMSUnavailable:              newSingleAPIError("phone.unavailable", app.ErrSMSUnavailable.Error(), nethttp.StatusServiceUnavailable, true),
+		app.ErrInvalidFundingLink:          newSingleAPIError("funding_link.invalid", app.ErrInvalidFundingLink.Error(), nethttp.StatusBadRequest, false),
+		app.ErrFundingLinkRejected:         newSingleAPIError("funding_link.rejected", app.ErrFundingLinkRejected.Error(), nethttp.StatusForbidden, false),
+		app.ErrFundingLinkExpired:          newSingleAPIError("funding_link.expired", app.ErrFundingLinkExpired.Error(), nethttp.StatusGone, false),
+		app.ErrFundingLinkUsed:             newSingleAPIError("funding_link.used", app.ErrFundingLinkUsed.Error(), nethttp.StatusConflict, false),
+		oidc.ErrInvalidToken:               newSingleAPIError("oidc.invalid_token", oidc.ErrInvalidToken.Error(), nethttp.StatusUnauthorized, false),
+		oidc.ErrUnavailable:                newSingleAPIError("oidc.unavailable", oidc.ErrUnavailable.Error(), nethttp.StatusServiceUnavailable, true),
+		scheduler.ErrJobNotFound:           newSingleAPIError("job.not_found", scheduler.ErrJobNotFound.Error(), nethttp.StatusNotFound, false),
+	}
+
+	var decodeErr http.DecodeError
+	if errors.As(err, &decodeErr) {
+		return newSingleAPIError("request.invalid", decodeErr.Error(), nethttp.StatusBadRequest, false)
 	}
 
 	for e, internalErr := range errList {
 		if errors.Is(err, e) {
+			var throttled app.ThrottledError
+			if errors.As(err, &throttled) {
+				internalErr.nextAvailableAt = throttled.NextAvailableAt
+			}
+			var suggested app.AddressSuggestionError
+			if errors.As(err, &suggested) {
+				internalErr.message = fmt.Sprintf("%s, did you mean %s?", internalErr.message, suggested.Suggestion)
+				internalErr.suggestion = suggested.Suggestion
+			}
+			var exceeded app.BudgetExceededError
+			if errors.As(err, &exceeded) {
+				internalErr.message = fmt.Sprintf("%s for %s", internalErr.message, exceeded.Denom)
+				internalErr.denom = exceeded.Denom
+			}
 			return internalErr
 		}
 	}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// Outcomes of the funding requests counted by the experiment metrics.
+const (
+	experimentOutcomeSuccess   = "success"
+	experimentOutcomeThrottled = "throttled"
+	experimentOutcomeError     = "error"
+)
+
+// experimentMiddleware counts the outcomes of the funding requests by the experiment group of the requester, so
+// the impact of the experiment variant may be compared with the control group.
+func (h HTTP) experimentMiddleware() func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(c http.Context) error {
+			requester, err := requesterFromContext(c)
+			if err != nil {
+				return err
+			}
+
+			err = next(c)
+
+			outcome := experimentOutcomeSuccess
+			var throttled app.ThrottledError
+			switch {
+			case errors.As(err, &throttled):
+				outcome = experimentOutcomeThrottled
+			case err != nil:
+				outcome = experimentOutcomeError
+			}
+			h.metrics.experimentRequests.WithLabelValues(
+				h.app.Experiment().Name,
+				h.app.ExperimentVariant(requester),
+				outcome,
+			).Inc()
+			return err
+		}
+	}
+}
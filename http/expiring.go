@@ -0,0 +1,67 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+const defaultExpiringWithin = 24 * time.Hour
+
+// ExpiringItemResponse describes the artifact collected once it expires.
+type ExpiringItemResponse struct {
+	Kind      string    `json:"kind"`
+	ID        string    `json:"id"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ExpiringItemsResponse is the output to /admin/expiring request.
+type ExpiringItemsResponse struct {
+	Items []ExpiringItemResponse `json:"items"`
+}
+
+func (h HTTP) expiringItemsHandle(ctx http.Context) error {
+	within := defaultExpiringWithin
+	if w := ctx.QueryParam("within"); w != "" {
+		var err error
+		within, err = time.ParseDuration(w)
+		if err != nil || within < 0 {
+			return errors.Wrapf(ErrInvalidQuery, "invalid within: %q", w)
+		}
+	}
+
+	items, err := h.app.ExpiringItems(ctx.Request().Context(), within)
+	if err != nil {
+		return err
+	}
+	resp := ExpiringItemsResponse{Items: make([]ExpiringItemResponse, 0, len(items))}
+	for _, item := range items {
+		resp.Items = append(resp.Items, expiringItemResponse(item))
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
+
+// ExtendExpiryRequest is the input to PUT /admin/expiring/:kind/:id request.
+type ExtendExpiryRequest struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func (h HTTP) extendExpiryHandle(ctx http.Context) error {
+	var rqBody ExtendExpiryRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+	item, err := h.app.ExtendExpiry(ctx.Request().Context(), ctx.Param("kind"), ctx.Param("id"), rqBody.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, expiringItemResponse(item))
+}
+
+func expiringItemResponse(item app.ExpiringItem) ExpiringItemResponse {
+	return ExpiringItemResponse{Kind: item.Kind, ID: item.ID, ExpiresAt: item.ExpiresAt}
+}
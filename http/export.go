@@ -0,0 +1,87 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/indexer"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+	"github.com/CoreumFoundation/faucet/pkg/pagination"
+)
+
+const (
+	defaultExportPeriod  = 24 * time.Hour
+	defaultHistoryPeriod = 24 * time.Hour
+)
+
+// indexerExportHandle streams the fundings in the format ingested by chain indexers. The fundings since the time
+// given by `since` are exported, or over the `period` if it is not set, so the indexer may export incrementally.
+func (h HTTP) indexerExportHandle(ctx http.Context) error {
+	format := indexer.FormatJSONL
+	if f := ctx.QueryParam("format"); f != "" {
+		var err error
+		if format, err = indexer.ParseFormat(f); err != nil {
+			return errors.Wrap(ErrInvalidQuery, err.Error())
+		}
+	}
+
+	since, err := sinceFromQuery(ctx, h.app.Now(), defaultExportPeriod)
+	if err != nil {
+		return err
+	}
+
+	records, err := h.app.FundingsSince(ctx.Request().Context(), since)
+	if err != nil {
+		return err
+	}
+
+	resp := ctx.Response()
+	resp.Header().Set(echo.HeaderContentType, format.ContentType())
+	resp.Header().Set(echo.HeaderContentDisposition, `attachment; filename="faucet-fundings.`+string(format)+`"`)
+	resp.WriteHeader(nethttp.StatusOK)
+	return indexer.Write(resp, format, h.app.NetworkInfo().ChainID, records)
+}
+
+// HistoryResponse is the output to /admin/history request.
+type HistoryResponse struct {
+	Fundings   []app.FundingRecord `json:"fundings"`
+	NextCursor string              `json:"nextCursor,omitempty"`
+}
+
+// historySpec lists the fundings, the latest first by default.
+var historySpec = pagination.Spec[app.FundingRecord]{
+	ID: func(r app.FundingRecord) string { return r.RequestID },
+	Sorts: map[string]func(r app.FundingRecord) string{
+		"time":    func(r app.FundingRecord) string { return pagination.TimeKey(r.Time) },
+		"address": func(r app.FundingRecord) string { return r.Address },
+	},
+	DefaultSort: "time",
+	DefaultDesc: true,
+	Filters: map[string]func(r app.FundingRecord, value string) bool{
+		"address":   func(r app.FundingRecord, value string) bool { return r.Address == value },
+		"ip":        func(r app.FundingRecord, value string) bool { return r.IP == value },
+		"txHash":    func(r app.FundingRecord, value string) bool { return r.TxHash == value },
+		"invoiceId": func(r app.FundingRecord, value string) bool { return r.InvoiceID == value },
+	},
+}
+
+// historyHandle lists the fundings since the time given by `since`, or over the `period` if it is not set.
+func (h HTTP) historyHandle(ctx http.Context) error {
+	since, err := sinceFromQuery(ctx, h.app.Now(), defaultHistoryPeriod)
+	if err != nil {
+		return err
+	}
+	records, err := h.app.FundingsSince(ctx.Request().Context(), since)
+	if err != nil {
+		return err
+	}
+	page, err := listPage(ctx, historySpec, records)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, HistoryResponse{Fundings: page.Items, NextCursor: page.NextCursor})
+}
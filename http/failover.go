@@ -0,0 +1,63 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/failover"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// activeMiddleware rejects funding requests if the instance is not the active one.
+func activeMiddleware(coordinator *failover.Coordinator) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(c http.Context) error {
+			if coordinator != nil && !coordinator.IsActive() {
+				return errors.Wrap(ErrStandby, "funding is handled by the active instance")
+			}
+			return next(c)
+		}
+	}
+}
+
+// FailoverLeaseResponse describes the lease held by the active instance.
+type FailoverLeaseResponse struct {
+	Holder    string    `json:"holder"`
+	Epoch     uint64    `json:"epoch"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// FailoverStatusResponse is the output to /admin/failover requests.
+type FailoverStatusResponse struct {
+	InstanceID  string                `json:"instanceId"`
+	Role        string                `json:"role"`
+	Lease       FailoverLeaseResponse `json:"lease"`
+	ActiveFrom  time.Time             `json:"activeFrom"`
+	ActiveUntil time.Time             `json:"activeUntil"`
+	Checks      map[string]string     `json:"checks"`
+}
+
+func (h HTTP) failoverStatusHandle(ctx http.Context) error {
+	return ctx.JSON(nethttp.StatusOK, failoverStatusResponse(h.cfg.Failover.Status()))
+}
+
+func (h HTTP) failoverPromoteHandle(ctx http.Context) error {
+	status, err := h.cfg.Failover.Promote(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, failoverStatusResponse(status))
+}
+
+func failoverStatusResponse(status failover.Status) FailoverStatusResponse {
+	return FailoverStatusResponse{
+		InstanceID:  status.InstanceID,
+		Role:        string(status.Role),
+		Lease:       FailoverLeaseResponse(status.Lease),
+		ActiveFrom:  status.ActiveFrom,
+		ActiveUntil: status.ActiveUntil,
+		Checks:      status.Checks,
+	}
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/http/pb"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+const (
+	// mimeApplicationNDJSON is the content type of the streamed response, each line containing one JSON object.
+	mimeApplicationNDJSON = "application/x-ndjson"
+	maxFundManyAddresses  = 1000
+)
+
+// FundManyRequest is the input to /admin/fund-many request.
+type FundManyRequest struct {
+	Addresses []string `json:"addresses"`
+}
+
+// ItemErrorResponse describes the failure of a single item of the batch.
+type ItemErrorResponse struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// FundingResultResponse is the result of funding a single address of the batch.
+type FundingResultResponse struct {
+	Index   int                `json:"index"`
+	Address string             `json:"address"`
+	TxHash  string             `json:"txHash,omitempty"`
+	Error   *ItemErrorResponse `json:"error,omitempty"`
+}
+
+// FundManyResponse is the output to /admin/fund-many request if streaming is not requested.
+type FundManyResponse struct {
+	Results []FundingResultResponse `json:"results"`
+}
+
+// fundManyHandle funds many addresses at once. If the client accepts application/x-ndjson, the result of each
+// funding is streamed as soon as it completes, otherwise all the results are returned at the end ordered by index.
+func (h HTTP) fundManyHandle(ctx http.Context) error {
+	var rqBody FundManyRequest
+	if http.IsProtobufRequest(ctx) {
+		var pbBody pb.FundManyRequest
+		if err := http.BindProtobuf(ctx, &pbBody); err != nil {
+			return err
+		}
+		rqBody.Addresses = pbBody.Addresses
+	} else if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+	if len(rqBody.Addresses) == 0 || len(rqBody.Addresses) > maxFundManyAddresses {
+		return errors.Wrapf(ErrInvalidRequest, "number of addresses must be between 1 and %d", maxFundManyAddresses)
+	}
+
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	// the endpoint is served behind the admin token only
+	requester.Admin = true
+
+	reqCtx := ctx.Request().Context()
+	results := h.app.GiveFundsMany(reqCtx, requester, rqBody.Addresses)
+
+	if !strings.Contains(ctx.Request().Header.Get(echo.HeaderAccept), mimeApplicationNDJSON) {
+		resp := FundManyResponse{Results: make([]FundingResultResponse, 0, len(rqBody.Addresses))}
+		for result := range results {
+			resp.Results = append(resp.Results, fundingResultResponse(ctx, result))
+		}
+		sort.Slice(resp.Results, func(i, j int) bool {
+			return resp.Results[i].Index < resp.Results[j].Index
+		})
+		if http.AcceptsProtobuf(ctx) {
+			return http.Protobuf(ctx, nethttp.StatusOK, resp.toProto())
+		}
+		return ctx.JSON(nethttp.StatusOK, resp)
+	}
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, mimeApplicationNDJSON)
+	res.WriteHeader(nethttp.StatusOK)
+	encoder := json.NewEncoder(res)
+	for result := range results {
+		if err := encoder.Encode(fundingResultResponse(ctx, result)); err != nil {
+			// the client is gone, the remaining fundings are completed anyway
+			logger.Get(reqCtx).Warn("Streaming funding result failed", zap.Error(err))
+			continue
+		}
+		res.Flush()
+	}
+	return nil
+}
+
+func (r FundManyResponse) toProto() *pb.FundManyResponse {
+	resp := &pb.FundManyResponse{}
+	for _, result := range r.Results {
+		item := &pb.FundingResult{
+			Index:   int32(result.Index),
+			Address: result.Address,
+			TxHash:  result.TxHash,
+		}
+		if result.Error != nil {
+			item.Error = &pb.ItemError{Kind: result.Error.Kind, Message: result.Error.Message}
+		}
+		resp.Results = append(resp.Results, item)
+	}
+	return resp
+}
+
+func fundingResultResponse(ctx http.Context, result app.FundingResult) FundingResultResponse {
+	resp := FundingResultResponse{
+		Index:   result.Index,
+		Address: result.Address,
+		TxHash:  result.TxHash,
+	}
+	if result.Err != nil {
+		mappedError := mapSingleError(result.Err)
+		if mappedError.Loggable() {
+			logger.Get(ctx.Request().Context()).Error("Error funding address",
+				zap.String("address", result.Address), zap.Error(result.Err))
+		}
+		resp.Error = &ItemErrorResponse{Kind: mappedError.kind, Message: mappedError.message}
+	}
+	return resp
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func sendFundMany(handler nethttp.Handler, accept, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(nethttp.MethodPost, "/api/faucet/v1/admin/fund-many", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+contractAdminToken)
+	if accept != "" {
+		req.Header.Set(echo.HeaderAccept, accept)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestFundManyStreaming(t *testing.T) {
+	requireT := require.New(t)
+
+	handler, _ := newContractServer(t)
+	rec := sendFundMany(handler, mimeApplicationNDJSON,
+		`{"addresses":["`+contractAddress+`","devcore1invalid"]}`)
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+	requireT.Equal(mimeApplicationNDJSON, rec.Header().Get(echo.HeaderContentType))
+	requireT.True(rec.Flushed)
+
+	var results []FundingResultResponse
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var result FundingResultResponse
+		requireT.NoError(json.Unmarshal(scanner.Bytes(), &result), scanner.Text())
+		results = append(results, result)
+	}
+	requireT.Len(results, 2)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Index < results[j].Index
+	})
+	requireT.Equal(contractAddress, results[0].Address)
+	requireT.Equal(contractTxHash, results[0].TxHash)
+	requireT.Nil(results[0].Error)
+	requireT.Equal("devcore1invalid", results[1].Address)
+	requireT.NotNil(results[1].Error)
+	requireT.Equal("address.invalid", results[1].Error.Kind)
+}
+
+func TestFundMany(t *testing.T) {
+	requireT := require.New(t)
+
+	handler, _ := newContractServer(t)
+	rec := sendFundMany(handler, "", `{"addresses":[]}`)
+	requireT.Equal(nethttp.StatusBadRequest, rec.Code)
+
+	req := httptest.NewRequest(nethttp.MethodPost, "/api/faucet/v1/admin/fund-many",
+		strings.NewReader(`{"addresses":["`+contractAddress+`"]}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	requireT.Equal(nethttp.StatusUnauthorized, rec.Code)
+
+	rec = sendFundMany(handler, "", `{"addresses":["devcore1invalid","`+contractAddress+`"]}`)
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+	requireT.Contains(rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+	var resp FundManyResponse
+	requireT.NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	requireT.Len(resp.Results, 2)
+	// results are ordered by index
+	requireT.Equal(0, resp.Results[0].Index)
+	requireT.Equal("devcore1invalid", resp.Results[0].Address)
+	requireT.NotNil(resp.Results[0].Error)
+	requireT.Equal("address.invalid", resp.Results[0].Error.Kind)
+	requireT.Equal(1, resp.Results[1].Index)
+	requireT.Equal(contractTxHash, resp.Results[1].TxHash)
+	requireT.Nil(resp.Results[1].Error)
+}
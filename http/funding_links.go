@@ -0,0 +1,110 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// CreateFundingLinksRequest is the input to POST /admin/funding-links request.
+type CreateFundingLinksRequest struct {
+	// Amount is the amount sent by each link in the smallest unit, e.g. "1000000".
+	Amount string `json:"amount"`
+	// Denom of the amount, the denom of the chain if empty.
+	Denom string `json:"denom"`
+	// Count is the number of links to mint, 1 by default.
+	Count int `json:"count"`
+	// ExpiresAt is the time the links can't be redeemed anymore.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// FundingLinkResponse describes the minted funding link.
+type FundingLinkResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Token     string    `json:"token"`
+	Amount    string    `json:"amount"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// CreateFundingLinksResponse is the output to POST /admin/funding-links request.
+type CreateFundingLinksResponse struct {
+	FundingLinks []FundingLinkResponse `json:"fundingLinks"`
+}
+
+// RedeemFundingLinkRequest is the input to /funding-links/redeem request, the token is taken from the link.
+type RedeemFundingLinkRequest struct {
+	Token   string `json:"token"`
+	Address string `json:"address"`
+}
+
+// RedeemFundingLinkResponse is the output to /funding-links/redeem request.
+type RedeemFundingLinkResponse struct {
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
+	TxHash  string `json:"txHash"`
+	// Coin is the amount enriched with the display units.
+	Coin CoinResponse `json:"coin"`
+}
+
+func (h HTTP) createFundingLinksHandle(ctx http.Context) error {
+	var rqBody CreateFundingLinksRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+	amount, ok := chain.NewIntFromString(rqBody.Amount)
+	if !ok {
+		return errors.Wrapf(ErrInvalidRequest, "invalid amount: %q", rqBody.Amount)
+	}
+	if rqBody.Count == 0 {
+		rqBody.Count = 1
+	}
+
+	links, err := h.app.CreateFundingLinks(ctx.Request().Context(), app.FundingLinkSpec{
+		Amount:    amount,
+		Denom:     rqBody.Denom,
+		ExpiresAt: rqBody.ExpiresAt,
+	}, rqBody.Count)
+	if err != nil {
+		return err
+	}
+	resp := CreateFundingLinksResponse{FundingLinks: make([]FundingLinkResponse, 0, len(links))}
+	for _, link := range links {
+		resp.FundingLinks = append(resp.FundingLinks, FundingLinkResponse{
+			ID:        link.ID,
+			URL:       link.URL,
+			Token:     link.Token,
+			Amount:    link.Amount.String(),
+			ExpiresAt: link.ExpiresAt,
+		})
+	}
+	return ctx.JSON(nethttp.StatusCreated, resp)
+}
+
+func (h HTTP) redeemFundingLinkHandle(ctx http.Context) error {
+	var rqBody RedeemFundingLinkRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.app.RedeemFundingLink(ctx.Request().Context(), requester, rqBody.Token, rqBody.Address)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, RedeemFundingLinkResponse{
+		Address: result.Address,
+		Amount:  result.Amount.String(),
+		TxHash:  result.TxHash,
+		Coin:    h.coinResponse(ctx.Request().Context(), result.Amount),
+	})
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/pkg/geoip"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// geoIPMiddleware refuses the funding requests of the IPs located in the countries denied by the restriction. It runs
+// before the handler, so refused requests never reach the chain. Requests from private ranges are not located
+// anywhere, so they are accepted, same as the ones authenticated by the API key or the admin token.
+func (h HTTP) geoIPMiddleware(restriction *geoip.Restriction) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(c http.Context) error {
+			if restriction == nil {
+				return next(c)
+			}
+			if _, ok := apiKeyHolder(c); ok || h.adminAuthorized(c) {
+				return next(c)
+			}
+			ip, err := http.IPFromRequest(c.Request())
+			if err != nil {
+				return err
+			}
+			if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+				return next(c)
+			}
+			allowed, country, err := restriction.Allowed(ip)
+			if err != nil {
+				return err
+			}
+			if !allowed {
+				err := errors.Wrapf(ErrCountryDenied, "ip %q located in %q is not allowed", ip.String(), country)
+				if requester, rErr := requesterFromContext(c); rErr == nil {
+					h.app.ReportBlocked(c.Request().Context(), requester, "", err)
+				}
+				return err
+			}
+			return next(c)
+		}
+	}
+}
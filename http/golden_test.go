@@ -0,0 +1,133 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// updateGolden rewrites the golden files with the actual responses: go test ./http -update.
+var updateGolden = flag.Bool("update", false, "update golden files of HTTP API contract tests")
+
+// volatileFields are replaced in all the responses, because they differ between runs.
+var volatileFields = []string{"go", "since", "nextAvailableAt"}
+
+// goldenCase is the request whose response must match the golden file testdata/golden/<name>.golden.
+type goldenCase struct {
+	name    string
+	method  string
+	path    string
+	body    string
+	headers map[string]string
+	// remoteIP is the IP the request comes from, public IP subject to the rate limit by default.
+	remoteIP string
+	// volatile are the additional fields to replace, e.g. the generated mnemonic.
+	volatile []string
+}
+
+// goldenHeaders are the response headers being part of the contract.
+var goldenHeaders = []string{"Content-Type", "Retry-After"}
+
+// assertGolden sends the request to the handler and compares the response with the golden file.
+func assertGolden(t *testing.T, handler nethttp.Handler, tc goldenCase) {
+	t.Helper()
+
+	req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+	req.RemoteAddr = "203.0.113.1:1234"
+	if tc.remoteIP != "" {
+		req.RemoteAddr = tc.remoteIP + ":1234"
+	}
+	req.Header.Set("X-Request-Id", "rq-"+tc.name)
+	if tc.body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range tc.headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	actual := formatGolden(t, rec, append(append([]string{}, volatileFields...), tc.volatile...))
+	path := filepath.Join("testdata", "golden", tc.name+".golden")
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(path, actual, 0o600))
+		return
+	}
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err, "golden file is missing, run go test ./http -update to create it")
+	require.Equal(t, string(expected), string(actual), "response doesn't match %s", path)
+}
+
+// formatGolden renders the status, contract headers and the body with sorted keys and volatile fields replaced.
+func formatGolden(t *testing.T, rec *httptest.ResponseRecorder, volatile []string) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "%s %d\n", rec.Result().Proto, rec.Code)
+	for _, name := range goldenHeaders {
+		value := rec.Header().Get(name)
+		if value == "" {
+			continue
+		}
+		if name == "Retry-After" {
+			value = "<volatile>"
+		}
+		fmt.Fprintf(buf, "%s: %s\n", name, value)
+	}
+	buf.WriteString("\n")
+
+	body := rec.Body.Bytes()
+	var decoded interface{}
+	if len(body) == 0 || json.Unmarshal(body, &decoded) != nil {
+		buf.Write(body)
+		return buf.Bytes()
+	}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	require.NoError(t, encoder.Encode(scrub(decoded, volatile)))
+	return buf.Bytes()
+}
+
+func scrub(value interface{}, volatile []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if contains(volatile, k) && v[k] != "" && v[k] != nil {
+				v[k] = "<volatile>"
+				continue
+			}
+			v[k] = scrub(v[k], volatile)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = scrub(v[i], volatile)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func contains(list []string, item string) bool {
+	for _, i := range list {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -2,44 +2,366 @@ package http
 
 import (
 	"context"
+	"encoding/base64"
 	nethttp "net/http"
+	"net/http/pprof"
+	"net/url"
 	"runtime"
 	"time"
 
+	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
+	"github.com/CoreumFoundation/coreum-tools/pkg/parallel"
 	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/failover"
+	"github.com/CoreumFoundation/faucet/http/pb"
+	"github.com/CoreumFoundation/faucet/oidc"
+	"github.com/CoreumFoundation/faucet/pkg/attribution"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+	"github.com/CoreumFoundation/faucet/pkg/config"
+	"github.com/CoreumFoundation/faucet/pkg/geoip"
 	"github.com/CoreumFoundation/faucet/pkg/http"
+	"github.com/CoreumFoundation/faucet/pkg/iplist"
+	"github.com/CoreumFoundation/faucet/pkg/iprep"
+	"github.com/CoreumFoundation/faucet/pkg/lambda"
 	"github.com/CoreumFoundation/faucet/pkg/limiter"
+	"github.com/CoreumFoundation/faucet/scheduler"
 )
 
+// HeaderXFaucetSession assigns the request of the authenticated tenant to its session for budget accounting.
+const HeaderXFaucetSession = "X-Faucet-Session"
+
+// HeaderXFaucetActor names the operator calling the admin API, it is recorded with the configuration changes.
+const HeaderXFaucetActor = "X-Faucet-Actor"
+
+// cacheMaxAge is how long clients may cache responses of the cacheable endpoints.
+const cacheMaxAge = 30 * time.Second
+
+// Config stores the configuration of the HTTP type.
+type Config struct {
+	// AdminToken is the bearer token required to access admin API. Admin API is enabled only if it is set.
+	AdminToken string
+	// StrictJSON enables rejection of JSON request bodies containing unknown fields.
+	StrictJSON bool
+	// TrustedProxies are the proxies whose forwarded headers are honored when the IP of the client is extracted.
+	TrustedProxies http.TrustedProxies
+	// FastForwardClock is the clock moved forward by the admin API, the endpoint is enabled only if it is set.
+	FastForwardClock *clock.Offset
+	// Failover coordinates active/standby deployment, funding is always enabled if it is not set.
+	Failover *failover.Coordinator
+	// APIKeys authenticates the clients having dedicated quota. API keys are disabled if there are no holders
+	// and new keys can't be issued by the admin API, which requires the quota.
+	APIKeys APIKeys
+	// RateLimitExemptions are the internal ranges bypassing the IP rate limit, e.g. office NAT.
+	RateLimitExemptions http.IPNets
+	// Environment is the label of the deployment, e.g. devnet or testnet, included in all JSON responses together
+	// with the chain ID.
+	Environment string
+	// SecurityHeaders configures HSTS, CSP and frame policy headers of all responses.
+	SecurityHeaders http.SecurityHeaders
+	// Preflight must succeed before /readyz reports the instance ready, the instance is ready at once if it is not set.
+	Preflight *app.Preflight
+	// Scheduler runs the background jobs listed by /admin/jobs, the endpoints are not served if it is not set.
+	Scheduler *scheduler.Scheduler
+	// MetricsCollectors are the metrics of other components exposed at /metrics, e.g. the broadcast worker pool.
+	MetricsCollectors []prometheus.Collector
+	// ExperimentLimiter limits the IPs assigned to the experiment group instead of the main limiter, the main one
+	// is used for all the IPs if it is not set.
+	ExperimentLimiter limiter.PerIPLimiter
+	// SubnetLimiter limits the aggregate requests of the networks the IPs belong to in addition to the IP limits,
+	// subnets are not limited if it is not set.
+	SubnetLimiter *limiter.SubnetLimiter
+	// FirstTimeLimiter limits the requests of the IPs which used their rate limit, let through to fund the addresses
+	// never funded before. Such requests are refused if it is not set.
+	FirstTimeLimiter limiter.PerIPLimiter
+	// IPFilter refuses the funding requests of the IPs denied by the allowlist and denylist, all the IPs are
+	// accepted if it is not set.
+	IPFilter *iplist.Filter
+	// GeoIP refuses the funding requests of the IPs located in the countries denied by it, all the countries are
+	// accepted if it is not set.
+	GeoIP *geoip.Restriction
+	// IPReputation flags the IPs of Tor exits, VPNs and datacenters, no IP is flagged if it is not set.
+	IPReputation iprep.Provider
+	// IPReputationAction is applied to the funding requests of the flagged IPs.
+	IPReputationAction iprep.Action
+	// SnapshotSources return the in-memory state of other components by name, included in /admin/snapshot,
+	// e.g. the broadcast worker pool.
+	SnapshotSources map[string]func() interface{}
+	// InternalAddress is the <host>:<port> the admin API, metrics and pprof are served at, separately from the public
+	// endpoints. They are served together with the public endpoints, except pprof, if it is empty.
+	InternalAddress string
+	// EffectiveConfig is the configuration the instance runs with, secrets redacted, returned by /admin/config.
+	EffectiveConfig []config.Entry
+	// OIDC refuses the funding requests not carrying the bearer token of the OIDC provider, e.g. of private
+	// devnets, the requests are not authenticated if it is not set.
+	OIDC *oidc.Verifier
+	// IdentityReturnURL is the page of the faucet UI the client is sent back to once it authenticates with
+	// the identity provider, the session is returned as JSON by the callback if it is empty.
+	IdentityReturnURL string
+}
+
 // HTTP type exposes app functionalities via http.
 type HTTP struct {
-	app    app.App
-	server http.Server
+	app     app.App
+	limiter limiter.PerIPLimiter
+	cfg     Config
+	server  http.Server
+	// internal serves the private endpoints, it is the same server as the public one if the internal address
+	// is not configured.
+	internal http.Server
+	metrics  metrics
+	apiKeys  *apiKeyRegistry
 }
 
 // New returns an instance of the HTTP type.
-func New(app app.App, limiter limiter.PerIPLimiter, log *zap.Logger) HTTP {
+func New(app app.App, limiter limiter.PerIPLimiter, cfg Config, log *zap.Logger) HTTP {
+	server := newServer(app, cfg, log)
+	internal := server
+	if cfg.InternalAddress != "" {
+		internal = newServer(app, cfg, log)
+	}
 	return HTTP{
-		app:    app,
-		server: http.New(log, writeErrorMiddleware(), limiterMiddleware(limiter)),
+		app:      app,
+		limiter:  limiter,
+		cfg:      cfg,
+		server:   server,
+		internal: internal,
+		metrics:  newMetrics(cfg.MetricsCollectors...),
+		apiKeys:  newAPIKeyRegistry(cfg.APIKeys.Holders),
+	}
+}
+
+func newServer(app app.App, cfg Config, log *zap.Logger) http.Server {
+	server := http.New(
+		log,
+		cfg.TrustedProxies,
+		http.SecurityMiddleware(cfg.SecurityHeaders),
+		http.CompressMiddleware(),
+		http.MetadataMiddleware(
+			http.MetadataField{Name: "chainId", Value: app.NetworkInfo().ChainID},
+			http.MetadataField{Name: "environment", Value: cfg.Environment},
+		),
+		writeErrorMiddleware(),
+	)
+	if cfg.StrictJSON {
+		server.Binder = http.NewStrictBinder()
 	}
+	return server
 }
 
-// ListenAndServe starts listening for http requests.
+// ListenAndServe starts listening for http requests, the private endpoints are served at the internal address
+// if it is configured.
 func (h HTTP) ListenAndServe(ctx context.Context, address string) error {
-	apiv1 := h.server.Group(
+	h.registerRoutes()
+	if h.cfg.InternalAddress == "" {
+		return h.server.Start(ctx, address, 30*time.Second)
+	}
+	return parallel.Run(ctx, func(ctx context.Context, spawn parallel.SpawnFn) error {
+		spawn("public", parallel.Fail, func(ctx context.Context) error {
+			return h.server.Start(ctx, address, 30*time.Second)
+		})
+		spawn("internal", parallel.Fail, func(ctx context.Context) error {
+			return h.internal.Start(ctx, h.cfg.InternalAddress, 30*time.Second)
+		})
+		return nil
+	})
+}
+
+// ServeLambda serves the requests delivered by API Gateway or ALB to AWS Lambda function instead of listening
+// for connections. The internal address is ignored, all the endpoints are served by the function.
+func (h HTTP) ServeLambda(ctx context.Context, runtimeAPI string) error {
+	h.internal = h.server
+	h.registerRoutes()
+	return lambda.Serve(ctx, runtimeAPI, h.server)
+}
+
+func (h HTTP) registerRoutes() {
+	h.server.GET("/readyz", h.readyHandle)
+	h.internal.GET("/metrics", h.metrics.handler())
+	if h.internal != h.server {
+		h.internal.GET("/readyz", h.readyHandle)
+		// profiles reveal the internals of the process, so they are never served at the public address
+		h.internal.GET("/debug/pprof/*", echo.WrapHandler(nethttp.HandlerFunc(pprof.Index)))
+		h.internal.GET("/debug/pprof/cmdline", echo.WrapHandler(nethttp.HandlerFunc(pprof.Cmdline)))
+		h.internal.GET("/debug/pprof/profile", echo.WrapHandler(nethttp.HandlerFunc(pprof.Profile)))
+		h.internal.GET("/debug/pprof/symbol", echo.WrapHandler(nethttp.HandlerFunc(pprof.Symbol)))
+		h.internal.GET("/debug/pprof/trace", echo.WrapHandler(nethttp.HandlerFunc(pprof.Trace)))
+	}
+
+	apiv1 := h.apiGroup(h.server)
+	if len(h.cfg.APIKeys.Holders) > 0 || h.apiKeysIssuable() {
+		apiv1.GET("/keys/self/usage", h.keyUsageHandle)
+		if h.app.TenantNotificationsEnabled() {
+			apiv1.GET("/keys/self/notifications", h.tenantNotificationsHandle)
+			apiv1.PUT("/keys/self/notifications", h.putTenantNotificationsHandle)
+			apiv1.DELETE("/keys/self/notifications", h.deleteTenantNotificationsHandle)
+		}
+	}
+
+	limited := h.limiterMiddleware(false)
+	// fund knows the address before funding it, so it is the one granting the grace to first-time addresses
+	limitedWithGrace := h.limiterMiddleware(true)
+	cached := http.CacheMiddleware(cacheMaxAge)
+	standby := activeMiddleware(h.cfg.Failover)
+	authenticated := h.oidcMiddleware(h.cfg.OIDC)
+	filtered := h.ipFilterMiddleware(h.cfg.IPFilter)
+	located := h.geoIPMiddleware(h.cfg.GeoIP)
+	screened := h.ipReputationMiddleware(h.cfg.IPReputation, h.cfg.IPReputationAction)
+	// funding endpoints are served by the active instance to the clients authenticated by the OIDC provider
+	// and the IPs allowed by the IP lists, GeoIP and IP reputation
+	active := func(next http.HandlerFunc) http.HandlerFunc {
+		return standby(authenticated(filtered(located(screened(next)))))
+	}
+	experiment := func(next http.HandlerFunc) http.HandlerFunc { return next }
+	if h.app.Experiment().Enabled() {
+		experiment = h.experimentMiddleware()
+	}
+
+	apiv1.GET("/status", h.statusHandle)
+	apiv1.GET("/network", h.networkHandle, cached)
+	apiv1.GET("/stats", h.statsHandle, cached)
+	if h.app.TransparencyEnabled() {
+		apiv1.GET("/transparency", h.transparencyHandle, cached)
+	}
+	apiv1.GET("/fund", h.fundHandle, active, experiment, limitedWithGrace, http.FieldsMiddleware("txHash"))
+	apiv1.POST("/fund", h.fundHandle, active, experiment, limitedWithGrace, http.FieldsMiddleware("txHash"))
+	apiv1.POST("/gen-funded", h.genFundedHandle, active, experiment, limited,
+		http.FieldsMiddleware("txHash", "mnemonic", "address"))
+	if h.app.NamedAccountsEnabled() {
+		// retrieval doesn't fund anything, so the rate limit is not applied
+		apiv1.GET("/gen-funded/:label", h.namedAccountHandle, http.FieldsMiddleware("mnemonic", "address"))
+	}
+	apiv1.GET("/quota", h.quotaHandle)
+	apiv1.GET("/qr", h.qrHandle, cached)
+	apiv1.GET("/tx/:hash", h.txStatusHandle, http.FieldsMiddleware("txHash", "status"))
+	// colon is escaped, so it is not taken for the path parameter
+	apiv1.POST("/requests\\:batchGet", h.batchGetRequestsHandle)
+	// the claim code itself limits the number of grants, so IP rate limit is not applied
+	apiv1.POST("/claim", h.claimHandle, active)
+	if h.app.ToSVersion() != "" {
+		apiv1.POST("/tos/accept", h.acceptToSHandle)
+	}
+	if h.app.PoWEnabled() {
+		// challenges are signed instead of stored, so issuing them is cheap and not rate limited
+		apiv1.POST("/pow/challenges", h.createPoWChallengeHandle, active)
+	}
+	if h.app.OwnershipProofEnabled() {
+		apiv1.POST("/ownership/challenges", h.createOwnershipChallengeHandle, active)
+	}
+	if len(h.app.IdentityProviders()) > 0 {
+		// login states are signed by the key of the instance, so both steps are served by the active one
+		apiv1.GET("/auth/:provider/login", h.identityLoginHandle, active)
+		apiv1.GET("/auth/:provider/callback", h.identityCallbackHandle, active)
+	}
+	if h.app.EmailVerificationEnabled() {
+		// each request sends an email, so it is rate limited, the link itself limits the grants
+		apiv1.POST("/email/verifications", h.requestEmailVerificationHandle, active, limited)
+		apiv1.GET("/email/verify", h.verifyEmailHandle, active, http.FieldsMiddleware("txHash", "address"))
+		apiv1.POST("/email/verify", h.verifyEmailHandle, active, http.FieldsMiddleware("txHash", "address"))
+	}
+	if h.app.FundingLinksEnabled() {
+		// the link itself limits the grants, so IP rate limit is not applied
+		apiv1.POST("/funding-links/redeem", h.redeemFundingLinkHandle, active, http.FieldsMiddleware("txHash", "address"))
+	}
+	if h.app.PhoneVerificationEnabled() {
+		// each request sends a text message, so it is rate limited, the code is presented to the fund endpoint
+		apiv1.POST("/phone/verifications", h.requestPhoneVerificationHandle, active, limited)
+	}
+	if h.app.OnChainChallengeEnabled() {
+		// the IP rate limit is consumed when the challenge is created, completion is limited by the challenge
+		apiv1.POST("/challenges", h.createChallengeHandle, active, experiment, limited)
+		apiv1.POST("/challenges/:id/complete", h.completeChallengeHandle, active, experiment,
+			http.FieldsMiddleware("txHash"))
+	}
+
+	if h.cfg.AdminToken != "" {
+		internalAPIv1 := apiv1
+		if h.internal != h.server {
+			internalAPIv1 = h.apiGroup(h.internal)
+		}
+		admin := internalAPIv1.Group("/admin", adminAuthMiddleware(h.cfg.AdminToken))
+		admin.POST("/fund-many", h.fundManyHandle, active)
+		admin.GET("/reports/clusters", h.clusterReportHandle)
+		admin.GET("/ledger/balances", h.ledgerBalancesHandle)
+		admin.POST("/ledger/allocations", h.ledgerAllocationHandle)
+		admin.GET("/ledger/discrepancies", h.ledgerDiscrepanciesHandle)
+		admin.GET("/export/indexer", h.indexerExportHandle)
+		admin.GET("/history", h.historyHandle)
+		admin.GET("/address-book", h.recipientsHandle)
+		admin.PUT("/address-book/:name", h.putRecipientHandle)
+		admin.DELETE("/address-book/:name", h.deleteRecipientHandle)
+		admin.GET("/claim-codes", h.claimCodesHandle)
+		admin.POST("/claim-codes", h.createClaimCodesHandle)
+		admin.DELETE("/claim-codes/:code", h.deleteClaimCodeHandle)
+		if h.app.FundingLinksEnabled() {
+			admin.POST("/funding-links", h.createFundingLinksHandle)
+		}
+		admin.GET("/bypass-tokens", h.bypassTokensHandle)
+		admin.POST("/bypass-tokens", h.createBypassTokenHandle)
+		admin.DELETE("/bypass-tokens/:id", h.deleteBypassTokenHandle)
+		admin.GET("/expiring", h.expiringItemsHandle)
+		admin.PUT("/expiring/:kind/:id", h.extendExpiryHandle)
+		admin.GET("/config", h.configHandle)
+		admin.GET("/config/changes", h.configChangesHandle)
+		admin.GET("/snapshot", h.snapshotHandle)
+		admin.GET("/controls", h.controlsHandle)
+		admin.POST("/controls/pause", h.pauseHandle)
+		admin.POST("/controls/resume", h.resumeHandle)
+		admin.PUT("/controls/transfer-amount", h.setTransferAmountHandle)
+		admin.GET("/blocked-addresses", h.blockedAddressesHandle)
+		admin.PUT("/blocked-addresses/:address", h.blockAddressHandle)
+		admin.DELETE("/blocked-addresses/:address", h.unblockAddressHandle)
+		if h.app.AddressTotalsTracked() {
+			admin.GET("/address-totals/:address", h.addressTotalHandle)
+			admin.DELETE("/address-totals/:address", h.resetAddressTotalHandle)
+		}
+		if len(h.cfg.APIKeys.Holders) > 0 || h.apiKeysIssuable() {
+			admin.GET("/api-keys", h.apiKeysHandle)
+		}
+		if h.apiKeysIssuable() {
+			admin.POST("/api-keys", h.issueAPIKeyHandle)
+		}
+		if h.cfg.Failover != nil {
+			admin.GET("/failover", h.failoverStatusHandle)
+			admin.POST("/failover/promote", h.failoverPromoteHandle)
+		}
+		if h.cfg.FastForwardClock != nil {
+			admin.POST("/clock/fast-forward", h.fastForwardHandle)
+		}
+		if h.cfg.Scheduler != nil {
+			admin.GET("/jobs", h.jobsHandle)
+			admin.POST("/jobs/:name/run", h.runJobHandle)
+		}
+		if h.app.TreasuryEnabled() {
+			admin.GET("/refills", h.refillsHandle)
+			admin.POST("/refills", h.proposeRefillHandle)
+			admin.GET("/refills/:id", h.refillHandle)
+			admin.GET("/refills/:id/tx", h.refillTxHandle)
+			admin.POST("/refills/:id/signatures", h.addRefillSignatureHandle)
+			admin.POST("/refills/:id/broadcast", h.broadcastRefillHandle)
+		}
+	}
+}
+
+// apiGroup returns the group of the API endpoints of the server.
+func (h HTTP) apiGroup(server http.Server) *echo.Group {
+	apiv1 := server.Group(
 		"/api/faucet/v1",
 		middleware.BodyLimit("4MB"),
 	)
+	if len(h.cfg.APIKeys.Holders) > 0 || h.apiKeysIssuable() {
+		apiv1.Use(apiKeyMiddleware(h.apiKeys))
+	}
+	return apiv1
+}
 
-	apiv1.GET("/status", h.statusHandle)
-	apiv1.POST("/fund", h.fundHandle)
-	apiv1.POST("/gen-funded", h.genFundedHandle)
-
-	return h.server.Start(ctx, address, 30*time.Second)
+// apiKeysIssuable tells if the admin API may issue API keys, the quota limiting their holders is required.
+func (h HTTP) apiKeysIssuable() bool {
+	return h.cfg.AdminToken != "" && h.cfg.APIKeys.Quota != nil
 }
 
 // StatusResponse is the output to /status request.
@@ -57,42 +379,396 @@ func (h HTTP) statusHandle(ctx http.Context) error {
 	})
 }
 
-// FundRequest is the input to GiveFunds request.
+// ReadyResponse is the output to /readyz request.
+type ReadyResponse struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+	// Funding is "available", "paused" by the operator, or "signing_unavailable" if only the endpoints which don't
+	// sign anything are served.
+	Funding string `json:"funding,omitempty"`
+}
+
+func (h HTTP) readyHandle(ctx http.Context) error {
+	if err := h.cfg.Preflight.Ready(); err != nil {
+		return ctx.JSON(nethttp.StatusServiceUnavailable, ReadyResponse{Status: "not_ready", Reason: err.Error()})
+	}
+	// paused instance stays ready, the operator may resume it any time
+	if controls := h.app.Controls(); controls.Paused {
+		return ctx.JSON(nethttp.StatusOK, ReadyResponse{Status: "ready", Reason: controls.PauseReason, Funding: "paused"})
+	}
+	// the instance stays ready during signing outage, so stats, network and tx endpoints keep being routed to it
+	if err := h.app.SigningStatus(); err != nil {
+		return ctx.JSON(nethttp.StatusOK, ReadyResponse{Status: "ready", Reason: err.Error(), Funding: "signing_unavailable"})
+	}
+	return ctx.JSON(nethttp.StatusOK, ReadyResponse{Status: "ready", Funding: "available"})
+}
+
+// NetworkResponse is the output to /network request.
+type NetworkResponse struct {
+	ChainID        string `json:"chainId"`
+	Denom          string `json:"denom"`
+	AddressPrefix  string `json:"addressPrefix"`
+	TransferAmount string `json:"transferAmount"`
+	// TransferCoin is the transfer amount enriched with the display unit.
+	TransferCoin CoinResponse `json:"transferCoin"`
+	// ToSVersion is the version of the terms of service to accept before funding, empty if acceptance
+	// is not required.
+	ToSVersion string `json:"tosVersion,omitempty"`
+}
+
+func (h HTTP) networkHandle(ctx http.Context) error {
+	info := h.app.NetworkInfo()
+	return ctx.JSON(nethttp.StatusOK, NetworkResponse{
+		ChainID:        info.ChainID,
+		Denom:          info.Denom,
+		AddressPrefix:  info.AddressPrefix,
+		TransferAmount: info.TransferAmount.String(),
+		TransferCoin:   h.coinResponse(ctx.Request().Context(), info.TransferAmount),
+		ToSVersion:     h.app.ToSVersion(),
+	})
+}
+
+// WindowStatsResponse contains the statistics of the fundings over the period.
+type WindowStatsResponse struct {
+	Period          string `json:"period"`
+	Grants          int    `json:"grants"`
+	UniqueAddresses int    `json:"uniqueAddresses"`
+	Amount          string `json:"amount"`
+	Fees            string `json:"fees"`
+	// AmountCoins and FeesCoins are the amount and fees enriched with the display units.
+	AmountCoins []CoinResponse `json:"amountCoins"`
+	FeesCoins   []CoinResponse `json:"feesCoins"`
+}
+
+// BudgetResponse is the status of the daily budget of the denom.
+type BudgetResponse struct {
+	Denom         string     `json:"denom"`
+	Limit         string     `json:"limit"`
+	Spent         string     `json:"spent"`
+	Remaining     string     `json:"remaining"`
+	NextReleaseAt *time.Time `json:"nextReleaseAt,omitempty"`
+}
+
+// StatsResponse is the output to /stats request.
+type StatsResponse struct {
+	Windows []WindowStatsResponse `json:"windows"`
+	// Budgets are the daily budgets per denom, if configured.
+	Budgets []BudgetResponse `json:"budgets,omitempty"`
+}
+
+func (h HTTP) statsHandle(ctx http.Context) error {
+	stats, err := h.app.Stats(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	resp := StatsResponse{Windows: []WindowStatsResponse{}}
+	for _, w := range stats {
+		resp.Windows = append(resp.Windows, WindowStatsResponse{
+			Period:          w.Window.String(),
+			Grants:          w.Grants,
+			UniqueAddresses: w.UniqueAddresses,
+			Amount:          w.Amount.String(),
+			Fees:            w.Fees.String(),
+			AmountCoins:     h.coinResponses(ctx.Request().Context(), w.Amount),
+			FeesCoins:       h.coinResponses(ctx.Request().Context(), w.Fees),
+		})
+	}
+
+	budgets, err := h.app.Budgets(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+	for _, b := range budgets {
+		budget := BudgetResponse{
+			Denom:     b.Limit.Denom,
+			Limit:     b.Limit.Amount.String(),
+			Spent:     b.Spent.Amount.String(),
+			Remaining: b.Remaining.Amount.String(),
+		}
+		if !b.NextReleaseAt.IsZero() {
+			nextReleaseAt := b.NextReleaseAt
+			budget.NextReleaseAt = &nextReleaseAt
+		}
+		resp.Budgets = append(resp.Budgets, budget)
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
+
+// FundRequest is the input to GiveFunds request. It is accepted as JSON or form-encoded body,
+// or as query parameters of GET request.
 type FundRequest struct {
-	Address string `json:"address"`
+	Address string `json:"address" form:"address" query:"address"`
+	// Recipient is the name of the address book entry funded instead of the address. It is accepted only
+	// from the clients authorized with the admin token.
+	Recipient string `json:"recipient" form:"recipient" query:"recipient"`
+	// CallbackURL is notified with the signed result once the funding transaction is confirmed.
+	CallbackURL string `json:"callbackUrl" form:"callbackUrl" query:"callbackUrl"`
+	// MinConfirmations overrides the number of confirmations required to report the request as confirmed.
+	MinConfirmations int64 `json:"minConfirmations" form:"minConfirmations" query:"minConfirmations"`
+	// CaptchaToken is the captcha solved by the user, required if captcha verification is enabled.
+	CaptchaToken string `json:"captcha_token" form:"captcha_token" query:"captcha_token"`
+	// Verification are the proofs of the client being a human keyed by the verifier checking them, it is accepted
+	// in JSON body only. The captcha token may be passed as CaptchaToken instead.
+	Verification map[string]string `json:"verification"`
+	// InvoiceID is the ID of the invoice the API key client correlates the funding with. Its hash is embedded
+	// into the memo of the transaction.
+	InvoiceID string `json:"invoiceId" form:"invoiceId" query:"invoiceId"`
 }
 
 // FundResponse is the output to GiveFunds request.
 type FundResponse struct {
 	TxHash string `json:"txHash"`
+	// InvoiceHash is the hash of the invoice ID embedded into the memo, set if the invoice ID is passed.
+	InvoiceHash string `json:"invoiceHash,omitempty"`
 }
 
 func (h HTTP) fundHandle(ctx http.Context) error {
 	var rqBody FundRequest
-	if err := ctx.Bind(&rqBody); err != nil {
+	if http.IsProtobufRequest(ctx) {
+		var pbBody pb.FundRequest
+		if err := http.BindProtobuf(ctx, &pbBody); err != nil {
+			return err
+		}
+		rqBody = FundRequest{
+			Address:          pbBody.Address,
+			Recipient:        pbBody.Recipient,
+			CallbackURL:      pbBody.CallbackUrl,
+			MinConfirmations: pbBody.MinConfirmations,
+			CaptchaToken:     pbBody.CaptchaToken,
+			Verification:     pbBody.Verification,
+			InvoiceID:        pbBody.InvoiceId,
+		}
+	} else if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+
+	address, err := h.fundAddress(ctx, rqBody)
+	if err != nil {
+		return err
+	}
+
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
 		return err
 	}
+	requester.CallbackURL = rqBody.CallbackURL
+	requester.MinConfirmations = rqBody.MinConfirmations
+	requester.Proofs = verificationProofs(rqBody.Verification, rqBody.CaptchaToken)
+	requester.Admin = h.adminAuthorized(ctx)
+	requester.InvoiceID = rqBody.InvoiceID
 
-	txHash, err := h.app.GiveFunds(ctx.Request().Context(), rqBody.Address)
+	if err := h.checkFirstTimeGrace(ctx, address); err != nil {
+		h.app.ReportBlocked(ctx.Request().Context(), requester, address, err)
+		return err
+	}
+
+	txHash, err := h.app.GiveFunds(ctx.Request().Context(), requester, address)
 	if err != nil {
 		return err
 	}
 
-	return ctx.JSON(nethttp.StatusOK, FundResponse{TxHash: txHash})
+	resp := FundResponse{TxHash: txHash}
+	if rqBody.InvoiceID != "" {
+		resp.InvoiceHash = attribution.HashInvoiceID(rqBody.InvoiceID)
+	}
+	if http.AcceptsProtobuf(ctx) {
+		return http.Protobuf(ctx, nethttp.StatusOK, &pb.FundResponse{TxHash: resp.TxHash, InvoiceHash: resp.InvoiceHash})
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
+
+// verificationProofs returns the proofs of the client being a human presented with the request.
+func verificationProofs(verification map[string]string, captchaToken string) app.Proofs {
+	proofs := app.Proofs{}
+	for name, proof := range verification {
+		proofs[name] = proof
+	}
+	if captchaToken != "" {
+		proofs[app.ProofCaptcha] = captchaToken
+	}
+	return proofs
+}
+
+// fundAddress returns the address to fund, resolving the recipient using the address book.
+func (h HTTP) fundAddress(ctx http.Context, rqBody FundRequest) (string, error) {
+	if rqBody.Recipient == "" {
+		return rqBody.Address, nil
+	}
+	if rqBody.Address != "" {
+		return "", errors.Wrap(ErrInvalidRequest, "address and recipient are mutually exclusive")
+	}
+	if !h.adminAuthorized(ctx) {
+		return "", errors.Wrap(ErrUnauthorized, "admin token is required to fund the recipient")
+	}
+	return h.app.RecipientAddress(ctx.Request().Context(), rqBody.Recipient)
+}
+
+// GenFundedRequest is the input to gen-funded request.
+type GenFundedRequest struct {
+	// Label names the generated account, so the same account is funded and returned again by the following
+	// requests of the API key holder using the label. New account is generated by each request if it is empty.
+	Label string `json:"label" form:"label" query:"label"`
+	// QR requests the QR code of the generated address in the response.
+	QR bool `json:"qr" form:"qr" query:"qr"`
+	// CaptchaToken is the captcha solved by the user, required if captcha verification is enabled.
+	CaptchaToken string `json:"captcha_token" form:"captcha_token" query:"captcha_token"`
+	// Verification are the proofs of the client being a human keyed by the verifier checking them, as in FundRequest.
+	Verification map[string]string `json:"verification"`
 }
 
 // GenFundedResponse is the output to GiveFunds request.
 type GenFundedResponse struct {
-	TxHash   string `json:"txHash"`
+	TxHash   string `json:"txHash,omitempty"`
 	Mnemonic string `json:"mnemonic"`
 	Address  string `json:"address"`
+	// Label is the label the account is generated under, empty if it is not named.
+	Label     string     `json:"label,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	// ExampleTx is base64-encoded signed transaction ready to be broadcast.
+	ExampleTx string `json:"exampleTx,omitempty"`
+	// QRCode is the data URI of the PNG image of the QR code encoding the address, set if requested.
+	QRCode string `json:"qrCode,omitempty"`
 }
 
 func (h HTTP) genFundedHandle(ctx http.Context) error {
-	result, err := h.app.GenMnemonicAndFund(ctx.Request().Context())
+	var rqBody GenFundedRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+
+	requester, err := requesterFromContext(ctx)
 	if err != nil {
 		return err
 	}
+	requester.Proofs = verificationProofs(rqBody.Verification, rqBody.CaptchaToken)
 
-	return ctx.JSON(nethttp.StatusOK, GenFundedResponse(result))
+	result, err := h.app.GenMnemonicAndFund(ctx.Request().Context(), requester, rqBody.Label)
+	if err != nil {
+		return err
+	}
+
+	resp := genFundedResponse(result)
+	if rqBody.QR {
+		if resp.QRCode, err = qrDataURI(result.Address); err != nil {
+			return err
+		}
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
+
+func (h HTTP) namedAccountHandle(ctx http.Context) error {
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// labels may contain slashes, which are escaped in the path
+	label, err := url.PathUnescape(ctx.Param("label"))
+	if err != nil {
+		return errors.Wrapf(ErrInvalidRequest, "invalid label: %s", err)
+	}
+	result, err := h.app.NamedAccount(ctx.Request().Context(), requester, label)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(nethttp.StatusOK, genFundedResponse(result))
+}
+
+// genFundedResponse is the only place the mnemonic is revealed, everywhere else it is redacted.
+func genFundedResponse(result app.GenMnemonicAndFundResult) GenFundedResponse {
+	resp := GenFundedResponse{
+		TxHash:    result.TxHash,
+		Mnemonic:  result.Mnemonic.Reveal(),
+		Address:   result.Address,
+		Label:     result.Label,
+		ExampleTx: base64.StdEncoding.EncodeToString(result.ExampleTx),
+	}
+	if !result.CreatedAt.IsZero() {
+		createdAt := result.CreatedAt
+		resp.CreatedAt = &createdAt
+	}
+	return resp
+}
+
+func requesterFromContext(ctx http.Context) (app.Requester, error) {
+	r := ctx.Request()
+	ip, err := http.IPFromRequest(r)
+	if err != nil {
+		return app.Requester{}, err
+	}
+	// the tenant is charged for the funding and picks its fee denom and notifications, so it is taken only
+	// from the credentials authenticated already, never from the headers sent by the client
+	holder, _ := apiKeyHolder(ctx)
+	tenant := holder
+	var bypassTokenID string
+	if token, ok := ctx.Get(contextKeyBypassToken).(app.BypassToken); ok {
+		bypassTokenID = token.ID
+		if tenant == "" {
+			tenant = token.Holder
+		}
+	}
+	var session string
+	if tenant != "" {
+		session = r.Header.Get(HeaderXFaucetSession)
+	}
+	apiKeyHash, _ := ctx.Get(contextKeyAPIKeyHash).(string)
+	ipReputation, _ := ctx.Get(contextKeyIPReputation).(iprep.Category)
+	return app.Requester{
+		RequestID:     r.Header.Get(http.HeaderXRequestID),
+		IP:            ip.String(),
+		Fingerprint:   http.FingerprintFromRequest(r),
+		Tenant:        tenant,
+		Session:       session,
+		APIKeyHash:    apiKeyHash,
+		APIKeyHolder:  holder,
+		BypassTokenID: bypassTokenID,
+		IPReputation:  string(ipReputation),
+		ToSToken:      r.Header.Get(HeaderXFaucetToSToken),
+	}, nil
+}
+
+// TxRequestResponse identifies the funding request included in the transaction.
+type TxRequestResponse struct {
+	RequestID string `json:"requestId"`
+	Address   string `json:"address"`
+	// RequiredConfirmations is the number of confirmations the request requires, it is not set if the request
+	// requires the default number.
+	RequiredConfirmations int64 `json:"requiredConfirmations,omitempty"`
+}
+
+func txRequestResponse(r app.TxRequest) TxRequestResponse {
+	return TxRequestResponse{RequestID: r.RequestID, Address: r.Address, RequiredConfirmations: r.RequiredConfirmations}
+}
+
+// TxStatusResponse is the output to /tx/:hash request.
+type TxStatusResponse struct {
+	TxHash        string `json:"txHash"`
+	Status        string `json:"status"`
+	Height        int64  `json:"height"`
+	Confirmations int64  `json:"confirmations"`
+	// RequiredConfirmations is the number of confirmations required to report the transaction as confirmed.
+	RequiredConfirmations int64               `json:"requiredConfirmations"`
+	Requests              []TxRequestResponse `json:"requests"`
+}
+
+func (h HTTP) txStatusHandle(ctx http.Context) error {
+	status, err := h.app.TxStatus(ctx.Param("hash"))
+	if err != nil {
+		return err
+	}
+
+	resp := TxStatusResponse{
+		TxHash:                status.TxHash,
+		Status:                string(status.State),
+		Height:                status.Height,
+		Confirmations:         status.Confirmations,
+		RequiredConfirmations: status.RequiredConfirmations,
+		Requests:              []TxRequestResponse{},
+	}
+	for _, r := range status.Requests {
+		resp.Requests = append(resp.Requests, txRequestResponse(r))
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
 }
@@ -0,0 +1,908 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"image/png"
+	"net"
+	nethttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/http/pb"
+	"github.com/CoreumFoundation/faucet/oidc"
+	"github.com/CoreumFoundation/faucet/pkg/attribution"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+	"github.com/CoreumFoundation/faucet/pkg/iprep"
+	"github.com/CoreumFoundation/faucet/pkg/limiter"
+	"github.com/CoreumFoundation/faucet/pkg/pagination"
+	"github.com/CoreumFoundation/faucet/pkg/ratelimit"
+	"github.com/CoreumFoundation/faucet/pkg/secret"
+	"github.com/CoreumFoundation/faucet/store"
+)
+
+func TestInternalListener(t *testing.T) {
+	requireT := require.New(t)
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	txTracker := app.NewTxTracker(contractChain{}, 1, nil, clock.System{})
+	a := app.New(&contractBatcher{txTracker: txTracker}, contractChain{}, txTracker, nil, nil, network,
+		chain.NewCoin(network.Denom(), chain.NewInt(1000000)))
+
+	h := New(a, contractLimiter{}, Config{
+		AdminToken:      contractAdminToken,
+		InternalAddress: "localhost:0",
+	}, zaptest.NewLogger(t))
+	h.registerRoutes()
+
+	status := func(handler nethttp.Handler, path string) int {
+		req := httptest.NewRequest(nethttp.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+contractAdminToken)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	requireT.Equal(nethttp.StatusOK, status(h.server, "/api/faucet/v1/network"))
+	requireT.Equal(nethttp.StatusOK, status(h.server, "/readyz"))
+	requireT.Equal(nethttp.StatusNotFound, status(h.server, "/metrics"))
+	requireT.Equal(nethttp.StatusNotFound, status(h.server, "/api/faucet/v1/admin/controls"))
+	requireT.Equal(nethttp.StatusNotFound, status(h.server, "/debug/pprof/"))
+
+	requireT.Equal(nethttp.StatusNotFound, status(h.internal, "/api/faucet/v1/network"))
+	requireT.Equal(nethttp.StatusOK, status(h.internal, "/readyz"))
+	requireT.Equal(nethttp.StatusOK, status(h.internal, "/metrics"))
+	requireT.Equal(nethttp.StatusOK, status(h.internal, "/api/faucet/v1/admin/controls"))
+	requireT.Equal(nethttp.StatusOK, status(h.internal, "/debug/pprof/"))
+}
+
+func TestOIDC(t *testing.T) {
+	requireT := require.New(t)
+
+	provider := httptest.NewServer(nethttp.NotFoundHandler())
+	t.Cleanup(provider.Close)
+	verifier, err := oidc.NewVerifier(oidc.Config{IssuerURL: provider.URL, ClientID: "faucet"}, provider.Client())
+	requireT.NoError(err)
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	txTracker := app.NewTxTracker(contractChain{}, 1, nil, clock.System{})
+	a := app.New(&contractBatcher{txTracker: txTracker}, contractChain{}, txTracker, nil, nil, network,
+		chain.NewCoin(network.Denom(), chain.NewInt(1000000)))
+	h := New(a, contractLimiter{}, Config{OIDC: verifier}, zaptest.NewLogger(t))
+	h.registerRoutes()
+
+	send := func(path, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(nethttp.MethodPost, path, strings.NewReader(`{"address":"`+contractAddress+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rec := httptest.NewRecorder()
+		h.server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send("/api/faucet/v1/fund", "")
+	requireT.Equal(nethttp.StatusUnauthorized, rec.Code)
+	requireT.Contains(rec.Body.String(), "oidc.invalid_token")
+	rec = send("/api/faucet/v1/fund", "Bearer invalid")
+	requireT.Equal(nethttp.StatusUnauthorized, rec.Code)
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"key1"}`))
+	rec = send("/api/faucet/v1/fund", "Bearer "+header+".e30.c2ln")
+	requireT.Equal(nethttp.StatusServiceUnavailable, rec.Code)
+	requireT.Contains(rec.Body.String(), "oidc.unavailable")
+
+	// only funding endpoints are protected
+	rec = send("/api/faucet/v1/requests:batchGet", "")
+	requireT.NotEqual(nethttp.StatusUnauthorized, rec.Code)
+}
+
+func TestQR(t *testing.T) {
+	requireT := require.New(t)
+
+	handler, _ := newContractServer(t)
+	req := httptest.NewRequest(nethttp.MethodGet, "/api/faucet/v1/qr?address="+contractAddress+"&size=128", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	requireT.Equal(nethttp.StatusOK, rec.Code)
+	requireT.Equal("image/png", rec.Header().Get("Content-Type"))
+	img, err := png.Decode(rec.Body)
+	requireT.NoError(err)
+	requireT.Equal(128, img.Bounds().Dx())
+
+	req = httptest.NewRequest(nethttp.MethodPost, "/api/faucet/v1/gen-funded", strings.NewReader(`{"qr":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	requireT.Equal(nethttp.StatusOK, rec.Code)
+	var resp GenFundedResponse
+	requireT.NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	requireT.True(strings.HasPrefix(resp.QRCode, "data:image/png;base64,"))
+	content, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(resp.QRCode, "data:image/png;base64,"))
+	requireT.NoError(err)
+	_, err = png.Decode(bytes.NewReader(content))
+	requireT.NoError(err)
+}
+
+func TestVerificationProofs(t *testing.T) {
+	requireT := require.New(t)
+
+	requireT.Equal(app.Proofs{}, verificationProofs(nil, ""))
+	requireT.Equal(app.Proofs{app.ProofCaptcha: "token", "pow": "nonce"}, verificationProofs(
+		map[string]string{app.ProofCaptcha: "other", "pow": "nonce"},
+		"token",
+	))
+}
+
+func TestAPIKeyRegistryList(t *testing.T) {
+	requireT := require.New(t)
+
+	r := newAPIKeyRegistry(map[string]string{"configured-key": "ci"})
+	key, err := r.issue("partner")
+	requireT.NoError(err)
+
+	page, err := apiKeysSpec.Apply(r.list(), pagination.Query{})
+	requireT.NoError(err)
+	requireT.Equal([]apiKeyEntry{
+		{Hash: attribution.HashAPIKey("configured-key"), Holder: "ci"},
+		{Hash: attribution.HashAPIKey(key), Holder: "partner", Issued: true},
+	}, page.Items)
+}
+
+func TestPoW(t *testing.T) {
+	requireT := require.New(t)
+
+	handler, _ := newContractServer(t, func(a app.App) app.App {
+		a, err := a.WithProofOfWork(4)
+		requireT.NoError(err)
+		return a
+	})
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(nethttp.MethodPost, "/api/faucet/v1/pow/challenges", "")
+	requireT.Equal(nethttp.StatusCreated, rec.Code)
+	var challenge PoWChallengeResponse
+	requireT.NoError(json.Unmarshal(rec.Body.Bytes(), &challenge))
+	requireT.Equal(4, challenge.Difficulty)
+	requireT.Equal("sha256", challenge.Algorithm)
+
+	rec = send(nethttp.MethodPost, "/api/faucet/v1/fund", `{"address":"`+contractAddress+`"}`)
+	requireT.Equal(nethttp.StatusForbidden, rec.Code)
+	requireT.Contains(rec.Body.String(), "pow.failed")
+
+	proof := challenge.Challenge + ":" + app.SolvePoW(challenge.Challenge, challenge.Difficulty)
+	rec = send(nethttp.MethodPost, "/api/faucet/v1/fund",
+		`{"address":"`+contractAddress+`","verification":{"pow":"`+proof+`"}}`)
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestDenomBudgets(t *testing.T) {
+	requireT := require.New(t)
+
+	handler, _ := newContractServer(t, func(a app.App) app.App {
+		return a.WithDailyBudget(
+			chain.NewCoin("udevcore", chain.NewInt(1500000)),
+			chain.NewCoin("uusdc", chain.NewInt(500000)),
+		)
+	})
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(nethttp.MethodPost, "/api/faucet/v1/fund", `{"address":"`+contractAddress+`"}`)
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+	rec = send(nethttp.MethodPost, "/api/faucet/v1/fund", `{"address":"`+contractAddress+`"}`)
+	requireT.Equal(nethttp.StatusServiceUnavailable, rec.Code)
+	requireT.NotEmpty(rec.Header().Get("Retry-After"))
+	var errResp struct {
+		Content []struct {
+			Kind    string `json:"kind"`
+			Message string `json:"message"`
+			Denom   string `json:"denom"`
+		} `json:"content"`
+	}
+	requireT.NoError(json.Unmarshal(rec.Body.Bytes(), &errResp))
+	requireT.Len(errResp.Content, 1)
+	requireT.Equal("server.budget_exceeded", errResp.Content[0].Kind)
+	requireT.Equal("udevcore", errResp.Content[0].Denom)
+	requireT.Equal("daily budget exceeded for udevcore", errResp.Content[0].Message)
+
+	rec = send(nethttp.MethodGet, "/api/faucet/v1/stats", "")
+	requireT.Equal(nethttp.StatusOK, rec.Code)
+	var stats StatsResponse
+	requireT.NoError(json.Unmarshal(rec.Body.Bytes(), &stats))
+	requireT.Len(stats.Budgets, 2)
+	requireT.Equal("udevcore", stats.Budgets[0].Denom)
+	requireT.Equal("1000000", stats.Budgets[0].Spent)
+	requireT.Equal("500000", stats.Budgets[0].Remaining)
+	requireT.NotNil(stats.Budgets[0].NextReleaseAt)
+	// the stablecoin budget is untouched by the native grants
+	requireT.Equal("uusdc", stats.Budgets[1].Denom)
+	requireT.Equal("0", stats.Budgets[1].Spent)
+	requireT.Equal("500000", stats.Budgets[1].Remaining)
+	requireT.Nil(stats.Budgets[1].NextReleaseAt)
+}
+
+func TestOwnershipProof(t *testing.T) {
+	requireT := require.New(t)
+
+	handler, _ := newContractServer(t, func(a app.App) app.App {
+		a, err := a.WithOwnershipProof()
+		requireT.NoError(err)
+		return a
+	})
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	key := secp256k1.GenPrivKey()
+	address, err := bech32.ConvertAndEncode("devcore", key.PubKey().Address())
+	requireT.NoError(err)
+
+	rec := send(nethttp.MethodPost, "/api/faucet/v1/ownership/challenges", "")
+	requireT.Equal(nethttp.StatusCreated, rec.Code)
+	var challenge OwnershipChallengeResponse
+	requireT.NoError(json.Unmarshal(rec.Body.Bytes(), &challenge))
+
+	rec = send(nethttp.MethodPost, "/api/faucet/v1/fund", `{"address":"`+address+`"}`)
+	requireT.Equal(nethttp.StatusForbidden, rec.Code)
+	requireT.Contains(rec.Body.String(), "ownership.failed")
+
+	signature, err := key.Sign(chain.ADR36SignBytes(address, []byte(challenge.Challenge)))
+	requireT.NoError(err)
+	proof := challenge.Challenge + ":" + base64.StdEncoding.EncodeToString(key.PubKey().Bytes()) + ":" +
+		base64.StdEncoding.EncodeToString(signature)
+	rec = send(nethttp.MethodPost, "/api/faucet/v1/fund",
+		`{"address":"`+address+`","verification":{"ownership":"`+proof+`"}}`)
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+}
+
+// fakeIdentityProvider authenticates every code as the same account.
+type fakeIdentityProvider struct{}
+
+func (fakeIdentityProvider) Name() string {
+	return "github"
+}
+
+func (fakeIdentityProvider) LoginURL(state string) string {
+	return "https://github.example.com/login/oauth/authorize?" + url.Values{"state": {state}}.Encode()
+}
+
+func (fakeIdentityProvider) Authenticate(ctx context.Context, code string) (app.Identity, error) {
+	return app.Identity{Subject: "42", Name: "octocat"}, nil
+}
+
+func TestIdentityLogin(t *testing.T) {
+	requireT := require.New(t)
+
+	handler, _ := newContractServer(t, func(a app.App) app.App {
+		a, err := a.WithIdentity(app.IdentityConfig{
+			SigningKey: "0123456789abcdef",
+			SessionTTL: time.Hour,
+		}, nil, fakeIdentityProvider{})
+		requireT.NoError(err)
+		return a
+	})
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(nethttp.MethodGet, "/api/faucet/v1/auth/discord/login", "")
+	requireT.Equal(nethttp.StatusNotFound, rec.Code)
+	requireT.Contains(rec.Body.String(), "identity.provider_not_found")
+
+	rec = send(nethttp.MethodGet, "/api/faucet/v1/auth/github/login", "")
+	requireT.Equal(nethttp.StatusFound, rec.Code)
+	location, err := url.Parse(rec.Header().Get("Location"))
+	requireT.NoError(err)
+	state := location.Query().Get("state")
+	requireT.NotEmpty(state)
+
+	rec = send(nethttp.MethodGet, "/api/faucet/v1/auth/github/callback?error=access_denied", "")
+	requireT.Equal(nethttp.StatusForbidden, rec.Code)
+	rec = send(nethttp.MethodGet, "/api/faucet/v1/auth/github/callback?"+
+		url.Values{"code": {"code1"}, "state": {state}}.Encode(), "")
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+	var session IdentitySessionResponse
+	requireT.NoError(json.Unmarshal(rec.Body.Bytes(), &session))
+	requireT.Equal("octocat", session.Account)
+
+	rec = send(nethttp.MethodPost, "/api/faucet/v1/fund", `{"address":"`+contractAddress+`"}`)
+	requireT.Equal(nethttp.StatusForbidden, rec.Code)
+	requireT.Contains(rec.Body.String(), "identity.failed")
+	rec = send(nethttp.MethodPost, "/api/faucet/v1/fund",
+		`{"address":"`+contractAddress+`","verification":{"identity":"`+session.Token+`"}}`)
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+}
+
+// capturedEmails keeps the emails sent by the faucet.
+type capturedEmails struct {
+	sent []app.EmailMessage
+}
+
+func (c *capturedEmails) SendEmail(ctx context.Context, msg app.EmailMessage) error {
+	c.sent = append(c.sent, msg)
+	return nil
+}
+
+func TestEmailVerification(t *testing.T) {
+	requireT := require.New(t)
+
+	db, err := store.Open(filepath.Join(t.TempDir(), "email.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	emails := &capturedEmails{}
+	handler, _ := newContractServer(t, func(a app.App) app.App {
+		a, err := a.WithEmailVerification(app.EmailVerificationConfig{
+			SigningKey: "0123456789abcdef",
+			LinkURL:    "https://faucet.example.com/api/faucet/v1/email/verify",
+			TTL:        time.Hour,
+		}, db, emails)
+		requireT.NoError(err)
+		return a
+	})
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(nethttp.MethodPost, "/api/faucet/v1/email/verifications",
+		`{"address":"`+contractAddress+`","email":"invalid"}`)
+	requireT.Equal(nethttp.StatusBadRequest, rec.Code)
+	requireT.Contains(rec.Body.String(), "email.invalid")
+	rec = send(nethttp.MethodPost, "/api/faucet/v1/email/verifications",
+		`{"address":"`+contractAddress+`","email":"dev@example.com"}`)
+	requireT.Equal(nethttp.StatusAccepted, rec.Code, rec.Body.String())
+	requireT.Len(emails.sent, 1)
+	i := strings.Index(emails.sent[0].Body, "https://")
+	link, err := url.Parse(strings.Fields(emails.sent[0].Body[i:])[0])
+	requireT.NoError(err)
+	requireT.Equal("/api/faucet/v1/email/verify", link.Path)
+
+	rec = send(nethttp.MethodPost, "/api/faucet/v1/fund", `{"address":"`+contractAddress+`"}`)
+	requireT.Equal(nethttp.StatusForbidden, rec.Code)
+	requireT.Contains(rec.Body.String(), "email.verification_failed")
+
+	rec = send(nethttp.MethodGet, link.RequestURI(), "")
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+	var funding EmailVerifyResponse
+	requireT.NoError(json.Unmarshal(rec.Body.Bytes(), &funding))
+	requireT.Equal(contractAddress, funding.Address)
+	requireT.NotEmpty(funding.TxHash)
+
+	rec = send(nethttp.MethodPost, "/api/faucet/v1/email/verify", `{"token":"`+link.Query().Get("token")+`"}`)
+	requireT.Equal(nethttp.StatusForbidden, rec.Code)
+	requireT.Contains(rec.Body.String(), "email.verification_failed")
+}
+
+type capturedSMS struct {
+	sent []string
+}
+
+func (c *capturedSMS) SendSMS(ctx context.Context, to, body string) error {
+	c.sent = append(c.sent, body)
+	return nil
+}
+
+func TestPhoneVerification(t *testing.T) {
+	requireT := require.New(t)
+
+	db, err := store.Open(filepath.Join(t.TempDir(), "phone.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	messages := &capturedSMS{}
+	handler, _ := newContractServer(t, func(a app.App) app.App {
+		a, err := a.WithPhoneVerification(app.PhoneVerificationConfig{
+			HashKey:     "0123456789abcdef",
+			CodeTTL:     10 * time.Minute,
+			Quota:       1,
+			QuotaPeriod: 24 * time.Hour,
+		}, db, db, messages)
+		requireT.NoError(err)
+		return a
+	})
+	send := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(nethttp.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send("/api/faucet/v1/phone/verifications", `{"address":"`+contractAddress+`","phone":"14155552671"}`)
+	requireT.Equal(nethttp.StatusBadRequest, rec.Code)
+	requireT.Contains(rec.Body.String(), "phone.invalid")
+	rec = send("/api/faucet/v1/phone/verifications", `{"address":"`+contractAddress+`","phone":"+14155552671"}`)
+	requireT.Equal(nethttp.StatusAccepted, rec.Code, rec.Body.String())
+	var request PhoneVerificationResponse
+	requireT.NoError(json.Unmarshal(rec.Body.Bytes(), &request))
+	requireT.NotEmpty(request.ID)
+	requireT.Len(messages.sent, 1)
+	code := regexp.MustCompile(`\d{6}`).FindString(messages.sent[0])
+
+	rec = send("/api/faucet/v1/fund", `{"address":"`+contractAddress+`"}`)
+	requireT.Equal(nethttp.StatusForbidden, rec.Code)
+	requireT.Contains(rec.Body.String(), "phone.verification_failed")
+
+	rec = send("/api/faucet/v1/fund",
+		`{"address":"`+contractAddress+`","verification":{"phone":"`+request.ID+`.`+code+`"}}`)
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestFundingLinks(t *testing.T) {
+	requireT := require.New(t)
+
+	db, err := store.Open(filepath.Join(t.TempDir(), "links.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	handler, _ := newContractServer(t, func(a app.App) app.App {
+		a, err := a.WithFundingLinks(app.FundingLinkConfig{
+			SigningKey: "0123456789abcdef",
+			LinkURL:    "https://faucet.example.com/redeem",
+		}, db)
+		requireT.NoError(err)
+		return a
+	})
+	send := func(path, body string, admin bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(nethttp.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if admin {
+			req.Header.Set("Authorization", "Bearer "+contractAdminToken)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	expiresAt := contractNow.Add(time.Hour).Format(time.RFC3339)
+	rec := send("/api/faucet/v1/admin/funding-links", `{"amount":"500","expiresAt":"`+expiresAt+`"}`, false)
+	requireT.Equal(nethttp.StatusUnauthorized, rec.Code)
+	rec = send("/api/faucet/v1/admin/funding-links", `{"amount":"abc","expiresAt":"`+expiresAt+`"}`, true)
+	requireT.Equal(nethttp.StatusBadRequest, rec.Code)
+	rec = send("/api/faucet/v1/admin/funding-links", `{"amount":"500","count":2,"expiresAt":"`+expiresAt+`"}`, true)
+	requireT.Equal(nethttp.StatusCreated, rec.Code, rec.Body.String())
+	var links CreateFundingLinksResponse
+	requireT.NoError(json.Unmarshal(rec.Body.Bytes(), &links))
+	requireT.Len(links.FundingLinks, 2)
+	requireT.Equal("500udevcore", links.FundingLinks[0].Amount)
+
+	rec = send("/api/faucet/v1/funding-links/redeem",
+		`{"token":"`+links.FundingLinks[0].Token+`","address":"`+contractAddress+`"}`, false)
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+	var result RedeemFundingLinkResponse
+	requireT.NoError(json.Unmarshal(rec.Body.Bytes(), &result))
+	requireT.Equal(contractAddress, result.Address)
+	requireT.Equal("500udevcore", result.Amount)
+	requireT.NotEmpty(result.TxHash)
+
+	rec = send("/api/faucet/v1/funding-links/redeem",
+		`{"token":"`+links.FundingLinks[0].Token+`","address":"`+contractAddress+`"}`, false)
+	requireT.Equal(nethttp.StatusConflict, rec.Code)
+	requireT.Contains(rec.Body.String(), "funding_link.used")
+	rec = send("/api/faucet/v1/funding-links/redeem", `{"token":"forged.00","address":"`+contractAddress+`"}`, false)
+	requireT.Equal(nethttp.StatusForbidden, rec.Code)
+	requireT.Contains(rec.Body.String(), "funding_link.rejected")
+}
+
+type fixedBalances struct {
+	balance chain.Int
+}
+
+func (b fixedBalances) Balance(ctx context.Context, address chain.AccAddress, denom string) (chain.Int, error) {
+	return b.balance, nil
+}
+
+func TestTransparency(t *testing.T) {
+	requireT := require.New(t)
+
+	handler, _ := newContractServer(t, func(a app.App) app.App {
+		funding := chain.AccAddress(make([]byte, 20))
+		return a.WithTransparency(fixedBalances{balance: chain.NewInt(5000000)}, []chain.AccAddress{funding})
+	})
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(nethttp.MethodPost, "/api/faucet/v1/fund", `{"address":"`+contractAddress+`"}`)
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+
+	rec = send(nethttp.MethodGet, "/api/faucet/v1/transparency", "")
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+	var transparency TransparencyResponse
+	requireT.NoError(json.Unmarshal(rec.Body.Bytes(), &transparency))
+	requireT.Equal("1000000udevcore", transparency.Distributed)
+	requireT.Equal(1, transparency.Grants)
+	requireT.NotNil(transparency.Balance)
+	requireT.Equal("5000000", transparency.Balance.Amount)
+	requireT.Equal(contractNow, transparency.StartedAt)
+	// nothing related to the recipients is published
+	requireT.NotContains(rec.Body.String(), contractAddress)
+}
+
+func TestRequesterTenant(t *testing.T) {
+	requireT := require.New(t)
+
+	e := echo.New()
+	newContext := func(set func(c echo.Context)) echo.Context {
+		req := httptest.NewRequest(nethttp.MethodPost, "/api/faucet/v1/fund", nil)
+		req.RemoteAddr = "203.0.113.5:1234"
+		req.Header.Set("X-Faucet-Tenant", "victim")
+		req.Header.Set(HeaderXFaucetSession, "workshop")
+		c := e.NewContext(req, httptest.NewRecorder())
+		set(c)
+		return c
+	}
+
+	// anonymous clients can't charge other tenants
+	requester, err := requesterFromContext(newContext(func(c echo.Context) {}))
+	requireT.NoError(err)
+	requireT.Empty(requester.Tenant)
+	requireT.Empty(requester.Session)
+
+	requester, err = requesterFromContext(newContext(func(c echo.Context) {
+		c.Set(contextKeyAPIKeyHolder, "ci")
+	}))
+	requireT.NoError(err)
+	requireT.Equal("ci", requester.Tenant)
+	requireT.Equal("workshop", requester.Session)
+
+	requester, err = requesterFromContext(newContext(func(c echo.Context) {
+		c.Set(contextKeyBypassToken, app.BypassToken{ID: "1", Holder: "loadtest"})
+	}))
+	requireT.NoError(err)
+	requireT.Equal("loadtest", requester.Tenant)
+	requireT.Equal("1", requester.BypassTokenID)
+}
+
+// graceLimiter allows the fixed number of requests.
+type graceLimiter struct {
+	remaining uint64
+}
+
+func (l *graceLimiter) IsRequestAllowed(ip net.IP) bool {
+	if l.remaining == 0 {
+		return false
+	}
+	l.remaining--
+	return true
+}
+
+func (l *graceLimiter) NextAllowedAt(ip net.IP) time.Time {
+	return contractNow.Add(time.Hour)
+}
+
+func (l *graceLimiter) Remaining(ip net.IP) uint64 {
+	return l.remaining
+}
+
+func TestFirstTimeGrace(t *testing.T) {
+	requireT := require.New(t)
+
+	db, err := store.Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		requireT.NoError(db.Close())
+	})
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	sdkConfigOnce.Do(network.SetSDKConfig)
+
+	txTracker := app.NewTxTracker(contractChain{}, 1, nil, clock.System{})
+	a := app.New(&contractBatcher{txTracker: txTracker}, contractChain{}, txTracker, db, db, network,
+		chain.NewCoin(network.Denom(), chain.NewInt(1000000))).
+		WithClock(clock.NewManual(contractNow)).
+		WithLifetimeCap(db, chain.Coin{})
+	h := New(a, contractLimiter{}, Config{
+		FirstTimeLimiter: &graceLimiter{remaining: 1},
+	}, zaptest.NewLogger(t))
+	h.registerRoutes()
+
+	fund := func(address string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(nethttp.MethodPost, "/api/faucet/v1/fund",
+			strings.NewReader(`{"address":"`+address+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = throttledIP + ":1234"
+		rec := httptest.NewRecorder()
+		h.server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// the IP has used its rate limit, but the address has never been funded
+	rec := fund(contractAddress)
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+
+	// repeat requester is limited strictly
+	rec = fund(contractAddress)
+	requireT.Equal(nethttp.StatusTooManyRequests, rec.Code)
+	requireT.Contains(rec.Body.String(), "server.rate_limit")
+
+	// first-time limit is used too
+	rec = fund("devcore1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqp09pnng")
+	requireT.Equal(nethttp.StatusTooManyRequests, rec.Code)
+
+	// gen-funded address is always new, so it gets no grace
+	req := httptest.NewRequest(nethttp.MethodPost, "/api/faucet/v1/gen-funded", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = throttledIP + ":1234"
+	rec = httptest.NewRecorder()
+	h.server.ServeHTTP(rec, req)
+	requireT.Equal(nethttp.StatusTooManyRequests, rec.Code)
+}
+
+type solvedCaptcha struct{}
+
+func (solvedCaptcha) VerifyCaptcha(_ context.Context, token, _ string) error {
+	if token != "solved" {
+		return errors.WithStack(app.ErrCaptchaFailed)
+	}
+	return nil
+}
+
+func TestIPReputationCaptcha(t *testing.T) {
+	requireT := require.New(t)
+
+	db, err := store.Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		requireT.NoError(db.Close())
+	})
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	sdkConfigOnce.Do(network.SetSDKConfig)
+
+	txTracker := app.NewTxTracker(contractChain{}, 1, nil, clock.System{})
+	a := app.New(&contractBatcher{txTracker: txTracker}, contractChain{}, txTracker, db, db, network,
+		chain.NewCoin(network.Denom(), chain.NewInt(1000000))).
+		WithClock(clock.NewManual(contractNow)).
+		WithFlaggedCaptcha(solvedCaptcha{})
+	h := New(a, contractLimiter{}, Config{
+		IPReputation:       contractReputation{},
+		IPReputationAction: iprep.ActionCaptcha,
+	}, zaptest.NewLogger(t))
+	h.registerRoutes()
+
+	fund := func(ip, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(nethttp.MethodPost, "/api/faucet/v1/fund", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		h.server.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// clean IP is not asked for captcha
+	rec := fund("192.0.2.1", `{"address":"`+contractAddress+`"}`)
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+
+	rec = fund(torExitIP, `{"address":"`+contractAddress+`"}`)
+	requireT.Equal(nethttp.StatusForbidden, rec.Code, rec.Body.String())
+	requireT.Contains(rec.Body.String(), "captcha")
+
+	rec = fund(torExitIP, `{"address":"`+contractAddress+`","captcha_token":"solved"}`)
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+}
+
+// leakingSigner fails embedding the mnemonic in the error, as careless code would.
+type leakingSigner struct{}
+
+func (leakingSigner) SignSelfSend(_ context.Context, mnemonic secret.String, _ chain.Coin) ([]byte, error) {
+	return nil, errors.Errorf("signing by %v failed, mnemonic: %s", mnemonic, mnemonic)
+}
+
+func TestGenFundedMnemonicNotLogged(t *testing.T) {
+	requireT := require.New(t)
+
+	db, err := store.Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		requireT.NoError(db.Close())
+	})
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	sdkConfigOnce.Do(network.SetSDKConfig)
+
+	// logs are encoded the same way as by the running faucet
+	logs := &bytes.Buffer{}
+	log := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.Lock(zapcore.AddSync(logs)), zapcore.DebugLevel))
+
+	txTracker := app.NewTxTracker(contractChain{}, 1, nil, clock.System{})
+	a := app.New(&contractBatcher{txTracker: txTracker}, contractChain{}, txTracker, db, db, network,
+		chain.NewCoin(network.Denom(), chain.NewInt(1000000))).
+		WithClock(clock.NewManual(contractNow)).
+		WithExampleTxSigner(leakingSigner{})
+	h := New(a, contractLimiter{}, Config{}, log)
+	h.registerRoutes()
+
+	req := httptest.NewRequest(nethttp.MethodPost, "/api/faucet/v1/gen-funded", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.server.ServeHTTP(rec, req)
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+
+	var resp GenFundedResponse
+	requireT.NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	requireT.Len(strings.Fields(resp.Mnemonic), 24)
+	requireT.NoError(log.Sync())
+
+	// the error of the signer is logged, but the mnemonic in it is redacted
+	requireT.Contains(logs.String(), "Signing example transaction failed")
+	requireT.Contains(logs.String(), secret.Redacted)
+	words := strings.Fields(resp.Mnemonic)
+	for i := 0; i+2 < len(words); i++ {
+		requireT.NotContains(logs.String(), strings.Join(words[i:i+3], " "))
+	}
+}
+
+type recordingVerifier struct {
+	requesters []app.Requester
+}
+
+func (v *recordingVerifier) Name() string {
+	return "pow"
+}
+
+func (v *recordingVerifier) Verify(ctx context.Context, proof string, requester app.Requester) error {
+	v.requesters = append(v.requesters, requester)
+	return nil
+}
+
+type allowedCallbacks struct{}
+
+func (allowedCallbacks) ValidateCallbackURL(rawURL string) error {
+	return nil
+}
+
+func TestFundProtobuf(t *testing.T) {
+	requireT := require.New(t)
+
+	verifier := &recordingVerifier{}
+	handler, _ := newContractServer(t, func(a app.App) app.App {
+		return a.WithCallbacks(allowedCallbacks{}).WithVerifiers(verifier)
+	})
+	send := func(msg *pb.FundRequest) *httptest.ResponseRecorder {
+		body, err := proto.Marshal(msg)
+		requireT.NoError(err)
+		req := httptest.NewRequest(nethttp.MethodPost, "/api/faucet/v1/fund", bytes.NewReader(body))
+		req.Header.Set("Content-Type", http.MIMEApplicationProtobuf)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(&pb.FundRequest{
+		Address:          contractAddress,
+		CaptchaToken:     "token",
+		Verification:     map[string]string{"pow": "nonce"},
+		CallbackUrl:      "https://hooks.example.com/done",
+		MinConfirmations: 2,
+	})
+	requireT.Equal(nethttp.StatusOK, rec.Code, rec.Body.String())
+	requireT.Equal(http.MIMEApplicationProtobuf, rec.Header().Get("Content-Type"))
+	var resp pb.FundResponse
+	requireT.NoError(proto.Unmarshal(rec.Body.Bytes(), &resp))
+	requireT.Equal(contractTxHash, resp.TxHash)
+	requireT.Empty(resp.InvoiceHash)
+
+	requireT.Len(verifier.requesters, 1)
+	requester := verifier.requesters[0]
+	requireT.Equal(app.Proofs{app.ProofCaptcha: "token", "pow": "nonce"}, requester.Proofs)
+	requireT.Equal("https://hooks.example.com/done", requester.CallbackURL)
+	requireT.Equal(int64(2), requester.MinConfirmations)
+
+	// invoice ID reaches the app, which accepts it from the API key holders only
+	rec = send(&pb.FundRequest{Address: contractAddress, InvoiceId: "INV-1001"})
+	requireT.Equal(nethttp.StatusUnauthorized, rec.Code, rec.Body.String())
+	requireT.Contains(rec.Body.String(), app.ErrInvoiceIDUnauthorized.Error())
+}
+
+func TestUnixSocketRateLimit(t *testing.T) {
+	requireT := require.New(t)
+
+	network, err := chain.NetworkByChainID(chain.ChainIDDev)
+	requireT.NoError(err)
+	db, err := store.Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	txTracker := app.NewTxTracker(contractChain{}, 1, nil, clock.System{})
+	a := app.New(&contractBatcher{txTracker: txTracker}, contractChain{}, txTracker, db, db, network,
+		chain.NewCoin(network.Denom(), chain.NewInt(1000000)))
+	clk := clock.NewManual(contractNow)
+	ipLimiter := limiter.NewRateLimiter(ratelimit.NewFixedWindow(ratelimit.Rule{Limit: 1, Period: time.Hour},
+		ratelimit.NewMemoryStore(clk), clk))
+	h := New(a, ipLimiter, Config{}, zaptest.NewLogger(t))
+	h.registerRoutes()
+
+	path := filepath.Join(t.TempDir(), "faucet.sock")
+	unix, err := net.Listen("unix", path)
+	requireT.NoError(err)
+	srv := httptest.NewUnstartedServer(h.server)
+	srv.Listener = http.UnixListener(unix)
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	client := &nethttp.Client{Transport: &nethttp.Transport{
+		Dial: func(_, _ string) (net.Conn, error) {
+			return net.Dial("unix", path)
+		},
+	}}
+	fund := func(forwardedFor string) int {
+		req, err := nethttp.NewRequest(nethttp.MethodPost, "http://faucet/api/faucet/v1/fund",
+			strings.NewReader(`{"address":"`+contractAddress+`"}`))
+		requireT.NoError(err)
+		req.Header.Set("Content-Type", "application/json")
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		resp, err := client.Do(req)
+		requireT.NoError(err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// clients forwarded by the proxy are limited by their IPs
+	requireT.Equal(nethttp.StatusOK, fund("203.0.113.7"))
+	requireT.Equal(nethttp.StatusTooManyRequests, fund("203.0.113.7"))
+	requireT.Equal(nethttp.StatusOK, fund("203.0.113.8"))
+
+	// requests without the forwarded IP share one limit instead of bypassing it
+	requireT.Equal(nethttp.StatusOK, fund(""))
+	requireT.Equal(nethttp.StatusTooManyRequests, fund(""))
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	nethttp "net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// IdentitySessionResponse is the output to /auth/:provider/callback request.
+type IdentitySessionResponse struct {
+	// Token is passed as `identity` verification of the funding requests.
+	Token     string    `json:"token"`
+	Provider  string    `json:"provider"`
+	Account   string    `json:"account"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func (h HTTP) identityLoginHandle(ctx http.Context) error {
+	loginURL, err := h.app.IdentityLoginURL(ctx.Param("provider"))
+	if err != nil {
+		return err
+	}
+	return ctx.Redirect(nethttp.StatusFound, loginURL)
+}
+
+func (h HTTP) identityCallbackHandle(ctx http.Context) error {
+	if reason := ctx.QueryParam("error"); reason != "" {
+		return errors.Wrapf(app.ErrIdentityFailed, "authorization denied: %s", reason)
+	}
+	session, err := h.app.CompleteIdentityLogin(ctx.Request().Context(), ctx.Param("provider"),
+		ctx.QueryParam("code"), ctx.QueryParam("state"))
+	if err != nil {
+		return err
+	}
+	if h.cfg.IdentityReturnURL == "" {
+		return ctx.JSON(nethttp.StatusOK, IdentitySessionResponse{
+			Token:     session.Token,
+			Provider:  session.Identity.Provider,
+			Account:   session.Identity.Name,
+			ExpiresAt: session.ExpiresAt,
+		})
+	}
+	// the token is passed in the fragment, so it is not sent to the server hosting the UI nor logged by it
+	fragment := url.Values{
+		"identity_token": {session.Token},
+		"provider":       {session.Identity.Provider},
+		"account":        {session.Identity.Name},
+		"expires_at":     {strconv.FormatInt(session.ExpiresAt.Unix(), 10)},
+	}
+	return ctx.Redirect(nethttp.StatusFound, h.cfg.IdentityReturnURL+"#"+fragment.Encode())
+}
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/pkg/http"
+	"github.com/CoreumFoundation/faucet/pkg/iplist"
+)
+
+// ipFilterMiddleware refuses the funding requests of the IPs denied by the filter. Requests authenticated by
+// the API key or the admin token are sent by automation, so they are exempt.
+func (h HTTP) ipFilterMiddleware(filter *iplist.Filter) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(c http.Context) error {
+			if filter == nil {
+				return next(c)
+			}
+			if _, ok := apiKeyHolder(c); ok || h.adminAuthorized(c) {
+				return next(c)
+			}
+			ip, err := http.IPFromRequest(c.Request())
+			if err != nil {
+				return err
+			}
+			if !filter.Allowed(ip) {
+				err := errors.Wrapf(ErrIPDenied, "ip %q is not allowed", ip.String())
+				if requester, rErr := requesterFromContext(c); rErr == nil {
+					h.app.ReportBlocked(c.Request().Context(), requester, "", err)
+				}
+				return err
+			}
+			return next(c)
+		}
+	}
+}
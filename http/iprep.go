@@ -0,0 +1,59 @@
+package http
+
+import (
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+	"github.com/CoreumFoundation/faucet/pkg/iprep"
+)
+
+const contextKeyIPReputation = "ipReputation"
+
+// ipReputationMiddleware refuses the funding requests of the IPs flagged by the provider or, if the action
+// is captcha or score, marks them, so the captcha is required from them or the flag is weighted by the abuse
+// score. Provider failures are logged and the request is accepted, so the outage of the reputation service
+// doesn't stop the faucet. Requests from private ranges, the ones authenticated by the API key or the admin token
+// are exempt.
+func (h HTTP) ipReputationMiddleware(
+	provider iprep.Provider,
+	action iprep.Action,
+) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(c http.Context) error {
+			if provider == nil {
+				return next(c)
+			}
+			if _, ok := apiKeyHolder(c); ok || h.adminAuthorized(c) {
+				return next(c)
+			}
+			ip, err := http.IPFromRequest(c.Request())
+			if err != nil {
+				return err
+			}
+			if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+				return next(c)
+			}
+			ctx := c.Request().Context()
+			category, err := provider.Category(ctx, ip)
+			if err != nil {
+				logger.Get(ctx).Warn("Checking IP reputation failed", zap.String("ip", ip.String()), zap.Error(err))
+				return next(c)
+			}
+			if category == "" {
+				return next(c)
+			}
+			h.metrics.ipReputation.WithLabelValues(string(category), string(action)).Inc()
+			if action == iprep.ActionCaptcha || action == iprep.ActionScore {
+				c.Set(contextKeyIPReputation, category)
+				return next(c)
+			}
+			err = errors.Wrapf(ErrIPReputation, "ip %q is flagged as %s", ip.String(), category)
+			if requester, rErr := requesterFromContext(c); rErr == nil {
+				h.app.ReportBlocked(ctx, requester, "", err)
+			}
+			return err
+		}
+	}
+}
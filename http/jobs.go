@@ -0,0 +1,83 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/CoreumFoundation/faucet/pkg/http"
+	"github.com/CoreumFoundation/faucet/scheduler"
+)
+
+// JobRunResponse describes a single run of the background job.
+type JobRunResponse struct {
+	StartedAt time.Time `json:"startedAt"`
+	Duration  string    `json:"duration"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// JobResponse describes the background job.
+type JobResponse struct {
+	Name          string           `json:"name"`
+	Interval      string           `json:"interval"`
+	Running       bool             `json:"running"`
+	Runs          uint64           `json:"runs"`
+	Failures      uint64           `json:"failures"`
+	LastRunAt     *time.Time       `json:"lastRunAt,omitempty"`
+	LastSuccessAt *time.Time       `json:"lastSuccessAt,omitempty"`
+	LastError     string           `json:"lastError,omitempty"`
+	LastErrorAt   *time.Time       `json:"lastErrorAt,omitempty"`
+	NextRunAt     *time.Time       `json:"nextRunAt,omitempty"`
+	History       []JobRunResponse `json:"history"`
+}
+
+// JobsResponse is the output to /admin/jobs request.
+type JobsResponse struct {
+	Jobs []JobResponse `json:"jobs"`
+}
+
+func (h HTTP) jobsHandle(ctx http.Context) error {
+	resp := JobsResponse{Jobs: []JobResponse{}}
+	for _, status := range h.cfg.Scheduler.Jobs() {
+		resp.Jobs = append(resp.Jobs, jobResponse(status))
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
+
+// runJobHandle triggers the job, it runs in the background, so the outcome is reported by /admin/jobs.
+func (h HTTP) runJobHandle(ctx http.Context) error {
+	if err := h.cfg.Scheduler.Trigger(ctx.Param("name")); err != nil {
+		return err
+	}
+	return ctx.NoContent(nethttp.StatusAccepted)
+}
+
+func jobResponse(status scheduler.Status) JobResponse {
+	resp := JobResponse{
+		Name:          status.Name,
+		Interval:      status.Interval.String(),
+		Running:       status.Running,
+		Runs:          status.Runs,
+		Failures:      status.Failures,
+		LastRunAt:     optionalTime(status.LastRunAt),
+		LastSuccessAt: optionalTime(status.LastSuccessAt),
+		LastError:     status.LastError,
+		LastErrorAt:   optionalTime(status.LastErrorAt),
+		NextRunAt:     optionalTime(status.NextRunAt),
+		History:       []JobRunResponse{},
+	}
+	for _, run := range status.History {
+		resp.History = append(resp.History, JobRunResponse{
+			StartedAt: run.StartedAt,
+			Duration:  run.Duration.String(),
+			Error:     run.Error,
+		})
+	}
+	return resp
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -1,30 +1,114 @@
 package http
 
 import (
-	nethttp "net/http"
-
 	"github.com/pkg/errors"
 
+	"github.com/CoreumFoundation/faucet/app"
 	"github.com/CoreumFoundation/faucet/pkg/http"
-	"github.com/CoreumFoundation/faucet/pkg/limiter"
 )
 
-func limiterMiddleware(limiter limiter.PerIPLimiter) func(http.HandlerFunc) http.HandlerFunc {
+// contextKeyIPThrottled holds the error the request over the IP rate limit is refused with, unless it is granted
+// the grace for the first-time address.
+const contextKeyIPThrottled = "ipThrottled"
+
+// limiterMiddleware rejects requests of IPs exceeding the rate limit, reporting them to the app. Requests
+// authenticated with the API key are limited by the quota of the key holder instead, the ones carrying the bypass
+// token by the quota of the token. Requests coming from private and exempt ranges are not limited. IPs assigned
+// to the experiment group are limited by the experiment limiter, if it is configured. Requests allowed by the IP
+// limit are limited by the subnet limit too, so rotating the IPs within the same network doesn't bypass the limit.
+// If grace is set and the first-time limiter is configured, requests over the IP limit are passed to the handler,
+// which lets them through only if they fund the address never funded before, see checkFirstTimeGrace.
+func (h HTTP) limiterMiddleware(grace bool) func(http.HandlerFunc) http.HandlerFunc {
 	return func(next http.HandlerFunc) http.HandlerFunc {
 		return func(c http.Context) error {
-			r := c.Request()
-			if r.Method == nethttp.MethodGet {
-				return next(c)
-			}
-
-			ip, err := http.IPFromRequest(r)
+			err := h.checkLimits(c, grace)
 			if err != nil {
+				if requester, rErr := requesterFromContext(c); rErr == nil {
+					h.app.ReportBlocked(c.Request().Context(), requester, "", err)
+				}
 				return err
 			}
-			if !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() && !limiter.IsRequestAllowed(ip) {
-				return errors.Wrapf(ErrRateLimitExhausted, "ip %q has already used its rate limit", ip.String())
-			}
 			return next(c)
 		}
 	}
 }
+
+func (h HTTP) checkLimits(c http.Context, grace bool) error {
+	if raw := c.Request().Header.Get(HeaderXFaucetToken); raw != "" {
+		token, err := h.app.UseBypassToken(c.Request().Context(), raw)
+		if err != nil {
+			return err
+		}
+		c.Set(contextKeyBypassToken, token)
+		h.metrics.bypassTokenRequests.WithLabelValues(token.Holder).Inc()
+		return nil
+	}
+	if holder, ok := apiKeyHolder(c); ok {
+		if allowed, allowedAt := h.cfg.APIKeys.Quota.Consume(holder); !allowed {
+			return app.ThrottledError{
+				Cause:           errors.Wrapf(ErrQuotaExhausted, "API key of %q has already used its quota", holder),
+				NextAvailableAt: allowedAt.UTC(),
+			}
+		}
+		return nil
+	}
+
+	ip, err := http.IPFromRequest(c.Request())
+	if err != nil {
+		return err
+	}
+	if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+		return nil
+	}
+	if exemption := h.cfg.RateLimitExemptions.Match(ip); exemption != nil {
+		h.metrics.rateLimitExemptions.WithLabelValues(exemption.String()).Inc()
+		return nil
+	}
+	ipLimiter := h.limiter
+	if h.cfg.ExperimentLimiter != nil && h.app.Experiment().Variant(ip.String()) == app.VariantExperiment {
+		ipLimiter = h.cfg.ExperimentLimiter
+	}
+	if !ipLimiter.IsRequestAllowed(ip) {
+		throttled := app.ThrottledError{
+			Cause:           errors.Wrapf(ErrRateLimitExhausted, "ip %q has already used its rate limit", ip.String()),
+			NextAvailableAt: ipLimiter.NextAllowedAt(ip).UTC(),
+		}
+		if !grace || h.cfg.FirstTimeLimiter == nil {
+			return throttled
+		}
+		c.Set(contextKeyIPThrottled, throttled)
+	}
+	if subnetLimiter := h.cfg.SubnetLimiter; subnetLimiter != nil && !subnetLimiter.IsRequestAllowed(ip) {
+		return app.ThrottledError{
+			Cause: errors.Wrapf(ErrRateLimitExhausted, "subnet %q has already used its rate limit",
+				subnetLimiter.Subnet(ip).String()),
+			NextAvailableAt: subnetLimiter.NextAllowedAt(ip).UTC(),
+		}
+	}
+	return nil
+}
+
+// checkFirstTimeGrace returns the error of the IP rate limit if the request is over it, unless the address has never
+// been funded before and the IP has not used its first-time limit yet. Repeat requesters stay strictly limited.
+func (h HTTP) checkFirstTimeGrace(c http.Context, address string) error {
+	throttled, ok := c.Get(contextKeyIPThrottled).(app.ThrottledError)
+	if !ok {
+		return nil
+	}
+	firstTime, err := h.app.FirstTimeAddress(c.Request().Context(), address)
+	if err != nil {
+		return err
+	}
+	if !firstTime {
+		return throttled
+	}
+	ip, err := http.IPFromRequest(c.Request())
+	if err != nil {
+		return err
+	}
+	if !h.cfg.FirstTimeLimiter.IsRequestAllowed(ip) {
+		return throttled
+	}
+	h.metrics.firstTimeGrace.Inc()
+	return nil
+}
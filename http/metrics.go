@@ -0,0 +1,62 @@
+package http
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// metrics are exposed in prometheus format at /metrics.
+type metrics struct {
+	registry            *prometheus.Registry
+	rateLimitExemptions *prometheus.CounterVec
+	experimentRequests  *prometheus.CounterVec
+	bypassTokenRequests *prometheus.CounterVec
+	firstTimeGrace      prometheus.Counter
+	ipReputation        *prometheus.CounterVec
+}
+
+// newMetrics returns the metrics registering also the collectors of other components, e.g. the batcher.
+func newMetrics(components ...prometheus.Collector) metrics {
+	m := metrics{
+		registry: prometheus.NewRegistry(),
+		rateLimitExemptions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "faucet_rate_limit_exemptions_total",
+			Help: "Number of requests exempted from the IP rate limit, by exempt CIDR",
+		}, []string{"cidr"}),
+		experimentRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "faucet_experiment_requests_total",
+			Help: "Number of funding requests by experiment group and outcome",
+		}, []string{"experiment", "variant", "outcome"}),
+		bypassTokenRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "faucet_bypass_token_requests_total",
+			Help: "Number of requests exempted from the rate limits by the bypass token, by token holder",
+		}, []string{"holder"}),
+		firstTimeGrace: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "faucet_first_time_grace_total",
+			Help: "Number of requests over the IP rate limit let through to fund addresses never funded before",
+		}),
+		ipReputation: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "faucet_ip_reputation_flagged_total",
+			Help: "Number of funding requests of the IPs flagged by the IP reputation, by category and action",
+		}, []string{"category", "action"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.rateLimitExemptions,
+		m.experimentRequests,
+		m.bypassTokenRequests,
+		m.firstTimeGrace,
+		m.ipReputation,
+	)
+	m.registry.MustRegister(components...)
+	return m
+}
+
+func (m metrics) handler() http.HandlerFunc {
+	return echo.WrapHandler(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+}
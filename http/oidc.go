@@ -0,0 +1,40 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/oidc"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// oidcMiddleware refuses the funding requests not carrying the bearer token of the OIDC provider. Requests
+// authenticated by the API key or the admin token are sent by automation, so they are exempt.
+func (h HTTP) oidcMiddleware(verifier *oidc.Verifier) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(c http.Context) error {
+			if verifier == nil {
+				return next(c)
+			}
+			if _, ok := apiKeyHolder(c); ok || h.adminAuthorized(c) {
+				return next(c)
+			}
+			authorization := c.Request().Header.Get(echo.HeaderAuthorization)
+			token := strings.TrimPrefix(authorization, "Bearer ")
+			if token == authorization || token == "" {
+				return errors.Wrap(oidc.ErrInvalidToken, "bearer token is required")
+			}
+			claims, err := verifier.Verify(c.Request().Context(), token)
+			if err != nil {
+				return err
+			}
+			logger.Get(c.Request().Context()).Debug("Request authenticated by OIDC",
+				zap.String("subject", claims.Subject))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// OwnershipChallengeResponse is the output to /ownership/challenges request.
+type OwnershipChallengeResponse struct {
+	// Challenge is the data signed by the key of the recipient address as specified by ADR-36.
+	Challenge string    `json:"challenge"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func (h HTTP) createOwnershipChallengeHandle(ctx http.Context) error {
+	challenge, err := h.app.CreateOwnershipChallenge()
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusCreated, OwnershipChallengeResponse{
+		Challenge: challenge.Challenge,
+		ExpiresAt: challenge.ExpiresAt,
+	})
+}
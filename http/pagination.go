@@ -0,0 +1,41 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/pkg/http"
+	"github.com/CoreumFoundation/faucet/pkg/pagination"
+)
+
+// HeaderXNextCursor carries the cursor of the next page of the lists responded with CSV.
+const HeaderXNextCursor = "X-Next-Cursor"
+
+// listPage returns the page of the items selected by the query parameters shared by the list endpoints: `limit`,
+// `cursor` taken from `nextCursor` of the previous page, `sort` naming the field optionally prefixed by "-"
+// for descending order, and the filters of the spec, each being the query parameter of its name.
+func listPage[T any](ctx http.Context, spec pagination.Spec[T], items []T) (pagination.Page[T], error) {
+	q := pagination.Query{
+		Cursor:  ctx.QueryParam("cursor"),
+		Filters: map[string]string{},
+	}
+	if l := ctx.QueryParam("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit <= 0 {
+			return pagination.Page[T]{}, errors.Wrapf(ErrInvalidQuery, "invalid limit: %q", l)
+		}
+		q.Limit = limit
+	}
+	q.Sort, q.Desc = pagination.ParseSort(ctx.QueryParam("sort"))
+	for name := range spec.Filters {
+		if value := ctx.QueryParam(name); value != "" {
+			q.Filters[name] = value
+		}
+	}
+	page, err := spec.Apply(items, q)
+	if err != nil {
+		return pagination.Page[T]{}, errors.Wrap(ErrInvalidQuery, err.Error())
+	}
+	return page, nil
+}
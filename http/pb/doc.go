@@ -0,0 +1,5 @@
+// Package pb contains types generated from proto/faucet/v1/faucet.proto, used by clients exchanging
+// protobuf-encoded bodies with the faucet.
+package pb
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=github.com/CoreumFoundation/faucet faucet/v1/faucet.proto
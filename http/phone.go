@@ -0,0 +1,39 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// PhoneVerificationRequest is the input to /phone/verifications request, the phone number is in E.164 format.
+type PhoneVerificationRequest struct {
+	Address string `json:"address"`
+	Phone   string `json:"phone"`
+}
+
+// PhoneVerificationResponse is the output to /phone/verifications request. The ID and the code sent by SMS,
+// joined by ".", are passed to the fund request as the `phone` verification.
+type PhoneVerificationResponse struct {
+	ID        string    `json:"id"`
+	Address   string    `json:"address"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func (h HTTP) requestPhoneVerificationHandle(ctx http.Context) error {
+	var rqBody PhoneVerificationRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+
+	request, err := h.app.RequestPhoneVerification(ctx.Request().Context(), rqBody.Address, rqBody.Phone)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusAccepted, PhoneVerificationResponse{
+		ID:        request.ID,
+		Address:   request.Address,
+		ExpiresAt: request.ExpiresAt,
+	})
+}
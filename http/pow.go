@@ -0,0 +1,33 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// powAlgorithm tells the clients how the solution is checked.
+const powAlgorithm = "sha256"
+
+// PoWChallengeResponse is the output to /pow/challenges request.
+type PoWChallengeResponse struct {
+	Challenge string `json:"challenge"`
+	// Difficulty is the number of leading zero bits required in the hash of <challenge>:<solution>.
+	Difficulty int       `json:"difficulty"`
+	Algorithm  string    `json:"algorithm"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+func (h HTTP) createPoWChallengeHandle(ctx http.Context) error {
+	challenge, err := h.app.CreatePoWChallenge()
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusCreated, PoWChallengeResponse{
+		Challenge:  challenge.Challenge,
+		Difficulty: challenge.Difficulty,
+		Algorithm:  powAlgorithm,
+		ExpiresAt:  challenge.ExpiresAt,
+	})
+}
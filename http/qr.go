@@ -0,0 +1,56 @@
+package http
+
+import (
+	"encoding/base64"
+	nethttp "net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+	qrcode "github.com/skip2/go-qrcode"
+
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+const (
+	defaultQRSize = 256
+	minQRSize     = 64
+	maxQRSize     = 1024
+)
+
+// qrHandle renders the PNG image of the QR code encoding the address or its wallet deep link if link=true.
+func (h HTTP) qrHandle(ctx http.Context) error {
+	size := defaultQRSize
+	if s := ctx.QueryParam("size"); s != "" {
+		var err error
+		if size, err = strconv.Atoi(s); err != nil || size < minQRSize || size > maxQRSize {
+			return errors.Wrapf(ErrInvalidQuery, "size must be between %d and %d pixels", minQRSize, maxQRSize)
+		}
+	}
+	link := false
+	if s := ctx.QueryParam("link"); s != "" {
+		var err error
+		if link, err = strconv.ParseBool(s); err != nil {
+			return errors.Wrapf(ErrInvalidQuery, "invalid link: %s", err)
+		}
+	}
+
+	content, err := h.app.QRContent(ctx.QueryParam("address"), link)
+	if err != nil {
+		return err
+	}
+	png, err := qrcode.Encode(content, qrcode.Medium, size)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return ctx.Blob(nethttp.StatusOK, "image/png", png)
+}
+
+// qrDataURI returns the data URI of the PNG image of the QR code encoding the content, ready to be used
+// as the source of the image by the web page.
+func qrDataURI(content string) (string, error) {
+	png, err := qrcode.Encode(content, qrcode.Medium, defaultQRSize)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png), nil
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// Kinds of the limit applied to the caller.
+const (
+	callerLimitIP     = "ip"
+	callerLimitAPIKey = "api_key"
+	callerLimitNone   = "none"
+)
+
+// CallerQuotaResponse describes the requests the caller may still make.
+type CallerQuotaResponse struct {
+	// Limit is the limit applied to the caller: "ip", "api_key" or "none" if the caller is exempt.
+	Limit string `json:"limit"`
+	// Remaining is the number of requests allowed at once, it is not set if the caller is not limited.
+	Remaining *uint64 `json:"remaining,omitempty"`
+	// NextAllowedAt is the time the next request is allowed at, it is the time of the query if it is allowed already.
+	NextAllowedAt time.Time `json:"nextAllowedAt"`
+}
+
+// AddressQuotaResponse describes how much the address may still be funded.
+type AddressQuotaResponse struct {
+	Address string `json:"address"`
+	// Amount is the amount sent by the next request.
+	Amount string `json:"amount"`
+	// Remaining is the number of requests the address may still be funded by, it is not set if it is not limited.
+	Remaining *uint64 `json:"remaining,omitempty"`
+	// LifetimeRemaining is the amount the address may still receive, it is not set if there is no lifetime cap.
+	LifetimeRemaining string `json:"lifetimeRemaining,omitempty"`
+	Blocked           bool   `json:"blocked,omitempty"`
+	// NextAllowedAt is the time the address may be funded again, it is not set if it is never funded again.
+	NextAllowedAt *time.Time `json:"nextAllowedAt,omitempty"`
+}
+
+// QuotaResponse is the output to /quota request.
+type QuotaResponse struct {
+	Caller  CallerQuotaResponse   `json:"caller"`
+	Address *AddressQuotaResponse `json:"address,omitempty"`
+	// NextAllowedAt is the time the caller may request the funds for the address again, it is not set if never.
+	NextAllowedAt *time.Time `json:"nextAllowedAt,omitempty"`
+}
+
+func (h HTTP) quotaHandle(ctx http.Context) error {
+	caller, err := h.callerQuota(ctx)
+	if err != nil {
+		return err
+	}
+	nextAllowedAt := caller.NextAllowedAt
+	resp := QuotaResponse{Caller: caller, NextAllowedAt: &nextAllowedAt}
+
+	address := ctx.QueryParam("address")
+	if address == "" {
+		return ctx.JSON(nethttp.StatusOK, resp)
+	}
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	requester.Admin = h.adminAuthorized(ctx)
+	quota, err := h.app.AddressQuota(ctx.Request().Context(), requester, address)
+	if err != nil {
+		return err
+	}
+
+	resp.Address = &AddressQuotaResponse{
+		Address:   quota.Address,
+		Amount:    quota.Amount.String(),
+		Remaining: quota.Remaining,
+		Blocked:   quota.Blocked,
+	}
+	if quota.LifetimeRemaining != nil {
+		resp.Address.LifetimeRemaining = quota.LifetimeRemaining.String()
+	}
+	if quota.NextAllowedAt.IsZero() {
+		resp.NextAllowedAt = nil
+		return ctx.JSON(nethttp.StatusOK, resp)
+	}
+	resp.Address.NextAllowedAt = &quota.NextAllowedAt
+	if quota.NextAllowedAt.After(nextAllowedAt) {
+		resp.NextAllowedAt = &quota.NextAllowedAt
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
+
+// callerQuota returns the quota of the caller, following the same rules as limiterMiddleware without consuming
+// anything.
+func (h HTTP) callerQuota(c http.Context) (CallerQuotaResponse, error) {
+	now := h.app.Now()
+	if holder, ok := apiKeyHolder(c); ok {
+		resp := CallerQuotaResponse{Limit: callerLimitAPIKey, NextAllowedAt: now}
+		for _, u := range h.cfg.APIKeys.Quota.Usage(holder) {
+			remaining := u.Remaining
+			if resp.Remaining == nil || remaining < *resp.Remaining {
+				resp.Remaining = &remaining
+			}
+			if remaining == 0 && u.ResetsAt.After(resp.NextAllowedAt) {
+				resp.NextAllowedAt = u.ResetsAt.UTC()
+			}
+		}
+		return resp, nil
+	}
+
+	ip, err := http.IPFromRequest(c.Request())
+	if err != nil {
+		return CallerQuotaResponse{}, err
+	}
+	if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || h.cfg.RateLimitExemptions.Match(ip) != nil {
+		return CallerQuotaResponse{Limit: callerLimitNone, NextAllowedAt: now}, nil
+	}
+	ipLimiter := h.limiter
+	if h.cfg.ExperimentLimiter != nil && h.app.Experiment().Variant(ip.String()) == app.VariantExperiment {
+		ipLimiter = h.cfg.ExperimentLimiter
+	}
+	remaining := ipLimiter.Remaining(ip)
+	resp := CallerQuotaResponse{Limit: callerLimitIP, Remaining: &remaining, NextAllowedAt: now}
+	if remaining == 0 {
+		resp.NextAllowedAt = ipLimiter.NextAllowedAt(ip).UTC()
+	}
+	if subnetLimiter := h.cfg.SubnetLimiter; subnetLimiter != nil {
+		subnetRemaining := subnetLimiter.Remaining(ip)
+		if subnetRemaining < remaining {
+			remaining = subnetRemaining
+		}
+		if at := subnetLimiter.NextAllowedAt(ip).UTC(); subnetRemaining == 0 && at.After(resp.NextAllowedAt) {
+			resp.NextAllowedAt = at
+		}
+	}
+	return resp, nil
+}
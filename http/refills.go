@@ -0,0 +1,136 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	nethttp "net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// ProposeRefillRequest is the input to POST /admin/refills request.
+type ProposeRefillRequest struct {
+	// Amount is the comma-separated list of coins sent from the treasury to the faucet, e.g. "1000000000ucore".
+	Amount string `json:"amount"`
+}
+
+// RefillProposalResponse describes the refill proposal.
+type RefillProposalResponse struct {
+	ID        string   `json:"id"`
+	Treasury  string   `json:"treasury"`
+	Recipient string   `json:"recipient"`
+	Amount    string   `json:"amount"`
+	Threshold int      `json:"threshold"`
+	Signers   []string `json:"signers"`
+	SignedBy  []string `json:"signedBy"`
+	Status    string   `json:"status"`
+	// AccountNumber and Sequence are passed to `cored tx sign` together with UnsignedTx.
+	AccountNumber uint64          `json:"accountNumber"`
+	Sequence      uint64          `json:"sequence"`
+	UnsignedTx    json.RawMessage `json:"unsignedTx"`
+	TxHash        string          `json:"txHash,omitempty"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	BroadcastAt   *time.Time      `json:"broadcastAt,omitempty"`
+}
+
+// RefillProposalsResponse is the output to GET /admin/refills request.
+type RefillProposalsResponse struct {
+	Refills []RefillProposalResponse `json:"refills"`
+}
+
+func (h HTTP) refillsHandle(ctx http.Context) error {
+	proposals, err := h.app.RefillProposals(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+	resp := RefillProposalsResponse{Refills: []RefillProposalResponse{}}
+	for _, p := range proposals {
+		resp.Refills = append(resp.Refills, refillProposalResponse(p))
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
+
+func (h HTTP) proposeRefillHandle(ctx http.Context) error {
+	var rqBody ProposeRefillRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+	amount, err := chain.ParseCoinsNormalized(rqBody.Amount)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidRequest, "invalid amount: %s", err)
+	}
+	proposal, err := h.app.ProposeRefill(ctx.Request().Context(), amount)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusCreated, refillProposalResponse(proposal))
+}
+
+func (h HTTP) refillHandle(ctx http.Context) error {
+	proposal, err := h.app.RefillProposal(ctx.Request().Context(), ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, refillProposalResponse(proposal))
+}
+
+// refillTxHandle returns the unsigned transaction as is, so it may be saved to the file signed by `cored tx sign`.
+func (h HTTP) refillTxHandle(ctx http.Context) error {
+	proposal, err := h.app.RefillProposal(ctx.Request().Context(), ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+	return ctx.JSONBlob(nethttp.StatusOK, proposal.UnsignedTx)
+}
+
+// addRefillSignatureHandle accepts the output of `cored tx sign` as the request body.
+func (h HTTP) addRefillSignatureHandle(ctx http.Context) error {
+	signature, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !json.Valid(signature) {
+		return errors.Wrap(ErrInvalidRequest, "signature must be the JSON produced by `cored tx sign`")
+	}
+	proposal, err := h.app.AddRefillSignature(ctx.Request().Context(), ctx.Param("id"), signature)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, refillProposalResponse(proposal))
+}
+
+func (h HTTP) broadcastRefillHandle(ctx http.Context) error {
+	proposal, err := h.app.BroadcastRefill(ctx.Request().Context(), ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, refillProposalResponse(proposal))
+}
+
+func refillProposalResponse(p app.RefillProposal) RefillProposalResponse {
+	resp := RefillProposalResponse{
+		ID:            p.ID,
+		Treasury:      p.Treasury,
+		Recipient:     p.Recipient,
+		Amount:        p.Amount.String(),
+		Threshold:     p.Threshold,
+		Signers:       p.Signers,
+		SignedBy:      p.SignedBy(),
+		Status:        string(p.Status),
+		AccountNumber: p.AccountNumber,
+		Sequence:      p.Sequence,
+		UnsignedTx:    p.UnsignedTx,
+		TxHash:        p.TxHash,
+		CreatedAt:     p.CreatedAt,
+	}
+	if !p.BroadcastAt.IsZero() {
+		broadcastAt := p.BroadcastAt
+		resp.BroadcastAt = &broadcastAt
+	}
+	return resp
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	nethttp "net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+const (
+	maxBatchGetRequestIDs = 100
+	// requestStatusNotFound is reported for the requests which are unknown or whose transactions are not tracked
+	// anymore.
+	requestStatusNotFound = "not_found"
+)
+
+// BatchGetRequestsRequest is the input to /requests:batchGet request.
+type BatchGetRequestsRequest struct {
+	RequestIDs []string `json:"requestIds"`
+}
+
+// RequestStatusResponse is the status of the funding request.
+type RequestStatusResponse struct {
+	RequestID     string `json:"requestId"`
+	Status        string `json:"status"`
+	TxHash        string `json:"txHash,omitempty"`
+	Height        int64  `json:"height"`
+	Confirmations int64  `json:"confirmations"`
+	// RequiredConfirmations is the number of confirmations required to report the request as confirmed.
+	RequiredConfirmations int64 `json:"requiredConfirmations,omitempty"`
+}
+
+// BatchGetRequestsResponse is the output to /requests:batchGet request, statuses are in the order of the IDs.
+type BatchGetRequestsResponse struct {
+	Requests []RequestStatusResponse `json:"requests"`
+}
+
+func (h HTTP) batchGetRequestsHandle(ctx http.Context) error {
+	var rqBody BatchGetRequestsRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+	if len(rqBody.RequestIDs) == 0 || len(rqBody.RequestIDs) > maxBatchGetRequestIDs {
+		return errors.Wrapf(ErrInvalidRequest, "number of request IDs must be between 1 and %d", maxBatchGetRequestIDs)
+	}
+
+	resp := BatchGetRequestsResponse{Requests: make([]RequestStatusResponse, 0, len(rqBody.RequestIDs))}
+	for _, requestID := range rqBody.RequestIDs {
+		status, err := h.app.RequestTxStatus(requestID)
+		switch {
+		case errors.Is(err, app.ErrTxNotFound):
+			resp.Requests = append(resp.Requests, RequestStatusResponse{RequestID: requestID, Status: requestStatusNotFound})
+		case err != nil:
+			return err
+		default:
+			resp.Requests = append(resp.Requests, RequestStatusResponse{
+				RequestID:             requestID,
+				Status:                string(status.State),
+				TxHash:                status.TxHash,
+				Height:                status.Height,
+				Confirmations:         status.Confirmations,
+				RequiredConfirmations: status.RequiredConfirmations,
+			})
+		}
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
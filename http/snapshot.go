@@ -0,0 +1,79 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// BreakerResponse is the state of the circuit breaker.
+type BreakerResponse struct {
+	Name    string     `json:"name"`
+	State   string     `json:"state"`
+	Since   *time.Time `json:"since,omitempty"`
+	RetryAt *time.Time `json:"retryAt,omitempty"`
+	Cause   string     `json:"cause,omitempty"`
+}
+
+// InFlightTxResponse is the transaction not confirmed yet.
+type InFlightTxResponse struct {
+	TxStatusResponse
+	Rebroadcasts int       `json:"rebroadcasts"`
+	BroadcastAt  time.Time `json:"broadcastAt"`
+}
+
+// SnapshotResponse is the output to /admin/snapshot request.
+type SnapshotResponse struct {
+	Time                  time.Time            `json:"time"`
+	Paused                bool                 `json:"paused"`
+	QueueDepth            int                  `json:"queueDepth"`
+	QueueDrainTimeSeconds float64              `json:"queueDrainTimeSeconds"`
+	InFlightTxs           []InFlightTxResponse `json:"inFlightTxs"`
+	Breakers              []BreakerResponse    `json:"breakers"`
+	// Components is the state of other components by name, e.g. the broadcast worker pool.
+	Components map[string]interface{} `json:"components"`
+}
+
+func (h HTTP) snapshotHandle(ctx http.Context) error {
+	snapshot := h.app.Snapshot()
+	resp := SnapshotResponse{
+		Time:                  snapshot.Time,
+		Paused:                snapshot.Paused,
+		QueueDepth:            snapshot.QueueDepth,
+		QueueDrainTimeSeconds: snapshot.QueueDrainTime.Seconds(),
+		InFlightTxs:           []InFlightTxResponse{},
+		Breakers:              []BreakerResponse{},
+		Components:            map[string]interface{}{},
+	}
+	for _, tx := range snapshot.InFlightTxs {
+		txResp := InFlightTxResponse{
+			TxStatusResponse: TxStatusResponse{
+				TxHash:                tx.TxHash,
+				Status:                string(tx.State),
+				Height:                tx.Height,
+				Confirmations:         tx.Confirmations,
+				RequiredConfirmations: tx.RequiredConfirmations,
+				Requests:              []TxRequestResponse{},
+			},
+			Rebroadcasts: tx.Rebroadcasts,
+			BroadcastAt:  tx.BroadcastAt,
+		}
+		for _, r := range tx.Requests {
+			txResp.Requests = append(txResp.Requests, txRequestResponse(r))
+		}
+		resp.InFlightTxs = append(resp.InFlightTxs, txResp)
+	}
+	for _, b := range snapshot.Breakers {
+		breaker := BreakerResponse{Name: b.Name, State: b.State, Cause: b.Cause}
+		if !b.Since.IsZero() {
+			breaker.Since = &b.Since
+			breaker.RetryAt = &b.RetryAt
+		}
+		resp.Breakers = append(resp.Breakers, breaker)
+	}
+	for name, source := range h.cfg.SnapshotSources {
+		resp.Components[name] = source()
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
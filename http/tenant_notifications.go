@@ -0,0 +1,99 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// TenantNotificationsRequest is the input to /keys/self/notifications request.
+type TenantNotificationsRequest struct {
+	Webhooks      []string            `json:"webhooks"`
+	SlackWebhooks []string            `json:"slackWebhooks"`
+	Events        []string            `json:"events"`
+	Alerts        AlertThresholdsBody `json:"alerts"`
+}
+
+// AlertThresholdsBody are the numbers of failed and blocked requests within the hour raising the alert.
+type AlertThresholdsBody struct {
+	Failed  int `json:"failed"`
+	Blocked int `json:"blocked"`
+}
+
+// TenantNotificationsResponse is the output to /keys/self/notifications request.
+type TenantNotificationsResponse struct {
+	Tenant string `json:"tenant"`
+	TenantNotificationsRequest
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (h HTTP) tenantNotificationsHandle(ctx http.Context) error {
+	holder, ok := apiKeyHolder(ctx)
+	if !ok {
+		return errors.Wrapf(ErrUnauthorized, "%s header is required", HeaderXAPIKey)
+	}
+	notifications, err := h.app.TenantNotifications(ctx.Request().Context(), holder)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, tenantNotificationsResponse(notifications))
+}
+
+func (h HTTP) putTenantNotificationsHandle(ctx http.Context) error {
+	holder, ok := apiKeyHolder(ctx)
+	if !ok {
+		return errors.Wrapf(ErrUnauthorized, "%s header is required", HeaderXAPIKey)
+	}
+	var rqBody TenantNotificationsRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+
+	// requests of the API key holder are accounted to it as the tenant
+	notifications := app.TenantNotifications{
+		Tenant:        holder,
+		Webhooks:      rqBody.Webhooks,
+		SlackWebhooks: rqBody.SlackWebhooks,
+		Alerts:        app.AlertThresholds(rqBody.Alerts),
+	}
+	for _, kind := range rqBody.Events {
+		notifications.Events = append(notifications.Events, app.EventKind(kind))
+	}
+	notifications, err := h.app.SetTenantNotifications(ctx.Request().Context(), notifications)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, tenantNotificationsResponse(notifications))
+}
+
+func (h HTTP) deleteTenantNotificationsHandle(ctx http.Context) error {
+	holder, ok := apiKeyHolder(ctx)
+	if !ok {
+		return errors.Wrapf(ErrUnauthorized, "%s header is required", HeaderXAPIKey)
+	}
+	if err := h.app.DeleteTenantNotifications(ctx.Request().Context(), holder); err != nil {
+		return err
+	}
+	return ctx.NoContent(nethttp.StatusNoContent)
+}
+
+func tenantNotificationsResponse(notifications app.TenantNotifications) TenantNotificationsResponse {
+	resp := TenantNotificationsResponse{
+		Tenant: notifications.Tenant,
+		TenantNotificationsRequest: TenantNotificationsRequest{
+			Webhooks:      append([]string{}, notifications.Webhooks...),
+			SlackWebhooks: append([]string{}, notifications.SlackWebhooks...),
+			Events:        []string{},
+			Alerts:        AlertThresholdsBody(notifications.Alerts),
+		},
+		UpdatedAt: notifications.UpdatedAt,
+	}
+	for _, kind := range notifications.Events {
+		resp.Events = append(resp.Events, string(kind))
+	}
+	return resp
+}
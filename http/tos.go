@@ -0,0 +1,46 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// HeaderXFaucetToSToken carries the token issued by /tos/accept, required by funding endpoints if acceptance
+// of the terms of service is required.
+const HeaderXFaucetToSToken = "X-Faucet-Tos-Token"
+
+// AcceptToSRequest is the input to /tos/accept request, sent once the user accepts the terms of service.
+type AcceptToSRequest struct {
+	Version string `json:"version"`
+}
+
+// AcceptToSResponse is the output to /tos/accept request.
+type AcceptToSResponse struct {
+	Token     string    `json:"token"`
+	Version   string    `json:"version"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func (h HTTP) acceptToSHandle(ctx http.Context) error {
+	var rqBody AcceptToSRequest
+	if err := ctx.Bind(&rqBody); err != nil {
+		return err
+	}
+
+	requester, err := requesterFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	acceptance, err := h.app.AcceptTermsOfService(requester, rqBody.Version)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(nethttp.StatusOK, AcceptToSResponse{
+		Token:     acceptance.Token,
+		Version:   acceptance.Version,
+		ExpiresAt: acceptance.ExpiresAt,
+	})
+}
@@ -0,0 +1,40 @@
+package http
+
+import (
+	nethttp "net/http"
+	"time"
+
+	"github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// TransparencyResponse is the output to /transparency request.
+type TransparencyResponse struct {
+	Distributed string `json:"distributed"`
+	// DistributedCoins is the distributed amount enriched with the display units.
+	DistributedCoins []CoinResponse `json:"distributedCoins"`
+	Grants           int            `json:"grants"`
+	// Balance of the funding accounts, omitted if it can't be queried at the moment.
+	Balance       *CoinResponse `json:"balance,omitempty"`
+	StartedAt     time.Time     `json:"startedAt"`
+	UptimeSeconds int64         `json:"uptimeSeconds"`
+}
+
+func (h HTTP) transparencyHandle(ctx http.Context) error {
+	transparency, err := h.app.Transparency(ctx.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	resp := TransparencyResponse{
+		Distributed:      transparency.Distributed.String(),
+		DistributedCoins: h.coinResponses(ctx.Request().Context(), transparency.Distributed),
+		Grants:           transparency.Grants,
+		StartedAt:        transparency.StartedAt,
+		UptimeSeconds:    int64(transparency.Uptime / time.Second),
+	}
+	if transparency.Balance != nil {
+		balance := h.coinResponse(ctx.Request().Context(), *transparency.Balance)
+		resp.Balance = &balance
+	}
+	return ctx.JSON(nethttp.StatusOK, resp)
+}
@@ -0,0 +1,166 @@
+// Package indexer exports the funding history in the formats ingested by Cosmos chain indexers, so explorers
+// can tag the faucet transactions.
+package indexer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// Format is the format of the export.
+type Format string
+
+// Supported formats.
+const (
+	// FormatJSONL writes one JSON object per line, each describing the funding as the ABCI event of the
+	// transaction, the shape event-based indexers consume.
+	FormatJSONL Format = "jsonl"
+	// FormatSQL writes PostgreSQL statements creating the table of fundings and inserting the rows,
+	// ready to be loaded next to the tables of SQL-based indexers and joined to their transactions by hash.
+	FormatSQL Format = "sql"
+)
+
+// EventType is the type of the event describing the funding.
+const EventType = "faucet_funding"
+
+// Table is the name of the SQL table of fundings.
+const Table = "faucet_funding"
+
+// ParseFormat parses the export format.
+func ParseFormat(format string) (Format, error) {
+	switch f := Format(format); f {
+	case FormatJSONL, FormatSQL:
+		return f, nil
+	default:
+		return "", errors.Errorf("invalid export format %q, supported formats: jsonl, sql", format)
+	}
+}
+
+// ContentType returns the MIME type of the export.
+func (f Format) ContentType() string {
+	if f == FormatSQL {
+		return "application/sql"
+	}
+	return "application/x-ndjson"
+}
+
+// Event is the funding described as the event of the transaction.
+type Event struct {
+	ChainID    string      `json:"chain_id"`
+	TxHash     string      `json:"tx_hash"`
+	Timestamp  time.Time   `json:"timestamp"`
+	Type       string      `json:"type"`
+	Attributes []Attribute `json:"attributes"`
+}
+
+// Attribute is the key-value attribute of the event.
+type Attribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// NewEvent returns the event of the funding. IPs and fingerprints of the clients are never exported.
+func NewEvent(chainID string, record app.FundingRecord) Event {
+	attributes := []Attribute{
+		{Key: "recipient", Value: record.Address},
+		{Key: "amount", Value: record.Amount.String()},
+		{Key: "request_id", Value: record.RequestID},
+	}
+	if !record.Fee.IsNil() && record.Fee.Denom != "" {
+		attributes = append(attributes, Attribute{Key: "fee", Value: record.Fee.String()})
+	}
+	if record.ToSVersion != "" {
+		attributes = append(attributes, Attribute{Key: "tos_version", Value: record.ToSVersion})
+	}
+	return Event{
+		ChainID:    chainID,
+		TxHash:     strings.ToUpper(record.TxHash),
+		Timestamp:  record.Time.UTC(),
+		Type:       EventType,
+		Attributes: attributes,
+	}
+}
+
+// schema is created idempotently, so exports may be loaded one after another. Primary key makes reloading
+// the overlapping periods safe.
+const schema = `CREATE TABLE IF NOT EXISTS ` + Table + ` (
+    chain_id    TEXT        NOT NULL,
+    request_id  TEXT        NOT NULL,
+    tx_hash     TEXT        NOT NULL,
+    recipient   TEXT        NOT NULL,
+    amount      NUMERIC     NOT NULL,
+    denom       TEXT        NOT NULL,
+    fee         NUMERIC,
+    fee_denom   TEXT,
+    tos_version TEXT,
+    timestamp   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (chain_id, request_id)
+);
+CREATE INDEX IF NOT EXISTS ` + Table + `_tx_hash_index ON ` + Table + ` (tx_hash);
+CREATE INDEX IF NOT EXISTS ` + Table + `_recipient_index ON ` + Table + ` (recipient);
+`
+
+// Write writes the fundings to w in the format.
+func Write(w io.Writer, format Format, chainID string, records []app.FundingRecord) error {
+	if format == FormatSQL {
+		return writeSQL(w, chainID, records)
+	}
+	return writeJSONL(w, chainID, records)
+}
+
+func writeJSONL(w io.Writer, chainID string, records []app.FundingRecord) error {
+	encoder := json.NewEncoder(w)
+	for _, record := range records {
+		if err := encoder.Encode(NewEvent(chainID, record)); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func writeSQL(w io.Writer, chainID string, records []app.FundingRecord) error {
+	if _, err := io.WriteString(w, schema); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, record := range records {
+		fee, feeDenom := "NULL", "NULL"
+		if !record.Fee.IsNil() && record.Fee.Denom != "" {
+			fee, feeDenom = record.Fee.Amount.String(), quote(record.Fee.Denom)
+		}
+		tosVersion := "NULL"
+		if record.ToSVersion != "" {
+			tosVersion = quote(record.ToSVersion)
+		}
+		_, err := fmt.Fprintf(w,
+			"INSERT INTO %s (chain_id, request_id, tx_hash, recipient, amount, denom, fee, fee_denom, tos_version, timestamp) "+
+				"VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s, %s) ON CONFLICT DO NOTHING;\n",
+			Table,
+			quote(chainID),
+			quote(record.RequestID),
+			quote(strings.ToUpper(record.TxHash)),
+			quote(record.Address),
+			record.Amount.Amount.String(),
+			quote(record.Amount.Denom),
+			fee,
+			feeDenom,
+			tosVersion,
+			quote(record.Time.UTC().Format(time.RFC3339Nano)),
+		)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// quote returns the SQL string literal, the values are never trusted even though the faucet produces them.
+func quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,73 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+func TestWrite(t *testing.T) {
+	requireT := require.New(t)
+
+	records := []app.FundingRecord{
+		{
+			RequestID:   "rq1",
+			Address:     "devcore1...",
+			IP:          "1.2.3.4",
+			Fingerprint: "fp",
+			Amount:      chain.NewCoin("udevcore", chain.NewInt(1000)),
+			Fee:         chain.NewCoin("udevcore", chain.NewInt(5)),
+			TxHash:      "abcd",
+			Time:        time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			ToSVersion:  "v1",
+		},
+		{
+			RequestID: "rq'2",
+			Address:   "devcore1...",
+			Amount:    chain.NewCoin("udevcore", chain.NewInt(1000)),
+			TxHash:    "abcd",
+			Time:      time.Date(2023, 1, 1, 0, 0, 1, 0, time.UTC),
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	requireT.NoError(Write(buf, FormatJSONL, "coreum-devnet-1", records))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	requireT.Len(lines, 2)
+	var event Event
+	requireT.NoError(json.Unmarshal([]byte(lines[0]), &event))
+	requireT.Equal(Event{
+		ChainID:   "coreum-devnet-1",
+		TxHash:    "ABCD",
+		Timestamp: records[0].Time,
+		Type:      EventType,
+		Attributes: []Attribute{
+			{Key: "recipient", Value: "devcore1..."},
+			{Key: "amount", Value: "1000udevcore"},
+			{Key: "request_id", Value: "rq1"},
+			{Key: "fee", Value: "5udevcore"},
+			{Key: "tos_version", Value: "v1"},
+		},
+	}, event)
+	// the clients are never revealed
+	requireT.NotContains(buf.String(), "1.2.3.4")
+
+	buf.Reset()
+	requireT.NoError(Write(buf, FormatSQL, "coreum-devnet-1", records))
+	requireT.Contains(buf.String(), "CREATE TABLE IF NOT EXISTS faucet_funding (")
+	requireT.Contains(buf.String(), "VALUES ('coreum-devnet-1', 'rq1', 'ABCD', 'devcore1...', 1000, 'udevcore', "+
+		"5, 'udevcore', 'v1', '2023-01-01T00:00:00Z') ON CONFLICT DO NOTHING;")
+	requireT.Contains(buf.String(), "'rq''2', 'ABCD', 'devcore1...', 1000, 'udevcore', NULL, NULL, NULL, "+
+		"'2023-01-01T00:00:01Z')")
+	requireT.NotContains(buf.String(), "1.2.3.4")
+
+	_, err := ParseFormat("csv")
+	requireT.Error(err)
+}
@@ -0,0 +1,215 @@
+//go:build integrationtests
+
+package integrationtests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	nethttp "net/http"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/http"
+	"github.com/CoreumFoundation/faucet/pkg/latency"
+)
+
+// perfReportVersion is bumped whenever the format of the performance report changes incompatibly.
+const perfReportVersion = 1
+
+// Flags of the performance test, they are declared at package level, so they are registered before the flags
+// are parsed by init.
+var (
+	perfOutput = flag.String("perf-output", "",
+		"Path of the JSON file the performance report is written to, the performance test runs only if it is set")
+	perfBudgets = flag.String("perf-budgets", "perf_budgets.json",
+		"Path of the JSON file with the performance budgets of the endpoints")
+	perfStepDuration = flag.Duration("perf-step-duration", 20*time.Second, "How long each load step lasts")
+	perfStartRPS     = flag.Int("perf-start-rps", 5, "Request rate of the first load step")
+	perfStepRPS      = flag.Int("perf-step-rps", 5, "Increase of the request rate between the load steps")
+	perfMaxRPS       = flag.Int("perf-max-rps", 200, "Request rate the load stops increasing at")
+)
+
+// perfBudget is the performance the endpoint must sustain, so regressions fail the run.
+type perfBudget struct {
+	MinRPS       float64 `json:"minRps"`
+	MaxP99MS     float64 `json:"maxP99Ms"`
+	MaxErrorRate float64 `json:"maxErrorRate"`
+}
+
+// perfStep is the outcome of sending the requests at the constant rate.
+type perfStep struct {
+	TargetRPS   int     `json:"targetRps"`
+	Sent        int     `json:"sent"`
+	Succeeded   int     `json:"succeeded"`
+	Failed      int     `json:"failed"`
+	AchievedRPS float64 `json:"achievedRps"`
+	ErrorRate   float64 `json:"errorRate"`
+	P50MS       float64 `json:"p50Ms"`
+	P99MS       float64 `json:"p99Ms"`
+	Sustained   bool    `json:"sustained"`
+}
+
+// perfEndpoint is the outcome of the load of the endpoint.
+type perfEndpoint struct {
+	Endpoint          string     `json:"endpoint"`
+	Budget            perfBudget `json:"budget"`
+	Steps             []perfStep `json:"steps"`
+	MaxSustainableRPS int        `json:"maxSustainableRps"`
+	P99AtMaxMS        float64    `json:"p99AtMaxMs"`
+	WithinBudget      bool       `json:"withinBudget"`
+}
+
+// perfReport is the machine-readable report of the run, compared release over release.
+type perfReport struct {
+	Version        int            `json:"version"`
+	FaucetAddress  string         `json:"faucetAddress"`
+	StartedAt      time.Time      `json:"startedAt"`
+	FinishedAt     time.Time      `json:"finishedAt"`
+	StepDurationMS int64          `json:"stepDurationMs"`
+	Endpoints      []perfEndpoint `json:"endpoints"`
+}
+
+// TestPerformance increases the rate of the requests step by step until the endpoint stops sustaining it
+// and checks the highest sustained rate against the budget. The faucet must be started with the rate limits
+// and the address cooldown disabled, e.g. by --rate-limit-exempt-cidrs covering the test runner.
+func TestPerformance(t *testing.T) {
+	if *perfOutput == "" {
+		t.Skip("performance test runs only if -perf-output is set")
+	}
+	requireT := require.New(t)
+
+	content, err := os.ReadFile(*perfBudgets)
+	requireT.NoError(err)
+	budgets := map[string]perfBudget{}
+	requireT.NoError(json.Unmarshal(content, &budgets))
+
+	client := &nethttp.Client{
+		Timeout:   30 * time.Second,
+		Transport: &nethttp.Transport{MaxIdleConnsPerHost: *perfMaxRPS},
+	}
+	endpoints := map[string]func(ctx context.Context) error{
+		"fund": func(ctx context.Context) error {
+			address := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address()).String()
+			body, err := json.Marshal(http.FundRequest{Address: address})
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			return perfPost(ctx, client, cfg.faucetAddress+"/api/faucet/v1/fund", body)
+		},
+		"gen-funded": func(ctx context.Context) error {
+			return perfPost(ctx, client, cfg.faucetAddress+"/api/faucet/v1/gen-funded", nil)
+		},
+	}
+
+	report := perfReport{
+		Version:        perfReportVersion,
+		FaucetAddress:  cfg.faucetAddress,
+		StartedAt:      time.Now().UTC(),
+		StepDurationMS: perfStepDuration.Milliseconds(),
+	}
+	names := make([]string, 0, len(endpoints))
+	for name := range endpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		budget, ok := budgets[name]
+		requireT.True(ok, "budget of %s is missing", name)
+		result := perfEndpoint{Endpoint: name, Budget: budget, Steps: []perfStep{}}
+		for rps := *perfStartRPS; rps <= *perfMaxRPS; rps += *perfStepRPS {
+			step := runPerfStep(context.Background(), endpoints[name], rps, *perfStepDuration)
+			step.Sustained = step.ErrorRate <= budget.MaxErrorRate && step.P99MS <= budget.MaxP99MS &&
+				step.AchievedRPS >= 0.9*float64(rps)
+			result.Steps = append(result.Steps, step)
+			t.Logf("%s at %d req/s: achieved %.1f req/s, p99 %.0f ms, errors %.2f%%", name, rps, step.AchievedRPS,
+				step.P99MS, 100*step.ErrorRate)
+			if !step.Sustained {
+				break
+			}
+			result.MaxSustainableRPS = rps
+			result.P99AtMaxMS = step.P99MS
+		}
+		result.WithinBudget = float64(result.MaxSustainableRPS) >= budget.MinRPS
+		report.Endpoints = append(report.Endpoints, result)
+	}
+	report.FinishedAt = time.Now().UTC()
+
+	content, err = json.MarshalIndent(report, "", "  ")
+	requireT.NoError(err)
+	requireT.NoError(os.WriteFile(*perfOutput, append(content, '\n'), 0o644))
+	for _, e := range report.Endpoints {
+		requireT.True(e.WithinBudget, "%s sustains %d req/s, budget is %.0f req/s", e.Endpoint,
+			e.MaxSustainableRPS, e.Budget.MinRPS)
+	}
+}
+
+// runPerfStep sends the requests at the constant rate for the duration, regardless of how fast the previous
+// ones are answered, so the latency of the overloaded faucet is measured instead of hidden.
+func runPerfStep(ctx context.Context, send func(ctx context.Context) error, rps int, duration time.Duration) perfStep {
+	step := perfStep{TargetRPS: rps}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	latencies := []time.Duration{}
+
+	ticker := time.NewTicker(time.Second / time.Duration(rps))
+	defer ticker.Stop()
+	started := time.Now()
+	for time.Since(started) < duration {
+		<-ticker.C
+		step.Sent++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sent := time.Now()
+			err := send(ctx)
+			latency := time.Since(sent)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				step.Failed++
+				return
+			}
+			step.Succeeded++
+			latencies = append(latencies, latency)
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(started)
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	step.AchievedRPS = float64(step.Succeeded) / elapsed.Seconds()
+	step.ErrorRate = float64(step.Failed) / float64(step.Sent)
+	step.P50MS = latency.PercentileMS(latencies, 50)
+	step.P99MS = latency.PercentileMS(latencies, 99)
+	return step
+}
+
+func perfPost(ctx context.Context, client *nethttp.Client, url string, body []byte) error {
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+	if res.StatusCode > 299 {
+		return errors.Errorf("non 2xx response, status: %d", res.StatusCode)
+	}
+	return nil
+}
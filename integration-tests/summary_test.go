@@ -0,0 +1,110 @@
+//go:build integrationtests
+
+package integrationtests
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// summaryVersion is bumped whenever the format of the summary changes incompatibly, so CI tooling can detect it.
+const summaryVersion = 1
+
+// interaction is the single request sent to the faucet by the test.
+type interaction struct {
+	Test       string        `json:"test"`
+	Endpoint   string        `json:"endpoint"`
+	Address    string        `json:"address,omitempty"`
+	StatusCode int           `json:"statusCode,omitempty"`
+	TxHash     string        `json:"txHash,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	StartedAt  time.Time     `json:"startedAt"`
+	Latency    time.Duration `json:"-"`
+	LatencyMS  float64       `json:"latencyMs"`
+}
+
+// testResult is the outcome of the test sending requests to the faucet.
+type testResult struct {
+	Name   string `json:"name"`
+	Failed bool   `json:"failed"`
+}
+
+// runSummary is the machine-readable summary of the run.
+type runSummary struct {
+	Version       int           `json:"version"`
+	FaucetAddress string        `json:"faucetAddress"`
+	CoredAddress  string        `json:"coredAddress"`
+	StartedAt     time.Time     `json:"startedAt"`
+	FinishedAt    time.Time     `json:"finishedAt"`
+	ExitCode      int           `json:"exitCode"`
+	Tests         []testResult  `json:"tests"`
+	Interactions  []interaction `json:"interactions"`
+}
+
+// recorder collects the interactions of the tests running in parallel.
+type recorder struct {
+	mu           sync.Mutex
+	tests        map[string]*testing.T
+	interactions []interaction
+}
+
+var summary = &recorder{tests: map[string]*testing.T{}}
+
+// record stores the interaction of the test, the outcome of the test is collected once the run finishes.
+func (r *recorder) record(t *testing.T, i interaction) {
+	i.Test = t.Name()
+	i.LatencyMS = float64(i.Latency.Microseconds()) / 1000
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tests[t.Name()] = t
+	r.interactions = append(r.interactions, i)
+}
+
+func (r *recorder) write(path string, startedAt time.Time, exitCode int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := runSummary{
+		Version:       summaryVersion,
+		FaucetAddress: cfg.faucetAddress,
+		CoredAddress:  cfg.coredAddress,
+		StartedAt:     startedAt.UTC(),
+		FinishedAt:    time.Now().UTC(),
+		ExitCode:      exitCode,
+		Tests:         []testResult{},
+		Interactions:  append([]interaction{}, r.interactions...),
+	}
+	for name, t := range r.tests {
+		s.Tests = append(s.Tests, testResult{Name: name, Failed: t.Failed()})
+	}
+	sort.Slice(s.Tests, func(i, j int) bool { return s.Tests[i].Name < s.Tests[j].Name })
+	sort.SliceStable(s.Interactions, func(i, j int) bool {
+		return s.Interactions[i].StartedAt.Before(s.Interactions[j].StartedAt)
+	})
+
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+func TestMain(m *testing.M) {
+	startedAt := time.Now()
+	exitCode := m.Run()
+	if summaryPath != "" {
+		if err := summary.write(summaryPath, startedAt, exitCode); err != nil {
+			fmt.Fprintf(os.Stderr, "writing run summary failed: %s\n", err)
+			if exitCode == 0 {
+				exitCode = 1
+			}
+		}
+	}
+	os.Exit(exitCode)
+}
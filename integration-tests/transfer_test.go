@@ -40,12 +40,17 @@ type testConfig struct {
 	network        coreumconfig.Network
 }
 
-var cfg testConfig
+var (
+	cfg         testConfig
+	summaryPath string
+)
 
 func init() {
 	flag.StringVar(&cfg.coredAddress, "cored-address", "localhost:9090", "Address of cored node started by znet")
 	flag.StringVar(&cfg.faucetAddress, "faucet-address", "http://localhost:8090", "Address of the faucet")
 	flag.StringVar(&cfg.transferAmount, "transfer-amount", "1000000", "Amount transferred by faucet in each request")
+	flag.StringVar(&summaryPath, "summary-path", "",
+		"Path of the JSON file summarizing the faucet interactions of the run, attached by CI to test artifacts")
 	// accept testing flags
 	testing.Init()
 	// parse additional flags
@@ -72,7 +77,7 @@ func TestTransferRequest(t *testing.T) {
 
 	// request fund
 	clientCtx := cfg.clientCtx
-	txHash, err := requestFunds(ctx, address)
+	txHash, err := requestFunds(ctx, t, address)
 	require.NoError(t, err)
 	require.Len(t, txHash, 64)
 
@@ -98,7 +103,7 @@ func TestTransferRequestWithGenPrivkey(t *testing.T) {
 
 	// request fund
 	clientCtx := cfg.clientCtx
-	response, err := requestFundsWithPrivkey(ctx)
+	response, err := requestFundsWithPrivkey(ctx, t)
 	require.NoError(t, err)
 	require.Len(t, response.TxHash, 64)
 
@@ -122,7 +127,7 @@ func TestTransferRequest_WrongAddress(t *testing.T) {
 
 	// request fund
 	clientCtx := cfg.clientCtx
-	txHash, err := requestFunds(ctx, address)
+	txHash, err := requestFunds(ctx, t, address)
 	assert.Error(t, err)
 	assert.Len(t, txHash, 0)
 
@@ -135,15 +140,25 @@ func TestTransferRequest_WrongAddress(t *testing.T) {
 	assert.Nil(t, resp)
 }
 
-func requestFunds(ctx context.Context, address string) (string, error) {
+func requestFunds(ctx context.Context, t *testing.T, address string) (txHash string, err error) {
 	url := cfg.faucetAddress + "/api/faucet/v1/fund"
 	method := "POST"
 
+	i := interaction{Endpoint: url, Address: address, StartedAt: time.Now()}
+	defer func() {
+		i.Latency = time.Since(i.StartedAt)
+		i.TxHash = txHash
+		if err != nil {
+			i.Error = err.Error()
+		}
+		summary.record(t, i)
+	}()
+
 	sendMoneyReq := http.FundRequest{
 		Address: address,
 	}
 	payloadBuffer := bytes.NewBuffer(nil)
-	err := json.NewEncoder(payloadBuffer).Encode(sendMoneyReq)
+	err = json.NewEncoder(payloadBuffer).Encode(sendMoneyReq)
 	if err != nil {
 		return "", errors.WithStack(err)
 	}
@@ -161,6 +176,7 @@ func requestFunds(ctx context.Context, address string) (string, error) {
 		return "", errors.WithStack(err)
 	}
 	defer res.Body.Close()
+	i.StatusCode = res.StatusCode
 	if res.StatusCode > 299 {
 		body, _ := io.ReadAll(res.Body)
 		return "", errors.Errorf("non 2xx response, body: %s", body)
@@ -176,10 +192,21 @@ func requestFunds(ctx context.Context, address string) (string, error) {
 	return sendMoneyResponse.TxHash, nil
 }
 
-func requestFundsWithPrivkey(ctx context.Context) (http.GenFundedResponse, error) {
+func requestFundsWithPrivkey(ctx context.Context, t *testing.T) (response http.GenFundedResponse, err error) {
 	url := cfg.faucetAddress + "/api/faucet/v1/gen-funded"
 	method := "POST"
 
+	i := interaction{Endpoint: url, StartedAt: time.Now()}
+	defer func() {
+		i.Latency = time.Since(i.StartedAt)
+		i.Address = response.Address
+		i.TxHash = response.TxHash
+		if err != nil {
+			i.Error = err.Error()
+		}
+		summary.record(t, i)
+	}()
+
 	client := &nethttp.Client{}
 	req, err := nethttp.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
@@ -191,6 +218,7 @@ func requestFundsWithPrivkey(ctx context.Context) (http.GenFundedResponse, error
 		return http.GenFundedResponse{}, errors.WithStack(err)
 	}
 	defer res.Body.Close()
+	i.StatusCode = res.StatusCode
 	if res.StatusCode > 299 {
 		body, _ := io.ReadAll(res.Body)
 		return http.GenFundedResponse{}, errors.Errorf("non 2xx response, body: %s", body)
@@ -1,62 +1,263 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"crypto/tls"
+	"fmt"
 	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/cosmos/cosmos-sdk/client/flags"
-	"github.com/cosmos/cosmos-sdk/crypto/hd"
-	"github.com/cosmos/cosmos-sdk/crypto/keyring"
-	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/cosmos/cosmos-sdk/types/tx/signing"
 	"github.com/pkg/errors"
 	"github.com/spf13/pflag"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/CoreumFoundation/coreum-tools/pkg/parallel"
-	"github.com/CoreumFoundation/coreum/pkg/client"
-	coreumconfig "github.com/CoreumFoundation/coreum/pkg/config"
-	"github.com/CoreumFoundation/coreum/pkg/config/constant"
+	"github.com/CoreumFoundation/faucet/admincli"
 	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/callback"
+	"github.com/CoreumFoundation/faucet/captcha"
 	"github.com/CoreumFoundation/faucet/client/coreum"
+	"github.com/CoreumFoundation/faucet/email"
+	"github.com/CoreumFoundation/faucet/failover"
 	"github.com/CoreumFoundation/faucet/http"
+	"github.com/CoreumFoundation/faucet/notify"
+	"github.com/CoreumFoundation/faucet/oauth"
+	"github.com/CoreumFoundation/faucet/oidc"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
 	"github.com/CoreumFoundation/faucet/pkg/config"
+	"github.com/CoreumFoundation/faucet/pkg/egress"
+	"github.com/CoreumFoundation/faucet/pkg/fsperm"
+	"github.com/CoreumFoundation/faucet/pkg/geoip"
+	pkghttp "github.com/CoreumFoundation/faucet/pkg/http"
+	"github.com/CoreumFoundation/faucet/pkg/iplist"
+	"github.com/CoreumFoundation/faucet/pkg/iprep"
+	"github.com/CoreumFoundation/faucet/pkg/lambda"
 	"github.com/CoreumFoundation/faucet/pkg/limiter"
 	"github.com/CoreumFoundation/faucet/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/ratelimit"
+	"github.com/CoreumFoundation/faucet/pkg/redis"
 	"github.com/CoreumFoundation/faucet/pkg/signal"
+	"github.com/CoreumFoundation/faucet/report"
+	"github.com/CoreumFoundation/faucet/scheduler"
+	"github.com/CoreumFoundation/faucet/sms"
+	"github.com/CoreumFoundation/faucet/store"
+	"github.com/CoreumFoundation/faucet/webhook"
 )
 
+// outboundTimeout is the timeout of HTTP requests sent to external services.
+const outboundTimeout = 30 * time.Second
+
 const (
 	flagChainID          = "chain-id"
+	flagEnvironment      = "environment"
 	flagNode             = "node"
 	flagAddress          = "address"
+	flagInternalAddress  = "internal-address"
 	flagTransferAmount   = "transfer-amount"
+	flagMaxTransfer      = "max-transfer-amount"
 	flagMnemonicFilePath = "key-path-mnemonic"
 	flagIPRateLimit      = "ip-rate-limit"
+	flagIPRateLimitAlgo  = "ip-rate-limit-algorithm"
+	flagIPRateLimitBurst = "ip-rate-limit-burst"
+	flagSubnetRateLimit  = "subnet-rate-limit"
+	flagSubnetIPv4Prefix = "subnet-ipv4-prefix"
+	flagSubnetIPv6Prefix = "subnet-ipv6-prefix"
+	flagFirstTimeLimit   = "first-time-ip-rate-limit"
+	flagTxConfirmations  = "tx-confirmations"
+	flagTxAwaitInitial   = "tx-await-initial-interval"
+	flagTxAwaitMax       = "tx-await-max-interval"
+	flagTxAwaitTimeout   = "tx-await-timeout"
+	flagMaxQueueDepth    = "max-queue-depth"
+	flagDailyBudget      = "daily-budget"
+	flagDenomBudgets     = "denom-budgets"
+	flagLifetimeCap      = "lifetime-cap"
+	flagBalanceThreshold = "balance-threshold"
+	flagAddressCooldown  = "address-cooldown"
+	flagSubAccounts      = "sub-accounts"
+	flagBroadcastWorkers = "broadcast-workers"
+	flagStorePath        = "store-path"
+	flagStoreMigrate     = "store-auto-migrate"
+	flagReplicaPath      = "store-replica-path"
+	flagReplicaInterval  = "store-replica-interval"
+	flagGCInterval       = "gc-interval"
+	flagAdminToken       = "admin-token"
+	flagFilePermCheck    = "file-perm-check"
+	flagStrictJSON       = "strict-json"
+	flagExampleTx        = "gen-funded-example-tx"
+	flagNamedAccountsKey = "gen-funded-encryption-key"
+	flagPreflight        = "preflight"
+	flagChallengeDust    = "onchain-challenge-dust"
+	flagTxAttribution    = "tx-attribution"
+	flagClockFastForward = "clock-fast-forward"
+	flagFailoverLease    = "failover-lease-path"
+	flagFailoverID       = "failover-instance-id"
+	flagFailoverTTL      = "failover-lease-ttl"
+	flagTrustedProxies   = "trusted-proxies"
+	flagExemptCIDRs      = "rate-limit-exempt-cidrs"
+	flagHSTSMaxAge       = "hsts-max-age"
+	flagHSTSSubdomains   = "hsts-include-subdomains"
+	flagCSP              = "content-security-policy"
+	flagFrameAncestors   = "frame-ancestors"
+	flagAPIKeys          = "api-keys"
+	flagAPIKeyQuotas     = "api-key-quotas"
+	flagOutboundProxy    = "outbound-proxy"
+	flagFeeGasPrices     = "fee-gas-prices"
+	flagTenantFeeDenoms  = "tenant-fee-denoms"
+	flagCongestionLevels = "congestion-levels"
+	flagIPPrivacy        = "ip-privacy"
+	flagIPPrivacyRetain  = "ip-privacy-retention"
+	flagIPHashSalt       = "ip-hash-salt"
+	flagCallbackHosts    = "callback-allowed-hosts"
+	flagCallbackKey      = "callback-signing-key"
+	flagReportInterval   = "report-interval"
+	flagReportFormat     = "report-format"
+	flagReportWebhookURL = "report-webhook-url"
+	flagReportSMTPAddr   = "report-smtp-address"
+	flagReportSMTPUser   = "report-smtp-username"
+	flagReportSMTPPass   = "report-smtp-password"
+	flagReportSMTPFrom   = "report-smtp-from"
+	flagReportSMTPTo     = "report-smtp-to"
+	flagExperimentName   = "experiment-name"
+	flagExperimentPct    = "experiment-percent"
+	flagExperimentAmount = "experiment-transfer-amount"
+	flagExperimentLimit  = "experiment-ip-rate-limit"
+	flagRedisURL         = "redis-url"
+	flagRedisKeyPrefix   = "redis-key-prefix"
+	flagToSVersion       = "tos-version"
+	flagToSSigningKey    = "tos-signing-key"
+	flagToSTokenTTL      = "tos-token-ttl"
+	flagRecaptchaSecret  = "recaptcha-secret"
+	flagRecaptchaScore   = "recaptcha-min-score"
+	flagHcaptchaSiteKey  = "hcaptcha-site-key"
+	flagHcaptchaSecret   = "hcaptcha-secret"
+	flagTurnstileSecret  = "turnstile-secret"
+	flagTenantNotify     = "tenant-notifications"
+	flagQRLinkTemplate   = "qr-link-template"
+	flagEventWebhooks    = "event-webhooks"
+	flagFundingHooks     = "funding-hooks"
+	flagPoWDifficulty    = "pow-difficulty"
+	flagOwnershipProof   = "ownership-proof"
+	flagGitHubClientID   = "github-client-id"
+	flagGitHubSecret     = "github-client-secret"
+	flagGitHubCallback   = "github-callback-url"
+	flagGitHubMinAge     = "github-min-account-age"
+	flagGitHubMinRepos   = "github-min-public-repos"
+	flagGitHubMinFollows = "github-min-followers"
+	flagDiscordClientID  = "discord-client-id"
+	flagDiscordSecret    = "discord-client-secret"
+	flagDiscordCallback  = "discord-callback-url"
+	flagDiscordGuild     = "discord-guild-id"
+	flagDiscordRoles     = "discord-roles"
+	flagIdentityKey      = "identity-signing-key"
+	flagOIDCIssuer       = "oidc-issuer-url"
+	flagOIDCClientID     = "oidc-client-id"
+	flagOIDCAudience     = "oidc-audience"
+	flagIdentityTTL      = "identity-session-ttl"
+	flagIdentityQuota    = "identity-quota"
+	flagIdentityPeriod   = "identity-quota-period"
+	flagIdentityReturn   = "identity-return-url"
+	flagEmailLinkURL     = "email-link-url"
+	flagEmailKey         = "email-signing-key"
+	flagEmailLinkTTL     = "email-link-ttl"
+	flagEmailSubject     = "email-subject"
+	flagEmailFrom        = "email-from"
+	flagEmailSMTPAddr    = "email-smtp-address"
+	flagEmailSMTPUser    = "email-smtp-username"
+	flagEmailSMTPPass    = "email-smtp-password"
+	flagEmailSESRegion   = "email-ses-region"
+	flagEmailSESKeyID    = "email-ses-access-key-id"
+	flagEmailSESSecret   = "email-ses-secret-access-key"
+	flagTwilioSID        = "sms-twilio-account-sid"
+	flagTwilioToken      = "sms-twilio-auth-token"
+	flagSMSFrom          = "sms-from"
+	flagPhoneKey         = "phone-hash-key"
+	flagPhoneCodeTTL     = "phone-code-ttl"
+	flagPhoneQuota       = "phone-quota"
+	flagPhonePeriod      = "phone-quota-period"
+	flagPhoneSendQuota   = "phone-send-quota"
+	flagPhoneSendPeriod  = "phone-send-quota-period"
+	flagFundingLinkKey   = "funding-link-key"
+	flagFundingLinkURL   = "funding-link-url"
+	flagIPAllowlist      = "ip-allowlist"
+	flagIPDenylist       = "ip-denylist"
+	flagIPListReload     = "ip-list-reload-interval"
+	flagGeoIPDB          = "geoip-db"
+	flagGeoIPAllowed     = "geoip-allowed-countries"
+	flagGeoIPBlocked     = "geoip-blocked-countries"
+	flagTreasurySigners  = "treasury-signers"
+	flagTreasuryThresh   = "treasury-threshold"
+	flagIPRepTorList     = "ip-reputation-tor-list"
+	flagIPRepVPNList     = "ip-reputation-vpn-list"
+	flagIPRepDCList      = "ip-reputation-datacenter-list"
+	flagIPRepProvider    = "ip-reputation-provider-url"
+	flagIPRepKey         = "ip-reputation-provider-key"
+	flagIPRepCacheTTL    = "ip-reputation-cache-ttl"
+	flagIPRepAction      = "ip-reputation-action"
+	flagAbuseCaptcha     = "abuse-score-captcha"
+	flagAbuseDeny        = "abuse-score-deny"
+	flagAbuseWeights     = "abuse-score-weights"
+	flagAbuseFrequency   = "abuse-score-frequency"
+	flagAbuseBalance     = "abuse-score-balance"
 )
 
+// secretFlags are redacted in the effective configuration logged on startup and returned by admin API.
+var secretFlags = []string{
+	flagAdminToken,
+	flagAPIKeys,
+	flagIPHashSalt,
+	flagCallbackKey,
+	flagReportWebhookURL,
+	flagReportSMTPPass,
+	flagToSSigningKey,
+	flagNamedAccountsKey,
+	flagRecaptchaSecret,
+	flagHcaptchaSecret,
+	flagTurnstileSecret,
+	flagGitHubSecret,
+	flagDiscordSecret,
+	flagIdentityKey,
+	flagEmailKey,
+	flagEmailSMTPPass,
+	flagEmailSESSecret,
+	flagTwilioToken,
+	flagPhoneKey,
+	flagIPRepKey,
+	flagFundingLinkKey,
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		if err := admincli.Run(context.Background(), os.Args[2:], os.Stdout, os.Stderr); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	ctx, log, cfg := setup()
 	if cfg.help {
 		return
 	}
 
-	log.Info("Starting faucet",
-		zap.String("address", cfg.address),
-		zap.String("chainID", cfg.chainID),
-		zap.String("mnemonicFilePath", cfg.mnemonicFilePath),
-		zap.String("node", cfg.node))
+	log.Info("Starting faucet", zap.Object("config", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		for _, entry := range cfg.effective {
+			enc.AddString(entry.Name, entry.Value)
+		}
+		return nil
+	})))
+
+	verifyDeployment(cfg, log)
 
-	network, err := coreumconfig.NetworkByChainID(constant.ChainID(cfg.chainID))
+	network, err := chain.NetworkByChainID(chain.ChainID(cfg.chainID))
 	if err != nil {
 		log.Fatal(
 			"Unable to get network config for chain-id",
@@ -65,18 +266,50 @@ func main() {
 		)
 	}
 
-	if network.ChainID() == constant.ChainIDMain {
+	if network.ChainID() == chain.ChainIDMain {
 		log.Fatal("running a faucet against mainnet is not allowed")
 	}
 
 	network.SetSDKConfig()
 
-	transferAmount := sdk.Coin{
-		Amount: sdk.NewInt(cfg.transferAmount),
-		Denom:  network.Denom(),
+	transferAmount := chain.NewCoin(network.Denom(), chain.NewInt(cfg.transferAmount))
+	if cfg.maxTransfer > 0 && cfg.transferAmount > cfg.maxTransfer {
+		log.Fatal("Transfer amount exceeds the absolute maximum",
+			zap.Int64("transferAmount", cfg.transferAmount), zap.Int64("maxTransferAmount", cfg.maxTransfer))
+	}
+	if cfg.maxTransfer > 0 && cfg.experiment.transferAmount > cfg.maxTransfer {
+		log.Fatal("Experiment transfer amount exceeds the absolute maximum",
+			zap.Int64("transferAmount", cfg.experiment.transferAmount), zap.Int64("maxTransferAmount", cfg.maxTransfer))
+	}
+	if cfg.dailyBudget < 0 || (cfg.dailyBudget > 0 && cfg.transferAmount > cfg.dailyBudget) {
+		log.Fatal("Daily budget must not be negative and must cover at least one transfer",
+			zap.Int64("transferAmount", cfg.transferAmount), zap.Int64("dailyBudget", cfg.dailyBudget))
+	}
+	if cfg.dailyBudget > 0 && cfg.denomBudgets.AmountOf(network.Denom()).IsPositive() {
+		log.Fatal("Daily budget of the transfer denom is set by --daily-budget only", zap.String("denom", network.Denom()))
+	}
+	if cfg.lifetimeCap < 0 || (cfg.lifetimeCap > 0 && cfg.transferAmount > cfg.lifetimeCap) {
+		log.Fatal("Lifetime cap must not be negative and must cover at least one transfer",
+			zap.Int64("transferAmount", cfg.transferAmount), zap.Int64("lifetimeCap", cfg.lifetimeCap))
+	}
+	if cfg.balanceThreshold < 0 {
+		log.Fatal("Balance threshold must not be negative", zap.Int64("balanceThreshold", cfg.balanceThreshold))
+	}
+	if cfg.qrLinkTemplate != "" && !strings.Contains(cfg.qrLinkTemplate, app.QRAddressPlaceholder) {
+		log.Fatal("QR link template must contain the address placeholder",
+			zap.String("template", cfg.qrLinkTemplate), zap.String("placeholder", app.QRAddressPlaceholder))
+	}
+	experiment := app.Experiment{Name: cfg.experiment.name, Percent: cfg.experiment.percent}
+	if cfg.experiment.transferAmount > 0 {
+		experiment.TransferAmount = chain.NewInt(cfg.experiment.transferAmount)
+	}
+	if experiment.Enabled() || experiment.Percent != 0 {
+		if err := experiment.Validate(); err != nil {
+			log.Fatal("Invalid experiment", zap.Error(err))
+		}
 	}
 
-	kr, addresses, err := newKeyringFromFile(cfg.mnemonicFilePath)
+	kr, accounts, err := coreum.NewKeyringFromFile(cfg.mnemonicFilePath, cfg.subAccounts)
 	if err != nil {
 		log.Fatal(
 			"Unable to create keyring",
@@ -85,39 +318,559 @@ func main() {
 		)
 	}
 
+	var addresses []chain.AccAddress
+	for _, account := range accounts {
+		addresses = append(addresses, account.Address)
+		addresses = append(addresses, account.SubAccounts...)
+	}
 	var addrList []string
 	for _, addr := range addresses {
 		addrList = append(addrList, addr.String())
 	}
 	log.Info("funding account addresses", zap.Strings("addresses", addrList))
 
-	clientCtx := client.NewContext(client.DefaultContextConfig(), config.NewModuleManager()).
-		WithChainID(string(network.ChainID())).
-		WithBroadcastMode(flags.BroadcastBlock)
-
-	clientCtx = addClient(cfg, log, clientCtx)
+	for tenant, denom := range cfg.tenantFeeDenoms {
+		if denom != network.Denom() && !cfg.feeGasPrices.AmountOf(denom).IsPositive() {
+			log.Fatal("Gas price of tenant fee denom is not set", zap.String("tenant", tenant), zap.String("denom", denom))
+		}
+	}
 
-	txf := client.Factory{}.
-		WithTxConfig(clientCtx.TxConfig()).
-		WithKeybase(kr).
-		WithChainID(string(network.ChainID())).
-		WithSignMode(signing.SignMode_SIGN_MODE_DIRECT)
 	cl := coreum.New(
 		network,
-		clientCtx,
-		txf,
+		dialNode(cfg, log),
+		kr,
+	).WithFeeGasPrices(cfg.feeGasPrices).WithAwaitConfig(cfg.txAwait)
+	if cfg.fundingHooks != "" {
+		hookConfigs, err := coreum.LoadHookConfig(cfg.fundingHooks)
+		if err != nil {
+			log.Fatal("Unable to load funding hooks", zap.Error(err))
+		}
+		hooks, err := coreum.NewFundingHooks(hookConfigs, addresses)
+		if err != nil {
+			log.Fatal("Unable to create funding hooks", zap.Error(err))
+		}
+		log.Info("Funding hooks enabled", zap.Strings("hooks", hooks.Names()))
+		cl = cl.WithFundingHooks(hooks)
+	}
+	events := app.NewEventBus()
+	events.Subscribe("auditLog", logEvent)
+	var callbacks *callback.Notifier
+	if len(cfg.callbacks.allowedHosts) > 0 {
+		callbacks, err = callback.NewNotifier(cfg.callbacks.allowedHosts, cfg.callbacks.signingKey, cfg.outboundProxy)
+		if err != nil {
+			log.Fatal("Unable to create callback notifier", zap.Error(err))
+		}
+		events.Subscribe("callbacks", callbacks.Handle)
+	}
+	if cfg.eventWebhooks != "" {
+		webhooks, err := webhook.LoadConfig(cfg.eventWebhooks)
+		if err != nil {
+			log.Fatal("Unable to load event webhooks", zap.Error(err))
+		}
+		dispatcher, err := webhook.NewDispatcher(webhooks, cfg.outboundProxy.HTTPClient(outboundTimeout))
+		if err != nil {
+			log.Fatal("Unable to create event webhooks", zap.Error(err))
+		}
+		events.Subscribe("eventWebhooks", dispatcher.Handle)
+	}
+	var clk clock.Clock = clock.System{}
+	var fastForwardClock *clock.Offset
+	if cfg.clockFastForward {
+		fastForwardClock = clock.NewOffset()
+		clk = fastForwardClock
+	}
+
+	txTracker := app.NewTxTracker(cl, cfg.txConfirmations, events, clk)
+	cl = cl.WithTxObserver(txTracker)
+
+	for _, account := range accounts {
+		// sub-accounts holding funds already keep serving, so the faucet starts even if the top-up fails
+		if err := cl.DistributeBalance(ctx, account, network.Denom()); err != nil {
+			log.Warn("Unable to distribute balance to sub-accounts", zap.Error(err), zap.Stringer("address", account.Address))
+		}
+	}
+
+	openStore := store.Open
+	if !cfg.storeMigrate {
+		openStore = store.OpenWithoutMigration
+	}
+	db, err := openStore(cfg.storePath)
+	if err != nil {
+		log.Fatal("Unable to open store", zap.Error(err), zap.String("path", cfg.storePath))
+	}
+	defer db.Close()
+	log.Info("Store opened", zap.String("path", cfg.storePath), zap.Uint64("schemaVersion", store.SchemaVersion))
+
+	var replica *store.Replica
+	if cfg.replicaPath != "" {
+		replica, err = store.OpenReplica(db, cfg.replicaPath)
+		if err != nil {
+			log.Fatal("Unable to open read replica", zap.Error(err), zap.String("path", cfg.replicaPath))
+		}
+		defer replica.Close()
+	}
+
+	var coordinator *failover.Coordinator
+	if cfg.failover.leasePath != "" {
+		coordinator = newFailoverCoordinator(cfg, cl, addresses, transferAmount)
+	}
+
+	var redisClient *redis.Client
+	var cooldowns app.AddressCooldowns = db
+	if cfg.redis.url != "" {
+		redisClient = newRedisClient(ctx, log, cfg.redis.url)
+		defer redisClient.Close()
+		cooldowns = store.NewKVCooldowns(redisClient, cfg.redis.keyPrefix+"cooldown:")
+	}
+
+	ipStore, ipMemoryStore := newRateLimitStore(redisClient, cfg.redis.keyPrefix+"ip:", clk)
+	ipRateLimiter, err := ratelimit.New(cfg.ipRateLimitAlgo, ratelimit.Rule{
+		Limit:  cfg.ipRateLimit.howMany,
+		Period: cfg.ipRateLimit.period,
+		Burst:  cfg.ipRateLimitBurst,
+	}, ipStore, clk)
+	if err != nil {
+		log.Fatal("Unable to create IP rate limiter", zap.Error(err))
+	}
+
+	var subnetMemoryStore *ratelimit.MemoryStore
+	var subnetLimiter *limiter.SubnetLimiter
+	if cfg.subnetRateLimit.limit.howMany > 0 {
+		var subnetStore ratelimit.Store
+		subnetStore, subnetMemoryStore = newRateLimitStore(redisClient, cfg.redis.keyPrefix+"subnet:", clk)
+		subnetRateLimiter, err := ratelimit.New(cfg.ipRateLimitAlgo, ratelimit.Rule{
+			Limit:  cfg.subnetRateLimit.limit.howMany,
+			Period: cfg.subnetRateLimit.limit.period,
+		}, subnetStore, clk)
+		if err != nil {
+			log.Fatal("Unable to create subnet rate limiter", zap.Error(err))
+		}
+		subnetLimiter, err = limiter.NewSubnetLimiter(
+			limiter.NewRateLimiter(subnetRateLimiter),
+			cfg.subnetRateLimit.ipv4Prefix,
+			cfg.subnetRateLimit.ipv6Prefix,
+		)
+		if err != nil {
+			log.Fatal("Unable to create subnet rate limiter", zap.Error(err))
+		}
+	}
+
+	// IPs of the experiment group are tracked separately, so the stricter limit doesn't affect the control group
+	var experimentMemoryStore *ratelimit.MemoryStore
+	var experimentLimiter limiter.PerIPLimiter
+	if experiment.Enabled() && cfg.experiment.ipRateLimit.howMany > 0 {
+		var experimentStore ratelimit.Store
+		experimentStore, experimentMemoryStore = newRateLimitStore(redisClient, cfg.redis.keyPrefix+"experiment-ip:", clk)
+		experimentRateLimiter, err := ratelimit.New(cfg.ipRateLimitAlgo, ratelimit.Rule{
+			Limit:  cfg.experiment.ipRateLimit.howMany,
+			Period: cfg.experiment.ipRateLimit.period,
+		}, experimentStore, clk)
+		if err != nil {
+			log.Fatal("Unable to create experiment IP rate limiter", zap.Error(err))
+		}
+		experimentLimiter = limiter.NewRateLimiter(experimentRateLimiter)
+	}
+
+	// newcomers get the separate allowance, so IPs using it up can't fund the repeat addresses
+	var firstTimeMemoryStore *ratelimit.MemoryStore
+	var firstTimeLimiter limiter.PerIPLimiter
+	if cfg.firstTimeLimit.howMany > 0 {
+		var firstTimeStore ratelimit.Store
+		firstTimeStore, firstTimeMemoryStore = newRateLimitStore(redisClient, cfg.redis.keyPrefix+"first-time-ip:", clk)
+		firstTimeRateLimiter, err := ratelimit.New(cfg.ipRateLimitAlgo, ratelimit.Rule{
+			Limit:  cfg.firstTimeLimit.howMany,
+			Period: cfg.firstTimeLimit.period,
+		}, firstTimeStore, clk)
+		if err != nil {
+			log.Fatal("Unable to create first-time IP rate limiter", zap.Error(err))
+		}
+		firstTimeLimiter = limiter.NewRateLimiter(firstTimeRateLimiter)
+	}
+
+	var geoRestriction *geoip.Restriction
+	if cfg.geoIP.db != "" {
+		geoDB, err := geoip.Open(cfg.geoIP.db)
+		if err != nil {
+			log.Fatal("Unable to open GeoIP database", zap.Error(err))
+		}
+		defer geoDB.Close()
+		geoRestriction, err = geoip.NewRestriction(geoDB, cfg.geoIP.allowed, cfg.geoIP.blocked)
+		if err != nil {
+			log.Fatal("Invalid GeoIP restriction", zap.Error(err))
+		}
+	} else if len(cfg.geoIP.allowed) > 0 || len(cfg.geoIP.blocked) > 0 {
+		log.Fatal("GeoIP database is required to restrict the countries")
+	}
+
+	var (
+		ipReputation       iprep.Provider
+		ipReputationLists  *iprep.Lists
+		ipReputationAction iprep.Action
 	)
+	if sources := cfg.ipReputation.sources(); len(sources) > 0 || cfg.ipReputation.providerURL != "" {
+		ipReputationAction, err = iprep.ParseAction(cfg.ipReputation.action)
+		if err != nil {
+			log.Fatal("Invalid IP reputation action", zap.Error(err))
+		}
+		var providers iprep.Providers
+		if len(sources) > 0 {
+			ipReputationLists = iprep.NewLists(sources, cfg.outboundProxy.HTTPClient(outboundTimeout))
+			if err := ipReputationLists.Reload(ctx); err != nil {
+				log.Fatal("Unable to load IP reputation lists", zap.Error(err))
+			}
+			providers = append(providers, ipReputationLists)
+		}
+		if cfg.ipReputation.providerURL != "" {
+			provider, err := iprep.NewHTTPProvider(cfg.ipReputation.providerURL, cfg.ipReputation.providerKey,
+				cfg.outboundProxy.HTTPClient(outboundTimeout), cfg.ipReputation.cacheTTL)
+			if err != nil {
+				log.Fatal("Invalid IP reputation provider", zap.Error(err))
+			}
+			providers = append(providers, provider)
+		}
+		ipReputation = providers
+	}
+
+	var congestion *app.CongestionMonitor
+	if len(cfg.congestionLevels) > 0 {
+		congestion = app.NewCongestionMonitor(cl, cfg.congestionLevels...)
+	}
+
+	var ipAnonymizer *app.IPAnonymizer
+	if cfg.ipPrivacy.mode != app.IPPrivacyOff {
+		ipAnonymizer, err = app.NewIPAnonymizer(cfg.ipPrivacy.mode, cfg.ipPrivacy.salt, cfg.ipPrivacy.retention, db, clk)
+		if err != nil {
+			log.Fatal("Unable to create IP anonymizer", zap.Error(err))
+		}
+	}
+
+	var tos *app.TermsOfService
+	if cfg.tos.version != "" {
+		tos, err = app.NewTermsOfService(cfg.tos.version, cfg.tos.signingKey, cfg.tos.tokenTTL)
+		if err != nil {
+			log.Fatal("Invalid terms of service", zap.Error(err))
+		}
+	}
+
+	var mnemonicCipher *app.MnemonicCipher
+	if cfg.namedAccountsKey != "" {
+		mnemonicCipher, err = app.NewMnemonicCipher(cfg.namedAccountsKey)
+		if err != nil {
+			log.Fatal("Invalid encryption key of named accounts", zap.Error(err))
+		}
+	}
+
+	var preflight *app.Preflight
+	if cfg.preflight {
+		preflight = app.NewPreflight(cl, addresses, transferAmount, preflightFeeDenoms(cfg, network)...)
+	}
 
 	err = parallel.Run(ctx, func(ctx context.Context, spawn parallel.SpawnFn) error {
-		batcher := coreum.NewBatcher(cl, addresses, 10)
-		application := app.New(batcher, network, transferAmount)
-		ipLimiter := limiter.NewWeightedWindowLimiter(cfg.ipRateLimit.howMany, cfg.ipRateLimit.period)
+		batcher := coreum.NewBatcher(cl, addresses, 10, cfg.broadcastWorkers)
+		application := app.New(batcher, cl, txTracker, db, db, network, transferAmount).
+			WithClock(clk).
+			WithMaxQueueDepth(cfg.maxQueueDepth).
+			WithAddressBook(db).
+			WithClaimCodes(db).
+			WithConfigAudit(db).
+			WithBypassTokens(db).
+			WithBlocklist(db).
+			WithAddressCooldown(cooldowns, cfg.addressCooldown).
+			WithExperiment(experiment).
+			WithEventBus(events).
+			WithTenantFeeDenoms(cfg.tenantFeeDenoms).
+			WithMaxTransferAmount(chain.NewInt(cfg.maxTransfer)).
+			WithDailyBudget(append(
+				chain.Coins{chain.NewCoin(network.Denom(), chain.NewInt(cfg.dailyBudget))}, cfg.denomBudgets...)...).
+			WithLifetimeCap(db, chain.NewCoin(network.Denom(), chain.NewInt(cfg.lifetimeCap))).
+			WithBalanceThreshold(cl, chain.NewInt(cfg.balanceThreshold)).
+			WithTransparency(cl, addresses).
+			WithTxAttribution(cfg.txAttribution).
+			WithCongestionMonitor(congestion).
+			WithIPAnonymizer(ipAnonymizer).
+			WithTermsOfService(tos).
+			WithDenomMetadata(cl).
+			WithQRLinkTemplate(cfg.qrLinkTemplate)
+		if replica != nil {
+			application = application.WithQueryStore(replica)
+		}
+		if cfg.exampleTx {
+			application = application.WithExampleTxSigner(cl)
+		}
+		if mnemonicCipher != nil {
+			application = application.WithNamedAccounts(db, mnemonicCipher)
+		}
+		if callbacks != nil {
+			application = application.WithCallbacks(callbacks)
+		}
+		var tenantNotifications *notify.Router
+		if cfg.tenantNotify {
+			tenantNotifications = notify.NewRouter(db, callback.NewHTTPClient(outboundTimeout, cfg.outboundProxy))
+			events.Subscribe("tenantNotifications", tenantNotifications.Handle)
+			application = application.WithTenantNotifications(db)
+		}
+		captchaVerifier, err := newCaptchaVerifier(cfg)
+		if err != nil {
+			log.Fatal("Unable to create captcha verifier", zap.Error(err))
+		}
+		var abuseScorer *app.AbuseScorer
+		if cfg.abuseScore.enabled() {
+			scoringConfig, err := cfg.abuseScore.scoringConfig(transferAmount)
+			if err != nil {
+				log.Fatal("Invalid abuse scoring", zap.Error(err))
+			}
+			abuseScorer, err = app.NewAbuseScorer(scoringConfig, cl, captchaVerifier, clk)
+			if err != nil {
+				log.Fatal("Invalid abuse scoring", zap.Error(err))
+			}
+			application = application.WithAbuseScoring(abuseScorer)
+		}
+		switch {
+		case ipReputationAction == iprep.ActionScore:
+			if abuseScorer == nil {
+				log.Fatal("Abuse scoring must be configured to score the IPs flagged by IP reputation")
+			}
+		case ipReputationAction == iprep.ActionCaptcha:
+			if abuseScorer != nil {
+				log.Fatal("IP reputation action must be score if abuse scoring is enabled")
+			}
+			if captchaVerifier == nil {
+				log.Fatal("Captcha must be configured to require it from the IPs flagged by IP reputation")
+			}
+			application = application.WithFlaggedCaptcha(captchaVerifier)
+		case abuseScorer != nil:
+			// captcha is required by the abuse score
+		case captchaVerifier != nil:
+			application = application.WithCaptcha(captchaVerifier)
+		}
+		if cfg.powDifficulty > 0 {
+			application, err = application.WithProofOfWork(cfg.powDifficulty)
+			if err != nil {
+				log.Fatal("Unable to enable proof of work", zap.Error(err))
+			}
+		}
+		if cfg.ownershipProof {
+			application, err = application.WithOwnershipProof()
+			if err != nil {
+				log.Fatal("Unable to enable proof of address ownership", zap.Error(err))
+			}
+		}
+		identityProviders, err := newIdentityProviders(cfg)
+		if err != nil {
+			log.Fatal("Unable to configure identity providers", zap.Error(err))
+		}
+		if len(identityProviders) > 0 {
+			application, err = application.WithIdentity(cfg.identity.config(), db, identityProviders...)
+			if err != nil {
+				log.Fatal("Unable to enable identity verification", zap.Error(err))
+			}
+		}
+		if cfg.email.linkURL != "" {
+			sender, err := newEmailSender(cfg)
+			if err != nil {
+				log.Fatal("Unable to configure email delivery", zap.Error(err))
+			}
+			application, err = application.WithEmailVerification(cfg.email.config(), db, sender)
+			if err != nil {
+				log.Fatal("Unable to enable email verification", zap.Error(err))
+			}
+		}
+		if cfg.phone.twilioAccountSID != "" {
+			sender, err := sms.NewTwilio(cfg.phone.twilioAccountSID, cfg.phone.twilioAuthToken, cfg.phone.from,
+				cfg.outboundProxy.HTTPClient(outboundTimeout))
+			if err != nil {
+				log.Fatal("Unable to configure SMS delivery", zap.Error(err))
+			}
+			application, err = application.WithPhoneVerification(cfg.phone.config(), db, db, sender)
+			if err != nil {
+				log.Fatal("Unable to enable phone verification", zap.Error(err))
+			}
+		}
+		if cfg.fundingLinks.signingKey != "" {
+			application, err = application.WithFundingLinks(cfg.fundingLinks.config(), db)
+			if err != nil {
+				log.Fatal("Unable to enable funding links", zap.Error(err))
+			}
+		}
+		if cfg.challengeDust > 0 {
+			application = application.WithOnChainChallenge(cl, chain.NewCoin(network.Denom(), chain.NewInt(cfg.challengeDust)))
+		}
+		if len(cfg.treasury.signers) > 0 {
+			threshold := cfg.treasury.threshold
+			if threshold == 0 {
+				threshold = len(cfg.treasury.signers)
+			}
+			treasury, err := cl.Treasury(cfg.treasury.signers, threshold)
+			if err != nil {
+				log.Fatal("Unable to configure treasury", zap.Error(err))
+			}
+			log.Info("Treasury refills enabled", zap.Stringer("treasury", treasury.Address()),
+				zap.Int("threshold", threshold))
+			application = application.WithTreasury(treasury, db, accounts[0].Address)
+		}
+		gc := app.NewGarbageCollector(application)
+		jobs := scheduler.New(db, clk)
+		var ipFilter *iplist.Filter
+		if cfg.ipLists.allowlist != "" || cfg.ipLists.denylist != "" {
+			ipFilter = iplist.NewFilter(cfg.ipLists.allowlist, cfg.ipLists.denylist,
+				cfg.outboundProxy.HTTPClient(outboundTimeout))
+			if err := ipFilter.Reload(ctx); err != nil {
+				log.Fatal("Unable to load IP lists", zap.Error(err))
+			}
+		}
+		var oidcVerifier *oidc.Verifier
+		if cfg.oidc.IssuerURL != "" {
+			oidcVerifier, err = oidc.NewVerifier(cfg.oidc, cfg.outboundProxy.HTTPClient(outboundTimeout))
+			if err != nil {
+				log.Fatal("Unable to configure OIDC authentication", zap.Error(err))
+			}
+		}
+		ipLimiter := limiter.NewRateLimiter(ipRateLimiter)
+		metricsCollectors := append(append(batcher.Collectors(), gc.Collectors()...), jobs.Collectors()...)
+		if abuseScorer != nil {
+			metricsCollectors = append(metricsCollectors, abuseScorer.Collectors()...)
+		}
 		//nolint:contextcheck
-		server := http.New(application, ipLimiter, log)
+		server := http.New(application, ipLimiter, http.Config{
+			AdminToken:       cfg.adminToken,
+			InternalAddress:  cfg.internalAddress,
+			StrictJSON:       cfg.strictJSON,
+			TrustedProxies:   cfg.trustedProxies,
+			FastForwardClock: fastForwardClock,
+			Failover:         coordinator,
+			APIKeys: http.APIKeys{
+				Holders: cfg.apiKeys,
+				Quota:   limiter.NewQuotaLimiter(clk, cfg.apiKeyQuotas...),
+			},
+			RateLimitExemptions: cfg.exemptCIDRs,
+			ExperimentLimiter:   experimentLimiter,
+			SubnetLimiter:       subnetLimiter,
+			FirstTimeLimiter:    firstTimeLimiter,
+			IPFilter:            ipFilter,
+			GeoIP:               geoRestriction,
+			IPReputation:        ipReputation,
+			IPReputationAction:  ipReputationAction,
+			Environment:         environment(cfg.environment, network.ChainID()),
+			SecurityHeaders:     cfg.securityHeaders,
+			Preflight:           preflight,
+			MetricsCollectors:   metricsCollectors,
+			Scheduler:           jobs,
+			EffectiveConfig:     cfg.effective,
+			IdentityReturnURL:   cfg.identity.returnURL,
+			OIDC:                oidcVerifier,
+			SnapshotSources: map[string]func() interface{}{
+				"batcher":   func() interface{} { return batcher.Snapshot() },
+				"sequences": func() interface{} { return cl.Sequences() },
+				"limiters": func() interface{} {
+					return limiterSnapshot(map[string]*ratelimit.MemoryStore{
+						"ip":            ipMemoryStore,
+						"experiment-ip": experimentMemoryStore,
+						"subnet":        subnetMemoryStore,
+						"first-time-ip": firstTimeMemoryStore,
+					})
+				},
+			},
+		}, log)
 
+		spawn("events", parallel.Fail, events.Run)
 		spawn("batcher", parallel.Fail, batcher.Run)
-		spawn("limiterCleanup", parallel.Fail, ipLimiter.Run)
+		for name, memoryStore := range map[string]struct {
+			store  *ratelimit.MemoryStore
+			period time.Duration
+		}{
+			"limiterCleanup":           {store: ipMemoryStore, period: cfg.ipRateLimit.period},
+			"experimentLimiterCleanup": {store: experimentMemoryStore, period: cfg.experiment.ipRateLimit.period},
+			"subnetLimiterCleanup":     {store: subnetMemoryStore, period: cfg.subnetRateLimit.limit.period},
+			"firstTimeLimiterCleanup":  {store: firstTimeMemoryStore, period: cfg.firstTimeLimit.period},
+		} {
+			if memoryStore.store == nil {
+				continue
+			}
+			prune := memoryStore.store.Prune
+			jobs.Add(scheduler.Job{Name: name, Interval: memoryStore.period, Run: func(ctx context.Context) error {
+				prune()
+				return nil
+			}})
+		}
+		if abuseScorer != nil {
+			jobs.Add(scheduler.Job{
+				Name:     "abuseScoreCleanup",
+				Interval: cfg.abuseScore.frequency.period,
+				Run: func(ctx context.Context) error {
+					abuseScorer.Prune()
+					return nil
+				},
+			})
+		}
+		spawn("txTracker", parallel.Fail, txTracker.Run)
+		// lists are reloaded on SIGHUP too, so the operators may apply the changes at once
+		var reloadJobs []string
+		if ipFilter != nil {
+			jobs.Add(scheduler.Job{Name: "ipLists", Interval: cfg.ipLists.reloadInterval, Run: func(ctx context.Context) error {
+				return reloadIPLists(ctx, ipFilter, application)
+			}})
+			reloadJobs = append(reloadJobs, "ipLists")
+		}
+		if ipReputationLists != nil {
+			jobs.Add(scheduler.Job{
+				Name:     "ipReputationLists",
+				Interval: cfg.ipLists.reloadInterval,
+				Run:      ipReputationLists.Reload,
+			})
+			reloadJobs = append(reloadJobs, "ipReputationLists")
+		}
+		if len(reloadJobs) > 0 {
+			spawn("hangup", parallel.Fail, func(ctx context.Context) error {
+				hangup := signal.Hangup(ctx)
+				for {
+					select {
+					case <-ctx.Done():
+						return errors.WithStack(ctx.Err())
+					case <-hangup:
+						for _, name := range reloadJobs {
+							if err := jobs.Trigger(name); err != nil {
+								return err
+							}
+						}
+					}
+				}
+			})
+		}
+		if congestion != nil {
+			spawn("congestion", parallel.Fail, congestion.Run)
+		}
+		if replica != nil {
+			jobs.Add(scheduler.Job{Name: "replica", Interval: cfg.replicaInterval, Run: func(ctx context.Context) error {
+				return replica.Refresh()
+			}})
+		}
+		if ipAnonymizer != nil {
+			jobs.Add(scheduler.Job{Name: "ipAnonymizer", Interval: app.IPAnonymizeInterval, Run: ipAnonymizer.AnonymizeExpired})
+		}
+		if cfg.gcInterval > 0 {
+			jobs.Add(scheduler.Job{Name: "gc", Interval: cfg.gcInterval, Run: gc.Collect})
+		}
+		if preflight != nil {
+			spawn("preflight", parallel.Fail, preflight.Run)
+		}
+		if coordinator != nil {
+			spawn("failover", parallel.Fail, coordinator.Run)
+		}
+		if tenantNotifications != nil {
+			spawn("tenantNotifications", parallel.Fail, tenantNotifications.Run)
+		}
+		if cfg.report.interval > 0 {
+			jobs.Add(scheduler.Job{
+				Name:     "report",
+				Interval: cfg.report.interval,
+				Run:      newReportJob(cfg, log, network, application).Report,
+			})
+		}
+		spawn("scheduler", parallel.Fail, jobs.Run)
 		spawn("server", parallel.Fail, func(ctx context.Context) error {
+			if runtimeAPI := os.Getenv(lambda.EnvRuntimeAPI); runtimeAPI != "" {
+				return server.ServeLambda(ctx, runtimeAPI)
+			}
 			return server.ListenAndServe(ctx, cfg.address)
 		})
 
@@ -129,7 +882,157 @@ func main() {
 	}
 }
 
-func addClient(cfg cfg, log *zap.Logger, clientCtx client.Context) client.Context {
+// environment returns the environment label, if it is not configured it is derived from the chain ID,
+// e.g. coreum-testnet-1 gives testnet.
+func environment(label string, chainID chain.ChainID) string {
+	if label != "" {
+		return label
+	}
+	parts := strings.Split(string(chainID), "-")
+	if len(parts) == 3 {
+		return parts[1]
+	}
+	return string(chainID)
+}
+
+// preflightFeeDenoms returns the denoms fees are paid in, empty denom stands for the gas price denom of the chain.
+func preflightFeeDenoms(cfg cfg, network chain.Network) []string {
+	var tenantDenoms []string
+	seen := map[string]bool{network.Denom(): true}
+	for _, denom := range cfg.tenantFeeDenoms {
+		if !seen[denom] {
+			seen[denom] = true
+			tenantDenoms = append(tenantDenoms, denom)
+		}
+	}
+	sort.Strings(tenantDenoms)
+	return append([]string{""}, tenantDenoms...)
+}
+
+// logEvent writes the event to the audit log.
+func logEvent(ctx context.Context, event app.Event) {
+	logger.Get(ctx).Info("Faucet event",
+		zap.String("kind", string(event.Kind)),
+		zap.Time("time", event.Time),
+		zap.String("requestID", event.Requester.RequestID),
+		zap.String("ip", event.Requester.IP),
+		zap.String("address", event.Address),
+		zap.String("txHash", event.TxHash),
+		zap.String("reason", event.Reason),
+		zap.String("tosVersion", event.Requester.ToSVersion),
+		zap.String("invoiceID", event.Requester.InvoiceID),
+		zap.String("actor", event.Actor),
+		zap.Any("changes", event.Changes),
+	)
+}
+
+// reloadIPLists reloads the IP lists and records the change of their sizes in the configuration audit.
+func reloadIPLists(ctx context.Context, filter *iplist.Filter, application app.App) error {
+	allowBefore, denyBefore := filter.Sizes()
+	if err := filter.Reload(ctx); err != nil {
+		return err
+	}
+	allow, deny := filter.Sizes()
+	var diff []app.ConfigDiff
+	if allow != allowBefore {
+		diff = append(diff, app.ConfigDiff{
+			Setting: flagIPAllowlist,
+			Old:     strconv.Itoa(allowBefore) + " entries",
+			New:     strconv.Itoa(allow) + " entries",
+		})
+	}
+	if deny != denyBefore {
+		diff = append(diff, app.ConfigDiff{
+			Setting: flagIPDenylist,
+			Old:     strconv.Itoa(denyBefore) + " entries",
+			New:     strconv.Itoa(deny) + " entries",
+		})
+	}
+	application.RecordConfigChange(ctx, "job:ipLists", app.ConfigSourceReload, diff)
+	return nil
+}
+
+func newReportJob(cfg cfg, log *zap.Logger, network chain.Network, application app.App) *report.Job {
+	var senders []report.Sender
+	if cfg.report.webhookURL != "" {
+		senders = append(senders, report.NewWebhookSender(cfg.report.webhookURL, cfg.outboundProxy.HTTPClient(outboundTimeout)))
+	}
+	if cfg.report.smtpAddress != "" {
+		senders = append(senders, report.NewSMTPSender(
+			cfg.report.smtpAddress,
+			cfg.report.smtpUsername,
+			cfg.report.smtpPassword,
+			cfg.report.smtpFrom,
+			cfg.report.smtpTo,
+			cfg.outboundProxy.DialContext,
+		))
+	}
+	if len(senders) == 0 {
+		log.Fatal("Report interval is set but neither webhook nor SMTP delivery is configured")
+	}
+
+	renderer := report.NewRenderer(cfg.report.format, "Faucet summary: "+string(network.ChainID()))
+	return report.NewJob(application, cfg.report.interval, renderer, senders...)
+}
+
+// newFailoverCoordinator returns the coordinator verifying that the keys are loaded, the chain is reachable
+// and at least one funding account holds enough funds before the instance takes the lease.
+func newFailoverCoordinator(
+	cfg cfg,
+	cl coreum.Client,
+	addresses []chain.AccAddress,
+	transferAmount chain.Coin,
+) *failover.Coordinator {
+	return failover.NewCoordinator(
+		failover.NewFileLease(cfg.failover.leasePath),
+		cfg.failover.instanceID,
+		cfg.failover.leaseTTL,
+		// the lease is shared by the instances, so it is timed by the wall clock, never by the fast-forwarded one
+		clock.System{},
+		failover.Check{Name: "key", Fn: func(ctx context.Context) error {
+			for _, address := range addresses {
+				if err := cl.VerifyKey(address); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		failover.Check{Name: "chain", Fn: func(ctx context.Context) error {
+			_, err := cl.LatestHeight(ctx)
+			return err
+		}},
+		failover.Check{Name: "balance", Fn: func(ctx context.Context) error {
+			for _, address := range addresses {
+				balance, err := cl.Balance(ctx, address, transferAmount.Denom)
+				if err != nil {
+					return err
+				}
+				if balance.GTE(transferAmount.Amount) {
+					return nil
+				}
+			}
+			return errors.New("no funding account holds enough funds")
+		}},
+	)
+}
+
+// verifyDeployment refuses to start (or warns, depending on configured strictness) if the secrets and state
+// of the faucet are accessible by other users.
+func verifyDeployment(cfg cfg, log *zap.Logger) {
+	fsperm.SetPrivateUmask()
+
+	checker := fsperm.NewChecker(cfg.filePermCheck, log)
+	if err := checker.CheckNotRoot(); err != nil {
+		log.Fatal("Insecure deployment", zap.Error(err))
+	}
+	for _, path := range []string{cfg.mnemonicFilePath, cfg.storePath} {
+		if err := checker.CheckPrivate(path); err != nil {
+			log.Fatal("Insecure deployment", zap.Error(err))
+		}
+	}
+}
+
+func dialNode(cfg cfg, log *zap.Logger) *grpc.ClientConn {
 	nodeURL, err := url.Parse(cfg.node)
 	if err != nil {
 		log.Fatal(
@@ -146,7 +1049,7 @@ func addClient(cfg cfg, log *zap.Logger, clientCtx client.Context) client.Contex
 			panic(err)
 		}
 
-		return clientCtx.WithGRPCClient(grpcClient)
+		return grpcClient
 	}
 
 	// no-tls grpc
@@ -163,7 +1066,7 @@ func addClient(cfg cfg, log *zap.Logger, clientCtx client.Context) client.Contex
 		)
 	}
 
-	return clientCtx.WithGRPCClient(grpcClient)
+	return grpcClient
 }
 
 func setup() (context.Context, *zap.Logger, cfg) {
@@ -175,6 +1078,7 @@ func setup() (context.Context, *zap.Logger, cfg) {
 	flagSet := pflag.NewFlagSet("faucet", pflag.ExitOnError)
 	loggerFlagRegistry(flagSet)
 	cfg := getConfig(log, flagSet)
+	cfg.effective = config.Effective(flagSet, "", secretFlags...)
 	if cfg.help {
 		flagSet.PrintDefaults()
 	}
@@ -183,14 +1087,316 @@ func setup() (context.Context, *zap.Logger, cfg) {
 
 type cfg struct {
 	chainID          string
+	environment      string
 	node             string
 	mnemonicFilePath string
 	address          string
+	internalAddress  string
 	transferAmount   int64
+	maxTransfer      int64
+	dailyBudget      int64
+	lifetimeCap      int64
+	balanceThreshold int64
 	ipRateLimit      rateLimit
+	ipRateLimitAlgo  string
+	ipRateLimitBurst uint64
+	subnetRateLimit  subnetRateLimitConfig
+	firstTimeLimit   rateLimit
+	txConfirmations  int64
+	txAwait          coreum.AwaitConfig
+	subAccounts      uint32
+	broadcastWorkers int
+	maxQueueDepth    int
+	storePath        string
+	storeMigrate     bool
+	replicaPath      string
+	replicaInterval  time.Duration
+	adminToken       string
+	filePermCheck    fsperm.Mode
+	strictJSON       bool
+	exampleTx        bool
+	tenantNotify     bool
+	qrLinkTemplate   string
+	eventWebhooks    string
+	fundingHooks     string
+	powDifficulty    int
+	ownershipProof   bool
+	github           githubConfig
+	discord          discordConfig
+	identity         identityConfig
+	email            emailConfig
+	phone            phoneConfig
+	fundingLinks     fundingLinkConfig
+	oidc             oidc.Config
+	ipLists          ipListsConfig
+	geoIP            geoIPConfig
+	treasury         treasuryConfig
+	ipReputation     ipReputationConfig
+	abuseScore       abuseScoreConfig
+	namedAccountsKey string
+	preflight        bool
+	challengeDust    int64
+	txAttribution    bool
+	clockFastForward bool
+	trustedProxies   pkghttp.TrustedProxies
+	exemptCIDRs      pkghttp.IPNets
+	securityHeaders  pkghttp.SecurityHeaders
+	apiKeys          map[string]string
+	apiKeyQuotas     []limiter.Quota
+	outboundProxy    egress.Proxy
+	feeGasPrices     chain.DecCoins
+	denomBudgets     chain.Coins
+	tenantFeeDenoms  map[string]string
+	congestionLevels []app.CongestionLevel
+	ipPrivacy        ipPrivacyConfig
+	callbacks        callbackConfig
+	addressCooldown  time.Duration
+	gcInterval       time.Duration
+	failover         failoverConfig
+	report           reportConfig
+	experiment       experimentConfig
+	redis            redisConfig
+	tos              tosConfig
+	recaptcha        recaptchaConfig
+	hcaptcha         hcaptchaConfig
+	turnstileSecret  string
+	effective        []config.Entry
 	help             bool
 }
 
+type ipPrivacyConfig struct {
+	mode      string
+	retention time.Duration
+	salt      string
+}
+
+type callbackConfig struct {
+	allowedHosts []string
+	signingKey   string
+}
+
+type failoverConfig struct {
+	leasePath  string
+	instanceID string
+	leaseTTL   time.Duration
+}
+
+type subnetRateLimitConfig struct {
+	limit      rateLimit
+	ipv4Prefix int
+	ipv6Prefix int
+}
+
+type experimentConfig struct {
+	name           string
+	percent        int
+	transferAmount int64
+	ipRateLimit    rateLimit
+}
+
+type redisConfig struct {
+	url       string
+	keyPrefix string
+}
+
+type tosConfig struct {
+	version    string
+	signingKey string
+	tokenTTL   time.Duration
+}
+
+type abuseScoreConfig struct {
+	captchaThreshold float64
+	denyThreshold    float64
+	weights          map[string]string
+	frequency        rateLimit
+	balanceLimit     int64
+}
+
+// enabled tells if the requests are scored, any of the thresholds is required.
+func (c abuseScoreConfig) enabled() bool {
+	return c.captchaThreshold != 0 || c.denyThreshold != 0
+}
+
+// scoringConfig returns the configuration of the abuse scorer, the balance limit defaults to 10 transfer amounts.
+func (c abuseScoreConfig) scoringConfig(transferAmount chain.Coin) (app.AbuseScoringConfig, error) {
+	weights := map[string]float64{}
+	for signal, weight := range c.weights {
+		w, err := strconv.ParseFloat(weight, 64)
+		if err != nil {
+			return app.AbuseScoringConfig{}, errors.Wrapf(err, "invalid weight of abuse signal %q", signal)
+		}
+		weights[signal] = w
+	}
+	balanceLimit := chain.NewCoin(transferAmount.Denom, chain.NewInt(c.balanceLimit))
+	if c.balanceLimit == 0 {
+		balanceLimit = chain.NewCoin(transferAmount.Denom, transferAmount.Amount.MulRaw(10))
+	}
+	return app.AbuseScoringConfig{
+		Weights:          weights,
+		CaptchaThreshold: c.captchaThreshold,
+		DenyThreshold:    c.denyThreshold,
+		FrequencyLimit:   int(c.frequency.howMany),
+		FrequencyWindow:  c.frequency.period,
+		BalanceLimit:     balanceLimit,
+	}, nil
+}
+
+type recaptchaConfig struct {
+	secret   string
+	minScore float64
+}
+
+type githubConfig struct {
+	clientID     string
+	clientSecret string
+	callbackURL  string
+	minAge       time.Duration
+	minRepos     int
+	minFollowers int
+}
+
+type discordConfig struct {
+	clientID     string
+	clientSecret string
+	callbackURL  string
+	guildID      string
+	roles        []string
+}
+
+type identityConfig struct {
+	signingKey  string
+	sessionTTL  time.Duration
+	quota       uint64
+	quotaPeriod time.Duration
+	returnURL   string
+}
+
+func (c identityConfig) config() app.IdentityConfig {
+	return app.IdentityConfig{
+		SigningKey:  c.signingKey,
+		SessionTTL:  c.sessionTTL,
+		Quota:       c.quota,
+		QuotaPeriod: c.quotaPeriod,
+	}
+}
+
+type emailConfig struct {
+	linkURL        string
+	signingKey     string
+	linkTTL        time.Duration
+	subject        string
+	from           string
+	smtpAddress    string
+	smtpUsername   string
+	smtpPassword   string
+	sesRegion      string
+	sesAccessKeyID string
+	sesSecretKey   string
+}
+
+func (c emailConfig) config() app.EmailVerificationConfig {
+	return app.EmailVerificationConfig{
+		SigningKey: c.signingKey,
+		LinkURL:    c.linkURL,
+		TTL:        c.linkTTL,
+		Subject:    c.subject,
+	}
+}
+
+type phoneConfig struct {
+	twilioAccountSID string
+	twilioAuthToken  string
+	from             string
+	hashKey          string
+	codeTTL          time.Duration
+	quota            uint64
+	quotaPeriod      time.Duration
+	sendQuota        uint64
+	sendQuotaPeriod  time.Duration
+}
+
+func (c phoneConfig) config() app.PhoneVerificationConfig {
+	return app.PhoneVerificationConfig{
+		HashKey:         c.hashKey,
+		CodeTTL:         c.codeTTL,
+		Quota:           c.quota,
+		QuotaPeriod:     c.quotaPeriod,
+		SendQuota:       c.sendQuota,
+		SendQuotaPeriod: c.sendQuotaPeriod,
+	}
+}
+
+type fundingLinkConfig struct {
+	signingKey string
+	linkURL    string
+}
+
+func (c fundingLinkConfig) config() app.FundingLinkConfig {
+	return app.FundingLinkConfig{
+		SigningKey: c.signingKey,
+		LinkURL:    c.linkURL,
+	}
+}
+
+type ipListsConfig struct {
+	allowlist      string
+	denylist       string
+	reloadInterval time.Duration
+}
+
+type geoIPConfig struct {
+	db      string
+	allowed []string
+	blocked []string
+}
+
+type ipReputationConfig struct {
+	torList        string
+	vpnList        string
+	datacenterList string
+	providerURL    string
+	providerKey    string
+	cacheTTL       time.Duration
+	action         string
+}
+
+// sources returns the sources of the static lists by category.
+func (c ipReputationConfig) sources() map[iprep.Category]string {
+	sources := map[iprep.Category]string{}
+	for category, source := range map[iprep.Category]string{
+		iprep.CategoryTor:        c.torList,
+		iprep.CategoryVPN:        c.vpnList,
+		iprep.CategoryDatacenter: c.datacenterList,
+	} {
+		if source != "" {
+			sources[category] = source
+		}
+	}
+	return sources
+}
+
+type treasuryConfig struct {
+	signers   []string
+	threshold int
+}
+
+type hcaptchaConfig struct {
+	siteKey string
+	secret  string
+}
+
+type reportConfig struct {
+	interval     time.Duration
+	format       report.Format
+	webhookURL   string
+	smtpAddress  string
+	smtpUsername string
+	smtpPassword string
+	smtpFrom     string
+	smtpTo       []string
+}
+
 func parseRateLimit(limit string) (rateLimit, error) {
 	parts := strings.Split(limit, "/")
 	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
@@ -219,13 +1425,178 @@ type rateLimit struct {
 func getConfig(log *zap.Logger, flagSet *pflag.FlagSet) cfg {
 	var conf cfg
 	var ipRateLimit string
+	var experimentIPRateLimit string
+	var subnetRateLimit string
+	var firstTimeLimit string
+	var abuseFrequency string
+	var filePermCheck string
+	var reportFormat string
+	var trustedProxies []string
+	var exemptCIDRs []string
+	var apiKeys []string
+	var apiKeyQuotas []string
+	var outboundProxy string
+	var feeGasPrices string
+	var denomBudgets string
+	var tenantFeeDenoms []string
+	var congestionLevels []string
+	hostname, _ := os.Hostname()
 
-	flagSet.StringVar(&conf.chainID, flagChainID, string(constant.ChainIDDev), "The network chain ID")
+	flagSet.StringVar(&conf.chainID, flagChainID, string(chain.ChainIDDev), "The network chain ID")
+	flagSet.StringVar(&conf.environment, flagEnvironment, "", "environment label included in all responses, e.g. devnet or testnet (default derived from the chain ID)")
 	flagSet.StringVar(&conf.node, flagNode, "localhost:9090", "<host>:<port> to Tendermint GRPC endpoint for this chain")
-	flagSet.StringVar(&conf.address, flagAddress, ":8090", "<host>:<port> address to start listening for http requests")
+	flagSet.StringVar(&conf.address, flagAddress, ":8090", "<host>:<port> address to start listening for http requests, or systemd[:<name>] to take over the socket passed by systemd socket activation")
+	flagSet.StringVar(&conf.internalAddress, flagInternalAddress, "", "<host>:<port> address (or systemd:<name>) to serve admin API, metrics and pprof at instead of the public address, they are served at the public address except pprof if empty")
 	flagSet.Int64Var(&conf.transferAmount, flagTransferAmount, 1000000, "how much to transfer in each request")
+	flagSet.Int64Var(&conf.maxTransfer, flagMaxTransfer, 100000000, "absolute maximum of a single transfer, transfers above it are refused and reported as incidents, 0 disables the check")
+	flagSet.Int64Var(&conf.dailyBudget, flagDailyBudget, 0, "hard cap on the total amount sent in the rolling 24h window, requests are refused once it is exhausted, 0 means no cap")
+	flagSet.StringVar(&denomBudgets, flagDenomBudgets, "", "comma-separated daily budgets of other denoms granted by claim codes, e.g. 1000000000uusdc, each exhausted independently")
+	flagSet.Int64Var(&conf.lifetimeCap, flagLifetimeCap, 0, "cap on the cumulative amount sent to each address, the address is refused once it is reached, 0 means the amounts are tracked only")
+	flagSet.Int64Var(&conf.balanceThreshold, flagBalanceThreshold, 0, "refuse funding addresses already holding more than this amount of the transfer denom, 0 disables the check")
 	flagSet.StringVar(&conf.mnemonicFilePath, flagMnemonicFilePath, "mnemonic.txt", "path to file containing mnemonic for private keys, each line containing one mnemonic")
 	flagSet.StringVar(&ipRateLimit, flagIPRateLimit, "2/1h", "limit of requests per IP in the format <num-of-req>/<period>")
+	flagSet.StringVar(&conf.ipRateLimitAlgo, flagIPRateLimitAlgo, ratelimit.AlgorithmSlidingWindow, fmt.Sprintf("algorithm of the IP rate limit, one of %v", ratelimit.Algorithms))
+	flagSet.Uint64Var(&conf.ipRateLimitBurst, flagIPRateLimitBurst, 0, "number of requests per IP allowed at once by gcra and token-bucket algorithms, 0 means the limit of --ip-rate-limit")
+	flagSet.StringVar(&subnetRateLimit, flagSubnetRateLimit, "", "aggregate limit of requests per subnet in the format <num-of-req>/<period>, applied in addition to the IP rate limit, subnets are not limited if empty")
+	flagSet.IntVar(&conf.subnetRateLimit.ipv4Prefix, flagSubnetIPv4Prefix, 24, "prefix length of IPv4 subnets the subnet rate limit is applied to")
+	flagSet.IntVar(&conf.subnetRateLimit.ipv6Prefix, flagSubnetIPv6Prefix, 64, "prefix length of IPv6 subnets the subnet rate limit is applied to")
+	flagSet.StringVar(&firstTimeLimit, flagFirstTimeLimit, "", "limit of requests per IP in the format <num-of-req>/<period> funding addresses never funded before once the IP rate limit is used, such requests are refused if empty")
+	flagSet.Int64Var(&conf.txConfirmations, flagTxConfirmations, 1, "number of blocks (including the one containing the tx) required to report the transaction as confirmed")
+	flagSet.DurationVar(&conf.txAwait.InitialInterval, flagTxAwaitInitial, coreum.DefaultAwaitConfig().InitialInterval, "time after the broadcast the inclusion of the transaction in a block is queried first, the interval is doubled after each query")
+	flagSet.DurationVar(&conf.txAwait.MaxInterval, flagTxAwaitMax, coreum.DefaultAwaitConfig().MaxInterval, "maximum interval between queries for the inclusion of the transaction")
+	flagSet.DurationVar(&conf.txAwait.Timeout, flagTxAwaitTimeout, coreum.DefaultAwaitConfig().Timeout, "how long to wait for the inclusion of the transaction before the transfer is reported as failed")
+	flagSet.Uint32Var(&conf.subAccounts, flagSubAccounts, 0, "number of sub-accounts derived from each mnemonic at next HD indices, the balance is distributed equally among them at startup")
+	flagSet.IntVar(&conf.broadcastWorkers, flagBroadcastWorkers, 0, "number of workers signing and broadcasting transactions, 0 means one per funding account, it never exceeds the number of funding accounts")
+	flagSet.IntVar(&conf.maxQueueDepth, flagMaxQueueDepth, 0, "number of pending funding requests above which new requests are rejected, 0 means no limit")
+	flagSet.DurationVar(&conf.addressCooldown, flagAddressCooldown, 24*time.Hour, "how long the address can't be funded again after it is funded, the cooldown is disabled if 0")
+	flagSet.StringVar(&conf.storePath, flagStorePath, "faucet.db", "path to the file storing the state of the faucet")
+	flagSet.BoolVar(&conf.storeMigrate, flagStoreMigrate, true, "migrate the store created by the older version of the faucet on startup, the faucet refuses to start if migration is required but disabled")
+	flagSet.StringVar(&conf.replicaPath, flagReplicaPath, "", "path to the read-only copy of the store serving statistics and reports, reads are served by the store itself if empty")
+	flagSet.DurationVar(&conf.replicaInterval, flagReplicaInterval, time.Minute, "how often the read replica is refreshed from the store")
+	flagSet.DurationVar(&conf.gcInterval, flagGCInterval, 10*time.Minute, "how often expired claim codes and challenges are deleted, garbage collection is disabled if 0")
+	flagSet.StringVar(&conf.adminToken, flagAdminToken, "", "bearer token required to access admin API, admin API is disabled if empty")
+	flagSet.StringVar(&filePermCheck, flagFilePermCheck, string(fsperm.ModeWarn), "how to handle key and state files accessible by other users or running as root: off | warn | fail")
+	flagSet.BoolVar(&conf.strictJSON, flagStrictJSON, false, "reject JSON request bodies containing unknown fields or values of unexpected types")
+	flagSet.BoolVar(&conf.txAttribution, flagTxAttribution, false, "encode tenant, session and API key hash of each transfer into the memo of the transaction")
+	flagSet.BoolVar(&conf.preflight, flagPreflight, true, "simulate transfer from each funding account on startup, /readyz reports ready only once it succeeds")
+	flagSet.Int64Var(&conf.challengeDust, flagChallengeDust, 0, "amount sent upfront to pay the fee of the transaction answering the on-chain challenge, the challenge is disabled if 0")
+	flagSet.BoolVar(&conf.exampleTx, flagExampleTx, false, "include signed example transaction sending 1 unit from the generated account to itself in gen-funded response")
+	flagSet.StringVar(&conf.namedAccountsKey, flagNamedAccountsKey, "", "secret key of at least 16 characters encrypting the mnemonics of the accounts generated by gen-funded under the label, labels are not accepted if empty")
+	flagSet.BoolVar(&conf.clockFastForward, flagClockFastForward, false, "enable admin endpoint fast-forwarding the clock of rate limits and budgets, intended for test networks")
+	flagSet.StringVar(&conf.failover.leasePath, flagFailoverLease, "", "path to the lease file on storage shared by active and standby instances, failover is disabled if empty")
+	flagSet.StringVar(&conf.failover.instanceID, flagFailoverID, hostname, "ID of this instance used as the holder of the failover lease")
+	flagSet.DurationVar(&conf.failover.leaseTTL, flagFailoverTTL, 30*time.Second, "how long the failover lease is valid without renewal")
+	flagSet.StringSliceVar(&trustedProxies, flagTrustedProxies, pkghttp.DefaultTrustedProxies, "comma-separated CIDRs or IPs of the reverse proxies allowed to set X-Forwarded-For header, empty to ignore forwarded headers")
+	flagSet.StringVar(&conf.ipLists.allowlist, flagIPAllowlist, "", "path or URL of the list of CIDRs or IPs allowed to request funds, all IPs are allowed if empty")
+	flagSet.StringVar(&conf.ipLists.denylist, flagIPDenylist, "", "path or URL of the list of CIDRs or IPs denied to request funds")
+	flagSet.DurationVar(&conf.ipLists.reloadInterval, flagIPListReload, 5*time.Minute, "how often the IP allowlist, denylist and IP reputation lists are reloaded, they are reloaded on SIGHUP too")
+	flagSet.StringVar(&conf.geoIP.db, flagGeoIPDB, "", "path of the MaxMind GeoIP2 or GeoLite2 database locating the IPs by country, countries are not restricted if empty")
+	flagSet.StringSliceVar(&conf.geoIP.allowed, flagGeoIPAllowed, nil, "comma-separated ISO codes of the countries allowed to request funds, all countries are allowed if empty")
+	flagSet.StringSliceVar(&conf.geoIP.blocked, flagGeoIPBlocked, nil, "comma-separated ISO codes of the countries denied to request funds")
+	flagSet.StringVar(&conf.ipReputation.torList, flagIPRepTorList, "", "path or URL of the list of Tor exit IPs, e.g. https://check.torproject.org/torbulkexitlist")
+	flagSet.StringVar(&conf.ipReputation.vpnList, flagIPRepVPNList, "", "path or URL of the list of CIDRs or IPs of VPNs and proxies")
+	flagSet.StringVar(&conf.ipReputation.datacenterList, flagIPRepDCList, "", "path or URL of the list of CIDRs of cloud datacenters")
+	flagSet.StringVar(&conf.ipReputation.providerURL, flagIPRepProvider, "", "URL template of the IP reputation service with {ip} placeholder, asked for the IPs not found in the lists")
+	flagSet.StringVar(&conf.ipReputation.providerKey, flagIPRepKey, "", "API key of the IP reputation service replacing {key} in its URL template")
+	flagSet.DurationVar(&conf.ipReputation.cacheTTL, flagIPRepCacheTTL, time.Hour, "how long the answers of the IP reputation service are cached")
+	flagSet.StringVar(&conf.ipReputation.action, flagIPRepAction, string(iprep.ActionBlock), "what happens to the funding requests of the flagged IPs: block, captcha or score")
+	flagSet.Float64Var(&conf.abuseScore.captchaThreshold, flagAbuseCaptcha, 0, "abuse score from which the funding requests must carry the solved captcha, 0 means captcha is never required by the score")
+	flagSet.Float64Var(&conf.abuseScore.denyThreshold, flagAbuseDeny, 0, "abuse score from which the funding requests are refused, 0 means requests are never refused by the score")
+	flagSet.StringToStringVar(&conf.abuseScore.weights, flagAbuseWeights, map[string]string{
+		app.AbuseSignalIPReputation: "50",
+		app.AbuseSignalFrequency:    "30",
+		app.AbuseSignalBalance:      "20",
+		app.AbuseSignalCaptcha:      "40",
+	}, "weights of the abuse signals in the format <signal>=<weight>, signals are ip_reputation, frequency, balance and captcha")
+	flagSet.StringVar(&abuseFrequency, flagAbuseFrequency, "10/1h", "number of requests from the IP within the period making the frequency signal of the abuse score maximal, in the format <num-of-req>/<period>")
+	flagSet.Int64Var(&conf.abuseScore.balanceLimit, flagAbuseBalance, 0, "balance of the recipient making the balance signal of the abuse score maximal, 10 transfer amounts if 0")
+	flagSet.StringSliceVar(&conf.treasury.signers, flagTreasurySigners, nil, "comma-separated base64 public keys of the signers of the multisig treasury refilling the faucet, refills are disabled if empty")
+	flagSet.IntVar(&conf.treasury.threshold, flagTreasuryThresh, 0, "number of signatures required by the multisig treasury, all the signers if 0")
+	flagSet.StringSliceVar(&exemptCIDRs, flagExemptCIDRs, nil, "comma-separated CIDRs or IPs of internal networks bypassing the IP rate limit, e.g. office NAT")
+	flagSet.DurationVar(&conf.securityHeaders.HSTSMaxAge, flagHSTSMaxAge, 0, "max-age of Strict-Transport-Security header, e.g. 8760h, HSTS is disabled if 0")
+	flagSet.BoolVar(&conf.securityHeaders.HSTSIncludeSubdomains, flagHSTSSubdomains, false, "apply HSTS to subdomains too")
+	flagSet.StringVar(&conf.securityHeaders.ContentSecurityPolicy, flagCSP, pkghttp.DefaultContentSecurityPolicy, "value of Content-Security-Policy header, frame-ancestors directive is appended unless present")
+	flagSet.StringSliceVar(&conf.securityHeaders.FrameAncestors, flagFrameAncestors, nil, "comma-separated origins allowed to embed the faucet in frames, e.g. pages hosting the widget, framing is denied if empty")
+	flagSet.StringSliceVar(&apiKeys, flagAPIKeys, nil, "comma-separated API keys in the format <holder>:<key>, requests authenticated with X-Api-Key header are limited by the quota of the holder instead of the IP rate limit")
+	flagSet.StringSliceVar(&apiKeyQuotas, flagAPIKeyQuotas, []string{"100/1h", "1000/24h"}, "comma-separated quotas of each API key holder in the format <num-of-req>/<period>")
+	flagSet.StringVar(&feeGasPrices, flagFeeGasPrices, "", "comma-separated gas prices of additional fee denoms accepted by the chain, e.g. 0.05uusdc")
+	flagSet.StringSliceVar(&congestionLevels, flagCongestionLevels, nil, "comma-separated levels in the format <gas price multiplier>:<amount percent> reducing grants when the chain is congested, e.g. 2:50,5:10")
+	flagSet.StringVar(&conf.ipPrivacy.mode, flagIPPrivacy, app.IPPrivacyOff, fmt.Sprintf("how client IPs are stored in the history once the retention period elapses, one of %v", app.IPPrivacyModes))
+	flagSet.DurationVar(&conf.ipPrivacy.retention, flagIPPrivacyRetain, 24*time.Hour, "how long full client IPs are kept in the history before they are hashed or truncated, 0 means they are never stored")
+	flagSet.StringVar(&conf.ipPrivacy.salt, flagIPHashSalt, "", "secret salt of at least 16 characters used to hash client IPs, required if IPs are hashed")
+	flagSet.StringSliceVar(&conf.callbacks.allowedHosts, flagCallbackHosts, nil, "comma-separated hosts the callback URLs of funding requests may point to, *.example.com allows all subdomains, callbacks are disabled if empty")
+	flagSet.StringVar(&conf.callbacks.signingKey, flagCallbackKey, "", "secret key of at least 16 characters signing the callbacks with HMAC-SHA256, required if callbacks are enabled")
+	flagSet.StringSliceVar(&tenantFeeDenoms, flagTenantFeeDenoms, nil, "comma-separated fee denoms of tenants in the format <tenant>:<denom>, fees of other tenants are paid in the gas price denom of the chain")
+	flagSet.StringVar(&outboundProxy, flagOutboundProxy, "", "URL of HTTP(S) or SOCKS5 proxy used for outbound calls other than chain gRPC, e.g. socks5://proxy:1080, HTTP_PROXY environment variables are honored if empty")
+	flagSet.DurationVar(&conf.report.interval, flagReportInterval, 0, "how often to send the summary report covering the last interval, e.g. 168h, reporting is disabled if 0")
+	flagSet.StringVar(&reportFormat, flagReportFormat, string(report.FormatMarkdown), "format of the summary report: markdown | html")
+	flagSet.StringVar(&conf.report.webhookURL, flagReportWebhookURL, "", "URL of the webhook the summary report is posted to")
+	flagSet.StringVar(&conf.report.smtpAddress, flagReportSMTPAddr, "", "<host>:<port> of the SMTP server used to send the summary report by email")
+	flagSet.StringVar(&conf.report.smtpUsername, flagReportSMTPUser, "", "username used to authenticate to the SMTP server")
+	flagSet.StringVar(&conf.report.smtpPassword, flagReportSMTPPass, "", "password used to authenticate to the SMTP server")
+	flagSet.StringVar(&conf.report.smtpFrom, flagReportSMTPFrom, "", "sender of the summary report email")
+	flagSet.StringSliceVar(&conf.report.smtpTo, flagReportSMTPTo, nil, "comma-separated recipients of the summary report email")
+	flagSet.StringVar(&conf.experiment.name, flagExperimentName, "", "name of the experiment applying the variant of the policy to the share of clients, labels its metrics, the experiment is disabled if empty")
+	flagSet.IntVar(&conf.experiment.percent, flagExperimentPct, 0, "percent of clients assigned to the experiment group by the hash of their IP or API key")
+	flagSet.Int64Var(&conf.experiment.transferAmount, flagExperimentAmount, 0, "how much to transfer in each request of the experiment group, 0 means the same amount as the control group")
+	flagSet.StringVar(&experimentIPRateLimit, flagExperimentLimit, "", "limit of requests per IP of the experiment group in the format <num-of-req>/<period>, the IP rate limit of the control group applies if empty")
+	flagSet.StringVar(&conf.redis.url, flagRedisURL, "", "URL of Redis in the format redis[s]://[[user]:password@]host:port[/db] sharing IP rate limits and address cooldowns between replicas, they are kept by each replica if empty")
+	flagSet.StringVar(&conf.redis.keyPrefix, flagRedisKeyPrefix, "faucet:", "prefix of the keys stored in Redis, set it differently for faucets of different chains sharing the same Redis")
+	flagSet.StringVar(&conf.tos.version, flagToSVersion, "", "version of the terms of service the clients must accept before funding, acceptance is not required if empty")
+	flagSet.StringVar(&conf.tos.signingKey, flagToSSigningKey, "", "secret key of at least 16 characters signing the terms of service acceptance tokens, required if acceptance is required")
+	flagSet.DurationVar(&conf.tos.tokenTTL, flagToSTokenTTL, 10*time.Minute, "how long the terms of service acceptance token is valid once issued")
+	flagSet.StringVar(&conf.recaptcha.secret, flagRecaptchaSecret, "", "secret key of the reCAPTCHA site, fund requests must carry the solved reCAPTCHA token if set")
+	flagSet.Float64Var(&conf.recaptcha.minScore, flagRecaptchaScore, 0.5, "minimal score of reCAPTCHA v3 tokens, v2 tokens carry no score")
+	flagSet.StringVar(&conf.hcaptcha.siteKey, flagHcaptchaSiteKey, "", "site key of hCaptcha, tokens solved on other sites are rejected if set")
+	flagSet.StringVar(&conf.hcaptcha.secret, flagHcaptchaSecret, "", "secret key of hCaptcha account, fund requests must carry the solved hCaptcha token if set")
+	flagSet.StringVar(&conf.turnstileSecret, flagTurnstileSecret, "", "secret key of Cloudflare Turnstile widget, fund requests must carry the solved Turnstile token if set")
+	flagSet.BoolVar(&conf.tenantNotify, flagTenantNotify, false, "let the API key holders configure the webhooks, Slack channels and alert thresholds their events are delivered to")
+	flagSet.StringVar(&conf.eventWebhooks, flagEventWebhooks, "", "path to JSON file configuring the webhooks the events are delivered to, with their event filters and payload templates")
+	flagSet.StringVar(&conf.fundingHooks, flagFundingHooks, "", "path to JSON file configuring the hooks appending extra messages to the funding transaction of every address, e.g. wasm execute or assetft mint")
+	flagSet.IntVar(&conf.powDifficulty, flagPoWDifficulty, 0, "number of leading zero bits of the proof-of-work solution required by fund requests, 0 disables proof of work")
+	flagSet.BoolVar(&conf.ownershipProof, flagOwnershipProof, false, "require fund requests to carry the ADR-36 signature of the issued challenge by the key of the recipient address, proving the client controls it")
+	flagSet.StringVar(&conf.github.clientID, flagGitHubClientID, "", "client ID of the GitHub OAuth app, fund requests must carry the session of the GitHub account if set")
+	flagSet.StringVar(&conf.github.clientSecret, flagGitHubSecret, "", "client secret of the GitHub OAuth app")
+	flagSet.StringVar(&conf.github.callbackURL, flagGitHubCallback, "", "callback URL of the GitHub OAuth app, the public URL of /api/faucet/v1/auth/github/callback")
+	flagSet.DurationVar(&conf.github.minAge, flagGitHubMinAge, 30*24*time.Hour, "minimal age of the GitHub accounts authenticated by the clients")
+	flagSet.IntVar(&conf.github.minRepos, flagGitHubMinRepos, 0, "minimal number of public repositories of the GitHub accounts authenticated by the clients")
+	flagSet.IntVar(&conf.github.minFollowers, flagGitHubMinFollows, 0, "minimal number of followers of the GitHub accounts authenticated by the clients")
+	flagSet.StringVar(&conf.discord.clientID, flagDiscordClientID, "", "client ID of the Discord OAuth app, fund requests must carry the session of the Discord account if set")
+	flagSet.StringVar(&conf.discord.clientSecret, flagDiscordSecret, "", "client secret of the Discord OAuth app")
+	flagSet.StringVar(&conf.discord.callbackURL, flagDiscordCallback, "", "callback URL of the Discord OAuth app, the public URL of /api/faucet/v1/auth/discord/callback")
+	flagSet.StringVar(&conf.discord.guildID, flagDiscordGuild, "", "ID of the Discord guild the accounts authenticated by the clients must be members of")
+	flagSet.StringSliceVar(&conf.discord.roles, flagDiscordRoles, nil, "comma-separated IDs of the roles in the Discord guild the members must have any of, any member is accepted if empty")
+	flagSet.StringVar(&conf.identity.signingKey, flagIdentityKey, "", "secret key of at least 16 characters signing the identity sessions, required if an identity provider is configured")
+	flagSet.DurationVar(&conf.identity.sessionTTL, flagIdentityTTL, 24*time.Hour, "how long the identity session is valid once the client authenticates")
+	flagSet.Uint64Var(&conf.identity.quota, flagIdentityQuota, 1, "number of fund requests each authenticated account may make in the quota period, 0 means unlimited")
+	flagSet.DurationVar(&conf.identity.quotaPeriod, flagIdentityPeriod, 24*time.Hour, "period the quota of the authenticated accounts is renewed every, 0 means the quota is for the whole lifetime of the account")
+	flagSet.StringVar(&conf.oidc.IssuerURL, flagOIDCIssuer, "", "issuer URL of the OIDC provider, funding endpoints require the bearer token issued by it if set, e.g. for private devnets")
+	flagSet.StringVar(&conf.oidc.ClientID, flagOIDCClientID, "", "client ID of the faucet at the OIDC provider, tokens issued to other clients are refused")
+	flagSet.StringVar(&conf.oidc.Audience, flagOIDCAudience, "", "audience the OIDC tokens must be issued for, the client ID if empty")
+	flagSet.StringVar(&conf.identity.returnURL, flagIdentityReturn, "", "page of the faucet UI the clients are sent back to with the identity session in the URL fragment, the session is returned as JSON if empty")
+	flagSet.StringVar(&conf.email.linkURL, flagEmailLinkURL, "", "URL of the email/verify endpoint or the page of the faucet UI calling it, the token is appended as query parameter, enables funding through the links sent by email")
+	flagSet.StringVar(&conf.email.signingKey, flagEmailKey, "", "secret key of at least 16 characters signing the email links, required if email verification is enabled")
+	flagSet.DurationVar(&conf.email.linkTTL, flagEmailLinkTTL, 30*time.Minute, "how long the link sent by email may be opened")
+	flagSet.StringVar(&conf.email.subject, flagEmailSubject, "", "subject of the email with the link, a generic one if empty")
+	flagSet.StringVar(&conf.email.from, flagEmailFrom, "", "sender of the emails with the links")
+	flagSet.StringVar(&conf.email.smtpAddress, flagEmailSMTPAddr, "", "<host>:<port> of the SMTP server sending the emails with the links")
+	flagSet.StringVar(&conf.email.smtpUsername, flagEmailSMTPUser, "", "username used to authenticate to the SMTP server sending the emails with the links")
+	flagSet.StringVar(&conf.email.smtpPassword, flagEmailSMTPPass, "", "password used to authenticate to the SMTP server sending the emails with the links")
+	flagSet.StringVar(&conf.email.sesRegion, flagEmailSESRegion, "", "AWS region of Amazon SES sending the emails with the links, used instead of SMTP if set")
+	flagSet.StringVar(&conf.email.sesAccessKeyID, flagEmailSESKeyID, "", "access key ID of the IAM user allowed to call ses:SendEmail")
+	flagSet.StringVar(&conf.email.sesSecretKey, flagEmailSESSecret, "", "secret access key of the IAM user allowed to call ses:SendEmail")
+	flagSet.StringVar(&conf.phone.twilioAccountSID, flagTwilioSID, "", "SID of the Twilio account sending the codes by SMS, fund requests must carry the code sent to the phone number if set")
+	flagSet.StringVar(&conf.phone.twilioAuthToken, flagTwilioToken, "", "auth token of the Twilio account sending the codes by SMS")
+	flagSet.StringVar(&conf.phone.from, flagSMSFrom, "", "phone number of the Twilio account or SID of its messaging service sending the codes by SMS")
+	flagSet.StringVar(&conf.phone.hashKey, flagPhoneKey, "", "secret key of at least 16 characters hashing the phone numbers, required if phone verification is enabled")
+	flagSet.DurationVar(&conf.phone.codeTTL, flagPhoneCodeTTL, 10*time.Minute, "how long the code sent by SMS may be used")
+	flagSet.Uint64Var(&conf.phone.quota, flagPhoneQuota, 1, "number of addresses each phone number may fund in the quota period, 0 means unlimited")
+	flagSet.DurationVar(&conf.phone.quotaPeriod, flagPhonePeriod, 24*time.Hour, "period the phone quota is renewed after, 0 means the quota is never renewed")
+	flagSet.Uint64Var(&conf.phone.sendQuota, flagPhoneSendQuota, 3, "number of codes sent to each phone number in the send quota period, 0 means unlimited")
+	flagSet.DurationVar(&conf.phone.sendQuotaPeriod, flagPhoneSendPeriod, time.Hour, "period the phone send quota is renewed after, 0 means the quota is never renewed")
+	flagSet.StringVar(&conf.fundingLinks.signingKey, flagFundingLinkKey, "", "key of at least 16 characters signing the one-time funding links minted by the admin, the links are disabled if empty")
+	flagSet.StringVar(&conf.fundingLinks.linkURL, flagFundingLinkURL, "", "URL of the page redeeming the funding links, the token is appended as the token query parameter")
+	flagSet.StringVar(&conf.qrLinkTemplate, flagQRLinkTemplate, "", "wallet deep link rendered into QR codes on request, "+app.QRAddressPlaceholder+" is replaced with the address")
 	flagSet.BoolVarP(&conf.help, "help", "h", false, "prints help")
 	_ = flagSet.Parse(os.Args[1:])
 
@@ -239,36 +1610,264 @@ func getConfig(log *zap.Logger, flagSet *pflag.FlagSet) cfg {
 	if err != nil {
 		log.Fatal("Error getting config", zap.Error(err))
 	}
+
+	if experimentIPRateLimit != "" {
+		conf.experiment.ipRateLimit, err = parseRateLimit(experimentIPRateLimit)
+		if err != nil {
+			log.Fatal("Error parsing experiment IP rate limit", zap.Error(err))
+		}
+	}
+
+	if subnetRateLimit != "" {
+		conf.subnetRateLimit.limit, err = parseRateLimit(subnetRateLimit)
+		if err != nil {
+			log.Fatal("Error parsing subnet rate limit", zap.Error(err))
+		}
+	}
+
+	if firstTimeLimit != "" {
+		conf.firstTimeLimit, err = parseRateLimit(firstTimeLimit)
+		if err != nil {
+			log.Fatal("Error parsing first-time IP rate limit", zap.Error(err))
+		}
+	}
+
+	conf.abuseScore.frequency, err = parseRateLimit(abuseFrequency)
+	if err != nil {
+		log.Fatal("Error parsing abuse score frequency", zap.Error(err))
+	}
+
+	if conf.txAwait.InitialInterval <= 0 || conf.txAwait.MaxInterval < conf.txAwait.InitialInterval || conf.txAwait.Timeout <= 0 {
+		log.Fatal("Invalid polling for transaction inclusion, intervals and timeout must be positive and the maximum interval must not be shorter than the initial one",
+			zap.Duration("initialInterval", conf.txAwait.InitialInterval),
+			zap.Duration("maxInterval", conf.txAwait.MaxInterval),
+			zap.Duration("timeout", conf.txAwait.Timeout))
+	}
+
+	conf.filePermCheck, err = fsperm.ParseMode(filePermCheck)
+	if err != nil {
+		log.Fatal("Error parsing file permission check mode", zap.Error(err))
+	}
+
+	conf.trustedProxies, err = pkghttp.ParseTrustedProxies(trustedProxies)
+	if err != nil {
+		log.Fatal("Error parsing trusted proxies", zap.Error(err))
+	}
+
+	conf.exemptCIDRs, err = pkghttp.ParseIPNets(exemptCIDRs)
+	if err != nil {
+		log.Fatal("Error parsing rate limit exempt CIDRs", zap.Error(err))
+	}
+
+	conf.report.format, err = report.ParseFormat(reportFormat)
+	if err != nil {
+		log.Fatal("Error parsing report format", zap.Error(err))
+	}
+
+	conf.feeGasPrices, err = chain.ParseDecCoins(feeGasPrices)
+	if err != nil {
+		log.Fatal("Error parsing fee gas prices", zap.Error(err))
+	}
+	conf.denomBudgets, err = chain.ParseCoinsNormalized(denomBudgets)
+	if err != nil {
+		log.Fatal("Error parsing denom budgets", zap.Error(err))
+	}
+	conf.tenantFeeDenoms, err = parseTenantFeeDenoms(tenantFeeDenoms)
+	if err != nil {
+		log.Fatal("Error parsing tenant fee denoms", zap.Error(err))
+	}
+	conf.congestionLevels, err = parseCongestionLevels(congestionLevels)
+	if err != nil {
+		log.Fatal("Error parsing congestion levels", zap.Error(err))
+	}
+
+	conf.outboundProxy, err = egress.ParseProxy(outboundProxy)
+	if err != nil {
+		log.Fatal("Error parsing outbound proxy", zap.Error(err))
+	}
+
+	conf.apiKeys, err = parseAPIKeys(apiKeys)
+	if err != nil {
+		log.Fatal("Error parsing API keys", zap.Error(err))
+	}
+	for _, q := range apiKeyQuotas {
+		quota, err := parseRateLimit(q)
+		if err != nil {
+			log.Fatal("Error parsing API key quota", zap.Error(err), zap.String("quota", q))
+		}
+		conf.apiKeyQuotas = append(conf.apiKeyQuotas, limiter.Quota{Limit: quota.howMany, Period: quota.period})
+	}
 	return conf
 }
 
-func newKeyringFromFile(path string) (keyring.Keyring, []sdk.AccAddress, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, nil, errors.Wrapf(err, "unable to open file at %s", path)
-	}
-	defer file.Close()
-	scanner := bufio.NewScanner(file)
-	kr := keyring.NewInMemory()
-	var addresses []sdk.AccAddress
-	for scanner.Scan() {
-		mnemonic := scanner.Text()
-		tempKr := keyring.NewInMemory()
-		info, err := tempKr.NewAccount("temp", mnemonic, "", sdk.GetConfig().GetFullBIP44Path(), hd.Secp256k1)
+// newCaptchaVerifier returns the verifier of the configured captcha provider, nil if captcha is not required.
+// Captcha is verified through the outbound proxy.
+func newCaptchaVerifier(cfg cfg) (app.CaptchaVerifier, error) {
+	var configured []string
+	for flag, secret := range map[string]string{
+		flagRecaptchaSecret: cfg.recaptcha.secret,
+		flagHcaptchaSecret:  cfg.hcaptcha.secret,
+		flagTurnstileSecret: cfg.turnstileSecret,
+	} {
+		if secret != "" {
+			configured = append(configured, "--"+flag)
+		}
+	}
+	if len(configured) > 1 {
+		sort.Strings(configured)
+		return nil, errors.Errorf("only one captcha provider may be configured, got %s", strings.Join(configured, ", "))
+	}
+
+	client := cfg.outboundProxy.HTTPClient(outboundTimeout)
+	switch {
+	case cfg.recaptcha.secret != "":
+		return captcha.NewRecaptcha(cfg.recaptcha.secret, cfg.recaptcha.minScore, client)
+	case cfg.hcaptcha.secret != "":
+		return captcha.NewHcaptcha(cfg.hcaptcha.siteKey, cfg.hcaptcha.secret, client)
+	case cfg.turnstileSecret != "":
+		return captcha.NewTurnstile(cfg.turnstileSecret, client)
+	default:
+		return nil, nil
+	}
+}
+
+// newIdentityProviders returns the identity providers configured by the flags.
+func newIdentityProviders(cfg cfg) ([]app.IdentityProvider, error) {
+	var providers []app.IdentityProvider
+	if cfg.github.clientID != "" {
+		github, err := oauth.NewGitHub(cfg.github.clientID, cfg.github.clientSecret, cfg.github.callbackURL,
+			oauth.GitHubRequirements{
+				MinAccountAge:  cfg.github.minAge,
+				MinPublicRepos: cfg.github.minRepos,
+				MinFollowers:   cfg.github.minFollowers,
+			}, cfg.outboundProxy.HTTPClient(outboundTimeout))
 		if err != nil {
-			return nil, nil, errors.Wrapf(err, "unable to parse mnemonic key")
+			return nil, err
 		}
-		address := info.GetAddress()
-		addresses = append(addresses, address)
-		_, err = kr.NewAccount(address.String(), mnemonic, "", sdk.GetConfig().GetFullBIP44Path(), hd.Secp256k1)
+		providers = append(providers, github)
+	}
+	if cfg.discord.clientID != "" {
+		discord, err := oauth.NewDiscord(cfg.discord.clientID, cfg.discord.clientSecret, cfg.discord.callbackURL,
+			oauth.DiscordRequirements{
+				GuildID: cfg.discord.guildID,
+				RoleIDs: cfg.discord.roles,
+			}, cfg.outboundProxy.HTTPClient(outboundTimeout))
 		if err != nil {
-			return nil, nil, errors.Wrapf(err, "unable to parse mnemonic key")
+			return nil, err
 		}
+		providers = append(providers, discord)
+	}
+	return providers, nil
+}
+
+// newEmailSender returns the sender of the emails configured by the flags, either Amazon SES or SMTP.
+func newEmailSender(cfg cfg) (app.EmailSender, error) {
+	if cfg.email.from == "" {
+		return nil, errors.New("sender of the emails is required")
+	}
+	switch {
+	case cfg.email.sesRegion != "" && cfg.email.smtpAddress != "":
+		return nil, errors.New("either Amazon SES or SMTP server must be configured, not both")
+	case cfg.email.sesRegion != "":
+		return email.NewSES(cfg.email.sesRegion, email.SESCredentials{
+			AccessKeyID:     cfg.email.sesAccessKeyID,
+			SecretAccessKey: cfg.email.sesSecretKey,
+		}, cfg.email.from, cfg.outboundProxy.HTTPClient(outboundTimeout))
+	case cfg.email.smtpAddress != "":
+		return email.NewSMTP(cfg.email.smtpAddress, cfg.email.smtpUsername, cfg.email.smtpPassword, cfg.email.from,
+			cfg.outboundProxy.DialContext), nil
+	default:
+		return nil, errors.New("either Amazon SES or SMTP server is required to send the emails")
 	}
+}
 
-	if len(addresses) == 0 {
-		return nil, nil, errors.New("could not parse any mnemonic")
+// parseTenantFeeDenoms parses entries in the format <tenant>:<denom> into the map of tenants to their fee denoms.
+func parseTenantFeeDenoms(entries []string) (map[string]string, error) {
+	feeDenoms := map[string]string{}
+	for _, entry := range entries {
+		tenant, denom, ok := strings.Cut(entry, ":")
+		if !ok || tenant == "" || denom == "" {
+			return nil, errors.Errorf("invalid format of tenant fee denom %q, expected <tenant>:<denom>", entry)
+		}
+		feeDenoms[tenant] = denom
 	}
+	return feeDenoms, nil
+}
 
-	return kr, addresses, nil
+// parseCongestionLevels parses entries in the format <gas price multiplier>:<amount percent>.
+func parseCongestionLevels(entries []string) ([]app.CongestionLevel, error) {
+	levels := make([]app.CongestionLevel, 0, len(entries))
+	for _, entry := range entries {
+		multiplierStr, percentStr, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, errors.Errorf("invalid format of congestion level %q, expected <gas price multiplier>:<amount percent>", entry)
+		}
+		multiplier, err := strconv.ParseFloat(multiplierStr, 64)
+		if err != nil || multiplier <= 1 {
+			return nil, errors.Errorf("invalid gas price multiplier of congestion level %q, must be greater than 1", entry)
+		}
+		percent, err := strconv.ParseInt(percentStr, 10, 64)
+		if err != nil || percent < 0 || percent >= 100 {
+			return nil, errors.Errorf("invalid amount percent of congestion level %q, must be between 0 and 99", entry)
+		}
+		levels = append(levels, app.CongestionLevel{Multiplier: multiplier, AmountPercent: percent})
+	}
+	return levels, nil
+}
+
+// parseAPIKeys parses entries in the format <holder>:<key> into the map of keys to their holders.
+func parseAPIKeys(entries []string) (map[string]string, error) {
+	keys := map[string]string{}
+	for _, entry := range entries {
+		holder, key, ok := strings.Cut(entry, ":")
+		if !ok || holder == "" || key == "" {
+			return nil, errors.New("invalid format of API key entry, expected <holder>:<key>")
+		}
+		if _, exists := keys[key]; exists {
+			return nil, errors.Errorf("API key of %q is already used", holder)
+		}
+		keys[key] = holder
+	}
+	return keys, nil
+}
+
+// newRedisClient returns the client of Redis, verifying the server is reachable, so the misconfigured faucet doesn't
+// start rejecting all the requests.
+func newRedisClient(ctx context.Context, log *zap.Logger, url string) *redis.Client {
+	client, err := redis.NewClient(url)
+	if err != nil {
+		log.Fatal("Error parsing Redis URL", zap.Error(err))
+	}
+	pingCtx, cancel := context.WithTimeout(ctx, outboundTimeout)
+	defer cancel()
+	if err := client.Ping(pingCtx); err != nil {
+		log.Fatal("Unable to connect to Redis", zap.Error(err))
+	}
+	return client
+}
+
+// newRateLimitStore returns the store of the rate limiter states shared through Redis if it is configured,
+// otherwise the in-memory store returned also as the second value, so its expired states can be pruned.
+func newRateLimitStore(
+	redisClient *redis.Client,
+	prefix string,
+	clk clock.Clock,
+) (ratelimit.Store, *ratelimit.MemoryStore) {
+	if redisClient != nil {
+		return ratelimit.NewKVStore(redisClient, prefix, clk), nil
+	}
+	memoryStore := ratelimit.NewMemoryStore(clk)
+	return memoryStore, memoryStore
+}
+
+// limiterSnapshot returns the number of keys tracked by each in-memory limiter store, limiters sharing the state
+// through Redis are not included.
+func limiterSnapshot(stores map[string]*ratelimit.MemoryStore) map[string]int {
+	keys := map[string]int{}
+	for name, memoryStore := range stores {
+		if memoryStore != nil {
+			keys[name] = memoryStore.Len()
+		}
+	}
+	return keys
 }
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/faucet/pkg/config"
+)
+
+func TestSecretFlagsRedacted(t *testing.T) {
+	requireT := require.New(t)
+
+	args := os.Args
+	os.Args = []string{"faucet"}
+	t.Cleanup(func() { os.Args = args })
+	t.Setenv("IP_REPUTATION_PROVIDER_URL", "https://pro.ip-api.com/json/{ip}?key={key}")
+	t.Setenv("IP_REPUTATION_PROVIDER_KEY", "s3cret")
+
+	flagSet := pflag.NewFlagSet("faucet", pflag.ContinueOnError)
+	getConfig(zaptest.NewLogger(t), flagSet)
+	entries := map[string]string{}
+	for _, entry := range config.Effective(flagSet, "", secretFlags...) {
+		entries[entry.Name] = entry.Value
+	}
+	requireT.Equal(config.Redacted, entries[flagIPRepKey])
+	requireT.Equal("https://pro.ip-api.com/json/{ip}?key={key}", entries[flagIPRepProvider])
+
+}
@@ -0,0 +1,281 @@
+// Package notify delivers the events of the tenants' requests to the webhooks and Slack channels configured
+// by the tenants, raising alerts once the number of failed or blocked requests crosses their thresholds.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/coreum-tools/pkg/parallel"
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+const (
+	// queueSize is the number of events queued for each tenant before new events of the tenant are dropped.
+	queueSize = 256
+	// refreshInterval is how often the settings of the tenants are reloaded, so the changes are applied.
+	refreshInterval = 30 * time.Second
+	timeout         = 10 * time.Second
+	// AlertWindow is the period the events are counted in against the alert thresholds. Once raised, the alert
+	// is not raised again within the window.
+	AlertWindow = time.Hour
+	// KindAlert is the kind of the payload raising the alert.
+	KindAlert = "alert"
+)
+
+// Alert describes the crossed threshold.
+type Alert struct {
+	Event  app.EventKind `json:"event"`
+	Count  int           `json:"count"`
+	Window string        `json:"window"`
+}
+
+// Payload is the JSON body posted to the webhooks of the tenant.
+type Payload struct {
+	Tenant string    `json:"tenant"`
+	Kind   string    `json:"kind"`
+	Time   time.Time `json:"time"`
+	// RequestID, Address, TxHash and Reason describe the event, Alert is set instead if Kind is alert.
+	RequestID string `json:"requestId,omitempty"`
+	Address   string `json:"address,omitempty"`
+	TxHash    string `json:"txHash,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Alert     *Alert `json:"alert,omitempty"`
+}
+
+// SettingsSource provides the notification settings of the tenants.
+type SettingsSource interface {
+	AllTenantNotifications(ctx context.Context) ([]app.TenantNotifications, error)
+}
+
+// Router delivers the events of each tenant to its destinations. Every tenant has its own queue and worker,
+// so the failing webhook of one tenant never delays the notifications of the others.
+type Router struct {
+	source SettingsSource
+	client *http.Client
+
+	mu      sync.RWMutex
+	tenants map[string]*tenant
+}
+
+type tenant struct {
+	events chan app.Event
+	done   chan struct{}
+
+	mu       sync.Mutex
+	settings app.TenantNotifications
+
+	// occurrences and alertedAt are accessed by the worker of the tenant only.
+	occurrences map[app.EventKind][]time.Time
+	alertedAt   map[app.EventKind]time.Time
+}
+
+// NewRouter returns the router delivering events by the client. Webhooks are configured by the tenants,
+// so the client returned by callback.NewHTTPClient, refusing to connect to the internal network, should be used.
+func NewRouter(source SettingsSource, client *http.Client) *Router {
+	return &Router{
+		source:  source,
+		client:  client,
+		tenants: map[string]*tenant{},
+	}
+}
+
+// Handle is the event bus subscriber queueing the event to the tenant of the request. Events of the tenants
+// without settings are ignored.
+func (r *Router) Handle(ctx context.Context, event app.Event) {
+	r.mu.RLock()
+	t := r.tenants[event.Requester.Tenant]
+	r.mu.RUnlock()
+	if t == nil {
+		return
+	}
+
+	select {
+	case t.events <- event:
+	default:
+		logger.Get(ctx).Warn("Notifications of tenant are too slow, dropping event",
+			zap.String("tenant", event.Requester.Tenant), zap.String("kind", string(event.Kind)))
+	}
+}
+
+// Run reloads the settings of the tenants periodically and runs the workers delivering their events.
+func (r *Router) Run(ctx context.Context) error {
+	return parallel.Run(ctx, func(ctx context.Context, spawn parallel.SpawnFn) error {
+		spawn("refresh", parallel.Fail, func(ctx context.Context) error {
+			for {
+				if err := r.refresh(ctx, spawn); err != nil {
+					logger.Get(ctx).Error("Loading tenant notifications failed", zap.Error(err))
+				}
+				select {
+				case <-ctx.Done():
+					return errors.WithStack(ctx.Err())
+				case <-time.After(refreshInterval):
+				}
+			}
+		})
+		return nil
+	})
+}
+
+// refresh applies the current settings, starting the workers of new tenants and stopping the workers of
+// the tenants whose settings are deleted.
+func (r *Router) refresh(ctx context.Context, spawn parallel.SpawnFn) error {
+	all, err := r.source.AllTenantNotifications(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	configured := map[string]bool{}
+	for _, settings := range all {
+		configured[settings.Tenant] = true
+		if t, exists := r.tenants[settings.Tenant]; exists {
+			t.mu.Lock()
+			t.settings = settings
+			t.mu.Unlock()
+			continue
+		}
+		t := &tenant{
+			events:      make(chan app.Event, queueSize),
+			done:        make(chan struct{}),
+			settings:    settings,
+			occurrences: map[app.EventKind][]time.Time{},
+			alertedAt:   map[app.EventKind]time.Time{},
+		}
+		r.tenants[settings.Tenant] = t
+		spawn("tenant-"+settings.Tenant, parallel.Continue, func(ctx context.Context) error {
+			return r.work(ctx, t)
+		})
+	}
+	for name, t := range r.tenants {
+		if !configured[name] {
+			close(t.done)
+			delete(r.tenants, name)
+		}
+	}
+	return nil
+}
+
+func (r *Router) work(ctx context.Context, t *tenant) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return errors.WithStack(ctx.Err())
+		case <-t.done:
+			return nil
+		case event := <-t.events:
+			t.mu.Lock()
+			settings := t.settings
+			t.mu.Unlock()
+			r.deliver(ctx, t, settings, event)
+		}
+	}
+}
+
+func (r *Router) deliver(ctx context.Context, t *tenant, settings app.TenantNotifications, event app.Event) {
+	if settings.Routes(event.Kind) {
+		r.post(ctx, settings.Tenant, settings.Webhooks, Payload{
+			Tenant:    settings.Tenant,
+			Kind:      string(event.Kind),
+			Time:      event.Time,
+			RequestID: event.Requester.RequestID,
+			Address:   event.Address,
+			TxHash:    event.TxHash,
+			Reason:    event.Reason,
+		})
+	}
+
+	alert, raised := t.count(settings.Alerts, event)
+	if !raised {
+		return
+	}
+	r.post(ctx, settings.Tenant, settings.Webhooks, Payload{
+		Tenant: settings.Tenant,
+		Kind:   KindAlert,
+		Time:   event.Time,
+		Alert:  &alert,
+	})
+	text := fmt.Sprintf("Faucet alert for tenant %s: %d %s requests in the last %s",
+		settings.Tenant, alert.Count, alert.Event, alert.Window)
+	r.post(ctx, settings.Tenant, settings.SlackWebhooks, struct {
+		Text string `json:"text"`
+	}{Text: text})
+}
+
+// count records the event and returns the alert if its threshold is crossed.
+func (t *tenant) count(thresholds app.AlertThresholds, event app.Event) (Alert, bool) {
+	var threshold int
+	switch event.Kind {
+	case app.EventFailed:
+		threshold = thresholds.Failed
+	case app.EventBlocked:
+		threshold = thresholds.Blocked
+	default:
+	}
+	if threshold <= 0 {
+		return Alert{}, false
+	}
+
+	occurrences := append(t.occurrences[event.Kind], event.Time)
+	for len(occurrences) > 0 && event.Time.Sub(occurrences[0]) >= AlertWindow {
+		occurrences = occurrences[1:]
+	}
+	t.occurrences[event.Kind] = occurrences
+	if len(occurrences) < threshold {
+		return Alert{}, false
+	}
+	if alertedAt, ok := t.alertedAt[event.Kind]; ok && event.Time.Sub(alertedAt) < AlertWindow {
+		return Alert{}, false
+	}
+	t.alertedAt[event.Kind] = event.Time
+	return Alert{Event: event.Kind, Count: len(occurrences), Window: AlertWindow.String()}, true
+}
+
+func (r *Router) post(ctx context.Context, tenantName string, webhooks []string, payload interface{}) {
+	if len(webhooks) == 0 {
+		return
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		logger.Get(ctx).Error("Encoding notification failed", zap.Error(err))
+		return
+	}
+	for _, webhook := range webhooks {
+		if err := r.send(ctx, webhook, body); err != nil {
+			logger.Get(ctx).Warn("Sending tenant notification failed", zap.String("tenant", tenantName), zap.Error(err))
+		}
+	}
+}
+
+func (r *Router) send(ctx context.Context, webhook string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode > 299 {
+		return errors.Errorf("webhook returned non 2xx response, status: %d", resp.StatusCode)
+	}
+	return nil
+}
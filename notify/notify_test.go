@@ -0,0 +1,110 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+type mockSource []app.TenantNotifications
+
+func (m mockSource) AllTenantNotifications(ctx context.Context) ([]app.TenantNotifications, error) {
+	return m, nil
+}
+
+func TestRouter(t *testing.T) {
+	requireT := require.New(t)
+	ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), zaptest.NewLogger(t)))
+	t.Cleanup(cancel)
+
+	release := make(chan struct{})
+	stuck := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(stuck.Close)
+	t.Cleanup(func() { close(release) })
+
+	bodies := make(chan []byte, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+	}))
+	t.Cleanup(srv.Close)
+
+	router := NewRouter(mockSource{
+		{Tenant: "stuck", Webhooks: []string{stuck.URL}},
+		{
+			Tenant:        "ci",
+			Webhooks:      []string{srv.URL + "/events"},
+			SlackWebhooks: []string{srv.URL + "/slack"},
+			Events:        []app.EventKind{app.EventFailed},
+			Alerts:        app.AlertThresholds{Failed: 2},
+		},
+	}, srv.Client())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = router.Run(ctx)
+	}()
+	// router logs, so it must finish before the test
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	requireT.Eventually(func() bool {
+		router.mu.RLock()
+		defer router.mu.RUnlock()
+		return len(router.tenants) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	// the webhook of another tenant not responding doesn't delay the notifications
+	router.Handle(ctx, app.Event{Kind: app.EventFailed, Time: now, Requester: app.Requester{Tenant: "stuck"}})
+	// events of other kinds and of other tenants are not delivered
+	router.Handle(ctx, app.Event{Kind: app.EventBroadcast, Time: now, Requester: app.Requester{Tenant: "ci"}})
+	router.Handle(ctx, app.Event{Kind: app.EventFailed, Time: now, Requester: app.Requester{Tenant: "other"}})
+	router.Handle(ctx, app.Event{
+		Kind: app.EventFailed, Time: now, Requester: app.Requester{Tenant: "ci", RequestID: "rq1"}, Reason: "err",
+	})
+
+	var payload Payload
+	requireT.NoError(json.Unmarshal(receive(t, bodies), &payload))
+	requireT.Equal(Payload{Tenant: "ci", Kind: "failed", Time: now, RequestID: "rq1", Reason: "err"}, payload)
+
+	// the second failure crosses the threshold
+	router.Handle(ctx, app.Event{Kind: app.EventFailed, Time: now.Add(time.Minute), Requester: app.Requester{Tenant: "ci"}})
+	receive(t, bodies)
+	requireT.NoError(json.Unmarshal(receive(t, bodies), &payload))
+	requireT.Equal(KindAlert, payload.Kind)
+	requireT.Equal(&Alert{Event: app.EventFailed, Count: 2, Window: "1h0m0s"}, payload.Alert)
+	var slack struct {
+		Text string `json:"text"`
+	}
+	requireT.NoError(json.Unmarshal(receive(t, bodies), &slack))
+	requireT.Equal("Faucet alert for tenant ci: 2 failed requests in the last 1h0m0s", slack.Text)
+
+	// the alert is not raised again within the window
+	router.Handle(ctx, app.Event{Kind: app.EventFailed, Time: now.Add(2 * time.Minute), Requester: app.Requester{Tenant: "ci"}})
+	receive(t, bodies)
+	requireT.Never(func() bool { return len(bodies) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+}
+
+func receive(t *testing.T, bodies <-chan []byte) []byte {
+	select {
+	case body := <-bodies:
+		return body
+	case <-time.After(time.Second):
+		t.Fatal("notification not received")
+		return nil
+	}
+}
@@ -0,0 +1,137 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// Endpoints of Discord.
+const (
+	DiscordAuthURL   = "https://discord.com/oauth2/authorize"
+	DiscordTokenURL  = "https://discord.com/api/oauth2/token"
+	DiscordUserURL   = "https://discord.com/api/users/@me"
+	DiscordMemberURL = "https://discord.com/api/users/@me/guilds/%s/member"
+)
+
+// ProviderDiscord is the name of the Discord identity provider.
+const ProviderDiscord = "discord"
+
+// DiscordRequirements are the requirements the Discord accounts must meet, so only the members of the community
+// are funded.
+type DiscordRequirements struct {
+	// GuildID is the server the account must be member of.
+	GuildID string
+	// RoleIDs are the roles in the guild the member must have any of, any member is accepted if empty.
+	RoleIDs []string
+}
+
+// Discord authenticates the users by their Discord accounts. The membership in the guild is read
+// by the guilds.members.read scope, so the faucet doesn't need a bot in the guild.
+type Discord struct {
+	config       Config
+	requirements DiscordRequirements
+	userURL      string
+	memberURL    string
+	client       *http.Client
+}
+
+// NewDiscord returns the provider of the OAuth app registered at Discord with the callback URL.
+func NewDiscord(
+	clientID, clientSecret, callbackURL string,
+	requirements DiscordRequirements,
+	client *http.Client,
+) (*Discord, error) {
+	config := Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AuthURL:      DiscordAuthURL,
+		TokenURL:     DiscordTokenURL,
+		CallbackURL:  callbackURL,
+		Scopes:       []string{"identify", "guilds.members.read"},
+	}
+	if err := config.validate("Discord"); err != nil {
+		return nil, err
+	}
+	if requirements.GuildID == "" {
+		return nil, errors.New("Discord guild ID is required")
+	}
+	return &Discord{
+		config:       config,
+		requirements: requirements,
+		userURL:      DiscordUserURL,
+		memberURL:    DiscordMemberURL,
+		client:       client,
+	}, nil
+}
+
+// Name returns the name of the provider.
+func (d *Discord) Name() string {
+	return ProviderDiscord
+}
+
+// LoginURL returns the URL of Discord authorizing the faucet to read the account and the guild membership
+// of the user.
+func (d *Discord) LoginURL(state string) string {
+	return d.config.AuthCodeURL(state)
+}
+
+// Authenticate exchanges the code for the access token and checks the account is member of the guild having
+// any of the roles.
+func (d *Discord) Authenticate(ctx context.Context, code string) (app.Identity, error) {
+	accessToken, err := d.config.Exchange(ctx, d.client, code)
+	if err != nil {
+		return app.Identity{}, err
+	}
+	var user struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	}
+	if _, err := getJSON(ctx, d.client, d.userURL, accessToken, &user); err != nil {
+		return app.Identity{}, err
+	}
+	if user.ID == "" {
+		return app.Identity{}, errors.Wrap(app.ErrIdentityUnavailable, "Discord returned the user without ID")
+	}
+
+	var member struct {
+		Roles []string `json:"roles"`
+	}
+	status, err := getJSON(ctx, d.client, fmt.Sprintf(d.memberURL, url.PathEscape(d.requirements.GuildID)),
+		accessToken, &member)
+	if status == http.StatusNotFound {
+		return app.Identity{}, errors.Wrapf(app.ErrIdentityRejected, "Discord account %s must be member of the guild",
+			user.Username)
+	}
+	if err != nil {
+		return app.Identity{}, err
+	}
+	if !hasAnyRole(member.Roles, d.requirements.RoleIDs) {
+		return app.Identity{}, errors.Wrapf(app.ErrIdentityRejected,
+			"Discord account %s must have any of the required roles in the guild", user.Username)
+	}
+	return app.Identity{
+		Provider: ProviderDiscord,
+		Subject:  user.ID,
+		Name:     user.Username,
+	}, nil
+}
+
+func hasAnyRole(roles, required []string) bool {
+	if len(required) == 0 {
+		return true
+	}
+	for _, role := range roles {
+		for _, r := range required {
+			if role == r {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestDiscord(t *testing.T) {
+	requireT := require.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/token":
+			requireT.NoError(r.ParseForm())
+			switch code := r.PostForm.Get("code"); code {
+			case "member", "guest", "outsider", "down":
+				_, _ = w.Write([]byte(`{"access_token":"token-` + code + `","token_type":"Bearer"}`))
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			}
+		case "/users/@me":
+			switch r.Header.Get("Authorization") {
+			case "Bearer token-member":
+				_, _ = w.Write([]byte(`{"id":"80351110224678912","username":"nelly"}`))
+			case "Bearer token-guest", "Bearer token-outsider", "Bearer token-down":
+				_, _ = w.Write([]byte(`{"id":"80351110224678913","username":"guest"}`))
+			default:
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		case "/users/@me/guilds/guild1/member":
+			switch r.Header.Get("Authorization") {
+			case "Bearer token-member":
+				_, _ = w.Write([]byte(`{"roles":["everyone","developer"]}`))
+			case "Bearer token-guest":
+				_, _ = w.Write([]byte(`{"roles":["everyone"]}`))
+			case "Bearer token-outsider":
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"Unknown Guild","code":10004}`))
+			default:
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	callbackURL := "https://faucet.example.com/api/faucet/v1/auth/discord/callback"
+	_, err := NewDiscord("client-id", "client-secret", callbackURL, DiscordRequirements{}, srv.Client())
+	requireT.Error(err)
+
+	d, err := NewDiscord("client-id", "client-secret", callbackURL, DiscordRequirements{
+		GuildID: "guild1",
+		RoleIDs: []string{"developer", "validator"},
+	}, srv.Client())
+	requireT.NoError(err)
+	d.config.TokenURL = srv.URL + "/token"
+	d.userURL = srv.URL + "/users/@me"
+	d.memberURL = srv.URL + "/users/@me/guilds/%s/member"
+
+	loginURL, err := url.Parse(d.LoginURL("state1"))
+	requireT.NoError(err)
+	requireT.Equal("discord.com", loginURL.Host)
+	requireT.Equal("identify guilds.members.read", loginURL.Query().Get("scope"))
+	requireT.Equal("state1", loginURL.Query().Get("state"))
+
+	ctx := context.Background()
+	identity, err := d.Authenticate(ctx, "member")
+	requireT.NoError(err)
+	requireT.Equal(app.Identity{Provider: ProviderDiscord, Subject: "80351110224678912", Name: "nelly"}, identity)
+
+	_, err = d.Authenticate(ctx, "guest")
+	requireT.ErrorIs(err, app.ErrIdentityRejected)
+	_, err = d.Authenticate(ctx, "outsider")
+	requireT.ErrorIs(err, app.ErrIdentityRejected)
+	_, err = d.Authenticate(ctx, "down")
+	requireT.ErrorIs(err, app.ErrIdentityUnavailable)
+	_, err = d.Authenticate(ctx, "invalid")
+	requireT.ErrorIs(err, app.ErrIdentityFailed)
+}
@@ -0,0 +1,120 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// Endpoints of GitHub.
+const (
+	GitHubAuthURL  = "https://github.com/login/oauth/authorize"
+	GitHubTokenURL = "https://github.com/login/oauth/access_token"
+	GitHubUserURL  = "https://api.github.com/user"
+)
+
+// ProviderGitHub is the name of the GitHub identity provider.
+const ProviderGitHub = "github"
+
+// GitHubRequirements are the requirements the GitHub accounts must meet, so the accounts created to farm the funds
+// are refused. Zero values don't require anything.
+type GitHubRequirements struct {
+	// MinAccountAge is the minimal time since the account was created.
+	MinAccountAge time.Duration
+	// MinPublicRepos is the minimal number of the public repositories of the account.
+	MinPublicRepos int
+	// MinFollowers is the minimal number of the followers of the account.
+	MinFollowers int
+}
+
+// GitHub authenticates the users by their GitHub accounts. No scope is requested, the public profile is enough
+// to check the requirements.
+type GitHub struct {
+	config       Config
+	requirements GitHubRequirements
+	userURL      string
+	client       *http.Client
+	now          func() time.Time
+}
+
+// NewGitHub returns the provider of the OAuth app registered at GitHub with the callback URL.
+func NewGitHub(
+	clientID, clientSecret, callbackURL string,
+	requirements GitHubRequirements,
+	client *http.Client,
+) (*GitHub, error) {
+	config := Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AuthURL:      GitHubAuthURL,
+		TokenURL:     GitHubTokenURL,
+		CallbackURL:  callbackURL,
+	}
+	if err := config.validate("GitHub"); err != nil {
+		return nil, err
+	}
+	if requirements.MinAccountAge < 0 || requirements.MinPublicRepos < 0 || requirements.MinFollowers < 0 {
+		return nil, errors.New("GitHub account requirements must not be negative")
+	}
+	return &GitHub{
+		config:       config,
+		requirements: requirements,
+		userURL:      GitHubUserURL,
+		client:       client,
+		now:          time.Now,
+	}, nil
+}
+
+// Name returns the name of the provider.
+func (g *GitHub) Name() string {
+	return ProviderGitHub
+}
+
+// LoginURL returns the URL of GitHub authorizing the faucet to read the public profile of the user.
+func (g *GitHub) LoginURL(state string) string {
+	return g.config.AuthCodeURL(state)
+}
+
+// Authenticate exchanges the code for the access token and checks the account of the user meets the requirements.
+func (g *GitHub) Authenticate(ctx context.Context, code string) (app.Identity, error) {
+	accessToken, err := g.config.Exchange(ctx, g.client, code)
+	if err != nil {
+		return app.Identity{}, err
+	}
+	var user struct {
+		ID          int64     `json:"id"`
+		Login       string    `json:"login"`
+		CreatedAt   time.Time `json:"created_at"`
+		PublicRepos int       `json:"public_repos"`
+		Followers   int       `json:"followers"`
+	}
+	if _, err := getJSON(ctx, g.client, g.userURL, accessToken, &user); err != nil {
+		return app.Identity{}, err
+	}
+	if user.ID == 0 {
+		return app.Identity{}, errors.Wrap(app.ErrIdentityUnavailable, "GitHub returned the user without ID")
+	}
+
+	r := g.requirements
+	switch age := g.now().Sub(user.CreatedAt); {
+	case age < r.MinAccountAge:
+		return app.Identity{}, errors.Wrapf(app.ErrIdentityRejected, "GitHub account %s must be older than %s",
+			user.Login, r.MinAccountAge)
+	case user.PublicRepos < r.MinPublicRepos:
+		return app.Identity{}, errors.Wrapf(app.ErrIdentityRejected,
+			"GitHub account %s must have at least %d public repositories", user.Login, r.MinPublicRepos)
+	case user.Followers < r.MinFollowers:
+		return app.Identity{}, errors.Wrapf(app.ErrIdentityRejected,
+			"GitHub account %s must have at least %d followers", user.Login, r.MinFollowers)
+	}
+	return app.Identity{
+		Provider: ProviderGitHub,
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Name:     user.Login,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestGitHub(t *testing.T) {
+	requireT := require.New(t)
+
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/token":
+			requireT.NoError(r.ParseForm())
+			requireT.Equal("client-secret", r.PostForm.Get("client_secret"))
+			switch code := r.PostForm.Get("code"); code {
+			case "old", "new", "idle":
+				_, _ = w.Write([]byte(`{"access_token":"token-` + code + `","token_type":"bearer"}`))
+			case "broken":
+				w.WriteHeader(http.StatusBadGateway)
+			default:
+				_, _ = w.Write([]byte(`{"error":"bad_verification_code"}`))
+			}
+		case "/user":
+			switch r.Header.Get("Authorization") {
+			case "Bearer token-old":
+				_, _ = w.Write([]byte(`{"id":42,"login":"octocat","created_at":"2011-01-25T18:44:36Z",` +
+					`"public_repos":8,"followers":100}`))
+			case "Bearer token-new":
+				_, _ = w.Write([]byte(`{"id":43,"login":"farmer","created_at":"2023-05-31T00:00:00Z",` +
+					`"public_repos":8,"followers":100}`))
+			case "Bearer token-idle":
+				_, _ = w.Write([]byte(`{"id":44,"login":"idle","created_at":"2011-01-25T18:44:36Z",` +
+					`"public_repos":0,"followers":100}`))
+			default:
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := NewGitHub("client-id", "", "https://faucet.example.com/callback", GitHubRequirements{}, srv.Client())
+	requireT.Error(err)
+	_, err = NewGitHub("client-id", "client-secret", "/callback", GitHubRequirements{}, srv.Client())
+	requireT.Error(err)
+
+	g, err := NewGitHub("client-id", "client-secret", "https://faucet.example.com/callback", GitHubRequirements{
+		MinAccountAge:  30 * 24 * time.Hour,
+		MinPublicRepos: 1,
+	}, srv.Client())
+	requireT.NoError(err)
+	g.config.TokenURL = srv.URL + "/token"
+	g.userURL = srv.URL + "/user"
+	g.now = func() time.Time { return now }
+
+	loginURL, err := url.Parse(g.LoginURL("state1"))
+	requireT.NoError(err)
+	requireT.Equal("github.com", loginURL.Host)
+	requireT.Equal("client-id", loginURL.Query().Get("client_id"))
+	requireT.Equal("state1", loginURL.Query().Get("state"))
+	requireT.Equal("https://faucet.example.com/callback", loginURL.Query().Get("redirect_uri"))
+
+	ctx := context.Background()
+	identity, err := g.Authenticate(ctx, "old")
+	requireT.NoError(err)
+	requireT.Equal(app.Identity{Provider: ProviderGitHub, Subject: "42", Name: "octocat"}, identity)
+
+	_, err = g.Authenticate(ctx, "new")
+	requireT.ErrorIs(err, app.ErrIdentityRejected)
+	_, err = g.Authenticate(ctx, "idle")
+	requireT.ErrorIs(err, app.ErrIdentityRejected)
+	_, err = g.Authenticate(ctx, "invalid")
+	requireT.ErrorIs(err, app.ErrIdentityFailed)
+	_, err = g.Authenticate(ctx, "broken")
+	requireT.ErrorIs(err, app.ErrIdentityUnavailable)
+}
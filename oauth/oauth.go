@@ -0,0 +1,129 @@
+// Package oauth authenticates the users of the faucet UI by the OAuth 2.0 authorization code flow of the identity
+// providers, e.g. GitHub or Discord, so each person is funded by the quota of the account instead of the quota
+// of the IP.
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// maxResponseSize bounds the responses read from the providers.
+const maxResponseSize = 1 << 20
+
+// Config is the OAuth client registered at the provider.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	// AuthURL is the endpoint the user is sent to for authorization.
+	AuthURL string
+	// TokenURL is the endpoint exchanging the authorization code for the access token.
+	TokenURL string
+	// CallbackURL is the URL of the faucet the provider sends the user back to, it must match the one registered
+	// at the provider.
+	CallbackURL string
+	Scopes      []string
+}
+
+func (c Config) validate(provider string) error {
+	if c.ClientID == "" || c.ClientSecret == "" {
+		return errors.Errorf("%s client ID and secret are required", provider)
+	}
+	u, err := url.Parse(c.CallbackURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return errors.Errorf("%s callback URL must be absolute http(s) URL, got %q", provider, c.CallbackURL)
+	}
+	return nil
+}
+
+// AuthCodeURL returns the URL the user is sent to for authorization, the state is passed back to the callback.
+func (c Config) AuthCodeURL(state string) string {
+	query := url.Values{
+		"client_id":     {c.ClientID},
+		"redirect_uri":  {c.CallbackURL},
+		"response_type": {"code"},
+		"state":         {state},
+	}
+	if len(c.Scopes) > 0 {
+		query.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	separator := "?"
+	if strings.Contains(c.AuthURL, "?") {
+		separator = "&"
+	}
+	return c.AuthURL + separator + query.Encode()
+}
+
+// Exchange exchanges the authorization code for the access token. It returns app.ErrIdentityFailed if the code
+// is rejected and app.ErrIdentityUnavailable if the provider can't be asked.
+func (c Config) Exchange(ctx context.Context, client *http.Client, code string) (string, error) {
+	form := url.Values{
+		"client_id":     {c.ClientID},
+		"client_secret": {c.ClientSecret},
+		"code":          {code},
+		"grant_type":    {"authorization_code"},
+		"redirect_uri":  {c.CallbackURL},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", errors.Wrapf(app.ErrIdentityUnavailable, "err:%s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return "", errors.Wrapf(app.ErrIdentityUnavailable, "unexpected status %d", resp.StatusCode)
+	}
+	var result struct {
+		AccessToken      string `json:"access_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&result); err != nil {
+		return "", errors.Wrapf(app.ErrIdentityUnavailable, "invalid response: %s", err)
+	}
+	// GitHub reports rejected codes with status 200 and the error in the body
+	if result.Error != "" || result.AccessToken == "" {
+		return "", errors.Wrapf(app.ErrIdentityFailed, "authorization code rejected: %s %s",
+			result.Error, result.ErrorDescription)
+	}
+	return result.AccessToken, nil
+}
+
+// getJSON fetches the resource of the user authorized by the access token and decodes it into v. The status
+// of the response is returned, so the callers may tell the missing resources from the failures.
+func getJSON(ctx context.Context, client *http.Client, resourceURL, accessToken string, v interface{}) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, resourceURL, nil)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, errors.Wrapf(app.ErrIdentityUnavailable, "err:%s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, errors.Wrapf(app.ErrIdentityUnavailable, "unexpected status %d fetching %s",
+			resp.StatusCode, resourceURL)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v); err != nil {
+		return resp.StatusCode, errors.Wrapf(app.ErrIdentityUnavailable, "invalid response: %s", err)
+	}
+	return resp.StatusCode, nil
+}
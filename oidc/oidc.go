@@ -0,0 +1,391 @@
+// Package oidc validates the bearer tokens issued by any OpenID Connect provider, e.g. Keycloak, Okta or Google,
+// so the private faucets of internal networks are served to the authenticated employees and services only.
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Error types produced by oidc package.
+var (
+	// ErrInvalidToken is returned if the bearer token is missing, malformed, expired or not issued for the faucet.
+	ErrInvalidToken = errors.New("invalid bearer token")
+	// ErrUnavailable is returned if the keys of the provider can't be fetched.
+	ErrUnavailable = errors.New("OIDC provider is unavailable")
+)
+
+const (
+	// keysTTL is how long the keys of the provider are cached.
+	keysTTL = time.Hour
+	// minRefreshInterval bounds how often the keys are refetched because the token is signed by an unknown key,
+	// so the tokens carrying random key IDs can't flood the provider.
+	minRefreshInterval = time.Minute
+	// leeway tolerates the clock skew between the faucet and the provider.
+	leeway = time.Minute
+	// maxResponseSize bounds the responses read from the provider.
+	maxResponseSize = 1 << 20
+)
+
+// Config identifies the provider and the tokens accepted.
+type Config struct {
+	// IssuerURL is the issuer of the tokens, the configuration of the provider is discovered
+	// at <issuer>/.well-known/openid-configuration.
+	IssuerURL string
+	// ClientID is the client the faucet is registered as at the provider. If set, tokens issued to other parties,
+	// as told by the azp claim, are refused.
+	ClientID string
+	// Audience must be included in the aud claim of the tokens, ClientID is required there if it is empty.
+	Audience string
+}
+
+// Claims are the claims of the valid token.
+type Claims struct {
+	Subject   string
+	Email     string
+	ExpiresAt time.Time
+}
+
+// Verifier validates the tokens. The configuration and the keys of the provider are fetched on the first token,
+// so the faucet starts even if the provider is down.
+type Verifier struct {
+	cfg          Config
+	audience     string
+	discoveryURL string
+	client       *http.Client
+	now          func() time.Time
+
+	mu        sync.Mutex
+	jwksURL   string
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewVerifier returns the verifier of the tokens of the provider, the provider is asked by the client.
+func NewVerifier(cfg Config, client *http.Client) (*Verifier, error) {
+	u, err := url.Parse(cfg.IssuerURL)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return nil, errors.Errorf("OIDC issuer must be absolute http(s) URL, got %q", cfg.IssuerURL)
+	}
+	audience := cfg.Audience
+	if audience == "" {
+		audience = cfg.ClientID
+	}
+	if audience == "" {
+		return nil, errors.New("OIDC client ID or audience is required")
+	}
+	return &Verifier{
+		cfg:          cfg,
+		audience:     audience,
+		discoveryURL: strings.TrimSuffix(cfg.IssuerURL, "/") + "/.well-known/openid-configuration",
+		client:       client,
+		now:          time.Now,
+	}, nil
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+type claims struct {
+	Issuer          string   `json:"iss"`
+	Subject         string   `json:"sub"`
+	Audience        audience `json:"aud"`
+	AuthorizedParty string   `json:"azp"`
+	Email           string   `json:"email"`
+	ExpiresAt       int64    `json:"exp"`
+	NotBefore       int64    `json:"nbf"`
+}
+
+// audience is either a single string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return errors.WithStack(err)
+	}
+	*a = list
+	return nil
+}
+
+func (a audience) contains(value string) bool {
+	for _, v := range a {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Verify checks the signature and the claims of the token.
+func (v *Verifier) Verify(ctx context.Context, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, errors.Wrap(ErrInvalidToken, "token is not JWT")
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return Claims{}, err
+	}
+	hash, err := algorithmHash(h.Algorithm)
+	if err != nil {
+		return Claims{}, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, errors.Wrap(ErrInvalidToken, "malformed signature")
+	}
+	key, err := v.key(ctx, h.KeyID)
+	if err != nil {
+		return Claims{}, err
+	}
+	if err := verifySignature(key, h.Algorithm, hash, parts[0]+"."+parts[1], signature); err != nil {
+		return Claims{}, err
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return Claims{}, err
+	}
+	now := v.now()
+	switch {
+	case c.Issuer != v.cfg.IssuerURL:
+		return Claims{}, errors.Wrapf(ErrInvalidToken, "token is issued by %q", c.Issuer)
+	case !c.Audience.contains(v.audience):
+		return Claims{}, errors.Wrapf(ErrInvalidToken, "token is not issued for audience %q", v.audience)
+	case v.cfg.ClientID != "" && c.AuthorizedParty != "" && c.AuthorizedParty != v.cfg.ClientID:
+		return Claims{}, errors.Wrapf(ErrInvalidToken, "token is issued to %q", c.AuthorizedParty)
+	case c.ExpiresAt == 0 || !now.Before(time.Unix(c.ExpiresAt, 0).Add(leeway)):
+		return Claims{}, errors.Wrap(ErrInvalidToken, "token is expired")
+	case c.NotBefore != 0 && now.Add(leeway).Before(time.Unix(c.NotBefore, 0)):
+		return Claims{}, errors.Wrap(ErrInvalidToken, "token is not valid yet")
+	case c.Subject == "":
+		return Claims{}, errors.Wrap(ErrInvalidToken, "token has no subject")
+	}
+	return Claims{Subject: c.Subject, Email: c.Email, ExpiresAt: time.Unix(c.ExpiresAt, 0).UTC()}, nil
+}
+
+// key returns the key of the provider having the ID. Keys are refetched if the ID is unknown, so the keys
+// rotated by the provider are picked up at once.
+func (v *Verifier) key(ctx context.Context, id string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+	key, ok := v.keys[id]
+	age := now.Sub(v.fetchedAt)
+	if ok && age < keysTTL {
+		return key, nil
+	}
+	if !ok && v.keys != nil && age < minRefreshInterval {
+		return nil, errors.Wrapf(ErrInvalidToken, "token is signed by unknown key %q", id)
+	}
+	keys, err := v.fetchKeys(ctx)
+	if err != nil {
+		if ok {
+			// the provider is down, the known key is still trusted and the keys are refetched a minute later
+			v.fetchedAt = now.Add(minRefreshInterval - keysTTL)
+			return key, nil
+		}
+		return nil, err
+	}
+	v.keys = keys
+	v.fetchedAt = now
+	if key, ok = keys[id]; !ok {
+		return nil, errors.Wrapf(ErrInvalidToken, "token is signed by unknown key %q", id)
+	}
+	return key, nil
+}
+
+func (v *Verifier) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	if v.jwksURL == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := v.getJSON(ctx, v.discoveryURL, &discovery); err != nil {
+			return nil, err
+		}
+		if discovery.Issuer != v.cfg.IssuerURL {
+			return nil, errors.Wrapf(ErrUnavailable, "provider reports issuer %q instead of %q", discovery.Issuer,
+				v.cfg.IssuerURL)
+		}
+		if discovery.JWKSURI == "" {
+			return nil, errors.Wrap(ErrUnavailable, "provider doesn't publish its keys")
+		}
+		v.jwksURL = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := v.getJSON(ctx, v.jwksURL, &set); err != nil {
+		return nil, err
+	}
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		// keys of unsupported types are skipped, tokens signed by them are refused as signed by unknown keys
+		if key, err := k.publicKey(); err == nil {
+			keys[k.KeyID] = key
+		}
+	}
+	return keys, nil
+}
+
+func (v *Verifier) getJSON(ctx context.Context, resourceURL string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, resourceURL, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(ErrUnavailable, "err:%s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Wrapf(ErrUnavailable, "unexpected status %d fetching %s", resp.StatusCode, resourceURL)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(result); err != nil {
+		return errors.Wrapf(ErrUnavailable, "invalid response of %s: %s", resourceURL, err)
+	}
+	return nil
+}
+
+type jwk struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, errors.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, errors.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
+
+func algorithmHash(algorithm string) (crypto.Hash, error) {
+	switch algorithm {
+	case "RS256", "ES256":
+		return crypto.SHA256, nil
+	case "RS384", "ES384":
+		return crypto.SHA384, nil
+	case "RS512":
+		return crypto.SHA512, nil
+	default:
+		// "none" and HMAC are refused, the faucet doesn't share secrets with the provider
+		return 0, errors.Wrapf(ErrInvalidToken, "unsupported algorithm %q", algorithm)
+	}
+}
+
+func verifySignature(
+	key crypto.PublicKey,
+	algorithm string,
+	hash crypto.Hash,
+	signed string,
+	signature []byte,
+) error {
+	h := hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if strings.HasPrefix(algorithm, "RS") && rsa.VerifyPKCS1v15(key, hash, digest, signature) == nil {
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if strings.HasPrefix(algorithm, "ES") && len(signature) == 2*size {
+			r := new(big.Int).SetBytes(signature[:size])
+			s := new(big.Int).SetBytes(signature[size:])
+			if ecdsa.Verify(key, digest, r, s) {
+				return nil
+			}
+		}
+	}
+	return errors.Wrap(ErrInvalidToken, "invalid signature")
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.Wrap(ErrInvalidToken, "malformed token")
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return errors.Wrap(ErrInvalidToken, "malformed token")
+	}
+	return nil
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(raw) == 0 {
+		return nil, errors.New("malformed key")
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
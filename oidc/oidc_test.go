@@ -0,0 +1,149 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sign(t *testing.T, key crypto.Signer, alg, kid string, claims map[string]interface{}) string {
+	requireT := require.New(t)
+
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	requireT.NoError(err)
+	payload, err := json.Marshal(claims)
+	requireT.NoError(err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := crypto.SHA256.New()
+	digest.Write([]byte(signed))
+
+	var signature []byte
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest.Sum(nil))
+		requireT.NoError(err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest.Sum(nil))
+		requireT.NoError(err)
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func encodeInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+func TestVerifier(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	requireT.NoError(err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	requireT.NoError(err)
+
+	var jwksRequests int32
+	var issuer string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/realms/dev/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]string{"issuer": issuer, "jwks_uri": issuer + "/certs"})
+		case "/realms/dev/certs":
+			atomic.AddInt32(&jwksRequests, 1)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{
+				{"kty": "RSA", "kid": "rsa1", "use": "sig", "n": encodeInt(rsaKey.N), "e": "AQAB"},
+				{"kty": "EC", "kid": "ec1", "crv": "P-256", "x": encodeInt(ecKey.X), "y": encodeInt(ecKey.Y)},
+				{"kty": "RSA", "kid": "enc1", "use": "enc", "n": encodeInt(rsaKey.N), "e": "AQAB"},
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	issuer = srv.URL + "/realms/dev"
+
+	_, err = NewVerifier(Config{IssuerURL: "realms/dev", ClientID: "faucet"}, srv.Client())
+	requireT.Error(err)
+	_, err = NewVerifier(Config{IssuerURL: issuer}, srv.Client())
+	requireT.Error(err)
+
+	v, err := NewVerifier(Config{IssuerURL: issuer, ClientID: "faucet"}, srv.Client())
+	requireT.NoError(err)
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	v.now = func() time.Time { return now }
+
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":   issuer,
+			"sub":   "f81d4fae",
+			"aud":   []string{"faucet", "account"},
+			"azp":   "faucet",
+			"email": "dev@example.com",
+			"exp":   now.Add(time.Hour).Unix(),
+		}
+	}
+
+	claims, err := v.Verify(ctx, sign(t, rsaKey, "RS256", "rsa1", valid()))
+	requireT.NoError(err)
+	requireT.Equal(Claims{Subject: "f81d4fae", Email: "dev@example.com", ExpiresAt: now.Add(time.Hour)}, claims)
+	_, err = v.Verify(ctx, sign(t, ecKey, "ES256", "ec1", valid()))
+	requireT.NoError(err)
+	requireT.EqualValues(1, atomic.LoadInt32(&jwksRequests))
+
+	for name, modify := range map[string]func(c map[string]interface{}){
+		"issuer":   func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" },
+		"audience": func(c map[string]interface{}) { c["aud"] = "account" },
+		"party":    func(c map[string]interface{}) { c["azp"] = "other" },
+		"expired":  func(c map[string]interface{}) { c["exp"] = now.Add(-2 * time.Minute).Unix() },
+		"future":   func(c map[string]interface{}) { c["nbf"] = now.Add(2 * time.Minute).Unix() },
+		"subject":  func(c map[string]interface{}) { delete(c, "sub") },
+	} {
+		c := valid()
+		modify(c)
+		_, err := v.Verify(ctx, sign(t, rsaKey, "RS256", "rsa1", c))
+		requireT.ErrorIs(err, ErrInvalidToken, name)
+	}
+
+	token := sign(t, rsaKey, "RS256", "rsa1", valid())
+	_, err = v.Verify(ctx, token[:len(token)-4]+"AAAA")
+	requireT.ErrorIs(err, ErrInvalidToken)
+	// key of the other type is not accepted for the algorithm
+	_, err = v.Verify(ctx, sign(t, rsaKey, "ES256", "rsa1", valid()))
+	requireT.ErrorIs(err, ErrInvalidToken)
+	_, err = v.Verify(ctx, sign(t, rsaKey, "HS256", "rsa1", valid()))
+	requireT.ErrorIs(err, ErrInvalidToken)
+	_, err = v.Verify(ctx, "not-a-token")
+	requireT.ErrorIs(err, ErrInvalidToken)
+	// encryption keys don't verify the signatures
+	_, err = v.Verify(ctx, sign(t, rsaKey, "RS256", "enc1", valid()))
+	requireT.ErrorIs(err, ErrInvalidToken)
+
+	// unknown keys are refetched at most once a minute
+	_, err = v.Verify(ctx, sign(t, rsaKey, "RS256", "rotated", valid()))
+	requireT.ErrorIs(err, ErrInvalidToken)
+	requireT.EqualValues(1, atomic.LoadInt32(&jwksRequests))
+	now = now.Add(2 * time.Minute)
+	_, err = v.Verify(ctx, sign(t, rsaKey, "RS256", "rotated", valid()))
+	requireT.ErrorIs(err, ErrInvalidToken)
+	requireT.EqualValues(2, atomic.LoadInt32(&jwksRequests))
+
+	unavailable, err := NewVerifier(Config{IssuerURL: srv.URL + "/missing", Audience: "faucet"}, srv.Client())
+	requireT.NoError(err)
+	_, err = unavailable.Verify(ctx, token)
+	requireT.ErrorIs(err, ErrUnavailable)
+}
@@ -0,0 +1,129 @@
+// Package attribution encodes the attribution of the transfers into the memo of the transaction, so the spend
+// may be attributed to tenants, sessions and API keys using on-chain data only.
+//
+// The memo has the format `faucet:1|<entry>|<entry>...` where each entry is `<tenant>,<session>,<api key hash>`,
+// followed by `,<invoice hash>` if the client passed the invoice ID, and describes the message of the same index.
+// If all the messages share the attribution, single entry is used.
+// Characters `%`, `,` and `|` in the fields are percent-encoded and each field is truncated to 64 characters,
+// so the attribution of a single message always fits into the memo.
+package attribution
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MaxMemoLength is the maximum length of the memo accepted by the chain by default.
+const MaxMemoLength = 256
+
+const (
+	memoPrefix     = "faucet:1|"
+	entrySeparator = "|"
+	fieldSeparator = ","
+	apiKeyHashLen  = 8
+	invoiceHashLen = 16
+	maxFieldLength = 64
+)
+
+var escaper = strings.NewReplacer("%", "%25", ",", "%2C", "|", "%7C")
+
+// Attribution identifies the party the transfer is attributed to.
+type Attribution struct {
+	Tenant     string
+	Session    string
+	APIKeyHash string
+	// InvoiceHash is the hash of the invoice ID the client correlates the transfer with, see HashInvoiceID.
+	InvoiceHash string
+}
+
+// HashAPIKey returns the short hash identifying the API key without revealing it.
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])[:apiKeyHashLen]
+}
+
+// HashInvoiceID returns the hash of the invoice ID embedded into the memo, the first 16 hex characters
+// of SHA-256 of the ID, so the clients may compute it themselves to match the transfers.
+func HashInvoiceID(invoiceID string) string {
+	hash := sha256.Sum256([]byte(invoiceID))
+	return hex.EncodeToString(hash[:])[:invoiceHashLen]
+}
+
+// Memo returns the memo attributing the messages, empty if none of the messages is attributed.
+func Memo(attributions []Attribution) string {
+	if len(attributions) == 0 {
+		return ""
+	}
+	shared := true
+	attributed := false
+	for _, a := range attributions {
+		shared = shared && a == attributions[0]
+		attributed = attributed || a != Attribution{}
+	}
+	if !attributed {
+		return ""
+	}
+	if shared {
+		attributions = attributions[:1]
+	}
+
+	entries := make([]string, 0, len(attributions))
+	for _, a := range attributions {
+		fields := []string{
+			encodeField(a.Tenant),
+			encodeField(a.Session),
+			encodeField(a.APIKeyHash),
+		}
+		if a.InvoiceHash != "" {
+			fields = append(fields, encodeField(a.InvoiceHash))
+		}
+		entries = append(entries, strings.Join(fields, fieldSeparator))
+	}
+	return memoPrefix + strings.Join(entries, entrySeparator)
+}
+
+// encodeField escapes the field and truncates it, so the result is at most maxFieldLength long.
+func encodeField(field string) string {
+	var b strings.Builder
+	for _, r := range field {
+		escaped := escaper.Replace(string(r))
+		if b.Len()+len(escaped) > maxFieldLength {
+			break
+		}
+		b.WriteString(escaped)
+	}
+	return b.String()
+}
+
+// ParseMemo returns the attributions of the messages of the transaction with the memo. Single attribution
+// applies to all the messages. Memo not produced by the faucet returns no attributions.
+func ParseMemo(memo string) ([]Attribution, error) {
+	if !strings.HasPrefix(memo, memoPrefix) {
+		return nil, nil
+	}
+
+	var attributions []Attribution
+	for _, entry := range strings.Split(strings.TrimPrefix(memo, memoPrefix), entrySeparator) {
+		fields := strings.Split(entry, fieldSeparator)
+		if len(fields) != 3 && len(fields) != 4 {
+			return nil, errors.Errorf("invalid attribution entry %q", entry)
+		}
+		for i, field := range fields {
+			unescaped, err := url.PathUnescape(field)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid attribution entry %q", entry)
+			}
+			fields[i] = unescaped
+		}
+		a := Attribution{Tenant: fields[0], Session: fields[1], APIKeyHash: fields[2]}
+		if len(fields) == 4 {
+			a.InvoiceHash = fields[3]
+		}
+		attributions = append(attributions, a)
+	}
+	return attributions, nil
+}
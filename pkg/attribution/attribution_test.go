@@ -0,0 +1,62 @@
+package attribution
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemo(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	ci := Attribution{Tenant: "ci", Session: "s|1", APIKeyHash: HashAPIKey("key")}
+	public := Attribution{Tenant: "public"}
+
+	assertT.Equal("", Memo(nil))
+	assertT.Equal("", Memo([]Attribution{{}, {}}))
+	assertT.Equal("faucet:1|ci,s%7C1,"+ci.APIKeyHash, Memo([]Attribution{ci, ci}))
+	assertT.Len(ci.APIKeyHash, 8)
+
+	memo := Memo([]Attribution{ci, public, {}})
+	assertT.Equal("faucet:1|ci,s%7C1,"+ci.APIKeyHash+"|public,,|,,", memo)
+
+	parsed, err := ParseMemo(memo)
+	requireT.NoError(err)
+	assertT.Equal([]Attribution{ci, public, {}}, parsed)
+
+	long := Memo([]Attribution{{Tenant: strings.Repeat("|", 100), Session: strings.Repeat("s", 100)}})
+	assertT.LessOrEqual(len(long), MaxMemoLength)
+	parsed, err = ParseMemo(long)
+	requireT.NoError(err)
+	assertT.Equal(strings.Repeat("|", 21), parsed[0].Tenant)
+	assertT.Equal(strings.Repeat("s", 64), parsed[0].Session)
+
+	parsed, err = ParseMemo("thanks")
+	requireT.NoError(err)
+	assertT.Nil(parsed)
+
+	_, err = ParseMemo("faucet:1|ci")
+	requireT.Error(err)
+}
+
+func TestMemoInvoice(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	// hash is the prefix of `echo -n INV-1001 | sha256sum`
+	invoice := Attribution{Tenant: "billing", InvoiceHash: HashInvoiceID("INV-1001")}
+	assertT.Equal("2efe089ec8f71e7b", invoice.InvoiceHash)
+	assertT.NotEqual(HashInvoiceID("INV-1002"), invoice.InvoiceHash)
+
+	memo := Memo([]Attribution{invoice, {Tenant: "public"}})
+	assertT.Equal("faucet:1|billing,,,"+invoice.InvoiceHash+"|public,,", memo)
+	parsed, err := ParseMemo(memo)
+	requireT.NoError(err)
+	assertT.Equal([]Attribution{invoice, {Tenant: "public"}}, parsed)
+
+	_, err = ParseMemo("faucet:1|a,b,c,d,e")
+	requireT.Error(err)
+}
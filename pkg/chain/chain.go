@@ -0,0 +1,149 @@
+// Package chain re-exports the cosmos-sdk and coreum types and functions the faucet uses outside the client/coreum
+// adapter and pkg/config, so an SDK bump is absorbed by these packages instead of rippling through the whole
+// codebase. The faucet is built against one release line, cosmos-sdk v0.45 and coreum v1, building against several
+// lines at once isn't supported.
+package chain
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/coreum/pkg/config"
+	"github.com/CoreumFoundation/coreum/pkg/config/constant"
+
+	"github.com/CoreumFoundation/faucet/pkg/secret"
+)
+
+// Re-export types from SDK libraries, so the users will not need to import them.
+type (
+	// Coin is the amount of a single denom.
+	Coin = sdk.Coin
+	// Coins is the sorted set of amounts of distinct denoms.
+	Coins = sdk.Coins
+	// DecCoins are the coins with decimal amounts, e.g. the gas prices.
+	DecCoins = sdk.DecCoins
+	// Int is the arbitrary-precision integer the amounts are expressed in.
+	Int = sdk.Int
+	// AccAddress is the raw account address, rendered in bech32 with the prefix of the network.
+	AccAddress = sdk.AccAddress
+	// DenomMetadata describes the units of the denom registered on chain.
+	DenomMetadata = banktypes.Metadata
+	// DenomUnit is the unit of the denom with its exponent relative to the base unit.
+	DenomUnit = banktypes.DenomUnit
+	// Network is the configuration of the Coreum network: its chain ID, denom and address prefix.
+	Network = config.Network
+	// ChainID identifies the Coreum network.
+	ChainID = constant.ChainID
+)
+
+// Re-export chain IDs.
+const (
+	ChainIDMain = constant.ChainIDMain
+	ChainIDDev  = constant.ChainIDDev
+)
+
+// Re-export SDK functions.
+var (
+	NewInt               = sdk.NewInt
+	NewIntFromString     = sdk.NewIntFromString
+	NewCoin              = sdk.NewCoin
+	NewCoins             = sdk.NewCoins
+	ParseCoinNormalized  = sdk.ParseCoinNormalized
+	ParseCoinsNormalized = sdk.ParseCoinsNormalized
+	ParseDecCoins        = sdk.ParseDecCoins
+	NetworkByChainID     = config.NetworkByChainID
+	VerifyAddressFormat  = sdk.VerifyAddressFormat
+)
+
+// DecodeBech32 decodes bech32 address into human-readable part and address bytes.
+func DecodeBech32(address string) (string, AccAddress, error) {
+	hrp, bz, err := bech32.DecodeAndConvert(address)
+	return hrp, bz, errors.WithStack(err)
+}
+
+// GenerateMnemonic generates new mnemonic and returns it together with the address derived from it.
+// The mnemonic is wrapped, so it is redacted when logged.
+func GenerateMnemonic() (AccAddress, secret.String, error) {
+	kr := keyring.NewInMemory()
+	info, mnemonic, err := kr.NewMnemonic("", keyring.English, sdk.GetConfig().GetFullBIP44Path(), "", hd.Secp256k1)
+	if err != nil {
+		return nil, secret.String{}, errors.WithStack(err)
+	}
+	return info.GetAddress(), secret.New(mnemonic), nil
+}
+
+// adr36SignDoc is the amino JSON of the transaction signed by ADR-36 wallets, e.g. Keplr signArbitrary. Fields are
+// declared in the alphabetical order, so the JSON is sorted the way amino sorts it.
+type adr36SignDoc struct {
+	AccountNumber string         `json:"account_number"`
+	ChainID       string         `json:"chain_id"`
+	Fee           adr36Fee       `json:"fee"`
+	Memo          string         `json:"memo"`
+	Msgs          []adr36Message `json:"msgs"`
+	Sequence      string         `json:"sequence"`
+}
+
+type adr36Fee struct {
+	Amount []struct{} `json:"amount"`
+	Gas    string     `json:"gas"`
+}
+
+type adr36Message struct {
+	Type  string         `json:"type"`
+	Value adr36SignValue `json:"value"`
+}
+
+type adr36SignValue struct {
+	Data   string `json:"data"`
+	Signer string `json:"signer"`
+}
+
+// ADR36SignBytes returns the bytes signed by the signer signing the data off-chain as specified by ADR-36.
+func ADR36SignBytes(signer string, data []byte) []byte {
+	doc, err := json.Marshal(adr36SignDoc{
+		AccountNumber: "0",
+		Fee:           adr36Fee{Amount: []struct{}{}, Gas: "0"},
+		Msgs: []adr36Message{{
+			Type: "sign/MsgSignData",
+			Value: adr36SignValue{
+				Data:   base64.StdEncoding.EncodeToString(data),
+				Signer: signer,
+			},
+		}},
+		Sequence: "0",
+	})
+	if err != nil {
+		// the document consists of strings only
+		panic(err)
+	}
+	return doc
+}
+
+// VerifyADR36Signature verifies the signature of the data signed off-chain by the signer as specified by ADR-36.
+// The public key is the compressed secp256k1 key, which must derive the address of the signer.
+func VerifyADR36Signature(signer string, data, pubKey, signature []byte) error {
+	_, address, err := DecodeBech32(signer)
+	if err != nil {
+		return err
+	}
+	if len(pubKey) != secp256k1.PubKeySize {
+		return errors.Errorf("public key must be %d bytes long, got %d", secp256k1.PubKeySize, len(pubKey))
+	}
+	key := &secp256k1.PubKey{Key: pubKey}
+	if !bytes.Equal(key.Address(), address) {
+		return errors.New("public key doesn't belong to the signer")
+	}
+	if !key.VerifySignature(ADR36SignBytes(signer, data), signature) {
+		return errors.New("signature is invalid")
+	}
+	return nil
+}
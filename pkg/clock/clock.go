@@ -0,0 +1,74 @@
+// Package clock abstracts the source of the current time, so the time-dependent logic might be tested
+// deterministically and time might be fast-forwarded on test networks.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the clock returning the time of the operating system.
+type System struct{}
+
+// Now returns the current time.
+func (System) Now() time.Time {
+	return time.Now()
+}
+
+// NewOffset returns the clock running with the system one, shifted by the offset which might be only increased.
+func NewOffset() *Offset {
+	return &Offset{}
+}
+
+// Offset is the system clock which might be fast-forwarded.
+type Offset struct {
+	mu     sync.RWMutex
+	offset time.Duration
+}
+
+// Now returns the current time.
+func (c *Offset) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return time.Now().Add(c.offset)
+}
+
+// Advance moves the clock forward by the duration and returns the new current time.
+func (c *Offset) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d > 0 {
+		c.offset += d
+	}
+	return time.Now().Add(c.offset)
+}
+
+// NewManual returns the clock which stands still at the given time unless it is moved explicitly.
+func NewManual(now time.Time) *Manual {
+	return &Manual{now: now}
+}
+
+// Manual is the clock controlled explicitly, intended for tests.
+type Manual struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+// Now returns the current time.
+func (c *Manual) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.now
+}
+
+// Advance moves the clock by the duration.
+func (c *Manual) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"net/url"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/cosmos/cosmos-sdk/types/module"
@@ -32,12 +34,7 @@ func WithEnv(f *pflag.FlagSet, prefix string) error {
 			continue
 		}
 
-		name := flag.Name
-		if prefix != "" {
-			name = prefix + "_" + name
-		}
-		name = strings.ReplaceAll(strings.ToUpper(name), "-", "_")
-		envValue := os.Getenv(name)
+		envValue := os.Getenv(envName(prefix, flag.Name))
 		if envValue != "" {
 			flag.DefValue = envValue
 			if err := flag.Value.Set(envValue); err != nil {
@@ -48,3 +45,78 @@ func WithEnv(f *pflag.FlagSet, prefix string) error {
 
 	return nil
 }
+
+// Sources of the effective flag values.
+const (
+	SourceFlag    = "flag"
+	SourceEnv     = "env"
+	SourceDefault = "default"
+)
+
+// Redacted replaces the values of the secrets.
+const Redacted = "<redacted>"
+
+// Entry is the effective value of the flag.
+type Entry struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// Effective returns the effective values of the flags ordered by name. Values of the secret flags are redacted,
+// and so are the passwords embedded in URLs of other flags, e.g. proxy credentials.
+// This function should be called after the flags are parsed and WithEnv is applied.
+func Effective(f *pflag.FlagSet, prefix string, secrets ...string) []Entry {
+	secret := map[string]bool{}
+	for _, name := range secrets {
+		secret[name] = true
+	}
+
+	var entries []Entry
+	f.VisitAll(func(flag *pflag.Flag) {
+		entry := Entry{Name: flag.Name, Value: flag.Value.String(), Source: SourceDefault}
+		switch {
+		case flag.Changed:
+			entry.Source = SourceFlag
+		case os.Getenv(envName(prefix, flag.Name)) != "":
+			entry.Source = SourceEnv
+		}
+		switch {
+		case secret[flag.Name]:
+			if entry.Value != "" && entry.Value != "[]" {
+				entry.Value = Redacted
+			}
+		default:
+			entry.Value = redactURLPassword(entry.Value)
+		}
+		entries = append(entries, entry)
+	})
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+func redactURLPassword(value string) string {
+	if !strings.Contains(value, "@") {
+		return value
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.User == nil {
+		return value
+	}
+	if _, ok := u.User.Password(); !ok {
+		return value
+	}
+	u.User = url.UserPassword(u.User.Username(), Redacted)
+	// the placeholder is escaped by the URL encoding
+	return strings.Replace(u.String(), url.QueryEscape(Redacted), Redacted, 1)
+}
+
+func envName(prefix, flagName string) string {
+	name := flagName
+	if prefix != "" {
+		name = prefix + "_" + name
+	}
+	return strings.ReplaceAll(strings.ToUpper(name), "-", "_")
+}
@@ -0,0 +1,69 @@
+package fsperm
+
+import (
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Mode defines how violations of the secure deployment requirements are handled.
+type Mode string
+
+// Supported modes.
+const (
+	ModeOff  Mode = "off"
+	ModeWarn Mode = "warn"
+	ModeFail Mode = "fail"
+)
+
+// ParseMode parses the mode.
+func ParseMode(mode string) (Mode, error) {
+	switch m := Mode(mode); m {
+	case ModeOff, ModeWarn, ModeFail:
+		return m, nil
+	default:
+		return "", errors.Errorf("invalid mode %q, supported modes: off, warn, fail", mode)
+	}
+}
+
+// NewChecker returns new instance of Checker.
+func NewChecker(mode Mode, log *zap.Logger) Checker {
+	return Checker{
+		mode: mode,
+		log:  log,
+	}
+}
+
+// Checker verifies that the process runs in a secure way: files storing secrets and state are not accessible
+// by other users and the process doesn't run as root.
+type Checker struct {
+	mode Mode
+	log  *zap.Logger
+}
+
+// CheckPrivate verifies that the file is not accessible by group and other users.
+// Missing file is not a violation, because it is created later with private permissions.
+func (c Checker) CheckPrivate(path string) error {
+	if c.mode == ModeOff {
+		return nil
+	}
+	return c.report(checkPrivate(path), zap.String("path", path))
+}
+
+// CheckNotRoot verifies that the process doesn't run as root.
+func (c Checker) CheckNotRoot() error {
+	if c.mode == ModeOff || !isRoot() {
+		return nil
+	}
+	return c.report(errors.New("process runs as root"))
+}
+
+func (c Checker) report(err error, fields ...zap.Field) error {
+	if err == nil {
+		return nil
+	}
+	if c.mode == ModeFail {
+		return err
+	}
+	c.log.Warn("Insecure deployment detected", append(fields, zap.Error(err))...)
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package fsperm
+
+// SetPrivateUmask is a no-op on systems not supporting umask.
+func SetPrivateUmask() {}
+
+// checkPrivate is a no-op because unix permission bits are not meaningful on this system.
+func checkPrivate(string) error {
+	return nil
+}
+
+func isRoot() bool {
+	return false
+}
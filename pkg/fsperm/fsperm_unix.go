@@ -0,0 +1,34 @@
+//go:build unix
+
+package fsperm
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// SetPrivateUmask sets the umask of the process so files and directories it creates are accessible only by the owner.
+func SetPrivateUmask() {
+	syscall.Umask(0o077)
+}
+
+func checkPrivate(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		return errors.Errorf("file %s is accessible by other users (permissions %#o), expected at most %#o",
+			path, perm, perm&0o700)
+	}
+	return nil
+}
+
+func isRoot() bool {
+	return os.Geteuid() == 0
+}
@@ -0,0 +1,30 @@
+//go:build unix
+
+package fsperm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestCheckPrivate(t *testing.T) {
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "private")
+	publicPath := filepath.Join(dir, "public")
+	require.NoError(t, os.WriteFile(privatePath, nil, 0o600))
+	require.NoError(t, os.WriteFile(publicPath, nil, 0o600))
+	require.NoError(t, os.Chmod(publicPath, 0o644))
+
+	failChecker := NewChecker(ModeFail, zaptest.NewLogger(t))
+	assert.NoError(t, failChecker.CheckPrivate(privatePath))
+	assert.NoError(t, failChecker.CheckPrivate(filepath.Join(dir, "missing")))
+	assert.Error(t, failChecker.CheckPrivate(publicPath))
+
+	warnChecker := NewChecker(ModeWarn, zaptest.NewLogger(t))
+	assert.NoError(t, warnChecker.CheckPrivate(publicPath))
+}
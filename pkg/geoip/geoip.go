@@ -0,0 +1,91 @@
+// Package geoip restricts access by the country the IP is located in, according to the MaxMind database.
+package geoip
+
+import (
+	"net"
+	"strings"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/pkg/errors"
+)
+
+// Lookup returns the ISO 3166-1 alpha-2 code of the country the IP is located in, empty if it is unknown.
+type Lookup interface {
+	Country(ip net.IP) (string, error)
+}
+
+// DB looks the countries up in the MaxMind database, e.g. GeoLite2-Country or GeoIP2-City.
+type DB struct {
+	reader *maxminddb.Reader
+}
+
+// Open opens the MaxMind database at the path.
+func Open(path string) (*DB, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening GeoIP database %s failed", path)
+	}
+	return &DB{reader: reader}, nil
+}
+
+// Country returns the ISO code of the country the IP is located in, empty if it is not in the database.
+func (db *DB) Country(ip net.IP) (string, error) {
+	var record struct {
+		Country struct {
+			ISOCode string `maxminddb:"iso_code"`
+		} `maxminddb:"country"`
+	}
+	if err := db.reader.Lookup(ip, &record); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return record.Country.ISOCode, nil
+}
+
+// Close closes the database.
+func (db *DB) Close() error {
+	return errors.WithStack(db.reader.Close())
+}
+
+// Restriction refuses the IPs located in the blocked countries and, if the allowed countries are set, the IPs
+// located elsewhere, including the ones of unknown country.
+type Restriction struct {
+	lookup  Lookup
+	allowed map[string]bool
+	blocked map[string]bool
+}
+
+// NewRestriction returns the restriction of the countries given by their ISO codes, case-insensitive.
+func NewRestriction(lookup Lookup, allowed, blocked []string) (*Restriction, error) {
+	r := &Restriction{lookup: lookup}
+	var err error
+	if r.allowed, err = countrySet(allowed); err != nil {
+		return nil, err
+	}
+	if r.blocked, err = countrySet(blocked); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Allowed tells if the requests of the IP are accepted and returns the country of the IP.
+func (r *Restriction) Allowed(ip net.IP) (bool, string, error) {
+	country, err := r.lookup.Country(ip)
+	if err != nil {
+		return false, "", err
+	}
+	if r.blocked[country] {
+		return false, country, nil
+	}
+	return len(r.allowed) == 0 || r.allowed[country], country, nil
+}
+
+func countrySet(codes []string) (map[string]bool, error) {
+	set := map[string]bool{}
+	for _, code := range codes {
+		if len(code) != 2 {
+			return nil, errors.Errorf("invalid country code %q, ISO 3166-1 alpha-2 code is expected", code)
+		}
+		set[strings.ToUpper(code)] = true
+	}
+	return set, nil
+}
@@ -0,0 +1,51 @@
+package geoip
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type countries map[string]string
+
+func (c countries) Country(ip net.IP) (string, error) {
+	return c[ip.String()], nil
+}
+
+func TestRestriction(t *testing.T) {
+	requireT := require.New(t)
+
+	lookup := countries{"203.0.113.1": "DE", "203.0.113.2": "PL", "203.0.113.3": "KP"}
+
+	r, err := NewRestriction(lookup, nil, []string{"kp"})
+	requireT.NoError(err)
+	allowed, country, err := r.Allowed(net.ParseIP("203.0.113.3"))
+	requireT.NoError(err)
+	requireT.False(allowed)
+	requireT.Equal("KP", country)
+	for _, ip := range []string{"203.0.113.1", "198.51.100.1"} {
+		allowed, _, err = r.Allowed(net.ParseIP(ip))
+		requireT.NoError(err)
+		requireT.True(allowed)
+	}
+
+	// allowed countries exclude the unknown ones too, blocked ones win
+	r, err = NewRestriction(lookup, []string{"DE", "KP"}, []string{"KP"})
+	requireT.NoError(err)
+	expected := map[string]bool{"203.0.113.1": true, "203.0.113.2": false, "203.0.113.3": false, "198.51.100.1": false}
+	for ip, exp := range expected {
+		allowed, _, err = r.Allowed(net.ParseIP(ip))
+		requireT.NoError(err)
+		requireT.Equal(exp, allowed, ip)
+	}
+
+	_, err = NewRestriction(lookup, []string{"DEU"}, nil)
+	requireT.Error(err)
+}
+
+func TestOpenMissingDatabase(t *testing.T) {
+	_, err := Open(filepath.Join(t.TempDir(), "GeoLite2-Country.mmdb"))
+	require.Error(t, err)
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// DecodeError is returned by the strict binder if the request body doesn't match the expected schema.
+type DecodeError struct {
+	// Path is the dot-separated path of the offending field, empty if the error is not related to any field.
+	Path   string
+	Reason string
+}
+
+func (e DecodeError) Error() string {
+	if e.Path == "" {
+		return "invalid request body: " + e.Reason
+	}
+	return fmt.Sprintf("invalid request body: field %q: %s", e.Path, e.Reason)
+}
+
+// NewStrictBinder returns binder rejecting JSON bodies containing unknown fields, values of unexpected types
+// and trailing data. Other content types are bound the same way as by the default echo binder.
+func NewStrictBinder() echo.Binder {
+	return strictBinder{}
+}
+
+type strictBinder struct {
+	echo.DefaultBinder
+}
+
+func (b strictBinder) Bind(i interface{}, c Context) error {
+	req := c.Request()
+	if req.ContentLength == 0 || !strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		return b.DefaultBinder.Bind(i, c)
+	}
+	if err := b.BindPathParams(c, i); err != nil {
+		return err
+	}
+	return decodeStrictJSON(req.Body, i)
+}
+
+func decodeStrictJSON(body io.Reader, i interface{}) error {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(i); err != nil {
+		return toDecodeError(err)
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errors.WithStack(DecodeError{Reason: "unexpected data after JSON object"})
+	}
+	return nil
+}
+
+func toDecodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &typeErr):
+		path := typeErr.Field
+		if path == "" {
+			path = "."
+		}
+		return errors.WithStack(DecodeError{
+			Path:   path,
+			Reason: fmt.Sprintf("expected %s, got %s", typeErr.Type, typeErr.Value),
+		})
+	case errors.As(err, &syntaxErr):
+		return errors.WithStack(DecodeError{Reason: fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset)})
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return errors.WithStack(DecodeError{Reason: "unexpected end of JSON"})
+	}
+
+	// encoding/json doesn't provide dedicated error type for unknown fields.
+	const unknownFieldPrefix = `json: unknown field "`
+	if msg := err.Error(); strings.HasPrefix(msg, unknownFieldPrefix) {
+		return errors.WithStack(DecodeError{
+			Path:   strings.TrimSuffix(strings.TrimPrefix(msg, unknownFieldPrefix), `"`),
+			Reason: "unknown field",
+		})
+	}
+	return errors.WithStack(DecodeError{Reason: err.Error()})
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type strictBinderRequest struct {
+	Address string `json:"address"`
+	Nested  struct {
+		Count int `json:"count"`
+	} `json:"nested"`
+}
+
+func TestStrictBinder(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		path   string
+		reason string
+	}{
+		{name: "valid", body: `{"address":"devcore1","nested":{"count":1}}`},
+		{name: "unknown field", body: `{"adress":"devcore1"}`, path: "adress", reason: "unknown field"},
+		{name: "type mismatch", body: `{"address":1}`, path: "address", reason: "expected string, got number"},
+		{name: "nested type mismatch", body: `{"nested":{"count":"1"}}`, path: "nested.count", reason: "expected int, got string"},
+		{name: "trailing data", body: `{"address":"devcore1"}{}`, reason: "unexpected data after JSON object"},
+		{name: "malformed", body: `{"address":`, reason: "unexpected end of JSON"},
+	}
+
+	binder := NewStrictBinder()
+	e := echo.New()
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			var rq strictBinderRequest
+			err := binder.Bind(&rq, e.NewContext(req, httptest.NewRecorder()))
+			if tt.reason == "" {
+				require.NoError(t, err)
+				assert.Equal(t, "devcore1", rq.Address)
+				return
+			}
+
+			var decodeErr DecodeError
+			require.True(t, errors.As(err, &decodeErr), "unexpected error: %v", err)
+			assert.Equal(t, tt.path, decodeErr.Path)
+			assert.Equal(t, tt.reason, decodeErr.Reason)
+		})
+	}
+}
+
+func TestStrictBinderForm(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("address=devcore1&unknown=1"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+
+	var rq struct {
+		Address string `form:"address"`
+	}
+	require.NoError(t, NewStrictBinder().Bind(&rq, echo.New().NewContext(req, httptest.NewRecorder())))
+	assert.Equal(t, "devcore1", rq.Address)
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CacheMiddleware sets ETag and Cache-Control headers on successful GET responses and responds with
+// 304 Not Modified if the client already has the current version of the resource.
+func CacheMiddleware(maxAge time.Duration) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			if c.Request().Method != http.MethodGet {
+				return next(c)
+			}
+
+			res := c.Response()
+			original := res.Writer
+			bw := &bufferWriter{ResponseWriter: original, status: http.StatusOK}
+			res.Writer = bw
+			err := next(c)
+			res.Writer = original
+			if err != nil || bw.streamed {
+				return err
+			}
+
+			if bw.status != http.StatusOK {
+				original.WriteHeader(bw.status)
+				_, err := original.Write(bw.body.Bytes())
+				return err
+			}
+
+			hash := sha256.Sum256(bw.body.Bytes())
+			etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+			res.Header().Set(echo.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+			res.Header().Set(HeaderETag, etag)
+
+			if etagMatches(c.Request().Header.Get(HeaderIfNoneMatch), etag) {
+				res.Header().Del(echo.HeaderContentType)
+				res.Status = http.StatusNotModified
+				original.WriteHeader(http.StatusNotModified)
+				return nil
+			}
+			original.WriteHeader(http.StatusOK)
+			_, err = original.Write(bw.body.Bytes())
+			return err
+		}
+	}
+}
+
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferWriter keeps the response in memory, so the ETag may be computed before the response is sent.
+// Once the handler flushes the response, e.g. to stream it, the buffered part is sent and the rest is passed
+// through untouched.
+type bufferWriter struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	streamed bool
+}
+
+func (w *bufferWriter) WriteHeader(code int) {
+	if w.streamed {
+		return
+	}
+	w.status = code
+}
+
+func (w *bufferWriter) Write(b []byte) (int, error) {
+	if w.streamed {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.body.Write(b)
+}
+
+func (w *bufferWriter) Flush() {
+	if !w.streamed {
+		w.streamed = true
+		w.ResponseWriter.WriteHeader(w.status)
+		if _, err := w.ResponseWriter.Write(w.body.Bytes()); err != nil {
+			return
+		}
+		w.body.Reset()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
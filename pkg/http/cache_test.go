@@ -0,0 +1,116 @@
+package http
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCacheTestServer() *echo.Echo {
+	e := echo.New()
+	e.Use(CompressMiddleware())
+	e.GET("/cached", func(c Context) error {
+		return c.String(http.StatusOK, "cached body")
+	}, CacheMiddleware(time.Minute))
+	return e
+}
+
+func TestCacheMiddleware(t *testing.T) {
+	assertT := assert.New(t)
+	e := newCacheTestServer()
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cached", nil))
+	assertT.Equal(http.StatusOK, rec.Code)
+	assertT.Equal("cached body", rec.Body.String())
+	assertT.Equal("public, max-age=60", rec.Header().Get(echo.HeaderCacheControl))
+	etag := rec.Header().Get(HeaderETag)
+	assertT.NotEmpty(etag)
+
+	req := httptest.NewRequest(http.MethodGet, "/cached", nil)
+	req.Header.Set(HeaderIfNoneMatch, etag)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assertT.Equal(http.StatusNotModified, rec.Code)
+	assertT.Empty(rec.Body.Bytes())
+	assertT.Empty(rec.Header().Get(echo.HeaderContentEncoding))
+}
+
+func TestCompressMiddleware(t *testing.T) {
+	testCases := []struct {
+		acceptEncoding string
+		encoding       string
+		decode         func(io.Reader) (io.Reader, error)
+	}{
+		{
+			acceptEncoding: "gzip, deflate, br",
+			encoding:       "br",
+			decode: func(r io.Reader) (io.Reader, error) {
+				return brotli.NewReader(r), nil
+			},
+		},
+		{
+			acceptEncoding: "gzip, br;q=0",
+			encoding:       "gzip",
+			decode: func(r io.Reader) (io.Reader, error) {
+				return gzip.NewReader(r)
+			},
+		},
+		{
+			acceptEncoding: "br;q=0.000, gzip",
+			encoding:       "gzip",
+			decode: func(r io.Reader) (io.Reader, error) {
+				return gzip.NewReader(r)
+			},
+		},
+		{
+			acceptEncoding: "br;q=0., gzip;q=0.5",
+			encoding:       "gzip",
+			decode: func(r io.Reader) (io.Reader, error) {
+				return gzip.NewReader(r)
+			},
+		},
+		{
+			acceptEncoding: "gzip;q=0.0",
+			encoding:       "",
+			decode: func(r io.Reader) (io.Reader, error) {
+				return r, nil
+			},
+		},
+		{
+			acceptEncoding: "",
+			encoding:       "",
+			decode: func(r io.Reader) (io.Reader, error) {
+				return r, nil
+			},
+		},
+	}
+
+	e := newCacheTestServer()
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.acceptEncoding, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/cached", nil)
+			req.Header.Set(echo.HeaderAcceptEncoding, tc.acceptEncoding)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tc.encoding, rec.Header().Get(echo.HeaderContentEncoding))
+			reader, err := tc.decode(rec.Body)
+			require.NoError(t, err)
+			body, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, "cached body", string(body))
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// Supported content encodings.
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+// CompressMiddleware compresses responses with brotli or gzip, whichever is preferred by the client.
+func CompressMiddleware() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			res := c.Response()
+			res.Header().Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+
+			encoding := negotiateEncoding(c.Request().Header.Get(echo.HeaderAcceptEncoding))
+			if encoding == "" {
+				return next(c)
+			}
+
+			cw := &compressWriter{ResponseWriter: res.Writer, encoding: encoding}
+			res.Writer = cw
+			defer func() {
+				_ = cw.Close()
+				res.Writer = cw.ResponseWriter
+			}()
+			return next(c)
+		}
+	}
+}
+
+// negotiateEncoding returns the supported encoding accepted by the client, preferring brotli.
+func negotiateEncoding(acceptEncoding string) string {
+	var gzipAccepted bool
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		if !encodingAccepted(fields[1:]) {
+			continue
+		}
+		switch strings.TrimSpace(fields[0]) {
+		case encodingBrotli:
+			return encodingBrotli
+		case encodingGzip:
+			gzipAccepted = true
+		}
+	}
+	if gzipAccepted {
+		return encodingGzip
+	}
+	return ""
+}
+
+// encodingAccepted tells if the parameters of the encoding don't refuse it by zero quality, e.g. q=0 or q=0.000.
+// Malformed quality refuses the encoding too.
+func encodingAccepted(params []string) bool {
+	for _, param := range params {
+		name, value, ok := strings.Cut(strings.ReplaceAll(param, " ", ""), "=")
+		if !ok || !strings.EqualFold(name, "q") {
+			continue
+		}
+		q, err := strconv.ParseFloat(value, 64)
+		return err == nil && q > 0
+	}
+	return true
+}
+
+// compressWriter starts compressing when the handler writes the body, so responses without body
+// (e.g. 304 Not Modified) are passed through untouched.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	encoder     io.WriteCloser
+	wroteHeader bool
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if code != http.StatusNoContent && code != http.StatusNotModified {
+			w.Header().Set(echo.HeaderContentEncoding, w.encoding)
+			w.Header().Del(echo.HeaderContentLength)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.Header().Get(echo.HeaderContentEncoding) != w.encoding {
+		return w.ResponseWriter.Write(b)
+	}
+	if w.encoder == nil {
+		switch w.encoding {
+		case encodingBrotli:
+			w.encoder = brotli.NewWriter(w.ResponseWriter)
+		default:
+			w.encoder = gzip.NewWriter(w.ResponseWriter)
+		}
+	}
+	return w.encoder.Write(b)
+}
+
+func (w *compressWriter) Flush() {
+	if flusher, ok := w.encoder.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer doesn't support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+func (w *compressWriter) Close() error {
+	if w.encoder == nil {
+		return nil
+	}
+	return errors.WithStack(w.encoder.Close())
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Query parameters shaping the response.
+const (
+	QueryParamFields  = "fields"
+	QueryParamMinimal = "minimal"
+)
+
+// FieldsMiddleware lets bandwidth-sensitive clients shape successful JSON object responses. `fields` query parameter
+// keeps only the listed comma-separated top-level fields, `minimal=true` keeps only the essential ones.
+// Other responses are passed through untouched.
+func FieldsMiddleware(essential ...string) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			keep, err := keptFields(c, essential)
+			if err != nil {
+				return err
+			}
+			if keep == nil {
+				return next(c)
+			}
+
+			res := c.Response()
+			original := res.Writer
+			bw := &bufferWriter{ResponseWriter: original, status: http.StatusOK}
+			res.Writer = bw
+			err = next(c)
+			res.Writer = original
+			if err != nil || bw.streamed {
+				return err
+			}
+
+			body := bw.body.Bytes()
+			if bw.status >= 200 && bw.status < 300 &&
+				strings.HasPrefix(res.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+				body = filterFields(body, keep)
+				res.Header().Del(echo.HeaderContentLength)
+			}
+			original.WriteHeader(bw.status)
+			_, err = original.Write(body)
+			return err
+		}
+	}
+}
+
+// keptFields returns the fields requested by the client, nil if the response shouldn't be filtered.
+func keptFields(c Context, essential []string) (map[string]bool, error) {
+	var fields []string
+	if f := c.QueryParam(QueryParamFields); f != "" {
+		fields = strings.Split(f, ",")
+	} else if m := c.QueryParam(QueryParamMinimal); m != "" {
+		minimal, err := strconv.ParseBool(m)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid minimal query parameter: "+m)
+		}
+		if !minimal {
+			return nil, nil
+		}
+		fields = essential
+	} else {
+		return nil, nil
+	}
+
+	keep := map[string]bool{}
+	for _, field := range fields {
+		keep[strings.TrimSpace(field)] = true
+	}
+	return keep, nil
+}
+
+// filterFields removes top-level fields not being kept from JSON object, other bodies are returned unchanged.
+func filterFields(body []byte, keep map[string]bool) []byte {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil {
+		return body
+	}
+	for field := range object {
+		if !keep[field] {
+			delete(object, field)
+		}
+	}
+	filtered, err := json.Marshal(object)
+	if err != nil {
+		return body
+	}
+	return append(filtered, '\n')
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldsMiddleware(t *testing.T) {
+	e := echo.New()
+	e.GET("/fund", func(c Context) error {
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"txHash":   "hash",
+			"explorer": "https://explorer/hash",
+			"gas":      map[string]int{"used": 1},
+		})
+	}, FieldsMiddleware("txHash"))
+
+	testCases := []struct {
+		query string
+		code  int
+		body  string
+	}{
+		{query: "", code: http.StatusOK, body: `{"explorer":"https://explorer/hash","gas":{"used":1},"txHash":"hash"}`},
+		{query: "?fields=txHash,gas", code: http.StatusOK, body: `{"gas":{"used":1},"txHash":"hash"}`},
+		{query: "?fields=unknown", code: http.StatusOK, body: `{}`},
+		{query: "?minimal=true", code: http.StatusOK, body: `{"txHash":"hash"}`},
+		{query: "?minimal=false", code: http.StatusOK, body: `{"explorer":"https://explorer/hash","gas":{"used":1},"txHash":"hash"}`},
+		{query: "?minimal=maybe", code: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.query, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fund"+tc.query, nil))
+			assert.Equal(t, tc.code, rec.Code)
+			if tc.body != "" {
+				assert.JSONEq(t, tc.body, rec.Body.String())
+			}
+		})
+	}
+}
@@ -27,13 +27,13 @@ type (
 	Context = echo.Context
 )
 
-// New returns a server instance.
-func New(log *zap.Logger, middlewares ...MiddlewareFunc) Server {
+// New returns a server instance. Forwarded headers are honored only for requests coming from the trusted proxies.
+func New(log *zap.Logger, proxies TrustedProxies, middlewares ...MiddlewareFunc) Server {
 	e := echo.New()
 	e.Logger.SetLevel(99)
 	e.HideBanner = true
 	e.HidePort = true
-	e.Use(prepareRequestContextMiddleware(log))
+	e.Use(prepareRequestContextMiddleware(log, proxies))
 	e.Use(middlewares...)
 	e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
 		LogURI:     true,
@@ -54,12 +54,12 @@ type Server struct {
 }
 
 // Start begins listening and serving http requests with graceful shut down. graceful shutdown signal should be
-// passed to the function as input and should come from the signal package.
+// passed to the function as input and should come from the signal package. The address is resolved by Listen.
 // NOTE: graceful shutdown does not handle websocket and other hijacked connections (because it relies on http.server#Shutdown).
 func (s Server) Start(ctx context.Context, listenAddress string, forceShutdownTimeout time.Duration) error {
-	listener, err := net.Listen("tcp", listenAddress)
+	listener, err := Listen(listenAddress)
 	if err != nil {
-		return errors.Wrap(err, "unable to listen on address")
+		return err
 	}
 
 	return parallel.Run(ctx, func(ctx context.Context, spawn parallel.SpawnFn) error {
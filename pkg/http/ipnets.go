@@ -0,0 +1,51 @@
+package http
+
+import (
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IPNets is the list of IP ranges.
+type IPNets []*net.IPNet
+
+// ParseIPNets parses the list of CIDRs or single IPs, empty entries are skipped.
+func ParseIPNets(cidrs []string) (IPNets, error) {
+	var nets IPNets
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, errors.Errorf("invalid IP %q", cidr)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid CIDR %q", cidr)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// Match returns the first range containing the IP, nil if there is none.
+func (n IPNets) Match(ip net.IP) *net.IPNet {
+	for _, ipNet := range n {
+		if ipNet.Contains(ip) {
+			return ipNet
+		}
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package http
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPNetsMatch(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	nets, err := ParseIPNets([]string{"203.0.113.0/24", " ", "198.51.100.7", "2001:db8::/32"})
+	requireT.NoError(err)
+	requireT.Len(nets, 3)
+
+	assertT.Equal("203.0.113.0/24", nets.Match(net.ParseIP("203.0.113.9")).String())
+	assertT.Equal("198.51.100.7/32", nets.Match(net.ParseIP("198.51.100.7")).String())
+	assertT.Equal("2001:db8::/32", nets.Match(net.ParseIP("2001:db8::1")).String())
+	assertT.Nil(nets.Match(net.ParseIP("198.51.100.8")))
+}
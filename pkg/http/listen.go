@@ -0,0 +1,65 @@
+package http
+
+import (
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/pkg/systemd"
+)
+
+// AddressSystemd is the listen address taking over the socket passed by systemd socket activation. The socket
+// is selected by the name set by FileDescriptorName= of the socket unit, e.g. "systemd:public", or it is the only
+// socket passed if the name is omitted.
+const AddressSystemd = "systemd"
+
+// Listen returns the listener of the address, either <host>:<port> or the socket passed by systemd.
+func Listen(address string) (net.Listener, error) {
+	if address != AddressSystemd && !strings.HasPrefix(address, AddressSystemd+":") {
+		listener, err := net.Listen("tcp", address)
+		return listener, errors.Wrap(err, "unable to listen on address")
+	}
+	listener, err := systemd.Listener(strings.TrimPrefix(strings.TrimPrefix(address, AddressSystemd), ":"))
+	if err != nil {
+		return nil, err
+	}
+	if listener.Addr().Network() == "unix" {
+		return UnixListener(listener), nil
+	}
+	return listener, nil
+}
+
+// UnixRemoteAddr is the remote address of the requests accepted by the unix socket. It is not an IP, so the client
+// IP is taken from the forwarded headers set by the proxy on the same host, see TrustedProxies.ClientIP.
+const UnixRemoteAddr = "unix"
+
+// UnixClientIP is the client IP of the requests accepted by the unix socket without forwarded client IP, so all
+// of them share one rate limit instead of bypassing it. The unspecified IP is neither private nor loopback.
+var UnixClientIP = net.IPv4zero
+
+// UnixListener returns the listener reporting the connections accepted by the unix socket as coming from
+// UnixRemoteAddr.
+func UnixListener(listener net.Listener) net.Listener {
+	return unixListener{Listener: listener}
+}
+
+type unixListener struct {
+	net.Listener
+}
+
+func (l unixListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return unixConn{Conn: conn}, nil
+}
+
+type unixConn struct {
+	net.Conn
+}
+
+func (unixConn) RemoteAddr() net.Addr {
+	return &net.UnixAddr{Name: UnixRemoteAddr, Net: "unix"}
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListen(t *testing.T) {
+	requireT := require.New(t)
+
+	listener, err := Listen("127.0.0.1:0")
+	requireT.NoError(err)
+	requireT.NoError(listener.Close())
+
+	// the faucet is not socket activated
+	_, err = Listen(AddressSystemd)
+	requireT.Error(err)
+	_, err = Listen(AddressSystemd + ":public")
+	requireT.Error(err)
+}
+
+func TestUnixListener(t *testing.T) {
+	requireT := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "faucet.sock")
+	unix, err := net.Listen("unix", path)
+	requireT.NoError(err)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, err := TrustedProxies{}.ClientIP(r)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(ip.String()))
+	}))
+	srv.Listener = UnixListener(unix)
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	client := &http.Client{Transport: &http.Transport{
+		Dial: func(_, _ string) (net.Conn, error) {
+			return net.Dial("unix", path)
+		},
+	}}
+	clientIP := func(forwardedFor string) string {
+		req, err := http.NewRequest(http.MethodGet, "http://faucet/", nil)
+		requireT.NoError(err)
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		resp, err := client.Do(req)
+		requireT.NoError(err)
+		defer resp.Body.Close()
+		requireT.Equal(http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		requireT.NoError(err)
+		return string(body)
+	}
+
+	// the proxy on the same host is trusted without being listed
+	requireT.Equal("203.0.113.7", clientIP("203.0.113.7"))
+	// requests without forwarded client IP share the unspecified IP
+	requireT.Equal(UnixClientIP.String(), clientIP(""))
+	requireT.Equal(UnixClientIP.String(), clientIP("unknown"))
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MetadataField is the field added to every JSON object response.
+type MetadataField struct {
+	Name  string
+	Value string
+}
+
+// MetadataMiddleware appends the fields to JSON object responses, successful or not, including the errors handled
+// by echo, like 404. Fields already present in the response are kept untouched and the order of the fields is
+// preserved. Other responses, including the streamed ones, are passed through untouched.
+func MetadataMiddleware(fields ...MetadataField) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			res := c.Response()
+			original := res.Writer
+			bw := &bufferWriter{ResponseWriter: original, status: http.StatusOK}
+			res.Writer = bw
+			if err := next(c); err != nil {
+				// the error is written here so its body is extended too
+				c.Error(err)
+			}
+			res.Writer = original
+			if bw.streamed {
+				return nil
+			}
+
+			body := bw.body.Bytes()
+			if strings.HasPrefix(res.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+				body = appendFields(body, fields)
+				res.Header().Del(echo.HeaderContentLength)
+			}
+			original.WriteHeader(bw.status)
+			_, err := original.Write(body)
+			return err
+		}
+	}
+}
+
+// appendFields appends the fields missing in JSON object, other bodies are returned unchanged.
+func appendFields(body []byte, fields []MetadataField) []byte {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(body, &object); err != nil || object == nil {
+		return body
+	}
+
+	trimmed := bytes.TrimSpace(body)
+	result := append([]byte{}, trimmed[:len(trimmed)-1]...)
+	empty := len(object) == 0
+	for _, field := range fields {
+		if _, exists := object[field.Name]; exists {
+			continue
+		}
+		name, err := json.Marshal(field.Name)
+		if err != nil {
+			return body
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return body
+		}
+		if !empty {
+			result = append(result, ',')
+		}
+		empty = false
+		result = append(result, name...)
+		result = append(result, ':')
+		result = append(result, value...)
+	}
+	return append(result, '}', '\n')
+}
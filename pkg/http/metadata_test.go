@@ -0,0 +1,79 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataMiddleware(t *testing.T) {
+	e := echo.New()
+	e.Use(MetadataMiddleware(
+		MetadataField{Name: "chainId", Value: "coreum-devnet-1"},
+		MetadataField{Name: "environment", Value: "devnet"},
+	))
+	e.GET("/object", func(c Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"txHash": "hash"})
+	})
+	e.GET("/empty", func(c Context) error {
+		return c.JSON(http.StatusOK, map[string]string{})
+	})
+	e.GET("/network", func(c Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"chainId": "other"})
+	})
+	e.GET("/array", func(c Context) error {
+		return c.JSON(http.StatusOK, []string{"a"})
+	})
+	e.GET("/text", func(c Context) error {
+		return c.String(http.StatusOK, "metrics")
+	})
+
+	testCases := []struct {
+		path string
+		code int
+		body string
+	}{
+		{path: "/object", code: http.StatusOK, body: `{"txHash":"hash","chainId":"coreum-devnet-1","environment":"devnet"}` + "\n"},
+		{path: "/empty", code: http.StatusOK, body: `{"chainId":"coreum-devnet-1","environment":"devnet"}` + "\n"},
+		{path: "/network", code: http.StatusOK, body: `{"chainId":"other","environment":"devnet"}` + "\n"},
+		{path: "/array", code: http.StatusOK, body: `["a"]` + "\n"},
+		{path: "/text", code: http.StatusOK, body: "metrics"},
+		{path: "/missing", code: http.StatusNotFound, body: `{"message":"Not Found","chainId":"coreum-devnet-1","environment":"devnet"}` + "\n"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			assert.Equal(t, tc.code, rec.Code)
+			assert.Equal(t, tc.body, rec.Body.String())
+		})
+	}
+}
+
+func TestMetadataMiddlewareStreaming(t *testing.T) {
+	e := echo.New()
+	e.Use(MetadataMiddleware(MetadataField{Name: "chainId", Value: "coreum-devnet-1"}))
+	e.GET("/stream", func(c Context) error {
+		res := c.Response()
+		res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+		res.WriteHeader(http.StatusOK)
+		for _, line := range []string{`{"index":0}`, `{"index":1}`} {
+			if _, err := res.Write([]byte(line + "\n")); err != nil {
+				return err
+			}
+			res.Flush()
+		}
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, `{"index":0}`+"\n"+`{"index":1}`+"\n", rec.Body.String())
+}
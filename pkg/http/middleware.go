@@ -1,13 +1,12 @@
 package http
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"net"
 	"net/http"
-	"strings"
 
 	"github.com/google/uuid"
-	"github.com/labstack/echo/v4"
-	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
 	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
@@ -15,14 +14,16 @@ import (
 
 // Predefined headers.
 const (
-	HeaderXRequestID = "X-Request-Id"
+	HeaderXRequestID  = "X-Request-Id"
+	HeaderETag        = "ETag"
+	HeaderIfNoneMatch = "If-None-Match"
 )
 
-func prepareRequestContextMiddleware(log *zap.Logger) func(HandlerFunc) HandlerFunc {
+func prepareRequestContextMiddleware(log *zap.Logger, proxies TrustedProxies) func(HandlerFunc) HandlerFunc {
 	return func(next HandlerFunc) HandlerFunc {
 		return func(c Context) error {
 			r := c.Request()
-			userIP, err := IPFromRequest(r)
+			userIP, err := proxies.ClientIP(r)
 			if err != nil {
 				return err
 			}
@@ -39,7 +40,7 @@ func prepareRequestContextMiddleware(log *zap.Logger) func(HandlerFunc) HandlerF
 				zap.String("requestID", rid),
 				zap.String("method", r.Method),
 			)
-			ctx := logger.WithLogger(c.Request().Context(), logNew)
+			ctx := logger.WithLogger(withClientIP(c.Request().Context(), userIP), logNew)
 			request := c.Request().WithContext(ctx)
 			c.SetRequest(request)
 			return next(c)
@@ -47,27 +48,23 @@ func prepareRequestContextMiddleware(log *zap.Logger) func(HandlerFunc) HandlerF
 	}
 }
 
-// IPFromRequest returns IP of the client sending http request.
-func IPFromRequest(r *http.Request) (i net.IP, err error) {
-	host, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		return nil, errors.WithStack(err)
-	}
-
-	xForwardedFor := r.Header["X-Original-Forwarded-For"]
-	if len(xForwardedFor) == 0 {
-		xForwardedFor = r.Header[echo.HeaderXForwardedFor]
-	}
-	if len(xForwardedFor) > 0 {
-		addrs := strings.Split(xForwardedFor[len(xForwardedFor)-1], ",")
-		if addr := strings.TrimSpace(addrs[len(addrs)-1]); addr != "" {
-			host = addr
-		}
+// IPFromRequest returns IP of the client sending http request, as resolved by the server according to
+// the trusted proxies.
+func IPFromRequest(r *http.Request) (net.IP, error) {
+	if ip, ok := r.Context().Value(clientIPKey{}).(net.IP); ok {
+		return ip, nil
 	}
+	// request not processed by the server, so no forwarded header is trusted
+	return TrustedProxies{}.ClientIP(r)
+}
 
-	ip := net.ParseIP(host)
-	if ip == nil {
-		return nil, errors.Errorf("failed to parse %q as an IP address", host)
+// FingerprintFromRequest returns a fingerprint of the client software derived from the request headers.
+// It is not unique per client, but helps to link requests sent by the same script from different IPs.
+func FingerprintFromRequest(r *http.Request) string {
+	hash := sha256.New()
+	for _, header := range []string{"User-Agent", "Accept-Language", "Accept-Encoding"} {
+		hash.Write([]byte(r.Header.Get(header)))
+		hash.Write([]byte{0})
 	}
-	return ip, nil
+	return hex.EncodeToString(hash.Sum(nil))[:16]
 }
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"io"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// MIMEApplicationProtobuf is the content type of protobuf-encoded bodies.
+const MIMEApplicationProtobuf = "application/x-protobuf"
+
+// IsProtobufRequest tells if the body of the request is protobuf-encoded.
+func IsProtobufRequest(c Context) bool {
+	return strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), MIMEApplicationProtobuf)
+}
+
+// AcceptsProtobuf tells if the response should be protobuf-encoded. It is the case if the client accepts it
+// explicitly or if the client sent protobuf-encoded body without specifying the accepted content type.
+func AcceptsProtobuf(c Context) bool {
+	accept := c.Request().Header.Get(echo.HeaderAccept)
+	if accept == "" {
+		return IsProtobufRequest(c)
+	}
+	return strings.Contains(accept, MIMEApplicationProtobuf)
+}
+
+// BindProtobuf decodes protobuf-encoded body of the request into the message.
+func BindProtobuf(c Context, msg proto.Message) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := proto.Unmarshal(body, msg); err != nil {
+		return errors.WithStack(DecodeError{Reason: err.Error()})
+	}
+	return nil
+}
+
+// Protobuf sends protobuf-encoded response with the status code.
+func Protobuf(c Context, code int, msg proto.Message) error {
+	body, err := proto.Marshal(msg)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return c.Blob(code, MIMEApplicationProtobuf, body)
+}
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestProtobufRoundTrip(t *testing.T) {
+	requireT := require.New(t)
+	assertT := assert.New(t)
+
+	e := echo.New()
+	e.POST("/echo", func(c Context) error {
+		var msg wrapperspb.StringValue
+		if err := BindProtobuf(c, &msg); err != nil {
+			return err
+		}
+		if AcceptsProtobuf(c) {
+			return Protobuf(c, http.StatusOK, &msg)
+		}
+		return c.String(http.StatusOK, msg.Value)
+	})
+
+	body, err := proto.Marshal(wrapperspb.String("devcore1"))
+	requireT.NoError(err)
+
+	req := httptest.NewRequest(http.MethodPost, "/echo", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, MIMEApplicationProtobuf)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	requireT.Equal(http.StatusOK, rec.Code)
+	assertT.Equal(MIMEApplicationProtobuf, rec.Header().Get(echo.HeaderContentType))
+	var resp wrapperspb.StringValue
+	requireT.NoError(proto.Unmarshal(rec.Body.Bytes(), &resp))
+	assertT.Equal("devcore1", resp.Value)
+
+	req = httptest.NewRequest(http.MethodPost, "/echo", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, MIMEApplicationProtobuf)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationJSON)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assertT.Equal("devcore1", rec.Body.String())
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// DefaultTrustedProxies are the loopback and private ranges, typical for load balancers and ingress controllers
+// running next to the faucet.
+var DefaultTrustedProxies = []string{
+	"127.0.0.0/8",
+	"::1/128",
+	"10.0.0.0/8",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"fc00::/7",
+}
+
+// TrustedProxies decides which forwarded headers are honored when the IP of the client is extracted.
+type TrustedProxies struct {
+	nets IPNets
+}
+
+// ParseTrustedProxies parses the list of CIDRs or single IPs of the trusted proxies.
+func ParseTrustedProxies(cidrs []string) (TrustedProxies, error) {
+	nets, err := ParseIPNets(cidrs)
+	if err != nil {
+		return TrustedProxies{}, errors.Wrap(err, "invalid trusted proxy")
+	}
+	return TrustedProxies{nets: nets}, nil
+}
+
+// ClientIP returns IP of the client sending http request. Forwarded headers are honored only if the request
+// comes from the trusted proxy. The forwarded chain is then walked from the right and the first address
+// not belonging to the trusted proxy is taken, so the addresses prepended by the client are ignored.
+// X-Real-IP header, set by nginx with real_ip module, is used if the proxy doesn't forward the chain.
+// X-Original-Forwarded-For is ignored, because proxies pass it from the client unchanged, so it can't be trusted.
+// Requests accepted by the unix socket come from the proxy on the same host, so their forwarded headers are always
+// honored, and UnixClientIP is returned if there are none.
+func (p TrustedProxies) ClientIP(r *http.Request) (net.IP, error) {
+	if r.RemoteAddr == UnixRemoteAddr {
+		if ip := p.forwardedIP(r); ip != nil {
+			return ip, nil
+		}
+		return UnixClientIP, nil
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, errors.Errorf("failed to parse %q as an IP address", host)
+	}
+	if !p.isTrusted(ip) {
+		return ip, nil
+	}
+	if forwarded := p.forwardedIP(r); forwarded != nil {
+		return forwarded, nil
+	}
+	return ip, nil
+}
+
+// forwardedIP returns the client IP given by the forwarded headers, nil if there is none.
+func (p TrustedProxies) forwardedIP(r *http.Request) net.IP {
+	forwardedFor := r.Header.Values(echo.HeaderXForwardedFor)
+	if len(forwardedFor) == 0 {
+		return net.ParseIP(strings.TrimSpace(r.Header.Get(echo.HeaderXRealIP)))
+	}
+	hops := strings.Split(strings.Join(forwardedFor, ","), ",")
+	var ip net.IP
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			// the chain is broken, so nothing to the left of it can be trusted
+			break
+		}
+		ip = hop
+		if !p.isTrusted(ip) {
+			break
+		}
+	}
+	return ip
+}
+
+func (p TrustedProxies) isTrusted(ip net.IP) bool {
+	return p.nets.Match(ip) != nil
+}
+
+type clientIPKey struct{}
+
+func withClientIP(ctx context.Context, ip net.IP) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
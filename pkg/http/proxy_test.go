@@ -0,0 +1,67 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedProxiesClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		originalFor  string
+		realIP       string
+		expectedIP   string
+	}{
+		{name: "direct", remoteAddr: "1.1.1.1:1000", expectedIP: "1.1.1.1"},
+		{name: "untrusted sender", remoteAddr: "1.1.1.1:1000", forwardedFor: []string{"2.2.2.2"}, expectedIP: "1.1.1.1"},
+		{name: "trusted proxy", remoteAddr: "10.0.0.1:1000", forwardedFor: []string{"2.2.2.2"}, expectedIP: "2.2.2.2"},
+		{name: "single ip proxy", remoteAddr: "192.168.1.1:1000", forwardedFor: []string{"2.2.2.2"}, expectedIP: "2.2.2.2"},
+		{name: "spoofed prefix", remoteAddr: "10.0.0.1:1000", forwardedFor: []string{"6.6.6.6, 2.2.2.2, 10.0.0.2"}, expectedIP: "2.2.2.2"},
+		{name: "multiple headers", remoteAddr: "10.0.0.1:1000", forwardedFor: []string{"6.6.6.6", "2.2.2.2"}, expectedIP: "2.2.2.2"},
+		{name: "malformed hop", remoteAddr: "10.0.0.1:1000", forwardedFor: []string{"2.2.2.2, garbage"}, expectedIP: "10.0.0.1"},
+		{name: "only proxies", remoteAddr: "10.0.0.1:1000", forwardedFor: []string{"10.0.0.3, 10.0.0.2"}, expectedIP: "10.0.0.3"},
+		{name: "original forwarded for ignored", remoteAddr: "10.0.0.1:1000", forwardedFor: []string{"2.2.2.2"}, originalFor: "10.0.0.9", expectedIP: "2.2.2.2"},
+		{name: "real ip", remoteAddr: "10.0.0.1:1000", realIP: "4.4.4.4", expectedIP: "4.4.4.4"},
+		{name: "real ip of untrusted sender", remoteAddr: "1.1.1.1:1000", realIP: "4.4.4.4", expectedIP: "1.1.1.1"},
+		{name: "forwarded for preferred to real ip", remoteAddr: "10.0.0.1:1000", forwardedFor: []string{"2.2.2.2"}, realIP: "4.4.4.4", expectedIP: "2.2.2.2"},
+		{name: "malformed real ip", remoteAddr: "10.0.0.1:1000", realIP: "garbage", expectedIP: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				req.Header.Add(echo.HeaderXForwardedFor, value)
+			}
+			if tt.originalFor != "" {
+				req.Header.Set("X-Original-Forwarded-For", tt.originalFor)
+			}
+			if tt.realIP != "" {
+				req.Header.Set(echo.HeaderXRealIP, tt.realIP)
+			}
+
+			ip, err := proxies.ClientIP(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedIP, ip.String())
+		})
+	}
+}
+
+func TestParseTrustedProxiesInvalid(t *testing.T) {
+	_, err := ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = ParseTrustedProxies([]string{"proxy"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityMiddleware(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cfg     SecurityHeaders
+		headers map[string]string
+	}{
+		{
+			name: "defaults",
+			cfg:  SecurityHeaders{ContentSecurityPolicy: DefaultContentSecurityPolicy},
+			headers: map[string]string{
+				echo.HeaderXContentTypeOptions:     "nosniff",
+				echo.HeaderXFrameOptions:           "DENY",
+				echo.HeaderContentSecurityPolicy:   "default-src 'none'; frame-ancestors 'none'",
+				echo.HeaderStrictTransportSecurity: "",
+			},
+		},
+		{
+			name: "widget",
+			cfg: SecurityHeaders{
+				HSTSMaxAge:            365 * 24 * time.Hour,
+				HSTSIncludeSubdomains: true,
+				ContentSecurityPolicy: "default-src 'self';",
+				FrameAncestors:        []string{"https://docs.coreum.dev", "https://*.coreum.dev"},
+			},
+			headers: map[string]string{
+				echo.HeaderXFrameOptions:           "",
+				echo.HeaderContentSecurityPolicy:   "default-src 'self'; frame-ancestors https://docs.coreum.dev https://*.coreum.dev",
+				echo.HeaderStrictTransportSecurity: "max-age=31536000; includeSubDomains",
+			},
+		},
+		{
+			name: "explicit frame ancestors",
+			cfg:  SecurityHeaders{ContentSecurityPolicy: "frame-ancestors 'self'"},
+			headers: map[string]string{
+				echo.HeaderContentSecurityPolicy: "frame-ancestors 'self'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(SecurityMiddleware(tc.cfg))
+			e.GET("/", func(c Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			for name, value := range tc.headers {
+				assert.Equal(t, value, rec.Header().Get(name), name)
+			}
+		})
+	}
+}
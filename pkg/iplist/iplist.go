@@ -0,0 +1,136 @@
+// Package iplist maintains the allowlist and denylist of IP ranges loaded from files or URLs, reloading them
+// without restart, so operators can block abusive ranges as soon as they are noticed.
+package iplist
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	pkghttp "github.com/CoreumFoundation/faucet/pkg/http"
+)
+
+// maxListSize bounds the list downloaded from URL.
+const maxListSize = 10 << 20
+
+// Filter refuses the IPs of the denylist and, if the allowlist is not empty, the IPs outside of it.
+// Lists are loaded from the sources, a source is either a path of a file or http(s) URL. Each line of the list
+// is CIDR or single IP, empty lines and text after # are ignored.
+type Filter struct {
+	allowSource string
+	denySource  string
+	client      *http.Client
+
+	mu    sync.RWMutex
+	allow pkghttp.IPNets
+	deny  pkghttp.IPNets
+}
+
+// NewFilter returns the filter loading the lists from the sources, empty source means the list is empty.
+// Lists are downloaded from URLs by the client.
+func NewFilter(allowSource, denySource string, client *http.Client) *Filter {
+	return &Filter{
+		allowSource: allowSource,
+		denySource:  denySource,
+		client:      client,
+	}
+}
+
+// Allowed tells if the requests of the IP are accepted.
+func (f *Filter) Allowed(ip net.IP) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.deny.Match(ip) != nil {
+		return false
+	}
+	return len(f.allow) == 0 || f.allow.Match(ip) != nil
+}
+
+// Sizes returns the number of the entries in the allowlist and the denylist.
+func (f *Filter) Sizes() (allow, deny int) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return len(f.allow), len(f.deny)
+}
+
+// Reload loads both lists and applies them at once. If any of them fails, the previous lists are kept.
+func (f *Filter) Reload(ctx context.Context) error {
+	allow, err := Load(ctx, f.client, f.allowSource)
+	if err != nil {
+		return errors.Wrap(err, "loading allowlist failed")
+	}
+	deny, err := Load(ctx, f.client, f.denySource)
+	if err != nil {
+		return errors.Wrap(err, "loading denylist failed")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.allow = allow
+	f.deny = deny
+	return nil
+}
+
+// Load loads the list of CIDRs or IPs from the source, a path of a file or http(s) URL downloaded by the client.
+// Each line of the list is CIDR or single IP, empty lines and text after # are ignored. Empty source means
+// the list is empty.
+func Load(ctx context.Context, client *http.Client, source string) (pkghttp.IPNets, error) {
+	if source == "" {
+		return nil, nil
+	}
+	content, err := read(ctx, client, source)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		entries = append(entries, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return pkghttp.ParseIPNets(entries)
+}
+
+func read(ctx context.Context, client *http.Client, source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		content, err := os.ReadFile(source)
+		return content, errors.WithStack(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxListSize+1))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(content) > maxListSize {
+		return nil, errors.Errorf("list is larger than %d bytes", maxListSize)
+	}
+	return content, nil
+}
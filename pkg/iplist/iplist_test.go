@@ -0,0 +1,55 @@
+package iplist
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+)
+
+func TestFilter(t *testing.T) {
+	requireT := require.New(t)
+	ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), zaptest.NewLogger(t)))
+	t.Cleanup(cancel)
+
+	allowlist := "10.0.0.0/8\n# workshop\n203.0.113.0/24\n2001:db8::/32\n"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(allowlist))
+	}))
+	t.Cleanup(srv.Close)
+	denylist := filepath.Join(t.TempDir(), "denylist.txt")
+	requireT.NoError(os.WriteFile(denylist, []byte("203.0.113.66 # abuser\n"), 0o600))
+
+	f := NewFilter(srv.URL, denylist, srv.Client())
+	// nothing is refused before the lists are loaded
+	requireT.True(f.Allowed(net.ParseIP("198.51.100.1")))
+	requireT.NoError(f.Reload(ctx))
+	allow, deny := f.Sizes()
+	requireT.Equal(3, allow)
+	requireT.Equal(1, deny)
+
+	requireT.True(f.Allowed(net.ParseIP("203.0.113.1")))
+	requireT.True(f.Allowed(net.ParseIP("2001:db8::1")))
+	requireT.False(f.Allowed(net.ParseIP("203.0.113.66")))
+	requireT.False(f.Allowed(net.ParseIP("198.51.100.1")))
+
+	// invalid lists are not applied
+	requireT.NoError(os.WriteFile(denylist, []byte("203.0.113.0/33\n"), 0o600))
+	requireT.Error(f.Reload(ctx))
+	requireT.False(f.Allowed(net.ParseIP("203.0.113.66")))
+
+	// reload applies the updated lists
+	requireT.NoError(os.WriteFile(denylist, []byte("10.0.0.0/24\n"), 0o600))
+	allowlist = ""
+	requireT.NoError(f.Reload(ctx))
+	requireT.True(f.Allowed(net.ParseIP("198.51.100.1")))
+	requireT.False(f.Allowed(net.ParseIP("10.0.0.1")))
+}
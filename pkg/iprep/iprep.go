@@ -0,0 +1,242 @@
+// Package iprep flags the IPs of Tor exits, VPNs and cloud datacenters, which hide the real origin of the clients,
+// so the faucet may refuse them or require them to prove they are human.
+package iprep
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	pkghttp "github.com/CoreumFoundation/faucet/pkg/http"
+	"github.com/CoreumFoundation/faucet/pkg/iplist"
+)
+
+// Category is the kind of the network the IP belongs to.
+type Category string
+
+// Categories of the flagged IPs.
+const (
+	CategoryTor        Category = "tor"
+	CategoryVPN        Category = "vpn"
+	CategoryDatacenter Category = "datacenter"
+)
+
+// categories are checked in this order, the first match is reported.
+var categories = []Category{CategoryTor, CategoryVPN, CategoryDatacenter}
+
+// Action is what happens to the requests of the flagged IPs.
+type Action string
+
+// Actions applied to the flagged IPs.
+const (
+	// ActionBlock refuses the requests.
+	ActionBlock Action = "block"
+	// ActionCaptcha requires the captcha to be solved.
+	ActionCaptcha Action = "captcha"
+	// ActionScore lets the requests through, the flag is weighted by the abuse score.
+	ActionScore Action = "score"
+)
+
+// ParseAction parses the action.
+func ParseAction(s string) (Action, error) {
+	switch action := Action(s); action {
+	case ActionBlock, ActionCaptcha, ActionScore:
+		return action, nil
+	default:
+		return "", errors.Errorf("invalid action %q, expected %q, %q or %q", s, ActionBlock, ActionCaptcha, ActionScore)
+	}
+}
+
+// Provider returns the category of the IP, empty if it is not flagged.
+type Provider interface {
+	Category(ctx context.Context, ip net.IP) (Category, error)
+}
+
+// Providers asks the providers in order and returns the first category reported.
+type Providers []Provider
+
+// Category returns the category reported by the first provider flagging the IP.
+func (p Providers) Category(ctx context.Context, ip net.IP) (Category, error) {
+	for _, provider := range p {
+		category, err := provider.Category(ctx, ip)
+		if err != nil {
+			return "", err
+		}
+		if category != "" {
+			return category, nil
+		}
+	}
+	return "", nil
+}
+
+// Lists flags the IPs found in the static lists of the categories. Lists are loaded from the sources the same way
+// as the lists of iplist.Filter, e.g. https://check.torproject.org/torbulkexitlist for Tor exits or the ranges
+// published by the cloud providers for datacenters.
+type Lists struct {
+	sources map[Category]string
+	client  *http.Client
+
+	mu   sync.RWMutex
+	nets map[Category]pkghttp.IPNets
+}
+
+// NewLists returns the lists loaded from the sources of the categories. Lists are downloaded from URLs
+// by the client.
+func NewLists(sources map[Category]string, client *http.Client) *Lists {
+	return &Lists{
+		sources: sources,
+		client:  client,
+		nets:    map[Category]pkghttp.IPNets{},
+	}
+}
+
+// Category returns the category of the first list containing the IP.
+func (l *Lists) Category(_ context.Context, ip net.IP) (Category, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, category := range categories {
+		if l.nets[category].Match(ip) != nil {
+			return category, nil
+		}
+	}
+	return "", nil
+}
+
+// Reload loads all the lists and applies them at once. If any of them fails, the previous lists are kept.
+func (l *Lists) Reload(ctx context.Context) error {
+	nets := map[Category]pkghttp.IPNets{}
+	for category, source := range l.sources {
+		list, err := iplist.Load(ctx, l.client, source)
+		if err != nil {
+			return errors.Wrapf(err, "loading %s list failed", category)
+		}
+		nets[category] = list
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.nets = nets
+	return nil
+}
+
+// maxCachedIPs bounds the number of IPs whose categories are cached by HTTPProvider.
+const maxCachedIPs = 100000
+
+// HTTPProvider asks the HTTP API of the reputation service. {ip} in the URL template is replaced by the IP and
+// {key} by the API key, so the key is configured apart from the template and isn't logged with it. The response is JSON object with boolean fields: tor, vpn or proxy, and datacenter or hosting, so e.g.
+// http://ip-api.com/json/{ip}?fields=proxy,hosting is understood as is. Answers are cached for the TTL.
+type HTTPProvider struct {
+	urlTemplate string
+	key         string
+	client      *http.Client
+	ttl         time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedCategory
+}
+
+type cachedCategory struct {
+	category  Category
+	expiresAt time.Time
+}
+
+// NewHTTPProvider returns the provider asking the service at the URL template. The key may be empty if the service
+// needs none, otherwise the template must contain {key}.
+func NewHTTPProvider(urlTemplate, key string, client *http.Client, ttl time.Duration) (*HTTPProvider, error) {
+	if !strings.Contains(urlTemplate, "{ip}") {
+		return nil, errors.Errorf("URL template %q doesn't contain {ip}", urlTemplate)
+	}
+	if (key != "") != strings.Contains(urlTemplate, "{key}") {
+		return nil, errors.Errorf("URL template %q must contain {key} if and only if the key is set", urlTemplate)
+	}
+	return &HTTPProvider{
+		urlTemplate: urlTemplate,
+		key:         key,
+		client:      client,
+		ttl:         ttl,
+		cache:       map[string]cachedCategory{},
+	}, nil
+}
+
+// Category asks the service for the category of the IP unless it is cached.
+func (p *HTTPProvider) Category(ctx context.Context, ip net.IP) (Category, error) {
+	key := ip.String()
+	now := time.Now()
+	p.mu.Lock()
+	cached, ok := p.cache[key]
+	p.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.category, nil
+	}
+
+	category, err := p.ask(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.cache) >= maxCachedIPs {
+		for k, c := range p.cache {
+			if !now.Before(c.expiresAt) {
+				delete(p.cache, k)
+			}
+		}
+		if len(p.cache) >= maxCachedIPs {
+			p.cache = map[string]cachedCategory{}
+		}
+	}
+	p.cache[key] = cachedCategory{category: category, expiresAt: now.Add(p.ttl)}
+	return category, nil
+}
+
+func (p *HTTPProvider) ask(ctx context.Context, ip string) (Category, error) {
+	rawURL := strings.NewReplacer("{ip}", ip, "{key}", url.QueryEscape(p.key)).Replace(p.urlTemplate)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		// the error quotes the URL, which contains the key
+		return "", errors.New("URL of IP reputation service is invalid")
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return "", errors.Wrap(err, "asking IP reputation service failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("IP reputation service responded with status %d", resp.StatusCode)
+	}
+
+	var answer struct {
+		Tor        bool `json:"tor"`
+		VPN        bool `json:"vpn"`
+		Proxy      bool `json:"proxy"`
+		Datacenter bool `json:"datacenter"`
+		Hosting    bool `json:"hosting"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&answer); err != nil {
+		return "", errors.Wrap(err, "decoding answer of IP reputation service failed")
+	}
+	switch {
+	case answer.Tor:
+		return CategoryTor, nil
+	case answer.VPN || answer.Proxy:
+		return CategoryVPN, nil
+	case answer.Datacenter || answer.Hosting:
+		return CategoryDatacenter, nil
+	default:
+		return "", nil
+	}
+}
@@ -0,0 +1,126 @@
+package iprep
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLists(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("# exits\n198.51.100.7\n"))
+	}))
+	t.Cleanup(srv.Close)
+	datacenters := filepath.Join(t.TempDir(), "datacenters.txt")
+	requireT.NoError(os.WriteFile(datacenters, []byte("198.51.100.0/24\n"), 0o600))
+
+	l := NewLists(map[Category]string{
+		CategoryTor:        srv.URL,
+		CategoryDatacenter: datacenters,
+	}, srv.Client())
+	category, err := l.Category(ctx, net.ParseIP("198.51.100.7"))
+	requireT.NoError(err)
+	requireT.Empty(category)
+
+	requireT.NoError(l.Reload(ctx))
+	// tor wins over datacenter
+	category, err = l.Category(ctx, net.ParseIP("198.51.100.7"))
+	requireT.NoError(err)
+	requireT.Equal(CategoryTor, category)
+	category, err = l.Category(ctx, net.ParseIP("198.51.100.8"))
+	requireT.NoError(err)
+	requireT.Equal(CategoryDatacenter, category)
+	category, err = l.Category(ctx, net.ParseIP("203.0.113.1"))
+	requireT.NoError(err)
+	requireT.Empty(category)
+
+	// failed reload keeps the lists
+	requireT.NoError(os.Remove(datacenters))
+	requireT.Error(l.Reload(ctx))
+	category, err = l.Category(ctx, net.ParseIP("198.51.100.8"))
+	requireT.NoError(err)
+	requireT.Equal(CategoryDatacenter, category)
+}
+
+func TestHTTPProvider(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Query().Get("key") != "s3cret&x" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/json/198.51.100.1":
+			_, _ = w.Write([]byte(`{"proxy":true,"hosting":true}`))
+		case "/json/198.51.100.2":
+			_, _ = w.Write([]byte(`{"proxy":false,"hosting":true}`))
+		case "/json/198.51.100.3":
+			_, _ = w.Write([]byte(`{"proxy":false,"hosting":false}`))
+		default:
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := NewHTTPProvider(srv.URL+"/json", "", srv.Client(), time.Minute)
+	requireT.Error(err)
+	_, err = NewHTTPProvider(srv.URL+"/json/{ip}", "secret", srv.Client(), time.Minute)
+	requireT.Error(err)
+	_, err = NewHTTPProvider(srv.URL+"/json/{ip}?key={key}", "", srv.Client(), time.Minute)
+	requireT.Error(err)
+	p, err := NewHTTPProvider(srv.URL+"/json/{ip}?fields=proxy,hosting&key={key}", "s3cret&x", srv.Client(),
+		time.Minute)
+	requireT.NoError(err)
+
+	category, err := p.Category(ctx, net.ParseIP("198.51.100.1"))
+	requireT.NoError(err)
+	requireT.Equal(CategoryVPN, category)
+	category, err = p.Category(ctx, net.ParseIP("198.51.100.2"))
+	requireT.NoError(err)
+	requireT.Equal(CategoryDatacenter, category)
+	category, err = p.Category(ctx, net.ParseIP("198.51.100.3"))
+	requireT.NoError(err)
+	requireT.Empty(category)
+	_, err = p.Category(ctx, net.ParseIP("198.51.100.4"))
+	requireT.Error(err)
+
+	// answers are cached
+	category, err = p.Category(ctx, net.ParseIP("198.51.100.1"))
+	requireT.NoError(err)
+	requireT.Equal(CategoryVPN, category)
+	requireT.EqualValues(4, atomic.LoadInt32(&calls))
+
+	// the first provider flagging the IP wins
+	providers := Providers{NewLists(nil, nil), p}
+	category, err = providers.Category(ctx, net.ParseIP("198.51.100.2"))
+	requireT.NoError(err)
+	requireT.Equal(CategoryDatacenter, category)
+}
+
+func TestParseAction(t *testing.T) {
+	requireT := require.New(t)
+
+	action, err := ParseAction("captcha")
+	requireT.NoError(err)
+	requireT.Equal(ActionCaptcha, action)
+	action, err = ParseAction("score")
+	requireT.NoError(err)
+	requireT.Equal(ActionScore, action)
+	_, err = ParseAction("warn")
+	requireT.Error(err)
+}
@@ -0,0 +1,18 @@
+// Package kv defines the key-value store sharing the state of rate limits and cooldowns between the replicas
+// of the faucet.
+package kv
+
+import (
+	"context"
+	"time"
+)
+
+// Store is the key-value store shared by many instances of the faucet, e.g. Redis.
+type Store interface {
+	// Get returns the value of the key and whether it exists.
+	Get(ctx context.Context, key string) (string, bool, error)
+	// CompareAndSwap atomically replaces the value of the key by the one expiring after ttl if the current value
+	// is the expected one. Empty expected value means the key doesn't exist, empty value deletes the key. It returns
+	// false if the value was changed concurrently.
+	CompareAndSwap(ctx context.Context, key, expected, value string, ttl time.Duration) (bool, error)
+}
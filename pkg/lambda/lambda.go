@@ -0,0 +1,276 @@
+// Package lambda runs the http handler under AWS Lambda, serving the requests delivered by API Gateway
+// (REST and HTTP APIs) and Application Load Balancer. It talks to the Lambda runtime API directly,
+// so no AWS SDK is required.
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+)
+
+// EnvRuntimeAPI is the environment variable set by Lambda to the address of the runtime API.
+const EnvRuntimeAPI = "AWS_LAMBDA_RUNTIME_API"
+
+const (
+	runtimeAPIVersion     = "2018-06-01"
+	headerRequestID       = "Lambda-Runtime-Aws-Request-Id"
+	headerDeadline        = "Lambda-Runtime-Deadline-Ms"
+	headerContentEncoding = "Content-Encoding"
+	payloadVersionHTTPAPI = "2.0"
+)
+
+// Serve processes the invocations received from the runtime API by the handler until the context is canceled.
+func Serve(ctx context.Context, runtimeAPI string, handler http.Handler) error {
+	log := logger.Get(ctx)
+	log.Info("Started processing Lambda invocations", zap.String("runtimeAPI", runtimeAPI))
+
+	// waiting for the next invocation blocks until it comes, so the client has no timeout
+	client := &http.Client{}
+	baseURL := fmt.Sprintf("http://%s/%s/runtime", runtimeAPI, runtimeAPIVersion)
+	for {
+		requestID, deadline, payload, err := nextInvocation(ctx, client, baseURL)
+		if err != nil {
+			if ctx.Err() != nil {
+				return errors.WithStack(ctx.Err())
+			}
+			return err
+		}
+
+		invocationCtx, cancel := context.WithDeadline(ctx, deadline)
+		response, err := HandleEvent(invocationCtx, handler, payload)
+		cancel()
+		if err != nil {
+			log.Error("Lambda invocation failed", zap.String("requestID", requestID), zap.Error(err))
+			err = reportError(ctx, client, baseURL, requestID, err)
+		} else {
+			err = postInvocation(ctx, client, baseURL+"/invocation/"+requestID+"/response", response)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// HandleEvent serves the request described by API Gateway or ALB event and returns the response event.
+func HandleEvent(ctx context.Context, handler http.Handler, payload []byte) ([]byte, error) {
+	var ev event
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		return nil, errors.Wrap(err, "invalid event")
+	}
+
+	req, err := ev.request(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	res, err := json.Marshal(ev.response(rec.Result()))
+	return res, errors.WithStack(err)
+}
+
+// event contains the fields of API Gateway REST API (payload v1), HTTP API (payload v2) and ALB events.
+type event struct {
+	Version                         string              `json:"version"`
+	HTTPMethod                      string              `json:"httpMethod"`
+	Path                            string              `json:"path"`
+	RawPath                         string              `json:"rawPath"`
+	RawQueryString                  string              `json:"rawQueryString"`
+	Headers                         map[string]string   `json:"headers"`
+	MultiValueHeaders               map[string][]string `json:"multiValueHeaders"`
+	QueryStringParameters           map[string]string   `json:"queryStringParameters"`
+	MultiValueQueryStringParameters map[string][]string `json:"multiValueQueryStringParameters"`
+	Cookies                         []string            `json:"cookies"`
+	Body                            string              `json:"body"`
+	IsBase64Encoded                 bool                `json:"isBase64Encoded"`
+	RequestContext                  struct {
+		ELB      *struct{} `json:"elb"`
+		Identity struct {
+			SourceIP string `json:"sourceIp"`
+		} `json:"identity"`
+		HTTP struct {
+			Method   string `json:"method"`
+			SourceIP string `json:"sourceIp"`
+		} `json:"http"`
+	} `json:"requestContext"`
+}
+
+func (ev event) request(ctx context.Context) (*http.Request, error) {
+	body := []byte(ev.Body)
+	if ev.IsBase64Encoded {
+		var err error
+		body, err = base64.StdEncoding.DecodeString(ev.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid base64-encoded body")
+		}
+	}
+
+	method, path, query, sourceIP := ev.HTTPMethod, ev.Path, ev.query(), ev.RequestContext.Identity.SourceIP
+	if ev.Version == payloadVersionHTTPAPI {
+		method, path, query, sourceIP = ev.RequestContext.HTTP.Method, ev.RawPath, ev.RawQueryString,
+			ev.RequestContext.HTTP.SourceIP
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req.URL.RawQuery = query
+	for name, value := range ev.Headers {
+		req.Header.Set(name, value)
+	}
+	for name, values := range ev.MultiValueHeaders {
+		req.Header.Del(name)
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	for _, cookie := range ev.Cookies {
+		req.Header.Add("Cookie", cookie)
+	}
+	req.Host = req.Header.Get("Host")
+	req.ContentLength = int64(len(body))
+
+	if ev.RequestContext.ELB != nil {
+		// ALB appends the address of the client to X-Forwarded-For header, so the last entry is trustworthy
+		forwarded := strings.Split(req.Header.Get("X-Forwarded-For"), ",")
+		sourceIP = strings.TrimSpace(forwarded[len(forwarded)-1])
+	}
+	if sourceIP != "" {
+		req.RemoteAddr = net.JoinHostPort(sourceIP, "0")
+	}
+	return req, nil
+}
+
+func (ev event) query() string {
+	values := url.Values{}
+	for name, value := range ev.QueryStringParameters {
+		values.Set(name, value)
+	}
+	for name, multi := range ev.MultiValueQueryStringParameters {
+		values[name] = multi
+	}
+	return values.Encode()
+}
+
+// responseEvent contains the fields of API Gateway and ALB responses.
+type responseEvent struct {
+	StatusCode        int                 `json:"statusCode"`
+	StatusDescription string              `json:"statusDescription,omitempty"`
+	Headers           map[string]string   `json:"headers,omitempty"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders,omitempty"`
+	Cookies           []string            `json:"cookies,omitempty"`
+	Body              string              `json:"body"`
+	IsBase64Encoded   bool                `json:"isBase64Encoded"`
+}
+
+func (ev event) response(res *http.Response) responseEvent {
+	body, _ := io.ReadAll(res.Body)
+	result := responseEvent{StatusCode: res.StatusCode}
+	if ev.RequestContext.ELB != nil {
+		result.StatusDescription = fmt.Sprintf("%d %s", res.StatusCode, http.StatusText(res.StatusCode))
+	}
+
+	// compressed and binary bodies must be base64-encoded, text is passed as is, so it is readable
+	// even if API Gateway is not configured to decode binary media types
+	if res.Header.Get(headerContentEncoding) != "" || !utf8.Valid(body) {
+		result.Body = base64.StdEncoding.EncodeToString(body)
+		result.IsBase64Encoded = true
+	} else {
+		result.Body = string(body)
+	}
+
+	if ev.Version == payloadVersionHTTPAPI {
+		result.Cookies = res.Header.Values("Set-Cookie")
+		res.Header.Del("Set-Cookie")
+	}
+	// ALB and REST API respond with multi-value headers only if the request contained them
+	if ev.MultiValueHeaders != nil {
+		result.MultiValueHeaders = res.Header
+		return result
+	}
+	result.Headers = map[string]string{}
+	for name, values := range res.Header {
+		result.Headers[name] = strings.Join(values, ",")
+	}
+	return result
+}
+
+func nextInvocation(
+	ctx context.Context,
+	client *http.Client,
+	baseURL string,
+) (string, time.Time, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/invocation/next", nil)
+	if err != nil {
+		return "", time.Time{}, nil, errors.WithStack(err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return "", time.Time{}, nil, errors.Wrap(err, "fetching next invocation failed")
+	}
+	defer res.Body.Close()
+
+	payload, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", time.Time{}, nil, errors.Wrap(err, "reading next invocation failed")
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", time.Time{}, nil, errors.Errorf("fetching next invocation failed with status %d", res.StatusCode)
+	}
+
+	deadline := time.Now().Add(time.Minute)
+	if ms, err := strconv.ParseInt(res.Header.Get(headerDeadline), 10, 64); err == nil {
+		deadline = time.UnixMilli(ms)
+	}
+	return res.Header.Get(headerRequestID), deadline, payload, nil
+}
+
+func reportError(ctx context.Context, client *http.Client, baseURL, requestID string, invocationErr error) error {
+	body, err := json.Marshal(struct {
+		ErrorMessage string `json:"errorMessage"`
+		ErrorType    string `json:"errorType"`
+	}{
+		ErrorMessage: invocationErr.Error(),
+		ErrorType:    "InvocationError",
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return postInvocation(ctx, client, baseURL+"/invocation/"+requestID+"/error", body)
+}
+
+func postInvocation(ctx context.Context, client *http.Client, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "posting invocation result failed")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusAccepted {
+		return errors.Errorf("posting invocation result failed with status %d", res.StatusCode)
+	}
+	return nil
+}
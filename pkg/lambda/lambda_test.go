@@ -0,0 +1,132 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+)
+
+// echoHandler responds with the details of the request.
+var echoHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Set-Cookie", "session=1")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"method":     r.Method,
+		"path":       r.URL.Path,
+		"query":      r.URL.RawQuery,
+		"remoteAddr": r.RemoteAddr,
+		"body":       string(body),
+		"tenant":     r.Header.Get("X-Faucet-Tenant"),
+	})
+})
+
+func TestHandleEvent(t *testing.T) {
+	testCases := []struct {
+		name    string
+		event   string
+		ip      string
+		headers string
+	}{
+		{
+			name: "rest-api",
+			event: `{"httpMethod":"POST","path":"/api/faucet/v1/fund","queryStringParameters":{"minimal":"true"},` +
+				`"headers":{"x-faucet-tenant":"ci"},"body":"eyJhZGRyZXNzIjoiYSJ9","isBase64Encoded":true,` +
+				`"requestContext":{"identity":{"sourceIp":"1.2.3.4"}}}`,
+			ip:      "1.2.3.4:0",
+			headers: "headers",
+		},
+		{
+			name: "http-api",
+			event: `{"version":"2.0","rawPath":"/api/faucet/v1/fund","rawQueryString":"minimal=true",` +
+				`"headers":{"x-faucet-tenant":"ci"},"body":"{\"address\":\"a\"}",` +
+				`"requestContext":{"http":{"method":"POST","sourceIp":"1.2.3.4"}}}`,
+			ip:      "1.2.3.4:0",
+			headers: "headers",
+		},
+		{
+			name: "alb",
+			event: `{"httpMethod":"POST","path":"/api/faucet/v1/fund","multiValueQueryStringParameters":{"minimal":["true"]},` +
+				`"multiValueHeaders":{"x-faucet-tenant":["ci"],"x-forwarded-for":["9.9.9.9, 1.2.3.4"]},` +
+				`"body":"{\"address\":\"a\"}","requestContext":{"elb":{"targetGroupArn":"arn"}}}`,
+			ip:      "1.2.3.4:0",
+			headers: "multiValueHeaders",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assertT := assert.New(t)
+			requireT := require.New(t)
+
+			payload, err := HandleEvent(context.Background(), echoHandler, []byte(tc.event))
+			requireT.NoError(err)
+
+			var res map[string]json.RawMessage
+			requireT.NoError(json.Unmarshal(payload, &res))
+			assertT.Equal("201", string(res["statusCode"]))
+			assertT.Contains(res, tc.headers)
+
+			var body string
+			requireT.NoError(json.Unmarshal(res["body"], &body))
+			assertT.JSONEq(`{"method":"POST","path":"/api/faucet/v1/fund","query":"minimal=true",`+
+				`"remoteAddr":"`+tc.ip+`","body":"{\"address\":\"a\"}","tenant":"ci"}`, body)
+		})
+	}
+}
+
+func TestServe(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	responses := make(chan string, 1)
+	var served bool
+	runtime := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/2018-06-01/runtime/invocation/next" && !served:
+			served = true
+			w.Header().Set(headerRequestID, "req1")
+			w.Header().Set(headerDeadline, "4102444800000")
+			_, _ = w.Write([]byte(`{"version":"2.0","rawPath":"/status","requestContext":{"http":{"method":"GET"}}}`))
+		case r.URL.Path == "/2018-06-01/runtime/invocation/next":
+			<-r.Context().Done()
+		case r.URL.Path == "/2018-06-01/runtime/invocation/req1/response":
+			body, _ := io.ReadAll(r.Body)
+			responses <- string(body)
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer runtime.Close()
+
+	ctx, cancel := context.WithTimeout(logger.WithLogger(context.Background(), zaptest.NewLogger(t)), 10*time.Second)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- Serve(ctx, strings.TrimPrefix(runtime.URL, "http://"), echoHandler)
+	}()
+
+	select {
+	case response := <-responses:
+		assertT.Contains(response, `"statusCode":201`)
+		assertT.Contains(response, `"cookies":["session=1"]`)
+	case <-ctx.Done():
+		requireT.Fail("response not posted")
+	}
+	cancel()
+	requireT.ErrorIs(<-errCh, context.Canceled)
+}
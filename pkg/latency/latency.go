@@ -0,0 +1,16 @@
+// Package latency summarizes request latencies measured by the benchmarks and performance tests.
+package latency
+
+import "time"
+
+// PercentileMS returns the nearest-rank percentile of the sorted latencies in milliseconds.
+func PercentileMS(sorted []time.Duration, percentile int) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (len(sorted)*percentile + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return float64(sorted[rank-1].Microseconds()) / 1000
+}
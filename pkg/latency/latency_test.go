@@ -0,0 +1,22 @@
+package latency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentileMS(t *testing.T) {
+	assertT := assert.New(t)
+
+	sorted := make([]time.Duration, 0, 100)
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	assertT.InDelta(50, PercentileMS(sorted, 50), 0.001)
+	assertT.InDelta(99, PercentileMS(sorted, 99), 0.001)
+	assertT.InDelta(1, PercentileMS(sorted, 0), 0.001)
+	assertT.InDelta(1.5, PercentileMS([]time.Duration{1500 * time.Microsecond}, 99), 0.001)
+	assertT.Zero(PercentileMS(nil, 50))
+}
@@ -7,12 +7,14 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/pkg/clock"
 )
 
-func newPeriod(duration time.Duration) period {
+func newPeriod(start time.Time, duration time.Duration) period {
 	return period{
 		duration: duration,
-		end:      time.Now().Add(duration),
+		end:      start.Add(duration),
 		counters: map[string]uint64{},
 	}
 }
@@ -23,11 +25,11 @@ type period struct {
 	counters map[string]uint64
 }
 
-func (p period) GetProportionally(ip net.IP) uint64 {
+func (p period) GetProportionally(ip net.IP, now time.Time) uint64 {
 	if p.duration.Nanoseconds() == 0 {
 		return 0
 	}
-	overlappedDuration := time.Since(p.end)
+	overlappedDuration := now.Sub(p.end)
 	if overlappedDuration >= p.duration {
 		return 0
 	}
@@ -43,11 +45,14 @@ func (p period) Increment(ip net.IP) {
 }
 
 // NewWeightedWindowLimiter returns new limiter implementing weighted window algorithm.
-func NewWeightedWindowLimiter(limit uint64, duration time.Duration) *WeightedWindowLimiter {
+//
+// Deprecated: use NewRateLimiter with the sliding window of the ratelimit package.
+func NewWeightedWindowLimiter(limit uint64, duration time.Duration, clock clock.Clock) *WeightedWindowLimiter {
 	return &WeightedWindowLimiter{
 		limit:    limit,
 		duration: duration,
-		current:  newPeriod(duration),
+		clock:    clock,
+		current:  newPeriod(clock.Now(), duration),
 	}
 }
 
@@ -55,6 +60,7 @@ func NewWeightedWindowLimiter(limit uint64, duration time.Duration) *WeightedWin
 type WeightedWindowLimiter struct {
 	limit    uint64
 	duration time.Duration
+	clock    clock.Clock
 
 	mu       sync.Mutex
 	previous period
@@ -66,13 +72,50 @@ func (l *WeightedWindowLimiter) IsRequestAllowed(ip net.IP) bool {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	allowed := l.previous.GetProportionally(ip)+l.current.Get(ip) <= l.limit
+	now := l.clock.Now()
+	l.rotate(now)
+	allowed := l.previous.GetProportionally(ip, now)+l.current.Get(ip) <= l.limit
 	if allowed {
 		l.current.Increment(ip)
 	}
 	return allowed
 }
 
+// NextAllowedAt returns the time the next request from the IP will be allowed at.
+func (l *WeightedWindowLimiter) NextAllowedAt(ip net.IP) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	l.rotate(now)
+	currentStart := l.current.end.Add(-l.duration)
+	current := l.current.Get(ip)
+	if current <= l.limit {
+		at := currentStart.Add(weightDecay(l.previous.Get(ip), l.limit-current+1, l.duration))
+		if at.Before(now) {
+			return now
+		}
+		return at
+	}
+	// current period becomes the previous one and must decay enough
+	return l.current.end.Add(weightDecay(current, l.limit+1, l.duration))
+}
+
+// Remaining returns the number of requests from the IP allowed at once, without consuming any.
+func (l *WeightedWindowLimiter) Remaining(ip net.IP) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	l.rotate(now)
+	// requests are allowed as long as the count doesn't exceed the limit before they are counted
+	used := l.previous.GetProportionally(ip, now) + l.current.Get(ip)
+	if used > l.limit {
+		return 0
+	}
+	return l.limit - used + 1
+}
+
 // Run runs cleaning task of the limiter.
 func (l *WeightedWindowLimiter) Run(ctx context.Context) error {
 	for {
@@ -81,9 +124,33 @@ func (l *WeightedWindowLimiter) Run(ctx context.Context) error {
 			return errors.WithStack(ctx.Err())
 		case <-time.After(l.duration):
 			l.mu.Lock()
-			l.previous = l.current
-			l.current = newPeriod(l.duration)
+			l.rotate(l.clock.Now())
 			l.mu.Unlock()
 		}
 	}
 }
+
+// rotate starts new period if the current one has ended. Periods are rotated lazily, so the limiter behaves
+// correctly even if the clock jumps forward.
+func (l *WeightedWindowLimiter) rotate(now time.Time) {
+	if now.Before(l.current.end) {
+		return
+	}
+	if now.Before(l.current.end.Add(l.duration)) {
+		l.previous = l.current
+		l.current = newPeriod(l.current.end, l.duration)
+		return
+	}
+	// more than one period has passed, so nothing counted so far matters anymore
+	l.previous = newPeriod(now.Add(-l.duration), l.duration)
+	l.current = newPeriod(now, l.duration)
+}
+
+// weightDecay returns how long since the start of the period the weight of the count from the previous period
+// stays at or above the capacity.
+func weightDecay(count, capacity uint64, duration time.Duration) time.Duration {
+	if count < capacity {
+		return 0
+	}
+	return duration - time.Duration(float64(duration)*float64(capacity)/float64(count)) + time.Nanosecond
+}
@@ -0,0 +1,84 @@
+package limiter
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+func TestWeightedWindowLimiter(t *testing.T) {
+	assertT := assert.New(t)
+
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	l := NewWeightedWindowLimiter(1, time.Hour, clk)
+	ip := net.ParseIP("1.1.1.1")
+
+	assertT.True(l.IsRequestAllowed(ip))
+	assertT.True(l.IsRequestAllowed(ip))
+	assertT.False(l.IsRequestAllowed(ip))
+	assertT.True(l.IsRequestAllowed(net.ParseIP("2.2.2.2")))
+
+	// previous period still weighs 2 * 3/4, rounded down to 1
+	clk.Advance(75 * time.Minute)
+	assertT.True(l.IsRequestAllowed(ip))
+	assertT.False(l.IsRequestAllowed(ip))
+
+	// previous period weighs 2 * 1/4 now, rounded down to 0
+	clk.Advance(30 * time.Minute)
+	assertT.True(l.IsRequestAllowed(ip))
+	assertT.False(l.IsRequestAllowed(ip))
+
+	// jumping over many periods resets the limits
+	clk.Advance(10 * time.Hour)
+	assertT.True(l.IsRequestAllowed(ip))
+	assertT.True(l.IsRequestAllowed(ip))
+	assertT.False(l.IsRequestAllowed(ip))
+}
+
+func TestWeightedWindowLimiterNextAllowedAt(t *testing.T) {
+	assertT := assert.New(t)
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewManual(start)
+	l := NewWeightedWindowLimiter(1, time.Hour, clk)
+	ip := net.ParseIP("1.1.1.1")
+
+	assertT.Equal(start, l.NextAllowedAt(ip))
+	assertT.True(l.IsRequestAllowed(ip))
+	assertT.True(l.IsRequestAllowed(ip))
+	assertT.False(l.IsRequestAllowed(ip))
+
+	// weight of 2 requests drops below 2 just after the next period starts
+	next := l.NextAllowedAt(ip)
+	assertT.Equal(start.Add(time.Hour+time.Nanosecond), next)
+
+	clk.Advance(next.Sub(start) - time.Second)
+	assertT.False(l.IsRequestAllowed(ip))
+	clk.Advance(time.Second)
+	assertT.Equal(next, l.NextAllowedAt(ip))
+	assertT.True(l.IsRequestAllowed(ip))
+}
+
+func TestWeightedWindowLimiterRemaining(t *testing.T) {
+	assertT := assert.New(t)
+
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	l := NewWeightedWindowLimiter(1, time.Hour, clk)
+	ip := net.ParseIP("1.1.1.1")
+
+	assertT.EqualValues(2, l.Remaining(ip))
+	assertT.True(l.IsRequestAllowed(ip))
+	assertT.EqualValues(1, l.Remaining(ip))
+	assertT.True(l.IsRequestAllowed(ip))
+	assertT.EqualValues(0, l.Remaining(ip))
+	assertT.False(l.IsRequestAllowed(ip))
+	assertT.EqualValues(0, l.Remaining(ip))
+
+	// previous period weighs 2 * 1/4 now, rounded down to 0
+	clk.Advance(105 * time.Minute)
+	assertT.EqualValues(2, l.Remaining(ip))
+}
@@ -0,0 +1,107 @@
+package limiter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+// Quota limits the number of requests allowed in each window of the period.
+type Quota struct {
+	Limit  uint64
+	Period time.Duration
+}
+
+// QuotaUsage describes the usage of the quota in the current window.
+type QuotaUsage struct {
+	Quota     Quota
+	Consumed  uint64
+	Remaining uint64
+	ResetsAt  time.Time
+}
+
+type quotaWindow struct {
+	start    time.Time
+	consumed uint64
+}
+
+// NewQuotaLimiter returns new limiter enforcing all the quotas. Windows are fixed and aligned to the period,
+// so the holder of the key may easily predict when the quota resets.
+func NewQuotaLimiter(clock clock.Clock, quotas ...Quota) *QuotaLimiter {
+	return &QuotaLimiter{
+		clock:   clock,
+		quotas:  quotas,
+		windows: map[string][]quotaWindow{},
+	}
+}
+
+// QuotaLimiter limits requests of the keys, e.g. API keys, to the fixed quotas.
+type QuotaLimiter struct {
+	clock  clock.Clock
+	quotas []Quota
+
+	mu      sync.Mutex
+	windows map[string][]quotaWindow
+}
+
+// Consume consumes the request from all the quotas of the key. If any quota is exhausted, nothing is consumed
+// and the time the request will be allowed at is returned.
+func (l *QuotaLimiter) Consume(key string) (bool, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	windows := l.currentWindows(key, now)
+	var allowedAt time.Time
+	for i, q := range l.quotas {
+		if windows[i].consumed >= q.Limit {
+			if resetsAt := windows[i].start.Add(q.Period); resetsAt.After(allowedAt) {
+				allowedAt = resetsAt
+			}
+		}
+	}
+	if !allowedAt.IsZero() {
+		return false, allowedAt
+	}
+	for i := range windows {
+		windows[i].consumed++
+	}
+	return true, now
+}
+
+// Usage returns the usage of all the quotas of the key in the current windows.
+func (l *QuotaLimiter) Usage(key string) []QuotaUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	windows := l.currentWindows(key, l.clock.Now())
+	usage := make([]QuotaUsage, 0, len(l.quotas))
+	for i, q := range l.quotas {
+		u := QuotaUsage{
+			Quota:    q,
+			Consumed: windows[i].consumed,
+			ResetsAt: windows[i].start.Add(q.Period),
+		}
+		if u.Consumed < q.Limit {
+			u.Remaining = q.Limit - u.Consumed
+		}
+		usage = append(usage, u)
+	}
+	return usage
+}
+
+// currentWindows returns the windows of the key, starting new ones for the quotas whose window has ended.
+func (l *QuotaLimiter) currentWindows(key string, now time.Time) []quotaWindow {
+	windows, ok := l.windows[key]
+	if !ok {
+		windows = make([]quotaWindow, len(l.quotas))
+		l.windows[key] = windows
+	}
+	for i, q := range l.quotas {
+		if start := now.Truncate(q.Period); !windows[i].start.Equal(start) {
+			windows[i] = quotaWindow{start: start}
+		}
+	}
+	return windows
+}
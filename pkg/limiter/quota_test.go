@@ -0,0 +1,46 @@
+package limiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+func TestQuotaLimiter(t *testing.T) {
+	assertT := assert.New(t)
+
+	start := time.Date(2023, 1, 1, 0, 30, 0, 0, time.UTC)
+	clk := clock.NewManual(start)
+	l := NewQuotaLimiter(clk, Quota{Limit: 2, Period: time.Hour}, Quota{Limit: 3, Period: 24 * time.Hour})
+
+	allowed, _ := l.Consume("ci")
+	assertT.True(allowed)
+	allowed, _ = l.Consume("ci")
+	assertT.True(allowed)
+	allowed, at := l.Consume("ci")
+	assertT.False(allowed)
+	assertT.Equal(time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC), at)
+	allowed, _ = l.Consume("other")
+	assertT.True(allowed)
+
+	assertT.Equal([]QuotaUsage{
+		{Quota: Quota{Limit: 2, Period: time.Hour}, Consumed: 2, Remaining: 0, ResetsAt: at},
+		{
+			Quota:     Quota{Limit: 3, Period: 24 * time.Hour},
+			Consumed:  2,
+			Remaining: 1,
+			ResetsAt:  time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}, l.Usage("ci"))
+
+	// hourly quota resets, the daily one is exhausted by the next request
+	clk.Advance(30 * time.Minute)
+	allowed, _ = l.Consume("ci")
+	assertT.True(allowed)
+	allowed, at = l.Consume("ci")
+	assertT.False(allowed)
+	assertT.Equal(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC), at)
+}
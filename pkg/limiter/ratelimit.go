@@ -0,0 +1,44 @@
+package limiter
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/CoreumFoundation/faucet/pkg/ratelimit"
+)
+
+// NewRateLimiter returns the per-IP limiter backed by the algorithm of the ratelimit package.
+func NewRateLimiter(limiter ratelimit.Limiter) *RateLimiter {
+	return &RateLimiter{limiter: limiter}
+}
+
+// RateLimiter adapts ratelimit.Limiter to the PerIPLimiter interface. If the state of the limiter is not available,
+// requests are rejected, because the faucet must not hand out funds unlimited.
+type RateLimiter struct {
+	limiter ratelimit.Limiter
+}
+
+// IsRequestAllowed checks if the request from the IP is allowed and consumes it.
+func (l *RateLimiter) IsRequestAllowed(ip net.IP) bool {
+	res, err := l.limiter.Allow(context.Background(), ip.String())
+	return err == nil && res.Allowed
+}
+
+// NextAllowedAt returns the time the next request from the IP will be allowed at.
+func (l *RateLimiter) NextAllowedAt(ip net.IP) time.Time {
+	res, err := l.limiter.Peek(context.Background(), ip.String())
+	if err != nil {
+		return time.Now()
+	}
+	return res.RetryAt
+}
+
+// Remaining returns the number of requests from the IP allowed at once, without consuming any.
+func (l *RateLimiter) Remaining(ip net.IP) uint64 {
+	res, err := l.limiter.Peek(context.Background(), ip.String())
+	if err != nil {
+		return 0
+	}
+	return res.Remaining
+}
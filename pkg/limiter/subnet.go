@@ -0,0 +1,54 @@
+package limiter
+
+import (
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// NewSubnetLimiter returns the limiter aggregating the requests of all the IPs of the same network, e.g. /24 (IPv4)
+// or /64 (IPv6), so the limit can't be bypassed by rotating the IPs within it.
+func NewSubnetLimiter(limiter PerIPLimiter, ipv4Bits, ipv6Bits int) (*SubnetLimiter, error) {
+	if ipv4Bits < 1 || ipv4Bits > 8*net.IPv4len {
+		return nil, errors.Errorf("IPv4 prefix length must be between 1 and %d", 8*net.IPv4len)
+	}
+	if ipv6Bits < 1 || ipv6Bits > 8*net.IPv6len {
+		return nil, errors.Errorf("IPv6 prefix length must be between 1 and %d", 8*net.IPv6len)
+	}
+	return &SubnetLimiter{
+		limiter:  limiter,
+		ipv4Mask: net.CIDRMask(ipv4Bits, 8*net.IPv4len),
+		ipv6Mask: net.CIDRMask(ipv6Bits, 8*net.IPv6len),
+	}, nil
+}
+
+// SubnetLimiter limits the requests of the networks the IPs belong to.
+type SubnetLimiter struct {
+	limiter  PerIPLimiter
+	ipv4Mask net.IPMask
+	ipv6Mask net.IPMask
+}
+
+// Subnet returns the network of the IP the limit is applied to.
+func (l *SubnetLimiter) Subnet(ip net.IP) *net.IPNet {
+	if v4 := ip.To4(); v4 != nil {
+		return &net.IPNet{IP: v4.Mask(l.ipv4Mask), Mask: l.ipv4Mask}
+	}
+	return &net.IPNet{IP: ip.Mask(l.ipv6Mask), Mask: l.ipv6Mask}
+}
+
+// IsRequestAllowed checks if the request from the network of the IP is allowed and consumes it.
+func (l *SubnetLimiter) IsRequestAllowed(ip net.IP) bool {
+	return l.limiter.IsRequestAllowed(l.Subnet(ip).IP)
+}
+
+// NextAllowedAt returns the time the next request from the network of the IP will be allowed at.
+func (l *SubnetLimiter) NextAllowedAt(ip net.IP) time.Time {
+	return l.limiter.NextAllowedAt(l.Subnet(ip).IP)
+}
+
+// Remaining returns the number of requests from the network of the IP allowed at once, without consuming any.
+func (l *SubnetLimiter) Remaining(ip net.IP) uint64 {
+	return l.limiter.Remaining(l.Subnet(ip).IP)
+}
@@ -0,0 +1,40 @@
+package limiter
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+func TestSubnetLimiter(t *testing.T) {
+	requireT := require.New(t)
+
+	_, err := NewSubnetLimiter(nil, 0, 64)
+	requireT.Error(err)
+	_, err = NewSubnetLimiter(nil, 24, 129)
+	requireT.Error(err)
+
+	clk := clock.NewManual(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	l, err := NewSubnetLimiter(NewWeightedWindowLimiter(1, time.Hour, clk), 24, 64)
+	requireT.NoError(err)
+
+	requireT.Equal("1.2.3.0/24", l.Subnet(net.ParseIP("1.2.3.4")).String())
+	requireT.Equal("2001:db8:1:2::/64", l.Subnet(net.ParseIP("2001:db8:1:2:3:4:5:6")).String())
+
+	// IPs of the same network share the limit
+	requireT.True(l.IsRequestAllowed(net.ParseIP("1.2.3.4")))
+	requireT.True(l.IsRequestAllowed(net.ParseIP("1.2.3.5")))
+	requireT.False(l.IsRequestAllowed(net.ParseIP("1.2.3.6")))
+	requireT.True(l.IsRequestAllowed(net.ParseIP("1.2.4.1")))
+
+	requireT.True(l.IsRequestAllowed(net.ParseIP("2001:db8:1:2::1")))
+	requireT.True(l.IsRequestAllowed(net.ParseIP("2001:db8:1:2::2")))
+	requireT.False(l.IsRequestAllowed(net.ParseIP("2001:db8:1:2:ffff::3")))
+	requireT.True(l.IsRequestAllowed(net.ParseIP("2001:db8:1:3::1")))
+
+	requireT.Equal(l.NextAllowedAt(net.ParseIP("1.2.3.4")), l.NextAllowedAt(net.ParseIP("1.2.3.200")))
+}
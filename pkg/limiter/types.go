@@ -1,8 +1,14 @@
 package limiter
 
-import "net"
+import (
+	"net"
+	"time"
+)
 
 // PerIPLimiter defines an interface of IP rate limiter.
 type PerIPLimiter interface {
 	IsRequestAllowed(ip net.IP) bool
+	NextAllowedAt(ip net.IP) time.Time
+	// Remaining returns the number of requests from the IP allowed at once, without consuming any.
+	Remaining(ip net.IP) uint64
 }
@@ -0,0 +1,201 @@
+// Package pagination pages, filters and sorts the items of the list endpoints, so every list behaves the same way.
+// Pages are addressed by the opaque cursor pointing after the last item of the previous page instead of offsets,
+// so items added or removed between the requests don't shift the pages.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultLimit is the number of items in the page if the limit is not set.
+	DefaultLimit = 100
+	// MaxLimit is the maximal number of items in the page.
+	MaxLimit = 1000
+)
+
+// ErrInvalid is returned if the query doesn't match the list, e.g. the sort field is unknown.
+var ErrInvalid = errors.New("invalid pagination query")
+
+// Query selects the page of the list.
+type Query struct {
+	// Limit is the maximal number of items in the page, DefaultLimit if zero.
+	Limit int
+	// Cursor is the NextCursor of the previous page, the first page is returned if empty.
+	Cursor string
+	// Sort is the field the items are ordered by, the default of the list is used if empty.
+	Sort string
+	// Desc orders the items in descending order.
+	Desc bool
+	// Filters maps the filter to the value the items must match.
+	Filters map[string]string
+}
+
+// Page is the page of the list.
+type Page[T any] struct {
+	Items []T
+	// NextCursor selects the next page, it is empty if this is the last one.
+	NextCursor string
+}
+
+// Spec describes the fields of the items the list may be sorted and filtered by.
+type Spec[T any] struct {
+	// ID returns the unique ID of the item, it orders the items having the same sort key.
+	ID func(item T) string
+	// Sorts maps the field to its sort key, keys are compared as strings, see TimeKey and UintKey.
+	Sorts map[string]func(item T) string
+	// DefaultSort is the field used if the query doesn't set one, DefaultDesc is its order.
+	DefaultSort string
+	DefaultDesc bool
+	// Filters maps the filter to the function reporting whether the item matches the value.
+	Filters map[string]func(item T, value string) bool
+}
+
+type cursor struct {
+	Sort string `json:"s"`
+	Desc bool   `json:"d,omitempty"`
+	Key  string `json:"k"`
+	ID   string `json:"i"`
+}
+
+// Apply returns the page of the items selected by the query. Items are not modified.
+func (s Spec[T]) Apply(items []T, q Query) (Page[T], error) {
+	limit := q.Limit
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	if limit < 0 || limit > MaxLimit {
+		return Page[T]{}, errors.Wrapf(ErrInvalid, "limit must be between 1 and %d", MaxLimit)
+	}
+	field, desc := q.Sort, q.Desc
+	if field == "" {
+		field, desc = s.DefaultSort, s.DefaultDesc
+	}
+	sortKey, ok := s.Sorts[field]
+	if !ok {
+		return Page[T]{}, errors.Wrapf(ErrInvalid, "unknown sort field %q, one of %s", field, names(s.Sorts))
+	}
+	for name := range q.Filters {
+		if _, ok := s.Filters[name]; !ok {
+			return Page[T]{}, errors.Wrapf(ErrInvalid, "unknown filter %q", name)
+		}
+	}
+	var after *cursor
+	if q.Cursor != "" {
+		c, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return Page[T]{}, err
+		}
+		if c.Sort != field || c.Desc != desc {
+			return Page[T]{}, errors.Wrap(ErrInvalid, "cursor was issued for another order")
+		}
+		after = &c
+	}
+
+	type entry struct {
+		item    T
+		key, id string
+	}
+	entries := make([]entry, 0, len(items))
+	for _, item := range items {
+		if !s.matches(item, q.Filters) {
+			continue
+		}
+		entries = append(entries, entry{item: item, key: sortKey(item), id: s.ID(item)})
+	}
+	less := func(aKey, aID, bKey, bID string) bool {
+		if aKey != bKey {
+			return (aKey < bKey) != desc
+		}
+		if aID == bID {
+			return false
+		}
+		return (aID < bID) != desc
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return less(entries[i].key, entries[i].id, entries[j].key, entries[j].id)
+	})
+	if after != nil {
+		entries = entries[sort.Search(len(entries), func(i int) bool {
+			return less(after.Key, after.ID, entries[i].key, entries[i].id)
+		}):]
+	}
+
+	page := Page[T]{Items: make([]T, 0, min(limit, len(entries)))}
+	for _, e := range entries[:min(limit, len(entries))] {
+		page.Items = append(page.Items, e.item)
+	}
+	if len(entries) > limit {
+		last := entries[limit-1]
+		page.NextCursor = encodeCursor(cursor{Sort: field, Desc: desc, Key: last.key, ID: last.id})
+	}
+	return page, nil
+}
+
+func (s Spec[T]) matches(item T, filters map[string]string) bool {
+	for name, value := range filters {
+		if !s.Filters[name](item, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseSort parses the sort field optionally prefixed by "-" ordering the items in descending order,
+// e.g. "-createdAt".
+func ParseSort(s string) (field string, desc bool) {
+	if strings.HasPrefix(s, "-") {
+		return s[1:], true
+	}
+	return s, false
+}
+
+// TimeKey returns the sort key ordering the times.
+func TimeKey(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000000Z")
+}
+
+// UintKey returns the sort key ordering the numbers.
+func UintKey(n uint64) string {
+	return fmt.Sprintf("%020d", n)
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor{}, errors.Wrap(ErrInvalid, "malformed cursor")
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return cursor{}, errors.Wrap(ErrInvalid, "malformed cursor")
+	}
+	return c, nil
+}
+
+func names[V any](m map[string]V) string {
+	list := make([]string, 0, len(m))
+	for name := range m {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return strings.Join(list, ", ")
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
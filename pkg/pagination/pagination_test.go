@@ -0,0 +1,103 @@
+package pagination
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	ID    string
+	Owner string
+	Size  uint64
+}
+
+var spec = Spec[item]{
+	ID: func(i item) string { return i.ID },
+	Sorts: map[string]func(i item) string{
+		"id":   func(i item) string { return i.ID },
+		"size": func(i item) string { return UintKey(i.Size) },
+	},
+	DefaultSort: "id",
+	Filters: map[string]func(i item, value string) bool{
+		"owner": func(i item, value string) bool { return i.Owner == value },
+	},
+}
+
+func ids(items []item) []string {
+	list := []string{}
+	for _, i := range items {
+		list = append(list, i.ID)
+	}
+	return list
+}
+
+func TestApply(t *testing.T) {
+	requireT := require.New(t)
+
+	items := []item{}
+	for i := 0; i < 5; i++ {
+		owner := "alice"
+		if i%2 == 1 {
+			owner = "bob"
+		}
+		items = append(items, item{ID: "item" + strconv.Itoa(i), Owner: owner, Size: uint64(10 - i%3)})
+	}
+
+	page, err := spec.Apply(items, Query{})
+	requireT.NoError(err)
+	requireT.Equal([]string{"item0", "item1", "item2", "item3", "item4"}, ids(page.Items))
+	requireT.Empty(page.NextCursor)
+
+	// items having the same sort key are ordered by ID, pages follow each other
+	q := Query{Limit: 2, Sort: "size", Desc: true}
+	var all []string
+	for pages := 0; ; pages++ {
+		requireT.Less(pages, 3)
+		page, err = spec.Apply(items, q)
+		requireT.NoError(err)
+		all = append(all, ids(page.Items)...)
+		if page.NextCursor == "" {
+			break
+		}
+		q.Cursor = page.NextCursor
+	}
+	requireT.Equal([]string{"item3", "item0", "item4", "item1", "item2"}, all)
+
+	// removed items don't shift the pages
+	page, err = spec.Apply(items, Query{Limit: 2})
+	requireT.NoError(err)
+	page, err = spec.Apply(items[2:], Query{Limit: 2, Cursor: page.NextCursor})
+	requireT.NoError(err)
+	requireT.Equal([]string{"item2", "item3"}, ids(page.Items))
+
+	page, err = spec.Apply(items, Query{Filters: map[string]string{"owner": "bob"}})
+	requireT.NoError(err)
+	requireT.Equal([]string{"item1", "item3"}, ids(page.Items))
+
+	_, err = spec.Apply(items, Query{Sort: "owner"})
+	requireT.ErrorIs(err, ErrInvalid)
+	_, err = spec.Apply(items, Query{Filters: map[string]string{"size": "10"}})
+	requireT.ErrorIs(err, ErrInvalid)
+	_, err = spec.Apply(items, Query{Limit: MaxLimit + 1})
+	requireT.ErrorIs(err, ErrInvalid)
+	_, err = spec.Apply(items, Query{Cursor: "???"})
+	requireT.ErrorIs(err, ErrInvalid)
+	// cursor of another order is refused
+	page, err = spec.Apply(items, Query{Limit: 1})
+	requireT.NoError(err)
+	_, err = spec.Apply(items, Query{Limit: 1, Cursor: page.NextCursor, Sort: "id", Desc: true})
+	requireT.ErrorIs(err, ErrInvalid)
+}
+
+func TestParseSort(t *testing.T) {
+	requireT := require.New(t)
+
+	field, desc := ParseSort("-createdAt")
+	requireT.Equal("createdAt", field)
+	requireT.True(desc)
+	field, desc = ParseSort("code")
+	requireT.Equal("code", field)
+	requireT.False(desc)
+}
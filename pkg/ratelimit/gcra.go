@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// NewGCRA returns the limiter spacing the events evenly over the period, allowing the burst of events at once.
+func NewGCRA(rule Rule, store Store, clock Clock) *GCRA {
+	burst := rule.Burst
+	if burst == 0 {
+		burst = rule.Limit
+	}
+	interval := rule.Period / time.Duration(rule.Limit)
+	return &GCRA{
+		interval:  interval,
+		tolerance: interval * time.Duration(burst),
+		store:     store,
+		clock:     clock,
+	}
+}
+
+// GCRA implements the generic cell rate algorithm. Each event moves the theoretical arrival time (TAT) forward
+// by the emission interval, the event is allowed unless TAT is too far ahead of the current time.
+type GCRA struct {
+	interval  time.Duration
+	tolerance time.Duration
+	store     Store
+	clock     Clock
+}
+
+// Allow consumes the event of the key if it is allowed.
+func (l *GCRA) Allow(ctx context.Context, key string) (Result, error) {
+	var result Result
+	err := l.store.Update(ctx, key, func(state State) (State, time.Time) {
+		now := l.clock.Now()
+		result = l.result(state, now)
+		if result.Allowed {
+			state.TAT = l.tat(state, now).Add(l.interval)
+			result.Remaining--
+		}
+		return state, state.TAT
+	})
+	return result, err
+}
+
+// Peek returns the result the event of the key would get.
+func (l *GCRA) Peek(ctx context.Context, key string) (Result, error) {
+	state, err := l.store.Get(ctx, key)
+	if err != nil {
+		return Result{}, err
+	}
+	return l.result(state, l.clock.Now()), nil
+}
+
+func (l *GCRA) tat(state State, now time.Time) time.Time {
+	if state.TAT.Before(now) {
+		return now
+	}
+	return state.TAT
+}
+
+func (l *GCRA) result(state State, now time.Time) Result {
+	allowAt := l.tat(state, now).Add(l.interval - l.tolerance)
+	if now.Before(allowAt) {
+		return Result{RetryAt: allowAt}
+	}
+	return Result{Allowed: true, Remaining: uint64(now.Sub(allowAt)/l.interval) + 1, RetryAt: now}
+}
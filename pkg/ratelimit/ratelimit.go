@@ -0,0 +1,83 @@
+// Package ratelimit implements rate limiting algorithms sharing the store of their state, so the algorithm may be
+// chosen per rule and the state may be kept in memory or shared by many instances.
+//
+// Available algorithms:
+//   - fixed window counts the events in windows aligned to the period, it is the cheapest one and easy to predict,
+//     but allows bursts of twice the limit around the window boundary;
+//   - sliding window weights the count of the previous window by its overlap with the sliding period, which
+//     smooths the boundary bursts at the cost of an approximation;
+//   - GCRA (generic cell rate algorithm) spaces the events evenly over the period, allowing configured burst;
+//   - token bucket refills the bucket of tokens at the constant rate, each event takes one token, so the bucket
+//     size limits the burst while the refill rate limits the sustained rate.
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Algorithm names.
+const (
+	AlgorithmFixedWindow   = "fixed-window"
+	AlgorithmSlidingWindow = "sliding-window"
+	AlgorithmGCRA          = "gcra"
+	AlgorithmTokenBucket   = "token-bucket"
+)
+
+// Algorithms lists the names of the available algorithms.
+var Algorithms = []string{AlgorithmFixedWindow, AlgorithmSlidingWindow, AlgorithmGCRA, AlgorithmTokenBucket}
+
+// Result is the outcome of the rate limit check.
+type Result struct {
+	// Allowed tells if the event is allowed.
+	Allowed bool
+	// Remaining is the number of events still allowed at once.
+	Remaining uint64
+	// RetryAt is the time the next event will be allowed at, it is the time of the check if it is allowed already.
+	RetryAt time.Time
+}
+
+// Limiter limits the rate of events of each key, e.g. an IP address.
+type Limiter interface {
+	// Allow consumes the event of the key if it is allowed.
+	Allow(ctx context.Context, key string) (Result, error)
+	// Peek returns the result the event of the key would get, without consuming it.
+	Peek(ctx context.Context, key string) (Result, error)
+}
+
+// Rule describes the limit enforced by the limiter.
+type Rule struct {
+	// Limit is the number of events allowed in the period.
+	Limit uint64
+	// Period is the duration the limit applies to.
+	Period time.Duration
+	// Burst is the number of events GCRA and token bucket allow at once, the limit is used if it is zero.
+	// It is ignored by the window algorithms.
+	Burst uint64
+}
+
+// New returns the limiter enforcing the rule with the algorithm.
+func New(algorithm string, rule Rule, store Store, clock Clock) (Limiter, error) {
+	if rule.Limit == 0 || rule.Period <= 0 {
+		return nil, errors.Errorf("invalid rule, limit and period must be positive")
+	}
+	switch algorithm {
+	case AlgorithmFixedWindow:
+		return NewFixedWindow(rule, store, clock), nil
+	case AlgorithmSlidingWindow:
+		return NewSlidingWindow(rule, store, clock), nil
+	case AlgorithmGCRA:
+		return NewGCRA(rule, store, clock), nil
+	case AlgorithmTokenBucket:
+		return NewTokenBucket(rule, store, clock), nil
+	default:
+		return nil, errors.Errorf("unknown algorithm %q, expected one of %v", algorithm, Algorithms)
+	}
+}
+
+// Clock provides the current time.
+type Clock interface {
+	Now() time.Time
+}
@@ -0,0 +1,220 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+var start = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFixedWindow(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	ctx := context.Background()
+	clk := clock.NewManual(start.Add(50 * time.Minute))
+	l := NewFixedWindow(Rule{Limit: 2, Period: time.Hour}, NewMemoryStore(clk), clk)
+
+	res, err := l.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.Equal(Result{Allowed: true, Remaining: 1, RetryAt: clk.Now()}, res)
+	res, err = l.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.True(res.Allowed)
+	assertT.EqualValues(0, res.Remaining)
+
+	res, err = l.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.Equal(Result{RetryAt: start.Add(time.Hour)}, res)
+
+	// other keys are independent
+	res, err = l.Allow(ctx, "b")
+	requireT.NoError(err)
+	assertT.True(res.Allowed)
+
+	// the window resets at the boundary, so the burst of twice the limit is possible
+	clk.Advance(10 * time.Minute)
+	res, err = l.Peek(ctx, "a")
+	requireT.NoError(err)
+	assertT.Equal(Result{Allowed: true, Remaining: 2, RetryAt: clk.Now()}, res)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	ctx := context.Background()
+	clk := clock.NewManual(start.Add(50 * time.Minute))
+	l := NewSlidingWindow(Rule{Limit: 2, Period: time.Hour}, NewMemoryStore(clk), clk)
+
+	for i := 0; i < 2; i++ {
+		res, err := l.Allow(ctx, "a")
+		requireT.NoError(err)
+		assertT.True(res.Allowed)
+	}
+	res, err := l.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.False(res.Allowed)
+	// previous count of 2 must decay below 2, which happens right after the start of the next window
+	assertT.Equal(start.Add(time.Hour+time.Nanosecond), res.RetryAt)
+
+	// unlike the fixed window, the previous window still counts after the boundary
+	clk.Advance(20 * time.Minute)
+	res, err = l.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.True(res.Allowed)
+	res, err = l.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.False(res.Allowed)
+	// 2*(1-t/h) + 1 < 2 once t > 30 minutes
+	assertT.Equal(start.Add(90*time.Minute+time.Nanosecond), res.RetryAt)
+
+	peek, err := l.Peek(ctx, "a")
+	requireT.NoError(err)
+	assertT.Equal(res, peek)
+
+	clk.Advance(21 * time.Minute)
+	res, err = l.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.True(res.Allowed)
+
+	// windows long gone are forgotten
+	clk.Advance(3 * time.Hour)
+	res, err = l.Peek(ctx, "a")
+	requireT.NoError(err)
+	assertT.EqualValues(2, res.Remaining)
+}
+
+func TestGCRA(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	ctx := context.Background()
+	clk := clock.NewManual(start)
+	l := NewGCRA(Rule{Limit: 6, Period: time.Minute, Burst: 2}, NewMemoryStore(clk), clk)
+
+	res, err := l.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.Equal(Result{Allowed: true, Remaining: 1, RetryAt: start}, res)
+	res, err = l.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.Equal(Result{Allowed: true, Remaining: 0, RetryAt: start}, res)
+
+	// once the burst is used, events are spaced by the emission interval of 10 seconds
+	res, err = l.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.Equal(Result{RetryAt: start.Add(10 * time.Second)}, res)
+
+	clk.Advance(10 * time.Second)
+	res, err = l.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.True(res.Allowed)
+	res, err = l.Peek(ctx, "a")
+	requireT.NoError(err)
+	assertT.Equal(Result{RetryAt: start.Add(20 * time.Second)}, res)
+
+	// the burst is restored after idle time
+	clk.Advance(time.Minute)
+	res, err = l.Peek(ctx, "a")
+	requireT.NoError(err)
+	assertT.EqualValues(2, res.Remaining)
+}
+
+func TestTokenBucket(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	ctx := context.Background()
+	clk := clock.NewManual(start)
+	// sustained rate of one event per 10 seconds with the burst of 3 events
+	l := NewTokenBucket(Rule{Limit: 6, Period: time.Minute, Burst: 3}, NewMemoryStore(clk), clk)
+
+	for i := 2; i >= 0; i-- {
+		res, err := l.Allow(ctx, "a")
+		requireT.NoError(err)
+		assertT.Equal(Result{Allowed: true, Remaining: uint64(i), RetryAt: start}, res)
+	}
+	res, err := l.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.Equal(Result{RetryAt: start.Add(10 * time.Second)}, res)
+
+	// tokens are refilled at the constant rate
+	clk.Advance(15 * time.Second)
+	res, err = l.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.Equal(Result{Allowed: true, Remaining: 0, RetryAt: clk.Now()}, res)
+	res, err = l.Peek(ctx, "a")
+	requireT.NoError(err)
+	assertT.Equal(Result{RetryAt: clk.Now().Add(5 * time.Second)}, res)
+
+	// the bucket never holds more than the burst
+	clk.Advance(time.Hour)
+	res, err = l.Peek(ctx, "a")
+	requireT.NoError(err)
+	assertT.EqualValues(3, res.Remaining)
+}
+
+func TestNew(t *testing.T) {
+	requireT := require.New(t)
+
+	clk := clock.NewManual(start)
+	store := NewMemoryStore(clk)
+	for _, algorithm := range Algorithms {
+		_, err := New(algorithm, Rule{Limit: 1, Period: time.Hour}, store, clk)
+		requireT.NoError(err)
+	}
+	_, err := New("leaky-bucket", Rule{Limit: 1, Period: time.Hour}, store, clk)
+	requireT.Error(err)
+	_, err = New(AlgorithmGCRA, Rule{Period: time.Hour}, store, clk)
+	requireT.Error(err)
+}
+
+func TestMemoryStorePrune(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	ctx := context.Background()
+	clk := clock.NewManual(start)
+	store := NewMemoryStore(clk)
+	l := NewFixedWindow(Rule{Limit: 1, Period: time.Hour}, store, clk)
+
+	_, err := l.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.Equal(1, store.Len())
+
+	clk.Advance(time.Hour)
+	store.Prune()
+	assertT.Equal(0, store.Len())
+}
+
+func BenchmarkLimiters(b *testing.B) {
+	clk := clock.System{}
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	for _, algorithm := range Algorithms {
+		l, err := New(algorithm, Rule{Limit: 100, Period: time.Minute}, NewMemoryStore(clk), clk)
+		require.NoError(b, err)
+		b.Run(algorithm, func(b *testing.B) {
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					if _, err := l.Allow(ctx, keys[i%len(keys)]); err != nil {
+						b.Fatal(err)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/pkg/kv"
+)
+
+// maxSwapAttempts is the number of attempts to update the state modified concurrently by other instances.
+const maxSwapAttempts = 10
+
+// NewKVStore returns the store keeping the states in the shared key-value store, so the limits are enforced
+// across all the instances using it. Keys are prefixed to separate the states of different limiters.
+func NewKVStore(kvStore kv.Store, prefix string, clock Clock) *KVStore {
+	return &KVStore{kv: kvStore, prefix: prefix, clock: clock}
+}
+
+// KVStore keeps the states in the shared key-value store. Expired states are removed by the key-value store itself.
+type KVStore struct {
+	kv     kv.Store
+	prefix string
+	clock  Clock
+}
+
+type kvEntry struct {
+	State     State     `json:"state"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Get returns the state of the key.
+func (s *KVStore) Get(ctx context.Context, key string) (State, error) {
+	state, _, err := s.get(ctx, key)
+	return state, err
+}
+
+// Update replaces the state of the key, retrying if it is updated concurrently by another instance.
+func (s *KVStore) Update(ctx context.Context, key string, fn func(State) (State, time.Time)) error {
+	for i := 0; i < maxSwapAttempts; i++ {
+		state, raw, err := s.get(ctx, key)
+		if err != nil {
+			return err
+		}
+		state, expiresAt := fn(state)
+		value, err := json.Marshal(kvEntry{State: state, ExpiresAt: expiresAt})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		// the expiration time is stored in the value too, because the clock of the limiter may be moved
+		// forward independently of the key-value store
+		swapped, err := s.kv.CompareAndSwap(ctx, s.prefix+key, raw, string(value), expiresAt.Sub(s.clock.Now()))
+		if err != nil {
+			return err
+		}
+		if swapped {
+			return nil
+		}
+	}
+	return errors.Errorf("state of %q is updated concurrently too often", key)
+}
+
+func (s *KVStore) get(ctx context.Context, key string) (State, string, error) {
+	raw, ok, err := s.kv.Get(ctx, s.prefix+key)
+	if err != nil || !ok {
+		return State{}, "", err
+	}
+	var entry kvEntry
+	if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+		return State{}, "", errors.WithStack(err)
+	}
+	if !s.clock.Now().Before(entry.ExpiresAt) {
+		return State{}, raw, nil
+	}
+	return entry.State, raw, nil
+}
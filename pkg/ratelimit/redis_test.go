@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type mockKV struct {
+	mu     sync.Mutex
+	values map[string]string
+	// conflicts is the number of the next swaps failing as if another instance updated the value
+	conflicts int
+}
+
+func (m *mockKV) Get(ctx context.Context, key string) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	value, ok := m.values[key]
+	return value, ok, nil
+}
+
+func (m *mockKV) CompareAndSwap(ctx context.Context, key, expected, value string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.conflicts > 0 {
+		m.conflicts--
+		return false, nil
+	}
+	if m.values[key] != expected {
+		return false, nil
+	}
+	m.values[key] = value
+	return true, nil
+}
+
+func TestKVStore(t *testing.T) {
+	assertT := assert.New(t)
+	requireT := require.New(t)
+
+	ctx := context.Background()
+	clk := clock.NewManual(start)
+	kv := &mockKV{values: map[string]string{}}
+	rule := Rule{Limit: 2, Period: time.Hour}
+
+	// limiters of two instances share the state
+	l1 := NewFixedWindow(rule, NewKVStore(kv, "ip:", clk), clk)
+	l2 := NewFixedWindow(rule, NewKVStore(kv, "ip:", clk), clk)
+
+	res, err := l1.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.True(res.Allowed)
+	kv.conflicts = 1
+	res, err = l2.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.True(res.Allowed)
+	res, err = l1.Allow(ctx, "a")
+	requireT.NoError(err)
+	assertT.False(res.Allowed)
+	assertT.Contains(kv.values, "ip:a")
+
+	// expired state is ignored
+	clk.Advance(time.Hour)
+	res, err = l2.Peek(ctx, "a")
+	requireT.NoError(err)
+	assertT.EqualValues(2, res.Remaining)
+
+	kv.conflicts = maxSwapAttempts
+	_, err = l1.Allow(ctx, "a")
+	requireT.Error(err)
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// State is the state of the limiter for a single key. Each algorithm uses its own subset of the fields.
+type State struct {
+	// WindowStart is the start of the current window of the window algorithms.
+	WindowStart time.Time
+	// Count is the number of events in the current window.
+	Count uint64
+	// PreviousCount is the number of events in the previous window, used by the sliding window.
+	PreviousCount uint64
+	// TAT is the theoretical arrival time of the next event, used by GCRA.
+	TAT time.Time
+	// Tokens is the number of tokens left in the bucket when it was refilled last time, used by the token bucket.
+	Tokens float64
+	// RefilledAt is the time the tokens were refilled last time, used by the token bucket.
+	RefilledAt time.Time
+}
+
+// Store keeps the states of the limiters. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the state of the key, zero state if the key is not known or its state expired.
+	Get(ctx context.Context, key string) (State, error)
+	// Update atomically replaces the state of the key by the one returned by fn. The state expires at expiresAt.
+	Update(ctx context.Context, key string, fn func(State) (State, time.Time)) error
+}
+
+// NewMemoryStore returns the store keeping the states in memory of the process.
+func NewMemoryStore(clock Clock) *MemoryStore {
+	return &MemoryStore{
+		clock:  clock,
+		states: map[string]memoryEntry{},
+	}
+}
+
+type memoryEntry struct {
+	state     State
+	expiresAt time.Time
+}
+
+// MemoryStore keeps the states in memory of the process.
+type MemoryStore struct {
+	clock Clock
+
+	mu     sync.Mutex
+	states map[string]memoryEntry
+}
+
+// Get returns the state of the key.
+func (s *MemoryStore) Get(ctx context.Context, key string) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.get(key), nil
+}
+
+// Update replaces the state of the key.
+func (s *MemoryStore) Update(ctx context.Context, key string, fn func(State) (State, time.Time)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, expiresAt := fn(s.get(key))
+	s.states[key] = memoryEntry{state: state, expiresAt: expiresAt}
+	return nil
+}
+
+// Len returns the number of stored states, including the expired ones not pruned yet.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.states)
+}
+
+func (s *MemoryStore) get(key string) State {
+	entry, ok := s.states[key]
+	if !ok || !s.clock.Now().Before(entry.expiresAt) {
+		return State{}
+	}
+	return entry.state
+}
+
+// Prune deletes the expired states.
+func (s *MemoryStore) Prune() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	for key, entry := range s.states {
+		if !now.Before(entry.expiresAt) {
+			delete(s.states, key)
+		}
+	}
+}
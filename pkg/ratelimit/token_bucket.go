@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// NewTokenBucket returns the limiter refilling the bucket of each key by the limit of tokens per period, events are
+// allowed while there are tokens in the bucket holding the burst of them at most.
+func NewTokenBucket(rule Rule, store Store, clock Clock) *TokenBucket {
+	burst := rule.Burst
+	if burst == 0 {
+		burst = rule.Limit
+	}
+	return &TokenBucket{
+		interval: rule.Period / time.Duration(rule.Limit),
+		capacity: float64(burst),
+		store:    store,
+		clock:    clock,
+	}
+}
+
+// TokenBucket implements the token bucket algorithm. Each event takes one token from the bucket, tokens are added
+// back at the constant rate until the bucket is full, so bursts are tolerated while the sustained rate is limited.
+type TokenBucket struct {
+	interval time.Duration
+	capacity float64
+	store    Store
+	clock    Clock
+}
+
+// Allow consumes the event of the key if it is allowed.
+func (l *TokenBucket) Allow(ctx context.Context, key string) (Result, error) {
+	var result Result
+	err := l.store.Update(ctx, key, func(state State) (State, time.Time) {
+		now := l.clock.Now()
+		tokens := l.tokens(state, now)
+		result = l.result(tokens, now)
+		if result.Allowed {
+			tokens--
+			result.Remaining--
+		}
+		state.Tokens = tokens
+		state.RefilledAt = now
+		// once the bucket is full again the state is equal to the initial one
+		return state, now.Add(time.Duration((l.capacity - tokens) * float64(l.interval)))
+	})
+	return result, err
+}
+
+// Peek returns the result the event of the key would get.
+func (l *TokenBucket) Peek(ctx context.Context, key string) (Result, error) {
+	state, err := l.store.Get(ctx, key)
+	if err != nil {
+		return Result{}, err
+	}
+	now := l.clock.Now()
+	return l.result(l.tokens(state, now), now), nil
+}
+
+func (l *TokenBucket) tokens(state State, now time.Time) float64 {
+	if state.RefilledAt.IsZero() {
+		return l.capacity
+	}
+	return math.Min(l.capacity, state.Tokens+float64(now.Sub(state.RefilledAt))/float64(l.interval))
+}
+
+func (l *TokenBucket) result(tokens float64, now time.Time) Result {
+	if tokens < 1 {
+		return Result{RetryAt: now.Add(time.Duration(math.Ceil((1 - tokens) * float64(l.interval))))}
+	}
+	return Result{Allowed: true, Remaining: uint64(tokens), RetryAt: now}
+}
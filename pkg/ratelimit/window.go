@@ -0,0 +1,134 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// NewFixedWindow returns the limiter counting the events in fixed windows aligned to the period.
+func NewFixedWindow(rule Rule, store Store, clock Clock) *FixedWindow {
+	return &FixedWindow{rule: rule, store: store, clock: clock}
+}
+
+// FixedWindow allows the limit of events in each window aligned to the period.
+type FixedWindow struct {
+	rule  Rule
+	store Store
+	clock Clock
+}
+
+// Allow consumes the event of the key if it is allowed.
+func (l *FixedWindow) Allow(ctx context.Context, key string) (Result, error) {
+	var result Result
+	err := l.store.Update(ctx, key, func(state State) (State, time.Time) {
+		now := l.clock.Now()
+		state = l.current(state, now)
+		result = l.result(state, now)
+		if result.Allowed {
+			state.Count++
+			result.Remaining--
+		}
+		return state, state.WindowStart.Add(l.rule.Period)
+	})
+	return result, err
+}
+
+// Peek returns the result the event of the key would get.
+func (l *FixedWindow) Peek(ctx context.Context, key string) (Result, error) {
+	state, err := l.store.Get(ctx, key)
+	if err != nil {
+		return Result{}, err
+	}
+	now := l.clock.Now()
+	return l.result(l.current(state, now), now), nil
+}
+
+func (l *FixedWindow) current(state State, now time.Time) State {
+	if start := now.Truncate(l.rule.Period); !state.WindowStart.Equal(start) {
+		return State{WindowStart: start}
+	}
+	return state
+}
+
+func (l *FixedWindow) result(state State, now time.Time) Result {
+	if state.Count >= l.rule.Limit {
+		return Result{RetryAt: state.WindowStart.Add(l.rule.Period)}
+	}
+	return Result{Allowed: true, Remaining: l.rule.Limit - state.Count, RetryAt: now}
+}
+
+// NewSlidingWindow returns the limiter approximating the count of events in the sliding period by weighting
+// the count of the previous window by its overlap with the period.
+func NewSlidingWindow(rule Rule, store Store, clock Clock) *SlidingWindow {
+	return &SlidingWindow{rule: rule, store: store, clock: clock}
+}
+
+// SlidingWindow allows the limit of events in any period, approximately.
+type SlidingWindow struct {
+	rule  Rule
+	store Store
+	clock Clock
+}
+
+// Allow consumes the event of the key if it is allowed.
+func (l *SlidingWindow) Allow(ctx context.Context, key string) (Result, error) {
+	var result Result
+	err := l.store.Update(ctx, key, func(state State) (State, time.Time) {
+		now := l.clock.Now()
+		state = l.current(state, now)
+		result = l.result(state, now)
+		if result.Allowed {
+			state.Count++
+			result.Remaining--
+		}
+		// the count is needed as the previous one during the next window
+		return state, state.WindowStart.Add(2 * l.rule.Period)
+	})
+	return result, err
+}
+
+// Peek returns the result the event of the key would get.
+func (l *SlidingWindow) Peek(ctx context.Context, key string) (Result, error) {
+	state, err := l.store.Get(ctx, key)
+	if err != nil {
+		return Result{}, err
+	}
+	now := l.clock.Now()
+	return l.result(l.current(state, now), now), nil
+}
+
+func (l *SlidingWindow) current(state State, now time.Time) State {
+	start := now.Truncate(l.rule.Period)
+	switch {
+	case state.WindowStart.Equal(start):
+		return state
+	case state.WindowStart.Add(l.rule.Period).Equal(start):
+		return State{WindowStart: start, PreviousCount: state.Count}
+	default:
+		return State{WindowStart: start}
+	}
+}
+
+func (l *SlidingWindow) result(state State, now time.Time) Result {
+	elapsed := now.Sub(state.WindowStart)
+	weight := float64(l.rule.Period-elapsed) / float64(l.rule.Period)
+	estimate := uint64(float64(state.PreviousCount)*weight) + state.Count
+	if estimate < l.rule.Limit {
+		return Result{Allowed: true, Remaining: l.rule.Limit - estimate, RetryAt: now}
+	}
+
+	// the event is allowed once the weighted previous count decays enough
+	if state.Count < l.rule.Limit {
+		return Result{RetryAt: state.WindowStart.Add(decay(state.PreviousCount, l.rule.Limit-state.Count, l.rule.Period))}
+	}
+	// the current count becomes the previous one in the next window
+	return Result{RetryAt: state.WindowStart.Add(l.rule.Period + decay(state.Count, l.rule.Limit, l.rule.Period))}
+}
+
+// decay returns how long it takes the count weighted linearly over the period to drop below the capacity.
+func decay(count, capacity uint64, period time.Duration) time.Duration {
+	if count < capacity {
+		return 0
+	}
+	return period - time.Duration(float64(period)*float64(capacity)/float64(count)) + time.Nanosecond
+}
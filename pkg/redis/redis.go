@@ -0,0 +1,88 @@
+// Package redis implements the key-value store on Redis sharing the state of rate limits and cooldowns between
+// the replicas of the faucet.
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const (
+	dialTimeout    = 5 * time.Second
+	commandTimeout = 5 * time.Second
+	maxIdle        = 16
+)
+
+// compareAndSwapScript replaces the value of the key if it is still the expected one, empty value stands for
+// the missing key. Empty new value deletes the key.
+var compareAndSwapScript = goredis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if (current == false and ARGV[1] ~= '') or (current ~= false and current ~= ARGV[1]) then
+	return 0
+end
+if ARGV[2] == '' then
+	redis.call('DEL', KEYS[1])
+else
+	redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+end
+return 1
+`)
+
+// Client is the key-value store kept in Redis. It is safe for concurrent use.
+type Client struct {
+	client *goredis.Client
+}
+
+// NewClient returns the client of the server at the URL in the format redis://[[user]:password@]host:port[/db],
+// rediss scheme enables TLS. Connections are established lazily.
+func NewClient(rawURL string) (*Client, error) {
+	opts, err := goredis.ParseURL(rawURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid redis URL")
+	}
+	opts.DialTimeout = dialTimeout
+	opts.ReadTimeout = commandTimeout
+	opts.WriteTimeout = commandTimeout
+	opts.MaxIdleConns = maxIdle
+	return &Client{client: goredis.NewClient(opts)}, nil
+}
+
+// Ping verifies the server is reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	return errors.WithStack(c.client.Ping(ctx).Err())
+}
+
+// Get returns the value of the key and whether it exists.
+func (c *Client) Get(ctx context.Context, key string) (string, bool, error) {
+	value, err := c.client.Get(ctx, key).Result()
+	if errors.Is(err, goredis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, errors.WithStack(err)
+	}
+	return value, true, nil
+}
+
+// CompareAndSwap atomically replaces the value of the key by the one expiring after ttl if the current value
+// is the expected one. Empty expected value means the key doesn't exist, empty value deletes the key. It returns
+// false if the value was changed concurrently.
+func (c *Client) CompareAndSwap(ctx context.Context, key, expected, value string, ttl time.Duration) (bool, error) {
+	ttlMillis := ttl.Milliseconds()
+	if ttlMillis < 1 {
+		ttlMillis = 1
+	}
+	swapped, err := compareAndSwapScript.Run(ctx, c.client, []string{key}, expected, value, ttlMillis).Int64()
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return swapped == 1, nil
+}
+
+// Close closes the connections.
+func (c *Client) Close() error {
+	return errors.WithStack(c.client.Close())
+}
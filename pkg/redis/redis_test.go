@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient(t *testing.T) {
+	requireT := require.New(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	server := miniredis.RunT(t)
+	server.RequireUserAuth("faucet", "secret")
+	client, err := NewClient("redis://faucet:secret@" + server.Addr())
+	requireT.NoError(err)
+	defer client.Close()
+
+	requireT.NoError(client.Ping(ctx))
+
+	_, ok, err := client.Get(ctx, "key")
+	requireT.NoError(err)
+	requireT.False(ok)
+
+	swapped, err := client.CompareAndSwap(ctx, "key", "", "value with\r\nnewline", time.Minute)
+	requireT.NoError(err)
+	requireT.True(swapped)
+	value, ok, err := client.Get(ctx, "key")
+	requireT.NoError(err)
+	requireT.True(ok)
+	requireT.Equal("value with\r\nnewline", value)
+	requireT.Equal(time.Minute, server.TTL("key"))
+
+	// the value was changed in the meantime
+	swapped, err = client.CompareAndSwap(ctx, "key", "", "other", time.Minute)
+	requireT.NoError(err)
+	requireT.False(swapped)
+
+	swapped, err = client.CompareAndSwap(ctx, "key", "value with\r\nnewline", "", 0)
+	requireT.NoError(err)
+	requireT.True(swapped)
+	_, ok, err = client.Get(ctx, "key")
+	requireT.NoError(err)
+	requireT.False(ok)
+
+	// the value expires
+	swapped, err = client.CompareAndSwap(ctx, "key", "", "value", time.Second)
+	requireT.NoError(err)
+	requireT.True(swapped)
+	server.FastForward(time.Second)
+	_, ok, err = client.Get(ctx, "key")
+	requireT.NoError(err)
+	requireT.False(ok)
+
+	client, err = NewClient("redis://faucet:wrong@" + server.Addr())
+	requireT.NoError(err)
+	defer client.Close()
+	requireT.Error(client.Ping(ctx))
+}
+
+func TestNewClient(t *testing.T) {
+	requireT := require.New(t)
+
+	client, err := NewClient("rediss://redis.internal/2")
+	requireT.NoError(err)
+	opts := client.client.Options()
+	requireT.Equal("redis.internal:6379", opts.Addr)
+	requireT.Equal(2, opts.DB)
+	requireT.NotNil(opts.TLSConfig)
+
+	_, err = NewClient("http://redis.internal")
+	requireT.Error(err)
+	_, err = NewClient("redis://redis.internal/db")
+	requireT.Error(err)
+}
@@ -0,0 +1,58 @@
+// Package secret wraps the secrets handed out to the clients, e.g. the mnemonics of the generated accounts, so they
+// never leak to logs, traces, error messages or webhooks by accident. The secret is redacted whenever it is
+// formatted or marshaled, its value is revealed only explicitly, when the response is written.
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Redacted replaces the value of the secret in all the representations.
+const Redacted = "[REDACTED]"
+
+// String is the secret string.
+type String struct {
+	value string
+}
+
+// New wraps the value.
+func New(value string) String {
+	return String{value: value}
+}
+
+// Reveal returns the value. It must be called only to hand the secret out to its owner, e.g. in the response.
+func (s String) Reveal() string {
+	return s.value
+}
+
+// IsEmpty tells if there is no secret.
+func (s String) IsEmpty() bool {
+	return s.value == ""
+}
+
+// String returns the redacted placeholder.
+func (s String) String() string {
+	return Redacted
+}
+
+// GoString returns the redacted placeholder, so %#v doesn't print the value.
+func (s String) GoString() string {
+	return Redacted
+}
+
+// Format writes the redacted placeholder for all the verbs, including %x and %q.
+func (s String) Format(f fmt.State, _ rune) {
+	_, _ = io.WriteString(f, Redacted)
+}
+
+// MarshalJSON returns the redacted placeholder, so the secret isn't written by JSON loggers and webhooks.
+func (s String) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+// MarshalText returns the redacted placeholder.
+func (s String) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
@@ -0,0 +1,72 @@
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const value = "abandon ability able about above absent absorb abstract absurd abuse access accident"
+
+func TestRedacted(t *testing.T) {
+	requireT := require.New(t)
+
+	s := New(value)
+	requireT.Equal(value, s.Reveal())
+	requireT.False(s.IsEmpty())
+	requireT.True(String{}.IsEmpty())
+
+	wrapper := struct {
+		Address  string
+		Mnemonic String
+	}{Address: "devcore1abc", Mnemonic: s}
+	for _, format := range []string{"%s", "%v", "%+v", "%#v", "%q", "%x", "%X", "%d"} {
+		requireT.NotContains(fmt.Sprintf(format, s), "abandon", format)
+		requireT.NotContains(fmt.Sprintf(format, wrapper), "abandon", format)
+		requireT.NotContains(fmt.Sprintf(format, &wrapper), "abandon", format)
+	}
+	requireT.NotContains(errors.Errorf("signing failed for %v", s).Error(), "abandon")
+	requireT.NotContains(fmt.Sprintf("%+v", errors.Wrapf(errors.New("boom"), "mnemonic %s", s)), "abandon")
+
+	encoded, err := json.Marshal(wrapper)
+	requireT.NoError(err)
+	requireT.JSONEq(`{"Address":"devcore1abc","Mnemonic":"[REDACTED]"}`, string(encoded))
+	text, err := s.MarshalText()
+	requireT.NoError(err)
+	requireT.Equal(Redacted, string(text))
+}
+
+func TestRedactedInLogs(t *testing.T) {
+	requireT := require.New(t)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := zap.New(core)
+	s := New(value)
+	log.Info("generated", zap.Stringer("mnemonic", s), zap.Any("secret", s), zap.Any("result", struct {
+		Mnemonic String
+	}{Mnemonic: s}), zap.Reflect("reflected", s))
+
+	for _, entry := range logs.All() {
+		for _, field := range entry.Context {
+			enc := zapcore.NewMapObjectEncoder()
+			field.AddTo(enc)
+			requireT.NotContains(fmt.Sprintf("%v", enc.Fields), "abandon", field.Key)
+		}
+	}
+
+	// the JSON encoder used in production
+	encoder := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	buf, err := encoder.EncodeEntry(zapcore.Entry{Message: "generated"}, []zap.Field{
+		zap.Any("secret", s),
+		zap.Reflect("result", struct{ Mnemonic String }{Mnemonic: s}),
+	})
+	requireT.NoError(err)
+	requireT.NotContains(buf.String(), "abandon")
+	requireT.Contains(buf.String(), Redacted)
+}
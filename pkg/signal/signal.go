@@ -24,3 +24,28 @@ func TerminateSignal(ctx context.Context) context.Context {
 	}()
 	return ctx
 }
+
+// Hangup returns the channel receiving once SIGHUP is received by the application, so the configuration can be
+// reloaded without restart. The channel is never closed.
+func Hangup(ctx context.Context) <-chan struct{} {
+	log := logger.Get(ctx)
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	hangup := make(chan struct{}, 1)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				signal.Stop(sigChan)
+				return
+			case s := <-sigChan:
+				log.Info("Received signal", zap.Stringer("signal", s))
+				select {
+				case hangup <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return hangup
+}
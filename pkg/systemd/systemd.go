@@ -0,0 +1,80 @@
+// Package systemd takes over the sockets passed by systemd socket activation. The sockets are owned by systemd,
+// so connections are queued by the kernel while the faucet restarts instead of being refused, and the address
+// and permissions of unix sockets are managed by the socket unit.
+package systemd
+
+import (
+	"net"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd, see sd_listen_fds(3).
+const listenFDsStart = 3
+
+// Environment variables set by systemd for the activated process.
+const (
+	envListenPID     = "LISTEN_PID"
+	envListenFDs     = "LISTEN_FDS"
+	envListenFDNames = "LISTEN_FDNAMES"
+)
+
+var (
+	once      sync.Once
+	errLoad   error
+	mu        sync.Mutex
+	listeners []namedListener
+)
+
+type namedListener struct {
+	name     string
+	listener net.Listener
+	taken    bool
+}
+
+// Listener returns the socket passed by systemd under the name set by FileDescriptorName= of the socket unit.
+// If the name is empty, exactly one socket must be passed. Each socket is returned once.
+func Listener(name string) (net.Listener, error) {
+	once.Do(func() {
+		listeners, errLoad = load(os.Getenv, listenFDsStart)
+		// the variables are not inherited by the child processes
+		for _, env := range []string{envListenPID, envListenFDs, envListenFDNames} {
+			_ = os.Unsetenv(env)
+		}
+	})
+	if errLoad != nil {
+		return nil, errLoad
+	}
+	return take(listeners, name)
+}
+
+func take(listeners []namedListener, name string) (net.Listener, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(listeners) == 0 {
+		return nil, errors.New("no sockets passed by systemd, is the faucet started by the socket unit?")
+	}
+	if name == "" {
+		if len(listeners) > 1 {
+			return nil, errors.Errorf("%d sockets passed by systemd, name of the socket is required", len(listeners))
+		}
+		return takeListener(&listeners[0])
+	}
+	for i := range listeners {
+		if listeners[i].name == name {
+			return takeListener(&listeners[i])
+		}
+	}
+	return nil, errors.Errorf("socket %q not passed by systemd", name)
+}
+
+func takeListener(l *namedListener) (net.Listener, error) {
+	if l.taken {
+		return nil, errors.Errorf("socket %q passed by systemd is used twice", l.name)
+	}
+	l.taken = true
+	return l.listener, nil
+}
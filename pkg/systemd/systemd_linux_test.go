@@ -0,0 +1,97 @@
+//go:build linux
+
+package systemd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// passSockets duplicates the descriptors of the listeners to consecutive descriptors, as systemd does,
+// and returns the first one.
+func passSockets(t *testing.T, listeners ...net.Listener) int {
+	requireT := require.New(t)
+
+	files := make([]*os.File, 0, len(listeners))
+	for _, l := range listeners {
+		var (
+			file *os.File
+			err  error
+		)
+		switch l := l.(type) {
+		case *net.TCPListener:
+			file, err = l.File()
+		case *net.UnixListener:
+			file, err = l.File()
+		}
+		requireT.NoError(err)
+		files = append(files, file)
+	}
+	// descriptors far above the ones used by the test binary are free
+	startFD := 200
+	for i, file := range files {
+		requireT.NoError(syscall.Dup3(int(file.Fd()), startFD+i, 0))
+		requireT.NoError(file.Close())
+	}
+	return startFD
+}
+
+func TestLoad(t *testing.T) {
+	requireT := require.New(t)
+
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	requireT.NoError(err)
+	t.Cleanup(func() { _ = tcp.Close() })
+	unix, err := net.Listen("unix", filepath.Join(t.TempDir(), "faucet.sock"))
+	requireT.NoError(err)
+	t.Cleanup(func() { _ = unix.Close() })
+	startFD := passSockets(t, tcp, unix)
+
+	env := map[string]string{
+		envListenPID:     strconv.Itoa(os.Getpid()),
+		envListenFDs:     "2",
+		envListenFDNames: "public:internal",
+	}
+	loaded, err := load(func(key string) string { return env[key] }, startFD)
+	requireT.NoError(err)
+	requireT.Len(loaded, 2)
+	t.Cleanup(func() {
+		for _, l := range loaded {
+			_ = l.listener.Close()
+		}
+	})
+	requireT.Equal("public", loaded[0].name)
+	requireT.Equal(tcp.Addr().String(), loaded[0].listener.Addr().String())
+	requireT.Equal("unix", loaded[1].listener.Addr().Network())
+
+	// name is required if many sockets are passed
+	_, err = take(loaded, "")
+	requireT.Error(err)
+	_, err = take(loaded, "metrics")
+	requireT.Error(err)
+	l, err := take(loaded, "internal")
+	requireT.NoError(err)
+	requireT.Equal(unix.Addr().String(), l.Addr().String())
+	// each socket is taken once
+	_, err = take(loaded, "internal")
+	requireT.Error(err)
+
+	// the variables of another process are ignored
+	env[envListenPID] = "1"
+	loaded, err = load(func(key string) string { return env[key] }, startFD)
+	requireT.NoError(err)
+	requireT.Empty(loaded)
+	_, err = take(loaded, "")
+	requireT.Error(err)
+
+	env[envListenPID] = strconv.Itoa(os.Getpid())
+	env[envListenFDs] = "two"
+	_, err = load(func(key string) string { return env[key] }, startFD)
+	requireT.Error(err)
+}
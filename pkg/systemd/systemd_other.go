@@ -0,0 +1,15 @@
+//go:build !unix
+
+package systemd
+
+import (
+	"github.com/pkg/errors"
+)
+
+// load fails if the process is socket activated, the activation is supported on unix only.
+func load(getenv func(string) string, _ int) ([]namedListener, error) {
+	if getenv(envListenPID) == "" {
+		return nil, nil
+	}
+	return nil, errors.New("systemd socket activation is supported on unix only")
+}
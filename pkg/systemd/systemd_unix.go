@@ -0,0 +1,52 @@
+//go:build unix
+
+package systemd
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// load returns the listeners of the sockets passed to the process, nil if it is not socket activated.
+func load(getenv func(string) string, startFD int) ([]namedListener, error) {
+	pid := getenv(envListenPID)
+	if pid == "" {
+		return nil, nil
+	}
+	if pid != strconv.Itoa(os.Getpid()) {
+		// the variables are meant for another process, e.g. the parent of the faucet
+		return nil, nil
+	}
+	count, err := strconv.Atoi(getenv(envListenFDs))
+	if err != nil || count < 0 {
+		return nil, errors.Errorf("invalid %s: %q", envListenFDs, getenv(envListenFDs))
+	}
+	var names []string
+	if n := getenv(envListenFDNames); n != "" {
+		names = strings.Split(n, ":")
+	}
+
+	result := make([]namedListener, 0, count)
+	for i := 0; i < count; i++ {
+		fd := startFD + i
+		syscall.CloseOnExec(fd)
+		name := "LISTEN_FD_" + strconv.Itoa(fd)
+		if i < len(names) {
+			name = names[i]
+		}
+		file := os.NewFile(uintptr(fd), name)
+		listener, err := net.FileListener(file)
+		// the listener holds its own copy of the descriptor
+		_ = file.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "socket %q passed by systemd is not a listening stream socket", name)
+		}
+		result = append(result, namedListener{name: name, listener: listener})
+	}
+	return result, nil
+}
@@ -0,0 +1,135 @@
+package report
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// Format is the format of the rendered report.
+type Format string
+
+// Supported formats.
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// ParseFormat parses the report format.
+func ParseFormat(format string) (Format, error) {
+	switch f := Format(format); f {
+	case FormatMarkdown, FormatHTML:
+		return f, nil
+	default:
+		return "", errors.Errorf("invalid report format %q, supported formats: markdown, html", format)
+	}
+}
+
+const timeLayout = "2006-01-02 15:04 MST"
+
+const markdownTemplate = `# {{ .Title }}
+
+Period: {{ .Summary.From.Format "` + timeLayout + `" }} – {{ .Summary.To.Format "` + timeLayout + `" }}
+
+## Grants
+
+- Grants: {{ .Summary.Grants }}
+- Unique addresses: {{ .Summary.UniqueAddresses }}
+- Total amount: {{ .Summary.TotalAmount }}
+- Daily burn: {{ .Summary.DailyBurn }}
+
+## Top consumers
+
+| Addresses | IPs | Requests | Amount | Periodic |
+|---|---|---|---|---|
+{{- range .Summary.TopConsumers }}
+| {{ len .Addresses }} | {{ len .IPs }} | {{ .Requests }} | {{ .TotalAmount }} | {{ .Periodic }} |
+{{- end }}
+
+## Incidents
+{{ range .Summary.Incidents }}
+- {{ .Time.Format "` + timeLayout + `" }} ` + "`{{ .Kind }}`" + ` {{ .Message }}
+{{- else }}
+No incidents.
+{{- end }}
+`
+
+const htmlTemplate = `<html><body>
+<h1>{{ .Title }}</h1>
+<p>Period: {{ .Summary.From.Format "` + timeLayout + `" }} – {{ .Summary.To.Format "` + timeLayout + `" }}</p>
+<h2>Grants</h2>
+<ul>
+<li>Grants: {{ .Summary.Grants }}</li>
+<li>Unique addresses: {{ .Summary.UniqueAddresses }}</li>
+<li>Total amount: {{ .Summary.TotalAmount }}</li>
+<li>Daily burn: {{ .Summary.DailyBurn }}</li>
+</ul>
+<h2>Top consumers</h2>
+<table>
+<tr><th>Addresses</th><th>IPs</th><th>Requests</th><th>Amount</th><th>Periodic</th></tr>
+{{- range .Summary.TopConsumers }}
+<tr><td>{{ len .Addresses }}</td><td>{{ len .IPs }}</td><td>{{ .Requests }}</td><td>{{ .TotalAmount }}</td><td>{{ .Periodic }}</td></tr>
+{{- end }}
+</table>
+<h2>Incidents</h2>
+<ul>
+{{- range .Summary.Incidents }}
+<li>{{ .Time.Format "` + timeLayout + `" }} <code>{{ .Kind }}</code> {{ .Message }}</li>
+{{- else }}
+<li>No incidents.</li>
+{{- end }}
+</ul>
+</body></html>
+`
+
+var (
+	markdownTmpl = template.Must(template.New("report").Parse(markdownTemplate))
+	htmlTmpl     = htmltemplate.Must(htmltemplate.New("report").Parse(htmlTemplate))
+)
+
+// NewRenderer returns new instance of Renderer.
+func NewRenderer(format Format, title string) Renderer {
+	return Renderer{
+		format: format,
+		title:  title,
+	}
+}
+
+// Renderer renders the summary in the configured format.
+type Renderer struct {
+	format Format
+	title  string
+}
+
+// Render renders the summary.
+func (r Renderer) Render(summary app.Summary) (Report, error) {
+	data := struct {
+		Title   string
+		Summary app.Summary
+	}{
+		Title:   r.title,
+		Summary: summary,
+	}
+
+	buf := &bytes.Buffer{}
+	var err error
+	switch r.format {
+	case FormatHTML:
+		err = htmlTmpl.Execute(buf, data)
+	default:
+		err = markdownTmpl.Execute(buf, data)
+	}
+	if err != nil {
+		return Report{}, errors.WithStack(err)
+	}
+
+	return Report{
+		Subject: r.title,
+		Format:  r.format,
+		Body:    buf.String(),
+	}, nil
+}
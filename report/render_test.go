@@ -0,0 +1,42 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+func TestRender(t *testing.T) {
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	summary := app.Summary{
+		From:            from,
+		To:              from.Add(7 * 24 * time.Hour),
+		Grants:          3,
+		UniqueAddresses: 2,
+		TotalAmount:     chain.NewCoins(chain.NewCoin("ucore", chain.NewInt(30))),
+		DailyBurn:       chain.NewCoins(chain.NewCoin("ucore", chain.NewInt(4))),
+		TopConsumers: []app.AddressCluster{
+			{Addresses: []string{"addr1"}, IPs: []string{"1.1.1.1"}, Requests: 2, TotalAmount: chain.NewCoins(chain.NewCoin("ucore", chain.NewInt(20)))},
+		},
+		Incidents: []app.Incident{
+			{Kind: app.IncidentKindTransferFailed, Message: "<insufficient funds>", Time: from},
+		},
+	}
+
+	report, err := NewRenderer(FormatMarkdown, "Faucet").Render(summary)
+	require.NoError(t, err)
+	assert.Equal(t, "Faucet", report.Subject)
+	assert.Contains(t, report.Body, "Total amount: 30ucore")
+	assert.Contains(t, report.Body, "| 1 | 1 | 2 | 20ucore | false |")
+	assert.Contains(t, report.Body, "`transfer_failed` <insufficient funds>")
+
+	report, err = NewRenderer(FormatHTML, "Faucet").Render(summary)
+	require.NoError(t, err)
+	assert.Contains(t, report.Body, "<li>Daily burn: 4ucore</li>")
+	assert.Contains(t, report.Body, "&lt;insufficient funds&gt;")
+}
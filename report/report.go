@@ -0,0 +1,68 @@
+package report
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// Summarizer provides the summary of the faucet activity.
+type Summarizer interface {
+	Summary(ctx context.Context, since time.Time) (app.Summary, error)
+}
+
+// Sender delivers the report to the stakeholders.
+type Sender interface {
+	Send(ctx context.Context, report Report) error
+}
+
+// Report is the rendered summary.
+type Report struct {
+	Subject string
+	Format  Format
+	Body    string
+}
+
+// NewJob returns new instance of Job.
+func NewJob(summarizer Summarizer, interval time.Duration, renderer Renderer, senders ...Sender) *Job {
+	return &Job{
+		summarizer: summarizer,
+		interval:   interval,
+		renderer:   renderer,
+		senders:    senders,
+	}
+}
+
+// Job renders the summary of the faucet activity over the last interval and sends it.
+type Job struct {
+	summarizer Summarizer
+	interval   time.Duration
+	renderer   Renderer
+	senders    []Sender
+}
+
+// Report renders the summary of the faucet activity over the last interval and sends it. It is run every interval
+// by the scheduler.
+func (j *Job) Report(ctx context.Context) error {
+	summary, err := j.summarizer.Summary(ctx, time.Now().UTC().Add(-j.interval))
+	if err != nil {
+		return err
+	}
+	report, err := j.renderer.Render(summary)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, sender := range j.senders {
+		if err := sender.Send(ctx, report); err != nil {
+			logger.Get(ctx).Error("Sending summary report failed", zap.Error(err))
+			lastErr = err
+		}
+	}
+	return lastErr
+}
@@ -0,0 +1,159 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	nethttp "net/http"
+	"net/smtp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DialFunc opens connection to the address, e.g. through the outbound proxy.
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// NewSMTPSender returns sender delivering reports by email. Authentication is skipped if username is empty.
+func NewSMTPSender(address, username, password, from string, to []string, dial DialFunc) SMTPSender {
+	host := address
+	if i := strings.LastIndex(address, ":"); i >= 0 {
+		host = address[:i]
+	}
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return SMTPSender{
+		address: address,
+		host:    host,
+		auth:    auth,
+		from:    from,
+		to:      to,
+		dial:    dial,
+	}
+}
+
+// SMTPSender delivers reports by email.
+type SMTPSender struct {
+	address string
+	host    string
+	auth    smtp.Auth
+	from    string
+	to      []string
+	dial    DialFunc
+}
+
+// Send sends the report.
+func (s SMTPSender) Send(ctx context.Context, report Report) error {
+	contentType := "text/plain; charset=UTF-8"
+	if report.Format == FormatHTML {
+		contentType = "text/html; charset=UTF-8"
+	}
+
+	msg := &bytes.Buffer{}
+	fmt.Fprintf(msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(msg, "To: %s\r\n", strings.Join(s.to, ", "))
+	fmt.Fprintf(msg, "Subject: %s\r\n", report.Subject)
+	fmt.Fprintf(msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(msg, "Content-Type: %s\r\n\r\n", contentType)
+	msg.WriteString(report.Body)
+
+	return errors.Wrap(s.sendMail(ctx, msg.Bytes()), "sending email failed")
+}
+
+// sendMail does the same as smtp.SendMail, but over the connection opened by the dial function.
+func (s SMTPSender) sendMail(ctx context.Context, msg []byte) error {
+	conn, err := s.dial(ctx, "tcp", s.address)
+	if err != nil {
+		return err
+	}
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		_ = conn.Close()
+		return errors.WithStack(err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if s.auth != nil {
+		if err := client.Auth(s.auth); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if err := client.Mail(s.from); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, to := range s.to {
+		if err := client.Rcpt(to); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := w.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(client.Quit())
+}
+
+// NewWebhookSender returns sender posting reports to the webhook using the client.
+func NewWebhookSender(url string, client *nethttp.Client) WebhookSender {
+	return WebhookSender{
+		url:    url,
+		client: client,
+	}
+}
+
+// WebhookSender posts reports to the webhook. The body is placed in the `text` field,
+// so the report may be posted directly to chat hooks like Slack or Mattermost.
+type WebhookSender struct {
+	url    string
+	client *nethttp.Client
+}
+
+// Send sends the report.
+func (s WebhookSender) Send(ctx context.Context, report Report) error {
+	payload, err := json.Marshal(struct {
+		Subject string `json:"subject"`
+		Format  Format `json:"format"`
+		Text    string `json:"text"`
+	}{
+		Subject: report.Subject,
+		Format:  report.Format,
+		Text:    report.Body,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "posting report to webhook failed")
+	}
+	defer res.Body.Close()
+	if res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return errors.Errorf("webhook returned non 2xx response, status: %d, body: %s", res.StatusCode, body)
+	}
+	return nil
+}
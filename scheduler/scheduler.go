@@ -0,0 +1,281 @@
+// Package scheduler runs the periodic background jobs of the faucet and records the outcome of each run, so a job
+// failing repeatedly is visible through the admin API instead of failing silently in the logs. The state of the jobs
+// is persisted, so the jobs keep their schedule across restarts.
+package scheduler
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/coreum-tools/pkg/parallel"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+// historySize is the number of the most recent runs kept for each job.
+const historySize = 20
+
+// ErrJobNotFound is returned if the job is not scheduled.
+var ErrJobNotFound = errors.New("job not found")
+
+// Job is the work run every interval.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Run is the outcome of a single run of the job.
+type Run struct {
+	StartedAt time.Time     `json:"startedAt"`
+	Duration  time.Duration `json:"duration"`
+	// Error is empty if the run succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+// State is the persisted state of the job.
+type State struct {
+	Name          string    `json:"name"`
+	Runs          uint64    `json:"runs"`
+	Failures      uint64    `json:"failures"`
+	LastRunAt     time.Time `json:"lastRunAt"`
+	LastSuccessAt time.Time `json:"lastSuccessAt"`
+	LastError     string    `json:"lastError"`
+	LastErrorAt   time.Time `json:"lastErrorAt"`
+	// History are the most recent runs, the latest first.
+	History []Run `json:"history"`
+}
+
+// Store persists the states of the jobs.
+type Store interface {
+	PutJobState(ctx context.Context, state State) error
+	// JobStates returns the states of all the jobs ever run.
+	JobStates(ctx context.Context) ([]State, error)
+}
+
+// Status describes the job.
+type Status struct {
+	State
+	Interval  time.Duration
+	NextRunAt time.Time
+	Running   bool
+}
+
+// New returns the scheduler persisting the states of the jobs in the store.
+func New(store Store, clock clock.Clock) *Scheduler {
+	return &Scheduler{
+		store:  store,
+		clock:  clock,
+		jobs:   map[string]*job{},
+		states: map[string]State{},
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "faucet_job_runs_total",
+			Help: "Number of runs of the background jobs, by job and outcome",
+		}, []string{"job", "outcome"}),
+		lastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "faucet_job_last_success_timestamp_seconds",
+			Help: "Unix time of the last successful run of the background job",
+		}, []string{"job"}),
+	}
+}
+
+// Scheduler runs the jobs every their interval, or at once when triggered. Jobs run independently, a slow job
+// doesn't delay the others. The next run is scheduled an interval after the previous one started, a job never
+// run before runs at once.
+type Scheduler struct {
+	store Store
+	clock clock.Clock
+
+	runs        *prometheus.CounterVec
+	lastSuccess *prometheus.GaugeVec
+
+	mu     sync.Mutex
+	jobs   map[string]*job
+	states map[string]State
+}
+
+type job struct {
+	Job
+	trigger chan struct{}
+	running bool
+}
+
+// Add schedules the job, it must be called before Run.
+func (s *Scheduler) Add(j Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[j.Name] = &job{Job: j, trigger: make(chan struct{}, 1)}
+}
+
+// Collectors returns the metrics of the jobs.
+func (s *Scheduler) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{s.runs, s.lastSuccess}
+}
+
+// Jobs returns the status of the scheduled jobs ordered by name.
+func (s *Scheduler) Jobs() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]Status, 0, len(s.jobs))
+	for name, j := range s.jobs {
+		state := s.states[name]
+		state.Name = name
+		statuses = append(statuses, Status{
+			State:     state,
+			Interval:  j.Interval,
+			NextRunAt: s.nextRunAt(j),
+			Running:   j.running,
+		})
+	}
+	sort.Slice(statuses, func(i, k int) bool {
+		return statuses[i].Name < statuses[k].Name
+	})
+	return statuses
+}
+
+// Trigger runs the job as soon as possible, without waiting for its next run. If the job is running, it runs
+// once more after the current run.
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	j, ok := s.jobs[name]
+	s.mu.Unlock()
+	if !ok {
+		return errors.Wrapf(ErrJobNotFound, "job: %s", name)
+	}
+	select {
+	case j.trigger <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run loads the persisted states of the jobs and runs them until the context is canceled.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if err := s.load(ctx); err != nil {
+		return err
+	}
+	return parallel.Run(ctx, func(ctx context.Context, spawn parallel.SpawnFn) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if len(s.jobs) == 0 {
+			// nothing to run, but the scheduler exits only once the context is canceled
+			spawn("idle", parallel.Fail, func(ctx context.Context) error {
+				<-ctx.Done()
+				return errors.WithStack(ctx.Err())
+			})
+		}
+		for _, j := range s.jobs {
+			j := j
+			spawn("job-"+j.Name, parallel.Fail, func(ctx context.Context) error {
+				return s.loop(ctx, j)
+			})
+		}
+		return nil
+	})
+}
+
+func (s *Scheduler) load(ctx context.Context) error {
+	states, err := s.store.JobStates(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, state := range states {
+		s.states[state.Name] = state
+	}
+	return nil
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) error {
+	for {
+		s.mu.Lock()
+		wait := s.nextRunAt(j).Sub(s.clock.Now())
+		s.mu.Unlock()
+		if wait > 0 {
+			select {
+			case <-ctx.Done():
+				return errors.WithStack(ctx.Err())
+			case <-time.After(wait):
+			case <-j.trigger:
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return errors.WithStack(err)
+		}
+		s.run(ctx, j)
+	}
+}
+
+// nextRunAt returns the time the job is due, the caller must hold the mutex.
+func (s *Scheduler) nextRunAt(j *job) time.Time {
+	lastRunAt := s.states[j.Name].LastRunAt
+	if lastRunAt.IsZero() {
+		return lastRunAt
+	}
+	return lastRunAt.Add(j.Interval)
+}
+
+// run runs the job once and records the outcome. Panics of the job are recovered and recorded as failures,
+// so they don't take the faucet down.
+func (s *Scheduler) run(ctx context.Context, j *job) {
+	log := logger.Get(ctx).With(zap.String("job", j.Name))
+	startedAt := s.clock.Now().UTC()
+	s.mu.Lock()
+	j.running = true
+	s.mu.Unlock()
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = errors.Errorf("panic: %v", r)
+			}
+		}()
+		return j.Run(ctx)
+	}()
+
+	run := Run{StartedAt: startedAt, Duration: s.clock.Now().Sub(startedAt)}
+	s.mu.Lock()
+	j.running = false
+	if ctx.Err() != nil {
+		// the run interrupted by the shutdown is not recorded, so the job runs again on start
+		s.mu.Unlock()
+		return
+	}
+	state := s.states[j.Name]
+	state.Name = j.Name
+	state.Runs++
+	state.LastRunAt = startedAt
+	if err != nil {
+		run.Error = err.Error()
+		state.Failures++
+		state.LastError = run.Error
+		state.LastErrorAt = startedAt
+	} else {
+		state.LastSuccessAt = startedAt
+	}
+	state.History = append([]Run{run}, state.History...)
+	if len(state.History) > historySize {
+		state.History = state.History[:historySize]
+	}
+	s.states[j.Name] = state
+	s.mu.Unlock()
+
+	if err != nil {
+		s.runs.WithLabelValues(j.Name, "failure").Inc()
+		log.Error("Job failed", zap.Error(err))
+	} else {
+		s.runs.WithLabelValues(j.Name, "success").Inc()
+		s.lastSuccess.WithLabelValues(j.Name).Set(float64(startedAt.Unix()))
+	}
+	if err := s.store.PutJobState(ctx, state); err != nil {
+		log.Error("Storing job state failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,136 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/pkg/clock"
+)
+
+type memoryStore struct {
+	mu     sync.Mutex
+	states map[string]State
+}
+
+func (m *memoryStore) PutJobState(_ context.Context, state State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.states == nil {
+		m.states = map[string]State{}
+	}
+	m.states[state.Name] = state
+	return nil
+}
+
+func (m *memoryStore) JobStates(_ context.Context) ([]State, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	states := []State{}
+	for _, state := range m.states {
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+func TestSchedulerRun(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clock.NewManual(now)
+	store := &memoryStore{}
+	s := New(store, clk)
+
+	fail := true
+	s.Add(Job{Name: "gc", Interval: time.Hour, Run: func(ctx context.Context) error {
+		if fail {
+			return errors.New("store is locked")
+		}
+		return nil
+	}})
+	s.Add(Job{Name: "report", Interval: time.Minute, Run: func(ctx context.Context) error {
+		panic("nil summary")
+	}})
+	requireT.ErrorIs(s.Trigger("missing"), ErrJobNotFound)
+
+	// jobs never run before are due at once
+	jobs := s.Jobs()
+	requireT.Len(jobs, 2)
+	requireT.Equal("gc", jobs[0].Name)
+	requireT.True(jobs[0].NextRunAt.IsZero())
+
+	s.run(ctx, s.jobs["gc"])
+	s.run(ctx, s.jobs["report"])
+	jobs = s.Jobs()
+	requireT.Equal(uint64(1), jobs[0].Failures)
+	requireT.Equal("store is locked", jobs[0].LastError)
+	requireT.Equal(now.Add(time.Hour), jobs[0].NextRunAt)
+	// panic is recorded as failure
+	requireT.Equal("panic: nil summary", jobs[1].LastError)
+
+	fail = false
+	clk.Advance(time.Hour)
+	s.run(ctx, s.jobs["gc"])
+	jobs = s.Jobs()
+	requireT.Equal(uint64(2), jobs[0].Runs)
+	requireT.Equal(uint64(1), jobs[0].Failures)
+	requireT.Equal(now.Add(time.Hour), jobs[0].LastSuccessAt)
+	requireT.Len(jobs[0].History, 2)
+	requireT.Empty(jobs[0].History[0].Error)
+	requireT.Equal("store is locked", jobs[0].History[1].Error)
+
+	// history is bounded
+	for i := 0; i < historySize; i++ {
+		s.run(ctx, s.jobs["gc"])
+	}
+	requireT.Len(s.Jobs()[0].History, historySize)
+
+	// restarted scheduler keeps the schedule
+	restarted := New(store, clk)
+	restarted.Add(Job{Name: "gc", Interval: time.Hour, Run: func(ctx context.Context) error { return nil }})
+	requireT.NoError(restarted.load(ctx))
+	jobs = restarted.Jobs()
+	requireT.Equal(now.Add(2*time.Hour), jobs[0].NextRunAt)
+	requireT.Equal(uint64(2+historySize), jobs[0].Runs)
+}
+
+func TestSchedulerTrigger(t *testing.T) {
+	requireT := require.New(t)
+	ctx, cancel := context.WithCancel(logger.WithLogger(context.Background(), zaptest.NewLogger(t)))
+	t.Cleanup(cancel)
+
+	s := New(&memoryStore{}, clock.System{})
+	runs := make(chan struct{})
+	s.Add(Job{Name: "reload", Interval: time.Hour, Run: func(ctx context.Context) error {
+		select {
+		case runs <- struct{}{}:
+		case <-ctx.Done():
+		}
+		return nil
+	}})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = s.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	// the first run is immediate, the next one is triggered
+	<-runs
+	requireT.NoError(s.Trigger("reload"))
+	select {
+	case <-runs:
+	case <-time.After(5 * time.Second):
+		requireT.Fail("triggered job didn't run")
+	}
+}
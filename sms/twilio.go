@@ -0,0 +1,83 @@
+// Package sms delivers the text messages sent to the clients, e.g. the verification codes.
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TwilioEndpoint is the endpoint of Twilio REST API.
+const TwilioEndpoint = "https://api.twilio.com"
+
+// NewTwilio returns sender delivering the messages by Twilio account. The sender is either the phone number
+// owned by the account or the SID of the messaging service, starting with "MG".
+func NewTwilio(accountSID, authToken, from string, client *http.Client) (*Twilio, error) {
+	if accountSID == "" || authToken == "" {
+		return nil, errors.New("Twilio account SID and auth token are required")
+	}
+	if from == "" {
+		return nil, errors.New("sender of the text messages is required")
+	}
+	return &Twilio{
+		endpoint:   TwilioEndpoint,
+		accountSID: accountSID,
+		authToken:  authToken,
+		from:       from,
+		client:     client,
+	}, nil
+}
+
+// Twilio delivers the text messages by Twilio Programmable Messaging.
+type Twilio struct {
+	endpoint   string
+	accountSID string
+	authToken  string
+	from       string
+	client     *http.Client
+}
+
+// SendSMS sends the text message to the phone number.
+func (t *Twilio) SendSMS(ctx context.Context, to, body string) error {
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("Body", body)
+	if strings.HasPrefix(t.from, "MG") {
+		form.Set("MessagingServiceSid", t.from)
+	} else {
+		form.Set("From", t.from)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		t.endpoint+"/2010-04-01/Accounts/"+url.PathEscape(t.accountSID)+"/Messages.json",
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(t.accountSID, t.authToken)
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "sending text message by Twilio failed")
+	}
+	defer res.Body.Close()
+	if res.StatusCode > 299 {
+		var twilioErr struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		resBody, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		if json.Unmarshal(resBody, &twilioErr) == nil && twilioErr.Message != "" {
+			return errors.Errorf("Twilio returned non 2xx response, status: %d, code: %d, message: %s",
+				res.StatusCode, twilioErr.Code, twilioErr.Message)
+		}
+		return errors.Errorf("Twilio returned non 2xx response, status: %d, body: %s", res.StatusCode, resBody)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package sms
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwilio(t *testing.T) {
+	requireT := require.New(t)
+
+	var form map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "AC123" || password != "token" || r.URL.Path != "/2010-04-01/Accounts/AC123/Messages.json" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		requireT.NoError(r.ParseForm())
+		form = map[string]string{}
+		for name := range r.PostForm {
+			form[name] = r.PostForm.Get(name)
+		}
+		if form["To"] == "+15005550001" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code": 21211, "message": "The 'To' number is not a valid phone number."}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := NewTwilio("", "token", "+15005550006", srv.Client())
+	requireT.Error(err)
+
+	twilio, err := NewTwilio("AC123", "token", "+15005550006", srv.Client())
+	requireT.NoError(err)
+	twilio.endpoint = srv.URL
+	requireT.NoError(twilio.SendSMS(context.Background(), "+14155552671", "Your code is 123456"))
+	requireT.Equal(map[string]string{"To": "+14155552671", "From": "+15005550006", "Body": "Your code is 123456"}, form)
+
+	err = twilio.SendSMS(context.Background(), "+15005550001", "Your code is 123456")
+	requireT.ErrorContains(err, "21211")
+
+	service, err := NewTwilio("AC123", "token", "MG0123", srv.Client())
+	requireT.NoError(err)
+	service.endpoint = srv.URL
+	requireT.NoError(service.SendSMS(context.Background(), "+14155552671", "Your code is 123456"))
+	requireT.Equal("MG0123", form["MessagingServiceSid"])
+	requireT.NotContains(form, "From")
+
+	wrong, err := NewTwilio("AC123", "wrong", "+15005550006", srv.Client())
+	requireT.NoError(err)
+	wrong.endpoint = srv.URL
+	requireT.Error(wrong.SendSMS(context.Background(), "+14155552671", "Your code is 123456"))
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// PutRecipient stores the recipient, replacing the existing one having the same name.
+func (s *Store) PutRecipient(ctx context.Context, recipient app.Recipient) error {
+	value, err := json.Marshal(recipient)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketAddressBook).Put([]byte(recipient.Name), value)
+	}))
+}
+
+// Recipient returns the recipient stored under the name.
+func (s *Store) Recipient(ctx context.Context, name string) (app.Recipient, error) {
+	var recipient app.Recipient
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(bucketAddressBook).Get([]byte(name))
+		if value == nil {
+			return errors.Wrapf(app.ErrRecipientNotFound, "name: %s", name)
+		}
+		return errors.WithStack(json.Unmarshal(value, &recipient))
+	})
+	return recipient, err
+}
+
+// Recipients returns all the stored recipients ordered by name.
+func (s *Store) Recipients(ctx context.Context) ([]app.Recipient, error) {
+	recipients := []app.Recipient{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketAddressBook).ForEach(func(_, value []byte) error {
+			var recipient app.Recipient
+			if err := json.Unmarshal(value, &recipient); err != nil {
+				return errors.WithStack(err)
+			}
+			recipients = append(recipients, recipient)
+			return nil
+		})
+	})
+	return recipients, err
+}
+
+// DeleteRecipient deletes the recipient stored under the name.
+func (s *Store) DeleteRecipient(ctx context.Context, name string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketAddressBook)
+		if bucket.Get([]byte(name)) == nil {
+			return errors.Wrapf(app.ErrRecipientNotFound, "name: %s", name)
+		}
+		return errors.WithStack(bucket.Delete([]byte(name)))
+	})
+}
@@ -0,0 +1,42 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestAddressBook(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	requireT.NoError(s.PutRecipient(ctx, app.Recipient{Name: "relayer-2", Address: "addr2"}))
+	requireT.NoError(s.PutRecipient(ctx, app.Recipient{Name: "relayer-1", Address: "addr1"}))
+	requireT.NoError(s.PutRecipient(ctx, app.Recipient{Name: "relayer-1", Address: "addr3"}))
+
+	recipient, err := s.Recipient(ctx, "relayer-1")
+	requireT.NoError(err)
+	requireT.Equal("addr3", recipient.Address)
+
+	recipients, err := s.Recipients(ctx)
+	requireT.NoError(err)
+	requireT.Len(recipients, 2)
+	requireT.Equal("relayer-1", recipients[0].Name)
+	requireT.Equal("relayer-2", recipients[1].Name)
+
+	requireT.NoError(s.DeleteRecipient(ctx, "relayer-1"))
+	_, err = s.Recipient(ctx, "relayer-1")
+	requireT.True(errors.Is(err, app.ErrRecipientNotFound))
+	requireT.True(errors.Is(s.DeleteRecipient(ctx, "relayer-1"), app.ErrRecipientNotFound))
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// AddressTotal returns the cumulative amount sent to the address, it is empty if nothing is recorded.
+func (s *Store) AddressTotal(ctx context.Context, address chain.AccAddress) (app.AddressTotal, error) {
+	var total app.AddressTotal
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		total, err = getAddressTotal(tx.Bucket(bucketAddressTotals), address)
+		return err
+	})
+	return total, err
+}
+
+// ReserveAddressTotal adds the amount to the total of the address unless the total of the denom would exceed
+// the limit, nil limit means no limit. Bolt serializes write transactions, so concurrent requests can't exceed it.
+func (s *Store) ReserveAddressTotal(
+	ctx context.Context,
+	address chain.AccAddress,
+	amount chain.Coin,
+	limit chain.Int,
+	now time.Time,
+) (app.AddressTotal, bool, error) {
+	var total app.AddressTotal
+	var reserved bool
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketAddressTotals)
+		var err error
+		total, err = getAddressTotal(bucket, address)
+		if err != nil {
+			return err
+		}
+		if !limit.IsNil() && total.Amount.AmountOf(amount.Denom).Add(amount.Amount).GT(limit) {
+			return nil
+		}
+		total.Address = address.String()
+		total.Amount = total.Amount.Add(amount)
+		total.UpdatedAt = now
+		reserved = true
+		return putAddressTotal(bucket, address, total)
+	})
+	return total, reserved, err
+}
+
+// ReleaseAddressTotal subtracts the amount from the total of the address.
+func (s *Store) ReleaseAddressTotal(ctx context.Context, address chain.AccAddress, amount chain.Coin) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketAddressTotals)
+		total, err := getAddressTotal(bucket, address)
+		if err != nil {
+			return err
+		}
+		// the total never goes negative, even if it was reset while the transfer was in progress
+		released := amount.Amount
+		if current := total.Amount.AmountOf(amount.Denom); released.GT(current) {
+			released = current
+		}
+		total.Amount = total.Amount.Sub(chain.NewCoins(chain.NewCoin(amount.Denom, released)))
+		return putAddressTotal(bucket, address, total)
+	})
+}
+
+// ResetAddressTotal deletes the total of the address.
+func (s *Store) ResetAddressTotal(ctx context.Context, address chain.AccAddress) error {
+	return errors.WithStack(s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketAddressTotals).Delete(address)
+	}))
+}
+
+func getAddressTotal(bucket *bolt.Bucket, address chain.AccAddress) (app.AddressTotal, error) {
+	total := app.AddressTotal{Address: address.String(), Amount: chain.NewCoins()}
+	value := bucket.Get(address)
+	if value == nil {
+		return total, nil
+	}
+	if err := json.Unmarshal(value, &total); err != nil {
+		return app.AddressTotal{}, errors.WithStack(err)
+	}
+	return total, nil
+}
+
+func putAddressTotal(bucket *bolt.Bucket, address chain.AccAddress, total app.AddressTotal) error {
+	value, err := json.Marshal(total)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(bucket.Put(address, value))
+}
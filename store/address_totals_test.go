@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+func TestAddressTotals(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	address := chain.AccAddress("address-1")
+	limit := chain.NewInt(250)
+
+	total, err := s.AddressTotal(ctx, address)
+	requireT.NoError(err)
+	requireT.True(total.Amount.IsZero())
+
+	total, reserved, err := s.ReserveAddressTotal(ctx, address, chain.NewCoin("ucore", chain.NewInt(100)), limit, now)
+	requireT.NoError(err)
+	requireT.True(reserved)
+	total, reserved, err = s.ReserveAddressTotal(ctx, address, chain.NewCoin("ucore", chain.NewInt(100)), limit, now)
+	requireT.NoError(err)
+	requireT.True(reserved)
+	requireT.Equal("200ucore", total.Amount.String())
+
+	// the limit would be exceeded
+	total, reserved, err = s.ReserveAddressTotal(ctx, address, chain.NewCoin("ucore", chain.NewInt(100)), limit, now)
+	requireT.NoError(err)
+	requireT.False(reserved)
+	requireT.Equal("200ucore", total.Amount.String())
+
+	// no limit, e.g. for the admin
+	total, reserved, err = s.ReserveAddressTotal(ctx, address, chain.NewCoin("ucore", chain.NewInt(100)),
+		chain.Int{}, now.Add(time.Hour))
+	requireT.NoError(err)
+	requireT.True(reserved)
+	requireT.Equal("300ucore", total.Amount.String())
+	requireT.Equal(now.Add(time.Hour), total.UpdatedAt)
+
+	requireT.NoError(s.ReleaseAddressTotal(ctx, address, chain.NewCoin("ucore", chain.NewInt(100))))
+	total, err = s.AddressTotal(ctx, address)
+	requireT.NoError(err)
+	requireT.Equal("200ucore", total.Amount.String())
+
+	requireT.NoError(s.ResetAddressTotal(ctx, address))
+	// release after the reset doesn't make the total negative
+	requireT.NoError(s.ReleaseAddressTotal(ctx, address, chain.NewCoin("ucore", chain.NewInt(100))))
+	total, err = s.AddressTotal(ctx, address)
+	requireT.NoError(err)
+	requireT.True(total.Amount.IsZero())
+}
+
+func TestAddressTotalsMigration(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "faucet.db")
+
+	const address = "devcore10krrrqxxy948n5p9xvwgq6krgy9hg5g8svaz62"
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	s, err := Open(path)
+	requireT.NoError(err)
+	for i, requestID := range []string{"rq1", "rq2"} {
+		requireT.NoError(s.RecordFunding(ctx, app.FundingRecord{
+			RequestID: requestID,
+			Address:   address,
+			Amount:    chain.NewCoin("udevcore", chain.NewInt(1000)),
+			Time:      now.Add(time.Duration(i) * time.Hour),
+		}))
+	}
+	requireT.NoError(s.Close())
+
+	// store of the schema before the totals were tracked
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	requireT.NoError(err)
+	requireT.NoError(db.Update(func(tx *bolt.Tx) error {
+		return tx.DeleteBucket(bucketAddressTotals)
+	}))
+	requireT.NoError(db.Close())
+	setSchemaVersion(t, path, 3)
+
+	s, err = Open(path)
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+	_, sdkAddr, err := chain.DecodeBech32(address)
+	requireT.NoError(err)
+	total, err := s.AddressTotal(ctx, sdkAddr)
+	requireT.NoError(err)
+	requireT.Equal(address, total.Address)
+	requireT.Equal("2000udevcore", total.Amount.String())
+	requireT.Equal(now.Add(time.Hour), total.UpdatedAt)
+}
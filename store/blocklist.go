@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// BlockAddress stores the blocked address, replacing the existing entry.
+func (s *Store) BlockAddress(ctx context.Context, address chain.AccAddress, entry app.BlockedAddress) error {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketBlocklist).Put(address, value)
+	}))
+}
+
+// UnblockAddress deletes the blocked address.
+func (s *Store) UnblockAddress(ctx context.Context, address chain.AccAddress) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketBlocklist)
+		if bucket.Get(address) == nil {
+			return errors.WithStack(app.ErrAddressNotBlocked)
+		}
+		return errors.WithStack(bucket.Delete(address))
+	})
+}
+
+// BlockedAddress returns the entry of the address if it is blocked.
+func (s *Store) BlockedAddress(ctx context.Context, address chain.AccAddress) (app.BlockedAddress, bool, error) {
+	var entry app.BlockedAddress
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(bucketBlocklist).Get(address)
+		if value == nil {
+			return nil
+		}
+		found = true
+		return errors.WithStack(json.Unmarshal(value, &entry))
+	})
+	return entry, found, err
+}
+
+// BlockedAddresses returns all the blocked addresses.
+func (s *Store) BlockedAddresses(ctx context.Context) ([]app.BlockedAddress, error) {
+	entries := []app.BlockedAddress{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketBlocklist).ForEach(func(_, value []byte) error {
+			var entry app.BlockedAddress
+			if err := json.Unmarshal(value, &entry); err != nil {
+				return errors.WithStack(err)
+			}
+			entries = append(entries, entry)
+			return nil
+		})
+	})
+	return entries, err
+}
@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+func TestBlocklist(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	address := chain.AccAddress{0x01, 0x02}
+	_, blocked, err := s.BlockedAddress(ctx, address)
+	requireT.NoError(err)
+	requireT.False(blocked)
+
+	requireT.NoError(s.BlockAddress(ctx, address, app.BlockedAddress{Address: "devcore1abc", Reason: "abuse"}))
+	entry, blocked, err := s.BlockedAddress(ctx, address)
+	requireT.NoError(err)
+	requireT.True(blocked)
+	requireT.Equal("abuse", entry.Reason)
+
+	entries, err := s.BlockedAddresses(ctx)
+	requireT.NoError(err)
+	requireT.Len(entries, 1)
+
+	requireT.NoError(s.UnblockAddress(ctx, address))
+	requireT.ErrorIs(s.UnblockAddress(ctx, address), app.ErrAddressNotBlocked)
+	_, blocked, err = s.BlockedAddress(ctx, address)
+	requireT.NoError(err)
+	requireT.False(blocked)
+}
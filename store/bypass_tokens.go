@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// PutBypassToken stores the bypass token, token already stored is rejected.
+func (s *Store) PutBypassToken(ctx context.Context, token app.BypassToken) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketBypassTokens)
+		if bucket.Get([]byte(token.ID)) != nil {
+			return errors.Errorf("bypass token %s already exists", token.ID)
+		}
+		return putBypassToken(bucket, token)
+	})
+}
+
+// BypassTokens returns all the stored bypass tokens ordered by ID.
+func (s *Store) BypassTokens(ctx context.Context) ([]app.BypassToken, error) {
+	tokens := []app.BypassToken{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketBypassTokens).ForEach(func(_, value []byte) error {
+			var token app.BypassToken
+			if err := json.Unmarshal(value, &token); err != nil {
+				return errors.WithStack(err)
+			}
+			tokens = append(tokens, token)
+			return nil
+		})
+	})
+	return tokens, err
+}
+
+// DeleteBypassToken deletes the bypass token.
+func (s *Store) DeleteBypassToken(ctx context.Context, id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketBypassTokens)
+		if bucket.Get([]byte(id)) == nil {
+			return errors.Wrapf(app.ErrBypassTokenNotFound, "token: %s", id)
+		}
+		return errors.WithStack(bucket.Delete([]byte(id)))
+	})
+}
+
+// UseBypassToken consumes one request of the quota of the bypass token. Bolt serializes write transactions,
+// so concurrent requests never exceed the quota.
+func (s *Store) UseBypassToken(ctx context.Context, id, hash string, now time.Time) (app.BypassToken, error) {
+	var token app.BypassToken
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketBypassTokens)
+		var err error
+		token, err = getBypassToken(bucket, id)
+		if errors.Is(err, app.ErrBypassTokenNotFound) || (err == nil && !app.VerifyBypassTokenHash(token, hash)) {
+			return errors.Wrap(app.ErrBypassTokenInvalid, "unknown token")
+		}
+		if err != nil {
+			return err
+		}
+		token, err = token.Consume(now)
+		if err != nil {
+			return err
+		}
+		return putBypassToken(bucket, token)
+	})
+	return token, err
+}
+
+// SetBypassTokenExpiry changes the expiry time of the bypass token.
+func (s *Store) SetBypassTokenExpiry(ctx context.Context, id string, expiresAt time.Time) (app.BypassToken, error) {
+	var token app.BypassToken
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketBypassTokens)
+		var err error
+		token, err = getBypassToken(bucket, id)
+		if err != nil {
+			return err
+		}
+		token.ExpiresAt = expiresAt
+		return putBypassToken(bucket, token)
+	})
+	return token, err
+}
+
+// DeleteExpiredBypassTokens deletes the bypass tokens expired at the time.
+func (s *Store) DeleteExpiredBypassTokens(ctx context.Context, now time.Time) (int, error) {
+	var deleted int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketBypassTokens)
+		var expired [][]byte
+		err := bucket.ForEach(func(key, value []byte) error {
+			var token app.BypassToken
+			if err := json.Unmarshal(value, &token); err != nil {
+				return errors.WithStack(err)
+			}
+			if !token.ExpiresAt.IsZero() && !now.Before(token.ExpiresAt) {
+				expired = append(expired, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// keys can't be deleted while iterating the bucket
+		for _, key := range expired {
+			if err := bucket.Delete(key); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		deleted = len(expired)
+		return nil
+	})
+	return deleted, err
+}
+
+func getBypassToken(bucket *bolt.Bucket, id string) (app.BypassToken, error) {
+	value := bucket.Get([]byte(id))
+	if value == nil {
+		return app.BypassToken{}, errors.Wrapf(app.ErrBypassTokenNotFound, "token: %s", id)
+	}
+	var token app.BypassToken
+	return token, errors.WithStack(json.Unmarshal(value, &token))
+}
+
+func putBypassToken(bucket *bolt.Bucket, token app.BypassToken) error {
+	value, err := json.Marshal(token)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(bucket.Put([]byte(token.ID), value))
+}
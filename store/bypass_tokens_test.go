@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestBypassTokens(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	requireT.NoError(s.PutBypassToken(ctx, app.BypassToken{ID: "bbbb", Hash: "hash-b", Quota: 1, WindowStart: now}))
+	requireT.NoError(s.PutBypassToken(ctx, app.BypassToken{
+		ID: "aaaa", Hash: "hash-a", Quota: 5, WindowStart: now, ExpiresAt: now.Add(time.Hour),
+	}))
+	requireT.Error(s.PutBypassToken(ctx, app.BypassToken{ID: "aaaa", Hash: "hash-c", Quota: 1}))
+
+	tokens, err := s.BypassTokens(ctx)
+	requireT.NoError(err)
+	requireT.Len(tokens, 2)
+	requireT.Equal("aaaa", tokens[0].ID)
+
+	token, err := s.UseBypassToken(ctx, "bbbb", "hash-b", now)
+	requireT.NoError(err)
+	requireT.EqualValues(1, token.Used)
+	_, err = s.UseBypassToken(ctx, "bbbb", "hash-b", now)
+	requireT.True(errors.Is(err, app.ErrBypassTokenQuotaExhausted))
+	_, err = s.UseBypassToken(ctx, "aaaa", "hash-b", now)
+	requireT.True(errors.Is(err, app.ErrBypassTokenInvalid))
+	_, err = s.UseBypassToken(ctx, "cccc", "hash-c", now)
+	requireT.True(errors.Is(err, app.ErrBypassTokenInvalid))
+
+	token, err = s.SetBypassTokenExpiry(ctx, "bbbb", now.Add(2*time.Hour))
+	requireT.NoError(err)
+	requireT.Equal(now.Add(2*time.Hour), token.ExpiresAt)
+
+	deleted, err := s.DeleteExpiredBypassTokens(ctx, now.Add(time.Hour))
+	requireT.NoError(err)
+	requireT.Equal(1, deleted)
+
+	requireT.NoError(s.DeleteBypassToken(ctx, "bbbb"))
+	requireT.True(errors.Is(s.DeleteBypassToken(ctx, "bbbb"), app.ErrBypassTokenNotFound))
+	tokens, err = s.BypassTokens(ctx)
+	requireT.NoError(err)
+	requireT.Empty(tokens)
+}
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// PutClaimCodes stores the claim codes in a single transaction, codes already stored are rejected.
+func (s *Store) PutClaimCodes(ctx context.Context, codes []app.ClaimCode) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketClaimCodes)
+		for _, code := range codes {
+			if bucket.Get([]byte(code.Code)) != nil {
+				return errors.Errorf("claim code %s already exists", code.Code)
+			}
+			if err := putClaimCode(bucket, code); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ClaimCode returns the claim code.
+func (s *Store) ClaimCode(ctx context.Context, code string) (app.ClaimCode, error) {
+	var claimCode app.ClaimCode
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		claimCode, err = getClaimCode(tx.Bucket(bucketClaimCodes), code)
+		return err
+	})
+	return claimCode, err
+}
+
+// ClaimCodes returns all the stored claim codes ordered by code.
+func (s *Store) ClaimCodes(ctx context.Context) ([]app.ClaimCode, error) {
+	codes := []app.ClaimCode{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketClaimCodes).ForEach(func(_, value []byte) error {
+			var code app.ClaimCode
+			if err := json.Unmarshal(value, &code); err != nil {
+				return errors.WithStack(err)
+			}
+			codes = append(codes, code)
+			return nil
+		})
+	})
+	return codes, err
+}
+
+// DeleteClaimCode deletes the claim code.
+func (s *Store) DeleteClaimCode(ctx context.Context, code string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketClaimCodes)
+		if bucket.Get([]byte(code)) == nil {
+			return errors.Wrapf(app.ErrClaimCodeNotFound, "code: %s", code)
+		}
+		return errors.WithStack(bucket.Delete([]byte(code)))
+	})
+}
+
+// UseClaimCode consumes one use of the claim code. Bolt serializes write transactions, so concurrent claims
+// never exceed the number of uses.
+func (s *Store) UseClaimCode(ctx context.Context, code string) (app.ClaimCode, error) {
+	var claimCode app.ClaimCode
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketClaimCodes)
+		var err error
+		claimCode, err = getClaimCode(bucket, code)
+		if err != nil {
+			return err
+		}
+		if claimCode.Uses >= claimCode.MaxUses {
+			return errors.Wrapf(app.ErrClaimCodeUsedUp, "code: %s", code)
+		}
+		claimCode.Uses++
+		return putClaimCode(bucket, claimCode)
+	})
+	return claimCode, err
+}
+
+// ReleaseClaimCode gives back one use of the claim code.
+func (s *Store) ReleaseClaimCode(ctx context.Context, code string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketClaimCodes)
+		claimCode, err := getClaimCode(bucket, code)
+		if err != nil {
+			return err
+		}
+		if claimCode.Uses > 0 {
+			claimCode.Uses--
+		}
+		return putClaimCode(bucket, claimCode)
+	})
+}
+
+// SetClaimCodeExpiry changes the expiry time of the claim code.
+func (s *Store) SetClaimCodeExpiry(ctx context.Context, code string, expiresAt time.Time) (app.ClaimCode, error) {
+	var claimCode app.ClaimCode
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketClaimCodes)
+		var err error
+		claimCode, err = getClaimCode(bucket, code)
+		if err != nil {
+			return err
+		}
+		claimCode.ExpiresAt = expiresAt
+		return putClaimCode(bucket, claimCode)
+	})
+	return claimCode, err
+}
+
+// DeleteExpiredClaimCodes deletes the claim codes expired at the time.
+func (s *Store) DeleteExpiredClaimCodes(ctx context.Context, now time.Time) (int, error) {
+	var deleted int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketClaimCodes)
+		var expired [][]byte
+		err := bucket.ForEach(func(key, value []byte) error {
+			var code app.ClaimCode
+			if err := json.Unmarshal(value, &code); err != nil {
+				return errors.WithStack(err)
+			}
+			if !code.ExpiresAt.IsZero() && !now.Before(code.ExpiresAt) {
+				expired = append(expired, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// keys can't be deleted while iterating the bucket
+		for _, key := range expired {
+			if err := bucket.Delete(key); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		deleted = len(expired)
+		return nil
+	})
+	return deleted, err
+}
+
+func getClaimCode(bucket *bolt.Bucket, code string) (app.ClaimCode, error) {
+	value := bucket.Get([]byte(code))
+	if value == nil {
+		return app.ClaimCode{}, errors.Wrapf(app.ErrClaimCodeNotFound, "code: %s", code)
+	}
+	var claimCode app.ClaimCode
+	return claimCode, errors.WithStack(json.Unmarshal(value, &claimCode))
+}
+
+func putClaimCode(bucket *bolt.Bucket, code app.ClaimCode) error {
+	value, err := json.Marshal(code)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(bucket.Put([]byte(code.Code), value))
+}
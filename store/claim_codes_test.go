@@ -0,0 +1,95 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+func TestClaimCodes(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	amount := chain.NewCoins(chain.NewCoin("ucore", chain.NewInt(10)))
+	requireT.NoError(s.PutClaimCodes(ctx, []app.ClaimCode{
+		{Code: "BBBB", Amount: amount, MaxUses: 2},
+		{Code: "AAAA", Amount: amount, MaxUses: 1},
+	}))
+	requireT.Error(s.PutClaimCodes(ctx, []app.ClaimCode{{Code: "AAAA", Amount: amount, MaxUses: 5}}))
+
+	codes, err := s.ClaimCodes(ctx)
+	requireT.NoError(err)
+	requireT.Len(codes, 2)
+	requireT.Equal("AAAA", codes[0].Code)
+	requireT.Equal(1, codes[0].MaxUses)
+	requireT.Equal(amount.String(), codes[0].Amount.String())
+
+	code, err := s.UseClaimCode(ctx, "AAAA")
+	requireT.NoError(err)
+	requireT.Equal(1, code.Uses)
+	_, err = s.UseClaimCode(ctx, "AAAA")
+	requireT.True(errors.Is(err, app.ErrClaimCodeUsedUp))
+
+	requireT.NoError(s.ReleaseClaimCode(ctx, "AAAA"))
+	code, err = s.ClaimCode(ctx, "AAAA")
+	requireT.NoError(err)
+	requireT.Equal(0, code.Uses)
+
+	requireT.NoError(s.DeleteClaimCode(ctx, "AAAA"))
+	_, err = s.UseClaimCode(ctx, "AAAA")
+	requireT.True(errors.Is(err, app.ErrClaimCodeNotFound))
+	requireT.True(errors.Is(s.DeleteClaimCode(ctx, "AAAA"), app.ErrClaimCodeNotFound))
+}
+
+func TestDeleteExpiredClaimCodes(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	amount := chain.NewCoins(chain.NewCoin("ucore", chain.NewInt(10)))
+	requireT.NoError(s.PutClaimCodes(ctx, []app.ClaimCode{
+		{Code: "AAAA", Amount: amount, MaxUses: 1, ExpiresAt: now.Add(-time.Second)},
+		{Code: "BBBB", Amount: amount, MaxUses: 1, ExpiresAt: now.Add(time.Hour)},
+		{Code: "CCCC", Amount: amount, MaxUses: 1},
+		{Code: "DDDD", Amount: amount, MaxUses: 1, ExpiresAt: now},
+	}))
+
+	deleted, err := s.DeleteExpiredClaimCodes(ctx, now)
+	requireT.NoError(err)
+	requireT.Equal(2, deleted)
+
+	codes, err := s.ClaimCodes(ctx)
+	requireT.NoError(err)
+	requireT.Len(codes, 2)
+	requireT.Equal("BBBB", codes[0].Code)
+	requireT.Equal("CCCC", codes[1].Code)
+
+	code, err := s.SetClaimCodeExpiry(ctx, "BBBB", now.Add(-time.Minute))
+	requireT.NoError(err)
+	requireT.Equal(now.Add(-time.Minute), code.ExpiresAt)
+	deleted, err = s.DeleteExpiredClaimCodes(ctx, now)
+	requireT.NoError(err)
+	requireT.Equal(1, deleted)
+
+	_, err = s.SetClaimCodeExpiry(ctx, "BBBB", now)
+	requireT.True(errors.Is(err, app.ErrClaimCodeNotFound))
+}
@@ -0,0 +1,30 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// RecordConfigChange stores the change of the runtime configuration.
+func (s *Store) RecordConfigChange(ctx context.Context, change app.ConfigChange) error {
+	return s.putTimeline(bucketConfigChanges, change.Time, change.ID, change)
+}
+
+// ConfigChangesSince returns the configuration changes stored since the given time ordered by time.
+func (s *Store) ConfigChangesSince(ctx context.Context, since time.Time) ([]app.ConfigChange, error) {
+	var changes []app.ConfigChange
+	err := s.scanTimeline(bucketConfigChanges, since, func(value []byte) error {
+		var change app.ConfigChange
+		if err := json.Unmarshal(value, &change); err != nil {
+			return errors.WithStack(err)
+		}
+		changes = append(changes, change)
+		return nil
+	})
+	return changes, err
+}
@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestConfigChanges(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	requireT.NoError(s.RecordConfigChange(ctx, app.ConfigChange{
+		ID:     "b",
+		Time:   now.Add(time.Hour),
+		Actor:  "alice",
+		Source: app.ConfigSourceAdminAPI,
+		Diff:   []app.ConfigDiff{{Setting: "paused", Old: "false", New: "true"}},
+	}))
+	requireT.NoError(s.RecordConfigChange(ctx, app.ConfigChange{ID: "a", Time: now, Actor: "job:ipLists"}))
+
+	changes, err := s.ConfigChangesSince(ctx, now)
+	requireT.NoError(err)
+	requireT.Len(changes, 2)
+	requireT.Equal("a", changes[0].ID)
+	requireT.Equal("alice", changes[1].Actor)
+	requireT.Equal([]app.ConfigDiff{{Setting: "paused", Old: "false", New: "true"}}, changes[1].Diff)
+
+	changes, err = s.ConfigChangesSince(ctx, now.Add(time.Minute))
+	requireT.NoError(err)
+	requireT.Len(changes, 1)
+}
@@ -0,0 +1,108 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+// AddressFundedAt returns the time the address was last funded, zero if it is not recorded.
+func (s *Store) AddressFundedAt(ctx context.Context, address chain.AccAddress) (time.Time, error) {
+	var fundedAt time.Time
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		fundedAt, err = getFundedAt(tx.Bucket(bucketCooldowns), address)
+		return err
+	})
+	return fundedAt, err
+}
+
+// ReserveAddressCooldown records the funding of the address at now, unless the address was funded after notBefore.
+// Bolt serializes write transactions, so concurrent requests for the same address reserve it once.
+func (s *Store) ReserveAddressCooldown(
+	ctx context.Context,
+	address chain.AccAddress,
+	now, notBefore time.Time,
+) (time.Time, bool, error) {
+	var fundedAt time.Time
+	var reserved bool
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketCooldowns)
+		var err error
+		fundedAt, err = getFundedAt(bucket, address)
+		if err != nil {
+			return err
+		}
+		if fundedAt.After(notBefore) {
+			return nil
+		}
+		value, err := json.Marshal(now)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		reserved = true
+		fundedAt = now
+		return errors.WithStack(bucket.Put(address, value))
+	})
+	return fundedAt, reserved, err
+}
+
+// ReleaseAddressCooldown deletes the funding time of the address if it is still the one reserved at the time.
+func (s *Store) ReleaseAddressCooldown(ctx context.Context, address chain.AccAddress, reservedAt time.Time) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketCooldowns)
+		fundedAt, err := getFundedAt(bucket, address)
+		if err != nil || !fundedAt.Equal(reservedAt) {
+			return err
+		}
+		return errors.WithStack(bucket.Delete(address))
+	})
+}
+
+// DeleteAddressCooldownsBefore deletes the funding times older than the time and returns the number of deleted ones.
+func (s *Store) DeleteAddressCooldownsBefore(ctx context.Context, before time.Time) (int, error) {
+	var deleted int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketCooldowns)
+		var expired [][]byte
+		err := bucket.ForEach(func(key, value []byte) error {
+			var fundedAt time.Time
+			if err := json.Unmarshal(value, &fundedAt); err != nil {
+				return errors.WithStack(err)
+			}
+			if fundedAt.Before(before) {
+				expired = append(expired, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// keys can't be deleted while iterating the bucket
+		for _, key := range expired {
+			if err := bucket.Delete(key); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		deleted = len(expired)
+		return nil
+	})
+	return deleted, err
+}
+
+func getFundedAt(bucket *bolt.Bucket, address chain.AccAddress) (time.Time, error) {
+	var fundedAt time.Time
+	value := bucket.Get(address)
+	if value == nil {
+		return fundedAt, nil
+	}
+	if err := json.Unmarshal(value, &fundedAt); err != nil {
+		return time.Time{}, errors.WithStack(err)
+	}
+	return fundedAt, nil
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+func TestAddressCooldown(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	address := chain.AccAddress("address-1")
+
+	fundedAt, err := s.AddressFundedAt(ctx, address)
+	requireT.NoError(err)
+	requireT.True(fundedAt.IsZero())
+
+	fundedAt, reserved, err := s.ReserveAddressCooldown(ctx, address, now, now.Add(-time.Hour))
+	requireT.NoError(err)
+	requireT.True(reserved)
+	requireT.Equal(now, fundedAt)
+
+	// the address is funded within the cooldown
+	fundedAt, reserved, err = s.ReserveAddressCooldown(ctx, address, now.Add(time.Minute), now.Add(-59*time.Minute))
+	requireT.NoError(err)
+	requireT.False(reserved)
+	requireT.Equal(now, fundedAt)
+
+	// release of the other reservation keeps the funding
+	requireT.NoError(s.ReleaseAddressCooldown(ctx, address, now.Add(time.Minute)))
+	fundedAt, err = s.AddressFundedAt(ctx, address)
+	requireT.NoError(err)
+	requireT.Equal(now, fundedAt)
+
+	requireT.NoError(s.ReleaseAddressCooldown(ctx, address, now))
+	fundedAt, err = s.AddressFundedAt(ctx, address)
+	requireT.NoError(err)
+	requireT.True(fundedAt.IsZero())
+
+	_, reserved, err = s.ReserveAddressCooldown(ctx, address, now, now.Add(-time.Hour))
+	requireT.NoError(err)
+	requireT.True(reserved)
+	_, reserved, err = s.ReserveAddressCooldown(ctx, chain.AccAddress("address-2"), now.Add(time.Hour), now)
+	requireT.NoError(err)
+	requireT.True(reserved)
+
+	deleted, err := s.DeleteAddressCooldownsBefore(ctx, now.Add(time.Minute))
+	requireT.NoError(err)
+	requireT.Equal(1, deleted)
+	fundedAt, err = s.AddressFundedAt(ctx, chain.AccAddress("address-2"))
+	requireT.NoError(err)
+	requireT.Equal(now.Add(time.Hour), fundedAt)
+}
@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// PutEmailVerification stores the link sent to the email.
+func (s *Store) PutEmailVerification(ctx context.Context, verification app.EmailVerification) error {
+	value, err := json.Marshal(verification)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketEmailVerifications)
+		if bucket.Get([]byte(verification.ID)) != nil {
+			return errors.Errorf("email verification %s already exists", verification.ID)
+		}
+		return errors.WithStack(bucket.Put([]byte(verification.ID), value))
+	})
+}
+
+// UseEmailVerification deletes the link and returns it. Bolt serializes write transactions, so each link
+// is used once even if it is opened concurrently.
+func (s *Store) UseEmailVerification(ctx context.Context, id string, now time.Time) (app.EmailVerification, error) {
+	var verification app.EmailVerification
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketEmailVerifications)
+		value := bucket.Get([]byte(id))
+		if value == nil {
+			return errors.Wrap(app.ErrEmailVerificationFailed, "link is unknown or used already")
+		}
+		if err := json.Unmarshal(value, &verification); err != nil {
+			return errors.WithStack(err)
+		}
+		if !now.Before(verification.ExpiresAt) {
+			return errors.Wrap(app.ErrEmailVerificationFailed, "link is expired")
+		}
+		return errors.WithStack(bucket.Delete([]byte(id)))
+	})
+	return verification, err
+}
+
+// DeleteExpiredEmailVerifications deletes the links expired at the time.
+func (s *Store) DeleteExpiredEmailVerifications(ctx context.Context, now time.Time) (int, error) {
+	var deleted int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketEmailVerifications)
+		var expired [][]byte
+		err := bucket.ForEach(func(key, value []byte) error {
+			var verification app.EmailVerification
+			if err := json.Unmarshal(value, &verification); err != nil {
+				return errors.WithStack(err)
+			}
+			if !now.Before(verification.ExpiresAt) {
+				expired = append(expired, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// keys can't be deleted while iterating the bucket
+		for _, key := range expired {
+			if err := bucket.Delete(key); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		deleted = len(expired)
+		return nil
+	})
+	return deleted, err
+}
@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestEmailVerifications(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, v := range []app.EmailVerification{
+		{ID: "a", Address: "devcore1a", Email: "a@example.com", CreatedAt: now, ExpiresAt: now.Add(time.Hour)},
+		{ID: "b", Address: "devcore1b", Email: "b@example.com", CreatedAt: now, ExpiresAt: now.Add(time.Minute)},
+		{ID: "c", Address: "devcore1c", Email: "c@example.com", CreatedAt: now, ExpiresAt: now.Add(time.Minute)},
+	} {
+		requireT.NoError(s.PutEmailVerification(ctx, v))
+	}
+	requireT.Error(s.PutEmailVerification(ctx, app.EmailVerification{ID: "a"}))
+
+	v, err := s.UseEmailVerification(ctx, "a", now)
+	requireT.NoError(err)
+	requireT.Equal("devcore1a", v.Address)
+	_, err = s.UseEmailVerification(ctx, "a", now)
+	requireT.ErrorIs(err, app.ErrEmailVerificationFailed)
+
+	_, err = s.UseEmailVerification(ctx, "b", now.Add(time.Minute))
+	requireT.ErrorIs(err, app.ErrEmailVerificationFailed)
+
+	deleted, err := s.DeleteExpiredEmailVerifications(ctx, now.Add(time.Minute))
+	requireT.NoError(err)
+	requireT.Equal(2, deleted)
+	_, err = s.UseEmailVerification(ctx, "c", now)
+	requireT.ErrorIs(err, app.ErrEmailVerificationFailed)
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// RedeemFundingLink records the redemption of the link. Bolt serializes write transactions, so each link
+// is redeemed once even if it is submitted concurrently.
+func (s *Store) RedeemFundingLink(ctx context.Context, redemption app.FundingLinkRedemption) error {
+	value, err := json.Marshal(redemption)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketFundingLinks)
+		if bucket.Get([]byte(redemption.ID)) != nil {
+			return errors.Wrapf(app.ErrFundingLinkUsed, "link %s", redemption.ID)
+		}
+		return errors.WithStack(bucket.Put([]byte(redemption.ID), value))
+	})
+}
+
+// ReleaseFundingLink deletes the redemption of the link.
+func (s *Store) ReleaseFundingLink(ctx context.Context, id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return errors.WithStack(tx.Bucket(bucketFundingLinks).Delete([]byte(id)))
+	})
+}
+
+// DeleteExpiredFundingLinks deletes the redemptions of the links expired at the time, the expired links are
+// refused by their signed expiry anyway.
+func (s *Store) DeleteExpiredFundingLinks(ctx context.Context, now time.Time) (int, error) {
+	var deleted int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketFundingLinks)
+		var expired [][]byte
+		err := bucket.ForEach(func(key, value []byte) error {
+			var redemption app.FundingLinkRedemption
+			if err := json.Unmarshal(value, &redemption); err != nil {
+				return errors.WithStack(err)
+			}
+			if !now.Before(redemption.ExpiresAt) {
+				expired = append(expired, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// keys can't be deleted while iterating the bucket
+		for _, key := range expired {
+			if err := bucket.Delete(key); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		deleted = len(expired)
+		return nil
+	})
+	return deleted, err
+}
@@ -0,0 +1,40 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestFundingLinks(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := app.FundingLinkRedemption{ID: "a", Address: "devcore1a", RedeemedAt: now, ExpiresAt: now.Add(time.Hour)}
+	b := app.FundingLinkRedemption{ID: "b", Address: "devcore1b", RedeemedAt: now, ExpiresAt: now.Add(time.Minute)}
+	requireT.NoError(s.RedeemFundingLink(ctx, a))
+	requireT.NoError(s.RedeemFundingLink(ctx, b))
+	requireT.ErrorIs(s.RedeemFundingLink(ctx, a), app.ErrFundingLinkUsed)
+
+	// released link is redeemed again
+	requireT.NoError(s.ReleaseFundingLink(ctx, "a"))
+	requireT.NoError(s.RedeemFundingLink(ctx, a))
+
+	deleted, err := s.DeleteExpiredFundingLinks(ctx, now.Add(time.Minute))
+	requireT.NoError(err)
+	requireT.Equal(1, deleted)
+	requireT.NoError(s.RedeemFundingLink(ctx, b))
+	requireT.ErrorIs(s.RedeemFundingLink(ctx, a), app.ErrFundingLinkUsed)
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// RecordFunding stores the funding record in history.
+func (s *Store) RecordFunding(ctx context.Context, record app.FundingRecord) error {
+	return s.putTimeline(bucketHistory, record.Time, record.RequestID, record)
+}
+
+// FundingsSince returns funding records stored since the given time ordered by time.
+func (s *Store) FundingsSince(ctx context.Context, since time.Time) ([]app.FundingRecord, error) {
+	var records []app.FundingRecord
+	err := s.scanTimeline(bucketHistory, since, func(value []byte) error {
+		var record app.FundingRecord
+		if err := json.Unmarshal(value, &record); err != nil {
+			return errors.WithStack(err)
+		}
+		records = append(records, record)
+		return nil
+	})
+	return records, err
+}
+
+// RecordIncident stores the incident.
+func (s *Store) RecordIncident(ctx context.Context, incident app.Incident) error {
+	return s.putTimeline(bucketIncidents, incident.Time, incident.RequestID, incident)
+}
+
+// IncidentsSince returns incidents stored since the given time ordered by time.
+func (s *Store) IncidentsSince(ctx context.Context, since time.Time) ([]app.Incident, error) {
+	var incidents []app.Incident
+	err := s.scanTimeline(bucketIncidents, since, func(value []byte) error {
+		var incident app.Incident
+		if err := json.Unmarshal(value, &incident); err != nil {
+			return errors.WithStack(err)
+		}
+		incidents = append(incidents, incident)
+		return nil
+	})
+	return incidents, err
+}
+
+// AnonymizeFundingIPs replaces IPs of the funding records stored in the period with the result of anonymize.
+func (s *Store) AnonymizeFundingIPs(
+	ctx context.Context,
+	since, before time.Time,
+	anonymize func(ip string) string,
+) (int, error) {
+	var updated int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketHistory)
+		// bolt cursor may be invalidated by modifications, so records are rewritten after the scan
+		changes := map[string][]byte{}
+		end := timelineKey(before, "")
+		c := bucket.Cursor()
+		for k, v := c.Seek(timelineKey(since, "")); k != nil && bytes.Compare(k, end) < 0; k, v = c.Next() {
+			var record app.FundingRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return errors.WithStack(err)
+			}
+			ip := anonymize(record.IP)
+			if ip == record.IP {
+				continue
+			}
+			record.IP = ip
+			value, err := json.Marshal(record)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			changes[string(k)] = value
+		}
+		for k, v := range changes {
+			if err := bucket.Put([]byte(k), v); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		updated = len(changes)
+		return nil
+	})
+	return updated, err
+}
+
+func (s *Store) putTimeline(bucket []byte, t time.Time, id string, item interface{}) error {
+	value, err := json.Marshal(item)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put(timelineKey(t, id), value)
+	}))
+}
+
+func (s *Store) scanTimeline(bucket []byte, since time.Time, fn func(value []byte) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+		for k, v := c.Seek(timelineKey(since, "")); k != nil; k, v = c.Next() {
+			if err := fn(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// timelineKey makes items ordered by time, id makes the key unique.
+func timelineKey(t time.Time, id string) []byte {
+	key := bytes.NewBuffer(make([]byte, 0, 8+len(id)))
+	_ = binary.Write(key, binary.BigEndian, uint64(t.UnixNano()))
+	key.WriteString(id)
+	return key.Bytes()
+}
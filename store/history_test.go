@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestFundingsSince(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, requestID := range []string{"rq3", "rq1", "rq2"} {
+		requireT.NoError(s.RecordFunding(ctx, app.FundingRecord{
+			RequestID: requestID,
+			Time:      start.Add(time.Duration(2-i) * time.Hour),
+		}))
+	}
+
+	records, err := s.FundingsSince(ctx, start.Add(time.Minute))
+	requireT.NoError(err)
+	requireT.Len(records, 2)
+	assert.Equal(t, "rq1", records[0].RequestID)
+	assert.Equal(t, "rq3", records[1].RequestID)
+}
+
+func TestAnonymizeFundingIPs(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		requireT.NoError(s.RecordFunding(ctx, app.FundingRecord{
+			RequestID: ip,
+			IP:        ip,
+			Time:      start.Add(time.Duration(i) * time.Hour),
+		}))
+	}
+
+	anonymize := func(ip string) string {
+		if ip == "10.0.0.1" {
+			// already anonymized values are returned unchanged
+			return ip
+		}
+		return "anonymized"
+	}
+	updated, err := s.AnonymizeFundingIPs(ctx, start, start.Add(90*time.Minute), anonymize)
+	requireT.NoError(err)
+	requireT.Equal(1, updated)
+
+	records, err := s.FundingsSince(ctx, start)
+	requireT.NoError(err)
+	requireT.Len(records, 3)
+	assert.Equal(t, "10.0.0.1", records[0].IP)
+	assert.Equal(t, "anonymized", records[1].IP)
+	assert.Equal(t, "10.0.0.3", records[2].IP)
+}
@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// UseIdentityQuota consumes one request of the quota of the account. Bolt serializes write transactions,
+// so concurrent requests never exceed the quota.
+func (s *Store) UseIdentityQuota(
+	ctx context.Context,
+	key string,
+	quota uint64,
+	period time.Duration,
+	now time.Time,
+) (app.IdentityUsage, error) {
+	usage := app.IdentityUsage{Key: key}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketIdentityQuotas)
+		if value := bucket.Get([]byte(key)); value != nil {
+			if err := json.Unmarshal(value, &usage); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		var err error
+		usage, err = usage.Consume(now, quota, period)
+		if err != nil {
+			return err
+		}
+		value, err := json.Marshal(usage)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		return errors.WithStack(bucket.Put([]byte(key), value))
+	})
+	return usage, err
+}
+
+// IdentityQuotaUsage returns the usage of the quota of the account.
+func (s *Store) IdentityQuotaUsage(ctx context.Context, key string) (app.IdentityUsage, error) {
+	usage := app.IdentityUsage{Key: key}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(bucketIdentityQuotas).Get([]byte(key))
+		if value == nil {
+			return nil
+		}
+		return errors.WithStack(json.Unmarshal(value, &usage))
+	})
+	return usage, err
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestUseIdentityQuota(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := uint64(1); i <= 2; i++ {
+		usage, err := s.UseIdentityQuota(ctx, "github:1", 2, time.Hour, now)
+		requireT.NoError(err)
+		requireT.Equal(i, usage.Used)
+	}
+	_, err = s.UseIdentityQuota(ctx, "github:1", 2, time.Hour, now.Add(time.Minute))
+	requireT.ErrorIs(err, app.ErrIdentityQuotaExhausted)
+	var throttled app.ThrottledError
+	requireT.ErrorAs(err, &throttled)
+	requireT.Equal(now.Add(time.Hour), throttled.NextAvailableAt)
+
+	// accounts have their own quotas
+	_, err = s.UseIdentityQuota(ctx, "github:2", 2, time.Hour, now)
+	requireT.NoError(err)
+
+	// quota is renewed once the period passes
+	usage, err := s.UseIdentityQuota(ctx, "github:1", 2, time.Hour, now.Add(time.Hour))
+	requireT.NoError(err)
+	requireT.Equal(uint64(1), usage.Used)
+	requireT.Equal(now.Add(time.Hour), usage.WindowStart)
+
+	usage, err = s.IdentityQuotaUsage(ctx, "github:1")
+	requireT.NoError(err)
+	requireT.Equal(uint64(1), usage.Used)
+	usage, err = s.IdentityQuotaUsage(ctx, "github:3")
+	requireT.NoError(err)
+	requireT.Equal(app.IdentityUsage{Key: "github:3"}, usage)
+}
@@ -0,0 +1,38 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/scheduler"
+)
+
+// PutJobState stores the state of the background job.
+func (s *Store) PutJobState(ctx context.Context, state scheduler.State) error {
+	value, err := json.Marshal(state)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return errors.WithStack(tx.Bucket(bucketJobs).Put([]byte(state.Name), value))
+	})
+}
+
+// JobStates returns the states of all the background jobs ever run, ordered by name.
+func (s *Store) JobStates(ctx context.Context) ([]scheduler.State, error) {
+	states := []scheduler.State{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketJobs).ForEach(func(_, value []byte) error {
+			var state scheduler.State
+			if err := json.Unmarshal(value, &state); err != nil {
+				return errors.WithStack(err)
+			}
+			states = append(states, state)
+			return nil
+		})
+	})
+	return states, err
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/scheduler"
+)
+
+func TestJobStates(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	states, err := s.JobStates(ctx)
+	requireT.NoError(err)
+	requireT.Empty(states)
+
+	lastRunAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	requireT.NoError(s.PutJobState(ctx, scheduler.State{Name: "report", Runs: 1, LastRunAt: lastRunAt}))
+	requireT.NoError(s.PutJobState(ctx, scheduler.State{Name: "gc", Runs: 1}))
+	requireT.NoError(s.PutJobState(ctx, scheduler.State{
+		Name:      "report",
+		Runs:      2,
+		LastRunAt: lastRunAt.Add(time.Hour),
+		History:   []scheduler.Run{{StartedAt: lastRunAt.Add(time.Hour), Error: "smtp unavailable"}},
+	}))
+
+	states, err = s.JobStates(ctx)
+	requireT.NoError(err)
+	requireT.Len(states, 2)
+	requireT.Equal("gc", states[0].Name)
+	requireT.Equal("report", states[1].Name)
+	requireT.Equal(uint64(2), states[1].Runs)
+	requireT.Equal(lastRunAt.Add(time.Hour), states[1].LastRunAt)
+	requireT.Equal("smtp unavailable", states[1].History[0].Error)
+}
@@ -0,0 +1,30 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// RecordLedgerTransaction stores the transaction in the budget ledger.
+func (s *Store) RecordLedgerTransaction(ctx context.Context, tx app.LedgerTransaction) error {
+	return s.putTimeline(bucketLedger, tx.Time, tx.ID, tx)
+}
+
+// LedgerTransactionsSince returns ledger transactions stored since the given time ordered by time.
+func (s *Store) LedgerTransactionsSince(ctx context.Context, since time.Time) ([]app.LedgerTransaction, error) {
+	var txs []app.LedgerTransaction
+	err := s.scanTimeline(bucketLedger, since, func(value []byte) error {
+		var tx app.LedgerTransaction
+		if err := json.Unmarshal(value, &tx); err != nil {
+			return errors.WithStack(err)
+		}
+		txs = append(txs, tx)
+		return nil
+	})
+	return txs, err
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// NamedAccount returns the account generated under the label in the namespace.
+func (s *Store) NamedAccount(ctx context.Context, namespace, label string) (app.NamedAccount, error) {
+	var account app.NamedAccount
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		account, err = getNamedAccount(tx.Bucket(bucketNamedAccounts), namespace, label)
+		return err
+	})
+	return account, err
+}
+
+// CreateNamedAccount stores the account unless the account of the label exists already in the namespace,
+// returning the stored one.
+func (s *Store) CreateNamedAccount(ctx context.Context, account app.NamedAccount) (app.NamedAccount, error) {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketNamedAccounts)
+		existing, err := getNamedAccount(bucket, account.Namespace, account.Label)
+		if err == nil {
+			account = existing
+			return nil
+		}
+		if !errors.Is(err, app.ErrNamedAccountNotFound) {
+			return err
+		}
+		value, err := json.Marshal(account)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		return errors.WithStack(bucket.Put(namedAccountKey(account.Namespace, account.Label), value))
+	})
+	return account, err
+}
+
+func getNamedAccount(bucket *bolt.Bucket, namespace, label string) (app.NamedAccount, error) {
+	value := bucket.Get(namedAccountKey(namespace, label))
+	if value == nil {
+		return app.NamedAccount{}, errors.Wrapf(app.ErrNamedAccountNotFound, "label: %s", label)
+	}
+	var account app.NamedAccount
+	return account, errors.WithStack(json.Unmarshal(value, &account))
+}
+
+// namedAccountKey separates the namespace from the label by the zero byte, which is allowed in neither.
+func namedAccountKey(namespace, label string) []byte {
+	return []byte(namespace + "\x00" + label)
+}
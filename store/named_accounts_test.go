@@ -0,0 +1,47 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestNamedAccounts(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	_, err = s.NamedAccount(ctx, "ci", "suite-1")
+	requireT.True(errors.Is(err, app.ErrNamedAccountNotFound))
+
+	account, err := s.CreateNamedAccount(ctx, app.NamedAccount{
+		Namespace: "ci", Label: "suite-1", Address: "address1", EncryptedMnemonic: []byte{0x01},
+	})
+	requireT.NoError(err)
+	requireT.Equal("address1", account.Address)
+
+	// the account stored first wins
+	account, err = s.CreateNamedAccount(ctx, app.NamedAccount{
+		Namespace: "ci", Label: "suite-1", Address: "address2", EncryptedMnemonic: []byte{0x02},
+	})
+	requireT.NoError(err)
+	requireT.Equal("address1", account.Address)
+	requireT.Equal([]byte{0x01}, account.EncryptedMnemonic)
+
+	account, err = s.NamedAccount(ctx, "ci", "suite-1")
+	requireT.NoError(err)
+	requireT.Equal("address1", account.Address)
+
+	_, err = s.NamedAccount(ctx, "partner", "suite-1")
+	requireT.True(errors.Is(err, app.ErrNamedAccountNotFound))
+}
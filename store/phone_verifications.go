@@ -0,0 +1,108 @@
+package store
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// PutPhoneVerification stores the code sent to the phone number.
+func (s *Store) PutPhoneVerification(ctx context.Context, verification app.PhoneVerification) error {
+	value, err := json.Marshal(verification)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketPhoneVerifications)
+		if bucket.Get([]byte(verification.ID)) != nil {
+			return errors.Errorf("phone verification %s already exists", verification.ID)
+		}
+		return errors.WithStack(bucket.Put([]byte(verification.ID), value))
+	})
+}
+
+// UsePhoneVerification deletes the verification and returns it if the code matches, otherwise it counts
+// the attempt. Bolt serializes write transactions, so each code is used once and the attempts are never lost.
+func (s *Store) UsePhoneVerification(
+	ctx context.Context,
+	id, codeHash string,
+	maxAttempts int,
+	now time.Time,
+) (app.PhoneVerification, error) {
+	var verification app.PhoneVerification
+	var useErr error
+	// the transaction is committed even if the code doesn't match, so the attempt is counted
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketPhoneVerifications)
+		value := bucket.Get([]byte(id))
+		if value == nil {
+			useErr = errors.Wrap(app.ErrPhoneVerificationFailed, "code is unknown or used already")
+			return nil
+		}
+		if err := json.Unmarshal(value, &verification); err != nil {
+			return errors.WithStack(err)
+		}
+		if !now.Before(verification.ExpiresAt) {
+			useErr = errors.Wrap(app.ErrPhoneVerificationFailed, "code is expired")
+			return nil
+		}
+		if subtle.ConstantTimeCompare([]byte(verification.CodeHash), []byte(codeHash)) == 1 {
+			return errors.WithStack(bucket.Delete([]byte(id)))
+		}
+
+		useErr = errors.Wrap(app.ErrPhoneVerificationFailed, "code is wrong")
+		verification.Attempts++
+		if verification.Attempts >= maxAttempts {
+			return errors.WithStack(bucket.Delete([]byte(id)))
+		}
+		value, err := json.Marshal(verification)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		return errors.WithStack(bucket.Put([]byte(id), value))
+	})
+	if err != nil {
+		return app.PhoneVerification{}, err
+	}
+	if useErr != nil {
+		return app.PhoneVerification{}, useErr
+	}
+	return verification, nil
+}
+
+// DeleteExpiredPhoneVerifications deletes the codes expired at the time.
+func (s *Store) DeleteExpiredPhoneVerifications(ctx context.Context, now time.Time) (int, error) {
+	var deleted int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketPhoneVerifications)
+		var expired [][]byte
+		err := bucket.ForEach(func(key, value []byte) error {
+			var verification app.PhoneVerification
+			if err := json.Unmarshal(value, &verification); err != nil {
+				return errors.WithStack(err)
+			}
+			if !now.Before(verification.ExpiresAt) {
+				expired = append(expired, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// keys can't be deleted while iterating the bucket
+		for _, key := range expired {
+			if err := bucket.Delete(key); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		deleted = len(expired)
+		return nil
+	})
+	return deleted, err
+}
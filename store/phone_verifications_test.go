@@ -0,0 +1,57 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestPhoneVerifications(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, v := range []app.PhoneVerification{
+		{ID: "a", Address: "devcore1a", PhoneKey: "phone:a", CodeHash: "ha", CreatedAt: now, ExpiresAt: now.Add(time.Hour)},
+		{ID: "b", Address: "devcore1b", PhoneKey: "phone:b", CodeHash: "hb", CreatedAt: now, ExpiresAt: now.Add(time.Hour)},
+		{ID: "c", Address: "devcore1c", PhoneKey: "phone:c", CodeHash: "hc", CreatedAt: now, ExpiresAt: now.Add(time.Minute)},
+	} {
+		requireT.NoError(s.PutPhoneVerification(ctx, v))
+	}
+	requireT.Error(s.PutPhoneVerification(ctx, app.PhoneVerification{ID: "a"}))
+
+	// wrong code is counted, the right one is still accepted within the attempts
+	_, err = s.UsePhoneVerification(ctx, "a", "wrong", 2, now)
+	requireT.ErrorIs(err, app.ErrPhoneVerificationFailed)
+	v, err := s.UsePhoneVerification(ctx, "a", "ha", 2, now)
+	requireT.NoError(err)
+	requireT.Equal("devcore1a", v.Address)
+	requireT.Equal(1, v.Attempts)
+	_, err = s.UsePhoneVerification(ctx, "a", "ha", 2, now)
+	requireT.ErrorIs(err, app.ErrPhoneVerificationFailed)
+
+	// the code is dropped once the attempts are exhausted
+	for i := 0; i < 2; i++ {
+		_, err = s.UsePhoneVerification(ctx, "b", "wrong", 2, now)
+		requireT.ErrorIs(err, app.ErrPhoneVerificationFailed)
+	}
+	_, err = s.UsePhoneVerification(ctx, "b", "hb", 2, now)
+	requireT.ErrorIs(err, app.ErrPhoneVerificationFailed)
+
+	_, err = s.UsePhoneVerification(ctx, "c", "hc", 2, now.Add(time.Minute))
+	requireT.ErrorIs(err, app.ErrPhoneVerificationFailed)
+	deleted, err := s.DeleteExpiredPhoneVerifications(ctx, now.Add(time.Minute))
+	requireT.NoError(err)
+	requireT.Equal(1, deleted)
+}
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+	"github.com/CoreumFoundation/faucet/pkg/kv"
+)
+
+// NewKVCooldowns returns the address cooldowns kept in the shared key-value store, so each address is funded once
+// per cooldown by all the instances. Keys are prefixed by the prefix.
+func NewKVCooldowns(kvStore kv.Store, prefix string) *KVCooldowns {
+	return &KVCooldowns{kv: kvStore, prefix: prefix}
+}
+
+// KVCooldowns keeps the time each address was last funded in the shared key-value store. Funding times expire
+// together with the cooldown, so they don't need to be deleted explicitly.
+type KVCooldowns struct {
+	kv     kv.Store
+	prefix string
+}
+
+// AddressFundedAt returns the time the address was last funded, zero if it is not recorded.
+func (c *KVCooldowns) AddressFundedAt(ctx context.Context, address chain.AccAddress) (time.Time, error) {
+	fundedAt, _, err := c.get(ctx, address)
+	return fundedAt, err
+}
+
+// ReserveAddressCooldown records the funding of the address at now, unless the address was funded after notBefore.
+// The reservation expires when the cooldown started at now elapses.
+func (c *KVCooldowns) ReserveAddressCooldown(
+	ctx context.Context,
+	address chain.AccAddress,
+	now, notBefore time.Time,
+) (time.Time, bool, error) {
+	fundedAt, raw, err := c.get(ctx, address)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if fundedAt.After(notBefore) {
+		return fundedAt, false, nil
+	}
+	value, err := json.Marshal(now)
+	if err != nil {
+		return time.Time{}, false, errors.WithStack(err)
+	}
+	swapped, err := c.kv.CompareAndSwap(ctx, c.key(address), raw, string(value), now.Sub(notBefore))
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if !swapped {
+		// another instance has just reserved the address
+		fundedAt, _, err = c.get(ctx, address)
+		return fundedAt, false, err
+	}
+	return now, true, nil
+}
+
+// ReleaseAddressCooldown deletes the funding time of the address if it is still the one reserved at the time.
+func (c *KVCooldowns) ReleaseAddressCooldown(ctx context.Context, address chain.AccAddress, reservedAt time.Time) error {
+	fundedAt, raw, err := c.get(ctx, address)
+	if err != nil || !fundedAt.Equal(reservedAt) {
+		return err
+	}
+	_, err = c.kv.CompareAndSwap(ctx, c.key(address), raw, "", 0)
+	return err
+}
+
+// DeleteAddressCooldownsBefore does nothing, because the funding times expire in the key-value store by themselves.
+func (c *KVCooldowns) DeleteAddressCooldownsBefore(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+func (c *KVCooldowns) key(address chain.AccAddress) string {
+	return c.prefix + address.String()
+}
+
+func (c *KVCooldowns) get(ctx context.Context, address chain.AccAddress) (time.Time, string, error) {
+	raw, ok, err := c.kv.Get(ctx, c.key(address))
+	if err != nil || !ok {
+		return time.Time{}, "", err
+	}
+	var fundedAt time.Time
+	if err := json.Unmarshal([]byte(raw), &fundedAt); err != nil {
+		return time.Time{}, "", errors.WithStack(err)
+	}
+	return fundedAt, raw, nil
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+type mockKV map[string]string
+
+func (m mockKV) Get(ctx context.Context, key string) (string, bool, error) {
+	value, ok := m[key]
+	return value, ok, nil
+}
+
+func (m mockKV) CompareAndSwap(ctx context.Context, key, expected, value string, ttl time.Duration) (bool, error) {
+	if m[key] != expected {
+		return false, nil
+	}
+	if value == "" {
+		delete(m, key)
+		return true, nil
+	}
+	m[key] = value
+	return true, nil
+}
+
+func TestKVCooldowns(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	kv := mockKV{}
+	c := NewKVCooldowns(kv, "cooldown:")
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	address := chain.AccAddress("address-1")
+
+	fundedAt, reserved, err := c.ReserveAddressCooldown(ctx, address, now, now.Add(-time.Hour))
+	requireT.NoError(err)
+	requireT.True(reserved)
+	requireT.Equal(now, fundedAt)
+	requireT.Contains(kv, "cooldown:"+address.String())
+
+	// another instance sharing the store sees the reservation
+	fundedAt, reserved, err = NewKVCooldowns(kv, "cooldown:").
+		ReserveAddressCooldown(ctx, address, now.Add(time.Minute), now.Add(-59*time.Minute))
+	requireT.NoError(err)
+	requireT.False(reserved)
+	requireT.Equal(now, fundedAt)
+
+	// only the own reservation is released
+	requireT.NoError(c.ReleaseAddressCooldown(ctx, address, now.Add(time.Second)))
+	fundedAt, err = c.AddressFundedAt(ctx, address)
+	requireT.NoError(err)
+	requireT.Equal(now, fundedAt)
+	requireT.NoError(c.ReleaseAddressCooldown(ctx, address, now))
+	fundedAt, err = c.AddressFundedAt(ctx, address)
+	requireT.NoError(err)
+	requireT.True(fundedAt.IsZero())
+}
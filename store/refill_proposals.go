@@ -0,0 +1,55 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// PutRefillProposal stores the refill proposal, replacing the previous version of it.
+func (s *Store) PutRefillProposal(ctx context.Context, proposal app.RefillProposal) error {
+	value, err := json.Marshal(proposal)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return errors.WithStack(tx.Bucket(bucketRefillProposals).Put([]byte(proposal.ID), value))
+	})
+}
+
+// RefillProposal returns the refill proposal.
+func (s *Store) RefillProposal(ctx context.Context, id string) (app.RefillProposal, error) {
+	var proposal app.RefillProposal
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(bucketRefillProposals).Get([]byte(id))
+		if value == nil {
+			return errors.Wrapf(app.ErrRefillProposalNotFound, "id: %s", id)
+		}
+		return errors.WithStack(json.Unmarshal(value, &proposal))
+	})
+	return proposal, err
+}
+
+// RefillProposals returns all the refill proposals ordered by creation time.
+func (s *Store) RefillProposals(ctx context.Context) ([]app.RefillProposal, error) {
+	proposals := []app.RefillProposal{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketRefillProposals).ForEach(func(_, value []byte) error {
+			var proposal app.RefillProposal
+			if err := json.Unmarshal(value, &proposal); err != nil {
+				return errors.WithStack(err)
+			}
+			proposals = append(proposals, proposal)
+			return nil
+		})
+	})
+	sort.SliceStable(proposals, func(i, j int) bool {
+		return proposals[i].CreatedAt.Before(proposals[j].CreatedAt)
+	})
+	return proposals, err
+}
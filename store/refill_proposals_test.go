@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestRefillProposals(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	_, err = s.RefillProposal(ctx, "missing")
+	requireT.True(errors.Is(err, app.ErrRefillProposalNotFound))
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	requireT.NoError(s.PutRefillProposal(ctx, app.RefillProposal{
+		ID: "b", Status: app.RefillPending, CreatedAt: createdAt,
+	}))
+	requireT.NoError(s.PutRefillProposal(ctx, app.RefillProposal{
+		ID: "a", Status: app.RefillPending, CreatedAt: createdAt.Add(time.Hour),
+	}))
+	requireT.NoError(s.PutRefillProposal(ctx, app.RefillProposal{
+		ID: "b", Status: app.RefillReady, CreatedAt: createdAt,
+	}))
+
+	proposal, err := s.RefillProposal(ctx, "b")
+	requireT.NoError(err)
+	requireT.Equal(app.RefillReady, proposal.Status)
+
+	proposals, err := s.RefillProposals(ctx)
+	requireT.NoError(err)
+	requireT.Len(proposals, 2)
+	requireT.Equal("b", proposals[0].ID)
+	requireT.Equal("a", proposals[1].ID)
+}
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// Replica is the read-only copy of the store serving heavy queries of dashboards and reports. Long read
+// transactions on the primary store delay its writes whenever bolt has to grow the memory map, so they are
+// served by the copy instead. The copy is refreshed periodically, so it lags behind the primary store.
+type Replica struct {
+	primary *Store
+	path    string
+
+	mu      sync.RWMutex
+	current *Store
+}
+
+// OpenReplica copies the primary store to the path and opens the copy.
+func OpenReplica(primary *Store, path string) (*Replica, error) {
+	r := &Replica{primary: primary, path: path}
+	if err := r.Refresh(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Refresh replaces the replica with the current copy of the primary store. The new copy is opened before
+// the previous one is closed, so if refresh fails at any step, the previous copy is kept serving.
+//
+// Bolt can't copy the changes only, so the whole store is copied in a single read transaction on the primary
+// store. The transaction takes as long as writing the file, e.g. a fraction of second for a store of tens
+// of megabytes, and runs once per refresh, while the dashboard queries it replaces would run on every request.
+func (r *Replica) Refresh() error {
+	tmpPath := r.path + ".tmp"
+	err := r.primary.db.View(func(tx *bolt.Tx) error {
+		return errors.WithStack(tx.CopyFile(tmpPath, 0o600))
+	})
+	if err != nil {
+		return err
+	}
+	db, err := bolt.Open(tmpPath, 0o600, &bolt.Options{Timeout: 5 * time.Second, ReadOnly: true})
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return errors.Wrapf(err, "unable to open read replica at %s", tmpPath)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the open copy follows the file it is renamed to
+	if err := os.Rename(tmpPath, r.path); err != nil {
+		_ = db.Close()
+		_ = os.Remove(tmpPath)
+		return errors.WithStack(err)
+	}
+	previous := r.current
+	r.current = &Store{db: db}
+	if previous != nil {
+		return previous.Close()
+	}
+	return nil
+}
+
+// Close closes the replica.
+func (r *Replica) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.current == nil {
+		return nil
+	}
+	err := r.current.Close()
+	r.current = nil
+	return err
+}
+
+// FundingsSince returns funding records stored since the given time ordered by time.
+func (r *Replica) FundingsSince(ctx context.Context, since time.Time) ([]app.FundingRecord, error) {
+	var records []app.FundingRecord
+	err := r.view(func(s *Store) error {
+		var err error
+		records, err = s.FundingsSince(ctx, since)
+		return err
+	})
+	return records, err
+}
+
+// IncidentsSince returns incidents stored since the given time ordered by time.
+func (r *Replica) IncidentsSince(ctx context.Context, since time.Time) ([]app.Incident, error) {
+	var incidents []app.Incident
+	err := r.view(func(s *Store) error {
+		var err error
+		incidents, err = s.IncidentsSince(ctx, since)
+		return err
+	})
+	return incidents, err
+}
+
+// LedgerTransactionsSince returns ledger transactions stored since the given time ordered by time.
+func (r *Replica) LedgerTransactionsSince(ctx context.Context, since time.Time) ([]app.LedgerTransaction, error) {
+	var txs []app.LedgerTransaction
+	err := r.view(func(s *Store) error {
+		var err error
+		txs, err = s.LedgerTransactionsSince(ctx, since)
+		return err
+	})
+	return txs, err
+}
+
+// view runs the query on the current copy, keeping it open until the query completes.
+func (r *Replica) view(fn func(s *Store) error) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.current == nil {
+		return errors.New("read replica is closed")
+	}
+	return fn(r.current)
+}
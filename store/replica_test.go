@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestReplica(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	s, err := Open(filepath.Join(dir, "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	requireT.NoError(s.RecordFunding(ctx, app.FundingRecord{RequestID: "rq1", Time: start}))
+
+	replica, err := OpenReplica(s, filepath.Join(dir, "replica.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = replica.Close()
+	})
+
+	requireT.NoError(s.RecordFunding(ctx, app.FundingRecord{RequestID: "rq2", Time: start.Add(time.Hour)}))
+	requireT.NoError(s.RecordIncident(ctx, app.Incident{RequestID: "rq2", Time: start.Add(time.Hour)}))
+
+	// writes reach the replica only once it is refreshed
+	records, err := replica.FundingsSince(ctx, start)
+	requireT.NoError(err)
+	requireT.Len(records, 1)
+
+	requireT.NoError(replica.Refresh())
+	records, err = replica.FundingsSince(ctx, start)
+	requireT.NoError(err)
+	requireT.Len(records, 2)
+	incidents, err := replica.IncidentsSince(ctx, start)
+	requireT.NoError(err)
+	requireT.Len(incidents, 1)
+
+	// the previous copy keeps serving if the refresh fails, here because the replica can't be replaced
+	replicaPath := filepath.Join(dir, "replica.db")
+	requireT.NoError(os.Remove(replicaPath))
+	requireT.NoError(os.MkdirAll(filepath.Join(replicaPath, "blocker"), 0o700))
+	requireT.NoError(s.RecordFunding(ctx, app.FundingRecord{RequestID: "rq3", Time: start.Add(2 * time.Hour)}))
+	requireT.Error(replica.Refresh())
+	records, err = replica.FundingsSince(ctx, start)
+	requireT.NoError(err)
+	requireT.Len(records, 2)
+
+	requireT.NoError(replica.Close())
+	_, err = replica.LedgerTransactionsSince(ctx, start)
+	requireT.Error(err)
+}
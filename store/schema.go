@@ -0,0 +1,252 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+	"github.com/CoreumFoundation/faucet/pkg/chain"
+)
+
+var (
+	// ErrSchemaTooNew is returned if the store was migrated by the newer version of the faucet, e.g. after rollback.
+	ErrSchemaTooNew = errors.New("store schema is newer than supported")
+	// ErrMigrationRequired is returned if the store must be migrated but migration is not allowed.
+	ErrMigrationRequired = errors.New("store schema migration required")
+)
+
+var keySchemaVersion = []byte("schema_version")
+
+// migration upgrades the store to the version.
+type migration struct {
+	version     uint64
+	description string
+	migrate     func(tx *bolt.Tx) error
+}
+
+// migrations are applied in order to the stores having lower schema version. Stores created before the schema
+// was versioned have version 0. New migrations must be appended, never modified.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create history, incidents, ledger, address book and claim codes buckets",
+		migrate:     createBuckets(bucketHistory, bucketIncidents, bucketLedger, bucketAddressBook, bucketClaimCodes),
+	},
+	{
+		version:     2,
+		description: "create blocked addresses bucket",
+		migrate:     createBuckets(bucketBlocklist),
+	},
+	{
+		version:     3,
+		description: "create address cooldowns bucket",
+		migrate:     createBuckets(bucketCooldowns),
+	},
+	{
+		version:     4,
+		description: "create address totals bucket from the funding history",
+		migrate:     createAddressTotals,
+	},
+	{
+		version:     5,
+		description: "create bypass tokens bucket",
+		migrate:     createBuckets(bucketBypassTokens),
+	},
+	{
+		version:     6,
+		description: "create named accounts bucket",
+		migrate:     createBuckets(bucketNamedAccounts),
+	},
+	{
+		version:     7,
+		description: "create tenant notifications bucket",
+		migrate:     createBuckets(bucketTenantNotifications),
+	},
+	{
+		version:     8,
+		description: "create refill proposals bucket",
+		migrate:     createBuckets(bucketRefillProposals),
+	},
+	{
+		version:     9,
+		description: "create jobs bucket",
+		migrate:     createBuckets(bucketJobs),
+	},
+	{
+		version:     10,
+		description: "create config changes bucket",
+		migrate:     createBuckets(bucketConfigChanges),
+	},
+	{
+		version:     11,
+		description: "create identity quotas bucket",
+		migrate:     createBuckets(bucketIdentityQuotas),
+	},
+	{
+		version:     12,
+		description: "create email verifications bucket",
+		migrate:     createBuckets(bucketEmailVerifications),
+	},
+	{
+		version:     13,
+		description: "create phone verifications bucket",
+		migrate:     createBuckets(bucketPhoneVerifications),
+	},
+	{
+		version:     14,
+		description: "create funding link redemptions bucket",
+		migrate:     createBuckets(bucketFundingLinks),
+	},
+}
+
+// SchemaVersion is the version of the store schema supported by this binary.
+var SchemaVersion = migrations[len(migrations)-1].version
+
+func createBuckets(buckets ...[]byte) func(tx *bolt.Tx) error {
+	return func(tx *bolt.Tx) error {
+		for _, bucket := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		return nil
+	}
+}
+
+// createAddressTotals sums the amounts of the funding history per address, so the lifetime cap accounts
+// for the fundings recorded before the totals were tracked.
+func createAddressTotals(tx *bolt.Tx) error {
+	bucket, err := tx.CreateBucketIfNotExists(bucketAddressTotals)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	history := tx.Bucket(bucketHistory)
+	if history == nil {
+		return nil
+	}
+	totals := map[string]app.AddressTotal{}
+	err = history.ForEach(func(_, value []byte) error {
+		var record app.FundingRecord
+		if err := json.Unmarshal(value, &record); err != nil {
+			return errors.WithStack(err)
+		}
+		_, address, err := chain.DecodeBech32(record.Address)
+		if err != nil || record.Amount.Denom == "" || record.Amount.Amount.IsNil() {
+			// such records are not expected, they are skipped instead of blocking the migration
+			return nil //nolint:nilerr
+		}
+		total, ok := totals[string(address)]
+		if !ok {
+			total = app.AddressTotal{Address: record.Address, Amount: chain.NewCoins()}
+		}
+		total.Amount = total.Amount.Add(record.Amount)
+		if record.Time.After(total.UpdatedAt) {
+			total.UpdatedAt = record.Time
+		}
+		totals[string(address)] = total
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for address, total := range totals {
+		if err := putAddressTotal(bucket, chain.AccAddress(address), total); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SchemaVersion returns the schema version of the store.
+func (s *Store) SchemaVersion() (uint64, error) {
+	var version uint64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		version = schemaVersion(tx)
+		return nil
+	})
+	return version, err
+}
+
+func schemaVersion(tx *bolt.Tx) uint64 {
+	meta := tx.Bucket(bucketMeta)
+	if meta == nil {
+		return 0
+	}
+	value := meta.Get(keySchemaVersion)
+	if len(value) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(value)
+}
+
+// isEmpty tells if the store has just been created.
+func isEmpty(tx *bolt.Tx) bool {
+	empty := true
+	_ = tx.ForEach(func(_ []byte, _ *bolt.Bucket) error {
+		empty = false
+		return nil
+	})
+	return empty
+}
+
+// migrate verifies the schema version of the store and applies the pending migrations if allowed. The store
+// is backed up next to the original file before it is migrated, so the previous version of the faucet may be
+// restored along with the backup. New stores are initialized without any backup.
+func migrate(db *bolt.DB, path string, autoMigrate bool) error {
+	var version uint64
+	var empty bool
+	if err := db.View(func(tx *bolt.Tx) error {
+		version = schemaVersion(tx)
+		empty = isEmpty(tx)
+		return nil
+	}); err != nil {
+		return errors.WithStack(err)
+	}
+
+	switch {
+	case version == SchemaVersion:
+		return nil
+	case version > SchemaVersion:
+		return errors.Wrapf(ErrSchemaTooNew,
+			"store %s has schema version %d, this binary supports up to %d, upgrade the faucet or restore the backup",
+			path, version, SchemaVersion)
+	case !empty && !autoMigrate:
+		return errors.Wrapf(ErrMigrationRequired,
+			"store %s has schema version %d, this binary requires %d, enable migration to upgrade it",
+			path, version, SchemaVersion)
+	}
+
+	if !empty {
+		backupPath := fmt.Sprintf("%s.v%d.bak", path, version)
+		if err := db.View(func(tx *bolt.Tx) error {
+			return errors.WithStack(tx.CopyFile(backupPath, 0o600))
+		}); err != nil {
+			_ = os.Remove(backupPath)
+			return errors.Wrapf(err, "unable to back up store to %s before migration", backupPath)
+		}
+	}
+
+	// all the migrations are applied in a single transaction, so the store is never left partially migrated
+	return db.Update(func(tx *bolt.Tx) error {
+		for _, m := range migrations {
+			if m.version <= version {
+				continue
+			}
+			if err := m.migrate(tx); err != nil {
+				return errors.Wrapf(err, "migration to schema version %d (%s) failed", m.version, m.description)
+			}
+		}
+		meta, err := tx.CreateBucketIfNotExists(bucketMeta)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		value := make([]byte, 8)
+		binary.BigEndian.PutUint64(value, SchemaVersion)
+		return errors.WithStack(meta.Put(keySchemaVersion, value))
+	})
+}
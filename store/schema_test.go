@@ -0,0 +1,70 @@
+package store
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	requireT := require.New(t)
+	path := filepath.Join(t.TempDir(), "faucet.db")
+
+	// new store is initialized even if migration is disabled
+	s, err := OpenWithoutMigration(path)
+	requireT.NoError(err)
+	version, err := s.SchemaVersion()
+	requireT.NoError(err)
+	requireT.Equal(SchemaVersion, version)
+	requireT.NoError(s.Close())
+
+	setSchemaVersion(t, path, SchemaVersion+1)
+	_, err = Open(path)
+	requireT.True(errors.Is(err, ErrSchemaTooNew))
+}
+
+func TestSchemaMigration(t *testing.T) {
+	requireT := require.New(t)
+	path := filepath.Join(t.TempDir(), "faucet.db")
+
+	// store created before the schema was versioned
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	requireT.NoError(err)
+	requireT.NoError(db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucket(bucketHistory)
+		return err
+	}))
+	requireT.NoError(db.Close())
+
+	_, err = OpenWithoutMigration(path)
+	requireT.True(errors.Is(err, ErrMigrationRequired))
+
+	s, err := Open(path)
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+	version, err := s.SchemaVersion()
+	requireT.NoError(err)
+	requireT.Equal(SchemaVersion, version)
+
+	_, err = os.Stat(path + ".v0.bak")
+	requireT.NoError(err)
+}
+
+func setSchemaVersion(t *testing.T, path string, version uint64) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.Update(func(tx *bolt.Tx) error {
+		value := make([]byte, 8)
+		binary.BigEndian.PutUint64(value, version)
+		return tx.Bucket(bucketMeta).Put(keySchemaVersion, value)
+	}))
+}
@@ -0,0 +1,65 @@
+package store
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	bucketMeta                = []byte("meta")
+	bucketHistory             = []byte("history")
+	bucketIncidents           = []byte("incidents")
+	bucketLedger              = []byte("ledger")
+	bucketAddressBook         = []byte("address_book")
+	bucketClaimCodes          = []byte("claim_codes")
+	bucketBlocklist           = []byte("blocked_addresses")
+	bucketCooldowns           = []byte("address_cooldowns")
+	bucketAddressTotals       = []byte("address_totals")
+	bucketBypassTokens        = []byte("bypass_tokens")
+	bucketNamedAccounts       = []byte("named_accounts")
+	bucketTenantNotifications = []byte("tenant_notifications")
+	bucketRefillProposals     = []byte("refill_proposals")
+	bucketJobs                = []byte("jobs")
+	bucketConfigChanges       = []byte("config_changes")
+	bucketIdentityQuotas      = []byte("identity_quotas")
+	bucketEmailVerifications  = []byte("email_verifications")
+	bucketPhoneVerifications  = []byte("phone_verifications")
+	bucketFundingLinks        = []byte("funding_link_redemptions")
+)
+
+// Open opens the store kept in the file, creating it if it doesn't exist, and migrates it to SchemaVersion.
+func Open(path string) (*Store, error) {
+	return open(path, true)
+}
+
+// OpenWithoutMigration opens the store kept in the file, creating it if it doesn't exist. Existing store of the
+// schema version other than SchemaVersion is refused.
+func OpenWithoutMigration(path string) (*Store, error) {
+	return open(path, false)
+}
+
+func open(path string, autoMigrate bool) (*Store, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to open store at %s", path)
+	}
+
+	if err := migrate(db, path, autoMigrate); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Store persists the state of the faucet.
+type Store struct {
+	db *bolt.DB
+}
+
+// Close closes the store.
+func (s *Store) Close() error {
+	return errors.WithStack(s.db.Close())
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+// TenantNotifications returns the notification settings of the tenant.
+func (s *Store) TenantNotifications(ctx context.Context, tenant string) (app.TenantNotifications, error) {
+	var notifications app.TenantNotifications
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(bucketTenantNotifications).Get([]byte(tenant))
+		if value == nil {
+			return errors.Wrapf(app.ErrTenantNotificationsNotFound, "tenant: %s", tenant)
+		}
+		return errors.WithStack(json.Unmarshal(value, &notifications))
+	})
+	return notifications, err
+}
+
+// AllTenantNotifications returns the notification settings of all the tenants ordered by tenant.
+func (s *Store) AllTenantNotifications(ctx context.Context) ([]app.TenantNotifications, error) {
+	all := []app.TenantNotifications{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketTenantNotifications).ForEach(func(_, value []byte) error {
+			var notifications app.TenantNotifications
+			if err := json.Unmarshal(value, &notifications); err != nil {
+				return errors.WithStack(err)
+			}
+			all = append(all, notifications)
+			return nil
+		})
+	})
+	return all, err
+}
+
+// PutTenantNotifications stores the notification settings of the tenant, replacing the previous ones.
+func (s *Store) PutTenantNotifications(ctx context.Context, notifications app.TenantNotifications) error {
+	value, err := json.Marshal(notifications)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return errors.WithStack(tx.Bucket(bucketTenantNotifications).Put([]byte(notifications.Tenant), value))
+	})
+}
+
+// DeleteTenantNotifications deletes the notification settings of the tenant.
+func (s *Store) DeleteTenantNotifications(ctx context.Context, tenant string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketTenantNotifications)
+		if bucket.Get([]byte(tenant)) == nil {
+			return errors.Wrapf(app.ErrTenantNotificationsNotFound, "tenant: %s", tenant)
+		}
+		return errors.WithStack(bucket.Delete([]byte(tenant)))
+	})
+}
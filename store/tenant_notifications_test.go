@@ -0,0 +1,47 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+func TestTenantNotifications(t *testing.T) {
+	requireT := require.New(t)
+	ctx := context.Background()
+
+	s, err := Open(filepath.Join(t.TempDir(), "faucet.db"))
+	requireT.NoError(err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+
+	_, err = s.TenantNotifications(ctx, "ci")
+	requireT.True(errors.Is(err, app.ErrTenantNotificationsNotFound))
+
+	requireT.NoError(s.PutTenantNotifications(ctx, app.TenantNotifications{
+		Tenant: "ci", Webhooks: []string{"https://hooks.example.com/1"},
+	}))
+	requireT.NoError(s.PutTenantNotifications(ctx, app.TenantNotifications{
+		Tenant: "ci", Webhooks: []string{"https://hooks.example.com/2"},
+	}))
+	requireT.NoError(s.PutTenantNotifications(ctx, app.TenantNotifications{Tenant: "partner"}))
+
+	notifications, err := s.TenantNotifications(ctx, "ci")
+	requireT.NoError(err)
+	requireT.Equal([]string{"https://hooks.example.com/2"}, notifications.Webhooks)
+
+	all, err := s.AllTenantNotifications(ctx)
+	requireT.NoError(err)
+	requireT.Len(all, 2)
+	requireT.Equal("ci", all[0].Tenant)
+	requireT.Equal("partner", all[1].Tenant)
+
+	requireT.NoError(s.DeleteTenantNotifications(ctx, "ci"))
+	requireT.True(errors.Is(s.DeleteTenantNotifications(ctx, "ci"), app.ErrTenantNotificationsNotFound))
+}
@@ -0,0 +1,198 @@
+// Package webhook delivers the events of the faucet to the webhooks configured by the operator. Each webhook
+// receives the kinds of events it subscribes to, rendered by its template to the format expected by the receiver,
+// e.g. Slack or Discord.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+const (
+	timeout            = 10 * time.Second
+	defaultContentType = "application/json"
+)
+
+// Config configures the single webhook.
+type Config struct {
+	URL string `json:"url"`
+	// Events are the kinds of events delivered to the webhook, all of them if empty.
+	Events []app.EventKind `json:"events"`
+	// Template is the Go template rendering the body from the Payload, the payload is posted as JSON if empty.
+	Template string `json:"template"`
+	// ContentType is the content type of the rendered body, application/json by default.
+	ContentType string `json:"contentType"`
+}
+
+// Payload describes the event, it is posted as JSON or rendered by the template of the webhook.
+type Payload struct {
+	Kind      app.EventKind `json:"kind"`
+	Time      time.Time     `json:"time"`
+	RequestID string        `json:"requestId,omitempty"`
+	Tenant    string        `json:"tenant,omitempty"`
+	Address   string        `json:"address,omitempty"`
+	TxHash    string        `json:"txHash,omitempty"`
+	// Confirmations is the number of confirmations collected by the transaction of the confirmed event.
+	Confirmations int64  `json:"confirmations,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+	// Actor and Changes describe the configuration change of the config_changed event.
+	Actor   string           `json:"actor,omitempty"`
+	Changes []app.ConfigDiff `json:"changes,omitempty"`
+}
+
+// templateFuncs are the functions available in the templates. json renders the value as JSON, so strings
+// are quoted and escaped safely inside JSON bodies.
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), errors.WithStack(err)
+	},
+}
+
+// LoadConfig reads the JSON array of webhook configs from the file.
+func LoadConfig(path string) ([]Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var configs []Config
+	if err := json.Unmarshal(content, &configs); err != nil {
+		return nil, errors.Wrapf(err, "invalid webhook config %s", path)
+	}
+	return configs, nil
+}
+
+type webhook struct {
+	url         string
+	events      map[app.EventKind]bool
+	template    *template.Template
+	contentType string
+}
+
+// Dispatcher delivers the events to the webhooks.
+type Dispatcher struct {
+	webhooks []webhook
+	client   *http.Client
+}
+
+// NewDispatcher validates the configs and returns the dispatcher posting the events by the client.
+func NewDispatcher(configs []Config, client *http.Client) (*Dispatcher, error) {
+	d := &Dispatcher{client: client}
+	for i, c := range configs {
+		u, err := url.Parse(c.URL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, errors.Errorf("webhook %d: invalid URL", i)
+		}
+		w := webhook{url: c.URL, contentType: c.ContentType}
+		if w.contentType == "" {
+			w.contentType = defaultContentType
+		}
+		if len(c.Events) > 0 {
+			w.events = map[app.EventKind]bool{}
+		}
+		for _, kind := range c.Events {
+			switch kind {
+			case app.EventRequestAccepted, app.EventBroadcast, app.EventConfirmed, app.EventFailed, app.EventBlocked,
+				app.EventConfigChanged:
+				w.events[kind] = true
+			default:
+				return nil, errors.Errorf("webhook %d: unknown event %q", i, kind)
+			}
+		}
+		if c.Template != "" {
+			w.template, err = template.New("webhook").Funcs(templateFuncs).Parse(c.Template)
+			if err != nil {
+				return nil, errors.Wrapf(err, "webhook %d: invalid template", i)
+			}
+			// unknown fields are reported on execution only, so the template is tried upfront
+			if _, err := w.render(Payload{Kind: app.EventFailed, Time: time.Now()}); err != nil {
+				return nil, errors.Wrapf(err, "webhook %d", i)
+			}
+		}
+		d.webhooks = append(d.webhooks, w)
+	}
+	return d, nil
+}
+
+// Handle is the event bus subscriber posting the event to the webhooks subscribed to its kind.
+// Failures are only logged.
+func (d *Dispatcher) Handle(ctx context.Context, event app.Event) {
+	payload := Payload{
+		Kind:          event.Kind,
+		Time:          event.Time,
+		RequestID:     event.Requester.RequestID,
+		Tenant:        event.Requester.Tenant,
+		Address:       event.Address,
+		TxHash:        event.TxHash,
+		Confirmations: event.Confirmations,
+		Reason:        event.Reason,
+		Actor:         event.Actor,
+		Changes:       event.Changes,
+	}
+	for i, w := range d.webhooks {
+		if w.events != nil && !w.events[event.Kind] {
+			continue
+		}
+		// URLs of the webhooks are secrets, so webhooks are logged by their index
+		if err := d.send(ctx, w, payload); err != nil {
+			logger.Get(ctx).Warn("Sending event to webhook failed", zap.Int("webhook", i),
+				zap.String("kind", string(event.Kind)), zap.Error(err))
+		}
+	}
+}
+
+// render returns the body posted to the webhook for the payload.
+func (w webhook) render(payload Payload) ([]byte, error) {
+	if w.template == nil {
+		body, err := json.Marshal(payload)
+		return body, errors.WithStack(err)
+	}
+	buf := &bytes.Buffer{}
+	if err := w.template.Execute(buf, payload); err != nil {
+		return nil, errors.Wrap(err, "rendering template failed")
+	}
+	return buf.Bytes(), nil
+}
+
+func (d *Dispatcher) send(ctx context.Context, w webhook, payload Payload) error {
+	body, err := w.render(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", w.contentType)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode > 299 {
+		return errors.Errorf("webhook returned non 2xx response, status: %d", resp.StatusCode)
+	}
+	return nil
+}
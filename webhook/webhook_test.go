@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/CoreumFoundation/coreum-tools/pkg/logger"
+	"github.com/CoreumFoundation/faucet/app"
+)
+
+type delivery struct {
+	path        string
+	contentType string
+	body        string
+}
+
+func TestDispatcher(t *testing.T) {
+	requireT := require.New(t)
+	ctx := logger.WithLogger(context.Background(), zaptest.NewLogger(t))
+
+	var deliveries []delivery
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		deliveries = append(deliveries, delivery{
+			path:        r.URL.Path,
+			contentType: r.Header.Get("Content-Type"),
+			body:        string(body),
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	configPath := filepath.Join(t.TempDir(), "webhooks.json")
+	requireT.NoError(os.WriteFile(configPath, []byte(`[
+		{"url": "`+srv.URL+`/all"},
+		{
+			"url": "`+srv.URL+`/slack",
+			"events": ["failed"],
+			"template": "{\"text\": {{ printf \"Funding of %s failed: %s\" .Address .Reason | json }}}"
+		},
+		{"url": "`+srv.URL+`/text", "events": ["broadcast"], "template": "{{ .TxHash }}", "contentType": "text/plain"}
+	]`), 0o600))
+	configs, err := LoadConfig(configPath)
+	requireT.NoError(err)
+	d, err := NewDispatcher(configs, srv.Client())
+	requireT.NoError(err)
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	d.Handle(ctx, app.Event{
+		Kind:      app.EventFailed,
+		Time:      now,
+		Requester: app.Requester{RequestID: "rq1"},
+		Address:   "devcore1abc",
+		Reason:    `node "a" unavailable`,
+	})
+	d.Handle(ctx, app.Event{Kind: app.EventBroadcast, Time: now, TxHash: "ABCD"})
+
+	requireT.Equal([]delivery{
+		{
+			path:        "/all",
+			contentType: "application/json",
+			body: `{"kind":"failed","time":"2023-01-01T00:00:00Z","requestId":"rq1","address":"devcore1abc",` +
+				`"reason":"node \"a\" unavailable"}`,
+		},
+		{
+			path:        "/slack",
+			contentType: "application/json",
+			body:        `{"text": "Funding of devcore1abc failed: node \"a\" unavailable"}`,
+		},
+		{
+			path:        "/all",
+			contentType: "application/json",
+			body:        `{"kind":"broadcast","time":"2023-01-01T00:00:00Z","txHash":"ABCD"}`,
+		},
+		{path: "/text", contentType: "text/plain", body: "ABCD"},
+	}, deliveries)
+}
+
+func TestNewDispatcherInvalid(t *testing.T) {
+	for _, config := range []Config{
+		{URL: "ftp://hooks.example.com"},
+		{URL: "https://hooks.example.com", Events: []app.EventKind{"unknown"}},
+		{URL: "https://hooks.example.com", Template: "{{ .Address"},
+		{URL: "https://hooks.example.com", Template: "{{ .Unknown }}"},
+	} {
+		_, err := NewDispatcher([]Config{config}, http.DefaultClient)
+		require.Error(t, err, config)
+	}
+}